@@ -20,6 +20,10 @@ func init() {
 	}
 }
 
+// AllNamespacesSentinel is the value to use when checking namespace
+// permissions to match any namespace.
+const AllNamespacesSentinel = "*"
+
 // capabilitySet is a type wrapper to help managing a set of capabilities
 type capabilitySet map[string]struct{}
 
@@ -58,6 +62,13 @@ type ACL struct {
 	// We use an iradix for the purposes of ordered iteration.
 	wildcardHostVolumes *iradix.Tree
 
+	// variables maps a variable path to a capabilitySet
+	variables *iradix.Tree
+
+	// wildcardVariables maps a glob pattern of variable paths to a capabilitySet
+	// We use an iradix for the purposes of ordered iteration.
+	wildcardVariables *iradix.Tree
+
 	agent    string
 	node     string
 	operator string
@@ -73,6 +84,8 @@ func maxPrivilege(a, b string) string {
 		return PolicyDeny
 	case a == PolicyWrite || b == PolicyWrite:
 		return PolicyWrite
+	case a == PolicyDebug || b == PolicyDebug:
+		return PolicyDebug
 	case a == PolicyRead || b == PolicyRead:
 		return PolicyRead
 	case a == PolicyList || b == PolicyList:
@@ -95,6 +108,8 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	wnsTxn := iradix.New().Txn()
 	hvTxn := iradix.New().Txn()
 	whvTxn := iradix.New().Txn()
+	varTxn := iradix.New().Txn()
+	wvarTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
@@ -183,6 +198,49 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 			}
 		}
 
+	VARIABLES:
+		for _, v := range policy.Variables {
+			// Should the path be matched using a glob?
+			globDefinition := strings.Contains(v.Path, "*")
+
+			// Check for existing capabilities
+			var capabilities capabilitySet
+
+			if globDefinition {
+				raw, ok := wvarTxn.Get([]byte(v.Path))
+				if ok {
+					capabilities = raw.(capabilitySet)
+				} else {
+					capabilities = make(capabilitySet)
+					wvarTxn.Insert([]byte(v.Path), capabilities)
+				}
+			} else {
+				raw, ok := varTxn.Get([]byte(v.Path))
+				if ok {
+					capabilities = raw.(capabilitySet)
+				} else {
+					capabilities = make(capabilitySet)
+					varTxn.Insert([]byte(v.Path), capabilities)
+				}
+			}
+
+			// Deny always takes precedence
+			if capabilities.Check(VariableCapabilityDeny) {
+				continue
+			}
+
+			// Add in all the capabilities
+			for _, cap := range v.Capabilities {
+				if cap == VariableCapabilityDeny {
+					// Overwrite any existing capabilities
+					capabilities.Clear()
+					capabilities.Set(VariableCapabilityDeny)
+					continue VARIABLES
+				}
+				capabilities.Set(cap)
+			}
+		}
+
 		// Take the maximum privilege for agent, node, and operator
 		if policy.Agent != nil {
 			acl.agent = maxPrivilege(acl.agent, policy.Agent.Policy)
@@ -206,6 +264,8 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.wildcardNamespaces = wnsTxn.Commit()
 	acl.hostVolumes = hvTxn.Commit()
 	acl.wildcardHostVolumes = whvTxn.Commit()
+	acl.variables = varTxn.Commit()
+	acl.wildcardVariables = wvarTxn.Commit()
 
 	return acl, nil
 }
@@ -222,6 +282,12 @@ func (a *ACL) AllowNamespaceOperation(ns string, op string) bool {
 		return true
 	}
 
+	// If using the all namespaces wildcard, allow if any namespace allows the
+	// operation.
+	if ns == AllNamespacesSentinel && a.anyNamespaceAllowsOp(op) {
+		return true
+	}
+
 	// Check for a matching capability set
 	capabilities, ok := a.matchingNamespaceCapabilitySet(ns)
 	if !ok {
@@ -239,6 +305,12 @@ func (a *ACL) AllowNamespace(ns string) bool {
 		return true
 	}
 
+	// If using the all namespaces wildcard, allow if any namespace allows any
+	// operation.
+	if ns == AllNamespacesSentinel && a.anyNamespaceAllowsAnyOp() {
+		return true
+	}
+
 	// Check for a matching capability set
 	capabilities, ok := a.matchingNamespaceCapabilitySet(ns)
 	if !ok {
@@ -253,6 +325,42 @@ func (a *ACL) AllowNamespace(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// anyNamespaceAllowsOp returns true if any namespace in the ACL allows the
+// given operation.
+func (a *ACL) anyNamespaceAllowsOp(op string) bool {
+	return a.anyNamespaceAllows(func(c capabilitySet) bool {
+		return c.Check(op)
+	})
+}
+
+// anyNamespaceAllowsAnyOp returns true if any namespace in the ACL allows at
+// least one operation.
+func (a *ACL) anyNamespaceAllowsAnyOp() bool {
+	return a.anyNamespaceAllows(func(c capabilitySet) bool {
+		return len(c) > 0 && !c.Check(PolicyDeny)
+	})
+}
+
+// anyNamespaceAllows returns true if the callback cb returns true for any
+// namespace operation of the ACL.
+func (a *ACL) anyNamespaceAllows(cb func(capabilitySet) bool) bool {
+	allow := false
+
+	checkFn := func(_ []byte, iv interface{}) bool {
+		v := iv.(capabilitySet)
+		allow = cb(v)
+		return allow
+	}
+
+	a.namespaces.Root().Walk(checkFn)
+	if allow {
+		return true
+	}
+
+	a.wildcardNamespaces.Root().Walk(checkFn)
+	return allow
+}
+
 // AllowHostVolumeOperation checks if a given operation is allowed for a host volume
 func (a *ACL) AllowHostVolumeOperation(hv string, op string) bool {
 	// Hot path management tokens
@@ -291,6 +399,24 @@ func (a *ACL) AllowHostVolume(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowVariableOperation checks if a given operation is allowed for the
+// variable stored at the given path
+func (a *ACL) AllowVariableOperation(path string, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := a.matchingVariableCapabilitySet(path)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	return capabilities.Check(op)
+}
+
 // matchingNamespaceCapabilitySet looks for a capabilitySet that matches the namespace,
 // if no concrete definitions are found, then we return the closest matching
 // glob.
@@ -323,6 +449,22 @@ func (a *ACL) matchingHostVolumeCapabilitySet(name string) (capabilitySet, bool)
 	return a.findClosestMatchingGlob(a.wildcardHostVolumes, name)
 }
 
+// matchingVariableCapabilitySet looks for a capabilitySet that matches the
+// variable path, if no concrete definitions are found, then we return the
+// closest matching glob.
+// The closest matching glob is the one that has the smallest character
+// difference between the path and the glob.
+func (a *ACL) matchingVariableCapabilitySet(path string) (capabilitySet, bool) {
+	// Check for a concrete matching capability set
+	raw, ok := a.variables.Get([]byte(path))
+	if ok {
+		return raw.(capabilitySet), true
+	}
+
+	// We didn't find a concrete match, so lets try and evaluate globs.
+	return a.findClosestMatchingGlob(a.wildcardVariables, path)
+}
+
 type matchingGlob struct {
 	name          string
 	difference    int
@@ -386,6 +528,8 @@ func (a *ACL) AllowAgentRead() bool {
 		return true
 	case a.agent == PolicyWrite:
 		return true
+	case a.agent == PolicyDebug:
+		return true
 	case a.agent == PolicyRead:
 		return true
 	default:
@@ -405,6 +549,21 @@ func (a *ACL) AllowAgentWrite() bool {
 	}
 }
 
+// AllowAgentDebug checks if the agent pprof endpoints may be used. The debug
+// policy grants this without also granting agent write operations.
+func (a *ACL) AllowAgentDebug() bool {
+	switch {
+	case a.management:
+		return true
+	case a.agent == PolicyWrite:
+		return true
+	case a.agent == PolicyDebug:
+		return true
+	default:
+		return false
+	}
+}
+
 // AllowNodeRead checks if read operations are allowed for a node
 func (a *ACL) AllowNodeRead() bool {
 	switch {
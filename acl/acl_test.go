@@ -263,6 +263,90 @@ func TestAllowNamespace(t *testing.T) {
 	}
 }
 
+func TestAllowNamespace_AllNamespacesSentinel(t *testing.T) {
+	tests := []struct {
+		Policy    string
+		Operation string
+		Allow     bool
+	}{
+		{
+			Policy:    `namespace "foo" { policy = "read" }`,
+			Operation: NamespaceCapabilityListJobs,
+			Allow:     true,
+		},
+		{
+			Policy:    `namespace "foo" { policy = "read" }`,
+			Operation: NamespaceCapabilitySubmitJob,
+			Allow:     false,
+		},
+		{
+			Policy:    `namespace "foo" { capabilities = ["submit-job"] }`,
+			Operation: NamespaceCapabilitySubmitJob,
+			Allow:     true,
+		},
+		{
+			Policy:    `namespace "*" { policy = "read" }`,
+			Operation: NamespaceCapabilityListJobs,
+			Allow:     true,
+		},
+		{
+			Policy:    `namespace "foo" { policy = "deny" }`,
+			Operation: NamespaceCapabilityListJobs,
+			Allow:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy+":"+tc.Operation, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.Nil(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowNamespaceOperation(AllNamespacesSentinel, tc.Operation))
+		})
+	}
+}
+
+func TestAgentDebug(t *testing.T) {
+	assert := assert.New(t)
+
+	// The debug policy grants read and debug but not write
+	p, err := Parse(`agent { policy = "debug" }`)
+	assert.Nil(err)
+	acl, err := NewACL(false, []*Policy{p})
+	assert.Nil(err)
+	assert.True(acl.AllowAgentRead())
+	assert.True(acl.AllowAgentDebug())
+	assert.False(acl.AllowAgentWrite())
+
+	// Write implies debug
+	p, err = Parse(`agent { policy = "write" }`)
+	assert.Nil(err)
+	acl, err = NewACL(false, []*Policy{p})
+	assert.Nil(err)
+	assert.True(acl.AllowAgentDebug())
+
+	// Read does not imply debug
+	p, err = Parse(`agent { policy = "read" }`)
+	assert.Nil(err)
+	acl, err = NewACL(false, []*Policy{p})
+	assert.Nil(err)
+	assert.False(acl.AllowAgentDebug())
+
+	// Deny takes precedence over debug
+	p, err = Parse(`agent { policy = "debug" }`)
+	assert.Nil(err)
+	p2, err := Parse(`agent { policy = "deny" }`)
+	assert.Nil(err)
+	acl, err = NewACL(false, []*Policy{p, p2})
+	assert.Nil(err)
+	assert.False(acl.AllowAgentDebug())
+}
+
 func TestWildcardNamespaceMatching(t *testing.T) {
 	tests := []struct {
 		Policy string
@@ -364,6 +448,57 @@ func TestWildcardHostVolumeMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestWildcardVariableMatching(t *testing.T) {
+	tests := []struct {
+		Policy string
+		Allow  bool
+	}{
+		{ // Wildcard matches
+			Policy: `variable "nomad/jobs/*" { policy = "read" }`,
+			Allow:  true,
+		},
+		{ // Non globbed paths are not wildcards
+			Policy: `variable "nomad/jobs" { policy = "read" }`,
+			Allow:  false,
+		},
+		{ // Concrete matches take precedence
+			Policy: `variable "nomad/jobs/example" { policy = "deny" }
+			         variable "nomad/jobs/*" { policy = "read" }`,
+			Allow: false,
+		},
+		{
+			Policy: `variable "nomad/jobs/*" { policy = "deny" }
+			         variable "nomad/jobs/example" { policy = "read" }`,
+			Allow: true,
+		},
+		{ // The closest character match wins
+			Policy: `variable "nomad/jobs/*" { policy = "deny" }
+			         variable "*/example" { policy = "read" }`, // 7 vs 10 chars
+			Allow: false,
+		},
+		{
+			Policy: `variable "*/example" { policy = "read" }
+			         variable "nomad/jobs/*" { policy = "deny" }`, // 7 vs 10 chars
+			Allow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.NoError(err)
+			assert.NotNil(policy.Variables)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowVariableOperation("nomad/jobs/example", VariableCapabilityRead))
+		})
+	}
+}
 func TestACL_matchingCapabilitySet_returnsAllMatches(t *testing.T) {
 	tests := []struct {
 		Policy        string
@@ -16,6 +16,10 @@ const (
 	PolicyList  = "list"
 	PolicyWrite = "write"
 	PolicyScale = "scale"
+
+	// PolicyDebug is only valid for agent policies and grants access to the
+	// agent pprof endpoints in addition to read operations.
+	PolicyDebug = "debug"
 )
 
 const (
@@ -61,14 +65,30 @@ const (
 	HostVolumeCapabilityMountReadWrite = "mount-readwrite"
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted for
+	// variable paths. The Policy stanza is a short hand for granting several
+	// of these. When capabilities are combined we take the union of all
+	// capabilities. If the deny capability is present, it takes precedence
+	// and overwrites all other capabilities.
+
+	VariableCapabilityDeny    = "deny"
+	VariableCapabilityList    = "list"
+	VariableCapabilityRead    = "read"
+	VariableCapabilityWrite   = "write"
+	VariableCapabilityDestroy = "destroy"
+)
+
 var (
-	validVolume = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+	validVolume       = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+	validVariablePath = regexp.MustCompile(`^[a-zA-Z0-9-_~/*]{1,128}$`)
 )
 
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
 	Namespaces  []*NamespacePolicy  `hcl:"namespace,expand"`
 	HostVolumes []*HostVolumePolicy `hcl:"host_volume,expand"`
+	Variables   []*VariablePolicy   `hcl:"variable,expand"`
 	Agent       *AgentPolicy        `hcl:"agent"`
 	Node        *NodePolicy         `hcl:"node"`
 	Operator    *OperatorPolicy     `hcl:"operator"`
@@ -82,6 +102,7 @@ type Policy struct {
 func (p *Policy) IsEmpty() bool {
 	return len(p.Namespaces) == 0 &&
 		len(p.HostVolumes) == 0 &&
+		len(p.Variables) == 0 &&
 		p.Agent == nil &&
 		p.Node == nil &&
 		p.Operator == nil &&
@@ -103,6 +124,14 @@ type HostVolumePolicy struct {
 	Capabilities []string
 }
 
+// VariablePolicy is the policy for variables under a path, which may contain
+// a glob to match a whole subtree
+type VariablePolicy struct {
+	Path         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
+}
+
 type AgentPolicy struct {
 	Policy string
 }
@@ -133,6 +162,12 @@ func isPolicyValid(policy string) bool {
 	}
 }
 
+// isAgentPolicyValid checks the validity of an agent policy, which accepts
+// the debug level in addition to the standard coarse grained levels.
+func isAgentPolicyValid(policy string) bool {
+	return policy == PolicyDebug || isPolicyValid(policy)
+}
+
 func (p *PluginPolicy) isValid() bool {
 	switch p.Policy {
 	case PolicyDeny, PolicyRead, PolicyList:
@@ -213,6 +248,16 @@ func isHostVolumeCapabilityValid(cap string) bool {
 	}
 }
 
+func isVariableCapabilityValid(cap string) bool {
+	switch cap {
+	case VariableCapabilityDeny, VariableCapabilityList, VariableCapabilityRead,
+		VariableCapabilityWrite, VariableCapabilityDestroy:
+		return true
+	default:
+		return false
+	}
+}
+
 func expandHostVolumePolicy(policy string) []string {
 	switch policy {
 	case PolicyDeny:
@@ -226,6 +271,22 @@ func expandHostVolumePolicy(policy string) []string {
 	}
 }
 
+func expandVariablePolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariableCapabilityDeny}
+	case PolicyList:
+		return []string{VariableCapabilityList}
+	case PolicyRead:
+		return []string{VariableCapabilityList, VariableCapabilityRead}
+	case PolicyWrite:
+		return []string{VariableCapabilityList, VariableCapabilityRead,
+			VariableCapabilityWrite, VariableCapabilityDestroy}
+	default:
+		return nil
+	}
+}
+
 // Parse is used to parse the specified ACL rules into an
 // intermediary set of policies, before being compiled into
 // the ACL
@@ -291,7 +352,28 @@ func Parse(rules string) (*Policy, error) {
 		}
 	}
 
-	if p.Agent != nil && !isPolicyValid(p.Agent.Policy) {
+	for _, v := range p.Variables {
+		if !validVariablePath.MatchString(v.Path) {
+			return nil, fmt.Errorf("Invalid variable path: %#v", v)
+		}
+		if v.Policy != "" && !isPolicyValid(v.Policy) {
+			return nil, fmt.Errorf("Invalid variable policy: %#v", v)
+		}
+		for _, cap := range v.Capabilities {
+			if !isVariableCapabilityValid(cap) {
+				return nil, fmt.Errorf("Invalid variable capability '%s': %#v", cap, v)
+			}
+		}
+
+		// Expand the short hand policy to the capabilities and
+		// add to any existing capabilities
+		if v.Policy != "" {
+			extraCap := expandVariablePolicy(v.Policy)
+			v.Capabilities = append(v.Capabilities, extraCap...)
+		}
+	}
+
+	if p.Agent != nil && !isAgentPolicyValid(p.Agent.Policy) {
 		return nil, fmt.Errorf("Invalid agent policy: %#v", p.Agent)
 	}
 
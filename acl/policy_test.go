@@ -283,6 +283,55 @@ func TestParse(t *testing.T) {
 			"Invalid host volume name",
 			nil,
 		},
+		{
+			`
+			variable "nomad/jobs/*" {
+				policy = "read"
+			}
+			`,
+			"",
+			&Policy{
+				Variables: []*VariablePolicy{
+					{
+						Path:   "nomad/jobs/*",
+						Policy: PolicyRead,
+						Capabilities: []string{
+							VariableCapabilityList,
+							VariableCapabilityRead,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			variable "nomad/jobs/example" {
+				capabilities = ["write", "destroy"]
+			}
+			`,
+			"",
+			&Policy{
+				Variables: []*VariablePolicy{
+					{
+						Path:   "nomad/jobs/example",
+						Policy: "",
+						Capabilities: []string{
+							VariableCapabilityWrite,
+							VariableCapabilityDestroy,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			variable "nomad jobs" {
+				policy = "read"
+			}
+			`,
+			"Invalid variable path",
+			nil,
+		},
 		{
 			`
 			plugin {
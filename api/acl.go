@@ -62,6 +62,232 @@ func (a *ACLPolicies) Info(policyName string, q *QueryOptions) (*ACLPolicy, *Que
 	return &resp, wm, nil
 }
 
+// ACLRoles is used to query the ACL role endpoints.
+type ACLRoles struct {
+	client *Client
+}
+
+// ACLRoles returns a new handle on the ACL roles.
+func (c *Client) ACLRoles() *ACLRoles {
+	return &ACLRoles{client: c}
+}
+
+// List is used to dump all of the roles.
+func (a *ACLRoles) List(q *QueryOptions) ([]*ACLRoleListStub, *QueryMeta, error) {
+	var resp []*ACLRoleListStub
+	qm, err := a.client.query("/v1/acl/roles", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Upsert is used to create or update a role
+func (a *ACLRoles) Upsert(role *ACLRole, q *WriteOptions) (*WriteMeta, error) {
+	if role == nil || role.Name == "" {
+		return nil, fmt.Errorf("missing role name")
+	}
+	wm, err := a.client.write("/v1/acl/role/"+role.Name, role, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a role
+func (a *ACLRoles) Delete(roleName string, q *WriteOptions) (*WriteMeta, error) {
+	if roleName == "" {
+		return nil, fmt.Errorf("missing role name")
+	}
+	wm, err := a.client.delete("/v1/acl/role/"+roleName, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a specific role
+func (a *ACLRoles) Info(roleName string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	if roleName == "" {
+		return nil, nil, fmt.Errorf("missing role name")
+	}
+	var resp ACLRole
+	wm, err := a.client.query("/v1/acl/role/"+roleName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLAuthMethods is used to query the ACL auth method endpoints.
+type ACLAuthMethods struct {
+	client *Client
+}
+
+// ACLAuthMethods returns a new handle on the ACL auth methods.
+func (c *Client) ACLAuthMethods() *ACLAuthMethods {
+	return &ACLAuthMethods{client: c}
+}
+
+// List is used to dump all of the auth methods.
+func (a *ACLAuthMethods) List(q *QueryOptions) ([]*ACLAuthMethodListStub, *QueryMeta, error) {
+	var resp []*ACLAuthMethodListStub
+	qm, err := a.client.query("/v1/acl/auth-methods", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Upsert is used to create or update an auth method
+func (a *ACLAuthMethods) Upsert(method *ACLAuthMethod, q *WriteOptions) (*WriteMeta, error) {
+	if method == nil || method.Name == "" {
+		return nil, fmt.Errorf("missing auth method name")
+	}
+	wm, err := a.client.write("/v1/acl/auth-method/"+method.Name, method, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete an auth method
+func (a *ACLAuthMethods) Delete(methodName string, q *WriteOptions) (*WriteMeta, error) {
+	if methodName == "" {
+		return nil, fmt.Errorf("missing auth method name")
+	}
+	wm, err := a.client.delete("/v1/acl/auth-method/"+methodName, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a specific auth method
+func (a *ACLAuthMethods) Info(methodName string, q *QueryOptions) (*ACLAuthMethod, *QueryMeta, error) {
+	if methodName == "" {
+		return nil, nil, fmt.Errorf("missing auth method name")
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.query("/v1/acl/auth-method/"+methodName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLBindingRules is used to query the ACL binding rule endpoints.
+type ACLBindingRules struct {
+	client *Client
+}
+
+// ACLBindingRules returns a new handle on the ACL binding rules.
+func (c *Client) ACLBindingRules() *ACLBindingRules {
+	return &ACLBindingRules{client: c}
+}
+
+// List is used to dump all of the binding rules.
+func (a *ACLBindingRules) List(q *QueryOptions) ([]*ACLBindingRule, *QueryMeta, error) {
+	var resp []*ACLBindingRule
+	qm, err := a.client.query("/v1/acl/binding-rules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create a binding rule. The ID is generated server side.
+func (a *ACLBindingRules) Create(rule *ACLBindingRule, q *WriteOptions) (*WriteMeta, error) {
+	if rule == nil {
+		return nil, fmt.Errorf("missing binding rule")
+	}
+	wm, err := a.client.write("/v1/acl/binding-rules", rule, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Upsert is used to update an existing binding rule
+func (a *ACLBindingRules) Upsert(rule *ACLBindingRule, q *WriteOptions) (*WriteMeta, error) {
+	if rule == nil || rule.ID == "" {
+		return nil, fmt.Errorf("missing binding rule ID")
+	}
+	wm, err := a.client.write("/v1/acl/binding-rule/"+rule.ID, rule, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a binding rule
+func (a *ACLBindingRules) Delete(ruleID string, q *WriteOptions) (*WriteMeta, error) {
+	if ruleID == "" {
+		return nil, fmt.Errorf("missing binding rule ID")
+	}
+	wm, err := a.client.delete("/v1/acl/binding-rule/"+ruleID, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a specific binding rule
+func (a *ACLBindingRules) Info(ruleID string, q *QueryOptions) (*ACLBindingRule, *QueryMeta, error) {
+	if ruleID == "" {
+		return nil, nil, fmt.Errorf("missing binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.query("/v1/acl/binding-rule/"+ruleID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLAuth is used to query the ACL login endpoints.
+type ACLAuth struct {
+	client *Client
+}
+
+// ACLAuth returns a new handle on the ACL login endpoints.
+func (c *Client) ACLAuth() *ACLAuth {
+	return &ACLAuth{client: c}
+}
+
+// Login is used to exchange a bearer token, such as an OIDC ID token or a
+// third party JWT, for a Nomad ACL token.
+func (a *ACLAuth) Login(req *ACLLoginRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/login", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// OIDCAuthURL is used to generate the OIDC provider URL that starts a login
+// flow.
+func (a *ACLAuth) OIDCAuthURL(req *ACLOIDCAuthURLRequest, q *WriteOptions) (string, *WriteMeta, error) {
+	var resp ACLOIDCAuthURLResponse
+	wm, err := a.client.write("/v1/acl/oidc/auth-url", req, &resp, q)
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.AuthURL, wm, nil
+}
+
+// OIDCCompleteAuth is used to complete an OIDC login flow by exchanging the
+// authorization code returned on the provider callback for an ACL token.
+func (a *ACLAuth) OIDCCompleteAuth(req *ACLOIDCCompleteAuthRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/oidc/complete-auth", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // ACLTokens is used to query the ACL token endpoints.
 type ACLTokens struct {
 	client *Client
@@ -171,26 +397,132 @@ type ACLPolicy struct {
 	ModifyIndex uint64
 }
 
-// ACLToken represents a client token which is used to Authenticate
-type ACLToken struct {
-	AccessorID  string
-	SecretID    string
+// ACLRole is used to represent a named group of policies
+type ACLRole struct {
 	Name        string
-	Type        string
+	Description string
 	Policies    []string
-	Global      bool
-	CreateTime  time.Time
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
-type ACLTokenListStub struct {
-	AccessorID  string
+// ACLRoleListStub is used to for listing ACL roles
+type ACLRoleListStub struct {
 	Name        string
-	Type        string
+	Description string
 	Policies    []string
-	Global      bool
-	CreateTime  time.Time
 	CreateIndex uint64
 	ModifyIndex uint64
 }
+
+// ACLAuthMethod is used to represent an external authentication method
+type ACLAuthMethod struct {
+	Name          string
+	Type          string
+	TokenLocality string
+	MaxTokenTTL   time.Duration
+	Default       bool
+	Config        *ACLAuthMethodConfig
+	CreateIndex   uint64
+	ModifyIndex   uint64
+}
+
+// ACLAuthMethodConfig holds the type specific configuration for an auth
+// method
+type ACLAuthMethodConfig struct {
+	OIDCDiscoveryURL     string
+	OIDCClientID         string
+	OIDCClientSecret     string
+	AllowedRedirectURIs  []string
+	JWTValidationPubKeys []string
+	BoundIssuer          string
+	BoundAudiences       []string
+	SigningAlgs          []string
+	ClaimMappings        map[string]string
+	ListClaimMappings    map[string]string
+}
+
+// ACLAuthMethodListStub is used to for listing ACL auth methods
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRule is used to convert an auth method identity into ACL token
+// grants
+type ACLBindingRule struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	Selector    string
+	BindType    string
+	BindName    string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLLoginRequest is used to login via an auth method
+type ACLLoginRequest struct {
+	AuthMethodName string
+	LoginToken     string
+}
+
+// ACLOIDCAuthURLRequest is used to request an OIDC authentication URL
+type ACLOIDCAuthURLRequest struct {
+	AuthMethodName string
+	RedirectURI    string
+	ClientNonce    string
+}
+
+// ACLOIDCAuthURLResponse is used to return the OIDC authentication URL
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete an OIDC login flow
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	RedirectURI    string
+	ClientNonce    string
+	Code           string
+	State          string
+}
+
+// ACLToken represents a client token which is used to Authenticate
+type ACLToken struct {
+	AccessorID string
+	SecretID   string
+	Name       string
+	Type       string
+	Policies   []string
+	Roles      []string
+	Global     bool
+	CreateTime time.Time
+
+	// ExpirationTime is the time at which the token becomes invalid. A nil
+	// value means the token never expires.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field for setting an expiration relative
+	// to the token's creation time. It is only used when creating a token.
+	ExpirationTTL time.Duration
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+type ACLTokenListStub struct {
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -59,6 +60,21 @@ func (a *Allocations) PrefixList(prefix string) ([]*AllocationListStub, *QueryMe
 	return a.List(&QueryOptions{Prefix: prefix})
 }
 
+// ListFields is like List but projects which large, rarely-needed stub
+// fields the server should include, reducing the payload for callers polling
+// many allocations. The supported fields are "task_states" and "resources";
+// fields not listed are omitted from the stubs.
+func (a *Allocations) ListFields(fields []string, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["fields"] = strings.Join(fields, ",")
+	return a.List(q)
+}
+
 // Info is used to retrieve a single allocation.
 func (a *Allocations) Info(allocID string, q *QueryOptions) (*Allocation, *QueryMeta, error) {
 	var resp Allocation
@@ -312,6 +328,33 @@ func (a *Allocations) Stop(alloc *Allocation, q *QueryOptions) (*AllocStopRespon
 	return &resp, err
 }
 
+// Hold places an allocation on hold for debugging. While held, the scheduler
+// will not replace the allocation and restarts are paused. Setting release
+// removes an existing hold.
+func (a *Allocations) Hold(alloc *Allocation, release bool, q *QueryOptions) (*AllocHoldResponse, error) {
+	var resp AllocHoldResponse
+	path := "/v1/allocation/" + alloc.ID + "/hold"
+	if release {
+		path += "?release=true"
+	}
+	_, err := a.client.putQuery(path, nil, &resp, q)
+	return &resp, err
+}
+
+// AllocHoldResponse is the response to an `AllocHoldRequest`
+type AllocHoldResponse struct {
+	// EvalID is the id of the follow up evalution created for the alloc.
+	EvalID string
+
+	WriteMeta
+}
+
+// SetCheckpointMeta replaces the checkpoint metadata stored for an
+// allocation.
+func (a *Allocations) SetCheckpointMeta(alloc *Allocation, meta map[string]string, q *WriteOptions) (*WriteMeta, error) {
+	return a.client.write("/v1/allocation/"+alloc.ID+"/checkpoint", meta, nil, q)
+}
+
 // AllocStopResponse is the response to an `AllocStopRequest`
 type AllocStopResponse struct {
 	// EvalID is the id of the follow up evalution for the rescheduled alloc.
@@ -360,12 +403,14 @@ type Allocation struct {
 	TaskStates            map[string]*TaskState
 	DeploymentID          string
 	DeploymentStatus      *AllocDeploymentStatus
+	NetworkStatus         *AllocNetworkStatus
 	FollowupEvalID        string
 	PreviousAllocation    string
 	NextAllocation        string
 	RescheduleTracker     *RescheduleTracker
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
+	CheckpointMeta        map[string]string
 	CreateIndex           uint64
 	ModifyIndex           uint64
 	AllocModifyIndex      uint64
@@ -447,6 +492,7 @@ type AllocationListStub struct {
 	ClientStatus          string
 	ClientDescription     string
 	TaskStates            map[string]*TaskState
+	AllocatedResources    *AllocatedResources `json:",omitempty"`
 	DeploymentStatus      *AllocDeploymentStatus
 	FollowupEvalID        string
 	RescheduleTracker     *RescheduleTracker
@@ -468,6 +514,14 @@ type AllocDeploymentStatus struct {
 	ModifyIndex uint64
 }
 
+// AllocNetworkStatus captures the status of an allocation's network during
+// runtime
+type AllocNetworkStatus struct {
+	InterfaceName string
+	Address       string
+	DNS           *DNSConfig
+}
+
 type AllocatedResources struct {
 	Tasks  map[string]*AllocatedTaskResources
 	Shared AllocatedSharedResources
@@ -592,6 +646,10 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// Hold is used to indicate that this allocation is held for debugging
+	// and should not be replaced or restarted until released.
+	Hold *bool
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -599,6 +657,12 @@ func (d DesiredTransition) ShouldMigrate() bool {
 	return d.Migrate != nil && *d.Migrate
 }
 
+// ShouldHold returns whether the transition object dictates that the
+// allocation is held for debugging.
+func (d DesiredTransition) ShouldHold() bool {
+	return d.Hold != nil && *d.Hold
+}
+
 // ExecStreamingIOOperation represents a stream write operation: either appending data or close (exclusively)
 type ExecStreamingIOOperation struct {
 	Data  []byte `json:"data,omitempty"`
@@ -58,6 +58,18 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// Filter is a go-bexpr expression used to filter the results of a list
+	// query server-side.
+	Filter string
+
+	// PerPage is the maximum number of entries a list query should return
+	// per page. Zero means no limit.
+	PerPage int32
+
+	// NextToken is the token of the entry a paged list query should resume
+	// at, as returned in the NextToken query meta of the previous page.
+	NextToken string
+
 	// Set HTTP parameters on the query.
 	Params map[string]string
 
@@ -93,6 +105,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is the token of the entry to pass as the NextToken query
+	// option to fetch the next page. It is empty on the last page.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -546,6 +562,15 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.Filter != "" {
+		r.params.Set("filter", q.Filter)
+	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", fmt.Sprint(q.PerPage))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
@@ -919,6 +944,10 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken, set for paged list responses
+	q.NextToken = header.Get("X-Nomad-NextToken")
+
 	return nil
 }
 
@@ -30,6 +30,17 @@ func (e *Evaluations) PrefixList(prefix string) ([]*Evaluation, *QueryMeta, erro
 	return e.List(&QueryOptions{Prefix: prefix})
 }
 
+// BrokerStats is used to retrieve a snapshot of the leader's eval broker
+// queues.
+func (e *Evaluations) BrokerStats(q *QueryOptions) (*EvalBrokerStats, *QueryMeta, error) {
+	var resp EvalBrokerStats
+	qm, err := e.client.query("/v1/evaluations/broker", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Info is used to query a single evaluation by its ID.
 func (e *Evaluations) Info(evalID string, q *QueryOptions) (*Evaluation, *QueryMeta, error) {
 	var resp Evaluation
@@ -52,6 +63,39 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// Retry is used to re-enqueue an evaluation that failed by reaching the
+// delivery limit.
+func (e *Evaluations) Retry(evalID string, q *WriteOptions) (*WriteMeta, error) {
+	return e.client.write("/v1/evaluation/"+evalID+"/retry", nil, nil, q)
+}
+
+// Discard is used to cancel an evaluation that failed by reaching the
+// delivery limit rather than retrying it.
+func (e *Evaluations) Discard(evalID string, q *WriteOptions) (*WriteMeta, error) {
+	return e.client.write("/v1/evaluation/"+evalID+"/discard", nil, nil, q)
+}
+
+// EvalBrokerStats is a snapshot of the eval broker queues on the leader
+type EvalBrokerStats struct {
+	TotalReady   int
+	TotalUnacked int
+	TotalBlocked int
+	TotalWaiting int
+	TotalAcks    int
+	TotalNacks   int
+	ByScheduler  map[string]*EvalBrokerSchedulerStats
+}
+
+// EvalBrokerSchedulerStats is the per-scheduler breakdown of eval broker
+// statistics
+type EvalBrokerSchedulerStats struct {
+	Ready         int
+	Unacked       int
+	Acks          int
+	Nacks         int
+	OldestEvalAge time.Duration
+}
+
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
 	ID                   string
@@ -77,6 +121,8 @@ type Evaluation struct {
 	QuotaLimitReached    string
 	AnnotatePlan         bool
 	QueuedAllocations    map[string]int
+	QueuePosition        int
+	EarliestStart        time.Time
 	SnapshotIndex        uint64
 	CreateIndex          uint64
 	ModifyIndex          uint64
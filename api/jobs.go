@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/cronexpr"
@@ -88,6 +89,35 @@ type RegisterOptions struct {
 	ModifyIndex    uint64
 	PolicyOverride bool
 	PreserveCounts bool
+
+	// Submission is the original job specification source, stored
+	// server-side so it can be retrieved later.
+	Submission *JobSubmission
+}
+
+// JobSubmission is the original source material of a job as it was
+// submitted, stored by the servers alongside the registered job.
+type JobSubmission struct {
+	// Source is the original job specification.
+	Source string
+
+	// Format indicates whether Source is "hcl1", "hcl2", or "json".
+	Format string
+
+	// VariableFlags are the HCL2 variable assignments given with -var
+	// flags at submission time.
+	VariableFlags map[string]string
+
+	// Variables is the content of the HCL2 var files given at submission
+	// time.
+	Variables string
+
+	// Namespace, JobID, Version, and JobModifyIndex identify the job
+	// version this submission produced. They are set by the server.
+	Namespace      string
+	JobID          string
+	Version        uint64
+	JobModifyIndex uint64
 }
 
 // Register is used to register a new job. It returns the ID
@@ -116,6 +146,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		}
 		req.PolicyOverride = opts.PolicyOverride
 		req.PreserveCounts = opts.PreserveCounts
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -153,6 +184,18 @@ func (j *Jobs) Info(jobID string, q *QueryOptions) (*Job, *QueryMeta, error) {
 	return &resp, qm, nil
 }
 
+// Submission is used to retrieve the original source material of the given
+// job version, if it was recorded at registration time.
+func (j *Jobs) Submission(jobID string, version uint64, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var resp JobSubmission
+	path := fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), version)
+	qm, err := j.client.query(path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Scale is used to retrieve information about a particular
 // job given its unique ID.
 func (j *Jobs) Scale(jobID, group string, count *int, message string, error bool, meta map[string]interface{},
@@ -202,6 +245,35 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Diff compares two stored versions of a job, or the selected local version
+// against the job's counterpart in another federated region. Nil versions
+// select the current version.
+func (j *Jobs) Diff(jobID string, fromVersion, toVersion *uint64, compareRegion string, q *QueryOptions) (*JobDiffResponse, *QueryMeta, error) {
+	u, err := url.Parse("/v1/job/" + url.PathEscape(jobID) + "/diff")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := u.Query()
+	if fromVersion != nil {
+		v.Add("from_version", strconv.FormatUint(*fromVersion, 10))
+	}
+	if toVersion != nil {
+		v.Add("to_version", strconv.FormatUint(*toVersion, 10))
+	}
+	if compareRegion != "" {
+		v.Add("compare_region", compareRegion)
+	}
+	u.RawQuery = v.Encode()
+
+	var resp JobDiffResponse
+	qm, err := j.client.query(u.String(), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -265,6 +337,37 @@ func (j *Jobs) Evaluations(jobID string, q *QueryOptions) ([]*Evaluation, *Query
 	return resp, qm, nil
 }
 
+// EvalLineageLink is an edge in the evaluation lineage graph for a job.
+type EvalLineageLink struct {
+	// FromEval is the ID of the evaluation the link originates from
+	FromEval string
+
+	// ToEval is the ID of the evaluation the link points at
+	ToEval string
+
+	// Relation describes how ToEval descends from FromEval
+	Relation string
+}
+
+// EvalLineageResponse holds the evaluations for a job together with the
+// links between them as a graph.
+type EvalLineageResponse struct {
+	Evaluations []*Evaluation
+	Links       []*EvalLineageLink
+	QueryMeta
+}
+
+// EvalLineage is used to query the evaluation lineage graph for the given
+// job ID.
+func (j *Jobs) EvalLineage(jobID string, q *QueryOptions) (*EvalLineageResponse, *QueryMeta, error) {
+	var resp EvalLineageResponse
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/lineage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Deregister is used to remove an existing job. If purge is set to true, the job
 // is deregistered and purged from the system versus still being queryable and
 // eventually GC'ed from the system. Most callers should not specify purge.
@@ -337,6 +440,32 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 	return resp.EvalID, wm, nil
 }
 
+// PeriodicLaunchRecord captures the timing of a single launch of a periodic
+// job.
+type PeriodicLaunchRecord struct {
+	ScheduledTime  time.Time
+	DispatchTime   time.Time
+	AllocStartTime time.Time
+}
+
+// PeriodicLaunchStatsResponse holds the recent launch timing records of a
+// periodic job along with its 95th percentile launch delay.
+type PeriodicLaunchStatsResponse struct {
+	Launches       []*PeriodicLaunchRecord
+	P95LaunchDelay time.Duration
+}
+
+// PeriodicLaunchStats returns the recent launch timing records of the periodic
+// job so scheduled vs actual launch times can be compared.
+func (j *Jobs) PeriodicLaunchStats(jobID string, q *QueryOptions) (*PeriodicLaunchStatsResponse, *QueryMeta, error) {
+	var resp PeriodicLaunchStatsResponse
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/periodic/stats", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // PlanOptions is used to pass through job planning parameters
 type PlanOptions struct {
 	Diff           bool
@@ -432,6 +561,36 @@ func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	return &resp, wm, nil
 }
 
+// Suspend is used to suspend a job: every task group is scaled to zero
+// while the job stays registered and its prior counts are preserved.
+func (j *Jobs) Suspend(jobID string, q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
+	var resp JobRegisterResponse
+	req := &JobSuspendRequest{
+		JobID:   jobID,
+		Suspend: true,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/suspend", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Resume is used to resume a suspended job, restoring the task group counts
+// it had before it was suspended.
+func (j *Jobs) Resume(jobID string, q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
+	var resp JobRegisterResponse
+	req := &JobSuspendRequest{
+		JobID:   jobID,
+		Suspend: false,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/suspend", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // periodicForceResponse is used to deserialize a force response
 type periodicForceResponse struct {
 	EvalID string
@@ -744,6 +903,9 @@ func (p *PeriodicConfig) Canonicalize() {
 // passed time.
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	if *p.SpecType == PeriodicSpecCron {
+		if spec := strings.TrimSpace(*p.Spec); strings.HasPrefix(spec, "@every") {
+			return everyParseNext(spec, fromTime)
+		}
 		e, err := cronexpr.Parse(*p.Spec)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", *p.Spec, err)
@@ -754,6 +916,19 @@ func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	return time.Time{}, nil
 }
 
+// everyParseNext computes the next launch of an "@every <duration>" spec.
+// ---  THIS LOGIC IS REPLICATED IN helper/crontab
+// and should be kept in sync.
+func everyParseNext(spec string, fromTime time.Time) (time.Time, error) {
+	arg := strings.TrimSpace(strings.TrimPrefix(spec, "@every"))
+	every, err := time.ParseDuration(arg)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", spec, err)
+	}
+
+	return fromTime.Add(every - time.Duration(fromTime.Nanosecond())), nil
+}
+
 // cronParseNext is a helper that parses the next time for the given expression
 // but captures any panic that may occur in the underlying library.
 // ---  THIS FUNCTION IS REPLICATED IN nomad/structs/structs.go
@@ -777,6 +952,44 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 	return time.LoadLocation(*p.TimeZone)
 }
 
+// JobExpiration is for serializing the expiration config for a job.
+type JobExpiration struct {
+	At    *string        `mapstructure:"at"`
+	TTL   *time.Duration `mapstructure:"ttl"`
+	Purge *bool          `mapstructure:"purge"`
+}
+
+func (e *JobExpiration) Canonicalize() {
+	if e.At == nil {
+		e.At = stringToPtr("")
+	}
+	if e.TTL == nil {
+		e.TTL = timeToPtr(0)
+	}
+	if e.Purge == nil {
+		e.Purge = boolToPtr(false)
+	}
+}
+
+// JobOwnership is for serializing the ownership metadata for a job.
+type JobOwnership struct {
+	Team    *string `mapstructure:"team"`
+	Contact *string `mapstructure:"contact"`
+	RepoURL *string `mapstructure:"repo_url"`
+}
+
+func (o *JobOwnership) Canonicalize() {
+	if o.Team == nil {
+		o.Team = stringToPtr("")
+	}
+	if o.Contact == nil {
+		o.Contact = stringToPtr("")
+	}
+	if o.RepoURL == nil {
+		o.RepoURL = stringToPtr("")
+	}
+}
+
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
 	Payload      string
@@ -787,6 +1000,7 @@ type ParameterizedJobConfig struct {
 // Job is used to serialize a job.
 type Job struct {
 	Stop              *bool
+	Suspended         *bool
 	Region            *string
 	Namespace         *string
 	ID                *string
@@ -809,6 +1023,9 @@ type Job struct {
 	Reschedule        *ReschedulePolicy
 	Migrate           *MigrateStrategy
 	Meta              map[string]string
+	GCThreshold       *time.Duration `mapstructure:"gc_threshold"`
+	Expiration        *JobExpiration
+	Ownership         *JobOwnership
 	ConsulToken       *string `mapstructure:"consul_token"`
 	VaultToken        *string `mapstructure:"vault_token"`
 	VaultNamespace    *string `mapstructure:"vault_namespace"`
@@ -857,6 +1074,9 @@ func (j *Job) Canonicalize() {
 	if j.Stop == nil {
 		j.Stop = boolToPtr(false)
 	}
+	if j.Suspended == nil {
+		j.Suspended = boolToPtr(false)
+	}
 	if j.Region == nil {
 		j.Region = stringToPtr(GlobalRegion)
 	}
@@ -905,6 +1125,12 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+	if j.Expiration != nil {
+		j.Expiration.Canonicalize()
+	}
+	if j.Ownership != nil {
+		j.Ownership.Canonicalize()
+	}
 	if j.Update != nil {
 		j.Update.Canonicalize()
 	} else if *j.Type == JobTypeService {
@@ -1147,10 +1373,11 @@ type JobRegisterRequest struct {
 	// If EnforceIndex is set then the job will only be registered if the passed
 	// JobModifyIndex matches the current Jobs index. If the index is zero, the
 	// register only occurs if the job is new.
-	EnforceIndex   bool   `json:",omitempty"`
-	JobModifyIndex uint64 `json:",omitempty"`
-	PolicyOverride bool   `json:",omitempty"`
-	PreserveCounts bool   `json:",omitempty"`
+	EnforceIndex   bool           `json:",omitempty"`
+	JobModifyIndex uint64         `json:",omitempty"`
+	PolicyOverride bool           `json:",omitempty"`
+	PreserveCounts bool           `json:",omitempty"`
+	Submission     *JobSubmission `json:",omitempty"`
 
 	WriteRequest
 }
@@ -1272,6 +1499,14 @@ type JobVersionsResponse struct {
 	QueryMeta
 }
 
+// JobDiffResponse is used for a job diff request
+type JobDiffResponse struct {
+	Diff        *JobDiff
+	FromVersion uint64
+	ToVersion   uint64
+	QueryMeta
+}
+
 // JobStabilityRequest is used to marked a job as stable.
 type JobStabilityRequest struct {
 	// Job to set the stability on
@@ -1289,6 +1524,17 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobSuspendRequest is used to suspend or resume a job.
+type JobSuspendRequest struct {
+	// JobID is the job being suspended or resumed
+	JobID string
+
+	// Suspend suspends the job when true and resumes it when false
+	Suspend bool
+
+	WriteRequest
+}
+
 // JobEvaluateRequest is used when we just need to re-evaluate a target job
 type JobEvaluateRequest struct {
 	JobID       string
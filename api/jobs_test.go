@@ -249,6 +249,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Status:            stringToPtr(""),
 				StatusDescription: stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				CreateIndex:       uint64ToPtr(0),
@@ -340,6 +341,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Status:            stringToPtr(""),
 				StatusDescription: stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				CreateIndex:       uint64ToPtr(0),
@@ -412,6 +414,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
@@ -579,6 +582,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
@@ -671,6 +675,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 										CanaryTags:  []string{"canary", "global", "cache"},
 										PortLabel:   "db",
 										AddressMode: "auto",
+										Provider:    "consul",
 										Checks: []ServiceCheck{
 											{
 												Name:     "alive",
@@ -738,6 +743,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
@@ -825,6 +831,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
@@ -991,6 +998,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
@@ -1155,6 +1163,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				VaultNamespace:    stringToPtr(""),
 				NomadTokenID:      stringToPtr(""),
 				Stop:              boolToPtr(false),
+				Suspended:         boolToPtr(false),
 				Stable:            boolToPtr(false),
 				Version:           uint64ToPtr(0),
 				Status:            stringToPtr(""),
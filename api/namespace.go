@@ -67,11 +67,12 @@ func (n *Namespaces) Delete(namespace string, q *WriteOptions) (*WriteMeta, erro
 
 // Namespace is used to serialize a namespace.
 type Namespace struct {
-	Name        string
-	Description string
-	Quota       string
-	CreateIndex uint64
-	ModifyIndex uint64
+	Name                string
+	Description         string
+	Quota               string
+	RequireJobOwnership bool
+	CreateIndex         uint64
+	ModifyIndex         uint64
 }
 
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
@@ -45,6 +45,31 @@ func (n *Nodes) PrefixList(prefix string) ([]*NodeListStub, *QueryMeta, error) {
 	return n.List(&QueryOptions{Prefix: prefix})
 }
 
+// NodeInventoryDrift describes a node whose OS inventory deviates from the
+// fleet baseline.
+type NodeInventoryDrift struct {
+	NodeID string
+	Name   string
+	Drift  map[string]string
+}
+
+// NodeInventoryReport holds the fleet OS inventory baseline and the nodes
+// drifting from it.
+type NodeInventoryReport struct {
+	Baseline map[string]string
+	Drifted  []*NodeInventoryDrift
+}
+
+// OSInventory is used to query the fleet OS inventory report.
+func (n *Nodes) OSInventory(q *QueryOptions) (*NodeInventoryReport, *QueryMeta, error) {
+	var resp NodeInventoryReport
+	qm, err := n.client.query("/v1/nodes/inventory", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Info is used to query a specific node by its ID.
 func (n *Nodes) Info(nodeID string, q *QueryOptions) (*Node, *QueryMeta, error) {
 	var resp Node
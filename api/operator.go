@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strconv"
@@ -124,6 +125,10 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// NamespaceAdmissionWeights optionally weights how often evaluations
+	// from each namespace are dequeued by the eval broker.
+	NamespaceAdmissionWeights map[string]float64
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -234,6 +239,126 @@ func (op *Operator) SnapshotRestore(in io.Reader, q *WriteOptions) (*WriteMeta,
 	return wm, nil
 }
 
+// FeatureFlags is the cluster-wide set of feature flag overrides.
+type FeatureFlags struct {
+	// Flags maps feature names to whether the feature is enabled.
+	Flags map[string]bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// FeatureFlagsResponse is the response object that wraps FeatureFlags.
+type FeatureFlagsResponse struct {
+	FeatureFlags *FeatureFlags
+
+	QueryMeta
+}
+
+// FeatureFlagsGet is used to query the current cluster feature flags.
+func (op *Operator) FeatureFlagsGet(q *QueryOptions) (*FeatureFlagsResponse, *QueryMeta, error) {
+	var resp FeatureFlagsResponse
+	qm, err := op.c.query("/v1/operator/features", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// FeatureFlagsSet is used to update the cluster feature flags.
+func (op *Operator) FeatureFlagsSet(flags map[string]bool, q *WriteOptions) (*WriteMeta, error) {
+	return op.c.write("/v1/operator/features", flags, nil, q)
+}
+
+// FreezeWindow declares a recurring change-freeze window during which job
+// registrations in the covered namespaces are rejected.
+type FreezeWindow struct {
+	// Name is a unique human readable name for the window
+	Name string
+
+	// Namespaces is the set of namespaces covered by the window. An empty
+	// set covers all namespaces.
+	Namespaces []string
+
+	// Spec is a cron specification for when the window opens
+	Spec string
+
+	// Duration is how long the window stays open after each trigger
+	Duration time.Duration
+
+	// Enabled allows a window to be retained but inactive
+	Enabled bool
+}
+
+// FreezeWindowsResponse is the response object that wraps the declared
+// freeze windows.
+type FreezeWindowsResponse struct {
+	Windows []*FreezeWindow
+
+	CreateIndex uint64
+	ModifyIndex uint64
+
+	QueryMeta
+}
+
+// FreezeWindowsGet is used to query the cluster's change-freeze windows.
+func (op *Operator) FreezeWindowsGet(q *QueryOptions) (*FreezeWindowsResponse, *QueryMeta, error) {
+	var resp FreezeWindowsResponse
+	qm, err := op.c.query("/v1/operator/freeze-windows", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// FreezeWindowsSet is used to replace the cluster's change-freeze windows.
+func (op *Operator) FreezeWindowsSet(windows []*FreezeWindow, q *WriteOptions) (*WriteMeta, error) {
+	return op.c.write("/v1/operator/freeze-windows", windows, nil, q)
+}
+
+// ClusterHeadroomShape is the hypothetical task shape used for a headroom
+// query.
+type ClusterHeadroomShape struct {
+	// CPU is the amount of CPU in MHz required per instance.
+	CPU int64
+
+	// MemoryMB is the amount of memory in MB required per instance.
+	MemoryMB int64
+
+	// Ports is the number of dynamic ports required per instance.
+	Ports int
+}
+
+// ClusterHeadroomEntry is the headroom of the nodes in a single datacenter
+// and node class.
+type ClusterHeadroomEntry struct {
+	Datacenter string
+	NodeClass  string
+	Nodes      int
+	Fit        int
+}
+
+// ClusterHeadroomResponse is the response object that wraps the computed
+// cluster headroom.
+type ClusterHeadroomResponse struct {
+	Headroom []*ClusterHeadroomEntry
+	Total    int
+
+	QueryMeta
+}
+
+// ClusterHeadroom computes how many instances of the given task shape could
+// still be placed on the cluster's ready nodes.
+func (op *Operator) ClusterHeadroom(shape *ClusterHeadroomShape, q *QueryOptions) (*ClusterHeadroomResponse, *QueryMeta, error) {
+	var resp ClusterHeadroomResponse
+	path := fmt.Sprintf("/v1/operator/headroom?cpu=%d&memory=%d&ports=%d", shape.CPU, shape.MemoryMB, shape.Ports)
+	qm, err := op.c.query(path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 type License struct {
 	// The unique identifier of the license
 	LicenseID string
@@ -296,6 +421,10 @@ func (op *Operator) LicensePut(license string, q *WriteOptions) (*WriteMeta, err
 	return wm, nil
 }
 
+func (op *Operator) LicenseReset(q *WriteOptions) (*WriteMeta, error) {
+	return op.c.delete("/v1/operator/license", nil, q)
+}
+
 func (op *Operator) LicenseGet(q *QueryOptions) (*LicenseReply, *QueryMeta, error) {
 	var reply LicenseReply
 	qm, err := op.c.query("/v1/operator/license", &reply, q)
@@ -304,3 +433,124 @@ func (op *Operator) LicenseGet(q *QueryOptions) (*LicenseReply, *QueryMeta, erro
 	}
 	return &reply, qm, nil
 }
+
+// RegionLicense is the license state of a single federated region.
+type RegionLicense struct {
+	// Region is the name of the federated region
+	Region string
+
+	// License is the region's parsed license, or nil when the region has
+	// none
+	License *License
+
+	// Error is set when the region's license could not be retrieved
+	Error string
+}
+
+// LicenseReportResponse is used to return the aggregated per-region license
+// report.
+type LicenseReportResponse struct {
+	// Regions holds one entry per federated region, sorted by region name
+	Regions []*RegionLicense
+
+	// FeatureDeltas maps each feature that is licensed in some regions but
+	// not all of them to the sorted list of regions missing it
+	FeatureDeltas map[string][]string
+
+	// Warnings describe multiregion jobs that require a license feature
+	// missing in one of their target regions
+	Warnings []string
+
+	QueryMeta
+}
+
+// LicenseReport is used to query the aggregated per-region license report.
+func (op *Operator) LicenseReport(q *QueryOptions) (*LicenseReportResponse, *QueryMeta, error) {
+	var reply LicenseReportResponse
+	qm, err := op.c.query("/v1/operator/license/report", &reply, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &reply, qm, nil
+}
+
+// LicenseEvent records a change to the stored cluster license.
+type LicenseEvent struct {
+	// Action is the change that occurred: apply or reset
+	Action string
+
+	// AccessorID is the accessor ID of the ACL token that requested the
+	// change, if any
+	AccessorID string
+
+	// OldLicenseID is the ID of the license being replaced or removed
+	OldLicenseID string
+
+	// NewLicenseID is the ID of the newly applied license
+	NewLicenseID string
+
+	// Time is the unix nanosecond timestamp the change was requested
+	Time int64
+
+	CreateIndex uint64
+}
+
+// LicenseHistoryResponse is used to return the license audit trail.
+type LicenseHistoryResponse struct {
+	Events []*LicenseEvent
+	QueryMeta
+}
+
+// LicenseHistory is used to query the license audit trail, oldest first.
+func (op *Operator) LicenseHistory(q *QueryOptions) (*LicenseHistoryResponse, *QueryMeta, error) {
+	var reply LicenseHistoryResponse
+	qm, err := op.c.query("/v1/operator/license/history", &reply, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &reply, qm, nil
+}
+
+// SigningKeyStub is the public metadata of a workload identity signing key.
+// The key material itself never leaves the servers.
+type SigningKeyStub struct {
+	KeyID       string
+	Active      bool
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// KeyringRotateResponse is returned when the signing keyring is rotated and
+// holds the newly activated key.
+type KeyringRotateResponse struct {
+	Key *SigningKeyStub
+	WriteMeta
+}
+
+// KeyringList is used to list the workload identity signing keyring.
+func (op *Operator) KeyringList(q *QueryOptions) ([]*SigningKeyStub, *QueryMeta, error) {
+	var resp []*SigningKeyStub
+	qm, err := op.c.query("/v1/operator/keyring", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// KeyringRotate is used to rotate the active signing key. The previous keys
+// are retained until they are removed with KeyringRemove.
+func (op *Operator) KeyringRotate(q *WriteOptions) (*KeyringRotateResponse, *WriteMeta, error) {
+	var resp KeyringRotateResponse
+	wm, err := op.c.write("/v1/operator/keyring/rotate", nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// KeyringRemove is used to remove an inactive, unused signing key from the
+// keyring.
+func (op *Operator) KeyringRemove(keyID string, q *WriteOptions) (*WriteMeta, error) {
+	return op.c.delete("/v1/operator/keyring/"+keyID, nil, q)
+}
@@ -37,3 +37,34 @@ type SearchResponse struct {
 	Truncations map[contexts.Context]bool
 	QueryMeta
 }
+
+// ResolveIdentifier returns typed matches for a prefix across all contexts.
+func (s *Search) ResolveIdentifier(prefix string, q *QueryOptions) (*ResolveIdentifierResponse, *QueryMeta, error) {
+	var resp ResolveIdentifierResponse
+	req := &ResolveIdentifierRequest{Prefix: prefix}
+
+	qm, err := s.client.putQuery("/v1/search/resolve", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &resp, qm, nil
+}
+
+type ResolveIdentifierRequest struct {
+	Prefix string
+	QueryOptions
+}
+
+// IdentifierMatch pairs a matched ID with the context it was found in.
+type IdentifierMatch struct {
+	Context   contexts.Context
+	ID        string
+	Namespace string
+}
+
+type ResolveIdentifierResponse struct {
+	Matches     []*IdentifierMatch
+	Truncations map[contexts.Context]bool
+	QueryMeta
+}
@@ -59,6 +59,83 @@ func (a *SentinelPolicies) Info(policyName string, q *QueryOptions) (*SentinelPo
 	return &resp, wm, nil
 }
 
+// Overrides is used to list the recorded soft-mandatory policy overrides
+func (a *SentinelPolicies) Overrides(q *QueryOptions) ([]*SentinelOverride, *QueryMeta, error) {
+	var resp []*SentinelOverride
+	qm, err := a.client.query("/v1/sentinel/overrides", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Test is used to dry-run a policy against a job without submitting either
+func (a *SentinelPolicies) Test(policy *SentinelPolicy, job *Job, q *WriteOptions) (*SentinelPolicyTestResponse, *WriteMeta, error) {
+	if policy == nil {
+		return nil, nil, fmt.Errorf("missing policy")
+	}
+	if job == nil {
+		return nil, nil, fmt.Errorf("missing job")
+	}
+	req := &SentinelPolicyTestRequest{
+		Policy: policy,
+		Job:    job,
+	}
+	var resp SentinelPolicyTestResponse
+	wm, err := a.client.write("/v1/sentinel/test", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ListModules is used to dump all of the modules.
+func (a *SentinelPolicies) ListModules(q *QueryOptions) ([]*SentinelModuleListStub, *QueryMeta, error) {
+	var resp []*SentinelModuleListStub
+	qm, err := a.client.query("/v1/sentinel/modules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// UpsertModule is used to create or update a module
+func (a *SentinelPolicies) UpsertModule(module *SentinelModule, q *WriteOptions) (*WriteMeta, error) {
+	if module == nil || module.Name == "" {
+		return nil, fmt.Errorf("missing module name")
+	}
+	wm, err := a.client.write("/v1/sentinel/module/"+module.Name, module, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// DeleteModule is used to delete a module
+func (a *SentinelPolicies) DeleteModule(moduleName string, q *WriteOptions) (*WriteMeta, error) {
+	if moduleName == "" {
+		return nil, fmt.Errorf("missing module name")
+	}
+	wm, err := a.client.delete("/v1/sentinel/module/"+moduleName, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// ModuleInfo is used to query a specific module
+func (a *SentinelPolicies) ModuleInfo(moduleName string, q *QueryOptions) (*SentinelModule, *QueryMeta, error) {
+	if moduleName == "" {
+		return nil, nil, fmt.Errorf("missing module name")
+	}
+	var resp SentinelModule
+	wm, err := a.client.query("/v1/sentinel/module/"+moduleName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 type SentinelPolicy struct {
 	Name             string
 	Description      string
@@ -77,3 +154,42 @@ type SentinelPolicyListStub struct {
 	CreateIndex      uint64
 	ModifyIndex      uint64
 }
+
+// SentinelModule is a reusable source unit that policies can import
+type SentinelModule struct {
+	Name        string
+	Description string
+	Source      string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+type SentinelModuleListStub struct {
+	Name        string
+	Description string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SentinelOverride is a record of a job submission that overrode
+// soft-mandatory Sentinel policies
+type SentinelOverride struct {
+	Policies    []string
+	JobID       string
+	Namespace   string
+	AccessorID  string
+	Time        int64
+	CreateIndex uint64
+}
+
+// SentinelPolicyTestRequest is used to dry-run a policy against a job
+type SentinelPolicyTestRequest struct {
+	Policy *SentinelPolicy
+	Job    *Job
+}
+
+// SentinelPolicyTestResponse is the result of a policy dry-run
+type SentinelPolicyTestResponse struct {
+	Passed bool
+	Trace  string
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Variables is used to access the encrypted key/value variables stored by
+// the Nomad servers.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// List is used to list the metadata of the variables in the request
+// namespace, optionally under a path prefix.
+func (v *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := v.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list variable metadata under a path prefix.
+func (v *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{Prefix: prefix}
+	} else {
+		q.Prefix = prefix
+	}
+
+	return v.List(q)
+}
+
+// Read is used to fetch the variable at a path.
+func (v *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	var resp Variable
+	qm, err := v.client.query("/v1/var/"+path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Put is used to create or update the variable at its path.
+func (v *Variables) Put(variable *Variable, q *WriteOptions) (*WriteMeta, error) {
+	if variable == nil || variable.Path == "" {
+		return nil, fmt.Errorf("missing variable path")
+	}
+	wm, err := v.client.write("/v1/var/"+variable.Path, variable, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete the variable at a path.
+func (v *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := v.client.delete("/v1/var/"+path, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Variable is a key/value document stored at a path. Items are returned
+// decrypted and are encrypted by the servers before being persisted.
+type Variable struct {
+	Namespace string
+	Path      string
+	Items     map[string]string
+
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// VariableMetadata is the list representation of a variable; it carries
+// everything but the items.
+type VariableMetadata struct {
+	Namespace string
+	Path      string
+
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
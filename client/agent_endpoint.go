@@ -31,11 +31,11 @@ func NewAgentEndpoint(c *Client) *Agent {
 }
 
 func (a *Agent) Profile(args *structs.AgentPprofRequest, reply *structs.AgentPprofResponse) error {
-	// Check ACL for agent write
+	// Check ACL for agent debug
 	aclObj, err := a.c.ResolveToken(args.AuthToken)
 	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowAgentWrite() {
+	} else if aclObj != nil && !aclObj.AllowAgentDebug() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -68,6 +68,14 @@ type allocRunner struct {
 	// managing SI tokens
 	sidsClient consul.ServiceIdentityAPI
 
+	// widClient is the client used by the identity hook for deriving
+	// workload identity tokens
+	widClient cinterfaces.WorkloadIdentityAPI
+
+	// varClient is the client used by the template hook for reading
+	// variables
+	varClient cinterfaces.VariablesAPI
+
 	// vaultClient is the used to manage Vault tokens
 	vaultClient vaultclient.VaultClient
 
@@ -187,6 +195,8 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		clientConfig:             config.ClientConfig,
 		consulClient:             config.Consul,
 		sidsClient:               config.ConsulSI,
+		widClient:                config.WorkloadIdentity,
+		varClient:                config.Variables,
 		vaultClient:              config.Vault,
 		tasks:                    make(map[string]*taskrunner.TaskRunner, len(tg.Tasks)),
 		waitCh:                   make(chan struct{}),
@@ -247,6 +257,8 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			DynamicRegistry:      ar.dynamicRegistry,
 			Consul:               ar.consulClient,
 			ConsulSI:             ar.sidsClient,
+			WorkloadIdentity:     ar.widClient,
+			Variables:            ar.varClient,
 			Vault:                ar.vaultClient,
 			DeviceStatsReporter:  ar.deviceStatsReporter,
 			CSIManager:           ar.csiManager,
@@ -386,8 +398,16 @@ func (ar *allocRunner) Restore() error {
 		return err
 	}
 
+	// Restore the network status so the alloc continues to report its
+	// assigned address across agent restarts.
+	ns, err := ar.stateDB.GetNetworkStatus(ar.id)
+	if err != nil {
+		return err
+	}
+
 	ar.stateLock.Lock()
 	ar.state.DeploymentStatus = ds
+	ar.state.NetworkStatus = ns
 	ar.stateLock.Unlock()
 
 	states := make(map[string]*structs.TaskState)
@@ -617,6 +637,10 @@ func (ar *allocRunner) clientAlloc(taskStates map[string]*structs.TaskState) *st
 		a.DeploymentStatus = d.Copy()
 	}
 
+	if n := ar.state.NetworkStatus; n != nil {
+		a.NetworkStatus = n.Copy()
+	}
+
 	// Compute the ClientStatus
 	if ar.state.ClientStatus != "" {
 		// The client status is being forced
@@ -700,6 +724,29 @@ func (ar *allocRunner) SetClientStatus(clientStatus string) {
 	ar.state.ClientStatus = clientStatus
 }
 
+// SetNetworkStatus is used to set the network status of the allocation once
+// the network setup hook has run. The status is persisted so it survives
+// agent restarts.
+func (ar *allocRunner) SetNetworkStatus(s *structs.AllocNetworkStatus) {
+	ar.stateLock.Lock()
+	defer ar.stateLock.Unlock()
+	ar.state.NetworkStatus = s.Copy()
+
+	if err := ar.stateDB.PutNetworkStatus(ar.id, ar.state.NetworkStatus); err != nil {
+		// Failing to persist the network status is not fatal: if the
+		// agent restarts the network hook will run again and set it.
+		ar.logger.Error("error storing network status", "error", err)
+	}
+}
+
+// NetworkStatus returns the network status of the allocation, which may be
+// nil if the network hook has not run or the alloc uses host networking.
+func (ar *allocRunner) NetworkStatus() *structs.AllocNetworkStatus {
+	ar.stateLock.Lock()
+	defer ar.stateLock.Unlock()
+	return ar.state.NetworkStatus.Copy()
+}
+
 // AllocState returns a copy of allocation state including a snapshot of task
 // states.
 func (ar *allocRunner) AllocState() *state.State {
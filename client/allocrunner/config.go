@@ -35,6 +35,12 @@ type Config struct {
 	// ConsulSI is the Consul client used to manage service identity tokens.
 	ConsulSI consul.ServiceIdentityAPI
 
+	// WorkloadIdentity is the client used to derive workload identity tokens.
+	WorkloadIdentity interfaces.WorkloadIdentityAPI
+
+	// Variables is the client used to read variables for task templates.
+	Variables interfaces.VariablesAPI
+
 	// Vault is the Vault client to use to retrieve Vault tokens
 	Vault vaultclient.VaultClient
 
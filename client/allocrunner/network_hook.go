@@ -16,6 +16,10 @@ type networkHook struct {
 	// network is created
 	setter networkIsolationSetter
 
+	// statusSetter is a callback to the alloc runner to set the network
+	// status once network setup has succeeded
+	statusSetter networkStatusSetter
+
 	// manager is used when creating the network namespace. This defaults to
 	// bind mounting a network namespace descritor under /var/run/netns but
 	// can be created by a driver if nessicary
@@ -36,9 +40,11 @@ type networkHook struct {
 
 func newNetworkHook(logger hclog.Logger, ns networkIsolationSetter,
 	alloc *structs.Allocation, netManager drivers.DriverNetworkManager,
-	netConfigurator NetworkConfigurator) *networkHook {
+	netConfigurator NetworkConfigurator,
+	networkStatusSetter networkStatusSetter) *networkHook {
 	return &networkHook{
 		setter:              ns,
+		statusSetter:        networkStatusSetter,
 		alloc:               alloc,
 		manager:             netManager,
 		networkConfigurator: netConfigurator,
@@ -73,9 +79,16 @@ func (h *networkHook) Prerun() error {
 	}
 
 	if created {
-		if err := h.networkConfigurator.Setup(context.TODO(), h.alloc, spec); err != nil {
+		status, err := h.networkConfigurator.Setup(context.TODO(), h.alloc, spec)
+		if err != nil {
 			return fmt.Errorf("failed to configure networking for alloc: %v", err)
 		}
+
+		// If the network status is available, upsert it into the alloc
+		// runner so it is reported to the server and exposed via the API
+		if status != nil {
+			h.statusSetter.SetNetworkStatus(status)
+		}
 	}
 	return nil
 }
@@ -78,9 +78,9 @@ func newCNINetworkConfiguratorWithConf(logger log.Logger, cniPath, cniInterfaceP
 }
 
 // Setup calls the CNI plugins with the add action
-func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec) error {
+func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec) (*structs.AllocNetworkStatus, error) {
 	if err := c.ensureCNIInitialized(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Depending on the version of bridge cni plugin used, a known race could occure
@@ -88,15 +88,16 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 	// in one of them to fail. This rety attempts to overcome those erroneous failures.
 	const retry = 3
 	var firstError error
+	var res *cni.CNIResult
 	for attempt := 1; ; attempt++ {
-		//TODO eventually returning the IP from the result would be nice to have in the alloc
-		if _, err := c.cni.Setup(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(getPortMapping(alloc, c.ignorePortMappingHostIP))); err != nil {
+		var err error
+		if res, err = c.cni.Setup(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(getPortMapping(alloc, c.ignorePortMappingHostIP))); err != nil {
 			c.logger.Warn("failed to configure network", "err", err, "attempt", attempt)
 			switch attempt {
 			case 1:
 				firstError = err
 			case retry:
-				return fmt.Errorf("failed to configure network: %v", firstError)
+				return nil, fmt.Errorf("failed to configure network: %v", firstError)
 			}
 
 			// Sleep for 1 second + jitter
@@ -106,8 +107,77 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 		break
 	}
 
-	return nil
+	return c.cniToAllocNet(res)
+}
+
+// cniToAllocNet converts a cni.CNIResult to an AllocNetworkStatus or returns
+// an error. The first interface with a sandbox and an assigned address is
+// used, so bridge mode uses the veth inside the namespace rather than the
+// host side.
+func (c *cniNetworkConfigurator) cniToAllocNet(res *cni.CNIResult) (*structs.AllocNetworkStatus, error) {
+	if len(res.Interfaces) == 0 {
+		return nil, fmt.Errorf("failed to configure network: no interfaces found")
+	}
+
+	netStatus := new(structs.AllocNetworkStatus)
+
+	// Unfortunately the go-cni library returns interfaces in an unordered map
+	// meaning the results may be nondeterministic depending on CNI plugin
+	// output, so sort the interface names for a stable choice
+	names := make([]string, 0, len(res.Interfaces))
+	for k := range res.Interfaces {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	// Use the first sandbox interface with an IP address
+	for _, name := range names {
+		iface := res.Interfaces[name]
+		if iface == nil {
+			continue
+		}
+
+		if iface.Sandbox != "" && len(iface.IPConfigs) > 0 {
+			netStatus.Address = iface.IPConfigs[0].IP.String()
+			netStatus.InterfaceName = name
+			break
+		}
+	}
+
+	// If no IP address was found, use the first interface with an address
+	// assigned as a fallback
+	if netStatus.Address == "" {
+		for _, name := range names {
+			iface := res.Interfaces[name]
+			if iface == nil {
+				continue
+			}
+
+			if len(iface.IPConfigs) > 0 {
+				c.logger.Debug("no sandbox interface with an address found, using first available", "interface", name)
+				netStatus.Address = iface.IPConfigs[0].IP.String()
+				netStatus.InterfaceName = name
+				break
+			}
+		}
+	}
+
+	// An address should always be returned by the plugins, but do not fail
+	// setup over missing status information
+	if netStatus.Address == "" {
+		c.logger.Warn("no address could be found from CNI result")
+	}
+
+	// Use the first DNS results
+	if len(res.DNS) > 0 {
+		netStatus.DNS = &structs.DNSConfig{
+			Servers:  res.DNS[0].Nameservers,
+			Searches: res.DNS[0].Search,
+			Options:  res.DNS[0].Options,
+		}
+	}
 
+	return netStatus, nil
 }
 
 func loadCNIConf(confDir, name string) ([]byte, error) {
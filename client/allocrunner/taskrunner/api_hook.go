@@ -0,0 +1,90 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+)
+
+// taskAPISocketName is the name of the socket file within the task's secrets
+// directory on which the agent serves the task API.
+const taskAPISocketName = "api.sock"
+
+// apiHook creates a unix socket in the task's secrets directory and serves
+// the agent's HTTP API on it so the task can interact with Nomad without
+// network access to the agent.
+type apiHook struct {
+	shutdownCtx context.Context
+	srv         config.APIListenerRegistrar
+	logger      log.Logger
+
+	// ln is the unix domain socket listener for the task. It is nil until
+	// the first Prestart and after Stop.
+	ln net.Listener
+}
+
+func newAPIHook(shutdownCtx context.Context, srv config.APIListenerRegistrar, logger log.Logger) *apiHook {
+	h := &apiHook{
+		shutdownCtx: shutdownCtx,
+		srv:         srv,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*apiHook) Name() string {
+	return "api"
+}
+
+func (h *apiHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	if h.ln != nil {
+		// The socket is already being served from a previous start
+		return nil
+	}
+
+	udsPath := filepath.Join(req.TaskDir.SecretsDir, taskAPISocketName)
+
+	// Remove any stale socket left behind by a previous agent
+	if err := os.Remove(udsPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing stale task api socket: %v", err)
+	}
+
+	udsLn, err := net.Listen("unix", udsPath)
+	if err != nil {
+		return fmt.Errorf("error creating task api socket: %v", err)
+	}
+
+	// Tasks may run as a different user than the agent, so the socket must
+	// be accessible by the task.
+	if err := os.Chmod(udsPath, 0666); err != nil {
+		udsLn.Close()
+		return fmt.Errorf("error setting task api socket permissions: %v", err)
+	}
+
+	go func() {
+		err := h.srv.Serve(h.shutdownCtx, udsLn)
+		if err != nil && !errors.Is(err, net.ErrClosed) && !errors.Is(err, context.Canceled) {
+			h.logger.Error("error serving task api", "error", err)
+		}
+	}()
+
+	h.ln = udsLn
+	return nil
+}
+
+func (h *apiHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	if h.ln != nil {
+		if err := h.ln.Close(); err != nil {
+			h.logger.Debug("error closing task api socket", "error", err)
+		}
+		h.ln = nil
+	}
+	return nil
+}
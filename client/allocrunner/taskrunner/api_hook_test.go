@@ -0,0 +1,78 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the api hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*apiHook)(nil)
+var _ interfaces.TaskStopHook = (*apiHook)(nil)
+
+// fakeAPIListenerRegistrar blocks on the provided listener until it is closed
+// or the context is canceled.
+type fakeAPIListenerRegistrar struct{}
+
+func (fakeAPIListenerRegistrar) Serve(ctx context.Context, ln net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// TestTaskRunner_APIHook asserts the api hook creates a unix socket in the
+// task's secrets directory and cleans it up on stop.
+func TestTaskRunner_APIHook(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	secretsDir, err := ioutil.TempDir("", "nomadtest_apihook")
+	require.NoError(err)
+	defer os.RemoveAll(secretsDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := newAPIHook(ctx, fakeAPIListenerRegistrar{}, testlog.HCLogger(t))
+	require.Equal("api", h.Name())
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: secretsDir},
+	}
+	require.NoError(h.Prestart(ctx, req, &interfaces.TaskPrestartResponse{}))
+
+	// The socket should exist and accept connections
+	udsPath := filepath.Join(secretsDir, taskAPISocketName)
+	conn, err := net.Dial("unix", udsPath)
+	require.NoError(err)
+	conn.Close()
+
+	// Prestart is idempotent across task restarts
+	require.NoError(h.Prestart(ctx, req, &interfaces.TaskPrestartResponse{}))
+
+	require.NoError(h.Stop(ctx, &interfaces.TaskStopRequest{}, &interfaces.TaskStopResponse{}))
+	require.Nil(h.ln)
+}
@@ -349,7 +349,14 @@ func (h *envoyBootstrapHook) newEnvoyBootstrapArgs(
 	}
 
 	if service.Connect.IsGateway() {
-		gateway = "ingress" // more types in the future
+		switch {
+		case service.Connect.Gateway.Ingress != nil:
+			gateway = "ingress"
+		case service.Connect.Gateway.Terminating != nil:
+			gateway = "terminating"
+		case service.Connect.Gateway.Mesh != nil:
+			gateway = "mesh"
+		}
 	}
 
 	h.logger.Debug("bootstrapping envoy",
@@ -0,0 +1,95 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/envproviders"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// envProviderHook runs the env providers configured on the client and injects
+// the environment variables and files they return into the task. It runs
+// before every task start so providers can refresh short-lived values such as
+// credentials.
+type envProviderHook struct {
+	configs []*structs.ClientEnvProviderConfig
+	alloc   *structs.Allocation
+	logger  log.Logger
+}
+
+func newEnvProviderHook(tr *TaskRunner, logger log.Logger) *envProviderHook {
+	h := &envProviderHook{
+		configs: tr.clientConfig.EnvProviders,
+		alloc:   tr.Alloc(),
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*envProviderHook) Name() string {
+	return "env_providers"
+}
+
+func (h *envProviderHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	env := make(map[string]string)
+	for _, config := range h.configs {
+		if !config.AllowsNamespace(h.alloc.Namespace) {
+			continue
+		}
+
+		provider, err := envproviders.New(config.Name, h.logger)
+		if err != nil {
+			return err
+		}
+
+		buildResp, err := provider.BuildEnv(&envproviders.BuildRequest{
+			AllocID:   h.alloc.ID,
+			JobID:     h.alloc.JobID,
+			Namespace: h.alloc.Namespace,
+			TaskName:  req.Task.Name,
+			TaskDir:   req.TaskDir.Dir,
+			Config:    config.Config,
+		})
+		if err != nil {
+			return fmt.Errorf("env provider %q failed: %v", config.Name, err)
+		}
+
+		for k, v := range buildResp.Env {
+			env[k] = v
+		}
+
+		if err := h.writeFiles(req.TaskDir.Dir, buildResp.Files); err != nil {
+			return fmt.Errorf("env provider %q failed: %v", config.Name, err)
+		}
+	}
+
+	resp.Env = env
+	return nil
+}
+
+// writeFiles writes the files returned by a provider into the task directory,
+// rejecting paths that escape it.
+func (h *envProviderHook) writeFiles(taskDir string, files map[string]string) error {
+	for rel, contents := range files {
+		path := filepath.Join(taskDir, rel)
+		if !strings.HasPrefix(path, taskDir) {
+			return fmt.Errorf("file %q escapes task directory", rel)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0640); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/envproviders"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the env provider hook implements the expected interface
+var _ interfaces.TaskPrestartHook = (*envProviderHook)(nil)
+
+// fileEnvProvider is a test provider that injects an env var and a file.
+type fileEnvProvider struct{}
+
+func (*fileEnvProvider) Name() string { return "file-test" }
+
+func (*fileEnvProvider) BuildEnv(req *envproviders.BuildRequest) (*envproviders.BuildResponse, error) {
+	return &envproviders.BuildResponse{
+		Env: map[string]string{
+			"PROVIDED": "true",
+		},
+		Files: map[string]string{
+			"local/provided.txt": "contents",
+		},
+	}, nil
+}
+
+func TestTaskRunner_EnvProviderHook(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, envproviders.Register("file-test", func(log.Logger) envproviders.Provider {
+		return &fileEnvProvider{}
+	}))
+
+	taskDir, err := ioutil.TempDir("", "envprovider_hook")
+	require.NoError(t, err)
+	defer os.RemoveAll(taskDir)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	h := &envProviderHook{
+		configs: []*structs.ClientEnvProviderConfig{
+			{
+				Name:   "static",
+				Config: map[string]string{"SITE": "east"},
+			},
+			{
+				Name: "file-test",
+			},
+			{
+				// Not allowed for the alloc's namespace and skipped.
+				Name:       "does-not-exist",
+				Namespaces: []string{"other"},
+			},
+		},
+		alloc:  alloc,
+		logger: testlog.HCLogger(t),
+	}
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{Dir: taskDir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	// Env from both providers is merged.
+	require.Equal(t, map[string]string{
+		"SITE":     "east",
+		"PROVIDED": "true",
+	}, resp.Env)
+
+	// The file was written into the task directory.
+	contents, err := ioutil.ReadFile(filepath.Join(taskDir, "local/provided.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "contents", string(contents))
+}
+
+func TestTaskRunner_EnvProviderHook_EscapingFile(t *testing.T) {
+	t.Parallel()
+
+	taskDir, err := ioutil.TempDir("", "envprovider_hook")
+	require.NoError(t, err)
+	defer os.RemoveAll(taskDir)
+
+	h := &envProviderHook{
+		alloc:  mock.Alloc(),
+		logger: testlog.HCLogger(t),
+	}
+
+	err = h.writeFiles(taskDir, map[string]string{
+		"../escape.txt": "contents",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "escapes task directory")
+}
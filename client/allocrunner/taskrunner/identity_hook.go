@@ -0,0 +1,126 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// the name of this hook, used in logs
+	identityHookName = "workload_identity"
+
+	// identityTokenFile is the name of the file holding the workload
+	// identity token inside the task's secret directory
+	identityTokenFile = "nomad_identity.jwt"
+
+	// identityTokenFilePerms is the level of file permissions granted on the
+	// file in the secrets directory for the task
+	identityTokenFilePerms = 0440
+
+	// taskIdentityTokenEnv is the environment variable holding the workload
+	// identity token inside the task
+	taskIdentityTokenEnv = "NOMAD_IDENTITY_TOKEN"
+)
+
+type identityHookConfig struct {
+	alloc     *structs.Allocation
+	task      *structs.Task
+	widClient cinterfaces.WorkloadIdentityAPI
+	logger    hclog.Logger
+}
+
+// identityHook derives a signed workload identity token for the task and
+// makes it available in the task's secrets directory and environment.
+type identityHook struct {
+	// alloc is the allocation
+	alloc *structs.Allocation
+
+	// task is the task to derive an identity for
+	task *structs.Task
+
+	// widClient is the Nomad server proxy for deriving identity tokens
+	widClient cinterfaces.WorkloadIdentityAPI
+
+	// logger is used to log
+	logger hclog.Logger
+}
+
+func newIdentityHook(c identityHookConfig) *identityHook {
+	return &identityHook{
+		alloc:     c.alloc,
+		task:      c.task,
+		widClient: c.widClient,
+		logger:    c.logger.Named(identityHookName),
+	}
+}
+
+func (h *identityHook) Name() string {
+	return identityHookName
+}
+
+func (h *identityHook) Prestart(
+	ctx context.Context,
+	req *interfaces.TaskPrestartRequest,
+	resp *interfaces.TaskPrestartResponse) error {
+
+	// optimistically try to recover the token from disk
+	token, err := h.recoverToken(req.TaskDir.SecretsDir)
+	if err != nil {
+		return err
+	}
+
+	// need to ask for a new identity token & persist it to disk
+	if token == "" {
+		tokens, err := h.widClient.DeriveWorkloadIdentities(h.alloc, []string{h.task.Name})
+		if err != nil {
+			return err
+		}
+		token = tokens[h.task.Name]
+		if token == "" {
+			return errors.Errorf("no identity token was derived for task %q", h.task.Name)
+		}
+		if err := h.writeToken(req.TaskDir.SecretsDir, token); err != nil {
+			return err
+		}
+	}
+
+	resp.Env = map[string]string{
+		taskIdentityTokenEnv: token,
+	}
+	resp.Done = true
+	return nil
+}
+
+// writeToken writes the given token to disk
+func (h *identityHook) writeToken(dir string, token string) error {
+	tokenPath := filepath.Join(dir, identityTokenFile)
+	if err := ioutil.WriteFile(tokenPath, []byte(token), identityTokenFilePerms); err != nil {
+		return errors.Wrap(err, "failed to write workload identity token")
+	}
+	return nil
+}
+
+// recoverToken returns the token saved to disk in the secrets directory for
+// the task if it exists, or the empty string if the file does not exist. an
+// error is returned only for some other (unexpected) error case.
+func (h *identityHook) recoverToken(dir string) (string, error) {
+	tokenPath := filepath.Join(dir, identityTokenFile)
+	token, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			// something went wrong other than the file not existing
+			return "", errors.Wrap(err, "failed to recover workload identity token")
+		}
+		// the file does not exist; token is not set
+		return "", nil
+	}
+	return string(token), nil
+}
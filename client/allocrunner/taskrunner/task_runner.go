@@ -57,6 +57,10 @@ const (
 	// updates have come in since the last one was handled, we only need to
 	// handle the last one.
 	triggerUpdateChCap = 1
+
+	// holdPollInterval is how often the restart loop checks whether a hold
+	// placed on the allocation has been released.
+	holdPollInterval = 3 * time.Second
 )
 
 type TaskRunner struct {
@@ -164,6 +168,12 @@ type TaskRunner struct {
 	// service identity tokens
 	siClient consul.ServiceIdentityAPI
 
+	// widClient is the client used to derive workload identity tokens
+	widClient cinterfaces.WorkloadIdentityAPI
+
+	// varClient is the client used by the template hook for reading variables
+	varClient cinterfaces.VariablesAPI
+
 	// vaultClient is the client to use to derive and renew Vault tokens
 	vaultClient vaultclient.VaultClient
 
@@ -185,6 +195,12 @@ type TaskRunner struct {
 	resourceUsage     *cstructs.TaskResourceUsage
 	resourceUsageLock sync.Mutex
 
+	// throttledPeriods is the last observed cgroup CPU throttling count and
+	// throttleEventEmitted tracks whether a task event has been emitted for
+	// it. Access is guarded by resourceUsageLock.
+	throttledPeriods     uint64
+	throttleEventEmitted bool
+
 	// deviceStatsReporter is used to lookup resource usage for alloc devices
 	deviceStatsReporter cinterfaces.DeviceStatsReporter
 
@@ -237,6 +253,13 @@ type Config struct {
 	// ConsulSI is the client to use for managing Consul SI tokens
 	ConsulSI consul.ServiceIdentityAPI
 
+	// WorkloadIdentity is the client to use for deriving workload identity
+	// tokens
+	WorkloadIdentity cinterfaces.WorkloadIdentityAPI
+
+	// Variables is the client to use for reading variables in task templates
+	Variables cinterfaces.VariablesAPI
+
 	// DynamicRegistry is where dynamic plugins should be registered.
 	DynamicRegistry dynamicplugins.Registry
 
@@ -304,6 +327,8 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		dynamicRegistry:      config.DynamicRegistry,
 		consulClient:         config.Consul,
 		siClient:             config.ConsulSI,
+		widClient:            config.WorkloadIdentity,
+		varClient:            config.Variables,
 		vaultClient:          config.Vault,
 		state:                tstate,
 		localState:           state.NewLocalState(),
@@ -667,6 +692,11 @@ func (tr *TaskRunner) emitExitResultEvent(result *drivers.ExitResult) {
 	if result.OOMKilled && !tr.clientConfig.DisableTaggedMetrics {
 		metrics.IncrCounterWithLabels([]string{"client", "allocs", "oom_killed"}, 1, tr.baseLabels)
 	}
+
+	if result.OOMKilled && tr.clientConfig.BackwardsCompatibleMetrics {
+		alloc := tr.Alloc()
+		metrics.IncrCounter([]string{"client", "allocs", alloc.Job.Name, alloc.TaskGroup, tr.allocID, tr.taskName, "oom_killed"}, 1)
+	}
 }
 
 // handleUpdates runs update hooks when triggerUpdateCh is ticked and exits
@@ -687,6 +717,24 @@ func (tr *TaskRunner) handleUpdates() {
 // shouldRestart determines whether the task should be restarted and updates
 // the task state unless the task is killed or terminated.
 func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
+	// If the allocation has been placed on hold, pause the restart policy
+	// until the hold is released so the task directory can be inspected.
+	if tr.Alloc().DesiredTransition.ShouldHold() {
+		tr.logger.Info("pausing restarts; allocation is on hold")
+		tr.UpdateState(structs.TaskStatePending, structs.NewTaskEvent(structs.TaskHeld))
+		for tr.Alloc().DesiredTransition.ShouldHold() {
+			select {
+			case <-time.After(holdPollInterval):
+			case <-tr.killCtx.Done():
+				tr.EmitEvent(structs.NewTaskEvent(structs.TaskKilled))
+				return false, 0
+			case <-tr.shutdownCtx.Done():
+				return false, 0
+			}
+		}
+		tr.logger.Info("hold released; resuming restart policy")
+	}
+
 	// Determine if we should restart
 	state, when := tr.restartTracker.GetState()
 	reason := tr.restartTracker.GetReason()
@@ -1299,10 +1347,34 @@ func (tr *TaskRunner) UpdateStats(ru *cstructs.TaskResourceUsage) {
 	tr.resourceUsage = ru
 	tr.resourceUsageLock.Unlock()
 	if ru != nil {
+		tr.checkCPUThrottling(ru)
 		tr.emitStats(ru)
 	}
 }
 
+// checkCPUThrottling emits a task event the first time cgroup CPU throttling
+// is observed for the task so users can distinguish slow tasks from
+// resource-limit pressure.
+func (tr *TaskRunner) checkCPUThrottling(ru *cstructs.TaskResourceUsage) {
+	if ru.ResourceUsage == nil || ru.ResourceUsage.CpuStats == nil {
+		return
+	}
+
+	periods := ru.ResourceUsage.CpuStats.ThrottledPeriods
+	tr.resourceUsageLock.Lock()
+	throttled := !tr.throttleEventEmitted && periods > tr.throttledPeriods
+	if throttled {
+		tr.throttleEventEmitted = true
+	}
+	tr.throttledPeriods = periods
+	tr.resourceUsageLock.Unlock()
+
+	if throttled {
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCPUThrottled).
+			SetCPUThrottled(periods, ru.ResourceUsage.CpuStats.ThrottledTime))
+	}
+}
+
 //TODO Remove Backwardscompat or use tr.Alloc()?
 func (tr *TaskRunner) setGaugeForMemory(ru *cstructs.TaskResourceUsage) {
 	alloc := tr.Alloc()
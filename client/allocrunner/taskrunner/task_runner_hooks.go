@@ -70,11 +70,33 @@ func (tr *TaskRunner) initHooks() {
 		newDeviceHook(tr.devicemanager, hookLogger),
 	}
 
+	// If the client can derive workload identities, add the identity hook so
+	// every task receives a signed identity token.
+	if tr.widClient != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newIdentityHook(identityHookConfig{
+			alloc:     tr.Alloc(),
+			task:      tr.Task(),
+			widClient: tr.widClient,
+			logger:    hookLogger,
+		}))
+	}
+
+	// If the agent is serving its HTTP API, expose it to the task over a
+	// unix socket in the task's secrets directory.
+	if tr.clientConfig.APIListenerRegistrar != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newAPIHook(tr.shutdownCtx, tr.clientConfig.APIListenerRegistrar, hookLogger))
+	}
+
 	// If the task has a CSI stanza, add the hook.
 	if task.CSIPluginConfig != nil {
 		tr.runnerHooks = append(tr.runnerHooks, newCSIPluginSupervisorHook(filepath.Join(tr.clientConfig.StateDir, "csi"), tr, tr, hookLogger))
 	}
 
+	// If any env providers are configured, add the hook
+	if len(tr.clientConfig.EnvProviders) != 0 {
+		tr.runnerHooks = append(tr.runnerHooks, newEnvProviderHook(tr, hookLogger))
+	}
+
 	// If Vault is enabled, add the hook
 	if task.Vault != nil {
 		tr.runnerHooks = append(tr.runnerHooks, newVaultHook(&vaultHookConfig{
@@ -92,12 +114,14 @@ func (tr *TaskRunner) initHooks() {
 	// If there are templates is enabled, add the hook
 	if len(task.Templates) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newTemplateHook(&templateHookConfig{
-			logger:       hookLogger,
-			lifecycle:    tr,
-			events:       tr,
-			templates:    task.Templates,
-			clientConfig: tr.clientConfig,
-			envBuilder:   tr.envBuilder,
+			logger:         hookLogger,
+			lifecycle:      tr,
+			events:         tr,
+			templates:      task.Templates,
+			clientConfig:   tr.clientConfig,
+			envBuilder:     tr.envBuilder,
+			nomadNamespace: tr.Alloc().Namespace,
+			variables:      tr.varClient,
 		}))
 	}
 
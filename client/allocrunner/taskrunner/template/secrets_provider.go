@@ -0,0 +1,82 @@
+package template
+
+import (
+	"fmt"
+	"sync"
+)
+
+// vaultProviderName is the name reserved for the built-in Vault secrets
+// provider, which is wired through consul-template directly rather than
+// through the provider registry.
+const vaultProviderName = "vault"
+
+// SecretsProvider is the interface a secret backend must implement to back
+// {{ secret ... }} lookups in the template stanza. Implementations are
+// registered by client-side plugins and instantiated per task from the
+// client configuration.
+type SecretsProvider interface {
+	// Read fetches the secret stored at the given path, returning its
+	// key/value data. Implementations should block until the secret is
+	// available or return an error.
+	Read(path string) (map[string]interface{}, error)
+}
+
+// SecretsProviderFactory instantiates a SecretsProvider from the
+// provider-specific configuration in the client config.
+type SecretsProviderFactory func(config map[string]string) (SecretsProvider, error)
+
+var (
+	// secretsProviderFactories holds the registered provider factories,
+	// keyed by provider name
+	secretsProviderFactories = map[string]SecretsProviderFactory{}
+	secretsProviderLock      sync.RWMutex
+)
+
+// RegisterSecretsProvider registers the factory for a named secrets
+// provider. It is intended to be called from the init function of a
+// client-side plugin and panics if the name is reserved or already in use.
+func RegisterSecretsProvider(name string, factory SecretsProviderFactory) {
+	secretsProviderLock.Lock()
+	defer secretsProviderLock.Unlock()
+
+	if name == vaultProviderName {
+		panic(fmt.Sprintf("secrets provider name %q is reserved", name))
+	}
+	if _, ok := secretsProviderFactories[name]; ok {
+		panic(fmt.Sprintf("secrets provider %q already registered", name))
+	}
+	secretsProviderFactories[name] = factory
+}
+
+// NewSecretsProviders instantiates the secrets providers configured on the
+// client. It returns an error if a configured provider has not been
+// registered so misconfiguration is surfaced when the task starts rather
+// than when a template lookup fails.
+func NewSecretsProviders(configs map[string]map[string]string) (map[string]SecretsProvider, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	secretsProviderLock.RLock()
+	defer secretsProviderLock.RUnlock()
+
+	providers := make(map[string]SecretsProvider, len(configs))
+	for name, config := range configs {
+		if name == vaultProviderName {
+			// Vault is handled natively by consul-template
+			continue
+		}
+
+		factory, ok := secretsProviderFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown secrets provider %q", name)
+		}
+
+		provider, err := factory(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure secrets provider %q: %v", name, err)
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
@@ -0,0 +1,62 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecretsProvider struct {
+	config map[string]string
+}
+
+func (f *fakeSecretsProvider) Read(path string) (map[string]interface{}, error) {
+	return map[string]interface{}{"path": path}, nil
+}
+
+func TestSecretsProvider_Registry(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecretsProvider("fake", func(config map[string]string) (SecretsProvider, error) {
+		return &fakeSecretsProvider{config: config}, nil
+	})
+
+	// Configured providers are instantiated with their config
+	providers, err := NewSecretsProviders(map[string]map[string]string{
+		"fake": {"region": "us-east-1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	fake, ok := providers["fake"].(*fakeSecretsProvider)
+	require.True(t, ok)
+	require.Equal(t, "us-east-1", fake.config["region"])
+
+	// Vault is reserved for the native consul-template integration
+	providers, err = NewSecretsProviders(map[string]map[string]string{
+		"vault": {},
+	})
+	require.NoError(t, err)
+	require.Empty(t, providers)
+
+	// Unknown providers surface a configuration error
+	_, err = NewSecretsProviders(map[string]map[string]string{
+		"missing": {},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown secrets provider "missing"`)
+
+	// Nothing configured yields no providers
+	providers, err = NewSecretsProviders(nil)
+	require.NoError(t, err)
+	require.Nil(t, providers)
+}
+
+func TestSecretsProvider_RegisterReserved(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		RegisterSecretsProvider("vault", func(config map[string]string) (SecretsProvider, error) {
+			return nil, nil
+		})
+	})
+}
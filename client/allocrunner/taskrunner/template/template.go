@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	texttemplate "text/template"
 	"time"
 
 	ctconf "github.com/hashicorp/consul-template/config"
@@ -19,6 +20,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -53,6 +55,10 @@ type TaskTemplateManager struct {
 	// runner is the consul-template runner
 	runner *manager.Runner
 
+	// secretsProviders holds the instantiated secret backends that may
+	// serve {{ secret ... }} lookups, keyed by provider name
+	secretsProviders map[string]SecretsProvider
+
 	// signals is a lookup map from the string representation of a signal to its
 	// actual signal
 	signals map[string]os.Signal
@@ -99,6 +105,15 @@ type TaskTemplateManagerConfig struct {
 	// MaxTemplateEventRate is the maximum rate at which we should emit events.
 	MaxTemplateEventRate time.Duration
 
+	// NomadNamespace is the alloc's Nomad namespace, used when reading
+	// variables with the nomadVar template function.
+	NomadNamespace string
+
+	// Variables is the client used to read variables for the nomadVar
+	// template function. It may be nil, in which case the function returns
+	// an error when used.
+	Variables cinterfaces.VariablesAPI
+
 	// retryRate is only used for testing and is used to increase the retry rate
 	retryRate time.Duration
 }
@@ -155,6 +170,16 @@ func NewTaskTemplateManager(config *TaskTemplateManagerConfig) (*TaskTemplateMan
 		tm.signals[tmpl.ChangeSignal] = sig
 	}
 
+	// Instantiate the configured secrets providers so misconfiguration
+	// fails the task at start rather than during a template lookup
+	if tc := config.ClientConfig.TemplateConfig; tc != nil {
+		providers, err := NewSecretsProviders(tc.SecretsProviders)
+		if err != nil {
+			return nil, err
+		}
+		tm.secretsProviders = providers
+	}
+
 	// Build the consul-template runner
 	runner, lookup, err := templateRunner(config)
 	if err != nil {
@@ -580,6 +605,9 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 		if !config.ClientConfig.TemplateConfig.DisableSandbox {
 			ct.SandboxPath = &config.TaskDir
 		}
+		ct.FuncMapMerge = texttemplate.FuncMap{
+			"nomadVar": nomadVarFunc(config.Variables, config.NomadNamespace),
+		}
 
 		// Set the permissions
 		if tmpl.Perms != "" {
@@ -598,6 +626,17 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 	return ctmpls, nil
 }
 
+// nomadVarFunc returns the template function that reads the decrypted items
+// of the Nomad variable at the given path within the alloc's namespace.
+func nomadVarFunc(client cinterfaces.VariablesAPI, namespace string) func(string) (map[string]string, error) {
+	return func(path string) (map[string]string, error) {
+		if client == nil {
+			return nil, fmt.Errorf("no variables client configured")
+		}
+		return client.ReadVariable(namespace, path)
+	}
+}
+
 // newRunnerConfig returns a consul-template runner configuration, setting the
 // Vault and Consul configurations based on the clients configs.
 func newRunnerConfig(config *TaskTemplateManagerConfig,
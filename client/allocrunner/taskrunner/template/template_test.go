@@ -18,6 +18,7 @@ import (
 
 	ctestutil "github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -121,6 +122,7 @@ type testHarness struct {
 	vault      *testutil.TestVault
 	consul     *ctestutil.TestServer
 	emitRate   time.Duration
+	variables  cinterfaces.VariablesAPI
 }
 
 // newTestHarness returns a harness starting a dev consul and vault server,
@@ -192,6 +194,8 @@ func (h *testHarness) startWithErr() error {
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.envBuilder,
 		MaxTemplateEventRate: h.emitRate,
+		NomadNamespace:       structs.DefaultNamespace,
+		Variables:            h.variables,
 		retryRate:            10 * time.Millisecond,
 	})
 
@@ -517,6 +521,60 @@ func TestTaskTemplateManager_Unblock_Static_NomadEnv(t *testing.T) {
 	}
 }
 
+// mockVariablesClient is a mock of the VariablesAPI interface that serves
+// variables from an in-memory map keyed by namespace/path.
+type mockVariablesClient struct {
+	variables map[string]map[string]string
+}
+
+func (m *mockVariablesClient) ReadVariable(namespace, path string) (map[string]string, error) {
+	items, ok := m.variables[namespace+"/"+path]
+	if !ok {
+		return nil, fmt.Errorf("variable %q not found in namespace %q", path, namespace)
+	}
+	return items, nil
+}
+
+func TestTaskTemplateManager_Unblock_Static_NomadVar(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render immediately
+	content := `password: {{with nomadVar "nomad/jobs/example"}}{{.password}}{{end}}`
+	expected := "password: passw0rd1"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.variables = &mockVariablesClient{
+		variables: map[string]map[string]string{
+			"default/nomad/jobs/example": {"password": "passw0rd1"},
+		},
+	}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is there
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+}
+
 func TestTaskTemplateManager_Unblock_Static_AlreadyRendered(t *testing.T) {
 	t.Parallel()
 	// Make a template that will render immediately
@@ -10,6 +10,7 @@ import (
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/template"
 	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -32,6 +33,13 @@ type templateHookConfig struct {
 
 	// envBuilder is the environment variable builder for the task.
 	envBuilder *taskenv.Builder
+
+	// nomadNamespace is the alloc's Nomad namespace, used when reading
+	// variables
+	nomadNamespace string
+
+	// variables is the client used to read variables for the templates
+	variables cinterfaces.VariablesAPI
 }
 
 type templateHook struct {
@@ -111,6 +119,8 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.config.envBuilder,
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
+		NomadNamespace:       h.config.nomadNamespace,
+		Variables:            h.config.variables,
 	})
 	if err != nil {
 		h.logger.Error("failed to create template manager", "error", err)
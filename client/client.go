@@ -351,6 +351,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		EnterpriseClient:     newEnterpriseClient(logger),
 	}
 
+	// Route nomad provider services to the servers rather than Consul
+	c.consulService = newServiceRegistrationRouter(c, consulService, logger)
+
 	c.batchNodeUpdates = newBatchNodeUpdates(
 		c.updateNodeFromDriver,
 		c.updateNodeFromDevices,
@@ -1100,6 +1103,8 @@ func (c *Client) restoreState() error {
 			DeviceStatsReporter: c,
 			Consul:              c.consulService,
 			ConsulSI:            c.tokensClient,
+			WorkloadIdentity:    c,
+			Variables:           c,
 			Vault:               c.vaultClient,
 			PrevAllocWatcher:    prevAllocWatcher,
 			PrevAllocMigrator:   prevAllocMigrator,
@@ -1258,6 +1263,11 @@ func (c *Client) NumAllocs() int {
 // The node ID is, if available, a persistent unique ID.  The secret ID is a
 // high-entropy random UUID.
 func (c *Client) nodeID() (id, secret string, err error) {
+	// Use the configured static node ID if there is one
+	if c.config.NodeID != "" {
+		return c.config.NodeID, uuid.Generate(), nil
+	}
+
 	var hostID string
 	hostInfo, err := host.Info()
 	if !c.config.NoHostUUID && err == nil {
@@ -1354,6 +1364,8 @@ func (c *Client) setupNode() error {
 	if node.NodeResources == nil {
 		node.NodeResources = &structs.NodeResources{}
 	}
+	node.NodeResources.MinDynamicPort = c.config.MinDynamicPort
+	node.NodeResources.MaxDynamicPort = c.config.MaxDynamicPort
 	if node.ReservedResources == nil {
 		node.ReservedResources = &structs.NodeReservedResources{}
 	}
@@ -2137,6 +2149,55 @@ OUTER:
 	}
 }
 
+// HostVolumes returns a copy of the host volumes currently registered on
+// the node.
+func (c *Client) HostVolumes() map[string]*structs.ClientHostVolumeConfig {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return structs.CopyMapStringClientHostVolumeConfig(c.configCopy.Node.HostVolumes)
+}
+
+// RegisterHostVolumes adds or updates host volumes on the node at runtime
+// and pushes the updated node to the servers, so scheduler feasibility
+// checking picks the volumes up without a client agent restart.
+func (c *Client) RegisterHostVolumes(volumes []*structs.ClientHostVolumeConfig) error {
+	for _, v := range volumes {
+		if v.Name == "" || v.Path == "" {
+			return fmt.Errorf("host volume must have a name and a path")
+		}
+	}
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	node := c.config.Node
+	if node.HostVolumes == nil {
+		node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig, len(volumes))
+	}
+	for _, v := range volumes {
+		node.HostVolumes[v.Name] = v.Copy()
+	}
+
+	c.updateNodeLocked()
+	return nil
+}
+
+// UnregisterHostVolume removes a host volume from the node at runtime and
+// pushes the updated node to the servers.
+func (c *Client) UnregisterHostVolume(name string) error {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	node := c.config.Node
+	if _, ok := node.HostVolumes[name]; !ok {
+		return fmt.Errorf("host volume %q not found", name)
+	}
+	delete(node.HostVolumes, name)
+
+	c.updateNodeLocked()
+	return nil
+}
+
 // updateNode updates the Node copy and triggers the client to send the updated
 // Node to the server. This should be done while the caller holds the
 // configLock lock.
@@ -2383,6 +2444,8 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		StateDB:             c.stateDB,
 		Consul:              c.consulService,
 		ConsulSI:            c.tokensClient,
+		WorkloadIdentity:    c,
+		Variables:           c,
 		Vault:               c.vaultClient,
 		StateUpdater:        c,
 		DeviceStatsReporter: c,
@@ -2568,6 +2631,65 @@ func (c *Client) deriveSIToken(alloc *structs.Allocation, taskNames []string) (m
 	return m, nil
 }
 
+// DeriveWorkloadIdentities takes an allocation and a set of tasks and derives
+// signed workload identity tokens for each of the tasks by requesting them
+// from the Nomad Server.
+func (c *Client) DeriveWorkloadIdentities(alloc *structs.Allocation, taskNames []string) (map[string]string, error) {
+	tasks, err := verifiedTasks(c.logger, alloc, taskNames)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &structs.DeriveWorkloadIdentityRequest{
+		NodeID:       c.NodeID(),
+		SecretID:     c.secretNodeID(),
+		AllocID:      alloc.ID,
+		Tasks:        tasks,
+		QueryOptions: structs.QueryOptions{Region: c.Region()},
+	}
+
+	var resp structs.DeriveWorkloadIdentityResponse
+	if err := c.RPC("Node.DeriveWorkloadIdentity", &req, &resp); err != nil {
+		c.logger.Error("error making derive workload identity RPC", "error", err)
+		return nil, fmt.Errorf("DeriveWorkloadIdentity RPC failed: %v", err)
+	}
+	if err := resp.Error; err != nil {
+		c.logger.Error("error deriving workload identities", "error", err)
+		return nil, structs.NewWrappedServerError(err)
+	}
+	if len(resp.Tokens) == 0 {
+		c.logger.Error("error deriving workload identities", "error", "invalid_response")
+		return nil, fmt.Errorf("failed to derive workload identities: invalid response")
+	}
+
+	return helper.CopyMapStringString(resp.Tokens), nil
+}
+
+// ReadVariable returns the decrypted items of the variable at a path within a
+// namespace. It is used to render task templates and authenticates with the
+// node secret, so no ACL token is required on the client.
+func (c *Client) ReadVariable(namespace, path string) (map[string]string, error) {
+	req := &structs.VariablesReadRequest{
+		Path: path,
+		QueryOptions: structs.QueryOptions{
+			Region:    c.Region(),
+			Namespace: namespace,
+			AuthToken: c.secretNodeID(),
+		},
+	}
+
+	var resp structs.VariablesReadResponse
+	if err := c.RPC("Variables.Read", &req, &resp); err != nil {
+		c.logger.Error("error making variable read RPC", "error", err)
+		return nil, fmt.Errorf("Variables.Read RPC failed: %v", err)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("variable %q not found in namespace %q", path, namespace)
+	}
+
+	return helper.CopyMapStringString(resp.Data.Items), nil
+}
+
 // verifiedTasks asserts each task in taskNames actually exists in the given alloc,
 // otherwise an error is returned.
 func verifiedTasks(logger hclog.Logger, alloc *structs.Allocation, taskNames []string) ([]string, error) {
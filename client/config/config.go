@@ -1,8 +1,10 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -68,6 +70,15 @@ type RPCHandler interface {
 }
 
 // Config is used to parameterize and configure the behavior of the client
+// APIListenerRegistrar is the interface used by the client to serve the
+// agent's HTTP API on additional listeners such as the per-allocation task API
+// unix sockets.
+type APIListenerRegistrar interface {
+	// Serve the HTTP API on the provided listener until it is closed or the
+	// context is canceled, whichever occurs first.
+	Serve(ctx context.Context, ln net.Listener) error
+}
+
 type Config struct {
 	// DevMode controls if we are in a development mode which
 	// avoids persistent storage.
@@ -130,6 +141,14 @@ type Config struct {
 	// communicating with plugin subsystems over loopback
 	ClientMinPort uint
 
+	// MinDynamicPort is the smallest dynamic port generated for allocations
+	// on this node. A zero value uses the scheduler default.
+	MinDynamicPort int
+
+	// MaxDynamicPort is the largest dynamic port generated for allocations
+	// on this node. A zero value uses the scheduler default.
+	MaxDynamicPort int
+
 	// A mapping of directories on the host OS to attempt to embed inside each
 	// task's chroot.
 	ChrootEnv map[string]string
@@ -191,6 +210,11 @@ type Config struct {
 	// random UUID.
 	NoHostUUID bool
 
+	// NodeID is a static node ID to use in place of the generated one. It is
+	// mainly useful for dev mode agents backing integration tests and demos
+	// that need a deterministic node identity.
+	NodeID string
+
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
@@ -231,6 +255,12 @@ type Config struct {
 	// StateDBFactory is used to override stateDB implementations,
 	StateDBFactory state.NewStateDBFunc
 
+	// APIListenerRegistrar is used to serve the agent's HTTP API on unix
+	// sockets created inside allocations so tasks can reach the API without
+	// network access to the agent. It may be nil if the agent is not serving
+	// the HTTP API.
+	APIListenerRegistrar APIListenerRegistrar
+
 	// CNIPath is the path used to search for CNI plugins. Multiple paths can
 	// be specified with colon delimited
 	CNIPath string
@@ -259,6 +289,10 @@ type Config struct {
 	// HostNetworks is a map of the conigured host networks by name.
 	HostNetworks map[string]*structs.ClientHostNetworkConfig
 
+	// EnvProviders is the list of configured task environment providers in
+	// the order they should run.
+	EnvProviders []*structs.ClientEnvProviderConfig
+
 	// BindWildcardDefaultHostNetwork toggles if the default host network should accept all
 	// destinations (true) or only filter on the IP of the default host network (false) when
 	// port mapping. This allows Nomad clients with no defined host networks to accept and
@@ -273,6 +307,10 @@ type Config struct {
 type ClientTemplateConfig struct {
 	FunctionBlacklist []string
 	DisableSandbox    bool
+
+	// SecretsProviders configures the secret backends available to the
+	// {{ secret ... }} template function, keyed by provider name
+	SecretsProviders map[string]map[string]string
 }
 
 func (c *ClientTemplateConfig) Copy() *ClientTemplateConfig {
@@ -283,6 +321,12 @@ func (c *ClientTemplateConfig) Copy() *ClientTemplateConfig {
 	nc := new(ClientTemplateConfig)
 	*nc = *c
 	nc.FunctionBlacklist = helper.CopySliceString(nc.FunctionBlacklist)
+	if c.SecretsProviders != nil {
+		nc.SecretsProviders = make(map[string]map[string]string, len(c.SecretsProviders))
+		for name, config := range c.SecretsProviders {
+			nc.SecretsProviders[name] = helper.CopyMapStringString(config)
+		}
+	}
 	return nc
 }
 
@@ -293,6 +337,7 @@ func (c *Config) Copy() *Config {
 	nc.Servers = helper.CopySliceString(nc.Servers)
 	nc.Options = helper.CopyMapStringString(nc.Options)
 	nc.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(nc.HostVolumes)
+	nc.EnvProviders = structs.CopySliceClientEnvProviderConfig(nc.EnvProviders)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
 	nc.TemplateConfig = c.TemplateConfig.Copy()
@@ -0,0 +1,95 @@
+// Package envproviders defines the interface for client plugins that inject
+// additional environment variables and files into tasks at start, such as
+// fetching instance credentials or adding site-specific variables. Providers
+// are enabled per client and may be restricted to a set of namespaces.
+package envproviders
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+// BuildRequest describes the task an env provider is building the environment
+// for.
+type BuildRequest struct {
+	// AllocID is the ID of the allocation the task belongs to.
+	AllocID string
+
+	// JobID is the ID of the job the task belongs to.
+	JobID string
+
+	// Namespace is the namespace of the job.
+	Namespace string
+
+	// TaskName is the name of the task.
+	TaskName string
+
+	// TaskDir is the task's directory tree on the host.
+	TaskDir string
+
+	// Config is the provider-specific configuration from the client config.
+	Config map[string]string
+}
+
+// BuildResponse holds the environment variables and files a provider injects
+// into a task.
+type BuildResponse struct {
+	// Env is the environment variables to set for the task.
+	Env map[string]string
+
+	// Files maps paths relative to the task directory to the contents to
+	// write there before the task starts.
+	Files map[string]string
+}
+
+// Provider is the interface implemented by task environment providers.
+type Provider interface {
+	// Name returns the name the provider is registered under.
+	Name() string
+
+	// BuildEnv returns the environment variables and files to inject into
+	// the task. It is called before the task starts, including after every
+	// restart.
+	BuildEnv(req *BuildRequest) (*BuildResponse, error)
+}
+
+// Factory instantiates a provider.
+type Factory func(logger log.Logger) Provider
+
+var (
+	// providers holds the registered provider factories by name.
+	providers = map[string]Factory{
+		"static": NewStaticProvider,
+	}
+
+	providersLock sync.RWMutex
+)
+
+// Register makes a provider available under the passed name. It returns an
+// error if the name is already taken.
+func Register(name string, factory Factory) error {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+
+	if _, ok := providers[name]; ok {
+		return fmt.Errorf("env provider %q already registered", name)
+	}
+
+	providers[name] = factory
+	return nil
+}
+
+// New instantiates the provider registered under the passed name.
+func New(name string, logger log.Logger) (Provider, error) {
+	providersLock.RLock()
+	factory, ok := providers[name]
+	providersLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown env provider %q", name)
+	}
+
+	return factory(logger), nil
+}
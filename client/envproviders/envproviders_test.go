@@ -0,0 +1,48 @@
+package envproviders
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Register("test-register", NewStaticProvider))
+
+	// Registering the same name again fails.
+	err := Register("test-register", NewStaticProvider)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already registered")
+}
+
+func TestNew_Unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("does-not-exist", testlog.HCLogger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown env provider")
+}
+
+func TestStaticProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New("static", testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, "static", provider.Name())
+
+	resp, err := provider.BuildEnv(&BuildRequest{
+		Config: map[string]string{
+			"SITE":   "east",
+			"TENANT": "ops",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"SITE":   "east",
+		"TENANT": "ops",
+	}, resp.Env)
+	require.Empty(t, resp.Files)
+}
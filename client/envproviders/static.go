@@ -0,0 +1,33 @@
+package envproviders
+
+import (
+	log "github.com/hashicorp/go-hclog"
+)
+
+// StaticProvider injects the provider configuration as environment variables,
+// allowing operators to add site-specific variables to tasks without changing
+// job files.
+type StaticProvider struct {
+	logger log.Logger
+}
+
+// NewStaticProvider returns a provider that injects its configuration as
+// environment variables.
+func NewStaticProvider(logger log.Logger) Provider {
+	return &StaticProvider{
+		logger: logger.Named("static"),
+	}
+}
+
+func (s *StaticProvider) Name() string {
+	return "static"
+}
+
+func (s *StaticProvider) BuildEnv(req *BuildRequest) (*BuildResponse, error) {
+	env := make(map[string]string, len(req.Config))
+	for k, v := range req.Config {
+		env[k] = v
+	}
+
+	return &BuildResponse{Env: env}, nil
+}
@@ -16,6 +16,23 @@ type AllocStateHandler interface {
 	AllocStateUpdated(alloc *structs.Allocation)
 }
 
+// WorkloadIdentityAPI is the interface the Nomad Client uses to request
+// signed workload identity tokens from the Nomad Server on behalf of an
+// allocation's tasks.
+type WorkloadIdentityAPI interface {
+	// DeriveWorkloadIdentities contacts the nomad server and requests signed
+	// identity tokens be generated for tasks in the allocation.
+	DeriveWorkloadIdentities(alloc *structs.Allocation, tasks []string) (map[string]string, error)
+}
+
+// VariablesAPI is the interface the Nomad Client uses to read variables from
+// the Nomad Server on behalf of an allocation's task templates.
+type VariablesAPI interface {
+	// ReadVariable returns the decrypted items of the variable at a path
+	// within a namespace.
+	ReadVariable(namespace, path string) (map[string]string, error)
+}
+
 // DeviceStatsReporter gives access to the latest resource usage
 // for devices
 type DeviceStatsReporter interface {
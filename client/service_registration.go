@@ -0,0 +1,236 @@
+package client
+
+import (
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+
+	consulApi "github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// serviceRegistrationRouter routes workload services to the discovery
+// provider named in their service stanzas. Services using the default
+// "consul" provider are passed through to the wrapped Consul client, while
+// services using the "nomad" provider are registered with the servers via
+// the ServiceRegistration RPCs so that clusters without Consul still get
+// basic discovery.
+type serviceRegistrationRouter struct {
+	c      *Client
+	consul consulApi.ConsulServiceAPI
+	logger log.Logger
+}
+
+// newServiceRegistrationRouter wraps the Consul service client used by the
+// allocation runners so nomad provider services are synced to the servers.
+func newServiceRegistrationRouter(c *Client, consulService consulApi.ConsulServiceAPI, logger log.Logger) consulApi.ConsulServiceAPI {
+	return &serviceRegistrationRouter{
+		c:      c,
+		consul: consulService,
+		logger: logger.Named("service_registration"),
+	}
+}
+
+// RegisterWorkload splits the workload services by provider, upserting the
+// nomad provider services with the servers and delegating the rest to Consul.
+func (r *serviceRegistrationRouter) RegisterWorkload(ws *consul.WorkloadServices) error {
+	consulServices, nomadServices := r.splitWorkload(ws)
+
+	if len(nomadServices) != 0 {
+		regs, err := r.registrations(ws, nomadServices)
+		if err != nil {
+			return err
+		}
+
+		req := structs.ServiceRegistrationUpsertRequest{
+			Services: regs,
+			WriteRequest: structs.WriteRequest{
+				Region:    r.c.Region(),
+				AuthToken: r.c.secretNodeID(),
+			},
+		}
+		var resp structs.ServiceRegistrationUpsertResponse
+		if err := r.c.RPC("ServiceRegistration.Upsert", &req, &resp); err != nil {
+			return err
+		}
+	}
+
+	if consulServices == nil {
+		return nil
+	}
+	return r.consul.RegisterWorkload(consulServices)
+}
+
+// RemoveWorkload removes the workload's registrations from their respective
+// providers.
+func (r *serviceRegistrationRouter) RemoveWorkload(ws *consul.WorkloadServices) {
+	consulServices, nomadServices := r.splitWorkload(ws)
+
+	for _, service := range nomadServices {
+		req := structs.ServiceRegistrationDeleteByIDRequest{
+			ID: nomadServiceID(ws, service),
+			WriteRequest: structs.WriteRequest{
+				Region:    r.c.Region(),
+				AuthToken: r.c.secretNodeID(),
+			},
+		}
+		var resp structs.ServiceRegistrationDeleteByIDResponse
+		if err := r.c.RPC("ServiceRegistration.DeleteByID", &req, &resp); err != nil {
+			r.logger.Error("failed to deregister service",
+				"service", service.Name, "alloc_id", ws.AllocID, "error", err)
+		}
+	}
+
+	if consulServices != nil {
+		r.consul.RemoveWorkload(consulServices)
+	}
+}
+
+// UpdateWorkload reconciles the registrations of the old workload against the
+// new one in each provider.
+func (r *serviceRegistrationRouter) UpdateWorkload(old, newWorkload *consul.WorkloadServices) error {
+	oldConsul, oldNomad := r.splitWorkload(old)
+	newConsul, newNomad := r.splitWorkload(newWorkload)
+
+	// Remove nomad provider registrations that are no longer present
+	for _, service := range oldNomad {
+		id := nomadServiceID(old, service)
+
+		found := false
+		for _, updated := range newNomad {
+			if nomadServiceID(newWorkload, updated) == id {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		req := structs.ServiceRegistrationDeleteByIDRequest{
+			ID: id,
+			WriteRequest: structs.WriteRequest{
+				Region:    r.c.Region(),
+				AuthToken: r.c.secretNodeID(),
+			},
+		}
+		var resp structs.ServiceRegistrationDeleteByIDResponse
+		if err := r.c.RPC("ServiceRegistration.DeleteByID", &req, &resp); err != nil {
+			r.logger.Error("failed to deregister service",
+				"service", service.Name, "alloc_id", old.AllocID, "error", err)
+		}
+	}
+
+	// Upserting the new registrations updates any that already exist
+	if len(newNomad) != 0 {
+		regs, err := r.registrations(newWorkload, newNomad)
+		if err != nil {
+			return err
+		}
+
+		req := structs.ServiceRegistrationUpsertRequest{
+			Services: regs,
+			WriteRequest: structs.WriteRequest{
+				Region:    r.c.Region(),
+				AuthToken: r.c.secretNodeID(),
+			},
+		}
+		var resp structs.ServiceRegistrationUpsertResponse
+		if err := r.c.RPC("ServiceRegistration.Upsert", &req, &resp); err != nil {
+			return err
+		}
+	}
+
+	if oldConsul == nil && newConsul == nil {
+		return nil
+	}
+	if oldConsul == nil {
+		oldConsul = old.Copy()
+		oldConsul.Services = nil
+	}
+	if newConsul == nil {
+		newConsul = newWorkload.Copy()
+		newConsul.Services = nil
+	}
+	return r.consul.UpdateWorkload(oldConsul, newConsul)
+}
+
+// AllocRegistrations returns the Consul registrations for the allocation.
+func (r *serviceRegistrationRouter) AllocRegistrations(allocID string) (*consul.AllocRegistration, error) {
+	return r.consul.AllocRegistrations(allocID)
+}
+
+// UpdateTTL updates the TTL of a Consul check. Nomad provider services do not
+// support checks, so these always belong to Consul.
+func (r *serviceRegistrationRouter) UpdateTTL(id, output, status string) error {
+	return r.consul.UpdateTTL(id, output, status)
+}
+
+// splitWorkload partitions the workload's services by provider. The first
+// return value is a workload containing only the Consul provider services,
+// or nil if there are none; the second is the set of nomad provider services.
+func (r *serviceRegistrationRouter) splitWorkload(ws *consul.WorkloadServices) (*consul.WorkloadServices, []*structs.Service) {
+	if ws == nil {
+		return nil, nil
+	}
+
+	var nomadServices []*structs.Service
+	var consulServices []*structs.Service
+	for _, service := range ws.Services {
+		if service.Provider == structs.ServiceProviderNomad {
+			nomadServices = append(nomadServices, service)
+		} else {
+			consulServices = append(consulServices, service)
+		}
+	}
+
+	if len(nomadServices) == 0 {
+		return ws, nil
+	}
+	if len(consulServices) == 0 {
+		return nil, nomadServices
+	}
+
+	consulWorkload := ws.Copy()
+	consulWorkload.Services = consulServices
+	return consulWorkload, nomadServices
+}
+
+// registrations builds the service registrations to upsert for the nomad
+// provider services of the workload.
+func (r *serviceRegistrationRouter) registrations(ws *consul.WorkloadServices, services []*structs.Service) ([]*structs.ServiceRegistration, error) {
+	alloc, err := r.c.GetAlloc(ws.AllocID)
+	if err != nil {
+		return nil, err
+	}
+
+	regs := make([]*structs.ServiceRegistration, 0, len(services))
+	for _, service := range services {
+		ip, port := ws.Networks.Port(service.PortLabel)
+		if ip == "" && len(ws.Networks) > 0 {
+			ip = ws.Networks[0].IP
+		}
+
+		regs = append(regs, &structs.ServiceRegistration{
+			ID:          nomadServiceID(ws, service),
+			ServiceName: service.Name,
+			Namespace:   alloc.Namespace,
+			NodeID:      r.c.NodeID(),
+			Datacenter:  r.c.Datacenter(),
+			JobID:       alloc.JobID,
+			AllocID:     ws.AllocID,
+			Tags:        service.Tags,
+			Address:     ip,
+			Port:        port,
+		})
+	}
+	return regs, nil
+}
+
+// nomadServiceID returns the unique ID of a nomad provider service
+// registration. The ID is deterministic so a registration can be removed
+// without consulting the servers.
+func nomadServiceID(ws *consul.WorkloadServices, service *structs.Service) string {
+	return fmt.Sprintf("_nomad-%s-%s-%s-%s", ws.AllocID, ws.Name(), service.Name, service.PortLabel)
+}
@@ -20,6 +20,9 @@ type MemDB struct {
 	// alloc_id -> value
 	deployStatus map[string]*structs.AllocDeploymentStatus
 
+	// alloc_id -> value
+	networkStatus map[string]*structs.AllocNetworkStatus
+
 	// alloc_id -> task_name -> value
 	localTaskState map[string]map[string]*state.LocalState
 	taskState      map[string]map[string]*structs.TaskState
@@ -43,6 +46,7 @@ func NewMemDB(logger hclog.Logger) *MemDB {
 	return &MemDB{
 		allocs:         make(map[string]*structs.Allocation),
 		deployStatus:   make(map[string]*structs.AllocDeploymentStatus),
+		networkStatus:  make(map[string]*structs.AllocNetworkStatus),
 		localTaskState: make(map[string]map[string]*state.LocalState),
 		taskState:      make(map[string]map[string]*structs.TaskState),
 		logger:         logger,
@@ -89,6 +93,19 @@ func (m *MemDB) PutDeploymentStatus(allocID string, ds *structs.AllocDeploymentS
 	return nil
 }
 
+func (m *MemDB) GetNetworkStatus(allocID string) (*structs.AllocNetworkStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.networkStatus[allocID], nil
+}
+
+func (m *MemDB) PutNetworkStatus(allocID string, ns *structs.AllocNetworkStatus) error {
+	m.mu.Lock()
+	m.networkStatus[allocID] = ns
+	defer m.mu.Unlock()
+	return nil
+}
+
 func (m *MemDB) GetTaskRunnerState(allocID string, taskName string) (*state.LocalState, *structs.TaskState, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ACLAuthMethodCommand struct {
+	Meta
+}
+
+func (f *ACLAuthMethodCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL auth methods.
+  Auth methods allow users to log in via an external identity provider,
+  such as an OIDC provider or a trusted JWT issuer, and be minted an ACL
+  token.
+
+  Create an ACL auth method:
+
+      $ nomad acl auth-method create -type=oidc -max-token-ttl=1h -config=config.json <name>
+
+  List ACL auth methods:
+
+      $ nomad acl auth-method list
+
+  Inspect an ACL auth method:
+
+      $ nomad acl auth-method info <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLAuthMethodCommand) Synopsis() string {
+	return "Interact with ACL auth methods"
+}
+
+func (f *ACLAuthMethodCommand) Name() string { return "acl auth-method" }
+
+func (f *ACLAuthMethodCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodCreateCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method create [options] <name>
+
+  Create is used to create or update an ACL auth method. Auth methods allow
+  users to log in via an external identity provider and be minted an ACL
+  token with the policies granted by the method's binding rules.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -type
+    Specifies the type of the auth method. Supported types are "oidc" and
+    "jwt".
+
+  -token-locality
+    Specifies whether minted tokens are "local" to the region or "global".
+    Defaults to "local".
+
+  -max-token-ttl
+    Specifies the duration minted tokens are valid for, such as "1h".
+
+  -default
+    Specifies that this auth method should be used when no method is named
+    during login.
+
+  -config
+    Specifies the path to a JSON file containing the type specific
+    configuration for the auth method.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-type":           complete.PredictSet("oidc", "jwt"),
+			"-token-locality": complete.PredictSet("local", "global"),
+			"-max-token-ttl":  complete.PredictAnything,
+			"-default":        complete.PredictNothing,
+			"-config":         complete.PredictFiles("*.json"),
+		})
+}
+
+func (c *ACLAuthMethodCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodCreateCommand) Synopsis() string {
+	return "Create or update an ACL auth method"
+}
+
+func (c *ACLAuthMethodCreateCommand) Name() string { return "acl auth-method create" }
+
+func (c *ACLAuthMethodCreateCommand) Run(args []string) int {
+	var methodType, locality, ttl, configPath string
+	var isDefault bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&methodType, "type", "", "")
+	flags.StringVar(&locality, "token-locality", "local", "")
+	flags.StringVar(&ttl, "max-token-ttl", "", "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	flags.StringVar(&configPath, "config", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the method name
+	methodName := args[0]
+
+	// Parse the TTL
+	maxTokenTTL, err := time.ParseDuration(ttl)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing max token TTL: %s", err))
+		return 1
+	}
+
+	// Read the type specific configuration
+	if configPath == "" {
+		c.Ui.Error("Must specify a configuration file with -config")
+		return 1
+	}
+	raw, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading config file: %s", err))
+		return 1
+	}
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing config file: %s", err))
+		return 1
+	}
+
+	// Construct the auth method
+	method := &api.ACLAuthMethod{
+		Name:          methodName,
+		Type:          methodType,
+		TokenLocality: locality,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       isDefault,
+		Config:        &config,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Upsert the auth method
+	_, err = client.ACLAuthMethods().Upsert(method, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote %q ACL auth method!",
+		methodName))
+	return 0
+}
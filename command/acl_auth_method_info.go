@@ -0,0 +1,91 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodInfoCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodInfoCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method info <name>
+
+  Info is used to fetch information on an existing ACL auth method.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *ACLAuthMethodInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodInfoCommand) Synopsis() string {
+	return "Fetch info on an existing ACL auth method"
+}
+
+func (c *ACLAuthMethodInfoCommand) Name() string { return "acl auth-method info" }
+
+func (c *ACLAuthMethodInfoCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the method name
+	methodName := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Fetch info on the auth method
+	method, _, err := client.ACLAuthMethods().Info(methodName, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching info on ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVAuthMethod(method))
+	return 0
+}
+
+// formatKVAuthMethod returns a K/V formatted ACL auth method
+func formatKVAuthMethod(method *api.ACLAuthMethod) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", method.Name),
+		fmt.Sprintf("Type|%s", method.Type),
+		fmt.Sprintf("Token Locality|%s", method.TokenLocality),
+		fmt.Sprintf("Max Token TTL|%s", method.MaxTokenTTL),
+		fmt.Sprintf("Default|%v", method.Default),
+		fmt.Sprintf("CreateIndex|%v", method.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", method.ModifyIndex),
+	}
+	return formatKV(output)
+}
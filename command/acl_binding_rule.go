@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ACLBindingRuleCommand struct {
+	Meta
+}
+
+func (f *ACLBindingRuleCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL binding rules.
+  Binding rules map the identity attributes returned by an auth method onto
+  Nomad ACL policies and roles.
+
+  Create an ACL binding rule:
+
+      $ nomad acl binding-rule create -auth-method=okta -bind-type=policy -bind-name=readonly
+
+  List ACL binding rules:
+
+      $ nomad acl binding-rule list
+
+  Inspect an ACL binding rule:
+
+      $ nomad acl binding-rule info <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLBindingRuleCommand) Synopsis() string {
+	return "Interact with ACL binding rules"
+}
+
+func (f *ACLBindingRuleCommand) Name() string { return "acl binding-rule" }
+
+func (f *ACLBindingRuleCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLBindingRuleCreateCommand struct {
+	Meta
+}
+
+func (c *ACLBindingRuleCreateCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule create [options]
+
+  Create is used to create an ACL binding rule. Binding rules map the
+  identity attributes returned by an auth method onto ACL policies and
+  roles.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Create Options:
+
+  -description
+    A free form text description of the binding rule.
+
+  -auth-method
+    Specifies the name of the ACL auth method this binding rule is
+    associated with.
+
+  -selector
+    Specifies an expression that matches against the identity attributes
+    returned by the auth method, such as 'value.email ends_with "@example.com"'.
+    If empty, the rule matches all logins via the auth method.
+
+  -bind-type
+    Specifies the type of binding the rule performs. Supported types are
+    "role", "policy" and "management".
+
+  -bind-name
+    Specifies the name of the policy or role to bind when the rule matches.
+    Must be absent when the bind type is "management".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description": complete.PredictAnything,
+			"-auth-method": complete.PredictAnything,
+			"-selector":    complete.PredictAnything,
+			"-bind-type":   complete.PredictSet("role", "policy", "management"),
+			"-bind-name":   complete.PredictAnything,
+		})
+}
+
+func (c *ACLBindingRuleCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLBindingRuleCreateCommand) Synopsis() string {
+	return "Create a new ACL binding rule"
+}
+
+func (c *ACLBindingRuleCreateCommand) Name() string { return "acl binding-rule create" }
+
+func (c *ACLBindingRuleCreateCommand) Run(args []string) int {
+	var description, authMethod, selector, bindType, bindName string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&description, "description", "", "")
+	flags.StringVar(&authMethod, "auth-method", "", "")
+	flags.StringVar(&selector, "selector", "", "")
+	flags.StringVar(&bindType, "bind-type", "", "")
+	flags.StringVar(&bindName, "bind-name", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Construct the binding rule
+	rule := &api.ACLBindingRule{
+		Description: description,
+		AuthMethod:  authMethod,
+		Selector:    selector,
+		BindType:    bindType,
+		BindName:    bindName,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the binding rule
+	_, err = client.ACLBindingRules().Create(rule, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating ACL binding rule: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully created ACL binding rule!")
+	return 0
+}
@@ -98,11 +98,14 @@ func formatKVACLToken(token *api.ACLToken) string {
 		fmt.Sprintf("Global|%v", token.Global),
 	}
 
-	// Special case the policy output
+	// Special case the policy and role output
 	if token.Type == "management" {
-		output = append(output, "Policies|n/a")
+		output = append(output, "Policies|n/a", "Roles|n/a")
 	} else {
-		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
+		output = append(output,
+			fmt.Sprintf("Policies|%v", token.Policies),
+			fmt.Sprintf("Roles|%v", token.Roles),
+		)
 	}
 
 	// Add the generic output
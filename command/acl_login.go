@@ -0,0 +1,207 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+// loginCallbackAddr is the address the temporary OIDC callback server binds
+// to while waiting for the provider redirect.
+const loginCallbackAddr = "127.0.0.1:4649"
+
+type ACLLoginCommand struct {
+	Meta
+}
+
+func (c *ACLLoginCommand) Help() string {
+	helpText := `
+Usage: nomad acl login [options]
+
+  Login is used to exchange an external identity for a Nomad ACL token. The
+  identity is verified by an ACL auth method, and the minted token is granted
+  the policies and roles mapped by the method's binding rules.
+
+  When the auth method is of type "oidc", a browser login flow is started and
+  a local server briefly listens on ` + loginCallbackAddr + ` for the
+  provider callback. When the auth method is of type "jwt", the bearer token
+  must be supplied with -login-token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Login Options:
+
+  -method
+    Specifies the name of the ACL auth method to log in with. If unset, the
+    default auth method is used.
+
+  -login-token
+    Specifies the bearer token to present when logging in via a "jwt" auth
+    method.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLLoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":      complete.PredictAnything,
+			"-login-token": complete.PredictAnything,
+		})
+}
+
+func (c *ACLLoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLLoginCommand) Synopsis() string {
+	return "Login to Nomad via an ACL auth method"
+}
+
+func (c *ACLLoginCommand) Name() string { return "acl login" }
+
+func (c *ACLLoginCommand) Run(args []string) int {
+	var method, loginToken string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&method, "method", "", "")
+	flags.StringVar(&loginToken, "login-token", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var token *api.ACLToken
+	if loginToken != "" {
+		// A bearer token was supplied directly, so log in without the
+		// browser flow.
+		token, _, err = client.ACLAuth().Login(&api.ACLLoginRequest{
+			AuthMethodName: method,
+			LoginToken:     loginToken,
+		}, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+			return 1
+		}
+	} else {
+		token, err = c.loginOIDC(client, method)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+			return 1
+		}
+	}
+
+	c.Ui.Output(formatKVACLToken(token))
+	return 0
+}
+
+// loginOIDC drives the OIDC authorization code flow. It asks the servers for
+// the provider auth URL, directs the user to it, and waits for the provider
+// to redirect back to a temporary local listener with the authorization code.
+func (c *ACLLoginCommand) loginOIDC(client *api.Client, method string) (*api.ACLToken, error) {
+	nonce, err := generateLoginNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/oidc/callback", loginCallbackAddr)
+
+	// Bind the callback listener before handing out the redirect URI so the
+	// provider has something to redirect to.
+	ln, err := net.Listen("tcp", loginCallbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback server: %v", err)
+	}
+	defer ln.Close()
+
+	authURL, _, err := client.ACLAuth().OIDCAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Ui.Output("Complete the login by visiting the following URL in your browser:")
+	c.Ui.Output("")
+	c.Ui.Output("    " + authURL)
+	c.Ui.Output("")
+
+	// Wait for the provider callback
+	type callbackResult struct {
+		code  string
+		state string
+		err   error
+	}
+	resultCh := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc/callback", func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		res := callbackResult{
+			code:  q.Get("code"),
+			state: q.Get("state"),
+		}
+		if res.code == "" {
+			res.err = fmt.Errorf("provider callback did not include an authorization code")
+			http.Error(w, "Login failed, no authorization code provided.", http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login successful. You may now close this window and return to your terminal.")
+		}
+		resultCh <- res
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Shutdown(context.Background())
+
+	res := <-resultCh
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	token, _, err := client.ACLAuth().OIDCCompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+		Code:           res.code,
+		State:          res.state,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// generateLoginNonce returns a random nonce used to bind the OIDC callback
+// to this login attempt.
+func generateLoginNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLRoleInfoCommand struct {
+	Meta
+}
+
+func (c *ACLRoleInfoCommand) Help() string {
+	helpText := `
+Usage: nomad acl role info <name>
+
+  Info is used to fetch information on an existing ACL role.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLRoleInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *ACLRoleInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLRoleInfoCommand) Synopsis() string {
+	return "Fetch info on an existing ACL role"
+}
+
+func (c *ACLRoleInfoCommand) Name() string { return "acl role info" }
+
+func (c *ACLRoleInfoCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the role name
+	roleName := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Fetch info on the role
+	role, _, err := client.ACLRoles().Info(roleName, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching info on ACL role: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVRole(role))
+	return 0
+}
+
+// formatKVRole returns a K/V formatted ACL role
+func formatKVRole(role *api.ACLRole) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", role.Name),
+		fmt.Sprintf("Description|%s", role.Description),
+		fmt.Sprintf("Policies|%v", strings.Join(role.Policies, ",")),
+		fmt.Sprintf("CreateIndex|%v", role.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", role.ModifyIndex),
+	}
+	return formatKV(output)
+}
@@ -36,6 +36,11 @@ Create Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -role=""
+    Specifies a role to associate with the token. The token is granted all of
+    the policies linked to the role. Can be specified multiple times, but only
+    with client type tokens.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -47,6 +52,7 @@ func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 			"type":   complete.PredictAnything,
 			"global": complete.PredictNothing,
 			"policy": complete.PredictAnything,
+			"role":   complete.PredictAnything,
 		})
 }
 
@@ -63,7 +69,7 @@ func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 func (c *ACLTokenCreateCommand) Run(args []string) int {
 	var name, tokenType string
 	var global bool
-	var policies []string
+	var policies, roles []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
@@ -73,6 +79,10 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var((funcVar)(func(s string) error {
+		roles = append(roles, s)
+		return nil
+	}), "role", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -90,6 +100,7 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		Name:     name,
 		Type:     tokenType,
 		Policies: policies,
+		Roles:    roles,
 		Global:   global,
 	}
 
@@ -110,6 +110,400 @@ func (s *HTTPServer) aclPolicyDelete(resp http.ResponseWriter, req *http.Request
 	return nil, nil
 }
 
+func (s *HTTPServer) ACLRolesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ACLRoleListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ACLRoleListResponse
+	if err := s.agent.RPC("ACL.ListRoles", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Roles == nil {
+		out.Roles = make([]*structs.ACLRoleListStub, 0)
+	}
+	return out.Roles, nil
+}
+
+func (s *HTTPServer) ACLRoleSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/acl/role/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Role Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.aclRoleQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.aclRoleUpdate(resp, req, name)
+	case "DELETE":
+		return s.aclRoleDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) aclRoleQuery(resp http.ResponseWriter, req *http.Request,
+	roleName string) (interface{}, error) {
+	args := structs.ACLRoleSpecificRequest{
+		Name: roleName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleACLRoleResponse
+	if err := s.agent.RPC("ACL.GetRole", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Role == nil {
+		return nil, CodedError(404, "ACL role not found")
+	}
+	return out.Role, nil
+}
+
+func (s *HTTPServer) aclRoleUpdate(resp http.ResponseWriter, req *http.Request,
+	roleName string) (interface{}, error) {
+	// Parse the role
+	var role structs.ACLRole
+	if err := decodeBody(req, &role); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the role name matches
+	if role.Name != roleName {
+		return nil, CodedError(400, "ACL role name does not match request path")
+	}
+
+	// Format the request
+	args := structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{&role},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.UpsertRoles", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) aclRoleDelete(resp http.ResponseWriter, req *http.Request,
+	roleName string) (interface{}, error) {
+
+	args := structs.ACLRoleDeleteRequest{
+		Names: []string{roleName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.DeleteRoles", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) ACLAuthMethodsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ACLAuthMethodListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ACLAuthMethodListResponse
+	if err := s.agent.RPC("ACL.ListAuthMethods", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.AuthMethods == nil {
+		out.AuthMethods = make([]*structs.ACLAuthMethodListStub, 0)
+	}
+	return out.AuthMethods, nil
+}
+
+func (s *HTTPServer) ACLAuthMethodSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/acl/auth-method/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Auth Method Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.aclAuthMethodQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.aclAuthMethodUpdate(resp, req, name)
+	case "DELETE":
+		return s.aclAuthMethodDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) aclAuthMethodQuery(resp http.ResponseWriter, req *http.Request,
+	methodName string) (interface{}, error) {
+	args := structs.ACLAuthMethodSpecificRequest{
+		Name: methodName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleACLAuthMethodResponse
+	if err := s.agent.RPC("ACL.GetAuthMethod", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.AuthMethod == nil {
+		return nil, CodedError(404, "ACL auth method not found")
+	}
+	return out.AuthMethod, nil
+}
+
+func (s *HTTPServer) aclAuthMethodUpdate(resp http.ResponseWriter, req *http.Request,
+	methodName string) (interface{}, error) {
+	// Parse the auth method
+	var method structs.ACLAuthMethod
+	if err := decodeBody(req, &method); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the auth method name matches
+	if method.Name != methodName {
+		return nil, CodedError(400, "ACL auth method name does not match request path")
+	}
+
+	// Format the request
+	args := structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{&method},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.UpsertAuthMethods", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) aclAuthMethodDelete(resp http.ResponseWriter, req *http.Request,
+	methodName string) (interface{}, error) {
+
+	args := structs.ACLAuthMethodDeleteRequest{
+		Names: []string{methodName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.DeleteAuthMethods", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) ACLBindingRulesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.aclBindingRuleList(resp, req)
+	case "PUT", "POST":
+		// Create a new binding rule, the ID is generated server side
+		return s.aclBindingRuleUpdate(resp, req, "")
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) ACLBindingRuleSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	id := strings.TrimPrefix(req.URL.Path, "/v1/acl/binding-rule/")
+	if len(id) == 0 {
+		return nil, CodedError(400, "Missing Binding Rule ID")
+	}
+	switch req.Method {
+	case "GET":
+		return s.aclBindingRuleQuery(resp, req, id)
+	case "PUT", "POST":
+		return s.aclBindingRuleUpdate(resp, req, id)
+	case "DELETE":
+		return s.aclBindingRuleDelete(resp, req, id)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) aclBindingRuleList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.ACLBindingRuleListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ACLBindingRuleListResponse
+	if err := s.agent.RPC("ACL.ListBindingRules", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.BindingRules == nil {
+		out.BindingRules = make([]*structs.ACLBindingRule, 0)
+	}
+	return out.BindingRules, nil
+}
+
+func (s *HTTPServer) aclBindingRuleQuery(resp http.ResponseWriter, req *http.Request,
+	ruleID string) (interface{}, error) {
+	args := structs.ACLBindingRuleSpecificRequest{
+		ID: ruleID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleACLBindingRuleResponse
+	if err := s.agent.RPC("ACL.GetBindingRule", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.BindingRule == nil {
+		return nil, CodedError(404, "ACL binding rule not found")
+	}
+	return out.BindingRule, nil
+}
+
+func (s *HTTPServer) aclBindingRuleUpdate(resp http.ResponseWriter, req *http.Request,
+	ruleID string) (interface{}, error) {
+	// Parse the binding rule
+	var rule structs.ACLBindingRule
+	if err := decodeBody(req, &rule); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the binding rule ID matches
+	if ruleID != "" && rule.ID != ruleID {
+		return nil, CodedError(400, "ACL binding rule ID does not match request path")
+	}
+
+	// Format the request
+	args := structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{&rule},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.UpsertBindingRules", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) aclBindingRuleDelete(resp http.ResponseWriter, req *http.Request,
+	ruleID string) (interface{}, error) {
+
+	args := structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{ruleID},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ACL.DeleteBindingRules", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) ACLLoginRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.ACLLoginRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ACLLoginResponse
+	if err := s.agent.RPC("ACL.Login", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Token, nil
+}
+
+func (s *HTTPServer) ACLOIDCAuthURLRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.ACLOIDCAuthURLRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ACLOIDCAuthURLResponse
+	if err := s.agent.RPC("ACL.OIDCAuthURL", &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *HTTPServer) ACLOIDCCompleteAuthRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.ACLOIDCCompleteAuthRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ACLLoginResponse
+	if err := s.agent.RPC("ACL.OIDCCompleteAuth", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Token, nil
+}
+
+func (s *HTTPServer) ACLCheckRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.ACLCheckRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ACLCheckResponse
+	if err := s.agent.RPC("ACL.Check", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) ACLTokensRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
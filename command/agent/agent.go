@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	golog "log"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -87,6 +88,14 @@ type Agent struct {
 	// configured to run a client.
 	client *client.Client
 
+	// taskAPIServer is the HTTP server whose handler is served on the
+	// per-allocation task API unix sockets. It is set once the agent's HTTP
+	// server has been created and taskAPIReady is closed when it is first
+	// available.
+	taskAPIServer *HTTPServer
+	taskAPILock   sync.Mutex
+	taskAPIReady  chan struct{}
+
 	// server is the launched Nomad Server. Can be nil if the agent isn't
 	// configured to run a server.
 	server *nomad.Server
@@ -108,10 +117,11 @@ type Agent struct {
 // NewAgent is used to create a new agent with the given configuration
 func NewAgent(config *Config, logger log.InterceptLogger, logOutput io.Writer, inmem *metrics.InmemSink) (*Agent, error) {
 	a := &Agent{
-		config:     config,
-		logOutput:  logOutput,
-		shutdownCh: make(chan struct{}),
-		InmemSink:  inmem,
+		config:       config,
+		logOutput:    logOutput,
+		shutdownCh:   make(chan struct{}),
+		taskAPIReady: make(chan struct{}),
+		InmemSink:    inmem,
 	}
 
 	// Create the loggers
@@ -206,6 +216,9 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.NumSchedulers != nil {
 		conf.NumSchedulers = *agentConfig.Server.NumSchedulers
 	}
+	if len(agentConfig.Server.SchedulerWorkerPins) != 0 {
+		conf.SchedulerWorkerPins = agentConfig.Server.SchedulerWorkerPins
+	}
 	if len(agentConfig.Server.EnabledSchedulers) != 0 {
 		// Convert to a set and require the core scheduler
 		set := make(map[string]struct{}, 4)
@@ -228,6 +241,9 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.ACL.ReplicationToken != "" {
 		conf.ReplicationToken = agentConfig.ACL.ReplicationToken
 	}
+	if len(agentConfig.ACL.TLSIdentityPolicies) != 0 {
+		conf.TLSIdentityPolicies = agentConfig.ACL.TLSIdentityPolicies
+	}
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
@@ -341,6 +357,13 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.DeploymentGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.CanaryGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.CanaryGCThreshold = dur
+	}
 	if gcThreshold := agentConfig.Server.CSIVolumeClaimGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -356,6 +379,30 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.CSIPluginGCThreshold = dur
 	}
 
+	if threshold := agentConfig.Server.BlockedEvalEscalationThreshold; threshold != 0 {
+		conf.BlockedEvalEscalationThreshold = threshold
+	}
+	if jobDefaults := agentConfig.Server.JobDefaults; jobDefaults != nil {
+		conf.JobDefaults = jobDefaults.Copy()
+	}
+	if size := agentConfig.Server.EventBufferSize; size != nil {
+		if *size <= 0 {
+			return nil, fmt.Errorf("event_buffer_size must be greater than zero")
+		}
+		conf.EventBufferSize = *size
+	}
+	if path := agentConfig.Server.EventBufferPath; path != "" {
+		conf.EventBufferPath = path
+	}
+	if webhooks := agentConfig.Server.PeriodicLaunchFailureWebhooks; len(webhooks) != 0 {
+		conf.PeriodicLaunchFailureWebhooks = webhooks
+	}
+	if limit := agentConfig.Server.DispatchPayloadSizeLimit; limit != nil {
+		if *limit <= 0 {
+			return nil, fmt.Errorf("dispatch_payload_size_limit must be greater than zero")
+		}
+		conf.DispatchPayloadSizeLimit = *limit
+	}
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
 	}
@@ -483,6 +530,10 @@ func (a *Agent) finalizeClientConfig(c *clientconfig.Config) error {
 	c.PluginLoader = a.pluginLoader
 	c.PluginSingletonLoader = a.pluginSingletonLoader
 
+	// The agent serves the task API on unix sockets created inside
+	// allocations
+	c.APIListenerRegistrar = a
+
 	// Log deprecation messages about Consul related configuration in client
 	// options
 	var invalidConsulKeys []string
@@ -552,9 +603,32 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.ClientMaxPort = uint(agentConfig.Client.ClientMaxPort)
 	conf.ClientMinPort = uint(agentConfig.Client.ClientMinPort)
+
+	// Set the dynamic port range used for allocations.
+	minDynamicPort := agentConfig.Client.MinDynamicPort
+	maxDynamicPort := agentConfig.Client.MaxDynamicPort
+	if minDynamicPort < 0 || minDynamicPort > 65535 {
+		return nil, fmt.Errorf("min_dynamic_port must be a valid port number")
+	}
+	if maxDynamicPort < 0 || maxDynamicPort > 65535 {
+		return nil, fmt.Errorf("max_dynamic_port must be a valid port number")
+	}
+	if minDynamicPort == 0 {
+		minDynamicPort = structs.MinDynamicPort
+	}
+	if maxDynamicPort == 0 {
+		maxDynamicPort = structs.MaxDynamicPort
+	}
+	if minDynamicPort > maxDynamicPort {
+		return nil, fmt.Errorf("min_dynamic_port must not be greater than max_dynamic_port (%d > %d)", minDynamicPort, maxDynamicPort)
+	}
+	conf.MinDynamicPort = minDynamicPort
+	conf.MaxDynamicPort = maxDynamicPort
+
 	conf.DisableRemoteExec = agentConfig.Client.DisableRemoteExec
 	conf.TemplateConfig.FunctionBlacklist = agentConfig.Client.TemplateConfig.FunctionBlacklist
 	conf.TemplateConfig.DisableSandbox = agentConfig.Client.TemplateConfig.DisableSandbox
+	conf.TemplateConfig.SecretsProviders = agentConfig.Client.TemplateConfig.SecretsProviders
 
 	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(agentConfig.Client.HostVolumes))
 	for _, v := range agentConfig.Client.HostVolumes {
@@ -562,6 +636,8 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.HostVolumes = hvMap
 
+	conf.EnvProviders = structs.CopySliceClientEnvProviderConfig(agentConfig.Client.EnvProviders)
+
 	// Setup the node
 	conf.Node = new(structs.Node)
 	conf.Node.Datacenter = agentConfig.Datacenter
@@ -628,6 +704,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 		// Default no_host_uuid to true
 		conf.NoHostUUID = true
 	}
+	conf.NodeID = agentConfig.Client.NodeID
 
 	// Setup the ACLs
 	conf.ACLEnabled = agentConfig.ACL.Enabled
@@ -940,6 +1017,51 @@ func (a *Agent) Leave() error {
 	return nil
 }
 
+// setTaskAPIServer provides the agent with the HTTP server whose handler is
+// used to serve the task API. It is called when the agent's HTTP server is
+// created, including when it is recreated during an agent reload.
+func (a *Agent) setTaskAPIServer(srv *HTTPServer) {
+	a.taskAPILock.Lock()
+	defer a.taskAPILock.Unlock()
+
+	a.taskAPIServer = srv
+	select {
+	case <-a.taskAPIReady:
+	default:
+		close(a.taskAPIReady)
+	}
+}
+
+// Serve serves the agent's HTTP API on the provided listener until it is
+// closed or the context is canceled. It implements the client's
+// APIListenerRegistrar so workloads can reach the API over a unix socket
+// mounted into their secrets directory.
+func (a *Agent) Serve(ctx context.Context, ln net.Listener) error {
+	// Wait for the agent's HTTP server to be available
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-a.taskAPIReady:
+	}
+
+	a.taskAPILock.Lock()
+	srv := &http.Server{Handler: a.taskAPIServer.mux}
+	a.taskAPILock.Unlock()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case <-ctx.Done():
+		srv.Close()
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
+
 // Shutdown is used to terminate the agent.
 func (a *Agent) Shutdown() error {
 	a.shutdownLock.Lock()
@@ -21,7 +21,6 @@ import (
 	"github.com/hashicorp/nomad/command/agent/pprof"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/serf/serf"
-	"github.com/mitchellh/copystructure"
 )
 
 type Member struct {
@@ -88,26 +87,12 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		Member: nomadMember(member),
 		Stats:  s.agent.Stats(),
 	}
-	if ac, err := copystructure.Copy(s.agent.config); err != nil {
-		return nil, CodedError(500, err.Error())
-	} else {
-		self.Config = ac.(*Config)
-	}
 
-	if self.Config != nil && self.Config.Vault != nil && self.Config.Vault.Token != "" {
-		self.Config.Vault.Token = "<redacted>"
-	}
-
-	if self.Config != nil && self.Config.ACL != nil && self.Config.ACL.ReplicationToken != "" {
-		self.Config.ACL.ReplicationToken = "<redacted>"
-	}
-
-	if self.Config != nil && self.Config.Consul != nil && self.Config.Consul.Token != "" {
-		self.Config.Consul.Token = "<redacted>"
-	}
-
-	if self.Config != nil && self.Config.Telemetry != nil && self.Config.Telemetry.CirconusAPIToken != "" {
-		self.Config.Telemetry.CirconusAPIToken = "<redacted>"
+	// Return the fully resolved runtime configuration with any secrets
+	// redacted so operators can verify what the agent actually loaded.
+	self.Config, err = s.agent.config.Sanitized()
+	if err != nil {
+		return nil, CodedError(500, err.Error())
 	}
 
 	return self, nil
@@ -4,6 +4,7 @@ package agent
 
 import (
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/command/agent/event"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
@@ -13,11 +14,19 @@ type EnterpriseAgent struct{}
 
 func (a *Agent) setupEnterpriseAgent(log hclog.Logger) error {
 	// configure eventer
-	a.auditor = &noOpAuditor{}
+	eventer, err := event.NewEventer(a.config.Audit, log)
+	if err != nil {
+		return err
+	}
+	a.auditor = eventer
 
 	return nil
 }
 
 func (a *Agent) entReloadEventer(cfg *config.AuditConfig) error {
-	return nil
+	eventer, ok := a.auditor.(*event.Eventer)
+	if !ok {
+		return nil
+	}
+	return eventer.Reload(cfg)
 }
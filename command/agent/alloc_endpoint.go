@@ -33,6 +33,23 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 		return nil, nil
 	}
 
+	// Parse the projection of stub fields, e.g. ?fields=task_states,resources.
+	// Without the parameter the full default stub is returned.
+	if fieldsStr := req.URL.Query().Get("fields"); fieldsStr != "" {
+		fields := &structs.AllocStubFields{}
+		for _, field := range strings.Split(fieldsStr, ",") {
+			switch strings.TrimSpace(field) {
+			case "task_states":
+				fields.TaskStates = true
+			case "resources":
+				fields.Resources = true
+			default:
+				return nil, CodedError(400, fmt.Sprintf("Unknown stub field %q", field))
+			}
+		}
+		args.Fields = fields
+	}
+
 	var out structs.AllocListResponse
 	if err := s.agent.RPC("Alloc.List", &args, &out); err != nil {
 		return nil, err
@@ -66,6 +83,10 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 	switch tokens[1] {
 	case "stop":
 		return s.allocStop(allocID, resp, req)
+	case "hold":
+		return s.allocHold(allocID, resp, req)
+	case "checkpoint":
+		return s.allocCheckpointMeta(allocID, resp, req)
 	}
 
 	return nil, CodedError(404, resourceNotFoundErr)
@@ -136,6 +157,59 @@ func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *ht
 	return &out, nil
 }
 
+func (s *HTTPServer) allocHold(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	hr := &structs.AllocHoldRequest{
+		AllocID: allocID,
+		Release: req.URL.Query().Get("release") == "true",
+	}
+	s.parseWriteRequest(req, &hr.WriteRequest)
+
+	var out structs.AllocHoldResponse
+	rpcErr := s.agent.RPC("Alloc.Hold", &hr, &out)
+
+	if rpcErr != nil {
+		if structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, allocNotFoundErr)
+		}
+		return nil, rpcErr
+	}
+
+	setIndex(resp, out.Index)
+	return &out, nil
+}
+
+func (s *HTTPServer) allocCheckpointMeta(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var meta map[string]string
+	if err := decodeBody(req, &meta); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	args := structs.AllocCheckpointMetaSetRequest{
+		AllocID: allocID,
+		Meta:    meta,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Alloc.SetCheckpointMeta", &args, &out); err != nil {
+		if structs.IsErrUnknownAllocation(err) {
+			return nil, CodedError(404, allocNotFoundErr)
+		}
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
 func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/client/allocation/")
 
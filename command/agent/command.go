@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"reflect"
@@ -30,6 +32,8 @@ import (
 	gatedwriter "github.com/hashicorp/nomad/helper/gated-writer"
 	"github.com/hashicorp/nomad/helper/logging"
 	"github.com/hashicorp/nomad/helper/winsvc"
+	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/version"
 	"github.com/mitchellh/cli"
@@ -54,6 +58,16 @@ type Command struct {
 	logFilter      *logutils.LevelFilter
 	logOutput      io.Writer
 	retryJoinErrCh chan struct{}
+
+	// devModeCfg holds the parsed -dev family of flags
+	devModeCfg *devModeConfig
+
+	// devPreloadJobs is the directory of job files registered at startup
+	// in dev mode, set via -dev-preload-jobs
+	devPreloadJobs string
+
+	// devDeps are the dev mode Consul and Vault child processes
+	devDeps []*exec.Cmd
 }
 
 func (c *Command) readConfig() *Config {
@@ -81,8 +95,14 @@ func (c *Command) readConfig() *Config {
 	// Role options
 	var devMode bool
 	var devConnectMode bool
+	var devConsulMode bool
+	var devVaultMode bool
 	flags.BoolVar(&devMode, "dev", false, "")
 	flags.BoolVar(&devConnectMode, "dev-connect", false, "")
+	flags.BoolVar(&devConsulMode, "dev-consul", false, "")
+	flags.BoolVar(&devVaultMode, "dev-vault", false, "")
+	flags.StringVar(&c.devPreloadJobs, "dev-preload-jobs", "", "")
+	flags.StringVar(&cmdConfig.Client.NodeID, "dev-node-id", "", "")
 	flags.BoolVar(&cmdConfig.Server.Enabled, "server", false, "")
 	flags.BoolVar(&cmdConfig.Client.Enabled, "client", false, "")
 
@@ -212,11 +232,20 @@ func (c *Command) readConfig() *Config {
 	}
 
 	// Load the configuration
-	dev, err := newDevModeConfig(devMode, devConnectMode)
+	dev, err := newDevModeConfig(devMode, devConnectMode, devConsulMode, devVaultMode)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return nil
 	}
+	c.devModeCfg = dev
+	if c.devPreloadJobs != "" && dev == nil {
+		c.Ui.Error("-dev-preload-jobs may only be used in dev mode")
+		return nil
+	}
+	if cmdConfig.Client.NodeID != "" && !helper.IsUUID(cmdConfig.Client.NodeID) {
+		c.Ui.Error(fmt.Sprintf("Invalid -dev-node-id %q: must be a UUID", cmdConfig.Client.NodeID))
+		return nil
+	}
 	var config *Config
 	if dev != nil {
 		config = DevConfig(dev)
@@ -530,6 +559,10 @@ func (c *Command) AutocompleteFlags() complete.Flags {
 	return map[string]complete.Predictor{
 		"-dev":                           complete.PredictNothing,
 		"-dev-connect":                   complete.PredictNothing,
+		"-dev-consul":                    complete.PredictNothing,
+		"-dev-vault":                     complete.PredictNothing,
+		"-dev-preload-jobs":              complete.PredictDirs("*"),
+		"-dev-node-id":                   complete.PredictAnything,
 		"-server":                        complete.PredictNothing,
 		"-client":                        complete.PredictNothing,
 		"-bootstrap-expect":              complete.PredictAnything,
@@ -641,6 +674,14 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Launch the dev mode Consul and Vault dependencies before the agent so
+	// they are available as it fingerprints and connects
+	if err := c.startDevDependencies(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	defer c.stopDevDependencies()
+
 	// Create the agent
 	if err := c.setupAgent(config, logger, logOutput, inmem); err != nil {
 		logGate.Flush()
@@ -704,10 +745,118 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Preload jobs into the dev mode agent
+	if config.DevMode && c.devPreloadJobs != "" {
+		go c.preloadDevJobs(config, c.devPreloadJobs)
+	}
+
 	// Wait for exit
 	return c.handleSignals()
 }
 
+// startDevDependencies launches dev mode Consul and Vault agents as child
+// processes when requested via -dev-consul or -dev-vault.
+func (c *Command) startDevDependencies() error {
+	if c.devModeCfg == nil {
+		return nil
+	}
+
+	start := func(name string, args ...string) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdout = c.logOutput
+		cmd.Stderr = c.logOutput
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("Failed to start dev mode %s: %v", name, err)
+		}
+		c.Ui.Output(fmt.Sprintf("Started dev mode %s (pid %d)", name, cmd.Process.Pid))
+		c.devDeps = append(c.devDeps, cmd)
+		return nil
+	}
+
+	if c.devModeCfg.consulMode {
+		if err := start("consul", "agent", "-dev"); err != nil {
+			return err
+		}
+	}
+	if c.devModeCfg.vaultMode {
+		if err := start("vault", "server", "-dev", "-dev-root-token-id="+devVaultRootToken); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopDevDependencies stops the dev mode Consul and Vault child processes.
+func (c *Command) stopDevDependencies() {
+	for _, cmd := range c.devDeps {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	c.devDeps = nil
+}
+
+// preloadDevJobs registers the job files found in dir once the dev mode
+// agent has elected itself leader, so integration tests and demos come up
+// with their jobs already submitted.
+func (c *Command) preloadDevJobs(config *Config, dir string) {
+	// Wait for the single dev mode server to elect itself leader
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		var leader string
+		args := structs.GenericRequest{
+			QueryOptions: structs.QueryOptions{Region: config.Region},
+		}
+		if err := c.agent.RPC("Status.Leader", &args, &leader); err == nil && leader != "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			c.Ui.Error(fmt.Sprintf("Failed to preload jobs from %s: no leader elected", dir))
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to preload jobs from %s: %v", dir, err))
+		return
+	}
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		switch filepath.Ext(f.Name()) {
+		case ".hcl", ".nomad":
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, f.Name())
+		apiJob, err := jobspec.ParseFile(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse job file %s: %v", path, err))
+			continue
+		}
+		apiJob.Canonicalize()
+		job := ApiJobToStructJob(apiJob)
+
+		req := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    job.Region,
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		if err := c.agent.RPC("Job.Register", &req, &resp); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to register job file %s: %v", path, err))
+			continue
+		}
+		c.Ui.Output(fmt.Sprintf("Preloaded job %q from %s", job.ID, path))
+	}
+}
+
 // handleRetryJoin is used to start retry joining if it is configured.
 func (c *Command) handleRetryJoin(config *Config) error {
 	c.retryJoinErrCh = make(chan struct{})
@@ -1229,6 +1378,24 @@ General Options (clients and servers):
     interface rather than localhost for using Consul Connect. This
     mode is supported only on Linux as root.
 
+  -dev-consul
+    Start the agent in development mode and launch a dev mode Consul
+    agent alongside it. Requires a 'consul' binary in Nomad's $PATH.
+
+  -dev-vault
+    Start the agent in development mode and launch a dev mode Vault
+    server alongside it, with the Vault integration pre-configured
+    against it. Requires a 'vault' binary in Nomad's $PATH.
+
+  -dev-preload-jobs=<dir>
+    Register the job files found in the given directory once the dev
+    mode agent has started, so demos and integration tests need no
+    separate job submission step.
+
+  -dev-node-id=<uuid>
+    Use the given node ID rather than generating one, so integration
+    tests and demos get a deterministic node identity.
+
 Server Options:
 
   -server
@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/version"
+	"github.com/mitchellh/copystructure"
 )
 
 // Config is the configuration for the Nomad agent.
@@ -235,6 +236,14 @@ type ClientConfig struct {
 	// particular set of ports.
 	Reserved *Resources `hcl:"reserved"`
 
+	// MinDynamicPort is the smallest dynamic port generated for allocations
+	// on this client. A zero value uses the scheduler default.
+	MinDynamicPort int `hcl:"min_dynamic_port"`
+
+	// MaxDynamicPort is the largest dynamic port generated for allocations
+	// on this client. A zero value uses the scheduler default.
+	MaxDynamicPort int `hcl:"max_dynamic_port"`
+
 	// GCInterval is the time interval at which the client triggers garbage
 	// collection
 	GCInterval    time.Duration
@@ -260,6 +269,11 @@ type ClientConfig struct {
 	// random UUID.
 	NoHostUUID *bool `hcl:"no_host_uuid"`
 
+	// NodeID is a static node ID to use in place of the generated one so
+	// that integration tests and demos get a deterministic node identity.
+	// It is set via the -dev-node-id flag.
+	NodeID string `hcl:"node_id"`
+
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool `hcl:"disable_remote_exec"`
 
@@ -273,6 +287,10 @@ type ClientConfig struct {
 	// available to jobs running on this node.
 	HostVolumes []*structs.ClientHostVolumeConfig `hcl:"host_volume"`
 
+	// EnvProviders is the list of task environment providers to run for
+	// tasks on this node, each with an optional namespace allow list.
+	EnvProviders []*structs.ClientEnvProviderConfig `hcl:"env_provider"`
+
 	// CNIPath is the path to search for CNI plugins, multiple paths can be
 	// specified colon delimited
 	CNIPath string `hcl:"cni_path"`
@@ -315,6 +333,11 @@ type ClientTemplateConfig struct {
 	// client host. By default templates can access files only within
 	// the task directory.
 	DisableSandbox bool `hcl:"disable_file_sandbox"`
+
+	// SecretsProviders configures the secret backends available to the
+	// {{ secret ... }} template function, keyed by provider name. Vault
+	// remains the default backend and needs no entry here.
+	SecretsProviders map[string]map[string]string `hcl:"secrets_providers"`
 }
 
 // ACLConfig is configuration specific to the ACL system
@@ -341,6 +364,13 @@ type ACLConfig struct {
 	// within the authoritative region.
 	ReplicationToken string `hcl:"replication_token"`
 
+	// TLSIdentityPolicies maps identities presented by verified TLS client
+	// certificates (the certificate common name, DNS SANs, or organizational
+	// units) to the set of ACL policies they are granted. Requests that
+	// present a matching certificate and no token are authorized using the
+	// mapped policies.
+	TLSIdentityPolicies map[string][]string `hcl:"tls_identity_policies"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -381,6 +411,11 @@ type ServerConfig struct {
 	// that the workers dequeue for processing.
 	EnabledSchedulers []string `hcl:"enabled_schedulers"`
 
+	// SchedulerWorkerPins maps scheduler types to a number of scheduler
+	// workers dedicated to evaluations of that type. The total must be less
+	// than num_schedulers so unpinned workers remain for the other types.
+	SchedulerWorkerPins map[string]int `hcl:"scheduler_worker_pins"`
+
 	// NodeGCThreshold controls how "old" a node must be to be collected by GC.
 	// Age is not the only requirement for a node to be GCed but the threshold
 	// can be used to filter by age.
@@ -405,6 +440,11 @@ type ServerConfig struct {
 	// GCed but the threshold can be used to filter by age.
 	DeploymentGCThreshold string `hcl:"deployment_gc_threshold"`
 
+	// CanaryGCThreshold controls how "old" a failed canary allocation of a
+	// failed deployment must be to be collected by GC while the deployment
+	// itself is retained.
+	CanaryGCThreshold string `hcl:"canary_gc_threshold"`
+
 	// CSIVolumeClaimGCThreshold controls how "old" a CSI volume must be to
 	// have its claims collected by GC.	Age is not the only requirement for
 	// a volume to be GCed but the threshold can be used to filter by age.
@@ -415,6 +455,34 @@ type ServerConfig struct {
 	// GCed but the threshold can be used to filter by age.
 	CSIPluginGCThreshold string `hcl:"csi_plugin_gc_threshold"`
 
+	// BlockedEvalEscalationThreshold is the duration after which an
+	// evaluation that is still blocked is escalated with a warning
+	// summarizing the missing resources. Defaults to zero, which disables
+	// escalation notices.
+	BlockedEvalEscalationThreshold    time.Duration
+	BlockedEvalEscalationThresholdHCL string `hcl:"blocked_eval_escalation_threshold" json:"-"`
+
+	// JobDefaults are default job values merged into submitted jobs at
+	// admission when the job does not specify them.
+	JobDefaults *config.JobDefaults `hcl:"job_defaults"`
+
+	// EventBufferSize is the number of event batches the event broker
+	// retains in memory for index-based resumption.
+	EventBufferSize *int `hcl:"event_buffer_size"`
+
+	// EventBufferPath enables on-disk persistence of the event buffer
+	// when set, so event stream subscribers can resume across server
+	// restarts.
+	EventBufferPath string `hcl:"event_buffer_path"`
+
+	// PeriodicLaunchFailureWebhooks are webhook URLs that periodic job
+	// launch failures are posted to.
+	PeriodicLaunchFailureWebhooks []string `hcl:"periodic_launch_failure_webhooks"`
+
+	// DispatchPayloadSizeLimit is the maximum size in bytes of an
+	// uncompressed dispatch payload.
+	DispatchPayloadSizeLimit *int `hcl:"dispatch_payload_size_limit"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -720,23 +788,42 @@ func (r *Resources) CanParseReserved() error {
 	return err
 }
 
-// devModeConfig holds the config for the -dev and -dev-connect flags
+// devModeConfig holds the config for the -dev family of flags
 type devModeConfig struct {
 	// mode flags are set at the command line via -dev and -dev-connect
 	defaultMode bool
 	connectMode bool
 
+	// consulMode and vaultMode launch dev mode Consul and Vault agents as
+	// child processes, set via -dev-consul and -dev-vault
+	consulMode bool
+	vaultMode  bool
+
 	bindAddr string
 	iface    string
 }
 
 // newDevModeConfig parses the optional string value of the -dev flag
-func newDevModeConfig(devMode, connectMode bool) (*devModeConfig, error) {
-	if !devMode && !connectMode {
+func newDevModeConfig(devMode, connectMode, consulMode, vaultMode bool) (*devModeConfig, error) {
+	if !devMode && !connectMode && !consulMode && !vaultMode {
 		return nil, nil
 	}
 	mode := &devModeConfig{}
 	mode.defaultMode = devMode
+	mode.consulMode = consulMode
+	mode.vaultMode = vaultMode
+	if consulMode {
+		// Ensure Consul is on PATH before launching it
+		if _, err := exec.LookPath("consul"); err != nil {
+			return nil, fmt.Errorf("-dev-consul requires a 'consul' binary in Nomad's $PATH")
+		}
+	}
+	if vaultMode {
+		// Ensure Vault is on PATH before launching it
+		if _, err := exec.LookPath("vault"); err != nil {
+			return nil, fmt.Errorf("-dev-vault requires a 'vault' binary in Nomad's $PATH")
+		}
+	}
 	if connectMode {
 		if runtime.GOOS != "linux" {
 			// strictly speaking -dev-connect only binds to the
@@ -835,9 +922,27 @@ func DevConfig(mode *devModeConfig) *Config {
 	conf.Telemetry.PublishAllocationMetrics = true
 	conf.Telemetry.PublishNodeMetrics = true
 
+	if mode.vaultMode {
+		// Point the agent at the dev mode Vault launched alongside it
+		conf.Vault.Enabled = helper.BoolToPtr(true)
+		conf.Vault.Addr = devVaultAddr
+		conf.Vault.Token = devVaultRootToken
+		conf.Vault.AllowUnauthenticated = helper.BoolToPtr(true)
+	}
+
 	return conf
 }
 
+const (
+	// devVaultRootToken is the root token given to the dev mode Vault
+	// launched by -dev-vault.
+	devVaultRootToken = "root"
+
+	// devVaultAddr is the address of the dev mode Vault launched by
+	// -dev-vault.
+	devVaultAddr = "http://127.0.0.1:8200"
+)
+
 // DefaultConfig is a the baseline configuration for Nomad
 func DefaultConfig() *Config {
 	return &Config{
@@ -907,6 +1012,45 @@ func DefaultConfig() *Config {
 	}
 }
 
+// redacted is the replacement value for any secret found in the
+// configuration returned by Sanitized.
+const redacted = "<redacted>"
+
+// Sanitized returns a deep copy of the config with all secrets redacted,
+// suitable for returning to operators via the HTTP API.
+func (c *Config) Sanitized() (*Config, error) {
+	copied, err := copystructure.Copy(c)
+	if err != nil {
+		return nil, err
+	}
+	sanitized := copied.(*Config)
+
+	if sanitized.Vault != nil && sanitized.Vault.Token != "" {
+		sanitized.Vault.Token = redacted
+	}
+	if sanitized.ACL != nil && sanitized.ACL.ReplicationToken != "" {
+		sanitized.ACL.ReplicationToken = redacted
+	}
+	if sanitized.Consul != nil && sanitized.Consul.Token != "" {
+		sanitized.Consul.Token = redacted
+	}
+	if sanitized.Telemetry != nil && sanitized.Telemetry.CirconusAPIToken != "" {
+		sanitized.Telemetry.CirconusAPIToken = redacted
+	}
+
+	// Secrets provider configurations are opaque maps that commonly hold
+	// credentials, so redact every value.
+	if sanitized.Client != nil && sanitized.Client.TemplateConfig != nil {
+		for _, providerConfig := range sanitized.Client.TemplateConfig.SecretsProviders {
+			for k := range providerConfig {
+				providerConfig[k] = redacted
+			}
+		}
+	}
+
+	return sanitized, nil
+}
+
 // Listener can be used to get a new listener using a custom bind address.
 // If the bind provided address is empty, the BindAddr is used instead.
 func (c *Config) Listener(proto, addr string, port int) (net.Listener, error) {
@@ -1308,6 +1452,9 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
+	if len(b.TLSIdentityPolicies) != 0 {
+		result.TLSIdentityPolicies = b.TLSIdentityPolicies
+	}
 	return &result
 }
 
@@ -1340,6 +1487,9 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.NumSchedulers != nil {
 		result.NumSchedulers = helper.IntToPtr(*b.NumSchedulers)
 	}
+	if len(b.SchedulerWorkerPins) != 0 {
+		result.SchedulerWorkerPins = b.SchedulerWorkerPins
+	}
 	if b.NodeGCThreshold != "" {
 		result.NodeGCThreshold = b.NodeGCThreshold
 	}
@@ -1355,12 +1505,40 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.DeploymentGCThreshold != "" {
 		result.DeploymentGCThreshold = b.DeploymentGCThreshold
 	}
+	if b.CanaryGCThreshold != "" {
+		result.CanaryGCThreshold = b.CanaryGCThreshold
+	}
 	if b.CSIVolumeClaimGCThreshold != "" {
 		result.CSIVolumeClaimGCThreshold = b.CSIVolumeClaimGCThreshold
 	}
 	if b.CSIPluginGCThreshold != "" {
 		result.CSIPluginGCThreshold = b.CSIPluginGCThreshold
 	}
+	if b.BlockedEvalEscalationThreshold != 0 {
+		result.BlockedEvalEscalationThreshold = b.BlockedEvalEscalationThreshold
+	}
+	if b.BlockedEvalEscalationThresholdHCL != "" {
+		result.BlockedEvalEscalationThresholdHCL = b.BlockedEvalEscalationThresholdHCL
+	}
+	if b.JobDefaults != nil {
+		if result.JobDefaults != nil {
+			result.JobDefaults = result.JobDefaults.Merge(b.JobDefaults)
+		} else {
+			result.JobDefaults = b.JobDefaults.Copy()
+		}
+	}
+	if b.EventBufferSize != nil {
+		result.EventBufferSize = helper.IntToPtr(*b.EventBufferSize)
+	}
+	if b.EventBufferPath != "" {
+		result.EventBufferPath = b.EventBufferPath
+	}
+	if len(b.PeriodicLaunchFailureWebhooks) != 0 {
+		result.PeriodicLaunchFailureWebhooks = b.PeriodicLaunchFailureWebhooks
+	}
+	if b.DispatchPayloadSizeLimit != nil {
+		result.DispatchPayloadSizeLimit = helper.IntToPtr(*b.DispatchPayloadSizeLimit)
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -1468,6 +1646,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	} else if b.Reserved != nil {
 		result.Reserved = result.Reserved.Merge(b.Reserved)
 	}
+	if b.MinDynamicPort != 0 {
+		result.MinDynamicPort = b.MinDynamicPort
+	}
+	if b.MaxDynamicPort != 0 {
+		result.MaxDynamicPort = b.MaxDynamicPort
+	}
 	if b.GCInterval != 0 {
 		result.GCInterval = b.GCInterval
 	}
@@ -1490,6 +1674,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
 	}
+	if b.NodeID != "" {
+		result.NodeID = b.NodeID
+	}
 
 	if b.DisableRemoteExec {
 		result.DisableRemoteExec = b.DisableRemoteExec
@@ -1536,6 +1723,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.HostVolumes = structs.HostVolumeSliceMerge(a.HostVolumes, b.HostVolumes)
 	}
 
+	if len(a.EnvProviders) == 0 && len(b.EnvProviders) != 0 {
+		result.EnvProviders = structs.CopySliceClientEnvProviderConfig(b.EnvProviders)
+	} else if len(b.EnvProviders) != 0 {
+		result.EnvProviders = structs.EnvProviderSliceMerge(a.EnvProviders, b.EnvProviders)
+	}
+
 	if b.CNIPath != "" {
 		result.CNIPath = b.CNIPath
 	}
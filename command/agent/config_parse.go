@@ -53,6 +53,7 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"acl.token_ttl", &c.ACL.TokenTTL, &c.ACL.TokenTTLHCL},
 		{"acl.policy_ttl", &c.ACL.PolicyTTL, &c.ACL.PolicyTTLHCL},
 		{"client.server_join.retry_interval", &c.Client.ServerJoin.RetryInterval, &c.Client.ServerJoin.RetryIntervalHCL},
+		{"server.blocked_eval_escalation_threshold", &c.Server.BlockedEvalEscalationThreshold, &c.Server.BlockedEvalEscalationThresholdHCL},
 		{"server.heartbeat_grace", &c.Server.HeartbeatGrace, &c.Server.HeartbeatGraceHCL},
 		{"server.min_heartbeat_ttl", &c.Server.MinHeartbeatTTL, &c.Server.MinHeartbeatTTLHCL},
 		{"server.retry_interval", &c.Server.RetryInterval, &c.Server.RetryIntervalHCL},
@@ -63,6 +64,31 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"telemetry.collection_interval", &c.Telemetry.collectionInterval, &c.Telemetry.CollectionInterval},
 	}
 
+	// Add default job value durations for time.Duration parsing
+	if jd := c.Server.JobDefaults; jd != nil {
+		if jd.Restart != nil {
+			tds = append(tds,
+				td{"server.job_defaults.restart.interval", &jd.Restart.Interval, &jd.Restart.IntervalHCL},
+				td{"server.job_defaults.restart.delay", &jd.Restart.Delay, &jd.Restart.DelayHCL},
+			)
+		}
+		if jd.Reschedule != nil {
+			tds = append(tds,
+				td{"server.job_defaults.reschedule.interval", &jd.Reschedule.Interval, &jd.Reschedule.IntervalHCL},
+				td{"server.job_defaults.reschedule.delay", &jd.Reschedule.Delay, &jd.Reschedule.DelayHCL},
+				td{"server.job_defaults.reschedule.max_delay", &jd.Reschedule.MaxDelay, &jd.Reschedule.MaxDelayHCL},
+			)
+		}
+		if jd.Update != nil {
+			tds = append(tds,
+				td{"server.job_defaults.update.stagger", &jd.Update.Stagger, &jd.Update.StaggerHCL},
+				td{"server.job_defaults.update.min_healthy_time", &jd.Update.MinHealthyTime, &jd.Update.MinHealthyTimeHCL},
+				td{"server.job_defaults.update.healthy_deadline", &jd.Update.HealthyDeadline, &jd.Update.HealthyDeadlineHCL},
+				td{"server.job_defaults.update.progress_deadline", &jd.Update.ProgressDeadline, &jd.Update.ProgressDeadlineHCL},
+			)
+		}
+	}
+
 	// Add enterprise audit sinks for time.Duration parsing
 	for i, sink := range c.Audit.Sinks {
 		tds = append(tds, td{
@@ -138,6 +164,12 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "host_volume")
 	}
 
+	// Remove EnvProvider extra keys
+	for _, ep := range c.Client.EnvProviders {
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, ep.Name)
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "env_provider")
+	}
+
 	// Remove HostNetwork extra keys
 	for _, hn := range c.Client.HostNetworks {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, hn.Name)
@@ -155,11 +187,19 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, "sink")
 	}
 
-	for _, k := range []string{"enabled_schedulers", "start_join", "retry_join", "server_join"} {
+	for _, k := range []string{"enabled_schedulers", "start_join", "retry_join", "server_join", "job_defaults"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
 	}
 
+	// Remove default job value keys
+	if jd := c.Server.JobDefaults; jd != nil {
+		for _, k := range []string{"restart", "reschedule", "update"} {
+			helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
+			helper.RemoveEqualFold(&jd.ExtraKeysHCL, k)
+		}
+	}
+
 	for _, k := range []string{"datadog_tags"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "telemetry")
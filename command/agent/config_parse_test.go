@@ -86,6 +86,13 @@ var basicConfig = &Config{
 		HostVolumes: []*structs.ClientHostVolumeConfig{
 			{Name: "tmp", Path: "/tmp"},
 		},
+		EnvProviders: []*structs.ClientEnvProviderConfig{
+			{
+				Name:       "static",
+				Namespaces: []string{"default"},
+				Config:     map[string]string{"SITE": "east"},
+			},
+		},
 		CNIPath:             "/tmp/cni_path",
 		BridgeNetworkName:   "custom_bridge_name",
 		BridgeNetworkSubnet: "custom_bridge_subnet",
@@ -857,3 +864,51 @@ func permutations(arr []string) [][]string {
 	helper(arr, len(arr))
 	return res
 }
+
+func TestConfig_ParseJobDefaults(t *testing.T) {
+	t.Parallel()
+
+	path, err := filepath.Abs(filepath.Join(".", "testdata", "job-defaults.hcl"))
+	require.NoError(t, err)
+
+	actual, err := ParseConfigFile(path)
+	require.NoError(t, err)
+
+	expect := &config.JobDefaults{
+		Restart: &config.RestartDefaults{
+			Attempts:    helper.IntToPtr(5),
+			Interval:    1 * time.Hour,
+			IntervalHCL: "1h",
+			Delay:       30 * time.Second,
+			DelayHCL:    "30s",
+			Mode:        "delay",
+		},
+		Reschedule: &config.RescheduleDefaults{
+			Attempts:      helper.IntToPtr(3),
+			Interval:      2 * time.Hour,
+			IntervalHCL:   "2h",
+			Delay:         10 * time.Second,
+			DelayHCL:      "10s",
+			DelayFunction: "fibonacci",
+			MaxDelay:      15 * time.Minute,
+			MaxDelayHCL:   "15m",
+			Unlimited:     helper.BoolToPtr(false),
+		},
+		Update: &config.UpdateDefaults{
+			Stagger:             1 * time.Minute,
+			StaggerHCL:          "1m",
+			MaxParallel:         helper.IntToPtr(2),
+			HealthCheck:         "task_states",
+			MinHealthyTime:      30 * time.Second,
+			MinHealthyTimeHCL:   "30s",
+			HealthyDeadline:     10 * time.Minute,
+			HealthyDeadlineHCL:  "10m",
+			ProgressDeadline:    20 * time.Minute,
+			ProgressDeadlineHCL: "20m",
+			AutoRevert:          helper.BoolToPtr(true),
+			AutoPromote:         helper.BoolToPtr(true),
+			Canary:              helper.IntToPtr(1),
+		},
+	}
+	require.Equal(t, expect, actual.Server.JobDefaults)
+}
@@ -698,7 +698,7 @@ func TestConfig_DevModeFlag(t *testing.T) {
 	}
 	for _, c := range cases {
 		t.Run("", func(t *testing.T) {
-			mode, err := newDevModeConfig(c.dev, c.connect)
+			mode, err := newDevModeConfig(c.dev, c.connect, false, false)
 			if err != nil && c.expectedErr == "" {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -720,6 +720,37 @@ func TestConfig_DevModeFlag(t *testing.T) {
 
 // TestConfig_normalizeAddrs_DevMode asserts that normalizeAddrs allows
 // advertising localhost in dev mode.
+func TestConfig_Sanitized(t *testing.T) {
+	c := DefaultConfig()
+	c.Vault.Token = "vault-token"
+	c.ACL.ReplicationToken = "replication-token"
+	c.Consul.Token = "consul-token"
+	c.Telemetry.CirconusAPIToken = "circonus-token"
+	c.Client.TemplateConfig = &ClientTemplateConfig{
+		SecretsProviders: map[string]map[string]string{
+			"custom": {
+				"address": "https://secrets.example.com",
+				"api_key": "super-secret",
+			},
+		},
+	}
+
+	sanitized, err := c.Sanitized()
+	require.NoError(t, err)
+
+	// All secrets should be redacted
+	require.Equal(t, "<redacted>", sanitized.Vault.Token)
+	require.Equal(t, "<redacted>", sanitized.ACL.ReplicationToken)
+	require.Equal(t, "<redacted>", sanitized.Consul.Token)
+	require.Equal(t, "<redacted>", sanitized.Telemetry.CirconusAPIToken)
+	require.Equal(t, "<redacted>", sanitized.Client.TemplateConfig.SecretsProviders["custom"]["address"])
+	require.Equal(t, "<redacted>", sanitized.Client.TemplateConfig.SecretsProviders["custom"]["api_key"])
+
+	// The original config should be untouched
+	require.Equal(t, "vault-token", c.Vault.Token)
+	require.Equal(t, "super-secret", c.Client.TemplateConfig.SecretsProviders["custom"]["api_key"])
+}
+
 func TestConfig_normalizeAddrs_DevMode(t *testing.T) {
 	// allow to advertise 127.0.0.1 if dev-mode is enabled
 	c := &Config{
@@ -867,7 +867,14 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, w
 	// Explicitly set the service kind in case this service represents a Connect gateway.
 	kind := api.ServiceKindTypical
 	if service.Connect.IsGateway() {
-		kind = api.ServiceKindIngressGateway
+		switch {
+		case service.Connect.Gateway.Ingress != nil:
+			kind = api.ServiceKindIngressGateway
+		case service.Connect.Gateway.Terminating != nil:
+			kind = api.ServiceKindTerminatingGateway
+		case service.Connect.Gateway.Mesh != nil:
+			kind = api.ServiceKindMeshGateway
+		}
 	}
 
 	// Build the Consul Service registration request
@@ -46,6 +46,11 @@ func newConnectGateway(serviceName string, connect *structs.ConsulConnect) *api.
 	}
 
 	proxy := connect.Gateway.Proxy
+	if proxy == nil {
+		// a mesh or terminating gateway in host network mode may not set
+		// any proxy configuration
+		return nil
+	}
 
 	envoyConfig := make(map[string]interface{})
 
@@ -0,0 +1,385 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// Stage is the point in the request lifecycle at which an audit event is
+// emitted.
+type Stage string
+
+const (
+	// OperationReceived is emitted when a request has been received but
+	// not yet processed.
+	OperationReceived Stage = "OperationReceived"
+
+	// OperationComplete is emitted when a request has finished
+	// processing, and includes the response.
+	OperationComplete Stage = "OperationComplete"
+)
+
+const (
+	// HTTPEvent is the audit event type for operations received over the
+	// HTTP API.
+	HTTPEvent = "HTTPEvent"
+
+	// SinkTypeFile writes events to a local file with rotation.
+	SinkTypeFile = "file"
+
+	// SinkTypeSyslog writes events to the system syslog daemon.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeTCP streams events to a remote address over TCP,
+	// optionally with TLS.
+	SinkTypeTCP = "tcp"
+
+	// SinkFormatJSON is the only supported sink format.
+	SinkFormatJSON = "json"
+
+	// EnforcedDelivery means a request must fail if its audit log cannot
+	// be delivered to a sink.
+	EnforcedDelivery = "enforced"
+
+	// BestEffortDelivery means delivery failures are logged but do not
+	// fail the audited request.
+	BestEffortDelivery = "best-effort"
+)
+
+// Event is an audit event emitted for a single stage of a request.
+type Event struct {
+	// ID identifies the request and is shared by all stages of it
+	ID string `json:"id"`
+
+	Stage     Stage     `json:"stage"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Auth     *Auth     `json:"auth,omitempty"`
+	Request  *Request  `json:"request,omitempty"`
+	Response *Response `json:"response,omitempty"`
+}
+
+// Auth describes the ACL token a request was authenticated with.
+type Auth struct {
+	AccessorID string    `json:"accessor_id"`
+	Name       string    `json:"name,omitempty"`
+	Global     bool      `json:"global"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// Request describes the audited operation.
+type Request struct {
+	// Operation is the HTTP method of the request
+	Operation string `json:"operation"`
+
+	// Endpoint is the path the request was made against
+	Endpoint string `json:"endpoint"`
+
+	RemoteAddress string `json:"remote_address,omitempty"`
+	UserAgent     string `json:"user_agent,omitempty"`
+}
+
+// Response describes the outcome of an audited operation.
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Eventer is an Auditor that filters audit events and delivers them to the
+// configured sinks as JSON lines.
+type Eventer struct {
+	logger hclog.Logger
+
+	// l protects the fields below, which are swapped on Reload
+	l       sync.RWMutex
+	enabled bool
+	filters []*filter
+	sinks   []*sink
+}
+
+// Ensure Eventer is an Auditor
+var _ Auditor = &Eventer{}
+
+// NewEventer returns an Eventer for the given audit config.
+func NewEventer(cfg *config.AuditConfig, logger hclog.Logger) (*Eventer, error) {
+	e := &Eventer{
+		logger: logger.Named("audit"),
+	}
+	if err := e.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload rebuilds the filters and sinks from the given config, replacing the
+// existing ones.
+func (e *Eventer) Reload(cfg *config.AuditConfig) error {
+	var enabled bool
+	var filters []*filter
+	var sinks []*sink
+
+	if cfg != nil {
+		enabled = cfg.Enabled != nil && *cfg.Enabled
+
+		for _, f := range cfg.Filters {
+			filter, err := newFilter(f)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, filter)
+		}
+
+		for _, s := range cfg.Sinks {
+			sink, err := newSink(s)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, sink)
+		}
+	}
+
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	// Close the sinks being replaced
+	for _, s := range e.sinks {
+		s.w.Close()
+	}
+
+	e.enabled = enabled
+	e.filters = filters
+	e.sinks = sinks
+	return nil
+}
+
+// Event delivers the given audit event to all sinks, unless it is excluded
+// by a filter. The payload must be an *Event.
+func (e *Eventer) Event(ctx context.Context, eventType string, payload interface{}) error {
+	e.l.RLock()
+	defer e.l.RUnlock()
+
+	if !e.enabled {
+		return nil
+	}
+
+	event, ok := payload.(*Event)
+	if !ok {
+		return fmt.Errorf("unexpected audit payload type %T", payload)
+	}
+
+	// Skip events excluded by a filter. The first matching filter
+	// decides, and its sample rate determines whether the event is still
+	// written.
+	for _, f := range e.filters {
+		if f.matches(event) {
+			if !f.sample() {
+				return nil
+			}
+			break
+		}
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %v", err)
+	}
+	data = append(data, '\n')
+
+	var mErr multierror.Error
+	for _, s := range e.sinks {
+		if _, err := s.w.Write(data); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("failed to deliver audit event to sink %q: %v", s.name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Enabled returns whether the eventer is enabled.
+func (e *Eventer) Enabled() bool {
+	e.l.RLock()
+	defer e.l.RUnlock()
+	return e.enabled
+}
+
+// SetEnabled sets the eventer to enabled or disabled.
+func (e *Eventer) SetEnabled(enabled bool) {
+	e.l.Lock()
+	defer e.l.Unlock()
+	e.enabled = enabled
+}
+
+// Reopen signals all sinks to reopen their files, for use with external log
+// rotation.
+func (e *Eventer) Reopen() error {
+	e.l.RLock()
+	defer e.l.RUnlock()
+
+	var mErr multierror.Error
+	for _, s := range e.sinks {
+		if err := s.w.Reopen(); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// DeliveryEnforced returns true if delivery to any sink must be enforced.
+func (e *Eventer) DeliveryEnforced() bool {
+	e.l.RLock()
+	defer e.l.RUnlock()
+
+	for _, s := range e.sinks {
+		if s.enforced {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// OperationClassReadOnly matches operations that cannot change state
+	OperationClassReadOnly = "read-only"
+
+	// OperationClassOperator matches operations that can change state
+	OperationClassOperator = "operator"
+)
+
+// filter is the parsed form of a config.AuditFilter, and describes events
+// that should not be delivered to sinks.
+type filter struct {
+	endpoints  []string
+	stages     map[Stage]struct{}
+	operations map[string]struct{}
+
+	// readOnly and operator match classes of operations rather than
+	// individual verbs
+	readOnly bool
+	operator bool
+
+	// sampleRate is the fraction of matching events still written
+	sampleRate float64
+}
+
+func newFilter(cfg *config.AuditFilter) (*filter, error) {
+	if cfg.Type != HTTPEvent {
+		return nil, fmt.Errorf("audit filter %q: unknown filter type %q", cfg.Name, cfg.Type)
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("audit filter %q: sample rate must be between 0 and 1, got %v", cfg.Name, cfg.SampleRate)
+	}
+
+	f := &filter{
+		endpoints:  cfg.Endpoints,
+		stages:     make(map[Stage]struct{}, len(cfg.Stages)),
+		operations: make(map[string]struct{}, len(cfg.Operations)),
+		sampleRate: cfg.SampleRate,
+	}
+	for _, s := range cfg.Stages {
+		switch stage := Stage(s); stage {
+		case OperationReceived, OperationComplete:
+			f.stages[stage] = struct{}{}
+		default:
+			return nil, fmt.Errorf("audit filter %q: unknown stage %q", cfg.Name, s)
+		}
+	}
+	for _, o := range cfg.Operations {
+		switch strings.ToLower(o) {
+		case OperationClassReadOnly:
+			f.readOnly = true
+		case OperationClassOperator:
+			f.operator = true
+		default:
+			f.operations[strings.ToUpper(o)] = struct{}{}
+		}
+	}
+	return f, nil
+}
+
+// readOnlyOperation returns whether the operation cannot change state
+func readOnlyOperation(op string) bool {
+	switch strings.ToUpper(op) {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	default:
+		return false
+	}
+}
+
+// matches returns true if the event is excluded by the filter. Unset filter
+// fields match any event.
+func (f *filter) matches(event *Event) bool {
+	if len(f.stages) != 0 {
+		if _, ok := f.stages[event.Stage]; !ok {
+			return false
+		}
+	}
+
+	if len(f.operations) != 0 || f.readOnly || f.operator {
+		if event.Request == nil {
+			return false
+		}
+		_, ok := f.operations[strings.ToUpper(event.Request.Operation)]
+		if f.readOnly && readOnlyOperation(event.Request.Operation) {
+			ok = true
+		}
+		if f.operator && !readOnlyOperation(event.Request.Operation) {
+			ok = true
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(f.endpoints) != 0 {
+		if event.Request == nil {
+			return false
+		}
+		match := false
+		for _, pattern := range f.endpoints {
+			if endpointMatches(pattern, event.Request.Endpoint) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sample returns whether a matching event should still be written, based on
+// the filter's sample rate.
+func (f *filter) sample() bool {
+	if f.sampleRate <= 0 {
+		return false
+	}
+	if f.sampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < f.sampleRate
+}
+
+// endpointMatches returns whether the endpoint matches the pattern. A
+// pattern of "*" matches all endpoints, and a trailing "*" matches by
+// prefix.
+func endpointMatches(pattern, endpoint string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(endpoint, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == endpoint
+}
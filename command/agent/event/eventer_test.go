@@ -0,0 +1,333 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(op, endpoint string) *Event {
+	return &Event{
+		ID:        "deadbeef",
+		Stage:     OperationReceived,
+		Type:      HTTPEvent,
+		Timestamp: time.Now().UTC(),
+		Request: &Request{
+			Operation: op,
+			Endpoint:  endpoint,
+		},
+	}
+}
+
+func TestEventer_Event(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	eventer, err := NewEventer(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:   "file",
+				Type:   SinkTypeFile,
+				Format: SinkFormatJSON,
+				Path:   filepath.Join(dir, "audit.log"),
+			},
+		},
+	}, hclog.NewNullLogger())
+	require.NoError(err)
+	require.True(eventer.Enabled())
+	require.False(eventer.DeliveryEnforced())
+
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/jobs")))
+
+	matches, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(matches, 1)
+
+	raw, err := ioutil.ReadFile(matches[0])
+	require.NoError(err)
+
+	var out Event
+	require.NoError(json.Unmarshal(raw, &out))
+	require.Equal(HTTPEvent, out.Type)
+	require.Equal(OperationReceived, out.Stage)
+	require.Equal("GET", out.Request.Operation)
+	require.Equal("/v1/jobs", out.Request.Endpoint)
+
+	// Disabled eventers emit nothing
+	eventer.SetEnabled(false)
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/nodes")))
+	after, err := ioutil.ReadFile(matches[0])
+	require.NoError(err)
+	require.Equal(raw, after)
+}
+
+func TestEventer_Filters(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	eventer, err := NewEventer(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:   "file",
+				Type:   SinkTypeFile,
+				Format: SinkFormatJSON,
+				Path:   filepath.Join(dir, "audit.log"),
+			},
+		},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "health-checks",
+				Type:       HTTPEvent,
+				Endpoints:  []string{"/v1/agent/*", "/v1/status/leader"},
+				Operations: []string{"GET"},
+			},
+		},
+	}, hclog.NewNullLogger())
+	require.NoError(err)
+
+	// Filtered events are not written
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/agent/health")))
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/status/leader")))
+
+	matches, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Empty(matches)
+
+	// A non-matching operation on a filtered endpoint is written
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("POST", "/v1/agent/join")))
+	matches, err = filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(matches, 1)
+}
+
+func TestEventer_OperationClassFilters(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	eventer, err := NewEventer(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:   "file",
+				Type:   SinkTypeFile,
+				Format: SinkFormatJSON,
+				Path:   filepath.Join(dir, "audit.log"),
+			},
+		},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "read-only",
+				Type:       HTTPEvent,
+				Operations: []string{OperationClassReadOnly},
+			},
+		},
+	}, hclog.NewNullLogger())
+	require.NoError(err)
+
+	// Read-only operations are filtered
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/jobs")))
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("HEAD", "/v1/jobs")))
+
+	matches, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Empty(matches)
+
+	// Operator operations are written
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("PUT", "/v1/jobs")))
+	matches, err = filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(matches, 1)
+}
+
+func TestEventer_SampleRate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	eventer, err := NewEventer(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:   "file",
+				Type:   SinkTypeFile,
+				Format: SinkFormatJSON,
+				Path:   filepath.Join(dir, "audit.log"),
+			},
+		},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "sample-health",
+				Type:       HTTPEvent,
+				Endpoints:  []string{"/v1/agent/health"},
+				SampleRate: 1,
+			},
+			{
+				Name:      "drop-metrics",
+				Type:      HTTPEvent,
+				Endpoints: []string{"/v1/metrics"},
+			},
+		},
+	}, hclog.NewNullLogger())
+	require.NoError(err)
+
+	// A sample rate of 1 keeps every matching event
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/agent/health")))
+	matches, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(matches, 1)
+
+	// The default sample rate of 0 drops every matching event
+	before, err := ioutil.ReadFile(matches[0])
+	require.NoError(err)
+	require.NoError(eventer.Event(context.Background(), "audit", testEvent("GET", "/v1/metrics")))
+	after, err := ioutil.ReadFile(matches[0])
+	require.NoError(err)
+	require.Equal(before, after)
+}
+
+func TestEventer_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		cfg  *config.AuditConfig
+		err  string
+	}{
+		{
+			name: "bad sink type",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{
+					{Name: "s", Type: "statsd", Format: SinkFormatJSON, Path: "audit.log"},
+				},
+			},
+			err: "unknown sink type",
+		},
+		{
+			name: "bad sink format",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{
+					{Name: "s", Type: SinkTypeFile, Format: "xml", Path: "audit.log"},
+				},
+			},
+			err: "unknown sink format",
+		},
+		{
+			name: "missing path",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{
+					{Name: "s", Type: SinkTypeFile, Format: SinkFormatJSON},
+				},
+			},
+			err: "path is required",
+		},
+		{
+			name: "bad delivery guarantee",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{
+					{Name: "s", Type: SinkTypeFile, Format: SinkFormatJSON, Path: "audit.log", DeliveryGuarantee: "exactly-once"},
+				},
+			},
+			err: "unknown delivery guarantee",
+		},
+		{
+			name: "bad filter type",
+			cfg: &config.AuditConfig{
+				Filters: []*config.AuditFilter{
+					{Name: "f", Type: "RPCEvent"},
+				},
+			},
+			err: "unknown filter type",
+		},
+		{
+			name: "bad sample rate",
+			cfg: &config.AuditConfig{
+				Filters: []*config.AuditFilter{
+					{Name: "f", Type: HTTPEvent, SampleRate: 1.5},
+				},
+			},
+			err: "sample rate must be between 0 and 1",
+		},
+		{
+			name: "bad filter stage",
+			cfg: &config.AuditConfig{
+				Filters: []*config.AuditFilter{
+					{Name: "f", Type: HTTPEvent, Stages: []string{"OperationStarted"}},
+				},
+			},
+			err: "unknown stage",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewEventer(tc.cfg, hclog.NewNullLogger())
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestEventer_DeliveryEnforced(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	eventer, err := NewEventer(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:              "file",
+				Type:              SinkTypeFile,
+				Format:            SinkFormatJSON,
+				Path:              filepath.Join(dir, "audit.log"),
+				DeliveryGuarantee: EnforcedDelivery,
+			},
+		},
+	}, hclog.NewNullLogger())
+	require.NoError(err)
+	require.True(eventer.DeliveryEnforced())
+
+	// Reloading with a best-effort sink clears enforcement
+	require.NoError(eventer.Reload(&config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:              "file",
+				Type:              SinkTypeFile,
+				Format:            SinkFormatJSON,
+				Path:              filepath.Join(dir, "audit.log"),
+				DeliveryGuarantee: BestEffortDelivery,
+			},
+		},
+	}))
+	require.False(eventer.DeliveryEnforced())
+}
@@ -0,0 +1,190 @@
+package event
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+var (
+	now = time.Now
+)
+
+// fileSink writes audit events to a file, rotating it by time elapsed or
+// bytes written.
+type fileSink struct {
+	// fileName is the name that log files follow. Rotated files are
+	// named name-timestamp.log
+	fileName string
+
+	// logPath is the directory log files are written to
+	logPath string
+
+	// duration is the time period after which the file is rotated
+	duration time.Duration
+
+	// maxBytes is the maximum number of bytes written to a file before
+	// it is rotated
+	maxBytes int
+
+	// maxFiles is the max number of rotated files to keep before
+	// removing them
+	maxFiles int
+
+	// lastCreated is the creation time of the current file
+	lastCreated time.Time
+
+	// f is the current file being written to
+	f *os.File
+
+	// bytesWritten is the number of bytes written to the current file
+	bytesWritten int64
+
+	// l serializes writes and rotation
+	l sync.Mutex
+}
+
+// newFileSink validates the sink config and returns a file sink. The file
+// is not opened until the first write.
+func newFileSink(cfg *config.AuditSink) (*fileSink, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("audit sink %q: path is required", cfg.Name)
+	}
+
+	logPath := filepath.Dir(cfg.Path)
+	if err := os.MkdirAll(logPath, 0700); err != nil {
+		return nil, fmt.Errorf("audit sink %q: failed to create directory %q: %v", cfg.Name, logPath, err)
+	}
+
+	return &fileSink{
+		fileName: filepath.Base(cfg.Path),
+		logPath:  logPath,
+		duration: cfg.RotateDuration,
+		maxBytes: cfg.RotateBytes,
+		maxFiles: cfg.RotateMaxFiles,
+	}, nil
+}
+
+// Write is used to implement io.Writer
+func (s *fileSink) Write(b []byte) (int, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	// Create a new file if we have no file to write to
+	if s.f == nil {
+		if err := s.openNew(); err != nil {
+			return 0, err
+		}
+	}
+
+	// Rotate if necessary
+	if err := s.rotate(); err != nil {
+		return 0, err
+	}
+
+	n, err := s.f.Write(b)
+	s.bytesWritten += int64(n)
+	return n, err
+}
+
+// Reopen closes the current file so the next write opens a new one
+func (s *fileSink) Reopen() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.f != nil {
+		if err := s.f.Close(); err != nil {
+			return err
+		}
+		s.f = nil
+	}
+	return nil
+}
+
+// Close is used to close the current file when the sink is replaced
+func (s *fileSink) Close() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.f != nil {
+		s.f.Close()
+		s.f = nil
+	}
+}
+
+func (s *fileSink) fileNamePattern() string {
+	// Extract the file extension
+	fileExt := filepath.Ext(s.fileName)
+	// If we have no file extension we append .log
+	if fileExt == "" {
+		fileExt = ".log"
+	}
+	// Remove the file extension from the filename
+	return strings.TrimSuffix(s.fileName, fileExt) + "-%s" + fileExt
+}
+
+func (s *fileSink) openNew() error {
+	fileNamePattern := s.fileNamePattern()
+	// New file name has the format : filename-timestamp.extension
+	createTime := now()
+	newfileName := fmt.Sprintf(fileNamePattern, strconv.FormatInt(createTime.UnixNano(), 10))
+	newfilePath := filepath.Join(s.logPath, newfileName)
+	// Try creating a file. We truncate the file because we are the only authority to write the logs
+	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	s.f = filePointer
+	// New file, new bytes tracker, new creation time :)
+	s.lastCreated = createTime
+	s.bytesWritten = 0
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	// Get the time from the last point of contact
+	timeElapsed := time.Since(s.lastCreated)
+	// Rotate if we hit the byte file limit or the time limit
+	if (s.maxBytes > 0 && s.bytesWritten >= int64(s.maxBytes)) || (s.duration > 0 && timeElapsed >= s.duration) {
+		s.f.Close()
+		if err := s.pruneFiles(); err != nil {
+			return err
+		}
+		return s.openNew()
+	}
+	return nil
+}
+
+func (s *fileSink) pruneFiles() error {
+	if s.maxFiles == 0 {
+		return nil
+	}
+	pattern := s.fileNamePattern()
+	// get all the files that match the log file pattern
+	globExpression := filepath.Join(s.logPath, fmt.Sprintf(pattern, "*"))
+	matches, err := filepath.Glob(globExpression)
+	if err != nil {
+		return err
+	}
+
+	// Sort the strings as filepath.Glob does not publicly guarantee that files
+	// are sorted, so here we add an extra defensive sort.
+	sort.Strings(matches)
+
+	// Prune if there are more files stored than the configured max
+	stale := len(matches) - s.maxFiles
+	for i := 0; i < stale; i++ {
+		if err := os.Remove(matches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
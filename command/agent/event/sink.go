@@ -0,0 +1,71 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// sinkWriter is the delivery half of a sink. Implementations are expected
+// to serialize their own writes.
+type sinkWriter interface {
+	// Write delivers one encoded event
+	Write(b []byte) (int, error)
+
+	// Reopen closes any file or connection the sink holds so the next
+	// write reestablishes it
+	Reopen() error
+
+	// Close releases the sink's resources when it is replaced
+	Close()
+}
+
+// sink pairs a writer with its configured delivery guarantee.
+type sink struct {
+	// name is the configured name of the sink
+	name string
+
+	// enforced is true if delivery to the sink must be enforced
+	enforced bool
+
+	w sinkWriter
+}
+
+// newSink validates the common sink config and builds the writer for the
+// configured sink type.
+func newSink(cfg *config.AuditSink) (*sink, error) {
+	if cfg.Format != SinkFormatJSON {
+		return nil, fmt.Errorf("audit sink %q: unknown sink format %q", cfg.Name, cfg.Format)
+	}
+
+	var enforced bool
+	switch cfg.DeliveryGuarantee {
+	case EnforcedDelivery:
+		enforced = true
+	case BestEffortDelivery, "":
+	default:
+		return nil, fmt.Errorf("audit sink %q: unknown delivery guarantee %q", cfg.Name, cfg.DeliveryGuarantee)
+	}
+
+	var w sinkWriter
+	var err error
+	switch cfg.Type {
+	case SinkTypeFile:
+		w, err = newFileSink(cfg)
+	case SinkTypeSyslog:
+		w, err = newSyslogSink(cfg)
+	case SinkTypeTCP:
+		w, err = newTCPSink(cfg)
+	default:
+		return nil, fmt.Errorf("audit sink %q: unknown sink type %q", cfg.Name, cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sink{
+		name:     cfg.Name,
+		enforced: enforced,
+		w:        w,
+	}, nil
+}
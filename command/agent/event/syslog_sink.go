@@ -0,0 +1,44 @@
+package event
+
+import (
+	"fmt"
+
+	gsyslog "github.com/hashicorp/go-syslog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// syslogSink writes audit events to the system syslog daemon.
+type syslogSink struct {
+	l gsyslog.Syslogger
+}
+
+// newSyslogSink opens a connection to the syslog daemon using the
+// configured facility, defaulting to LOCAL0.
+func newSyslogSink(cfg *config.AuditSink) (*syslogSink, error) {
+	facility := cfg.Facility
+	if facility == "" {
+		facility = "LOCAL0"
+	}
+
+	l, err := gsyslog.NewLogger(gsyslog.LOG_NOTICE, facility, "nomad-audit")
+	if err != nil {
+		return nil, fmt.Errorf("audit sink %q: failed to open syslog: %v", cfg.Name, err)
+	}
+	return &syslogSink{l: l}, nil
+}
+
+func (s *syslogSink) Write(b []byte) (int, error) {
+	if err := s.l.WriteLevel(gsyslog.LOG_NOTICE, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Reopen is a no-op as the syslog connection is kept open by the daemon
+func (s *syslogSink) Reopen() error {
+	return nil
+}
+
+func (s *syslogSink) Close() {
+	s.l.Close()
+}
@@ -0,0 +1,147 @@
+package event
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// defaultTCPSinkBuffer is the number of events a tcp sink holds in memory
+// while the remote is unreachable.
+const defaultTCPSinkBuffer = 512
+
+// tcpSink streams audit events to a remote address over TCP, optionally
+// with TLS. Events that cannot be delivered are buffered in memory, with
+// the oldest dropped once the buffer is full, and replayed when the remote
+// becomes reachable again.
+type tcpSink struct {
+	// address is the remote address events are streamed to
+	address string
+
+	// tlsConf is non-nil if the connection uses TLS
+	tlsConf *tls.Config
+
+	// bufferSize is the max number of undelivered events held in memory
+	bufferSize int
+
+	// l serializes writes and reconnects
+	l sync.Mutex
+
+	// conn is the current connection, nil when disconnected
+	conn net.Conn
+
+	// pending holds undelivered events, oldest first
+	pending [][]byte
+}
+
+// newTCPSink validates the sink config and returns a tcp sink. The
+// connection is not established until the first write.
+func newTCPSink(cfg *config.AuditSink) (*tcpSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("audit sink %q: address is required", cfg.Name)
+	}
+
+	var tlsConf *tls.Config
+	if cfg.TLS {
+		tlsConf = &tls.Config{}
+		if cfg.CAFile != "" {
+			pem, err := ioutil.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("audit sink %q: failed to read CA file: %v", cfg.Name, err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("audit sink %q: no certificates found in CA file %q", cfg.Name, cfg.CAFile)
+			}
+			tlsConf.RootCAs = pool
+		}
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultTCPSinkBuffer
+	} else if bufferSize < 0 {
+		return nil, fmt.Errorf("audit sink %q: buffer size must not be negative, got %d", cfg.Name, cfg.BufferSize)
+	}
+
+	return &tcpSink{
+		address:    cfg.Address,
+		tlsConf:    tlsConf,
+		bufferSize: bufferSize,
+	}, nil
+}
+
+func (s *tcpSink) Write(b []byte) (int, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	// Queue the event so delivery order is kept across reconnects,
+	// dropping the oldest once the buffer is full
+	event := make([]byte, len(b))
+	copy(event, b)
+	s.pending = append(s.pending, event)
+	if len(s.pending) > s.bufferSize {
+		s.pending = s.pending[1:]
+	}
+
+	if err := s.flush(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// flush delivers all pending events, connecting first if necessary. The
+// connection is dropped on error so the next write reconnects.
+func (s *tcpSink) flush() error {
+	if s.conn == nil {
+		var conn net.Conn
+		var err error
+		if s.tlsConf != nil {
+			conn, err = tls.Dial("tcp", s.address, s.tlsConf)
+		} else {
+			conn, err = net.Dial("tcp", s.address)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to connect to %q: %v", s.address, err)
+		}
+		s.conn = conn
+	}
+
+	for len(s.pending) > 0 {
+		if _, err := s.conn.Write(s.pending[0]); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+		s.pending = s.pending[1:]
+	}
+	return nil
+}
+
+// Reopen drops the connection so the next write reconnects
+func (s *tcpSink) Reopen() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.conn != nil {
+		err := s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *tcpSink) Close() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
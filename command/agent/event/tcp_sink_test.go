@@ -0,0 +1,113 @@
+package event
+
+import (
+	"bufio"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPSink(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer ln.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink, err := newTCPSink(&config.AuditSink{
+		Name:    "tcp",
+		Address: ln.Addr().String(),
+	})
+	require.NoError(err)
+	defer sink.Close()
+
+	_, err = sink.Write([]byte("one\n"))
+	require.NoError(err)
+	_, err = sink.Write([]byte("two\n"))
+	require.NoError(err)
+
+	require.Equal("one", <-lines)
+	require.Equal("two", <-lines)
+}
+
+func TestTCPSink_Buffering(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Reserve an address with no listener yet
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	address := ln.Addr().String()
+	ln.Close()
+
+	sink, err := newTCPSink(&config.AuditSink{
+		Name:       "tcp",
+		Address:    address,
+		BufferSize: 2,
+	})
+	require.NoError(err)
+	defer sink.Close()
+
+	// Writes fail while the remote is down but events are buffered,
+	// dropping the oldest once the buffer is full
+	for _, event := range []string{"one\n", "two\n", "three\n"} {
+		_, err = sink.Write([]byte(event))
+		require.Error(err)
+	}
+	require.Len(sink.pending, 2)
+
+	// Once the remote is reachable buffered events are replayed in order
+	ln, err = net.Listen("tcp", address)
+	require.NoError(err)
+	defer ln.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	// Writing "four" exceeds the buffer again, dropping "two"
+	_, err = sink.Write([]byte("four\n"))
+	require.NoError(err)
+
+	require.Equal("three", <-lines)
+	require.Equal("four", <-lines)
+}
+
+func TestTCPSink_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTCPSink(&config.AuditSink{Name: "tcp"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "address is required")
+
+	_, err = newTCPSink(&config.AuditSink{Name: "tcp", Address: "127.0.0.1:4646", BufferSize: -1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "buffer size must not be negative")
+
+	_, err = newTCPSink(&config.AuditSink{Name: "tcp", Address: "127.0.0.1:4646", TLS: true, CAFile: "does-not-exist.pem"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read CA file")
+}
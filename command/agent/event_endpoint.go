@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/hashicorp/go-msgpack/codec"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventStream streams state change events published by the server event
+// broker. Subscriptions are expressed with one or more "topic" query
+// parameters of the form Topic or Topic:Key, and may be resumed from a
+// raft index with the "index" query parameter.
+func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	query := req.URL.Query()
+
+	index := uint64(0)
+	if indexStr := query.Get("index"); indexStr != "" {
+		parsed, err := strconv.ParseUint(indexStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Unable to parse index: %v", err))
+		}
+		index = parsed
+	}
+
+	topics, err := parseEventTopics(query["topic"])
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Invalid topic query: %v", err))
+	}
+
+	args := &structs.EventStreamRequest{
+		Topics: topics,
+		Index:  index,
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-cache")
+
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Make the RPC
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if server := s.agent.Server(); server != nil {
+		handler, handlerErr = server.StreamingRpcHandler("Event.Stream")
+	} else if client := s.agent.Client(); client != nil {
+		handler, handlerErr = client.RemoteStreamingRpcHandler("Event.Stream")
+	} else {
+		handlerErr = fmt.Errorf("misconfigured connection")
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	// Create an output that gets flushed on every write
+	output := ioutils.NewWriteFlusher(resp)
+
+	// create an error channel to handle errors
+	errCh := make(chan HTTPCodedError, 2)
+
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				errCh <- nil
+				return
+			default:
+			}
+
+			// Decode the response
+			var res cstructs.StreamErrWrapper
+			if err := decoder.Decode(&res); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+			decoder.Reset(httpPipe)
+
+			if err := res.Error; err != nil {
+				if err.Code != nil {
+					errCh <- CodedError(int(*err.Code), err.Error())
+					return
+				}
+			}
+
+			// Flush json entry to response
+			if _, err := io.Copy(output, bytes.NewReader(res.Payload)); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+		}
+	}()
+
+	// invoke handler
+	handler(handlerPipe)
+	cancel()
+	codedErr := <-errCh
+
+	if codedErr != nil &&
+		(codedErr == io.EOF ||
+			strings.Contains(codedErr.Error(), "closed") ||
+			strings.Contains(codedErr.Error(), "EOF")) {
+		codedErr = nil
+	}
+
+	return nil, codedErr
+}
+
+// parseEventTopics parses a set of topic query parameters of the form
+// Topic or Topic:Key into the topic map of a subscription request. With no
+// topics specified the subscription matches everything.
+func parseEventTopics(topicList []string) (map[structs.Topic][]string, error) {
+	topics := make(map[structs.Topic][]string)
+	if len(topicList) == 0 {
+		topics[structs.TopicAll] = []string{"*"}
+		return topics, nil
+	}
+
+	for _, topic := range topicList {
+		parts := strings.SplitN(topic, ":", 2)
+		key := "*"
+		if len(parts) == 2 {
+			if parts[1] == "" {
+				return nil, fmt.Errorf("topic %q has an empty key", topic)
+			}
+			key = parts[1]
+		}
+		if parts[0] == "" {
+			return nil, fmt.Errorf("topic %q has an empty topic name", topic)
+		}
+		topics[structs.Topic(parts[0])] = append(topics[structs.Topic(parts[0])], key)
+	}
+	return topics, nil
+}
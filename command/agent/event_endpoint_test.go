@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream_ParseEventTopics(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		query    []string
+		expected map[structs.Topic][]string
+		err      string
+	}{
+		{
+			name:  "defaults to all topics",
+			query: nil,
+			expected: map[structs.Topic][]string{
+				structs.TopicAll: {"*"},
+			},
+		},
+		{
+			name:  "topic without key matches every key",
+			query: []string{"Job"},
+			expected: map[structs.Topic][]string{
+				structs.TopicJob: {"*"},
+			},
+		},
+		{
+			name:  "topics with keys",
+			query: []string{"Job:example", "Job:other", "Node"},
+			expected: map[structs.Topic][]string{
+				structs.TopicJob:  {"example", "other"},
+				structs.TopicNode: {"*"},
+			},
+		},
+		{
+			name:  "empty key",
+			query: []string{"Job:"},
+			err:   "empty key",
+		},
+		{
+			name:  "empty topic",
+			query: []string{":example"},
+			err:   "empty topic name",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			topics, err := parseEventTopics(tc.query)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, topics)
+		})
+	}
+}
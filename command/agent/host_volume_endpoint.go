@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientHostVolumesRequest handles /v1/client/host-volumes requests, listing,
+// registering, and unregistering host volumes on the local client at runtime
+// rather than requiring static config and an agent restart.
+func (s *HTTPServer) ClientHostVolumesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.agent.Client() == nil {
+		return nil, clientNotRunning
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	aclObj, err := s.agent.Client().ResolveToken(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Method {
+	case "GET":
+		if aclObj != nil && !aclObj.AllowNodeRead() {
+			return nil, structs.ErrPermissionDenied
+		}
+		return s.agent.Client().HostVolumes(), nil
+
+	case "PUT", "POST":
+		if aclObj != nil && !aclObj.AllowNodeWrite() {
+			return nil, structs.ErrPermissionDenied
+		}
+
+		var volumes []*structs.ClientHostVolumeConfig
+		if err := decodeBody(req, &volumes); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+		if len(volumes) == 0 {
+			return nil, CodedError(400, "missing host volumes")
+		}
+
+		if err := s.agent.Client().RegisterHostVolumes(volumes); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+		return nil, nil
+
+	case "DELETE":
+		if aclObj != nil && !aclObj.AllowNodeWrite() {
+			return nil, structs.ErrPermissionDenied
+		}
+
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			return nil, CodedError(400, "missing host volume name")
+		}
+
+		if err := s.agent.Client().UnregisterHostVolume(name); err != nil {
+			return nil, CodedError(404, err.Error())
+		}
+		return nil, nil
+
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
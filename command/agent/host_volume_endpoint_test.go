@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ClientHostVolumes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register a host volume at runtime
+		volumes := []*structs.ClientHostVolumeConfig{
+			{Name: "shared_data", Path: "/tmp/shared_data"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/client/host-volumes", encodeReq(volumes))
+		require.NoError(err)
+
+		respW := httptest.NewRecorder()
+		_, err = s.Server.ClientHostVolumesRequest(respW, req)
+		require.NoError(err)
+
+		// The volume is listed
+		req, err = http.NewRequest("GET", "/v1/client/host-volumes", nil)
+		require.NoError(err)
+
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.ClientHostVolumesRequest(respW, req)
+		require.NoError(err)
+
+		out := obj.(map[string]*structs.ClientHostVolumeConfig)
+		require.Len(out, 1)
+		require.Equal("/tmp/shared_data", out["shared_data"].Path)
+
+		// A volume without a path is rejected
+		req, err = http.NewRequest("PUT", "/v1/client/host-volumes",
+			encodeReq([]*structs.ClientHostVolumeConfig{{Name: "bad"}}))
+		require.NoError(err)
+
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientHostVolumesRequest(respW, req)
+		require.Error(err)
+
+		// Unregister the volume
+		req, err = http.NewRequest("DELETE", "/v1/client/host-volumes?name=shared_data", nil)
+		require.NoError(err)
+
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientHostVolumesRequest(respW, req)
+		require.NoError(err)
+
+		// Unknown volumes cannot be unregistered
+		req, err = http.NewRequest("DELETE", "/v1/client/host-volumes?name=shared_data", nil)
+		require.NoError(err)
+
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientHostVolumesRequest(respW, req)
+		require.Error(err)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -155,6 +156,10 @@ func NewHTTPServer(agent *Agent, config *Config) (*HTTPServer, error) {
 		httpServer.Serve(ln)
 	}()
 
+	// Make the handler available to the task API unix sockets created inside
+	// allocations
+	agent.setTaskAPIServer(srv)
+
 	return srv, nil
 }
 
@@ -250,17 +255,28 @@ func (s *HTTPServer) Shutdown() {
 
 // registerHandlers is used to attach our handlers to the mux
 func (s *HTTPServer) registerHandlers(enableDebug bool) {
-	s.mux.HandleFunc("/v1/jobs", s.wrap(s.JobsRequest))
+	s.mux.HandleFunc("/v1/jobs", s.wrapETag(s.wrap(s.JobsRequest)))
 	s.mux.HandleFunc("/v1/jobs/parse", s.wrap(s.JobsParseRequest))
 	s.mux.HandleFunc("/v1/job/", s.wrap(s.JobSpecificRequest))
 
-	s.mux.HandleFunc("/v1/nodes", s.wrap(s.NodesRequest))
+	s.mux.HandleFunc("/v1/nodes", s.wrapETag(s.wrap(s.NodesRequest)))
+	s.mux.HandleFunc("/v1/nodes/inventory", s.wrap(s.NodeInventoryRequest))
+	s.mux.HandleFunc("/v1/nodes/drain", s.wrap(s.NodesBatchDrainRequest))
+	s.mux.HandleFunc("/v1/nodes/eligibility", s.wrap(s.NodesBatchEligibilityRequest))
+	s.mux.HandleFunc("/v1/nodes/meta", s.wrap(s.NodesBatchMetaRequest))
+
+	s.mux.HandleFunc("/v1/services", s.wrap(s.ServicesRequest))
+	s.mux.HandleFunc("/v1/service/", s.wrap(s.ServiceSpecificRequest))
+
+	s.mux.HandleFunc("/v1/vars", s.wrap(s.VariablesRequest))
+	s.mux.HandleFunc("/v1/var/", s.wrap(s.VariableSpecificRequest))
 	s.mux.HandleFunc("/v1/node/", s.wrap(s.NodeSpecificRequest))
 
 	s.mux.HandleFunc("/v1/allocations", s.wrap(s.AllocsRequest))
 	s.mux.HandleFunc("/v1/allocation/", s.wrap(s.AllocSpecificRequest))
 
 	s.mux.HandleFunc("/v1/evaluations", s.wrap(s.EvalsRequest))
+	s.mux.HandleFunc("/v1/evaluations/broker", s.wrap(s.EvalBrokerStatsRequest))
 	s.mux.HandleFunc("/v1/evaluation/", s.wrap(s.EvalSpecificRequest))
 
 	s.mux.HandleFunc("/v1/deployments", s.wrap(s.DeploymentsRequest))
@@ -271,8 +287,29 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/plugins", s.wrap(s.CSIPluginsRequest))
 	s.mux.HandleFunc("/v1/plugin/csi/", s.wrap(s.CSIPluginSpecificRequest))
 
+	s.mux.HandleFunc("/v1/namespaces", s.wrap(s.NamespacesRequest))
+	s.mux.HandleFunc("/v1/namespace", s.wrap(s.NamespaceCreateRequest))
+	s.mux.HandleFunc("/v1/namespace/", s.wrap(s.NamespaceSpecificRequest))
+
 	s.mux.HandleFunc("/v1/acl/policies", s.wrap(s.ACLPoliciesRequest))
 	s.mux.HandleFunc("/v1/acl/policy/", s.wrap(s.ACLPolicySpecificRequest))
+	s.mux.HandleFunc("/v1/acl/roles", s.wrap(s.ACLRolesRequest))
+	s.mux.HandleFunc("/v1/acl/role/", s.wrap(s.ACLRoleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/auth-methods", s.wrap(s.ACLAuthMethodsRequest))
+	s.mux.HandleFunc("/v1/acl/auth-method/", s.wrap(s.ACLAuthMethodSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/binding-rules", s.wrap(s.ACLBindingRulesRequest))
+	s.mux.HandleFunc("/v1/acl/binding-rule/", s.wrap(s.ACLBindingRuleSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/login", s.wrap(s.ACLLoginRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/auth-url", s.wrap(s.ACLOIDCAuthURLRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/complete-auth", s.wrap(s.ACLOIDCCompleteAuthRequest))
+	s.mux.HandleFunc("/v1/acl/check", s.wrap(s.ACLCheckRequest))
+
+	s.mux.HandleFunc("/v1/sentinel/policies", s.wrap(s.SentinelPoliciesRequest))
+	s.mux.HandleFunc("/v1/sentinel/policy/", s.wrap(s.SentinelPolicySpecificRequest))
+	s.mux.HandleFunc("/v1/sentinel/modules", s.wrap(s.SentinelModulesRequest))
+	s.mux.HandleFunc("/v1/sentinel/module/", s.wrap(s.SentinelModuleSpecificRequest))
+	s.mux.HandleFunc("/v1/sentinel/test", s.wrap(s.SentinelPolicyTestRequest))
+	s.mux.HandleFunc("/v1/sentinel/overrides", s.wrap(s.SentinelOverridesRequest))
 
 	s.mux.HandleFunc("/v1/acl/bootstrap", s.wrap(s.ACLTokenBootstrap))
 	s.mux.HandleFunc("/v1/acl/tokens", s.wrap(s.ACLTokensRequest))
@@ -281,6 +318,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
 	s.mux.HandleFunc("/v1/client/gc", s.wrap(s.ClientGCRequest))
+	s.mux.HandleFunc("/v1/client/host-volumes", s.wrap(s.ClientHostVolumesRequest))
 	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
 	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
 
@@ -302,7 +340,9 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.HandleFunc("/v1/agent/pprof/", s.wrapNonJSON(s.AgentPprofRequest))
 
-	s.mux.HandleFunc("/v1/metrics", s.wrap(s.MetricsRequest))
+	s.mux.HandleFunc("/v1/metrics", s.wrapETag(s.wrap(s.MetricsRequest)))
+
+	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
 
 	s.mux.HandleFunc("/v1/validate/job", s.wrap(s.ValidateJobRequest))
 
@@ -315,6 +355,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/status/peers", s.wrap(s.StatusPeersRequest))
 
 	s.mux.HandleFunc("/v1/search", s.wrap(s.SearchRequest))
+	s.mux.HandleFunc("/v1/search/resolve", s.wrap(s.ResolveIdentifierRequest))
 
 	s.mux.HandleFunc("/v1/operator/raft/", s.wrap(s.OperatorRequest))
 	s.mux.HandleFunc("/v1/operator/autopilot/configuration", s.wrap(s.OperatorAutopilotConfiguration))
@@ -325,6 +366,18 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
 
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
+	s.mux.HandleFunc("/v1/operator/keyring", s.wrap(s.OperatorKeyringRequest))
+	s.mux.HandleFunc("/v1/operator/keyring/rotate", s.wrap(s.OperatorKeyringRotateRequest))
+	s.mux.HandleFunc("/v1/operator/keyring/", s.wrap(s.OperatorKeyringSpecificRequest))
+	s.mux.HandleFunc("/v1/operator/license", s.wrap(s.OperatorLicenseRequest))
+	s.mux.HandleFunc("/v1/operator/license/history", s.wrap(s.OperatorLicenseHistoryRequest))
+	s.mux.HandleFunc("/v1/operator/license/report", s.wrap(s.OperatorLicenseReportRequest))
+	s.mux.HandleFunc("/v1/operator/license/preview", s.wrap(s.OperatorLicensePreviewRequest))
+	s.mux.HandleFunc("/v1/operator/features", s.wrap(s.OperatorFeatureFlagsRequest))
+	s.mux.HandleFunc("/v1/operator/freeze-windows", s.wrap(s.OperatorFreezeWindowsRequest))
+	s.mux.HandleFunc("/v1/operator/headroom", s.wrap(s.OperatorClusterHeadroom))
+	s.mux.HandleFunc("/v1/operator/metrics/history", s.wrap(s.OperatorMetricsHistoryRequest))
+	s.mux.HandleFunc("/v1/operator/vault/status", s.wrap(s.OperatorVaultStatusRequest))
 
 	if uiEnabled {
 		s.mux.Handle("/ui/", http.StripPrefix("/ui/", s.handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()}))))
@@ -508,6 +561,84 @@ func (s *HTTPServer) wrap(handler func(resp http.ResponseWriter, req *http.Reque
 	return f
 }
 
+// etagResponseWriter buffers a response so that an ETag can be computed over
+// the body before any bytes are written to the client
+type etagResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newEtagResponseWriter() *etagResponseWriter {
+	return &etagResponseWriter{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+}
+
+func (e *etagResponseWriter) Header() http.Header {
+	return e.header
+}
+
+func (e *etagResponseWriter) Write(p []byte) (int, error) {
+	return e.body.Write(p)
+}
+
+func (e *etagResponseWriter) WriteHeader(status int) {
+	e.status = status
+}
+
+// wrapETag wraps an already wrapped handler with ETag generation and
+// If-None-Match handling for GET requests. This allows clients polling the
+// larger list endpoints to skip re-downloading an unchanged body, which
+// matters for UI-heavy usage over WAN links. Responses are buffered so the
+// hash covers the exact bytes that would be sent; when the client presents a
+// matching tag we reply 304 with the Nomad index headers intact.
+func (s *HTTPServer) wrapETag(handler func(resp http.ResponseWriter, req *http.Request)) func(resp http.ResponseWriter, req *http.Request) {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		// Only GET responses are cacheable
+		if req.Method != "GET" {
+			handler(resp, req)
+			return
+		}
+
+		// Buffer the response to hash the body
+		rec := newEtagResponseWriter()
+		handler(rec, req)
+
+		// Copy the recorded headers through unconditionally so blocking
+		// query metadata survives a 304
+		headers := resp.Header()
+		for key, values := range rec.header {
+			headers[key] = values
+		}
+
+		// Only tag successful responses with a body
+		if rec.status != http.StatusOK || rec.body.Len() == 0 {
+			resp.WriteHeader(rec.status)
+			resp.Write(rec.body.Bytes())
+			return
+		}
+
+		hash := fnv.New64a()
+		hash.Write(rec.body.Bytes())
+		etag := fmt.Sprintf("%q", strconv.FormatUint(hash.Sum64(), 16))
+		headers.Set("ETag", etag)
+
+		if match := req.Header.Get("If-None-Match"); match != "" {
+			for _, candidate := range strings.Split(match, ",") {
+				if strings.TrimSpace(candidate) == etag {
+					resp.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+		}
+
+		resp.WriteHeader(rec.status)
+		resp.Write(rec.body.Bytes())
+	}
+}
+
 // wrapNonJSON is used to wrap functions returning non JSON
 // serializeable data to make them more convenient. It is primarily
 // responsible for setting nomad headers and logging.
@@ -565,6 +696,13 @@ func setIndex(resp http.ResponseWriter, index uint64) {
 	resp.Header().Set("X-Nomad-Index", strconv.FormatUint(index, 10))
 }
 
+// setNextToken is used to set the next token header for paged list responses
+func setNextToken(resp http.ResponseWriter, nextToken string) {
+	if nextToken != "" {
+		resp.Header().Set("X-Nomad-NextToken", nextToken)
+	}
+}
+
 // setKnownLeader is used to set the known leader header
 func setKnownLeader(resp http.ResponseWriter, known bool) {
 	s := "true"
@@ -585,6 +723,7 @@ func setMeta(resp http.ResponseWriter, m *structs.QueryMeta) {
 	setIndex(resp, m.Index)
 	setLastContact(resp, m.LastContact)
 	setKnownLeader(resp, m.KnownLeader)
+	setNextToken(resp, m.NextToken)
 }
 
 // setHeaders is used to set canonical response header fields
@@ -635,6 +774,26 @@ func parsePrefix(req *http.Request, b *structs.QueryOptions) {
 	}
 }
 
+// parsePagination is used to parse the ?filter, ?per_page and ?next_token
+// query params used by paged list endpoints
+func parsePagination(resp http.ResponseWriter, req *http.Request, b *structs.QueryOptions) bool {
+	query := req.URL.Query()
+	b.Filter = query.Get("filter")
+	b.NextToken = query.Get("next_token")
+
+	if perPage := query.Get("per_page"); perPage != "" {
+		n, err := strconv.ParseInt(perPage, 10, 32)
+		if err != nil || n < 0 {
+			resp.WriteHeader(400)
+			resp.Write([]byte("Invalid per_page value"))
+			return true
+		}
+		b.PerPage = int32(n)
+	}
+
+	return false
+}
+
 // parseRegion is used to parse the ?region query param
 func (s *HTTPServer) parseRegion(req *http.Request, r *string) {
 	if other := req.URL.Query().Get("region"); other != "" {
@@ -656,11 +815,48 @@ func parseNamespace(req *http.Request, n *string) {
 // parseToken is used to parse the X-Nomad-Token param
 func (s *HTTPServer) parseToken(req *http.Request, token *string) {
 	if other := req.Header.Get("X-Nomad-Token"); other != "" {
-		*token = other
-		return
+		// TLS identity tokens are only ever derived from the connection's
+		// verified certificate; a caller-supplied token using the reserved
+		// prefix is forged and is ignored.
+		if !strings.HasPrefix(other, structs.ACLTLSIdentityPrefix) {
+			*token = other
+			return
+		}
+	}
+
+	// Fall back to the verified TLS client certificate identity if the agent
+	// has an ACL policy mapping configured for it.
+	if identity := s.tlsCertificateIdentity(req); identity != "" {
+		*token = structs.ACLTLSIdentityPrefix + identity
 	}
 }
 
+// tlsCertificateIdentity returns the first identity presented by the
+// request's verified TLS client certificate that has an ACL policy mapping
+// configured, or the empty string if there is no match. The certificate
+// common name, DNS SANs, and organizational units are checked in that order.
+func (s *HTTPServer) tlsCertificateIdentity(req *http.Request) string {
+	mapping := s.agent.config.ACL.TLSIdentityPolicies
+	if len(mapping) == 0 || req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+
+	cert := req.TLS.PeerCertificates[0]
+	candidates := make([]string, 0, 1+len(cert.DNSNames)+len(cert.Subject.OrganizationalUnit))
+	if cert.Subject.CommonName != "" {
+		candidates = append(candidates, cert.Subject.CommonName)
+	}
+	candidates = append(candidates, cert.DNSNames...)
+	candidates = append(candidates, cert.Subject.OrganizationalUnit...)
+
+	for _, identity := range candidates {
+		if _, ok := mapping[identity]; ok {
+			return identity
+		}
+	}
+	return ""
+}
+
 // parse is a convenience method for endpoints that need to parse multiple flags
 // It sets r to the region and b to the QueryOptions in req
 func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *string, b *structs.QueryOptions) bool {
@@ -669,6 +865,9 @@ func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *strin
 	parseConsistency(req, b)
 	parsePrefix(req, b)
 	parseNamespace(req, &b.Namespace)
+	if parsePagination(resp, req, b) {
+		return true
+	}
 	return parseWait(resp, req, b)
 }
 
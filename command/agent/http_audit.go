@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+// auditEnabled returns whether audit events should be emitted for requests
+// to this agent. Audit logging is gated on the audit-logging license
+// feature; the check is made against the local server when one is running,
+// as client-only agents have no view of the cluster license.
+func (s *HTTPServer) auditEnabled() bool {
+	if !s.agent.auditor.Enabled() {
+		return false
+	}
+	if srv := s.agent.Server(); srv != nil {
+		if err := srv.LicenseWatcher().FeatureCheck("audit-logging", false); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// auditHandler wraps the passed handlerFn, emitting audit events for the
+// request before and after it is processed.
+func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		e := s.auditEvent(req)
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			return nil, err
+		}
+
+		obj, rErr := h(resp, req)
+
+		s.completeAudit(e, rErr)
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			return nil, err
+		}
+		return obj, rErr
+	}
+}
+
+// auditNonJSONHandler wraps the passed handlerByteFn, emitting audit events
+// for the request before and after it is processed.
+func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		e := s.auditEvent(req)
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			return nil, err
+		}
+
+		obj, rErr := h(resp, req)
+
+		s.completeAudit(e, rErr)
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			return nil, err
+		}
+		return obj, rErr
+	}
+}
+
+// auditHTTPHandler wraps the passed http.Handler, emitting audit events for
+// the request before and after it is processed.
+func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !s.auditEnabled() {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		e := s.auditEvent(req)
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		rec := &auditResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+		h.ServeHTTP(rec, req)
+
+		e.Stage = event.OperationComplete
+		e.Timestamp = time.Now().UTC()
+		e.Response = &event.Response{StatusCode: rec.status}
+		if err := s.emitAudit(req.Context(), e); err != nil {
+			s.logger.Error("failed to emit audit event", "error", err)
+		}
+	})
+}
+
+// auditEvent returns the OperationReceived audit event for a request
+func (s *HTTPServer) auditEvent(req *http.Request) *event.Event {
+	return &event.Event{
+		ID:        uuid.Generate(),
+		Stage:     event.OperationReceived,
+		Type:      event.HTTPEvent,
+		Timestamp: time.Now().UTC(),
+		Auth:      s.auditAuth(req),
+		Request: &event.Request{
+			Operation:     req.Method,
+			Endpoint:      req.URL.Path,
+			RemoteAddress: req.RemoteAddr,
+			UserAgent:     req.UserAgent(),
+		},
+	}
+}
+
+// completeAudit updates the event for the OperationComplete stage with the
+// outcome of the request
+func (s *HTTPServer) completeAudit(e *event.Event, handlerErr error) {
+	e.Stage = event.OperationComplete
+	e.Timestamp = time.Now().UTC()
+
+	code := http.StatusOK
+	var errMsg string
+	if handlerErr != nil {
+		code, errMsg = errCodeFromHandler(handlerErr)
+	}
+	e.Response = &event.Response{
+		StatusCode: code,
+		Error:      errMsg,
+	}
+}
+
+// auditAuth resolves the request's ACL token to describe who made the
+// request. Client-only agents cannot resolve the accessor locally, and an
+// unresolvable token is elided rather than failing the request; ACL
+// enforcement itself happens in the RPC handlers.
+func (s *HTTPServer) auditAuth(req *http.Request) *event.Auth {
+	var secret string
+	s.parseToken(req, &secret)
+	if secret == "" {
+		return nil
+	}
+
+	srv := s.agent.Server()
+	if srv == nil {
+		return nil
+	}
+
+	token, err := srv.State().ACLTokenBySecretID(nil, secret)
+	if err != nil || token == nil {
+		return nil
+	}
+	return &event.Auth{
+		AccessorID: token.AccessorID,
+		Name:       token.Name,
+		Global:     token.Global,
+		CreateTime: token.CreateTime,
+	}
+}
+
+// emitAudit sends the event to the auditor. Delivery failures fail the
+// request if any sink enforces delivery, and are logged otherwise.
+func (s *HTTPServer) emitAudit(ctx context.Context, e *event.Event) error {
+	if err := s.agent.auditor.Event(ctx, "audit", e); err != nil {
+		if s.agent.auditor.DeliveryEnforced() {
+			return err
+		}
+		s.logger.Error("failed to emit audit event", "error", err)
+	}
+	return nil
+}
+
+// auditResponseWriter captures the status code written by the wrapped
+// handler
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
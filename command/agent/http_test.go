@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -223,6 +224,53 @@ func TestContentTypeIsJSON(t *testing.T) {
 	}
 }
 
+func TestWrapETag(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		return &structs.Job{Name: "foo"}, nil
+	}
+	wrapped := s.Server.wrapETag(s.Server.wrap(handler))
+
+	// A plain GET is tagged
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/jobs", nil)
+	wrapped(resp, req)
+
+	etag := resp.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected an ETag header")
+	}
+	if resp.Code != 200 || resp.Body.Len() == 0 {
+		t.Fatalf("expected a 200 with a body, got %d", resp.Code)
+	}
+
+	// Presenting the tag yields a 304 without a body
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/jobs", nil)
+	req.Header.Set("If-None-Match", etag)
+	wrapped(resp, req)
+
+	if resp.Code != 304 {
+		t.Fatalf("expected a 304, got %d", resp.Code)
+	}
+	if resp.Body.Len() != 0 {
+		t.Fatalf("expected an empty body, got %q", resp.Body.String())
+	}
+
+	// A stale tag yields the full response
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/jobs", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	wrapped(resp, req)
+
+	if resp.Code != 200 || resp.Body.Len() == 0 {
+		t.Fatalf("expected a 200 with a body, got %d", resp.Code)
+	}
+}
+
 func TestWrapNonJSON(t *testing.T) {
 	t.Parallel()
 	s := makeHTTPServer(t, nil)
@@ -515,6 +563,60 @@ func TestParseToken(t *testing.T) {
 	}
 }
 
+func TestParseToken_TLSIdentity(t *testing.T) {
+	t.Parallel()
+	s := makeHTTPServer(t, func(c *Config) {
+		c.ACL.TLSIdentityPolicies = map[string][]string{
+			"cli.global.nomad": {"readonly"},
+		}
+	})
+	defer s.Shutdown()
+
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "unmapped.global.nomad"},
+		DNSNames: []string{"cli.global.nomad"},
+	}
+	req, err := http.NewRequest("GET", "/v1/jobs", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+
+	// The mapped certificate identity is used when no token is given
+	var token string
+	s.Server.parseToken(req, &token)
+	if token != structs.ACLTLSIdentityPrefix+"cli.global.nomad" {
+		t.Fatalf("bad %s", token)
+	}
+
+	// An explicit token takes precedence over the certificate
+	req.Header.Set("X-Nomad-Token", "foobar")
+	token = ""
+	s.Server.parseToken(req, &token)
+	if token != "foobar" {
+		t.Fatalf("bad %s", token)
+	}
+
+	// A forged token bearing the reserved prefix is ignored and the verified
+	// certificate identity is used instead
+	req.Header.Set("X-Nomad-Token", structs.ACLTLSIdentityPrefix+"other.global.nomad")
+	token = ""
+	s.Server.parseToken(req, &token)
+	if token != structs.ACLTLSIdentityPrefix+"cli.global.nomad" {
+		t.Fatalf("bad %s", token)
+	}
+
+	// Without a verified certificate a forged token yields no token at all
+	req.TLS = nil
+	token = ""
+	s.Server.parseToken(req, &token)
+	if token != "" {
+		t.Fatalf("bad %s", token)
+	}
+}
+
 // TestHTTP_VerifyHTTPSClient asserts that a client certificate signed by the
 // appropriate CA is required when VerifyHTTPSClient=true.
 func TestHTTP_VerifyHTTPSClient(t *testing.T) {
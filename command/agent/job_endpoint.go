@@ -55,9 +55,15 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/evaluations"):
 		jobName := strings.TrimSuffix(path, "/evaluations")
 		return s.jobEvaluations(resp, req, jobName)
+	case strings.HasSuffix(path, "/lineage"):
+		jobName := strings.TrimSuffix(path, "/lineage")
+		return s.jobEvalLineage(resp, req, jobName)
 	case strings.HasSuffix(path, "/periodic/force"):
 		jobName := strings.TrimSuffix(path, "/periodic/force")
 		return s.periodicForceRequest(resp, req, jobName)
+	case strings.HasSuffix(path, "/periodic/stats"):
+		jobName := strings.TrimSuffix(path, "/periodic/stats")
+		return s.periodicLaunchStatsRequest(resp, req, jobName)
 	case strings.HasSuffix(path, "/plan"):
 		jobName := strings.TrimSuffix(path, "/plan")
 		return s.jobPlan(resp, req, jobName)
@@ -70,6 +76,12 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
+	case strings.HasSuffix(path, "/diff"):
+		jobName := strings.TrimSuffix(path, "/diff")
+		return s.jobDiff(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -85,6 +97,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/scale"):
 		jobName := strings.TrimSuffix(path, "/scale")
 		return s.jobScale(resp, req, jobName)
+	case strings.HasSuffix(path, "/suspend"):
+		jobName := strings.TrimSuffix(path, "/suspend")
+		return s.jobSuspend(resp, req, jobName)
 	default:
 		return s.jobCRUD(resp, req, path)
 	}
@@ -213,6 +228,27 @@ func (s *HTTPServer) periodicForceRequest(resp http.ResponseWriter, req *http.Re
 	return out, nil
 }
 
+func (s *HTTPServer) periodicLaunchStatsRequest(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.PeriodicLaunchStatsRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.PeriodicLaunchStatsResponse
+	if err := s.agent.RPC("Periodic.LaunchStats", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobAllocations(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -267,6 +303,33 @@ func (s *HTTPServer) jobEvaluations(resp http.ResponseWriter, req *http.Request,
 	return out.Evaluations, nil
 }
 
+func (s *HTTPServer) jobEvalLineage(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.EvalLineageRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.EvalLineageResponse
+	if err := s.agent.RPC("Eval.Lineage", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Evaluations == nil {
+		out.Evaluations = make([]*structs.Evaluation, 0)
+	}
+	if out.Links == nil {
+		out.Links = make([]*structs.EvalLineageLink, 0)
+	}
+	return out, nil
+}
+
 func (s *HTTPServer) jobDeployments(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -397,6 +460,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		JobModifyIndex: args.JobModifyIndex,
 		PolicyOverride: args.PolicyOverride,
 		PreserveCounts: args.PreserveCounts,
+		Submission:     apiJobSubmissionToStructs(args.Submission),
 		WriteRequest:   *writeReq,
 	}
 
@@ -556,6 +620,83 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	versionStr := req.URL.Query().Get("version")
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err)
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job submission not found")
+	}
+
+	return out.Submission, nil
+}
+
+func (s *HTTPServer) jobDiff(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobDiffRequest{
+		JobID:         jobName,
+		CompareRegion: req.URL.Query().Get("compare_region"),
+	}
+
+	parseVersion := func(param string) (*uint64, error) {
+		str := req.URL.Query().Get(param)
+		if str == "" {
+			return nil, nil
+		}
+		version, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse value of %q (%v) as a uint: %v", param, str, err)
+		}
+		return &version, nil
+	}
+
+	var err error
+	if args.FromVersion, err = parseVersion("from_version"); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if args.ToVersion, err = parseVersion("to_version"); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobDiffResponse
+	if err := s.agent.RPC("Job.Diff", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -614,6 +755,35 @@ func (s *HTTPServer) jobStable(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSuspend(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var suspendRequest structs.JobSuspendRequest
+	if err := decodeBody(req, &suspendRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if suspendRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if suspendRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &suspendRequest.WriteRequest)
+
+	var out structs.JobRegisterResponse
+	if err := s.agent.RPC("Job.Suspend", &suspendRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
 	args := structs.JobSummaryRequest{
 		JobID: name,
@@ -767,6 +937,20 @@ func regionForJob(job *api.Job, queryRegion, apiRegion, agentRegion string) (str
 	return requestRegion, jobRegion
 }
 
+// apiJobSubmissionToStructs converts a job submission from the API
+// representation.
+func apiJobSubmissionToStructs(sub *api.JobSubmission) *structs.JobSubmission {
+	if sub == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:        sub.Source,
+		Format:        sub.Format,
+		VariableFlags: sub.VariableFlags,
+		Variables:     sub.Variables,
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -790,6 +974,10 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.GCThreshold != nil {
+		j.GCThreshold = *job.GCThreshold
+	}
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -832,6 +1020,22 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if job.Expiration != nil {
+		j.Expiration = &structs.JobExpiration{
+			At:    *job.Expiration.At,
+			TTL:   *job.Expiration.TTL,
+			Purge: *job.Expiration.Purge,
+		}
+	}
+
+	if job.Ownership != nil {
+		j.Ownership = &structs.JobOwnership{
+			Team:    *job.Ownership.Team,
+			Contact: *job.Ownership.Contact,
+			RepoURL: *job.Ownership.RepoURL,
+		}
+	}
+
 	if job.Multiregion != nil {
 		j.Multiregion = &structs.Multiregion{}
 		j.Multiregion.Strategy = &structs.MultiregionStrategy{
@@ -1035,6 +1239,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 				CanaryTags:        service.CanaryTags,
 				EnableTagOverride: service.EnableTagOverride,
 				AddressMode:       service.AddressMode,
+				Provider:          service.Provider,
 				Meta:              helper.CopyMapStringString(service.Meta),
 				CanaryMeta:        helper.CopyMapStringString(service.CanaryMeta),
 			}
@@ -1231,7 +1436,7 @@ func ApiPortToStructs(in api.Port) structs.Port {
 	}
 }
 
-//TODO(schmichael) refactor and reuse in service parsing above
+// TODO(schmichael) refactor and reuse in service parsing above
 func ApiServicesToStructs(in []*api.Service) []*structs.Service {
 	if len(in) == 0 {
 		return nil
@@ -1247,6 +1452,7 @@ func ApiServicesToStructs(in []*api.Service) []*structs.Service {
 			CanaryTags:        s.CanaryTags,
 			EnableTagOverride: s.EnableTagOverride,
 			AddressMode:       s.AddressMode,
+			Provider:          s.Provider,
 			Meta:              helper.CopyMapStringString(s.Meta),
 			CanaryMeta:        helper.CopyMapStringString(s.CanaryMeta),
 		}
@@ -1311,8 +1517,10 @@ func apiConnectGatewayToStructs(in *api.ConsulGateway) *structs.ConsulGateway {
 	}
 
 	return &structs.ConsulGateway{
-		Proxy:   apiConnectGatewayProxyToStructs(in.Proxy),
-		Ingress: apiConnectIngressGatewayToStructs(in.Ingress),
+		Proxy:       apiConnectGatewayProxyToStructs(in.Proxy),
+		Ingress:     apiConnectIngressGatewayToStructs(in.Ingress),
+		Terminating: apiConnectTerminatingGatewayToStructs(in.Terminating),
+		Mesh:        apiConnectMeshGatewayToStructs(in.Mesh),
 	}
 }
 
@@ -1409,6 +1617,49 @@ func apiConnectIngressServiceToStructs(in *api.ConsulIngressService) *structs.Co
 	}
 }
 
+func apiConnectTerminatingGatewayToStructs(in *api.ConsulTerminatingConfigEntry) *structs.ConsulTerminatingConfigEntry {
+	if in == nil {
+		return nil
+	}
+
+	return &structs.ConsulTerminatingConfigEntry{
+		Services: apiConnectTerminatingServicesToStructs(in.Services),
+	}
+}
+
+func apiConnectTerminatingServicesToStructs(in []*api.ConsulLinkedService) []*structs.ConsulLinkedService {
+	if len(in) == 0 {
+		return nil
+	}
+
+	services := make([]*structs.ConsulLinkedService, len(in))
+	for i, service := range in {
+		services[i] = apiConnectTerminatingServiceToStructs(service)
+	}
+	return services
+}
+
+func apiConnectTerminatingServiceToStructs(in *api.ConsulLinkedService) *structs.ConsulLinkedService {
+	if in == nil {
+		return nil
+	}
+
+	return &structs.ConsulLinkedService{
+		Name:     in.Name,
+		CAFile:   in.CAFile,
+		CertFile: in.CertFile,
+		KeyFile:  in.KeyFile,
+		SNI:      in.SNI,
+	}
+}
+
+func apiConnectMeshGatewayToStructs(in *api.ConsulMeshConfigEntry) *structs.ConsulMeshConfigEntry {
+	if in == nil {
+		return nil
+	}
+	return new(structs.ConsulMeshConfigEntry)
+}
+
 func apiConnectSidecarServiceToStructs(in *api.ConsulSidecarService) *structs.ConsulSidecarService {
 	if in == nil {
 		return nil
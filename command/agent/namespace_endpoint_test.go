@@ -0,0 +1,212 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// enableNamespaces flips the namespaces feature flag on for the test agent's
+// server.
+func enableNamespaces(t *testing.T, s *TestAgent) {
+	t.Helper()
+	require.NoError(t, s.Agent.server.State().FeatureFlagsSet(1, &structs.FeatureFlags{
+		Flags: map[string]bool{structs.FeatureNamespaces: true},
+	}))
+}
+
+func TestHTTP_NamespaceList(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		ns1 := mock.Namespace()
+		ns2 := mock.Namespace()
+		ns3 := mock.Namespace()
+		args := structs.NamespaceUpsertRequest{
+			Namespaces:   []*structs.Namespace{ns1, ns2, ns3},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		enableNamespaces(t, s)
+		var resp structs.GenericResponse
+		require.Nil(s.Agent.RPC("Namespace.UpsertNamespaces", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/namespaces", nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NamespacesRequest(respW, req)
+		require.Nil(err)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal("true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-LastContact"))
+
+		// Check the output; the default namespace is always present
+		n := obj.([]*structs.Namespace)
+		require.Len(n, 4)
+	})
+}
+
+func TestHTTP_NamespaceQuery(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		ns1 := mock.Namespace()
+		args := structs.NamespaceUpsertRequest{
+			Namespaces:   []*structs.Namespace{ns1},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		enableNamespaces(t, s)
+		var resp structs.GenericResponse
+		require.Nil(s.Agent.RPC("Namespace.UpsertNamespaces", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/namespace/"+ns1.Name, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NamespaceSpecificRequest(respW, req)
+		require.Nil(err)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal("true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-LastContact"))
+
+		// Check the output
+		n := obj.(*structs.Namespace)
+		require.Equal(ns1.Name, n.Name)
+	})
+}
+
+func TestHTTP_NamespaceCreate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		enableNamespaces(t, s)
+
+		// Make the HTTP request
+		ns1 := api.Namespace{
+			Name:        "foo",
+			Description: "test namespace",
+		}
+		buf := encodeReq(ns1)
+		req, err := http.NewRequest("PUT", "/v1/namespace", buf)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NamespaceCreateRequest(respW, req)
+		require.Nil(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the namespace was created
+		state := s.Agent.server.State()
+		out, err := state.NamespaceByName(nil, ns1.Name)
+		require.Nil(err)
+		require.NotNil(out)
+		require.Equal(ns1.Name, out.Name)
+		require.Equal(ns1.Description, out.Description)
+	})
+}
+
+func TestHTTP_NamespaceUpdate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		enableNamespaces(t, s)
+
+		// Make the HTTP request
+		ns1 := api.Namespace{
+			Name:        "foo",
+			Description: "test namespace",
+		}
+		buf := encodeReq(ns1)
+		req, err := http.NewRequest("PUT", "/v1/namespace/"+ns1.Name, buf)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NamespaceSpecificRequest(respW, req)
+		require.Nil(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the namespace was created
+		state := s.Agent.server.State()
+		out, err := state.NamespaceByName(nil, ns1.Name)
+		require.Nil(err)
+		require.NotNil(out)
+	})
+}
+
+func TestHTTP_NamespaceUpdate_NameMismatch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		enableNamespaces(t, s)
+
+		// Make the HTTP request with a body whose name doesn't match the
+		// request path
+		ns1 := api.Namespace{
+			Name: "foo",
+		}
+		buf := encodeReq(ns1)
+		req, err := http.NewRequest("PUT", "/v1/namespace/bar", buf)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		_, err = s.Server.NamespaceSpecificRequest(respW, req)
+		require.NotNil(err)
+		require.Contains(err.Error(), "does not match")
+	})
+}
+
+func TestHTTP_NamespaceDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		ns1 := mock.Namespace()
+		args := structs.NamespaceUpsertRequest{
+			Namespaces:   []*structs.Namespace{ns1},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		enableNamespaces(t, s)
+		var resp structs.GenericResponse
+		require.Nil(s.Agent.RPC("Namespace.UpsertNamespaces", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("DELETE", "/v1/namespace/"+ns1.Name, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NamespaceSpecificRequest(respW, req)
+		require.Nil(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the namespace was deleted
+		state := s.Agent.server.State()
+		out, err := state.NamespaceByName(nil, ns1.Name)
+		require.Nil(err)
+		require.Nil(out)
+	})
+}
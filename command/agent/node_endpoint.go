@@ -32,6 +32,31 @@ func (s *HTTPServer) NodesRequest(resp http.ResponseWriter, req *http.Request) (
 	return out.Nodes, nil
 }
 
+func (s *HTTPServer) NodeInventoryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.NodeInventoryRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NodeInventoryReportResponse
+	if err := s.agent.RPC("Node.InventoryReport", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Baseline == nil {
+		out.Baseline = make(map[string]string)
+	}
+	if out.Drifted == nil {
+		out.Drifted = make([]*structs.NodeInventoryDrift, 0)
+	}
+	return out, nil
+}
+
 func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/node/")
 	switch {
@@ -219,3 +244,60 @@ func (s *HTTPServer) nodePurge(resp http.ResponseWriter, req *http.Request, node
 	setIndex(resp, out.Index)
 	return out, nil
 }
+
+func (s *HTTPServer) NodesBatchDrainRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.NodeBatchDrainUpdateRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeBatchUpdateResponse
+	if err := s.agent.RPC("Node.BatchUpdateDrain", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) NodesBatchEligibilityRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.NodeBatchEligibilityUpdateRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeBatchUpdateResponse
+	if err := s.agent.RPC("Node.BatchUpdateEligibility", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) NodesBatchMetaRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.NodeBatchMetaUpdateRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeBatchUpdateResponse
+	if err := s.agent.RPC("Node.BatchUpdateMeta", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"strings"
@@ -264,6 +265,7 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 			SystemSchedulerEnabled:  conf.PreemptionConfig.SystemSchedulerEnabled,
 			BatchSchedulerEnabled:   conf.PreemptionConfig.BatchSchedulerEnabled,
 			ServiceSchedulerEnabled: conf.PreemptionConfig.ServiceSchedulerEnabled},
+		NamespaceAdmissionWeights: conf.NamespaceAdmissionWeights,
 	}
 
 	if err := args.Config.Validate(); err != nil {
@@ -289,6 +291,296 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	return reply, nil
 }
 
+// OperatorKeyringRequest handles /v1/operator/keyring requests for listing
+// the workload identity signing keyring.
+func (s *HTTPServer) OperatorKeyringRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.KeyringListRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.KeyringListResponse
+	if err := s.agent.RPC("Operator.SigningKeyringList", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Keys == nil {
+		reply.Keys = make([]*structs.SigningKeyStub, 0)
+	}
+	return reply.Keys, nil
+}
+
+// OperatorKeyringRotateRequest handles /v1/operator/keyring/rotate requests
+// for rotating the active workload identity signing key.
+func (s *HTTPServer) OperatorKeyringRotateRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "PUT", "POST":
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.KeyringRotateRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.KeyringRotateResponse
+	if err := s.agent.RPC("Operator.SigningKeyringRotate", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return reply, nil
+}
+
+// OperatorKeyringSpecificRequest handles /v1/operator/keyring/<key_id>
+// requests for removing a signing key from the keyring.
+func (s *HTTPServer) OperatorKeyringSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "DELETE" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	keyID := strings.TrimPrefix(req.URL.Path, "/v1/operator/keyring/")
+	if keyID == "" {
+		return nil, CodedError(400, "missing key ID")
+	}
+
+	args := structs.KeyringRemoveRequest{KeyID: keyID}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.KeyringRemoveResponse
+	if err := s.agent.RPC("Operator.SigningKeyringRemove", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
+
+// OperatorFeatureFlagsRequest handles /v1/operator/features requests for
+// reading and toggling cluster-wide feature flags.
+func (s *HTTPServer) OperatorFeatureFlagsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.featureFlagsGet(resp, req)
+	case "PUT", "POST":
+		return s.featureFlagsSet(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) featureFlagsGet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.FeatureFlagsResponse
+	if err := s.agent.RPC("Operator.FeatureFlagsGet", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+func (s *HTTPServer) featureFlagsSet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.FeatureFlagsSetRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var flags map[string]bool
+	if err := decodeBody(req, &flags); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing feature flags: %v", err))
+	}
+	args.Flags = flags
+
+	var reply structs.GenericResponse
+	if err := s.agent.RPC("Operator.FeatureFlagsSet", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
+
+// OperatorFreezeWindowsRequest handles /v1/operator/freeze-windows requests
+// for reading and replacing the cluster's change-freeze windows.
+func (s *HTTPServer) OperatorFreezeWindowsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.freezeWindowsGet(resp, req)
+	case "PUT", "POST":
+		return s.freezeWindowsSet(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) freezeWindowsGet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.FreezeWindowsResponse
+	if err := s.agent.RPC("Operator.FreezeWindowsGet", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+func (s *HTTPServer) freezeWindowsSet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.FreezeWindowsSetRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var windows []*structs.FreezeWindow
+	if err := decodeBody(req, &windows); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing freeze windows: %v", err))
+	}
+	args.Windows = windows
+
+	var reply structs.GenericResponse
+	if err := s.agent.RPC("Operator.FreezeWindowsSet", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
+
+// OperatorLicenseHistoryRequest handles /v1/operator/license/history
+// requests for reading the license audit trail.
+func (s *HTTPServer) OperatorLicenseHistoryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.LicenseHistoryRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.LicenseHistoryResponse
+	if err := s.agent.RPC("License.GetLicenseHistory", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+// OperatorLicenseReportRequest handles /v1/operator/license/report requests
+// for the aggregated per-region license report.
+func (s *HTTPServer) OperatorLicenseReportRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.LicenseReportRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.LicenseReportResponse
+	if err := s.agent.RPC("License.Report", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+// OperatorLicensePreviewRequest handles /v1/operator/license/preview
+// requests for dry-running a candidate license against the state store.
+func (s *HTTPServer) OperatorLicensePreviewRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.LicensePreviewRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	// The license is submitted as the raw request body
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error reading license: %v", err))
+	}
+	args.License = &structs.StoredLicense{Signed: string(body)}
+
+	var reply structs.LicensePreviewResponse
+	if err := s.agent.RPC("License.PreviewLicense", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+// OperatorLicenseRequest handles /v1/operator/license requests for reading,
+// updating, and resetting the cluster license.
+func (s *HTTPServer) OperatorLicenseRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.operatorLicenseGet(resp, req)
+	case "PUT", "POST":
+		return s.operatorLicensePut(resp, req)
+	case "DELETE":
+		return s.operatorLicenseReset(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) operatorLicenseGet(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.LicenseGetRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.LicenseGetResponse
+	if err := s.agent.RPC("License.GetLicense", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+func (s *HTTPServer) operatorLicensePut(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.LicenseUpsertRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	// The license is submitted as the raw request body
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error reading license: %v", err))
+	}
+	args.License = &structs.StoredLicense{Signed: string(body)}
+
+	var reply structs.GenericResponse
+	if err := s.agent.RPC("License.UpsertLicense", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) operatorLicenseReset(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.LicenseDeleteRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.GenericResponse
+	if err := s.agent.RPC("License.DeleteLicense", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return nil, nil
+}
+
 func (s *HTTPServer) SnapshotRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	switch req.Method {
 	case "GET":
@@ -463,3 +755,95 @@ func (s *HTTPServer) snapshotRestoreRequest(resp http.ResponseWriter, req *http.
 
 	return nil, codedErr
 }
+
+// OperatorClusterHeadroom computes how many instances of a hypothetical task
+// shape could still be placed on the cluster's ready nodes.
+func (s *HTTPServer) OperatorClusterHeadroom(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.ClusterHeadroomRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	params := req.URL.Query()
+	for param, target := range map[string]*int64{
+		"cpu":    &args.CPU,
+		"memory": &args.MemoryMB,
+	} {
+		if value := params.Get(param); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing %s: %v", param, err))
+			}
+			*target = parsed
+		}
+	}
+	if value := params.Get("ports"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing ports: %v", err))
+		}
+		args.Ports = parsed
+	}
+
+	var reply structs.ClusterHeadroomResponse
+	if err := s.agent.RPC("Operator.ClusterHeadroom", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+// OperatorMetricsHistoryRequest handles /v1/operator/metrics/history requests
+// for the server's in-memory history of cluster gauges.
+func (s *HTTPServer) OperatorMetricsHistoryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.MetricsHistoryRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	if value := req.URL.Query().Get("since"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing since: %v", err))
+		}
+		args.Since = parsed
+	}
+
+	var reply structs.MetricsHistoryResponse
+	if err := s.agent.RPC("Operator.MetricsHistory", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply, nil
+}
+
+// OperatorVaultStatusRequest handles /v1/operator/vault/status requests for
+// the status of the leader's Vault integration.
+func (s *HTTPServer) OperatorVaultStatusRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.VaultStatusRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.VaultStatusResponse
+	if err := s.agent.RPC("Operator.VaultStatus", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Status, nil
+}
@@ -34,3 +34,29 @@ func (s *HTTPServer) newSearchRequest(resp http.ResponseWriter, req *http.Reques
 	setMeta(resp, &out.QueryMeta)
 	return out, nil
 }
+
+// ResolveIdentifierRequest accepts a prefix and returns typed matches for it
+// across all contexts.
+func (s *HTTPServer) ResolveIdentifierRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "POST" && req.Method != "PUT" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ResolveIdentifierRequest{}
+
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ResolveIdentifierResponse
+	if err := s.agent.RPC("Search.ResolveIdentifier", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
@@ -0,0 +1,273 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) SentinelPoliciesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.SentinelPolicyListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SentinelPolicyListResponse
+	if err := s.agent.RPC("Sentinel.ListPolicies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Policies == nil {
+		out.Policies = make([]*structs.SentinelPolicyListStub, 0)
+	}
+	return out.Policies, nil
+}
+
+func (s *HTTPServer) SentinelOverridesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.SentinelOverrideListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SentinelOverrideListResponse
+	if err := s.agent.RPC("Sentinel.ListOverrides", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Overrides == nil {
+		out.Overrides = make([]*structs.SentinelOverride, 0)
+	}
+	return out.Overrides, nil
+}
+
+func (s *HTTPServer) SentinelPolicyTestRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Parse the policy and job
+	var testRequest api.SentinelPolicyTestRequest
+	if err := decodeBody(req, &testRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if testRequest.Policy == nil {
+		return nil, CodedError(400, "Missing policy")
+	}
+	if testRequest.Job == nil {
+		return nil, CodedError(400, "Missing job")
+	}
+
+	args := structs.SentinelPolicyTestRequest{
+		Policy: &structs.SentinelPolicy{
+			Name:             testRequest.Policy.Name,
+			Description:      testRequest.Policy.Description,
+			Scope:            testRequest.Policy.Scope,
+			EnforcementLevel: testRequest.Policy.EnforcementLevel,
+			Policy:           testRequest.Policy.Policy,
+		},
+		Job: ApiJobToStructJob(testRequest.Job),
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.SentinelPolicyTestResponse
+	if err := s.agent.RPC("Sentinel.TestPolicy", &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *HTTPServer) SentinelPolicySpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/sentinel/policy/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Policy Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.sentinelPolicyQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.sentinelPolicyUpdate(resp, req, name)
+	case "DELETE":
+		return s.sentinelPolicyDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) sentinelPolicyQuery(resp http.ResponseWriter, req *http.Request,
+	policyName string) (interface{}, error) {
+	args := structs.SentinelPolicySpecificRequest{
+		Name: policyName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleSentinelPolicyResponse
+	if err := s.agent.RPC("Sentinel.GetPolicy", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Policy == nil {
+		return nil, CodedError(404, "Sentinel policy not found")
+	}
+	return out.Policy, nil
+}
+
+func (s *HTTPServer) sentinelPolicyUpdate(resp http.ResponseWriter, req *http.Request,
+	policyName string) (interface{}, error) {
+	// Parse the policy
+	var policy structs.SentinelPolicy
+	if err := decodeBody(req, &policy); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the policy name matches
+	if policy.Name != policyName {
+		return nil, CodedError(400, "Sentinel policy name does not match request path")
+	}
+
+	// Format the request
+	args := structs.SentinelPolicyUpsertRequest{
+		Policies: []*structs.SentinelPolicy{&policy},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Sentinel.UpsertPolicies", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) sentinelPolicyDelete(resp http.ResponseWriter, req *http.Request,
+	policyName string) (interface{}, error) {
+
+	args := structs.SentinelPolicyDeleteRequest{
+		Names: []string{policyName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Sentinel.DeletePolicies", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) SentinelModulesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.SentinelModuleListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SentinelModuleListResponse
+	if err := s.agent.RPC("Sentinel.ListModules", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Modules == nil {
+		out.Modules = make([]*structs.SentinelModuleListStub, 0)
+	}
+	return out.Modules, nil
+}
+
+func (s *HTTPServer) SentinelModuleSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/sentinel/module/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Module Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.sentinelModuleQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.sentinelModuleUpdate(resp, req, name)
+	case "DELETE":
+		return s.sentinelModuleDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) sentinelModuleQuery(resp http.ResponseWriter, req *http.Request,
+	moduleName string) (interface{}, error) {
+	args := structs.SentinelModuleSpecificRequest{
+		Name: moduleName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleSentinelModuleResponse
+	if err := s.agent.RPC("Sentinel.GetModule", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Module == nil {
+		return nil, CodedError(404, "Sentinel module not found")
+	}
+	return out.Module, nil
+}
+
+func (s *HTTPServer) sentinelModuleUpdate(resp http.ResponseWriter, req *http.Request,
+	moduleName string) (interface{}, error) {
+	// Parse the module
+	var module structs.SentinelModule
+	if err := decodeBody(req, &module); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the module name matches
+	if module.Name != moduleName {
+		return nil, CodedError(400, "Sentinel module name does not match request path")
+	}
+
+	// Format the request
+	args := structs.SentinelModuleUpsertRequest{
+		Modules: []*structs.SentinelModule{&module},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Sentinel.UpsertModules", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) sentinelModuleDelete(resp http.ResponseWriter, req *http.Request,
+	moduleName string) (interface{}, error) {
+
+	args := structs.SentinelModuleDeleteRequest{
+		Names: []string{moduleName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Sentinel.DeleteModules", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
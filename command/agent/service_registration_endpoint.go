@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServicesRequest handles /v1/services requests to list the registered
+// service names.
+func (s *HTTPServer) ServicesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ServiceRegistrationListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ServiceRegistrationListResponse
+	if err := s.agent.RPC("ServiceRegistration.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Services == nil {
+		out.Services = make([]*structs.ServiceRegistrationStub, 0)
+	}
+	return out.Services, nil
+}
+
+// ServiceSpecificRequest handles /v1/service/<name> requests for reading
+// the registrations of a service and /v1/service/<name>/<id> requests for
+// removing a single registration.
+func (s *HTTPServer) ServiceSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/service/")
+	switch {
+	case strings.Contains(path, "/"):
+		parts := strings.SplitN(path, "/", 2)
+		return s.serviceDelete(resp, req, parts[1])
+	default:
+		return s.serviceQuery(resp, req, path)
+	}
+}
+
+func (s *HTTPServer) serviceQuery(resp http.ResponseWriter, req *http.Request,
+	serviceName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	if serviceName == "" {
+		return nil, CodedError(400, "missing service name")
+	}
+
+	args := structs.ServiceRegistrationByNameRequest{
+		ServiceName: serviceName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ServiceRegistrationByNameResponse
+	if err := s.agent.RPC("ServiceRegistration.GetService", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Services == nil {
+		out.Services = make([]*structs.ServiceRegistration, 0)
+	}
+	return out.Services, nil
+}
+
+func (s *HTTPServer) serviceDelete(resp http.ResponseWriter, req *http.Request,
+	serviceID string) (interface{}, error) {
+	if req.Method != "DELETE" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ServiceRegistrationDeleteByIDRequest{
+		ID: serviceID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ServiceRegistrationDeleteByIDResponse
+	if err := s.agent.RPC("ServiceRegistration.DeleteByID", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
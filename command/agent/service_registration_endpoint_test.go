@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ServicesList(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		service := mock.ServiceRegistration()
+		state := s.Agent.server.State()
+		require.NoError(state.UpsertServiceRegistrations(1000,
+			[]*structs.ServiceRegistration{service}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/services", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ServicesRequest(respW, req)
+		require.NoError(err)
+
+		// Check for the index
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the stubs
+		stubs := obj.([]*structs.ServiceRegistrationStub)
+		require.Len(stubs, 1)
+		require.Equal(service.ServiceName, stubs[0].ServiceName)
+		require.Equal(service.Tags, stubs[0].Tags)
+	})
+}
+
+func TestHTTP_ServiceQuery(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		service := mock.ServiceRegistration()
+		state := s.Agent.server.State()
+		require.NoError(state.UpsertServiceRegistrations(1000,
+			[]*structs.ServiceRegistration{service}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/service/"+service.ServiceName, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ServiceSpecificRequest(respW, req)
+		require.NoError(err)
+
+		services := obj.([]*structs.ServiceRegistration)
+		require.Len(services, 1)
+		require.Equal(service.ID, services[0].ID)
+
+		// An unknown service returns an empty list rather than an error
+		req, err = http.NewRequest("GET", "/v1/service/nope", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.ServiceSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Empty(obj.([]*structs.ServiceRegistration))
+	})
+}
+
+func TestHTTP_ServiceDelete(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		service := mock.ServiceRegistration()
+		state := s.Agent.server.State()
+		require.NoError(state.UpsertServiceRegistrations(1000,
+			[]*structs.ServiceRegistration{service}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("DELETE", "/v1/service/"+service.ServiceName+"/"+service.ID, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ServiceSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		out, err := state.ServiceRegistrationByID(nil, service.ID)
+		require.NoError(err)
+		require.Nil(out)
+	})
+}
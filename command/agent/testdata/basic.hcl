@@ -96,6 +96,14 @@ client {
     path = "/tmp"
   }
 
+  env_provider "static" {
+    namespaces = ["default"]
+
+    config {
+      SITE = "east"
+    }
+  }
+
   cni_path              = "/tmp/cni_path"
   bridge_network_name   = "custom_bridge_name"
   bridge_network_subnet = "custom_bridge_subnet"
@@ -0,0 +1,33 @@
+server {
+  enabled = true
+
+  job_defaults {
+    restart {
+      attempts = 5
+      interval = "1h"
+      delay    = "30s"
+      mode     = "delay"
+    }
+
+    reschedule {
+      attempts       = 3
+      interval       = "2h"
+      delay          = "10s"
+      delay_function = "fibonacci"
+      max_delay      = "15m"
+      unlimited      = false
+    }
+
+    update {
+      stagger           = "1m"
+      max_parallel      = 2
+      health_check      = "task_states"
+      min_healthy_time  = "30s"
+      healthy_deadline  = "10m"
+      progress_deadline = "20m"
+      auto_revert       = true
+      auto_promote      = true
+      canary            = 1
+    }
+  }
+}
@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariablesRequest handles /v1/vars requests to list variable metadata,
+// optionally under the path given by the prefix query parameter.
+func (s *HTTPServer) VariablesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesListResponse
+	if err := s.agent.RPC("Variables.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		out.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return out.Data, nil
+}
+
+// VariableSpecificRequest handles /v1/var/<path> requests for reading,
+// writing, and deleting the variable at a path.
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if path == "" {
+		return nil, CodedError(400, "missing variable path")
+	}
+
+	switch req.Method {
+	case "GET":
+		return s.variableQuery(resp, req, path)
+	case "PUT", "POST":
+		return s.variableUpsert(resp, req, path)
+	case "DELETE":
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesReadResponse
+	if err := s.agent.RPC("Variables.Read", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		return nil, CodedError(404, "variable not found")
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	var variable structs.Variable
+	if err := decodeBody(req, &variable); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	variable.Path = path
+
+	args := structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{&variable},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesUpsertResponse
+	if err := s.agent.RPC("Variables.Upsert", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesDeleteRequest{
+		Path: path,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesDeleteResponse
+	if err := s.agent.RPC("Variables.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_VariablePutAndQuery(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		variable := &structs.Variable{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}
+
+		// Write the variable
+		req, err := http.NewRequest("PUT", "/v1/var/nomad/jobs/example", encodeReq(variable))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		// Read it back decrypted
+		req, err = http.NewRequest("GET", "/v1/var/nomad/jobs/example", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+
+		// Check for the index
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out := obj.(*structs.Variable)
+		require.Equal("nomad/jobs/example", out.Path)
+		require.Equal(map[string]string{"user": "me"}, out.Items)
+
+		// An unknown path is a 404
+		req, err = http.NewRequest("GET", "/v1/var/nope", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "variable not found")
+	})
+}
+
+func TestHTTP_VariablesList(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		for _, path := range []string{"nomad/jobs/example", "system/secrets"} {
+			variable := &structs.Variable{
+				Path:  path,
+				Items: map[string]string{"user": "me"},
+			}
+			req, err := http.NewRequest("PUT", "/v1/var/"+path, encodeReq(variable))
+			require.NoError(err)
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(err)
+		}
+
+		// List all the variables
+		req, err := http.NewRequest("GET", "/v1/vars", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.VariablesRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.VariableMetadata), 2)
+
+		// Filter by prefix
+		req, err = http.NewRequest("GET", "/v1/vars?prefix=nomad", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.VariablesRequest(respW, req)
+		require.NoError(err)
+
+		metadata := obj.([]*structs.VariableMetadata)
+		require.Len(metadata, 1)
+		require.Equal("nomad/jobs/example", metadata[0].Path)
+	})
+}
+
+func TestHTTP_VariableDelete(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		variable := &structs.Variable{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/var/nomad/jobs/example", encodeReq(variable))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+
+		// Delete the variable
+		req, err = http.NewRequest("DELETE", "/v1/var/nomad/jobs/example", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		out, err := s.Agent.server.State().GetVariable(nil, structs.DefaultNamespace, "nomad/jobs/example")
+		require.NoError(err)
+		require.Nil(out)
+	})
+}
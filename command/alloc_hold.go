@@ -0,0 +1,130 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+type AllocHoldCommand struct {
+	Meta
+}
+
+func (a *AllocHoldCommand) Help() string {
+	helpText := `
+Usage: nomad alloc hold [options] <allocation>
+
+  Place an existing allocation on hold for debugging. While held, the
+  scheduler will not replace the allocation and the client pauses the task
+  restart policy, keeping the task directory around for inspection. Use the
+  -release flag to remove the hold and resume normal scheduling and restart
+  behavior.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Hold Specific Options:
+
+  -release
+    Release a previously placed hold instead of placing one.
+
+  -verbose
+    Show full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocHoldCommand) Name() string { return "alloc hold" }
+
+func (c *AllocHoldCommand) Run(args []string) int {
+	var release, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&release, "release", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one alloc
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <alloc-id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	allocID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Query the allocation info
+	if len(allocID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Alloc ID must contain at least two characters."))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+
+	if len(allocs) > 1 {
+		// Format the allocs
+		out := formatAllocListStubs(allocs, verbose, length)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	// Prefix lookup matched a single allocation
+	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	_, err = client.Allocations().Hold(alloc, release, nil)
+	if err != nil {
+		if release {
+			c.Ui.Error(fmt.Sprintf("Error releasing hold on allocation: %s", err))
+		} else {
+			c.Ui.Error(fmt.Sprintf("Error placing hold on allocation: %s", err))
+		}
+		return 1
+	}
+
+	if release {
+		c.Ui.Output(fmt.Sprintf("Released hold on allocation %q", limit(alloc.ID, length)))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Placed hold on allocation %q", limit(alloc.ID, length)))
+	}
+	return 0
+}
+
+func (a *AllocHoldCommand) Synopsis() string {
+	return "Place an allocation on hold for debugging"
+}
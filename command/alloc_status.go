@@ -49,6 +49,13 @@ Alloc Status Options:
 
   -t
     Format and display allocation using a Go template.
+
+  -watch
+    Continuously update the status output using blocking queries.
+
+  -monitor-interval
+    How often to refresh the output when watching, and the upper bound on
+    how long a blocking query waits. Defaults to 2s.
 `
 
 	return strings.TrimSpace(helpText)
@@ -61,10 +68,12 @@ func (c *AllocStatusCommand) Synopsis() string {
 func (c *AllocStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-short":   complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-short":            complete.PredictNothing,
+			"-verbose":          complete.PredictNothing,
+			"-json":             complete.PredictNothing,
+			"-t":                complete.PredictAnything,
+			"-watch":            complete.PredictNothing,
+			"-monitor-interval": complete.PredictAnything,
 		})
 }
 
@@ -86,8 +95,9 @@ func (c *AllocStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *AllocStatusCommand) Name() string { return "alloc status" }
 
 func (c *AllocStatusCommand) Run(args []string) int {
-	var short, displayStats, verbose, json bool
+	var short, displayStats, verbose, json, watch bool
 	var tmpl string
+	var monitorInterval time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -96,11 +106,18 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	flags.BoolVar(&displayStats, "stats", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&watch, "watch", false, "")
+	flags.DurationVar(&monitorInterval, "monitor-interval", defaultMonitorInterval, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if watch && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -watch flag cannot be used with -json or -t")
+		return 1
+	}
+
 	// Check that we got exactly one allocation ID
 	args = flags.Args()
 
@@ -166,6 +183,22 @@ func (c *AllocStatusCommand) Run(args []string) int {
 		return 0
 	}
 	// Prefix lookup matched a single allocation
+	if watch {
+		return c.Meta.watch(monitorInterval, func(q *api.QueryOptions) (uint64, error) {
+			q.Namespace = allocs[0].Namespace
+			alloc, meta, err := client.Allocations().Info(allocs[0].ID, q)
+			if err != nil {
+				return 0, fmt.Errorf("Error querying allocation: %s", err)
+			}
+
+			c.clearScreen()
+			if err := c.outputAllocStatus(client, alloc, short, displayStats, verbose, length); err != nil {
+				return 0, err
+			}
+			return meta.LastIndex, nil
+		})
+	}
+
 	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
 	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
 	if err != nil {
@@ -185,14 +218,23 @@ func (c *AllocStatusCommand) Run(args []string) int {
 		return 0
 	}
 
+	if err := c.outputAllocStatus(client, alloc, short, displayStats, verbose, length); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// outputAllocStatus renders the full status output for a single allocation.
+func (c *AllocStatusCommand) outputAllocStatus(client *api.Client, alloc *api.Allocation, short, displayStats, verbose bool, length int) error {
 	// Format the allocation data
 	if short {
 		c.Ui.Output(formatAllocShortInfo(alloc, client))
 	} else {
 		output, err := formatAllocBasicInfo(alloc, client, length, verbose)
 		if err != nil {
-			c.Ui.Error(err.Error())
-			return 1
+			return err
 		}
 		c.Ui.Output(output)
 
@@ -200,6 +242,11 @@ func (c *AllocStatusCommand) Run(args []string) int {
 			c.Ui.Output("")
 			c.Ui.Output(formatAllocNetworkInfo(alloc))
 		}
+
+		if alloc.NetworkStatus != nil {
+			c.Ui.Output("")
+			c.Ui.Output(formatAllocNetworkStatus(alloc.NetworkStatus))
+		}
 	}
 
 	if short {
@@ -225,7 +272,7 @@ func (c *AllocStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatAllocMetrics(alloc.Metrics, true, "  "))
 	}
 
-	return 0
+	return nil
 }
 
 func formatAllocShortInfo(alloc *api.Allocation, client *api.Client) string {
@@ -355,6 +402,24 @@ func formatAllocNetworkInfo(alloc *api.Allocation) string {
 	return fmt.Sprintf("Allocation Addresses%s\n%s", mode, formatList(addrs))
 }
 
+// formatAllocNetworkStatus returns the runtime network status reported by the
+// client, including the address assigned inside the network namespace and any
+// DNS configuration applied by CNI plugins
+func formatAllocNetworkStatus(netStatus *api.AllocNetworkStatus) string {
+	status := []string{
+		fmt.Sprintf("Interface|%s", netStatus.InterfaceName),
+		fmt.Sprintf("Address|%s", netStatus.Address),
+	}
+	if dns := netStatus.DNS; dns != nil {
+		status = append(status,
+			fmt.Sprintf("DNS Servers|%s", strings.Join(dns.Servers, ",")),
+			fmt.Sprintf("DNS Searches|%s", strings.Join(dns.Searches, ",")),
+			fmt.Sprintf("DNS Options|%s", strings.Join(dns.Options, ",")))
+	}
+
+	return fmt.Sprintf("Network Status\n%s", formatKV(status))
+}
+
 // futureEvalTimePretty returns when the eval is eligible to reschedule
 // relative to current time, based on the WaitUntil field
 func futureEvalTimePretty(evalID string, client *api.Client) string {
@@ -74,11 +74,66 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl auth-method": func() (cli.Command, error) {
+			return &ACLAuthMethodCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method create": func() (cli.Command, error) {
+			return &ACLAuthMethodCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method delete": func() (cli.Command, error) {
+			return &ACLAuthMethodDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method info": func() (cli.Command, error) {
+			return &ACLAuthMethodInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method list": func() (cli.Command, error) {
+			return &ACLAuthMethodListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule": func() (cli.Command, error) {
+			return &ACLBindingRuleCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule create": func() (cli.Command, error) {
+			return &ACLBindingRuleCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule delete": func() (cli.Command, error) {
+			return &ACLBindingRuleDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule info": func() (cli.Command, error) {
+			return &ACLBindingRuleInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule list": func() (cli.Command, error) {
+			return &ACLBindingRuleListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl bootstrap": func() (cli.Command, error) {
 			return &ACLBootstrapCommand{
 				Meta: meta,
 			}, nil
 		},
+		"acl login": func() (cli.Command, error) {
+			return &ACLLoginCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl policy": func() (cli.Command, error) {
 			return &ACLPolicyCommand{
 				Meta: meta,
@@ -104,6 +159,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl role": func() (cli.Command, error) {
+			return &ACLRoleCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role apply": func() (cli.Command, error) {
+			return &ACLRoleApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role delete": func() (cli.Command, error) {
+			return &ACLRoleDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role info": func() (cli.Command, error) {
+			return &ACLRoleInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role list": func() (cli.Command, error) {
+			return &ACLRoleListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl token": func() (cli.Command, error) {
 			return &ACLTokenCommand{
 				Meta: meta,
@@ -149,6 +229,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc hold": func() (cli.Command, error) {
+			return &AllocHoldCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc signal": func() (cli.Command, error) {
 			return &AllocSignalCommand{
 				Meta: meta,
@@ -201,6 +286,36 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"config": func() (cli.Command, error) {
+			return &ConfigCommand{
+				Meta: meta,
+			}, nil
+		},
+		"config set-context": func() (cli.Command, error) {
+			return &ConfigSetContextCommand{
+				Meta: meta,
+			}, nil
+		},
+		"config use-context": func() (cli.Command, error) {
+			return &ConfigUseContextCommand{
+				Meta: meta,
+			}, nil
+		},
+		"config current-context": func() (cli.Command, error) {
+			return &ConfigCurrentContextCommand{
+				Meta: meta,
+			}, nil
+		},
+		"config list-contexts": func() (cli.Command, error) {
+			return &ConfigListContextsCommand{
+				Meta: meta,
+			}, nil
+		},
+		"config delete-context": func() (cli.Command, error) {
+			return &ConfigDeleteContextCommand{
+				Meta: meta,
+			}, nil
+		},
 		// operator debug was released in 0.12 as debug. This top-level alias preserves compatibility
 		"debug": func() (cli.Command, error) {
 			return &OperatorDebugCommand{
@@ -347,6 +462,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job resume": func() (cli.Command, error) {
+			return &JobResumeCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job revert": func() (cli.Command, error) {
 			return &JobRevertCommand{
 				Meta: meta,
@@ -367,6 +487,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job suspend": func() (cli.Command, error) {
+			return &JobSuspendCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job validate": func() (cli.Command, error) {
 			return &JobValidateCommand{
 				Meta: meta,
@@ -382,11 +507,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"license inspect": func() (cli.Command, error) {
+			return &LicenseInspectCommand{
+				Meta: meta,
+			}, nil
+		},
 		"license put": func() (cli.Command, error) {
 			return &LicensePutCommand{
 				Meta: meta,
 			}, nil
 		},
+		"license reset": func() (cli.Command, error) {
+			return &LicenseResetCommand{
+				Meta: meta,
+			}, nil
+		},
 		"logs": func() (cli.Command, error) {
 			return &AllocLogsCommand{
 				Meta: meta,
@@ -495,6 +630,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator client-state": func() (cli.Command, error) {
+			return &OperatorClientStateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator keyring": func() (cli.Command, error) {
 			return &OperatorKeyringCommand{
 				Meta: meta,
@@ -533,11 +673,42 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator root": func() (cli.Command, error) {
+			return &OperatorRootCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring": func() (cli.Command, error) {
+			return &OperatorRootKeyringCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring list": func() (cli.Command, error) {
+			return &OperatorRootKeyringListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring remove": func() (cli.Command, error) {
+			return &OperatorRootKeyringRemoveCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring rotate": func() (cli.Command, error) {
+			return &OperatorRootKeyringRotateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"operator snapshot": func() (cli.Command, error) {
 			return &OperatorSnapshotCommand{
 				Meta: meta,
 			}, nil
 		},
+		"operator snapshot agent": func() (cli.Command, error) {
+			return &OperatorSnapshotAgentCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator snapshot save": func() (cli.Command, error) {
 			return &OperatorSnapshotSaveCommand{
 				Meta: meta,
@@ -643,6 +814,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"sentinel overrides": func() (cli.Command, error) {
+			return &SentinelOverridesCommand{
+				Meta: meta,
+			}, nil
+		},
+		"sentinel test": func() (cli.Command, error) {
+			return &SentinelTestCommand{
+				Meta: meta,
+			}, nil
+		},
 		"server": func() (cli.Command, error) {
 			return &ServerCommand{
 				Meta: meta,
@@ -718,6 +899,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var": func() (cli.Command, error) {
+			return &VarCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var get": func() (cli.Command, error) {
+			return &VarGetCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var list": func() (cli.Command, error) {
+			return &VarListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var purge": func() (cli.Command, error) {
+			return &VarPurgeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var put": func() (cli.Command, error) {
+			return &VarPutCommand{
+				Meta: meta,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				Version: version.GetVersion(),
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ConfigCommand struct {
+	Meta
+}
+
+func (f *ConfigCommand) Help() string {
+	helpText := `
+Usage: nomad config <subcommand> [options] [args]
+
+  This command groups subcommands for managing named CLI contexts. A context
+  stores the connection settings for a cluster, such as the address, region,
+  namespace, CA certificate and token helper, so operators of several
+  clusters can switch between them without exporting environment variables.
+
+  Create or update a context:
+
+      $ nomad config set-context prod -address=https://prod.example.com:4646
+
+  Switch to a context:
+
+      $ nomad config use-context prod
+
+  Flags and environment variables always take precedence over the active
+  context. The NOMAD_CONTEXT environment variable selects a context for a
+  single invocation without changing the stored current context.
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ConfigCommand) Synopsis() string {
+	return "Manage CLI contexts for multiple clusters"
+}
+
+func (f *ConfigCommand) Name() string { return "config" }
+
+func (f *ConfigCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
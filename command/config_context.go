@@ -0,0 +1,132 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// EnvContext is the environment variable that selects the active CLI
+// context, overriding the current context stored in the contexts file.
+const EnvContext = "NOMAD_CONTEXT"
+
+// cliContext is a named connection profile for a cluster. Values from the
+// active context are used when the corresponding flag or environment
+// variable is not set.
+type cliContext struct {
+	// Address is the address of the Nomad server
+	Address string `json:"address,omitempty"`
+
+	// Region is the region to forward commands to
+	Region string `json:"region,omitempty"`
+
+	// Namespace is the target namespace for namespaced commands
+	Namespace string `json:"namespace,omitempty"`
+
+	// CACert is the path to a PEM encoded CA cert file used to verify the
+	// server SSL certificate
+	CACert string `json:"ca_cert,omitempty"`
+
+	// CAPath is the path to a directory of PEM encoded CA cert files
+	CAPath string `json:"ca_path,omitempty"`
+
+	// TokenHelper is the token helper command used to fetch the ACL token
+	TokenHelper string `json:"token_helper,omitempty"`
+}
+
+// cliContextFile is the on-disk set of CLI contexts, stored at
+// ~/.nomad/contexts.json and managed with the "nomad config" subcommands.
+type cliContextFile struct {
+	// CurrentContext is the name of the active context
+	CurrentContext string `json:"current_context,omitempty"`
+
+	// Contexts maps context names to their connection profiles
+	Contexts map[string]*cliContext `json:"contexts"`
+}
+
+// cliContextPath returns the path of the contexts file.
+func cliContextPath() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %v", err)
+	}
+	return filepath.Join(home, ".nomad", "contexts.json"), nil
+}
+
+// loadContextFile reads the contexts file, returning an empty set if the
+// file does not exist yet.
+func loadContextFile() (*cliContextFile, error) {
+	file := &cliContextFile{Contexts: make(map[string]*cliContext)}
+
+	path, err := cliContextPath()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(raw, file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if file.Contexts == nil {
+		file.Contexts = make(map[string]*cliContext)
+	}
+	return file, nil
+}
+
+// write persists the contexts file, creating ~/.nomad if necessary.
+func (f *cliContextFile) write() error {
+	path, err := cliContextPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// contextNameFromEnvOr returns the context name from the NOMAD_CONTEXT
+// environment variable, falling back to the given stored name.
+func contextNameFromEnvOr(stored string) string {
+	if name := os.Getenv(EnvContext); name != "" {
+		return name
+	}
+	return stored
+}
+
+// activeCLIContext returns the active context, preferring the NOMAD_CONTEXT
+// environment variable over the stored current context. It returns nil if
+// no context is active or the contexts file cannot be read, so commands
+// keep working without one.
+func activeCLIContext() *cliContext {
+	file, err := loadContextFile()
+	if err != nil {
+		return nil
+	}
+
+	name := contextNameFromEnvOr(file.CurrentContext)
+	if name == "" {
+		return nil
+	}
+	return file.Contexts[name]
+}
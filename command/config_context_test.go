@@ -0,0 +1,137 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/require"
+)
+
+// testContextHome points the context file at a temp home directory for the
+// duration of a test.
+func testContextHome(t *testing.T) func() {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "nomad-contexts")
+	require.NoError(t, err)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+	homedir.Reset()
+
+	return func() {
+		os.Setenv("HOME", oldHome)
+		homedir.Reset()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestConfigContext_Commands(t *testing.T) {
+	defer testContextHome(t)()
+	require := require.New(t)
+
+	ui := cli.NewMockUi()
+
+	// Creating the first context makes it current
+	set := &ConfigSetContextCommand{Meta: Meta{Ui: ui}}
+	require.Zero(set.Run([]string{"-address=https://prod.example.com:4646", "-region=east", "prod"}))
+	require.Contains(ui.OutputWriter.String(), `Context "prod" saved`)
+
+	require.Zero(set.Run([]string{"-address=https://staging.example.com:4646", "staging"}))
+
+	current := &ConfigCurrentContextCommand{Meta: Meta{Ui: ui}}
+	ui.OutputWriter.Reset()
+	require.Zero(current.Run(nil))
+	require.Contains(ui.OutputWriter.String(), "prod")
+
+	// Switching contexts
+	use := &ConfigUseContextCommand{Meta: Meta{Ui: ui}}
+	require.Zero(use.Run([]string{"staging"}))
+	ui.OutputWriter.Reset()
+	require.Zero(current.Run(nil))
+	require.Contains(ui.OutputWriter.String(), "staging")
+
+	// Unknown contexts are an error
+	require.Equal(1, use.Run([]string{"nope"}))
+	require.Contains(ui.ErrorWriter.String(), `No context named "nope"`)
+
+	// Listing marks the current context
+	list := &ConfigListContextsCommand{Meta: Meta{Ui: ui}}
+	ui.OutputWriter.Reset()
+	require.Zero(list.Run(nil))
+	out := ui.OutputWriter.String()
+	require.Contains(out, "prod")
+	require.Contains(out, "https://staging.example.com:4646")
+
+	// Deleting the current context clears it
+	del := &ConfigDeleteContextCommand{Meta: Meta{Ui: ui}}
+	require.Zero(del.Run([]string{"staging"}))
+	require.Equal(1, current.Run(nil))
+
+	file, err := loadContextFile()
+	require.NoError(err)
+	require.Len(file.Contexts, 1)
+	require.Empty(file.CurrentContext)
+}
+
+func TestConfigContext_SetContextMerges(t *testing.T) {
+	defer testContextHome(t)()
+	require := require.New(t)
+
+	ui := cli.NewMockUi()
+	set := &ConfigSetContextCommand{Meta: Meta{Ui: ui}}
+	require.Zero(set.Run([]string{"-address=https://prod.example.com:4646", "prod"}))
+	require.Zero(set.Run([]string{"-namespace=web", "prod"}))
+
+	file, err := loadContextFile()
+	require.NoError(err)
+	context := file.Contexts["prod"]
+	require.NotNil(context)
+	require.Equal("https://prod.example.com:4646", context.Address)
+	require.Equal("web", context.Namespace)
+}
+
+func TestConfigContext_ClientConfig(t *testing.T) {
+	defer testContextHome(t)()
+	require := require.New(t)
+
+	ui := cli.NewMockUi()
+	set := &ConfigSetContextCommand{Meta: Meta{Ui: ui}}
+	require.Zero(set.Run([]string{
+		"-address=https://prod.example.com:4646",
+		"-region=east",
+		"-namespace=web",
+		"prod",
+	}))
+
+	// The active context supplies defaults
+	m := &Meta{Ui: ui}
+	config := m.clientConfig()
+	require.Equal("https://prod.example.com:4646", config.Address)
+	require.Equal("east", config.Region)
+	require.Equal("web", config.Namespace)
+
+	// Flags take precedence over the context
+	m.flagAddress = "https://other.example.com:4646"
+	m.namespace = "batch"
+	config = m.clientConfig()
+	require.Equal("https://other.example.com:4646", config.Address)
+	require.Equal("east", config.Region)
+	require.Equal("batch", config.Namespace)
+
+	// The environment takes precedence over the context
+	os.Setenv("NOMAD_REGION", "west")
+	defer os.Unsetenv("NOMAD_REGION")
+	config = m.clientConfig()
+	require.Equal("west", config.Region)
+
+	// NOMAD_CONTEXT selects a context without changing the stored one
+	require.Zero(set.Run([]string{"-address=https://staging.example.com:4646", "staging"}))
+	os.Setenv(EnvContext, "staging")
+	defer os.Unsetenv(EnvContext)
+	config = (&Meta{Ui: ui}).clientConfig()
+	require.Equal("https://staging.example.com:4646", config.Address)
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ConfigCurrentContextCommand struct {
+	Meta
+}
+
+func (c *ConfigCurrentContextCommand) Help() string {
+	helpText := `
+Usage: nomad config current-context
+
+  Print the name of the current CLI context. The NOMAD_CONTEXT environment
+  variable, if set, takes precedence over the stored current context.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigCurrentContextCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigCurrentContextCommand) Synopsis() string {
+	return "Print the current CLI context"
+}
+
+func (c *ConfigCurrentContextCommand) Name() string { return "config current-context" }
+
+func (c *ConfigCurrentContextCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	file, err := loadContextFile()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading contexts: %s", err))
+		return 1
+	}
+
+	name := contextNameFromEnvOr(file.CurrentContext)
+	if name == "" {
+		c.Ui.Error("No current context is set")
+		return 1
+	}
+
+	c.Ui.Output(name)
+	return 0
+}
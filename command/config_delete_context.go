@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ConfigDeleteContextCommand struct {
+	Meta
+}
+
+func (c *ConfigDeleteContextCommand) Help() string {
+	helpText := `
+Usage: nomad config delete-context <name>
+
+  Delete a named CLI context. If the deleted context was the current
+  context, no context is active until another is selected with
+  "nomad config use-context".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigDeleteContextCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigDeleteContextCommand) Synopsis() string {
+	return "Delete a CLI context"
+}
+
+func (c *ConfigDeleteContextCommand) Name() string { return "config delete-context" }
+
+func (c *ConfigDeleteContextCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	file, err := loadContextFile()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading contexts: %s", err))
+		return 1
+	}
+
+	if _, ok := file.Contexts[name]; !ok {
+		c.Ui.Error(fmt.Sprintf("No context named %q", name))
+		return 1
+	}
+
+	delete(file.Contexts, name)
+	if file.CurrentContext == name {
+		file.CurrentContext = ""
+	}
+
+	if err := file.write(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error saving contexts: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Deleted context %q", name))
+	return 0
+}
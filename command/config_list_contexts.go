@@ -0,0 +1,80 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ConfigListContextsCommand struct {
+	Meta
+}
+
+func (c *ConfigListContextsCommand) Help() string {
+	helpText := `
+Usage: nomad config list-contexts
+
+  List the configured CLI contexts. The current context is marked with an
+  asterisk.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigListContextsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigListContextsCommand) Synopsis() string {
+	return "List the configured CLI contexts"
+}
+
+func (c *ConfigListContextsCommand) Name() string { return "config list-contexts" }
+
+func (c *ConfigListContextsCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	file, err := loadContextFile()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading contexts: %s", err))
+		return 1
+	}
+
+	if len(file.Contexts) == 0 {
+		c.Ui.Output("No contexts are configured")
+		return 0
+	}
+
+	names := make([]string, 0, len(file.Contexts))
+	for name := range file.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	current := contextNameFromEnvOr(file.CurrentContext)
+
+	out := make([]string, 0, len(names)+1)
+	out = append(out, "Current|Name|Address|Region|Namespace")
+	for _, name := range names {
+		context := file.Contexts[name]
+		marker := ""
+		if name == current {
+			marker = "*"
+		}
+		out = append(out, fmt.Sprintf("%s|%s|%s|%s|%s",
+			marker, name, context.Address, context.Region, context.Namespace))
+	}
+	c.Ui.Output(formatList(out))
+	return 0
+}
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ConfigSetContextCommand struct {
+	Meta
+}
+
+func (c *ConfigSetContextCommand) Help() string {
+	helpText := `
+Usage: nomad config set-context <name> [options]
+
+  Create or update a named CLI context. Options that are set are merged into
+  the existing context of the same name, so a context can be built up over
+  several invocations. The first context created becomes the current context.
+
+Set Context Options:
+
+  -address=<addr>
+    The address of the Nomad server for this context.
+
+  -region=<region>
+    The region of the Nomad servers to forward commands to.
+
+  -namespace=<namespace>
+    The target namespace for queries and actions bound to a namespace.
+
+  -ca-cert=<path>
+    Path to a PEM encoded CA cert file to use to verify the Nomad server
+    SSL certificate.
+
+  -ca-path=<path>
+    Path to a directory of PEM encoded CA cert files to verify the Nomad
+    server SSL certificate.
+
+  -token-helper=<command>
+    Token helper command used to fetch the ACL token for this context.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigSetContextCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-address":      complete.PredictAnything,
+		"-region":       complete.PredictAnything,
+		"-namespace":    complete.PredictAnything,
+		"-ca-cert":      complete.PredictFiles("*"),
+		"-ca-path":      complete.PredictDirs("*"),
+		"-token-helper": complete.PredictAnything,
+	}
+}
+
+func (c *ConfigSetContextCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigSetContextCommand) Synopsis() string {
+	return "Create or update a CLI context"
+}
+
+func (c *ConfigSetContextCommand) Name() string { return "config set-context" }
+
+func (c *ConfigSetContextCommand) Run(args []string) int {
+	var address, region, namespace, caCert, caPath, tokenHelper string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&address, "address", "", "")
+	flags.StringVar(&region, "region", "", "")
+	flags.StringVar(&namespace, "namespace", "", "")
+	flags.StringVar(&caCert, "ca-cert", "", "")
+	flags.StringVar(&caPath, "ca-path", "", "")
+	flags.StringVar(&tokenHelper, "token-helper", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	file, err := loadContextFile()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading contexts: %s", err))
+		return 1
+	}
+
+	context := file.Contexts[name]
+	if context == nil {
+		context = &cliContext{}
+		file.Contexts[name] = context
+	}
+
+	if address != "" {
+		context.Address = address
+	}
+	if region != "" {
+		context.Region = region
+	}
+	if namespace != "" {
+		context.Namespace = namespace
+	}
+	if caCert != "" {
+		context.CACert = caCert
+	}
+	if caPath != "" {
+		context.CAPath = caPath
+	}
+	if tokenHelper != "" {
+		context.TokenHelper = tokenHelper
+	}
+
+	// The first context created becomes the current context
+	if file.CurrentContext == "" {
+		file.CurrentContext = name
+	}
+
+	if err := file.write(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error saving contexts: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Context %q saved", name))
+	return 0
+}
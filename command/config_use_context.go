@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ConfigUseContextCommand struct {
+	Meta
+}
+
+func (c *ConfigUseContextCommand) Help() string {
+	helpText := `
+Usage: nomad config use-context <name>
+
+  Switch the current CLI context. Subsequent commands use the connection
+  settings of the named context unless overridden by flags or environment
+  variables.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigUseContextCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		file, err := loadContextFile()
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(file.Contexts))
+		for name := range file.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	})
+}
+
+func (c *ConfigUseContextCommand) Synopsis() string {
+	return "Switch the current CLI context"
+}
+
+func (c *ConfigUseContextCommand) Name() string { return "config use-context" }
+
+func (c *ConfigUseContextCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	file, err := loadContextFile()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading contexts: %s", err))
+		return 1
+	}
+
+	if _, ok := file.Contexts[name]; !ok {
+		c.Ui.Error(fmt.Sprintf("No context named %q, create it with \"nomad config set-context\"", name))
+		return 1
+	}
+
+	file.CurrentContext = name
+	if err := file.write(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error saving contexts: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Now using context %q", name))
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
@@ -36,6 +37,13 @@ Status Options:
 
   -t
     Format and display deployment using a Go template.
+
+  -watch
+    Continuously update the status output using blocking queries.
+
+  -monitor-interval
+    How often to refresh the output when watching, and the upper bound on
+    how long a blocking query waits. Defaults to 2s.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -47,9 +55,11 @@ func (c *DeploymentStatusCommand) Synopsis() string {
 func (c *DeploymentStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-verbose": complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-verbose":          complete.PredictNothing,
+			"-json":             complete.PredictNothing,
+			"-t":                complete.PredictAnything,
+			"-watch":            complete.PredictNothing,
+			"-monitor-interval": complete.PredictAnything,
 		})
 }
 
@@ -71,19 +81,27 @@ func (c *DeploymentStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *DeploymentStatusCommand) Name() string { return "deployment status" }
 
 func (c *DeploymentStatusCommand) Run(args []string) int {
-	var json, verbose bool
+	var json, verbose, watch bool
 	var tmpl string
+	var monitorInterval time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&watch, "watch", false, "")
+	flags.DurationVar(&monitorInterval, "monitor-interval", defaultMonitorInterval, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if watch && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -watch flag cannot be used with -json or -t")
+		return 1
+	}
+
 	// Check that we got exactly one argument
 	args = flags.Args()
 	if l := len(args); l > 1 {
@@ -141,6 +159,19 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 0
 	}
 
+	if watch {
+		return c.Meta.watch(monitorInterval, func(q *api.QueryOptions) (uint64, error) {
+			deploy, meta, err := client.Deployments().Info(deploy.ID, q)
+			if err != nil {
+				return 0, fmt.Errorf("Error retrieving deployment: %s", err)
+			}
+
+			c.clearScreen()
+			c.Ui.Output(c.Colorize().Color(formatDeployment(client, deploy, length)))
+			return meta.LastIndex, nil
+		})
+	}
+
 	c.Ui.Output(c.Colorize().Color(formatDeployment(client, deploy, length)))
 	return 0
 }
@@ -14,6 +14,7 @@ import (
 	gg "github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/kr/text"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -378,12 +379,81 @@ READ:
 }
 
 type JobGetter struct {
+	// HCL1 forces the HCL1 parser, skipping HCL2 variable and function
+	// evaluation.
+	HCL1 bool
+
+	// Vars are variable assignments from -var flags of the form
+	// "name=value".
+	Vars []string
+
+	// VarFiles are paths to variable assignment files from -var-file
+	// flags.
+	VarFiles []string
+
+	// submission is the original source material of the last parsed job
+	// file, recorded so it can be stored with the registration.
+	submission *api.JobSubmission
+
 	// The fields below can be overwritten for tests
 	testStdin io.Reader
 }
 
+// Submission returns the original source material of the last job file
+// parsed by ApiJob, or nil if no job has been parsed.
+func (j *JobGetter) Submission() *api.JobSubmission {
+	return j.submission
+}
+
+// recordSubmission captures the job file source and variable assignments
+// for storage alongside the registered job.
+func (j *JobGetter) recordSubmission(jpath string, buf []byte) {
+	format := "hcl2"
+	switch {
+	case j.HCL1:
+		format = "hcl1"
+	case strings.HasSuffix(jpath, ".json"):
+		format = "json"
+	}
+
+	var flags map[string]string
+	if len(j.Vars) != 0 {
+		flags = make(map[string]string, len(j.Vars))
+		for _, arg := range j.Vars {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) == 2 {
+				flags[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	var varFileContent strings.Builder
+	for _, vf := range j.VarFiles {
+		content, err := ioutil.ReadFile(vf)
+		if err != nil {
+			continue
+		}
+		varFileContent.Write(content)
+		varFileContent.WriteString("\n")
+	}
+
+	j.submission = &api.JobSubmission{
+		Source:        string(buf),
+		Format:        format,
+		VariableFlags: flags,
+		Variables:     varFileContent.String(),
+	}
+}
+
 // StructJob returns the Job struct from jobfile.
 func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+	return j.ApiJobWithIncludes(jpath, nil)
+}
+
+// ApiJobWithIncludes returns the Job struct from jobfile with job fragments
+// from the include paths merged in. The job file always wins over the
+// fragments; among fragments the lexically first file to set a value wins.
+func (j *JobGetter) ApiJobWithIncludes(jpath string, includes []string) (*api.Job, error) {
 	var jobfile io.Reader
 	switch jpath {
 	case "-":
@@ -432,11 +502,44 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 	}
 
 	// Parse the JobFile
-	jobStruct, err := jobspec.Parse(jobfile)
+	buf, err := ioutil.ReadAll(jobfile)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+
+	var jobStruct *api.Job
+	if j.HCL1 {
+		jobStruct, err = jobspec.Parse(bytes.NewReader(buf))
+	} else {
+		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+			Path:     jpath,
+			Body:     buf,
+			ArgVars:  j.Vars,
+			VarFiles: j.VarFiles,
+			Envs:     os.Environ(),
+			AllowFS:  true,
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
 	}
 
+	// Record the source material so it can be submitted with the job
+	j.recordSubmission(jpath, buf)
+
+	// Merge include fragments into the job
+	files, err := jobspec.CollectIncludes(includes)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		fragment, err := jobspec.ParseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing include %q: %v", file, err)
+		}
+		jobspec.MergeJob(jobStruct, fragment)
+	}
+
 	return jobStruct, nil
 }
 
@@ -32,6 +32,10 @@ Inspect Options:
   -json
     Output the job in its JSON format.
 
+  -hcl
+    Output the original job specification exactly as it was submitted,
+    if the source was stored with the registration.
+
   -t
     Format and display job using a Go template.
 `
@@ -47,6 +51,7 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
+			"-hcl":     complete.PredictNothing,
 			"-t":       complete.PredictAnything,
 		})
 }
@@ -69,12 +74,13 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, hcl bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&hcl, "hcl", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
 
@@ -149,6 +155,21 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output the stored submission source rather than the normalized job
+	if hcl {
+		var q *api.QueryOptions
+		if ns := jobs[0].JobSummary.Namespace; ns != "" {
+			q = &api.QueryOptions{Namespace: ns}
+		}
+		sub, _, err := client.Jobs().Submission(*job.ID, *job.Version, q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving job submission: %s", err))
+			return 1
+		}
+		c.Ui.Output(sub.Source)
+		return 0
+	}
+
 	// If output format is specified, format and output the data
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, job)
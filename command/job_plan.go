@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/posener/complete"
 )
@@ -77,6 +78,18 @@ Plan Options:
 
   -verbose
     Increase diff verbosity.
+
+  -hcl1
+    Parse the job file with the HCL1 parser, skipping HCL2 variable and
+    function evaluation.
+
+  -var 'name=value'
+    Set the value of an HCL2 variable declared in the job file. May be
+    specified multiple times.
+
+  -var-file
+    Path to an HCL file of variable assignments. May be specified multiple
+    times. Values from -var flags take precedence.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -91,6 +104,9 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 			"-diff":            complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.var"),
 		})
 }
 
@@ -107,6 +123,9 @@ func (c *JobPlanCommand) Run(args []string) int {
 	flags.BoolVar(&diff, "diff", true, "")
 	flags.BoolVar(&policyOverride, "policy-override", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.Vars), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.VarFiles), "var-file", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 255
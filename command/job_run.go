@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/helper"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/posener/complete"
 )
 
@@ -79,6 +80,12 @@ Run Options:
     the evaluation ID will be printed to the screen, which can be used to
     examine the evaluation using the eval-status command.
 
+  -include
+    Path to a job fragment file, or a directory of .hcl and .nomad fragment
+    files, merged into the job before submission. May be specified multiple
+    times. Values set in the job file always win; among fragments the
+    lexically first file to set a value wins.
+
   -output
     Output the JSON that would be submitted to the HTTP API without submitting
     the job.
@@ -107,6 +114,18 @@ Run Options:
 
   -verbose
     Display full information.
+
+  -hcl1
+    Parse the job file with the HCL1 parser, skipping HCL2 variable and
+    function evaluation.
+
+  -var 'name=value'
+    Set the value of an HCL2 variable declared in the job file. May be
+    specified multiple times.
+
+  -var-file
+    Path to an HCL file of variable assignments. May be specified multiple
+    times. Values from -var flags take precedence.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -120,6 +139,7 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-check-index":     complete.PredictNothing,
 			"-detach":          complete.PredictNothing,
+			"-include":         complete.PredictOr(complete.PredictDirs("*"), complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl")),
 			"-verbose":         complete.PredictNothing,
 			"-consul-token":    complete.PredictNothing,
 			"-vault-token":     complete.PredictAnything,
@@ -127,6 +147,9 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 			"-output":          complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-preserve-counts": complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.var"),
 		})
 }
 
@@ -139,6 +162,7 @@ func (c *JobRunCommand) Name() string { return "job run" }
 func (c *JobRunCommand) Run(args []string) int {
 	var detach, verbose, output, override, preserveCounts bool
 	var checkIndexStr, consulToken, vaultToken, vaultNamespace string
+	var includes []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -151,6 +175,10 @@ func (c *JobRunCommand) Run(args []string) int {
 	flags.StringVar(&consulToken, "consul-token", "", "")
 	flags.StringVar(&vaultToken, "vault-token", "", "")
 	flags.StringVar(&vaultNamespace, "vault-namespace", "", "")
+	flags.Var((*flaghelper.StringFlag)(&includes), "include", "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.Vars), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.VarFiles), "var-file", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -171,7 +199,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, err := c.JobGetter.ApiJobWithIncludes(args[0], includes)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -254,6 +282,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 	opts.PolicyOverride = override
 	opts.PreserveCounts = preserveCounts
+	opts.Submission = c.JobGetter.Submission()
 
 	// Submit the job
 	resp, _, err := client.Jobs().RegisterOpts(job, opts, nil)
@@ -52,6 +52,13 @@ Status Options:
 
   -verbose
     Display full information.
+
+  -watch
+    Continuously update the status output using blocking queries.
+
+  -monitor-interval
+    How often to refresh the output when watching, and the upper bound on
+    how long a blocking query waits. Defaults to 2s.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -63,10 +70,12 @@ func (c *JobStatusCommand) Synopsis() string {
 func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-all-allocs": complete.PredictNothing,
-			"-evals":      complete.PredictNothing,
-			"-short":      complete.PredictNothing,
-			"-verbose":    complete.PredictNothing,
+			"-all-allocs":       complete.PredictNothing,
+			"-evals":            complete.PredictNothing,
+			"-short":            complete.PredictNothing,
+			"-verbose":          complete.PredictNothing,
+			"-watch":            complete.PredictNothing,
+			"-monitor-interval": complete.PredictAnything,
 		})
 }
 
@@ -88,7 +97,8 @@ func (c *JobStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobStatusCommand) Name() string { return "status" }
 
 func (c *JobStatusCommand) Run(args []string) int {
-	var short bool
+	var short, watch bool
+	var monitorInterval time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -96,6 +106,8 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.BoolVar(&watch, "watch", false, "")
+	flags.DurationVar(&monitorInterval, "monitor-interval", defaultMonitorInterval, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -159,6 +171,22 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 	// Prefix lookup matched a single job
+	if watch {
+		return c.Meta.watch(monitorInterval, func(q *api.QueryOptions) (uint64, error) {
+			q.Namespace = jobs[0].JobSummary.Namespace
+			job, meta, err := client.Jobs().Info(jobs[0].ID, q)
+			if err != nil {
+				return 0, fmt.Errorf("Error querying job: %s", err)
+			}
+
+			c.clearScreen()
+			if err := c.outputJobStatus(client, job, short); err != nil {
+				return 0, err
+			}
+			return meta.LastIndex, nil
+		})
+	}
+
 	q := &api.QueryOptions{Namespace: jobs[0].JobSummary.Namespace}
 	job, _, err := client.Jobs().Info(jobs[0].ID, q)
 	if err != nil {
@@ -166,6 +194,16 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.outputJobStatus(client, job, short); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// outputJobStatus renders the full status output for a single job.
+func (c *JobStatusCommand) outputJobStatus(client *api.Client, job *api.Job, short bool) error {
 	periodic := job.IsPeriodic()
 	parameterized := job.IsParameterized()
 
@@ -183,6 +221,18 @@ func (c *JobStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Parameterized|%v", parameterized),
 	}
 
+	if job.Ownership != nil {
+		if *job.Ownership.Team != "" {
+			basic = append(basic, fmt.Sprintf("Owner Team|%s", *job.Ownership.Team))
+		}
+		if *job.Ownership.Contact != "" {
+			basic = append(basic, fmt.Sprintf("Owner Contact|%s", *job.Ownership.Contact))
+		}
+		if *job.Ownership.RepoURL != "" {
+			basic = append(basic, fmt.Sprintf("Owner Repo|%s", *job.Ownership.RepoURL))
+		}
+	}
+
 	if periodic && !parameterized {
 		if *job.Stop {
 			basic = append(basic, fmt.Sprintf("Next Periodic Launch|none (job stopped)"))
@@ -204,28 +254,17 @@ func (c *JobStatusCommand) Run(args []string) int {
 
 	// Exit early
 	if short {
-		return 0
+		return nil
 	}
 
 	// Print periodic job information
 	if periodic && !parameterized {
-		if err := c.outputPeriodicInfo(client, job); err != nil {
-			c.Ui.Error(err.Error())
-			return 1
-		}
+		return c.outputPeriodicInfo(client, job)
 	} else if parameterized {
-		if err := c.outputParameterizedInfo(client, job); err != nil {
-			c.Ui.Error(err.Error())
-			return 1
-		}
-	} else {
-		if err := c.outputJobInfo(client, job); err != nil {
-			c.Ui.Error(err.Error())
-			return 1
-		}
+		return c.outputParameterizedInfo(client, job)
 	}
 
-	return 0
+	return c.outputJobInfo(client, job)
 }
 
 // outputPeriodicInfo prints information about the passed periodic job. If a
@@ -347,6 +386,7 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	// Determine latest evaluation with failures whose follow up hasn't
 	// completed, this is done while formatting
 	var latestFailedPlacement *api.Evaluation
+	var latestBlockedEval *api.Evaluation
 	blockedEval := false
 
 	// Format the evals
@@ -364,6 +404,9 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 
 		if eval.Status == "blocked" {
 			blockedEval = true
+			if latestBlockedEval == nil || latestBlockedEval.CreateIndex < eval.CreateIndex {
+				latestBlockedEval = eval
+			}
 		}
 
 		if len(eval.FailedTGAllocs) == 0 {
@@ -385,6 +428,17 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		c.outputFailedPlacements(latestFailedPlacement)
 	}
 
+	// Output queue hints for the latest blocked eval so the user knows
+	// whether to wait for capacity or escalate.
+	if latestBlockedEval != nil && latestBlockedEval.QueuePosition > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Queued[reset]"))
+		c.Ui.Output(fmt.Sprintf("Queue Position: %d", latestBlockedEval.QueuePosition))
+		if !latestBlockedEval.EarliestStart.IsZero() {
+			c.Ui.Output(fmt.Sprintf("Earliest Start: %s (based on pending terminations)",
+				formatTime(latestBlockedEval.EarliestStart)))
+		}
+	}
+
 	c.outputReschedulingEvals(client, job, jobAllocs, c.length)
 
 	if latestDeployment != nil {
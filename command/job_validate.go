@@ -7,6 +7,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
@@ -27,6 +28,20 @@ Alias: nomad validate
   If the supplied path is "-", the jobfile is read from stdin. Otherwise
   it is read from the file at the supplied path or downloaded and
   read from URL specified.
+
+Validate Options:
+
+  -hcl1
+    Parse the job file with the HCL1 parser, skipping HCL2 variable and
+    function evaluation.
+
+  -var 'name=value'
+    Set the value of an HCL2 variable declared in the job file. May be
+    specified multiple times.
+
+  -var-file
+    Path to an HCL file of variable assignments. May be specified multiple
+    times. Values from -var flags take precedence.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -36,7 +51,11 @@ func (c *JobValidateCommand) Synopsis() string {
 }
 
 func (c *JobValidateCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-hcl1":     complete.PredictNothing,
+		"-var":      complete.PredictAnything,
+		"-var-file": complete.PredictFiles("*.var"),
+	}
 }
 
 func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
@@ -48,6 +67,9 @@ func (c *JobValidateCommand) Name() string { return "job validate" }
 func (c *JobValidateCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.Vars), "var", "")
+	flags.Var((*flaghelper.StringFlag)(&c.JobGetter.VarFiles), "var-file", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"strings"
 )
 
 type LicenseGetCommand struct {
@@ -15,9 +16,18 @@ Usage: nomad license get [options]
 Gets a new license in Servers and Clients
 General Options:
 
-  ` + generalOptionsUsage()
+  ` + generalOptionsUsage() + `
 
-	return helpText
+Get Options:
+
+  -json
+    Output the license in its JSON format.
+
+  -t
+    Format and display the license using a Go template.
+	`
+
+	return strings.TrimSpace(helpText)
 }
 
 func (c *LicenseGetCommand) Synopsis() string {
@@ -27,8 +37,13 @@ func (c *LicenseGetCommand) Synopsis() string {
 func (c *LicenseGetCommand) Name() string { return "license get" }
 
 func (c *LicenseGetCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing flags: %s", err))
@@ -47,5 +62,16 @@ func (c *LicenseGetCommand) Run(args []string) int {
 		return 1
 	}
 
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
 	return OutputLicenseReply(c.Ui, resp)
 }
@@ -24,8 +24,9 @@ func TestCommand_LicenseGet_OSSErr(t *testing.T) {
 	if srv.Enterprise {
 		require.Equal(t, 0, code)
 	} else {
+		// No license has been installed
 		require.Equal(t, 1, code)
-		require.Contains(t, ui.ErrorWriter.String(), "Nomad Enterprise only endpoint")
+		require.Contains(t, ui.ErrorWriter.String(), "no license stored")
 	}
 }
 
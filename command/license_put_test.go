@@ -26,7 +26,8 @@ func TestCommand_LicensePut_Err(t *testing.T) {
 	if srv.Enterprise {
 		require.Contains(t, ui.ErrorWriter.String(), "error validating license")
 	} else {
-		require.Contains(t, ui.ErrorWriter.String(), "Nomad Enterprise only endpoint")
+		// The blob is not a valid license
+		require.Contains(t, ui.ErrorWriter.String(), "error decoding license")
 	}
 
 }
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+type LicenseResetCommand struct {
+	Meta
+}
+
+func (c *LicenseResetCommand) Help() string {
+	helpText := `
+Usage: nomad license reset
+
+Resets the current license, removing it from the Servers so the cluster
+falls back to a temporary license when still in its grace window.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LicenseResetCommand) Synopsis() string {
+	return "Reset the current Nomad Enterprise License"
+}
+
+func (c *LicenseResetCommand) Name() string { return "license reset" }
+
+func (c *LicenseResetCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing flags: %s", err))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Operator().LicenseReset(nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error resetting license: %v", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully reset license")
+	return 0
+}
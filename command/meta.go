@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/token"
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/colorstring"
 	"github.com/posener/complete"
@@ -115,6 +116,29 @@ type ApiClientFactory func() (*api.Client, error)
 // the default command line arguments and env vars.
 func (m *Meta) clientConfig() *api.Config {
 	config := api.DefaultConfig()
+
+	// Apply the active CLI context, if any. Flags and environment
+	// variables always take precedence over the context.
+	var ctxTokenHelper string
+	if ctx := activeCLIContext(); ctx != nil {
+		if ctx.Address != "" && m.flagAddress == "" && os.Getenv("NOMAD_ADDR") == "" {
+			config.Address = ctx.Address
+		}
+		if ctx.Region != "" && m.region == "" && os.Getenv("NOMAD_REGION") == "" {
+			config.Region = ctx.Region
+		}
+		if ctx.Namespace != "" && m.namespace == "" && os.Getenv("NOMAD_NAMESPACE") == "" {
+			config.Namespace = ctx.Namespace
+		}
+		if ctx.CACert != "" && m.caCert == "" && os.Getenv("NOMAD_CACERT") == "" {
+			config.TLSConfig.CACert = ctx.CACert
+		}
+		if ctx.CAPath != "" && m.caPath == "" && os.Getenv("NOMAD_CAPATH") == "" {
+			config.TLSConfig.CAPath = ctx.CAPath
+		}
+		ctxTokenHelper = ctx.TokenHelper
+	}
+
 	if m.flagAddress != "" {
 		config.Address = m.flagAddress
 	}
@@ -142,6 +166,24 @@ func (m *Meta) clientConfig() *api.Config {
 		config.SecretID = m.token
 	}
 
+	// If no token was given by flag or environment, ask the configured
+	// token helper, if any. The NOMAD_TOKEN_HELPER environment variable
+	// takes precedence over the token helper of the active context.
+	if config.SecretID == "" {
+		helper := token.NewExternalHelper(config.Address)
+		if helper == nil && ctxTokenHelper != "" {
+			helper = &token.ExternalHelper{
+				Command: ctxTokenHelper,
+				Address: config.Address,
+			}
+		}
+		if helper != nil {
+			if t, err := helper.Get(); err == nil {
+				config.SecretID = t
+			}
+		}
+	}
+
 	return config
 }
 
@@ -216,7 +258,9 @@ func generalOptionsUsage() string {
 
   -token
     The SecretID of an ACL token to use to authenticate API requests with.
-    Overrides the NOMAD_TOKEN environment variable if set.
+    Overrides the NOMAD_TOKEN environment variable if set. If neither is
+    set, the token helper program named by NOMAD_TOKEN_HELPER is asked for
+    a token for the current address.
 `
 	return strings.TrimSpace(helpText)
 }
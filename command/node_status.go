@@ -32,7 +32,11 @@ type NodeStatusCommand struct {
 	self        bool
 	stats       bool
 	json        bool
+	osInventory bool
 	tmpl        string
+
+	watch           bool
+	monitorInterval time.Duration
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -73,8 +77,18 @@ Node Status Options:
   -json
     Output the node in its JSON format.
 
+  -os-inventory
+    Display the fleet OS inventory baseline and the nodes drifting from it.
+
   -t
     Format and display node using a Go template.
+
+  -watch
+    Continuously update the status output using blocking queries.
+
+  -monitor-interval
+    How often to refresh the output when watching, and the upper bound on
+    how long a blocking query waits. Defaults to 2s.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -86,13 +100,16 @@ func (c *NodeStatusCommand) Synopsis() string {
 func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-allocs":  complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-self":    complete.PredictNothing,
-			"-short":   complete.PredictNothing,
-			"-stats":   complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-allocs":           complete.PredictNothing,
+			"-json":             complete.PredictNothing,
+			"-monitor-interval": complete.PredictAnything,
+			"-os-inventory":     complete.PredictNothing,
+			"-self":             complete.PredictNothing,
+			"-short":            complete.PredictNothing,
+			"-stats":            complete.PredictNothing,
+			"-t":                complete.PredictAnything,
+			"-verbose":          complete.PredictNothing,
+			"-watch":            complete.PredictNothing,
 		})
 }
 
@@ -123,12 +140,20 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
+	flags.BoolVar(&c.osInventory, "os-inventory", false, "")
 	flags.StringVar(&c.tmpl, "t", "", "")
+	flags.BoolVar(&c.watch, "watch", false, "")
+	flags.DurationVar(&c.monitorInterval, "monitor-interval", defaultMonitorInterval, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if c.watch && (c.json || len(c.tmpl) > 0) {
+		c.Ui.Error("The -watch flag cannot be used with -json or -t")
+		return 1
+	}
+
 	// Check that we got either a single node or none
 	args = flags.Args()
 	if len(args) > 1 {
@@ -150,6 +175,11 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Show the fleet inventory report if requested
+	if c.osInventory {
+		return c.osInventoryReport(client)
+	}
+
 	// Use list mode if no node name was provided
 	if len(args) == 0 && !c.self {
 
@@ -258,6 +288,21 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	}
 
 	// Prefix lookup matched a single node
+	if c.watch {
+		return c.Meta.watch(c.monitorInterval, func(q *api.QueryOptions) (uint64, error) {
+			node, meta, err := client.Nodes().Info(nodes[0].ID, q)
+			if err != nil {
+				return 0, fmt.Errorf("Error querying node info: %s", err)
+			}
+
+			c.clearScreen()
+			if rc := c.formatNode(client, node); rc != 0 {
+				return 0, fmt.Errorf("Error formatting node status")
+			}
+			return meta.LastIndex, nil
+		})
+	}
+
 	node, _, err := client.Nodes().Info(nodes[0].ID, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying node info: %s", err))
@@ -770,6 +815,74 @@ func (c *NodeStatusCommand) printDiskStats(hostStats *api.HostStats) {
 }
 
 // getRunningAllocs returns a slice of allocation id's running on the node
+// osInventoryReport displays the fleet OS inventory baseline and the nodes
+// drifting from it
+func (c *NodeStatusCommand) osInventoryReport(client *api.Client) int {
+	report, _, err := client.Nodes().OSInventory(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node inventory: %s", err))
+		return 1
+	}
+
+	if c.json || len(c.tmpl) > 0 {
+		out, err := Format(c.json, c.tmpl, report)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(report.Baseline) == 0 {
+		c.Ui.Output("No inventory attributes found")
+		return 0
+	}
+
+	// Show the baseline sorted by attribute
+	keys := make([]string, 0, len(report.Baseline))
+	for key := range report.Baseline {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	baseline := make([]string, 0, len(keys)+1)
+	baseline = append(baseline, "Attribute|Baseline")
+	for _, key := range keys {
+		baseline = append(baseline, fmt.Sprintf("%s|%s", key, report.Baseline[key]))
+	}
+	c.Ui.Output(c.Colorize().Color("[bold]Fleet Baseline[reset]"))
+	c.Ui.Output(formatKV(baseline))
+
+	if len(report.Drifted) == 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Drifted Nodes[reset]"))
+		c.Ui.Output("No nodes drift from the baseline")
+		return 0
+	}
+
+	out := make([]string, 0, len(report.Drifted)+1)
+	out = append(out, "ID|Name|Attribute|Value|Baseline")
+	for _, node := range report.Drifted {
+		attrs := make([]string, 0, len(node.Drift))
+		for key := range node.Drift {
+			attrs = append(attrs, key)
+		}
+		sort.Strings(attrs)
+		for _, key := range attrs {
+			value := node.Drift[key]
+			if value == "" {
+				value = "<missing>"
+			}
+			out = append(out, fmt.Sprintf("%s|%s|%s|%s|%s",
+				limit(node.NodeID, c.length), node.Name, key, value, report.Baseline[key]))
+		}
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]Drifted Nodes[reset]"))
+	c.Ui.Output(formatList(out))
+	return 0
+}
+
 func getRunningAllocs(client *api.Client, nodeID string) ([]*api.Allocation, error) {
 	var allocs []*api.Allocation
 
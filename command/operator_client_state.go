@@ -0,0 +1,200 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hclog "github.com/hashicorp/go-hclog"
+	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
+	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
+	"github.com/hashicorp/nomad/client/dynamicplugins"
+	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	clientstate "github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type OperatorClientStateCommand struct {
+	Meta
+}
+
+func (c *OperatorClientStateCommand) Help() string {
+	helpText := `
+Usage: nomad operator client-state <path to nomad client data dir>
+
+  Display the content of the client's state database in json form: the
+  allocations the client would restore on restart, each task's runner local
+  state and driver handle, and the persisted plugin manager state. Any
+  allocation entries that fail to decode are reported alongside the valid
+  ones. The client must be stopped, as the database is locked while the
+  client runs.
+
+  This is a low-level debugging tool and not subject to Nomad's usual backward
+  compatibility guarantees.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientStateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{}
+}
+
+func (c *OperatorClientStateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientStateCommand) Synopsis() string {
+	return "Display the client state database content"
+}
+
+func (c *OperatorClientStateCommand) Name() string { return "operator client-state" }
+
+// clientStateTask is the state the client persisted for a single task.
+type clientStateTask struct {
+	// LocalState is the task runner's private state, including the driver
+	// handle used to reattach to the running task.
+	LocalState *trstate.LocalState
+
+	// TaskState is the task's state as reported to the servers.
+	TaskState *structs.TaskState
+}
+
+// clientStateAlloc is the state the client persisted for a single
+// allocation and its tasks.
+type clientStateAlloc struct {
+	Alloc         *structs.Allocation
+	DeployStatus  *structs.AllocDeploymentStatus
+	NetworkStatus *structs.AllocNetworkStatus
+	Tasks         map[string]*clientStateTask
+}
+
+// clientState is the output format: everything the client would use to
+// restore allocations and plugins on restart.
+type clientState struct {
+	Allocations map[string]*clientStateAlloc
+
+	// Errors maps allocation IDs to the error that prevented their state
+	// from being restored.
+	Errors map[string]string `json:",omitempty"`
+
+	DevicePluginState  *dmstate.PluginState
+	DriverPluginState  *driverstate.PluginState
+	DynamicPluginState *dynamicplugins.RegistryState
+}
+
+func (c *OperatorClientStateCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { fmt.Println(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+
+		return 1
+	}
+
+	var p string
+	if _, err := os.Stat(filepath.Join(args[0], "client", "state.db")); err == nil {
+		p = filepath.Join(args[0], "client")
+	} else if _, err := os.Stat(filepath.Join(args[0], "state.db")); err == nil {
+		p = args[0]
+	} else {
+		c.Ui.Error(fmt.Sprintf("path needs to be a client data dir path with state.db file: %v", err))
+		return 1
+	}
+
+	db, err := clientstate.NewBoltStateDB(hclog.NewNullLogger(), p)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("failed to open client state database: %v", err))
+		return 1
+	}
+	defer db.Close()
+
+	state, err := dumpClientState(db)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	out, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("failed to encode output: %v", err))
+		return 1
+	}
+	c.Ui.Output(string(out))
+
+	return 0
+}
+
+// dumpClientState reads everything the client would restore on restart out
+// of its state database.
+func dumpClientState(db clientstate.StateDB) (*clientState, error) {
+	allocs, errs, err := db.GetAllAllocations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allocations: %v", err)
+	}
+
+	state := &clientState{
+		Allocations: make(map[string]*clientStateAlloc, len(allocs)),
+	}
+	for id, err := range errs {
+		if state.Errors == nil {
+			state.Errors = make(map[string]string, len(errs))
+		}
+		state.Errors[id] = err.Error()
+	}
+
+	for _, alloc := range allocs {
+		entry := &clientStateAlloc{
+			Alloc: alloc,
+			Tasks: make(map[string]*clientStateTask),
+		}
+		state.Allocations[alloc.ID] = entry
+
+		if entry.DeployStatus, err = db.GetDeploymentStatus(alloc.ID); err != nil {
+			return nil, fmt.Errorf("failed to read deployment status for alloc %s: %v", alloc.ID, err)
+		}
+		if entry.NetworkStatus, err = db.GetNetworkStatus(alloc.ID); err != nil {
+			return nil, fmt.Errorf("failed to read network status for alloc %s: %v", alloc.ID, err)
+		}
+
+		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+		for _, task := range tg.Tasks {
+			ls, ts, err := db.GetTaskRunnerState(alloc.ID, task.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read state for task %s of alloc %s: %v", task.Name, alloc.ID, err)
+			}
+			if ls == nil && ts == nil {
+				continue
+			}
+			entry.Tasks[task.Name] = &clientStateTask{
+				LocalState: ls,
+				TaskState:  ts,
+			}
+		}
+	}
+
+	if state.DevicePluginState, err = db.GetDevicePluginState(); err != nil {
+		return nil, fmt.Errorf("failed to read device plugin state: %v", err)
+	}
+	if state.DriverPluginState, err = db.GetDriverPluginState(); err != nil {
+		return nil, fmt.Errorf("failed to read driver plugin state: %v", err)
+	}
+	if state.DynamicPluginState, err = db.GetDynamicPluginRegistryState(); err != nil {
+		return nil, fmt.Errorf("failed to read dynamic plugin state: %v", err)
+	}
+
+	return state, nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
+	clientstate "github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorClientStateCommand(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "nomad-client-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorClientStateCommand{Meta: Meta{Ui: ui}}
+
+	// A dir without a state.db is an error
+	code := cmd.Run([]string{dir})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "state.db")
+	ui.ErrorWriter.Reset()
+
+	// Populate a state database the way a client would
+	db, err := clientstate.NewBoltStateDB(hclog.NewNullLogger(), dir)
+	require.NoError(t, err)
+
+	alloc := mock.Alloc()
+	require.NoError(t, db.PutAllocation(alloc))
+	require.NoError(t, db.PutTaskRunnerLocalState(alloc.ID, "web", trstate.NewLocalState()))
+	require.NoError(t, db.PutTaskState(alloc.ID, "web", &structs.TaskState{
+		State: structs.TaskStateRunning,
+	}))
+	require.NoError(t, db.Close())
+
+	code = cmd.Run([]string{dir})
+	require.Zero(t, code)
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, alloc.ID)
+	require.Contains(t, output, "web")
+	require.Contains(t, output, structs.TaskStateRunning)
+}
@@ -575,6 +575,10 @@ func (c *OperatorDebugCommand) collectNomad(dir string, client *api.Client) erro
 	vs, _, err := client.CSIVolumes().List(qo)
 	c.writeJSON(dir, "volumes.json", vs, err)
 
+	var metrics interface{}
+	_, err = client.Raw().Query("/v1/metrics", &metrics, qo)
+	c.writeJSON(dir, "metrics.json", metrics, err)
+
 	return nil
 }
 
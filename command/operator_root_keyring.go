@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorRootKeyringCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring <subcommand> [options]
+
+  This command groups subcommands for managing the root encryption keyring.
+  The keys are used to sign workload identities and to seal variables; old
+  keys are retained after a rotation so outstanding material stays usable,
+  and can be removed once nothing references them.
+
+  List the keyring:
+
+      $ nomad operator root keyring list
+
+  Rotate the active encryption key:
+
+      $ nomad operator root keyring rotate
+
+  Remove an unused key from the keyring:
+
+      $ nomad operator root keyring remove <key_id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringCommand) Synopsis() string {
+	return "Manages the root encryption keyring"
+}
+
+func (c *OperatorRootKeyringCommand) Name() string { return "operator root keyring" }
+
+func (c *OperatorRootKeyringCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringListCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringListCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring list [options]
+
+  List the keys in the root encryption keyring. Only the public metadata is
+  shown; the key material never leaves the servers.
+
+  If ACLs are enabled, this command requires a token with the 'operator:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringListCommand) Synopsis() string {
+	return "List the root encryption keyring"
+}
+
+func (c *OperatorRootKeyringListCommand) Name() string { return "operator root keyring list" }
+
+func (c *OperatorRootKeyringListCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	keys, _, err := client.Operator().KeyringList(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing keyring: %s", err))
+		return 1
+	}
+
+	// Sort the keys with the newest first
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreateTime > keys[j].CreateTime })
+
+	rows := make([]string, len(keys)+1)
+	rows[0] = "Key ID|Active|Create Index"
+	for i, key := range keys {
+		rows[i+1] = fmt.Sprintf("%s|%v|%d", key.KeyID, key.Active, key.CreateIndex)
+	}
+	c.Ui.Output(formatList(rows))
+	return 0
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringRemoveCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring remove [options] <key_id>
+
+  Remove a key from the root encryption keyring. The active key cannot be
+  removed, nor can a key that still seals stored variables; rewrite those
+  variables so they are sealed with the active key first.
+
+  If ACLs are enabled, this command requires a token with the
+  'operator:write' capability.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Synopsis() string {
+	return "Remove a key from the root encryption keyring"
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Name() string { return "operator root keyring remove" }
+
+func (c *OperatorRootKeyringRemoveCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a key ID
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <key_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Operator().KeyringRemove(args[0], nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error removing key: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Removed encryption key %q from the keyring", args[0]))
+	return 0
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringRotateCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRotateCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring rotate [options]
+
+  Rotate the active key in the root encryption keyring. New workload
+  identities and variable writes use the new key immediately; material
+  sealed with the previous keys is re-wrapped the next time it is written,
+  so the previous keys are retained until they are removed.
+
+  If ACLs are enabled, this command requires a token with the
+  'operator:write' capability.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringRotateCommand) Synopsis() string {
+	return "Rotate the root encryption key"
+}
+
+func (c *OperatorRootKeyringRotateCommand) Name() string { return "operator root keyring rotate" }
+
+func (c *OperatorRootKeyringRotateCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Operator().KeyringRotate(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rotating keyring: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Rotated encryption keyring, new active key %q", resp.Key.KeyID))
+	return 0
+}
@@ -29,8 +29,7 @@ Usage: nomad operator snapshot <subcommand> [options]
 
       $ nomad operator snapshot inspect backup.snap
 
-  Run a daemon process that locally saves a snapshot every hour (available only in
-  Nomad Enterprise) :
+  Run a daemon process that locally saves a snapshot every hour:
 
       $ nomad operator snapshot agent
 
@@ -0,0 +1,372 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotAgentCommand struct {
+	Meta
+
+	// shutdownCh can be set by tests to terminate the agent loop without
+	// sending a signal.
+	shutdownCh <-chan struct{}
+}
+
+func (c *OperatorSnapshotAgentCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot agent [options]
+
+  Runs a long-lived process that periodically saves snapshots of the state of
+  the Nomad servers to local disk and, optionally, to S3-compatible storage.
+  Old snapshots beyond the retention limit are deleted after each save.
+
+  The agent is leader-aware: it only saves a snapshot when the server it is
+  connected to is the current cluster leader. This allows one snapshot agent
+  to run alongside every server while only a single agent performs uploads.
+
+  If ACLs are enabled, a management token must be supplied in order to perform
+  snapshot operations.
+
+  To save a snapshot every hour, keeping the thirty most recent:
+
+    $ nomad operator snapshot agent -interval=1h -retain=30
+
+  To also upload each snapshot to an S3-compatible object store:
+
+    $ nomad operator snapshot agent -s3-bucket=backups -s3-region=us-east-1
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Snapshot Agent Options:
+
+  -interval=<duration>
+    Interval between snapshot saves. Defaults to 1h.
+
+  -retain=<num>
+    Number of snapshots to retain locally and in S3. Older snapshots are
+    deleted after each save. Set to 0 to retain all snapshots. Defaults to 30.
+
+  -local-path=<path>
+    Directory in which snapshots are saved. Defaults to the current directory.
+
+  -s3-bucket=<bucket>
+    If set, snapshots are also uploaded to this S3 bucket. Credentials are
+    read from the standard AWS environment variables and configuration files.
+
+  -s3-key-prefix=<prefix>
+    Key prefix for snapshots uploaded to S3. Defaults to "nomad-snapshots".
+
+  -s3-region=<region>
+    AWS region of the S3 bucket.
+
+  -s3-endpoint=<endpoint>
+    Optional endpoint URL for S3-compatible storage such as Minio. When set,
+    path-style bucket addressing is used.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotAgentCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-interval":      complete.PredictAnything,
+			"-retain":        complete.PredictAnything,
+			"-local-path":    complete.PredictDirs("*"),
+			"-s3-bucket":     complete.PredictAnything,
+			"-s3-key-prefix": complete.PredictAnything,
+			"-s3-region":     complete.PredictAnything,
+			"-s3-endpoint":   complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSnapshotAgentCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSnapshotAgentCommand) Synopsis() string {
+	return "Periodically saves snapshots of Nomad server state"
+}
+
+func (c *OperatorSnapshotAgentCommand) Name() string { return "operator snapshot agent" }
+
+func (c *OperatorSnapshotAgentCommand) Run(args []string) int {
+	var interval time.Duration
+	var retain int
+	var localPath string
+	var s3Bucket, s3KeyPrefix, s3Region, s3Endpoint string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	flags.DurationVar(&interval, "interval", time.Hour, "")
+	flags.IntVar(&retain, "retain", 30, "")
+	flags.StringVar(&localPath, "local-path", ".", "")
+	flags.StringVar(&s3Bucket, "s3-bucket", "", "")
+	flags.StringVar(&s3KeyPrefix, "s3-key-prefix", "nomad-snapshots", "")
+	flags.StringVar(&s3Region, "s3-region", "", "")
+	flags.StringVar(&s3Endpoint, "s3-endpoint", "", "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check for misuse
+	if len(flags.Args()) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if interval <= 0 {
+		c.Ui.Error("Snapshot interval must be greater than zero")
+		return 1
+	}
+
+	if retain < 0 {
+		c.Ui.Error("Snapshot retention must not be negative")
+		return 1
+	}
+
+	if s3Bucket == "" && (s3Region != "" || s3Endpoint != "") {
+		c.Ui.Error("S3 options require -s3-bucket to be set")
+		return 1
+	}
+
+	if fi, err := os.Stat(localPath); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to stat local path: %v", err))
+		return 1
+	} else if !fi.IsDir() {
+		c.Ui.Error(fmt.Sprintf("Local path %q is not a directory", localPath))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Set up the S3 client if a bucket was given.
+	var s3Client *s3.S3
+	if s3Bucket != "" {
+		cfg := aws.NewConfig()
+		if s3Region != "" {
+			cfg = cfg.WithRegion(s3Region)
+		}
+		if s3Endpoint != "" {
+			cfg = cfg.WithEndpoint(s3Endpoint).WithS3ForcePathStyle(true)
+		}
+
+		sess, err := session.NewSession(cfg)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to configure S3 session: %v", err))
+			return 1
+		}
+		s3Client = s3.New(sess)
+	}
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+
+	c.Ui.Output(fmt.Sprintf("Snapshot agent started, saving every %v to %v", interval, localPath))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.saveRound(client, s3Client, localPath, s3Bucket, s3KeyPrefix, retain); err != nil {
+			c.Ui.Error(fmt.Sprintf("Snapshot round failed: %v", err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-signalCh:
+			c.Ui.Output("Snapshot agent shutting down")
+			return 0
+		case <-c.shutdownCh:
+			return 0
+		}
+	}
+}
+
+// saveRound performs a single snapshot cycle: it checks that the connected
+// server is the leader, saves a snapshot to local disk, uploads it to S3 if
+// configured, and prunes snapshots beyond the retention limit.
+func (c *OperatorSnapshotAgentCommand) saveRound(client *api.Client, s3Client *s3.S3, localPath, s3Bucket, s3KeyPrefix string, retain int) error {
+	leader, err := c.isLeader(client)
+	if err != nil {
+		return fmt.Errorf("failed to determine leadership: %v", err)
+	}
+	if !leader {
+		c.Ui.Info("Connected server is not the leader, skipping snapshot")
+		return nil
+	}
+
+	filename, err := c.saveSnapshot(client, localPath)
+	if err != nil {
+		return err
+	}
+	c.Ui.Output(fmt.Sprintf("State file written to %v", filename))
+
+	if s3Client != nil {
+		key := path.Join(s3KeyPrefix, filepath.Base(filename))
+		if err := uploadSnapshotS3(s3Client, s3Bucket, key, filename); err != nil {
+			return fmt.Errorf("failed to upload snapshot to S3: %v", err)
+		}
+		c.Ui.Output(fmt.Sprintf("Snapshot uploaded to s3://%v/%v", s3Bucket, key))
+	}
+
+	if retain > 0 {
+		if err := pruneLocalSnapshots(localPath, retain); err != nil {
+			return fmt.Errorf("failed to prune local snapshots: %v", err)
+		}
+		if s3Client != nil {
+			if err := pruneS3Snapshots(s3Client, s3Bucket, s3KeyPrefix, retain); err != nil {
+				return fmt.Errorf("failed to prune S3 snapshots: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isLeader returns true if the server the client is connected to is the
+// current cluster leader.
+func (c *OperatorSnapshotAgentCommand) isLeader(client *api.Client) (bool, error) {
+	self, err := client.Agent().Self()
+	if err != nil {
+		return false, err
+	}
+
+	stats, ok := self.Stats["nomad"]
+	if !ok {
+		return false, fmt.Errorf("connected agent is not a server")
+	}
+	return stats["leader"] == "true", nil
+}
+
+// saveSnapshot writes a snapshot from the leader to a timestamped file in
+// dir, using a temporary file so partial downloads are never left behind
+// under the final name.
+func (c *OperatorSnapshotAgentCommand) saveSnapshot(client *api.Client, dir string) (string, error) {
+	now := time.Now()
+	filename := filepath.Join(dir, fmt.Sprintf("nomad-state-%04d%02d%02d-%d.snap", now.Year(), now.Month(), now.Day(), now.UnixNano()))
+
+	tmpFile, err := os.Create(filename + ".tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	snapIn, err := client.Operator().Snapshot(&api.QueryOptions{})
+	if err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to get snapshot file: %v", err)
+	}
+	defer snapIn.Close()
+
+	if _, err := io.Copy(tmpFile, snapIn); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to download snapshot file: %v", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to close snapshot file: %v", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), filename); err != nil {
+		return "", fmt.Errorf("failed to finalize snapshot file: %v", err)
+	}
+
+	return filename, nil
+}
+
+// uploadSnapshotS3 uploads the snapshot at filename to the given bucket and
+// key.
+func uploadSnapshotS3(s3Client *s3.S3, bucket, key, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   f,
+	})
+	return err
+}
+
+// pruneLocalSnapshots deletes all but the retain most recent snapshots in
+// dir. Snapshot filenames embed their creation time so lexical order matches
+// chronological order.
+func pruneLocalSnapshots(dir string, retain int) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "nomad-state-*.snap"))
+	if err != nil {
+		return err
+	}
+
+	if len(matches) <= retain {
+		return nil
+	}
+
+	sort.Strings(matches)
+	for _, old := range matches[:len(matches)-retain] {
+		if err := os.Remove(old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneS3Snapshots deletes all but the retain most recent snapshots under
+// prefix in the given bucket.
+func pruneS3Snapshots(s3Client *s3.S3, bucket, prefix string, retain int) error {
+	out, err := s3Client.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(out.Contents))
+	for _, obj := range out.Contents {
+		keys = append(keys, *obj.Key)
+	}
+
+	if len(keys) <= retain {
+		return nil
+	}
+
+	sort.Strings(keys)
+	for _, old := range keys[:len(keys)-retain] {
+		_, err := s3Client.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(old),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
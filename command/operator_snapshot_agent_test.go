@@ -0,0 +1,108 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotAgent_Works(t *testing.T) {
+	t.Parallel()
+
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpDir)
+
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.DevMode = false
+		c.DataDir = filepath.Join(tmpDir, "server")
+
+		c.AdvertiseAddrs.HTTP = "127.0.0.1"
+		c.AdvertiseAddrs.RPC = "127.0.0.1"
+		c.AdvertiseAddrs.Serf = "127.0.0.1"
+	})
+
+	defer srv.Shutdown()
+
+	snapDir := filepath.Join(tmpDir, "snapshots")
+	require.NoError(t, os.Mkdir(snapDir, 0755))
+
+	// A pre-closed shutdown channel terminates the loop after one round.
+	shutdownCh := make(chan struct{})
+	close(shutdownCh)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotAgentCommand{Meta: Meta{Ui: ui}, shutdownCh: shutdownCh}
+
+	code := cmd.Run([]string{
+		"--address=" + url,
+		"-local-path=" + snapDir,
+	})
+	require.Zero(t, code)
+	require.Contains(t, ui.OutputWriter.String(), "State file written to ")
+
+	matches, err := filepath.Glob(filepath.Join(snapDir, "nomad-state-*.snap"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+}
+
+func TestOperatorSnapshotAgent_Fails(t *testing.T) {
+	t.Parallel()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotAgentCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a non-positive interval
+	code = cmd.Run([]string{"-interval=0"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "must be greater than zero")
+	ui.ErrorWriter.Reset()
+
+	// Fails on S3 options without a bucket
+	code = cmd.Run([]string{"-s3-region=us-east-1"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "require -s3-bucket")
+	ui.ErrorWriter.Reset()
+
+	// Fails when the local path does not exist
+	code = cmd.Run([]string{"-local-path=/unicorns/leprechauns"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Failed to stat local path")
+}
+
+func TestOperatorSnapshotAgent_PruneLocal(t *testing.T) {
+	t.Parallel()
+
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpDir)
+
+	for i := 0; i < 5; i++ {
+		name := filepath.Join(tmpDir, fmt.Sprintf("nomad-state-20200101-%d.snap", i))
+		require.NoError(t, ioutil.WriteFile(name, []byte("snap"), 0644))
+	}
+
+	require.NoError(t, pruneLocalSnapshots(tmpDir, 2))
+
+	matches, err := filepath.Glob(filepath.Join(tmpDir, "nomad-state-*.snap"))
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+
+	// The most recent snapshots are the ones retained.
+	require.Contains(t, matches, filepath.Join(tmpDir, "nomad-state-20200101-3.snap"))
+	require.Contains(t, matches, filepath.Join(tmpDir, "nomad-state-20200101-4.snap"))
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+)
+
+type SentinelOverridesCommand struct {
+	Meta
+}
+
+func (c *SentinelOverridesCommand) Help() string {
+	helpText := `
+Usage: nomad sentinel overrides [options]
+
+  Overrides is used to display recent job submissions that overrode
+  soft-mandatory Sentinel policies, for audit purposes.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SentinelOverridesCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *SentinelOverridesCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SentinelOverridesCommand) Synopsis() string {
+	return "Display recent Sentinel policy overrides"
+}
+
+func (c *SentinelOverridesCommand) Name() string { return "sentinel overrides" }
+
+func (c *SentinelOverridesCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+	}
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Get the list of overrides
+	overrides, _, err := client.SentinelPolicies().Overrides(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing Sentinel overrides: %s", err))
+		return 1
+	}
+
+	if len(overrides) == 0 {
+		c.Ui.Output("No overrides found")
+		return 0
+	}
+
+	out := []string{}
+	out = append(out, "Time|Job ID|Namespace|Accessor ID|Policies")
+	for _, o := range overrides {
+		line := fmt.Sprintf("%s|%s|%s|%s|%s",
+			formatTime(time.Unix(0, o.Time)), o.JobID, o.Namespace,
+			o.AccessorID, strings.Join(o.Policies, ","))
+		out = append(out, line)
+	}
+	c.Ui.Output(formatList(out))
+	return 0
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type SentinelTestCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *SentinelTestCommand) Help() string {
+	helpText := `
+Usage: nomad sentinel test [options] <file> <job file>
+
+  Test is used to dry-run a Sentinel policy against a job specification
+  without storing the policy or submitting the job. The policy is compiled
+  and evaluated by the servers and the result and execution trace are
+  returned, which is useful while authoring policies. The policy file will
+  be read from stdin by specifying "-".
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Test Options:
+
+  -scope (default: submit-job)
+    Sets the scope of the policy and when it should be enforced.
+
+  -level (default: advisory)
+    Sets the enforcement level of the policy. Must be one of advisory,
+    soft-mandatory, hard-mandatory.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SentinelTestCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-scope": complete.PredictAnything,
+			"-level": complete.PredictAnything,
+		})
+}
+
+func (c *SentinelTestCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *SentinelTestCommand) Synopsis() string {
+	return "Test a Sentinel policy against a job specification"
+}
+
+func (c *SentinelTestCommand) Name() string { return "sentinel test" }
+
+func (c *SentinelTestCommand) Run(args []string) int {
+	var scope, enfLevel string
+	var err error
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&scope, "scope", "submit-job", "")
+	flags.StringVar(&enfLevel, "level", "advisory", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes exactly two arguments: <file> <job file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Read the policy file contents
+	file := args[0]
+	var rawPolicy []byte
+	if file == "-" {
+		rawPolicy, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read stdin: %v", err))
+			return 1
+		}
+	} else {
+		rawPolicy, err = ioutil.ReadFile(file)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read file: %v", err))
+			return 1
+		}
+	}
+
+	// Parse the job specification
+	job, err := c.JobGetter.ApiJob(args[1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	// Construct the policy
+	sp := &api.SentinelPolicy{
+		Name:             "test",
+		Scope:            scope,
+		EnforcementLevel: enfLevel,
+		Policy:           string(rawPolicy),
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Test the policy
+	result, _, err := client.SentinelPolicies().Test(sp, job, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error testing Sentinel policy: %s", err))
+		return 1
+	}
+
+	if result.Trace != "" {
+		c.Ui.Output(result.Trace)
+	}
+	if !result.Passed {
+		c.Ui.Error("Sentinel policy failed")
+		return 1
+	}
+	c.Ui.Output("Sentinel policy passed")
+	return 0
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -105,45 +106,38 @@ func (c *StatusCommand) Run(args []string) int {
 
 	id := args[len(args)-1]
 
-	// Query for the context associated with the id
-	res, _, err := client.Search().PrefixSearch(id, contexts.All, nil)
+	// Resolve the id to a typed match in a single query
+	res, _, err := client.Search().ResolveIdentifier(id, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying search with id: %q", err))
 		return 1
 	}
 
-	if res.Matches == nil {
-		c.Ui.Error(fmt.Sprintf("No matches returned for query: %q", err))
+	if len(res.Matches) == 0 {
+		c.Ui.Error(fmt.Sprintf("Unable to resolve ID: %q", id))
 		return 1
 	}
 
-	var match contexts.Context
-	exactMatches := 0
-	for ctx, vers := range res.Matches {
-		if len(vers) > 0 && vers[0] == id {
-			match = ctx
-			exactMatches++
+	// Prefer exact matches over prefix matches
+	exact := make([]*api.IdentifierMatch, 0, 1)
+	for _, m := range res.Matches {
+		if m.ID == id {
+			exact = append(exact, m)
 		}
 	}
 
-	if exactMatches > 1 {
+	var match contexts.Context
+	switch {
+	case len(exact) == 1:
+		match = exact[0].Context
+	case len(exact) > 1:
+		c.logMultiMatchError(id, exact)
+		return 1
+	case len(res.Matches) == 1:
+		match = res.Matches[0].Context
+	default:
 		c.logMultiMatchError(id, res.Matches)
 		return 1
-	} else if exactMatches == 0 {
-		matchCount := 0
-		for ctx, vers := range res.Matches {
-			l := len(vers)
-			if l == 1 {
-				match = ctx
-				matchCount++
-			}
-
-			// Only a single result should return, as this is a match against a full id
-			if matchCount > 1 || l > 1 {
-				c.logMultiMatchError(id, res.Matches)
-				return 1
-			}
-		}
 	}
 
 	var cmd cli.Command
@@ -176,13 +170,14 @@ func (c *StatusCommand) Run(args []string) int {
 
 // logMultiMatchError is used to log an error message when multiple matches are
 // found. The error message logged displays the matched IDs per context.
-func (c *StatusCommand) logMultiMatchError(id string, matches map[contexts.Context][]string) {
-	c.Ui.Error(fmt.Sprintf("Multiple matches found for id %q", id))
-	for ctx, vers := range matches {
-		if len(vers) == 0 {
-			continue
-		}
+func (c *StatusCommand) logMultiMatchError(id string, matches []*api.IdentifierMatch) {
+	byContext := make(map[contexts.Context][]string)
+	for _, m := range matches {
+		byContext[m.Context] = append(byContext[m.Context], m.ID)
+	}
 
+	c.Ui.Error(fmt.Sprintf("Multiple matches found for id %q", id))
+	for ctx, vers := range byContext {
 		c.Ui.Error(fmt.Sprintf("\n%s:", strings.Title(string(ctx))))
 		c.Ui.Error(fmt.Sprintf("%s", strings.Join(vers, ", ")))
 	}
@@ -0,0 +1,107 @@
+// Package token provides a token helper mechanism so the CLI can fetch and
+// store ACL tokens with an external program, such as an OS keychain
+// integration, instead of the NOMAD_TOKEN environment variable.
+package token
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// EnvTokenHelper is the environment variable that configures the token
+// helper program.
+const EnvTokenHelper = "NOMAD_TOKEN_HELPER"
+
+// Helper is the interface for fetching, storing, and erasing the ACL token
+// used by the CLI.
+type Helper interface {
+	// Get returns the stored token, or an empty string if none is stored
+	Get() (string, error)
+
+	// Store stores the given token
+	Store(token string) error
+
+	// Erase removes the stored token
+	Erase() error
+}
+
+// ExternalHelper is a Helper that runs an external program. The program is
+// invoked with a single argument of "get", "store", or "erase"; for store
+// the token is written to the program's stdin, and for get the token is
+// expected on its stdout. The Nomad address is exported to the program in
+// NOMAD_ADDR so helpers can keep per-address profiles.
+type ExternalHelper struct {
+	// Command is the token helper command line as configured
+	Command string
+
+	// Address is the Nomad address the token is for
+	Address string
+}
+
+// NewExternalHelper returns the token helper configured in the environment,
+// or nil if none is configured.
+func NewExternalHelper(address string) *ExternalHelper {
+	command := os.Getenv(EnvTokenHelper)
+	if command == "" {
+		return nil
+	}
+	return &ExternalHelper{
+		Command: command,
+		Address: address,
+	}
+}
+
+func (h *ExternalHelper) Get() (string, error) {
+	var buf, stderr bytes.Buffer
+	cmd := h.cmd("get")
+	cmd.Stdout = &buf
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("error running token helper %q: %v: %s", h.Command, err, stderr.String())
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (h *ExternalHelper) Store(token string) error {
+	var stderr bytes.Buffer
+	cmd := h.cmd("store")
+	cmd.Stdin = strings.NewReader(token)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running token helper %q: %v: %s", h.Command, err, stderr.String())
+	}
+	return nil
+}
+
+func (h *ExternalHelper) Erase() error {
+	var stderr bytes.Buffer
+	cmd := h.cmd("erase")
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running token helper %q: %v: %s", h.Command, err, stderr.String())
+	}
+	return nil
+}
+
+// cmd builds the command to run the helper with the given operation
+// appended, through a shell so helper commands may include arguments.
+func (h *ExternalHelper) cmd(op string) *exec.Cmd {
+	script := h.Command + " " + op
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", script)
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", script)
+	}
+
+	cmd.Env = append(os.Environ(), "NOMAD_ADDR="+h.Address)
+	return cmd
+}
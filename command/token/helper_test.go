@@ -0,0 +1,82 @@
+package token
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalHelper(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script requires a shell")
+	}
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-token-helper")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A helper that keeps a token file per address
+	script := filepath.Join(dir, "helper.sh")
+	contents := fmt.Sprintf(`#!/bin/sh
+f="%s/$(echo "$NOMAD_ADDR" | tr -c '[:alnum:]' '_')"
+case "$1" in
+get)
+	if [ -f "$f" ]; then cat "$f"; fi
+	;;
+store)
+	cat > "$f"
+	;;
+erase)
+	rm -f "$f"
+	;;
+esac
+`, dir)
+	require.NoError(ioutil.WriteFile(script, []byte(contents), 0700))
+
+	h := &ExternalHelper{Command: script, Address: "http://127.0.0.1:4646"}
+
+	// No token stored yet
+	out, err := h.Get()
+	require.NoError(err)
+	require.Empty(out)
+
+	// Store and read it back
+	require.NoError(h.Store("secret-token"))
+	out, err = h.Get()
+	require.NoError(err)
+	require.Equal("secret-token", out)
+
+	// Profiles are per address
+	other := &ExternalHelper{Command: script, Address: "http://10.0.0.1:4646"}
+	out, err = other.Get()
+	require.NoError(err)
+	require.Empty(out)
+
+	// Erase removes the token
+	require.NoError(h.Erase())
+	out, err = h.Get()
+	require.NoError(err)
+	require.Empty(out)
+}
+
+func TestNewExternalHelper(t *testing.T) {
+	require := require.New(t)
+
+	defer os.Unsetenv(EnvTokenHelper)
+
+	os.Unsetenv(EnvTokenHelper)
+	require.Nil(NewExternalHelper("http://127.0.0.1:4646"))
+
+	os.Setenv(EnvTokenHelper, "helper.sh")
+	h := NewExternalHelper("http://127.0.0.1:4646")
+	require.NotNil(h)
+	require.Equal("helper.sh", h.Command)
+	require.Equal("http://127.0.0.1:4646", h.Address)
+}
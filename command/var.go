@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are encrypted key/value entries stored by the Nomad servers and are useful
+  for small amounts of configuration or secret data that does not warrant a
+  full Vault deployment.
+
+  Create or update a variable:
+
+      $ nomad var put secret/config username=admin
+
+  Read a variable:
+
+      $ nomad var get secret/config
+
+  List variables:
+
+      $ nomad var list
+
+  Delete a variable:
+
+      $ nomad var purge secret/config
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
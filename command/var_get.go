@@ -0,0 +1,132 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VarGetCommand struct {
+	Meta
+}
+
+func (c *VarGetCommand) Help() string {
+	helpText := `
+Usage: nomad var get [options] <path>
+
+  Get is used to read the variable stored at the given path. The items are
+  returned decrypted.
+
+  If ACLs are enabled, this command requires a token granting the 'read'
+  capability on the variable path.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Get Options:
+
+  -item=<key>
+    Print only the value of the given item. This is convenient for passing
+    a single value to other commands.
+
+  -json
+    Output the variable in a JSON format.
+
+  -t
+    Format and display the variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-item": complete.PredictAnything,
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarGetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarGetCommand) Synopsis() string {
+	return "Read a variable"
+}
+
+func (c *VarGetCommand) Name() string { return "var get" }
+
+func (c *VarGetCommand) Run(args []string) int {
+	var item, tmpl string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&item, "item", "", "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a path
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	variable, _, err := client.Variables().Read(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading variable: %s", err))
+		return 1
+	}
+
+	if item != "" {
+		value, ok := variable.Items[item]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Variable %q has no item %q", variable.Path, item))
+			return 1
+		}
+		c.Ui.Output(value)
+		return 0
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, variable)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Sort the items for a stable output
+	keys := make([]string, 0, len(variable.Items))
+	for k := range variable.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]string, 0, len(keys)+1)
+	kvs = append(kvs, fmt.Sprintf("Path|%s", variable.Path))
+	for _, k := range keys {
+		kvs = append(kvs, fmt.Sprintf("%s|%s", k, variable.Items[k]))
+	}
+	c.Ui.Output(formatKV(kvs))
+	return 0
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarListCommand struct {
+	Meta
+}
+
+func (c *VarListCommand) Help() string {
+	helpText := `
+Usage: nomad var list [options] [<prefix>]
+
+  List is used to list the variables stored in the request namespace,
+  optionally under a path prefix. Only metadata is returned; the items of a
+  variable are never included in list responses.
+
+  If ACLs are enabled, paths the token cannot list are filtered from the
+  results.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -json
+    Output the variables in a JSON format.
+
+  -t
+    Format and display the variables using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarListCommand) Synopsis() string {
+	return "List variables"
+}
+
+func (c *VarListCommand) Name() string { return "var list" }
+
+func (c *VarListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most a prefix
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <prefix>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	prefix := ""
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	variables, _, err := client.Variables().PrefixList(prefix, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving variables: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, variables)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVariables(variables))
+	return 0
+}
+
+func formatVariables(variables []*api.VariableMetadata) string {
+	if len(variables) == 0 {
+		return "No variables found"
+	}
+
+	// Sort the output by path
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Path < variables[j].Path })
+
+	rows := make([]string, len(variables)+1)
+	rows[0] = "Path|Namespace|Modify Index"
+	for i, v := range variables {
+		rows[i+1] = fmt.Sprintf("%s|%s|%d", v.Path, v.Namespace, v.ModifyIndex)
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> <key>=<value> [<key>=<value>]...
+
+  Put is used to create a variable at the given path or update the one
+  already stored there. The items are specified as key=value pairs and
+  replace any existing items; a put is not a merge with the current
+  contents.
+
+  If ACLs are enabled, this command requires a token granting the 'write'
+  capability on the variable path.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a path and at least one item
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <path> and <key>=<value>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	variable := &api.Variable{
+		Path:  args[0],
+		Items: make(map[string]string),
+	}
+	for _, item := range args[1:] {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid item %q, expected <key>=<value>", item))
+			return 1
+		}
+		variable.Items[parts[0]] = parts[1]
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Variables().Put(variable, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q!", variable.Path))
+	return 0
+}
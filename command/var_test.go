@@ -0,0 +1,90 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarCommands_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarPutCommand{}
+	var _ cli.Command = &VarGetCommand{}
+	var _ cli.Command = &VarListCommand{}
+	var _ cli.Command = &VarPurgeCommand{}
+}
+
+func TestVarPutCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some-path"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed items
+	if code := cmd.Run([]string{"some-path", "not-an-item"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "expected <key>=<value>") {
+		t.Fatalf("expected item error, got: %s", out)
+	}
+}
+
+func TestVarCommands_Roundtrip(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Create a server
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+
+	// Write a variable
+	put := &VarPutCommand{Meta: Meta{Ui: ui}}
+	code := put.Run([]string{"-address=" + url, "nomad/jobs/example", "user=me", "password=passw0rd1"})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Successfully wrote variable")
+	ui.OutputWriter.Reset()
+
+	// Read it back
+	get := &VarGetCommand{Meta: Meta{Ui: ui}}
+	code = get.Run([]string{"-address=" + url, "nomad/jobs/example"})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "passw0rd1")
+	ui.OutputWriter.Reset()
+
+	// Read a single item
+	code = get.Run([]string{"-address=" + url, "-item=user", "nomad/jobs/example"})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Equal("me", strings.TrimSpace(ui.OutputWriter.String()))
+	ui.OutputWriter.Reset()
+
+	// List the variables
+	list := &VarListCommand{Meta: Meta{Ui: ui}}
+	code = list.Run([]string{"-address=" + url, "nomad"})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "nomad/jobs/example")
+	ui.OutputWriter.Reset()
+
+	// Purge the variable
+	purge := &VarPurgeCommand{Meta: Meta{Ui: ui}}
+	code = purge.Run([]string{"-address=" + url, "nomad/jobs/example"})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Successfully purged variable")
+	ui.OutputWriter.Reset()
+
+	// It is gone
+	code = list.Run([]string{"-address=" + url})
+	require.Zero(code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "No variables found")
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// defaultMonitorInterval is how often watched status commands refresh when
+// the queried endpoint returns without blocking.
+const defaultMonitorInterval = 2 * time.Second
+
+// watch drives a live-updating status command. The render function is called
+// with query options whose WaitIndex is set to the index of the previously
+// rendered frame, so implementations can issue a blocking query against
+// their primary resource, redraw the output, and return the new index. For
+// endpoints that do not honor blocking queries the interval paces the loop
+// into plain polling, and it also bounds how long a blocking query waits so
+// secondary resources are refreshed regularly.
+func (m *Meta) watch(interval time.Duration, render func(q *api.QueryOptions) (uint64, error)) int {
+	if interval <= 0 {
+		interval = defaultMonitorInterval
+	}
+
+	var index uint64
+	for {
+		start := time.Now()
+
+		q := &api.QueryOptions{WaitIndex: index, WaitTime: interval}
+		newIndex, err := render(q)
+		if err != nil {
+			m.Ui.Error(err.Error())
+			return 1
+		}
+
+		// A query that returned immediately without advancing the index
+		// did not block; sleep the remainder of the interval so we don't
+		// spin against the server.
+		if newIndex == index {
+			if elapsed := time.Since(start); elapsed < interval {
+				time.Sleep(interval - elapsed)
+			}
+		}
+		index = newIndex
+	}
+}
+
+// clearScreen resets the terminal before a watched command redraws its
+// output.
+func (m *Meta) clearScreen() {
+	m.Ui.Output("\033[2J\033[H")
+}
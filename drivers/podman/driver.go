@@ -0,0 +1,782 @@
+package podman
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	hclog "github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/drivers/shared/eventer"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/helper/stats"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "podman"
+
+	// fingerprintPeriod is the interval at which the driver will send fingerprint responses
+	fingerprintPeriod = 30 * time.Second
+
+	// The keys populated in Node Attributes to indicate presence of the Podman driver
+	driverAttr        = "driver.podman"
+	driverVersionAttr = "driver.podman.version"
+	rootlessAttr      = "driver.podman.rootless"
+
+	// taskHandleVersion is the version of task handle which this driver sets
+	// and understands how to decode driver state
+	taskHandleVersion = 1
+)
+
+var (
+	// PluginID is the podman plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeDriver,
+	}
+
+	// PluginConfig is the podman driver factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Config:  map[string]interface{}{},
+		Factory: func(ctx context.Context, l hclog.Logger) interface{} { return NewPodmanDriver(ctx, l) },
+	}
+
+	versionRegex = regexp.MustCompile(`version (\d[\.\d]+)`)
+
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeDriver,
+		PluginApiVersions: []string{drivers.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"podman_path": hclspec.NewDefault(
+			hclspec.NewAttr("podman_path", "string", false),
+			hclspec.NewLiteral(`"podman"`),
+		),
+		"volumes_enabled": hclspec.NewDefault(
+			hclspec.NewAttr("volumes_enabled", "bool", false),
+			hclspec.NewLiteral("true"),
+		),
+		"gc_container": hclspec.NewDefault(
+			hclspec.NewAttr("gc_container", "bool", false),
+			hclspec.NewLiteral("true"),
+		),
+	})
+
+	// taskConfigSpec is the hcl specification for the driver config section of
+	// a taskConfig within a job. It is returned in the TaskConfigSchema RPC
+	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"image":   hclspec.NewAttr("image", "string", true),
+		"command": hclspec.NewAttr("command", "string", false),
+		"args":    hclspec.NewAttr("args", "list(string)", false),
+		"auth": hclspec.NewBlock("auth", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"username": hclspec.NewAttr("username", "string", true),
+			"password": hclspec.NewAttr("password", "string", true),
+		})),
+		"volumes":      hclspec.NewAttr("volumes", "list(string)", false),
+		"port_map":     hclspec.NewAttr("port_map", "list(map(number))", false),
+		"network_mode": hclspec.NewAttr("network_mode", "string", false),
+	})
+
+	// capabilities is returned by the Capabilities RPC and indicates what
+	// optional features this driver supports
+	capabilities = &drivers.Capabilities{
+		SendSignals:  true,
+		Exec:         true,
+		FSIsolation:  drivers.FSIsolationImage,
+		MountConfigs: drivers.MountConfigSupportAll,
+	}
+
+	// podmanMeasuredCPUStats and podmanMeasuredMemStats are the stats
+	// available from "podman stats"
+	podmanMeasuredCPUStats = []string{"Percent"}
+	podmanMeasuredMemStats = []string{"Usage"}
+
+	_ drivers.DriverPlugin = (*Driver)(nil)
+)
+
+// TaskConfig is the driver configuration of a taskConfig within a job
+type TaskConfig struct {
+	Image       string             `codec:"image"`        // image to run, pulled if not present
+	Command     string             `codec:"command"`      // command to override the image entrypoint
+	Args        []string           `codec:"args"`         // extra arguments to the command
+	Auth        AuthConfig         `codec:"auth"`         // registry credentials used when pulling the image
+	Volumes     []string           `codec:"volumes"`      // host_path:container_path mounts
+	PortMap     hclutils.MapStrInt `codec:"port_map"`     // A map of host port labels and container ports
+	NetworkMode string             `codec:"network_mode"` // the container network mode, e.g. "host"
+}
+
+// AuthConfig is the registry authentication used when pulling an image
+type AuthConfig struct {
+	Username string `codec:"username"`
+	Password string `codec:"password"`
+}
+
+// TaskState is the state which is encoded in the handle returned in StartTask.
+// This information is needed to rebuild the taskConfig state and handler
+// during recovery.
+type TaskState struct {
+	ContainerID   string
+	ContainerName string
+	TaskConfig    *drivers.TaskConfig
+	StartedAt     time.Time
+}
+
+// Config is the driver configuration set by SetConfig RPC call
+type Config struct {
+	// PodmanPath is the path or name of the podman binary
+	PodmanPath string `codec:"podman_path"`
+
+	// VolumesEnabled indicates whether tasks may mount host paths
+	VolumesEnabled bool `codec:"volumes_enabled"`
+
+	// GCContainer indicates whether containers are removed when the task is
+	// destroyed
+	GCContainer bool `codec:"gc_container"`
+}
+
+// Driver is a driver for running containers via Podman
+type Driver struct {
+	// eventer is used to handle multiplexing of TaskEvents calls such that an
+	// event can be broadcast to all callers
+	eventer *eventer.Eventer
+
+	// config is the driver configuration set by the SetConfig RPC
+	config Config
+
+	// tasks is the in memory datastore mapping taskIDs to podman taskHandles
+	tasks *taskStore
+
+	// ctx is the context for the driver. It is passed to other subsystems to
+	// coordinate shutdown
+	ctx context.Context
+
+	// nomadConf is the client agent's configuration
+	nomadConfig *base.ClientDriverConfig
+
+	// logger will log to the Nomad agent
+	logger hclog.Logger
+}
+
+func NewPodmanDriver(ctx context.Context, logger hclog.Logger) drivers.DriverPlugin {
+	logger = logger.Named(pluginName)
+	return &Driver{
+		eventer: eventer.NewEventer(ctx, logger),
+		tasks:   newTaskStore(),
+		ctx:     ctx,
+		logger:  logger,
+	}
+}
+
+func (d *Driver) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (d *Driver) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+
+	d.config = config
+	if cfg.AgentConfig != nil {
+		d.nomadConfig = cfg.AgentConfig.Driver
+	}
+	return nil
+}
+
+func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
+	return taskConfigSpec, nil
+}
+
+func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
+	return capabilities, nil
+}
+
+// podmanBin returns the configured podman binary, defaulting to resolving
+// "podman" from the path.
+func (d *Driver) podmanBin() string {
+	if d.config.PodmanPath != "" {
+		return d.config.PodmanPath
+	}
+	return "podman"
+}
+
+func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+	ch := make(chan *drivers.Fingerprint)
+	go d.handleFingerprint(ctx, ch)
+	return ch, nil
+}
+
+func (d *Driver) handleFingerprint(ctx context.Context, ch chan *drivers.Fingerprint) {
+	ticker := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(fingerprintPeriod)
+			ch <- d.buildFingerprint()
+		}
+	}
+}
+
+func (d *Driver) buildFingerprint() *drivers.Fingerprint {
+	fingerprint := &drivers.Fingerprint{
+		Attributes:        map[string]*pstructs.Attribute{},
+		Health:            drivers.HealthStateHealthy,
+		HealthDescription: drivers.DriverHealthy,
+	}
+
+	outBytes, err := exec.Command(d.podmanBin(), "--version").Output()
+	if err != nil {
+		// return no error, as it isn't an error to not find podman, it just
+		// means we can't use it.
+		fingerprint.Health = drivers.HealthStateUndetected
+		fingerprint.HealthDescription = ""
+		return fingerprint
+	}
+	out := strings.TrimSpace(string(outBytes))
+
+	matches := versionRegex.FindStringSubmatch(out)
+	if len(matches) != 2 {
+		fingerprint.Health = drivers.HealthStateUndetected
+		fingerprint.HealthDescription = fmt.Sprintf("Failed to parse podman version from %v", out)
+		return fingerprint
+	}
+
+	fingerprint.Attributes[driverAttr] = pstructs.NewBoolAttribute(true)
+	fingerprint.Attributes[driverVersionAttr] = pstructs.NewStringAttribute(matches[1])
+	fingerprint.Attributes[rootlessAttr] = pstructs.NewBoolAttribute(os.Geteuid() != 0)
+	return fingerprint
+}
+
+func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
+	if handle == nil {
+		return fmt.Errorf("error: handle cannot be nil")
+	}
+
+	// If already attached to handle there's nothing to recover.
+	if _, ok := d.tasks.Get(handle.Config.ID); ok {
+		d.logger.Trace("nothing to recover; task already exists",
+			"task_id", handle.Config.ID,
+			"task_name", handle.Config.Name,
+		)
+		return nil
+	}
+
+	var taskState TaskState
+	if err := handle.GetDriverState(&taskState); err != nil {
+		d.logger.Error("failed to decode taskConfig state from handle", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to decode taskConfig state from handle: %v", err)
+	}
+
+	h := &taskHandle{
+		bin:           d.podmanBin(),
+		containerID:   taskState.ContainerID,
+		containerName: taskState.ContainerName,
+		taskConfig:    taskState.TaskConfig,
+		procState:     drivers.TaskStateRunning,
+		startedAt:     taskState.StartedAt,
+		exitResult:    &drivers.ExitResult{},
+		doneCh:        make(chan struct{}),
+		logger:        d.logger,
+	}
+
+	d.tasks.Set(taskState.TaskConfig.ID, h)
+
+	// podman wait returns immediately for containers that have already
+	// stopped, so run resolves the container's fate either way.
+	go h.run()
+	return nil
+}
+
+// containerName returns the name the container is created with. Allocation
+// IDs are unique, so restarted or replacement tasks never collide with a
+// previous container.
+func containerName(cfg *drivers.TaskConfig) string {
+	return fmt.Sprintf("%s-%s", cfg.Name, cfg.AllocID)
+}
+
+// registryHost returns the registry component of an image reference,
+// defaulting to the Docker Hub registry for references that are not fully
+// qualified.
+func registryHost(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 {
+		if host := parts[0]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			return host
+		}
+	}
+	return "docker.io"
+}
+
+// writeAuthFile writes a containers auth file holding the task's registry
+// credentials into dir and returns its path.
+func writeAuthFile(dir, image string, auth AuthConfig) (string, error) {
+	creds := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+	contents, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registryHost(image): map[string]string{"auth": creds},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "podman-auth.json")
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		return "", fmt.Errorf("failed to write registry auth file: %v", err)
+	}
+	return path, nil
+}
+
+func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if _, ok := d.tasks.Get(cfg.ID); ok {
+		return nil, nil, fmt.Errorf("taskConfig with ID '%s' already started", cfg.ID)
+	}
+
+	var driverConfig TaskConfig
+	if err := cfg.DecodeDriverConfig(&driverConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	if driverConfig.Image == "" {
+		return nil, nil, fmt.Errorf("image must be set")
+	}
+
+	if len(driverConfig.Volumes) > 0 && !d.config.VolumesEnabled {
+		return nil, nil, fmt.Errorf("volumes are not enabled; cannot mount host paths")
+	}
+
+	// ensure that PortMap variables are populated early on
+	cfg.Env = taskenv.SetPortMapEnvs(cfg.Env, driverConfig.PortMap)
+
+	handle := drivers.NewTaskHandle(taskHandleVersion)
+	handle.Config = cfg
+
+	bin := d.podmanBin()
+
+	// Pull the image up front so that credentials are not needed beyond this
+	// point and run failures surface real errors.
+	pullArgs := []string{"pull"}
+	if driverConfig.Auth.Username != "" {
+		// Pass the registry credentials through an auth file in the task's
+		// secrets directory rather than on the argv, where any process on
+		// the host could read them from /proc while the pull runs.
+		authFile, err := writeAuthFile(cfg.TaskDir().SecretsDir, driverConfig.Image, driverConfig.Auth)
+		if err != nil {
+			return nil, nil, err
+		}
+		pullArgs = append(pullArgs, "--authfile", authFile)
+	}
+	pullArgs = append(pullArgs, driverConfig.Image)
+	if out, err := exec.Command(bin, pullArgs...).CombinedOutput(); err != nil {
+		return nil, nil, fmt.Errorf("failed to pull image %q: %v: %s", driverConfig.Image, err, strings.TrimSpace(string(out)))
+	}
+
+	name := containerName(cfg)
+	runArgs := []string{"run", "--detach", "--name", name}
+
+	// Resource limits from the scheduler
+	if cfg.Resources != nil && cfg.Resources.LinuxResources != nil {
+		if mem := cfg.Resources.LinuxResources.MemoryLimitBytes; mem > 0 {
+			runArgs = append(runArgs, "--memory", strconv.FormatInt(mem, 10))
+		}
+		if shares := cfg.Resources.LinuxResources.CPUShares; shares > 0 {
+			runArgs = append(runArgs, "--cpu-shares", strconv.FormatInt(shares, 10))
+		}
+	}
+
+	if cfg.User != "" {
+		runArgs = append(runArgs, "--user", cfg.User)
+	}
+
+	for _, env := range cfg.EnvList() {
+		runArgs = append(runArgs, "--env", env)
+	}
+
+	// Bind the standard task directories into the container
+	taskDir := cfg.TaskDir()
+	runArgs = append(runArgs,
+		"--volume", fmt.Sprintf("%s:/alloc", taskDir.SharedAllocDir),
+		"--volume", fmt.Sprintf("%s:/local", taskDir.LocalDir),
+		"--volume", fmt.Sprintf("%s:/secrets", taskDir.SecretsDir),
+	)
+
+	// User declared volumes; relative host paths are resolved against the
+	// task directory
+	for _, vol := range driverConfig.Volumes {
+		parts := strings.SplitN(vol, ":", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("invalid volume spec %q, expected host_path:container_path", vol)
+		}
+		if !filepath.IsAbs(parts[0]) {
+			parts[0] = filepath.Join(taskDir.Dir, parts[0])
+		}
+		runArgs = append(runArgs, "--volume", strings.Join(parts, ":"))
+	}
+
+	if driverConfig.NetworkMode != "" {
+		runArgs = append(runArgs, "--network", driverConfig.NetworkMode)
+	}
+
+	// Publish ports from the port map using the reserved host ports
+	if len(driverConfig.PortMap) > 0 {
+		if len(cfg.Resources.NomadResources.Networks) == 0 {
+			return nil, nil, fmt.Errorf("Trying to map ports but no network interface is available")
+		}
+		taskPorts := cfg.Resources.NomadResources.Networks[0].PortLabels()
+		for label, containerPort := range driverConfig.PortMap {
+			hostPort, ok := taskPorts[label]
+			if !ok {
+				return nil, nil, fmt.Errorf("Unknown port label %q", label)
+			}
+			runArgs = append(runArgs, "--publish", fmt.Sprintf("%d:%d", hostPort, containerPort))
+		}
+	}
+
+	runArgs = append(runArgs, driverConfig.Image)
+	if driverConfig.Command != "" {
+		runArgs = append(runArgs, driverConfig.Command)
+	}
+	runArgs = append(runArgs, driverConfig.Args...)
+
+	d.logger.Debug("starting podman container", "args", strings.Join(runArgs, " "))
+
+	out, err := exec.Command(bin, runArgs...).Output()
+	if err != nil {
+		msg := err.Error()
+		if ee, ok := err.(*exec.ExitError); ok {
+			msg = strings.TrimSpace(string(ee.Stderr))
+		}
+		return nil, nil, fmt.Errorf("failed to start container: %s", msg)
+	}
+	containerID := strings.TrimSpace(string(out))
+
+	cleanup := func() {
+		if out, err := exec.Command(bin, "rm", "--force", containerID).CombinedOutput(); err != nil {
+			d.logger.Error("failed to clean up container", "container", containerID, "error", err, "output", string(out))
+		}
+	}
+
+	h := &taskHandle{
+		bin:           bin,
+		containerID:   containerID,
+		containerName: name,
+		taskConfig:    cfg,
+		procState:     drivers.TaskStateRunning,
+		startedAt:     time.Now().Round(time.Millisecond),
+		doneCh:        make(chan struct{}),
+		logger:        d.logger,
+	}
+
+	if err := h.startLogStreaming(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	podmanDriverState := TaskState{
+		ContainerID:   containerID,
+		ContainerName: name,
+		TaskConfig:    cfg,
+		StartedAt:     h.startedAt,
+	}
+
+	if err := handle.SetDriverState(&podmanDriverState); err != nil {
+		d.logger.Error("failed to start task, error setting driver state", "error", err)
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to set driver state: %v", err)
+	}
+
+	d.tasks.Set(cfg.ID, h)
+	go h.run()
+
+	var driverNetwork *drivers.DriverNetwork
+	if len(driverConfig.PortMap) > 0 {
+		driverNetwork = &drivers.DriverNetwork{
+			PortMap: driverConfig.PortMap,
+		}
+	}
+	return handle, driverNetwork, nil
+}
+
+func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.ExitResult)
+	go d.handleWait(ctx, handle, ch)
+
+	return ch, nil
+}
+
+func (d *Driver) handleWait(ctx context.Context, handle *taskHandle, ch chan *drivers.ExitResult) {
+	defer close(ch)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case <-handle.doneCh:
+	}
+
+	result := handle.TaskStatus().ExitResult
+
+	select {
+	case <-ctx.Done():
+	case <-d.ctx.Done():
+	case ch <- result:
+	}
+}
+
+func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if signal != "" {
+		if out, err := exec.Command(handle.bin, "kill", "--signal", signal, handle.containerID).CombinedOutput(); err != nil {
+			d.logger.Debug("error sending signal to container", "container", handle.containerID, "signal", signal, "error", err, "output", string(out))
+		}
+	}
+
+	out, err := exec.Command(handle.bin, "stop", "--time", strconv.Itoa(int(timeout.Seconds())), handle.containerID).CombinedOutput()
+	if err != nil {
+		if handle.IsRunning() {
+			return fmt.Errorf("failed to stop container: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		// The container exited before podman stop got to it; nothing to do.
+	}
+
+	return nil
+}
+
+func (d *Driver) DestroyTask(taskID string, force bool) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if handle.IsRunning() && !force {
+		return fmt.Errorf("cannot destroy running task")
+	}
+
+	if d.config.GCContainer {
+		if out, err := exec.Command(handle.bin, "rm", "--force", handle.containerID).CombinedOutput(); err != nil {
+			d.logger.Error("failed to remove container", "container", handle.containerID, "error", err, "output", string(out))
+		}
+	}
+
+	d.tasks.Delete(taskID)
+	return nil
+}
+
+func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return handle.TaskStatus(), nil
+}
+
+func (d *Driver) TaskStats(ctx context.Context, taskID string, interval time.Duration) (<-chan *drivers.TaskResourceUsage, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.TaskResourceUsage)
+	go d.handleStats(ctx, handle, ch, interval)
+	return ch, nil
+}
+
+func (d *Driver) handleStats(ctx context.Context, handle *taskHandle, ch chan *drivers.TaskResourceUsage, interval time.Duration) {
+	defer close(ch)
+
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(interval)
+		}
+
+		out, err := exec.Command(handle.bin, "stats", "--no-stream", "--format", "json", handle.containerID).Output()
+		if err != nil {
+			d.logger.Debug("error collecting container stats", "container", handle.containerID, "error", err)
+			continue
+		}
+
+		usage, err := parseStats(out)
+		if err != nil {
+			d.logger.Debug("error parsing container stats", "container", handle.containerID, "error", err)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case ch <- usage:
+		}
+	}
+}
+
+// parseStats converts the output of "podman stats --no-stream --format json"
+// into a TaskResourceUsage. Field names have changed between podman releases,
+// so both the old and the current spellings are accepted.
+func parseStats(out []byte) (*drivers.TaskResourceUsage, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(out, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode stats output: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("stats output contained no containers")
+	}
+	row := rows[0]
+
+	cpuPercent, err := parseStatsPercent(statsField(row, "cpu_percent", "CPU"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpu percent: %v", err)
+	}
+
+	memUsage, err := parseStatsBytes(statsField(row, "mem_usage", "MemUsage"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse memory usage: %v", err)
+	}
+
+	cs := &cstructs.CpuStats{
+		Percent:  cpuPercent,
+		Measured: podmanMeasuredCPUStats,
+	}
+	cs.TotalTicks = (cs.Percent / 100) * stats.TotalTicksAvailable() / float64(runtime.NumCPU())
+
+	ms := &cstructs.MemoryStats{
+		Usage:    memUsage,
+		Measured: podmanMeasuredMemStats,
+	}
+
+	return &drivers.TaskResourceUsage{
+		ResourceUsage: &cstructs.ResourceUsage{
+			MemoryStats: ms,
+			CpuStats:    cs,
+		},
+		Timestamp: time.Now().UTC().UnixNano(),
+	}, nil
+}
+
+// statsField returns the first of the named fields present in the stats row
+// as a string.
+func statsField(row map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if v, ok := row[name]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// parseStatsPercent parses values such as "1.02%"
+func parseStatsPercent(v string) (float64, error) {
+	v = strings.TrimSuffix(strings.TrimSpace(v), "%")
+	if v == "" || v == "--" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// parseStatsBytes parses values such as "852KB / 16GB", returning the used
+// portion in bytes
+func parseStatsBytes(v string) (uint64, error) {
+	used := strings.TrimSpace(strings.SplitN(v, "/", 2)[0])
+	if used == "" || used == "--" {
+		return 0, nil
+	}
+	return humanize.ParseBytes(used)
+}
+
+func (d *Driver) TaskEvents(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
+	return d.eventer.TaskEvents(ctx)
+}
+
+func (d *Driver) SignalTask(taskID string, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if out, err := exec.Command(handle.bin, "kill", "--signal", signal, handle.containerID).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to signal container: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (d *Driver) ExecTask(taskID string, cmdArgs []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+	if len(cmdArgs) == 0 {
+		return nil, fmt.Errorf("error cmd must have at least one value")
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, timeout)
+	defer cancel()
+
+	args := append([]string{"exec", handle.containerID}, cmdArgs...)
+	cmd := exec.CommandContext(ctx, handle.bin, args...)
+
+	res := &drivers.ExecTaskResult{ExitResult: &drivers.ExitResult{}}
+	stdout, err := cmd.Output()
+	res.Stdout = stdout
+	if err != nil {
+		ee, ok := err.(*exec.ExitError)
+		if !ok {
+			return nil, fmt.Errorf("failed to exec in container: %v", err)
+		}
+		res.Stderr = ee.Stderr
+		res.ExitResult.ExitCode = ee.ExitCode()
+	}
+	return res, nil
+}
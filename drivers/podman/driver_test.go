@@ -0,0 +1,112 @@
+package podman
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodmanDriver_ContainerName(t *testing.T) {
+	t.Parallel()
+
+	cfg := &drivers.TaskConfig{
+		Name:    "redis",
+		AllocID: "5b9c3f1a-00ae-48ef-9a96-fbb496daceaf",
+	}
+	require.Equal(t, "redis-5b9c3f1a-00ae-48ef-9a96-fbb496daceaf", containerName(cfg))
+}
+
+func TestPodmanDriver_RegistryHost(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "docker.io", registryHost("redis:6"))
+	require.Equal(t, "docker.io", registryHost("library/redis"))
+	require.Equal(t, "registry.example.com", registryHost("registry.example.com/app/redis:6"))
+	require.Equal(t, "localhost:5000", registryHost("localhost:5000/redis"))
+	require.Equal(t, "localhost", registryHost("localhost/redis"))
+}
+
+func TestPodmanDriver_WriteAuthFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "nomadtest-podman-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path, err := writeAuthFile(dir, "registry.example.com/app/redis:6", AuthConfig{
+		Username: "user",
+		Password: "secret",
+	})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var parsed struct {
+		Auths map[string]struct {
+			Auth string
+		}
+	}
+	require.NoError(t, json.Unmarshal(raw, &parsed))
+	// base64("user:secret")
+	require.Equal(t, "dXNlcjpzZWNyZXQ=", parsed.Auths["registry.example.com"].Auth)
+}
+
+func TestPodmanDriver_ParseStats(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		output     string
+		cpuPercent float64
+		memUsage   uint64
+	}{
+		{
+			name:       "podman 1.x field names",
+			output:     `[{"name": "web-123", "cpu_percent": "1.25%", "mem_usage": "852KB / 16GB"}]`,
+			cpuPercent: 1.25,
+			memUsage:   852000,
+		},
+		{
+			name:       "podman 2.x field names",
+			output:     `[{"Name": "web-123", "CPU": "0.50%", "MemUsage": "1.5MB / 16GB"}]`,
+			cpuPercent: 0.5,
+			memUsage:   1500000,
+		},
+		{
+			name:       "stopped container placeholders",
+			output:     `[{"name": "web-123", "cpu_percent": "--", "mem_usage": "-- / --"}]`,
+			cpuPercent: 0,
+			memUsage:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			usage, err := parseStats([]byte(tc.output))
+			require.NoError(t, err)
+			require.Equal(t, tc.cpuPercent, usage.ResourceUsage.CpuStats.Percent)
+			require.Equal(t, tc.memUsage, usage.ResourceUsage.MemoryStats.Usage)
+		})
+	}
+}
+
+func TestPodmanDriver_ParseStats_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseStats([]byte("not json"))
+	require.Error(t, err)
+
+	_, err = parseStats([]byte("[]"))
+	require.Error(t, err)
+
+	_, err = parseStats([]byte(`[{"cpu_percent": "bogus", "mem_usage": "1MB / 2MB"}]`))
+	require.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package podman
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+type taskHandle struct {
+	// bin is the path to the podman binary managing the container
+	bin           string
+	containerID   string
+	containerName string
+	logger        hclog.Logger
+
+	// doneCh is closed when run exits and the container's exit result has
+	// been recorded
+	doneCh chan struct{}
+
+	// stateLock syncs access to all fields below
+	stateLock sync.RWMutex
+
+	taskConfig  *drivers.TaskConfig
+	procState   drivers.TaskState
+	startedAt   time.Time
+	completedAt time.Time
+	exitResult  *drivers.ExitResult
+}
+
+func (h *taskHandle) TaskStatus() *drivers.TaskStatus {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+
+	return &drivers.TaskStatus{
+		ID:          h.taskConfig.ID,
+		Name:        h.taskConfig.Name,
+		State:       h.procState,
+		StartedAt:   h.startedAt,
+		CompletedAt: h.completedAt,
+		ExitResult:  h.exitResult,
+		DriverAttributes: map[string]string{
+			"container_id":   h.containerID,
+			"container_name": h.containerName,
+		},
+	}
+}
+
+func (h *taskHandle) IsRunning() bool {
+	h.stateLock.RLock()
+	defer h.stateLock.RUnlock()
+	return h.procState == drivers.TaskStateRunning
+}
+
+// startLogStreaming launches a "podman logs --follow" process with its output
+// wired to the task's log fifos. The process exits on its own once the
+// container stops.
+func (h *taskHandle) startLogStreaming() error {
+	stdout, err := fifo.OpenWriter(h.taskConfig.StdoutPath)
+	if err != nil {
+		return fmt.Errorf("failed to open stdout fifo: %v", err)
+	}
+	stderr, err := fifo.OpenWriter(h.taskConfig.StderrPath)
+	if err != nil {
+		stdout.Close()
+		return fmt.Errorf("failed to open stderr fifo: %v", err)
+	}
+
+	cmd := exec.Command(h.bin, "logs", "--follow", h.containerID)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		stdout.Close()
+		stderr.Close()
+		return fmt.Errorf("failed to start log streaming: %v", err)
+	}
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			h.logger.Debug("log streaming process exited", "container", h.containerID, "error", err)
+		}
+		stdout.Close()
+		stderr.Close()
+	}()
+	return nil
+}
+
+// run blocks on "podman wait" until the container exits and records the exit
+// result. podman wait returns immediately for containers that have already
+// stopped, so run is safe to use during task recovery as well.
+func (h *taskHandle) run() {
+	defer close(h.doneCh)
+
+	h.stateLock.Lock()
+	if h.exitResult == nil {
+		h.exitResult = &drivers.ExitResult{}
+	}
+	h.stateLock.Unlock()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(h.bin, "wait", h.containerID)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	h.stateLock.Lock()
+	defer h.stateLock.Unlock()
+
+	if err != nil {
+		h.exitResult.Err = fmt.Errorf("podman wait failed: %v: %s", err, stderr.String())
+		h.procState = drivers.TaskStateUnknown
+		h.completedAt = time.Now()
+		return
+	}
+
+	exitCode, err := strconv.Atoi(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		h.exitResult.Err = fmt.Errorf("failed to parse container exit code %q: %v", stdout.String(), err)
+		h.procState = drivers.TaskStateUnknown
+		h.completedAt = time.Now()
+		return
+	}
+
+	h.procState = drivers.TaskStateExited
+	h.exitResult.ExitCode = exitCode
+	h.completedAt = time.Now()
+}
@@ -0,0 +1,114 @@
+// Package crontab parses the cron specifications used by periodic jobs. It
+// wraps the cronexpr library, adding support for the @every descriptor and
+// stricter validation so malformed specs are rejected at job submission
+// instead of producing surprising launch times. Plain five field specs keep
+// their existing behavior; seconds and year fields remain optional.
+package crontab
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+)
+
+// descriptors is the set of @ shorthands accepted in place of a full cron
+// spec, excluding @every which takes a duration argument.
+var descriptors = map[string]struct{}{
+	"@yearly":   {},
+	"@annually": {},
+	"@monthly":  {},
+	"@weekly":   {},
+	"@daily":    {},
+	"@hourly":   {},
+}
+
+// Expression is a parsed cron specification that can compute launch times.
+type Expression struct {
+	// every is the interval of an @every descriptor and zero for any other
+	// spec.
+	every time.Duration
+
+	// expr is the parsed cron expression and nil for @every descriptors.
+	expr *cronexpr.Expression
+}
+
+// Parse parses a cron specification. It accepts the descriptors @yearly,
+// @annually, @monthly, @weekly, @daily and @hourly, intervals of the form
+// "@every <duration>", and cron specs of five to seven fields where the
+// seconds and year fields are optional.
+func Parse(spec string) (*Expression, error) {
+	trimmed := strings.TrimSpace(spec)
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty cron spec")
+	}
+
+	if strings.HasPrefix(trimmed, "@") {
+		return parseDescriptor(trimmed)
+	}
+
+	// Validate the field count before handing the spec to cronexpr so the
+	// error describes what is wrong rather than how the parse failed.
+	if fields := len(strings.Fields(trimmed)); fields < 5 || fields > 7 {
+		return nil, fmt.Errorf("cron spec requires 5 to 7 fields, %q has %d", trimmed, fields)
+	}
+
+	expr, err := cronexpr.Parse(trimmed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{expr: expr}, nil
+}
+
+// parseDescriptor parses the @ shorthands.
+func parseDescriptor(spec string) (*Expression, error) {
+	if arg := strings.TrimPrefix(spec, "@every"); arg != spec {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			return nil, fmt.Errorf("@every requires a duration, e.g. \"@every 10m\"")
+		}
+
+		every, err := time.ParseDuration(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration %q: %v", arg, err)
+		}
+		if every < time.Second {
+			return nil, fmt.Errorf("@every duration must be at least 1s, got %q", arg)
+		}
+
+		return &Expression{every: every}, nil
+	}
+
+	if _, ok := descriptors[spec]; !ok {
+		return nil, fmt.Errorf("unknown cron descriptor %q", spec)
+	}
+
+	expr, err := cronexpr.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{expr: expr}, nil
+}
+
+// Next returns the next time matching the expression after the passed time,
+// capturing any panic that may occur in the underlying library. If no
+// matching instance exists, the zero value of time.Time is returned.
+func (e *Expression) Next(fromTime time.Time) (t time.Time, err error) {
+	if e.every != 0 {
+		// Schedule the next launch a full interval out, dropping any
+		// sub-second remainder so repeated launches stay aligned.
+		return fromTime.Add(e.every - time.Duration(fromTime.Nanosecond())), nil
+	}
+
+	defer func() {
+		if recover() != nil {
+			t = time.Time{}
+			err = fmt.Errorf("failed computing next launch time")
+		}
+	}()
+
+	return e.expr.Next(fromTime), nil
+}
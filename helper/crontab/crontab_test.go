@@ -0,0 +1,92 @@
+package crontab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Valid(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{
+		// Plain five field specs
+		"* * * * *",
+		"*/5 * * * *",
+		"1 15 * * 1-5",
+
+		// Optional seconds and year fields
+		"30 * * * * *",
+		"0 30 9 * * * 2026",
+
+		// Descriptors
+		"@hourly",
+		"@daily",
+		"@weekly",
+		"@monthly",
+		"@annually",
+		"@yearly",
+		"@every 10m",
+		"@every 1h30m",
+	}
+
+	for _, spec := range cases {
+		_, err := Parse(spec)
+		require.NoError(t, err, "spec %q", spec)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		spec     string
+		contains string
+	}{
+		{"", "empty"},
+		{"* * *", "5 to 7 fields"},
+		{"* * * * * * * *", "5 to 7 fields"},
+		{"@fortnightly", "unknown cron descriptor"},
+		{"@every", "requires a duration"},
+		{"@every banana", "invalid @every duration"},
+		{"@every 10ms", "at least 1s"},
+		{"@every -1h", "at least 1s"},
+		{"61 * * * *", "syntax error"},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.spec)
+		require.Error(t, err, "spec %q", c.spec)
+		require.Contains(t, err.Error(), c.contains, "spec %q", c.spec)
+	}
+}
+
+func TestExpression_Next_Cron(t *testing.T) {
+	t.Parallel()
+
+	e, err := Parse("1 15 * * *")
+	require.NoError(t, err)
+
+	from := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	next, err := e.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, time.January, 1, 15, 1, 0, 0, time.UTC), next)
+}
+
+func TestExpression_Next_Every(t *testing.T) {
+	t.Parallel()
+
+	e, err := Parse("@every 10m")
+	require.NoError(t, err)
+
+	from := time.Date(2026, time.January, 1, 12, 0, 0, 500, time.UTC)
+	next, err := e.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, time.January, 1, 12, 10, 0, 0, time.UTC), next)
+
+	// Launches stay aligned to the interval
+	next2, err := e.Next(next)
+	require.NoError(t, err)
+	require.Equal(t, next.Add(10*time.Minute), next2)
+}
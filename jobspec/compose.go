@@ -0,0 +1,264 @@
+package jobspec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// CollectIncludes expands the given include paths into the list of job
+// fragment files they contain. Directories contribute every .hcl and .nomad
+// file they hold, non-recursively. The result is sorted lexically so merge
+// order is deterministic regardless of how the paths were supplied.
+func CollectIncludes(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading include %q: %v", path, err)
+		}
+
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := filepath.Glob(filepath.Join(path, "*"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading include %q: %v", path, err)
+		}
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry)) {
+			case ".hcl", ".nomad":
+				files = append(files, entry)
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// MergeJob merges a job fragment into the job, filling in values the job
+// does not set itself. Values set by the job always win; list and map
+// entries the job lacks are taken from the fragment, and task groups and
+// tasks are merged by name. The fragment's ID and Name are ignored.
+func MergeJob(job, fragment *api.Job) {
+	if job.Region == nil {
+		job.Region = fragment.Region
+	}
+	if job.Namespace == nil {
+		job.Namespace = fragment.Namespace
+	}
+	if job.Type == nil {
+		job.Type = fragment.Type
+	}
+	if job.Priority == nil {
+		job.Priority = fragment.Priority
+	}
+	if job.AllAtOnce == nil {
+		job.AllAtOnce = fragment.AllAtOnce
+	}
+	if len(job.Datacenters) == 0 {
+		job.Datacenters = fragment.Datacenters
+	}
+	if job.Update == nil {
+		job.Update = fragment.Update
+	}
+	if job.Multiregion == nil {
+		job.Multiregion = fragment.Multiregion
+	}
+	if job.Periodic == nil {
+		job.Periodic = fragment.Periodic
+	}
+	if job.ParameterizedJob == nil {
+		job.ParameterizedJob = fragment.ParameterizedJob
+	}
+	if job.Reschedule == nil {
+		job.Reschedule = fragment.Reschedule
+	}
+	if job.Migrate == nil {
+		job.Migrate = fragment.Migrate
+	}
+	if job.ConsulToken == nil {
+		job.ConsulToken = fragment.ConsulToken
+	}
+	if job.VaultToken == nil {
+		job.VaultToken = fragment.VaultToken
+	}
+	if job.VaultNamespace == nil {
+		job.VaultNamespace = fragment.VaultNamespace
+	}
+
+	job.Constraints = append(job.Constraints, fragment.Constraints...)
+	job.Affinities = append(job.Affinities, fragment.Affinities...)
+	job.Spreads = append(job.Spreads, fragment.Spreads...)
+	job.Meta = mergeMap(job.Meta, fragment.Meta)
+
+	for _, ftg := range fragment.TaskGroups {
+		tg := findTaskGroup(job, ftg.Name)
+		if tg == nil {
+			job.TaskGroups = append(job.TaskGroups, ftg)
+			continue
+		}
+		mergeTaskGroup(tg, ftg)
+	}
+}
+
+// mergeTaskGroup merges a fragment task group into a task group of the same
+// name, filling in values the group does not set itself.
+func mergeTaskGroup(tg, fragment *api.TaskGroup) {
+	if tg.Count == nil {
+		tg.Count = fragment.Count
+	}
+	if tg.RestartPolicy == nil {
+		tg.RestartPolicy = fragment.RestartPolicy
+	}
+	if tg.ReschedulePolicy == nil {
+		tg.ReschedulePolicy = fragment.ReschedulePolicy
+	}
+	if tg.EphemeralDisk == nil {
+		tg.EphemeralDisk = fragment.EphemeralDisk
+	}
+	if tg.Update == nil {
+		tg.Update = fragment.Update
+	}
+	if tg.Migrate == nil {
+		tg.Migrate = fragment.Migrate
+	}
+	if tg.Scaling == nil {
+		tg.Scaling = fragment.Scaling
+	}
+	if tg.ShutdownDelay == nil {
+		tg.ShutdownDelay = fragment.ShutdownDelay
+	}
+	if tg.StopAfterClientDisconnect == nil {
+		tg.StopAfterClientDisconnect = fragment.StopAfterClientDisconnect
+	}
+	if len(tg.Networks) == 0 {
+		tg.Networks = fragment.Networks
+	}
+	if len(tg.Services) == 0 {
+		tg.Services = fragment.Services
+	}
+	if len(tg.Volumes) == 0 {
+		tg.Volumes = fragment.Volumes
+	}
+
+	tg.Constraints = append(tg.Constraints, fragment.Constraints...)
+	tg.Affinities = append(tg.Affinities, fragment.Affinities...)
+	tg.Spreads = append(tg.Spreads, fragment.Spreads...)
+	tg.Meta = mergeMap(tg.Meta, fragment.Meta)
+
+	for _, ft := range fragment.Tasks {
+		t := findTask(tg, ft.Name)
+		if t == nil {
+			tg.Tasks = append(tg.Tasks, ft)
+			continue
+		}
+		mergeTask(t, ft)
+	}
+}
+
+// mergeTask merges a fragment task into a task of the same name, filling in
+// values the task does not set itself.
+func mergeTask(t, fragment *api.Task) {
+	if t.Driver == "" {
+		t.Driver = fragment.Driver
+	}
+	if t.User == "" {
+		t.User = fragment.User
+	}
+	if t.KillSignal == "" {
+		t.KillSignal = fragment.KillSignal
+	}
+	if t.Lifecycle == nil {
+		t.Lifecycle = fragment.Lifecycle
+	}
+	if t.Resources == nil {
+		t.Resources = fragment.Resources
+	}
+	if t.RestartPolicy == nil {
+		t.RestartPolicy = fragment.RestartPolicy
+	}
+	if t.KillTimeout == nil {
+		t.KillTimeout = fragment.KillTimeout
+	}
+	if t.LogConfig == nil {
+		t.LogConfig = fragment.LogConfig
+	}
+	if t.Vault == nil {
+		t.Vault = fragment.Vault
+	}
+	if t.DispatchPayload == nil {
+		t.DispatchPayload = fragment.DispatchPayload
+	}
+	if len(t.Services) == 0 {
+		t.Services = fragment.Services
+	}
+	if len(t.Artifacts) == 0 {
+		t.Artifacts = fragment.Artifacts
+	}
+	if len(t.Templates) == 0 {
+		t.Templates = fragment.Templates
+	}
+	if len(t.VolumeMounts) == 0 {
+		t.VolumeMounts = fragment.VolumeMounts
+	}
+
+	t.Constraints = append(t.Constraints, fragment.Constraints...)
+	t.Affinities = append(t.Affinities, fragment.Affinities...)
+	t.Env = mergeMap(t.Env, fragment.Env)
+	t.Meta = mergeMap(t.Meta, fragment.Meta)
+
+	if len(t.Config) == 0 {
+		t.Config = fragment.Config
+	} else {
+		for k, v := range fragment.Config {
+			if _, ok := t.Config[k]; !ok {
+				t.Config[k] = v
+			}
+		}
+	}
+}
+
+// mergeMap fills keys missing from m with those of fragment
+func mergeMap(m, fragment map[string]string) map[string]string {
+	if len(fragment) == 0 {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]string, len(fragment))
+	}
+	for k, v := range fragment {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func findTaskGroup(job *api.Job, name *string) *api.TaskGroup {
+	if name == nil {
+		return nil
+	}
+	for _, tg := range job.TaskGroups {
+		if tg.Name != nil && *tg.Name == *name {
+			return tg
+		}
+	}
+	return nil
+}
+
+func findTask(tg *api.TaskGroup, name string) *api.Task {
+	for _, t := range tg.Tasks {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package jobspec
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectIncludes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Directories expand to their .hcl and .nomad files, sorted
+	files, err := CollectIncludes([]string{filepath.Join("test-fixtures", "include")})
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join("test-fixtures", "include", "a-common.hcl"),
+		filepath.Join("test-fixtures", "include", "b-override.hcl"),
+	}, files)
+
+	// Files are passed through
+	files, err = CollectIncludes([]string{filepath.Join("test-fixtures", "include", "b-override.hcl")})
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join("test-fixtures", "include", "b-override.hcl"),
+	}, files)
+
+	// Missing paths error
+	_, err = CollectIncludes([]string{filepath.Join("test-fixtures", "does-not-exist")})
+	require.Error(err)
+}
+
+func TestMergeJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job, err := ParseFile(filepath.Join("test-fixtures", "include-main.hcl"))
+	require.NoError(err)
+
+	includes, err := CollectIncludes([]string{filepath.Join("test-fixtures", "include")})
+	require.NoError(err)
+	for _, file := range includes {
+		fragment, err := ParseFile(file)
+		require.NoError(err)
+		MergeJob(job, fragment)
+	}
+
+	// Job-level fields fill from the first fragment to set them
+	require.Equal("global", *job.Region)
+	require.Equal(70, *job.Priority)
+	require.Equal([]string{"dc1"}, job.Datacenters)
+	require.Equal(map[string]string{"owner": "platform", "team": "sre"}, job.Meta)
+
+	// The web group merges: main's count wins, the fragment's restart
+	// policy, extra task, and task fields fill in
+	require.Len(job.TaskGroups, 2)
+	web := job.TaskGroups[0]
+	require.Equal("web", *web.Name)
+	require.Equal(3, *web.Count)
+	require.NotNil(web.RestartPolicy)
+	require.Equal(5, *web.RestartPolicy.Attempts)
+
+	require.Len(web.Tasks, 2)
+	server := web.Tasks[0]
+	require.Equal("server", server.Name)
+	require.Equal("docker", server.Driver)
+	require.Equal("nginx:1.19", server.Config["image"])
+	require.Equal("info", server.Env["LOG_LEVEL"])
+	require.Equal(500, *server.Resources.CPU)
+	require.Equal("sidecar", web.Tasks[1].Name)
+
+	// The cache group comes entirely from the fragment
+	require.Equal("cache", *job.TaskGroups[1].Name)
+
+	// The fragment job names are ignored
+	require.Equal("composed", *job.ID)
+}
@@ -29,6 +29,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "expiration")
+	delete(m, "ownership")
 	delete(m, "meta")
 	delete(m, "migrate")
 	delete(m, "parameterized")
@@ -44,7 +46,15 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	result.Name = stringToPtr(*result.ID)
 
 	// Decode the rest
-	if err := mapstructure.WeakDecode(m, result); err != nil {
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
 		return err
 	}
 
@@ -63,6 +73,9 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"affinity",
 		"spread",
 		"datacenters",
+		"expiration",
+		"gc_threshold",
+		"ownership",
 		"group",
 		"id",
 		"meta",
@@ -114,6 +127,20 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have an expiration definition, then parse that
+	if o := listVal.Filter("expiration"); len(o.Items) > 0 {
+		if err := parseExpiration(&result.Expiration, o); err != nil {
+			return multierror.Prefix(err, "expiration ->")
+		}
+	}
+
+	// If we have an ownership definition, then parse that
+	if o := listVal.Filter("ownership"); len(o.Items) > 0 {
+		if err := parseOwnership(&result.Ownership, o); err != nil {
+			return multierror.Prefix(err, "ownership ->")
+		}
+	}
+
 	// Parse spread
 	if o := listVal.Filter("spread"); len(o.Items) > 0 {
 		if err := parseSpread(&result.Spreads, o); err != nil {
@@ -260,6 +287,80 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseExpiration(result **api.JobExpiration, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'expiration' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"at",
+		"ttl",
+		"purge",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the expiration config
+	var e api.JobExpiration
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &e,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+	*result = &e
+	return nil
+}
+
+func parseOwnership(result **api.JobOwnership, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'ownership' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"team",
+		"contact",
+		"repo_url",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the ownership metadata
+	var ownership api.JobOwnership
+	if err := mapstructure.WeakDecode(m, &ownership); err != nil {
+		return err
+	}
+	*result = &ownership
+	return nil
+}
+
 func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -49,6 +49,7 @@ func parseService(o *ast.ObjectItem) (*api.Service, error) {
 		"task",
 		"meta",
 		"canary_meta",
+		"provider",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return nil, err
@@ -224,6 +225,8 @@ func parseGateway(o *ast.ObjectItem) (*api.ConsulGateway, error) {
 	valid := []string{
 		"proxy",
 		"ingress",
+		"terminating",
+		"mesh",
 	}
 
 	if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -238,6 +241,8 @@ func parseGateway(o *ast.ObjectItem) (*api.ConsulGateway, error) {
 
 	delete(m, "proxy")
 	delete(m, "ingress")
+	delete(m, "terminating")
+	delete(m, "mesh")
 
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
@@ -261,26 +266,42 @@ func parseGateway(o *ast.ObjectItem) (*api.ConsulGateway, error) {
 
 	// extract and parse the proxy block
 	po := listVal.Filter("proxy")
-	if len(po.Items) != 1 {
-		return nil, fmt.Errorf("must have one 'proxy' block")
-	}
-	proxy, err := parseGatewayProxy(po.Items[0])
-	if err != nil {
-		return nil, fmt.Errorf("proxy, %v", err)
+	if len(po.Items) > 1 {
+		return nil, fmt.Errorf("only one 'proxy' block allowed")
+	} else if len(po.Items) == 1 {
+		proxy, err := parseGatewayProxy(po.Items[0])
+		if err != nil {
+			return nil, fmt.Errorf("proxy, %v", err)
+		}
+		gateway.Proxy = proxy
 	}
-	gateway.Proxy = proxy
 
-	// extract and parse the ingress block
+	// extract and parse the configuration entry blocks, exactly one of which
+	// must be present
 	io := listVal.Filter("ingress")
-	if len(io.Items) != 1 {
-		// in the future, may be terminating or mesh block instead
-		return nil, fmt.Errorf("must have one 'ingress' block")
+	to := listVal.Filter("terminating")
+	mo := listVal.Filter("mesh")
+	if n := len(io.Items) + len(to.Items) + len(mo.Items); n != 1 {
+		return nil, fmt.Errorf("must have one 'ingress', 'terminating', or 'mesh' block")
 	}
-	ingress, err := parseIngressConfigEntry(io.Items[0])
-	if err != nil {
-		return nil, fmt.Errorf("ingress, %v", err)
+
+	switch {
+	case len(io.Items) == 1:
+		ingress, err := parseIngressConfigEntry(io.Items[0])
+		if err != nil {
+			return nil, fmt.Errorf("ingress, %v", err)
+		}
+		gateway.Ingress = ingress
+	case len(to.Items) == 1:
+		terminating, err := parseTerminatingConfigEntry(to.Items[0])
+		if err != nil {
+			return nil, fmt.Errorf("terminating, %v", err)
+		}
+		gateway.Terminating = terminating
+	case len(mo.Items) == 1:
+		// mesh gateways have no configurable fields
+		gateway.Mesh = &api.ConsulMeshConfigEntry{}
 	}
-	gateway.Ingress = ingress
 
 	return &gateway, nil
 }
@@ -543,6 +564,73 @@ func parseIngressConfigEntry(o *ast.ObjectItem) (*api.ConsulIngressConfigEntry,
 	return &ingress, nil
 }
 
+func parseTerminatingConfigEntry(o *ast.ObjectItem) (*api.ConsulTerminatingConfigEntry, error) {
+	valid := []string{
+		"service",
+	}
+
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return nil, multierror.Prefix(err, "terminating ->")
+	}
+
+	var terminating api.ConsulTerminatingConfigEntry
+
+	// Parse service(s)
+
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return nil, fmt.Errorf("terminating: should be an object")
+	}
+
+	so := listVal.Filter("service")
+	if len(so.Items) > 0 {
+		terminating.Services = make([]*api.ConsulLinkedService, len(so.Items))
+		for i := range so.Items {
+			service, err := parseConsulLinkedService(so.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			terminating.Services[i] = service
+		}
+	}
+
+	return &terminating, nil
+}
+
+func parseConsulLinkedService(o *ast.ObjectItem) (*api.ConsulLinkedService, error) {
+	valid := []string{
+		"name",
+		"ca_file",
+		"cert_file",
+		"key_file",
+		"sni",
+	}
+
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return nil, multierror.Prefix(err, "service ->")
+	}
+
+	var service api.ConsulLinkedService
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result: &service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("service: %v", err)
+	}
+
+	return &service, nil
+}
+
 func parseSidecarService(o *ast.ObjectItem) (*api.ConsulSidecarService, error) {
 	valid := []string{
 		"port",
@@ -838,6 +838,84 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"gc-threshold.hcl",
+			&api.Job{
+				ID:          stringToPtr("foo"),
+				Name:        stringToPtr("foo"),
+				Type:        stringToPtr("batch"),
+				GCThreshold: timeToPtr(4 * time.Hour),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "hashicorp/image",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"expiration.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Type: stringToPtr("batch"),
+				Expiration: &api.JobExpiration{
+					TTL:   timeToPtr(24 * time.Hour),
+					Purge: boolToPtr(true),
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "hashicorp/image",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"ownership.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Ownership: &api.JobOwnership{
+					Team:    stringToPtr("platform"),
+					Contact: stringToPtr("platform-oncall@example.com"),
+					RepoURL: stringToPtr("https://github.com/example/foo"),
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "hashicorp/image",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-check-driver-address.hcl",
 			&api.Job{
@@ -1464,6 +1542,57 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-service-connect-gateway-terminating.hcl",
+			&api.Job{
+				ID:   stringToPtr("connect_gateway_terminating"),
+				Name: stringToPtr("connect_gateway_terminating"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Services: []*api.Service{{
+						Name: "terminating-gateway-service",
+						Connect: &api.ConsulConnect{
+							Gateway: &api.ConsulGateway{
+								Proxy: &api.ConsulGatewayProxy{
+									ConnectTimeout: timeToPtr(3 * time.Second),
+								},
+								Terminating: &api.ConsulTerminatingConfigEntry{
+									Services: []*api.ConsulLinkedService{{
+										Name:     "service1",
+										CAFile:   "ca.pem",
+										CertFile: "cert.pem",
+										KeyFile:  "key.pem",
+									}, {
+										Name: "service2",
+										SNI:  "myhost",
+									}},
+								},
+							},
+						},
+					}},
+				}},
+			},
+			false,
+		},
+		{
+			"tg-service-connect-gateway-mesh.hcl",
+			&api.Job{
+				ID:   stringToPtr("connect_gateway_mesh"),
+				Name: stringToPtr("connect_gateway_mesh"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Services: []*api.Service{{
+						Name: "mesh-gateway-service",
+						Connect: &api.ConsulConnect{
+							Gateway: &api.ConsulGateway{
+								Mesh: &api.ConsulMeshConfigEntry{},
+							},
+						},
+					}},
+				}},
+			},
+			false,
+		},
 		{
 			"tg-scaling-policy-minimal.hcl",
 			&api.Job{
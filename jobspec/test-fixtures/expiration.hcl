@@ -0,0 +1,16 @@
+job "foo" {
+  type = "batch"
+
+  expiration {
+    ttl   = "24h"
+    purge = true
+  }
+
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "hashicorp/image"
+    }
+  }
+}
@@ -0,0 +1,12 @@
+job "foo" {
+  type         = "batch"
+  gc_threshold = "4h"
+
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "hashicorp/image"
+    }
+  }
+}
@@ -0,0 +1,15 @@
+job "composed" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    count = 3
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "nginx:1.19"
+      }
+    }
+  }
+}
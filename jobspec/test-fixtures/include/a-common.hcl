@@ -0,0 +1,50 @@
+job "common" {
+  region   = "global"
+  priority = 70
+
+  meta {
+    owner = "platform"
+  }
+
+  group "web" {
+    count = 1
+
+    restart {
+      attempts = 5
+      interval = "10m"
+      delay    = "15s"
+      mode     = "delay"
+    }
+
+    task "server" {
+      env {
+        LOG_LEVEL = "info"
+      }
+
+      resources {
+        cpu    = 500
+        memory = 256
+      }
+    }
+
+    task "sidecar" {
+      driver = "exec"
+
+      config {
+        command = "/bin/logshipper"
+      }
+    }
+  }
+
+  group "cache" {
+    count = 1
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = "redis:6"
+      }
+    }
+  }
+}
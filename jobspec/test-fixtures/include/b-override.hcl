@@ -0,0 +1,9 @@
+job "override" {
+  region   = "europe"
+  priority = 30
+
+  meta {
+    owner = "ops"
+    team  = "sre"
+  }
+}
@@ -0,0 +1,15 @@
+job "foo" {
+  ownership {
+    team     = "platform"
+    contact  = "platform-oncall@example.com"
+    repo_url = "https://github.com/example/foo"
+  }
+
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "hashicorp/image"
+    }
+  }
+}
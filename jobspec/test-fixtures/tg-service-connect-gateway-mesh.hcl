@@ -0,0 +1,13 @@
+job "connect_gateway_mesh" {
+  group "group" {
+    service {
+      name = "mesh-gateway-service"
+
+      connect {
+        gateway {
+          mesh {}
+        }
+      }
+    }
+  }
+}
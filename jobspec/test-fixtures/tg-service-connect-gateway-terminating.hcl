@@ -0,0 +1,27 @@
+job "connect_gateway_terminating" {
+  group "group" {
+    service {
+      name = "terminating-gateway-service"
+
+      connect {
+        gateway {
+          proxy {
+            connect_timeout = "3s"
+          }
+          terminating {
+            service {
+              name      = "service1"
+              ca_file   = "ca.pem"
+              cert_file = "cert.pem"
+              key_file  = "key.pem"
+            }
+            service {
+              name = "service2"
+              sni  = "myhost"
+            }
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,226 @@
+package jobspec2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Functions returns the functions available to HCL2 job specification
+// expressions. Relative paths passed to the filesystem functions are
+// resolved against basedir; when allowFS is false the filesystem functions
+// are omitted entirely so untrusted specs cannot read local files.
+func Functions(basedir string, allowFS bool) map[string]function.Function {
+	funcs := map[string]function.Function{
+		"abs":             stdlib.AbsoluteFunc,
+		"ceil":            stdlib.CeilFunc,
+		"chomp":           stdlib.ChompFunc,
+		"chunklist":       stdlib.ChunklistFunc,
+		"coalesce":        stdlib.CoalesceFunc,
+		"coalescelist":    stdlib.CoalesceListFunc,
+		"compact":         stdlib.CompactFunc,
+		"concat":          stdlib.ConcatFunc,
+		"contains":        stdlib.ContainsFunc,
+		"csvdecode":       stdlib.CSVDecodeFunc,
+		"distinct":        stdlib.DistinctFunc,
+		"element":         stdlib.ElementFunc,
+		"flatten":         stdlib.FlattenFunc,
+		"floor":           stdlib.FloorFunc,
+		"format":          stdlib.FormatFunc,
+		"formatdate":      stdlib.FormatDateFunc,
+		"formatlist":      stdlib.FormatListFunc,
+		"indent":          stdlib.IndentFunc,
+		"join":            stdlib.JoinFunc,
+		"jsondecode":      stdlib.JSONDecodeFunc,
+		"jsonencode":      stdlib.JSONEncodeFunc,
+		"keys":            stdlib.KeysFunc,
+		"length":          stdlib.LengthFunc,
+		"log":             stdlib.LogFunc,
+		"lookup":          stdlib.LookupFunc,
+		"lower":           stdlib.LowerFunc,
+		"max":             stdlib.MaxFunc,
+		"merge":           stdlib.MergeFunc,
+		"min":             stdlib.MinFunc,
+		"parseint":        stdlib.ParseIntFunc,
+		"pow":             stdlib.PowFunc,
+		"range":           stdlib.RangeFunc,
+		"regex":           stdlib.RegexFunc,
+		"regexall":        stdlib.RegexAllFunc,
+		"regex_replace":   stdlib.RegexReplaceFunc,
+		"replace":         stdlib.ReplaceFunc,
+		"reverse":         stdlib.ReverseListFunc,
+		"setintersection": stdlib.SetIntersectionFunc,
+		"setproduct":      stdlib.SetProductFunc,
+		"setunion":        stdlib.SetUnionFunc,
+		"signum":          stdlib.SignumFunc,
+		"slice":           stdlib.SliceFunc,
+		"sort":            stdlib.SortFunc,
+		"split":           stdlib.SplitFunc,
+		"strlen":          stdlib.StrlenFunc,
+		"strrev":          stdlib.ReverseFunc,
+		"substr":          stdlib.SubstrFunc,
+		"timeadd":         stdlib.TimeAddFunc,
+		"title":           stdlib.TitleFunc,
+		"trim":            stdlib.TrimFunc,
+		"trimprefix":      stdlib.TrimPrefixFunc,
+		"trimspace":       stdlib.TrimSpaceFunc,
+		"trimsuffix":      stdlib.TrimSuffixFunc,
+		"upper":           stdlib.UpperFunc,
+		"values":          stdlib.ValuesFunc,
+		"yamldecode":      yamlDecodeFunc,
+		"zipmap":          stdlib.ZipmapFunc,
+	}
+
+	if allowFS {
+		funcs["file"] = fileFunc(basedir)
+		funcs["templatefile"] = templateFileFunc(basedir)
+	}
+
+	return funcs
+}
+
+// resolvePath resolves relative paths against the directory of the job
+// specification.
+func resolvePath(basedir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(basedir, path)
+}
+
+// fileFunc reads the contents of a file as a string.
+func fileFunc(basedir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			src, err := ioutil.ReadFile(resolvePath(basedir, args[0].AsString()))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			return cty.StringVal(string(src)), nil
+		},
+	})
+}
+
+// templateFileFunc renders a file as an HCL2 template, exposing the given
+// variables to its expressions. The filesystem functions are not available
+// inside the template to prevent recursion.
+func templateFileFunc(basedir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+			{Name: "vars", Type: cty.DynamicPseudoType},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			vars := args[1]
+			if !vars.Type().IsObjectType() && !vars.Type().IsMapType() {
+				return cty.NilVal, fmt.Errorf("vars must be a map or object")
+			}
+
+			path := resolvePath(basedir, args[0].AsString())
+			src, err := ioutil.ReadFile(path)
+			if err != nil {
+				return cty.NilVal, err
+			}
+
+			expr, diags := hclsyntax.ParseTemplate(src, path, hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{},
+				Functions: Functions(basedir, false),
+			}
+			if vars.LengthInt() != 0 {
+				for name, val := range vars.AsValueMap() {
+					ctx.Variables[name] = val
+				}
+			}
+
+			val, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+			return convert.Convert(val, cty.String)
+		},
+	})
+}
+
+// yamlDecodeFunc parses a YAML document into a value.
+var yamlDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "src", Type: cty.String},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		return cty.DynamicPseudoType, nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		var decoded interface{}
+		if err := yaml.Unmarshal([]byte(args[0].AsString()), &decoded); err != nil {
+			return cty.NilVal, err
+		}
+		return yamlToCty(decoded)
+	},
+})
+
+// yamlToCty converts the interface values produced by the YAML decoder
+// into cty values.
+func yamlToCty(v interface{}) (cty.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case bool:
+		return cty.BoolVal(v), nil
+	case string:
+		return cty.StringVal(v), nil
+	case int:
+		return cty.NumberIntVal(int64(v)), nil
+	case int64:
+		return cty.NumberIntVal(v), nil
+	case uint64:
+		return cty.NumberUIntVal(v), nil
+	case float64:
+		return cty.NumberFloatVal(v), nil
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		vals := make([]cty.Value, len(v))
+		for i, elem := range v {
+			val, err := yamlToCty(elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[i] = val
+		}
+		return cty.TupleVal(vals), nil
+	case map[interface{}]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		vals := make(map[string]cty.Value, len(v))
+		for key, elem := range v {
+			val, err := yamlToCty(elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[fmt.Sprintf("%v", key)] = val
+		}
+		return cty.ObjectVal(vals), nil
+	default:
+		return cty.NilVal, fmt.Errorf("cannot decode YAML value of type %T", v)
+	}
+}
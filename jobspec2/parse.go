@@ -0,0 +1,369 @@
+package jobspec2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// ParseConfig is the set of inputs to ParseWithConfig.
+type ParseConfig struct {
+	// Path is the file path of the job specification, used for error
+	// messages and to resolve relative paths in file functions.
+	Path string
+
+	// Body is the raw job specification.
+	Body []byte
+
+	// ArgVars are variable assignments from -var command line flags, each
+	// of the form "name=value". They take precedence over VarFiles and
+	// Envs.
+	ArgVars []string
+
+	// VarFiles are paths to files of variable assignments, applied in
+	// order after environment variables.
+	VarFiles []string
+
+	// Envs is the process environment; entries prefixed with NOMAD_VAR_
+	// assign variables, and all entries are exposed to expressions through
+	// the env object.
+	Envs []string
+
+	// AllowFS enables functions that read the local filesystem, such as
+	// file and templatefile.
+	AllowFS bool
+}
+
+// Parse parses the job spec from the given io.Reader, evaluating any HCL2
+// variable blocks, expressions, and function calls before decoding the
+// result. Specs that fail to evaluate as HCL2 are retried with the HCL1
+// parser so existing job files keep working.
+func Parse(path string, r io.Reader) (*api.Job, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseWithConfig(&ParseConfig{
+		Path:    path,
+		Body:    buf,
+		Envs:    os.Environ(),
+		AllowFS: true,
+	})
+}
+
+// ParseWithConfig parses the job spec in c.Body with full control over
+// variable assignments and filesystem access. See Parse.
+func ParseWithConfig(c *ParseConfig) (*api.Job, error) {
+	// JSON specs have no variables or expressions to evaluate
+	if strings.HasSuffix(c.Path, ".json") {
+		return jobspec.Parse(bytes.NewReader(c.Body))
+	}
+
+	job, err := parseHCL2(c)
+	if err == nil {
+		return job, nil
+	}
+
+	// Fall back to the HCL1 parser for specs that predate HCL2. If both
+	// parsers fail, the HCL2 diagnostics are the more useful error.
+	if job, err1 := jobspec.Parse(bytes.NewReader(c.Body)); err1 == nil {
+		return job, nil
+	}
+
+	return nil, err
+}
+
+func parseHCL2(c *ParseConfig) (*api.Job, error) {
+	file, diags := hclsyntax.ParseConfig(c.Body, c.Path, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body := file.Body.(*hclsyntax.Body)
+
+	funcs := Functions(filepath.Dir(c.Path), c.AllowFS)
+
+	vars, err := parseVariables(body, c, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(vars),
+			"env": envValues(c.Envs),
+		},
+		Functions: funcs,
+	}
+
+	// Evaluate the spec and render it back out as HCL1 so the existing
+	// parser decodes the final structure
+	var buf bytes.Buffer
+	for _, block := range body.Blocks {
+		if block.Type == "variable" {
+			continue
+		}
+		if diags := appendBlock(&buf, block, ctx, c.Body, ""); diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	return jobspec.Parse(&buf)
+}
+
+// variableBlock is a declared variable and its resolved value.
+type variableBlock struct {
+	name  string
+	typ   cty.Type  // cty.NilType when the block has no type attribute
+	value cty.Value // cty.NilVal until assigned or defaulted
+}
+
+// parseVariables collects the variable blocks from the spec body and
+// resolves their values from defaults, NOMAD_VAR_ environment variables,
+// var files, and -var arguments, in increasing order of precedence.
+func parseVariables(body *hclsyntax.Body, c *ParseConfig, funcs map[string]function.Function) (map[string]cty.Value, error) {
+	defCtx := &hcl.EvalContext{Functions: funcs}
+
+	variables := map[string]*variableBlock{}
+	defaults := map[string]cty.Value{}
+	for _, block := range body.Blocks {
+		if block.Type != "variable" {
+			continue
+		}
+		if len(block.Labels) != 1 {
+			return nil, fmt.Errorf("%s: variable block must have a single name label", block.TypeRange)
+		}
+
+		v := &variableBlock{name: block.Labels[0], value: cty.NilVal}
+		for name, attr := range block.Body.Attributes {
+			switch name {
+			case "description":
+				// Informational only
+			case "type":
+				switch hcl.ExprAsKeyword(attr.Expr) {
+				case "string":
+					v.typ = cty.String
+				case "number":
+					v.typ = cty.Number
+				case "bool":
+					v.typ = cty.Bool
+				default:
+					// Collection types are accepted as declared
+				}
+			case "default":
+				def, diags := attr.Expr.Value(defCtx)
+				if diags.HasErrors() {
+					return nil, diags
+				}
+				defaults[v.name] = def
+			default:
+				return nil, fmt.Errorf("%s: invalid attribute %q in variable %q", attr.SrcRange, name, v.name)
+			}
+		}
+		variables[v.name] = v
+	}
+
+	// Environment variables; unknown names are ignored since the
+	// environment is shared with other tools
+	for _, env := range c.Envs {
+		if !strings.HasPrefix(env, "NOMAD_VAR_") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(env, "NOMAD_VAR_"), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if v, ok := variables[parts[0]]; ok {
+			if err := v.setString(parts[1], defaults); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Var files
+	for _, vf := range c.VarFiles {
+		src, err := ioutil.ReadFile(vf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read var file: %v", err)
+		}
+		vfile, diags := hclsyntax.ParseConfig(src, vf, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		for name, attr := range vfile.Body.(*hclsyntax.Body).Attributes {
+			v, ok := variables[name]
+			if !ok {
+				return nil, fmt.Errorf("variable %q was set in var file %q but was not declared", name, vf)
+			}
+			val, diags := attr.Expr.Value(defCtx)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if err := v.set(val, defaults); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Command line arguments
+	for _, arg := range c.ArgVars {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid -var option %q, expected name=value", arg)
+		}
+		v, ok := variables[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("variable %q was set with -var but was not declared", parts[0])
+		}
+		if err := v.setString(parts[1], defaults); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply defaults and collect any still unset variables
+	var missing []string
+	values := map[string]cty.Value{}
+	for name, v := range variables {
+		if v.value == cty.NilVal {
+			if def, ok := defaults[name]; ok {
+				v.value = def
+			} else {
+				missing = append(missing, name)
+				continue
+			}
+		}
+		values[name] = v.value
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing value for required variable(s): %s", strings.Join(missing, ", "))
+	}
+
+	return values, nil
+}
+
+// set assigns the value to the variable, converting it to the declared or
+// defaulted type.
+func (v *variableBlock) set(val cty.Value, defaults map[string]cty.Value) error {
+	typ := v.typ
+	if typ == cty.NilType {
+		if def, ok := defaults[v.name]; ok {
+			typ = def.Type()
+		}
+	}
+	if typ != cty.NilType {
+		converted, err := convert.Convert(val, typ)
+		if err != nil {
+			return fmt.Errorf("invalid value for variable %q: %v", v.name, err)
+		}
+		val = converted
+	}
+	v.value = val
+	return nil
+}
+
+func (v *variableBlock) setString(raw string, defaults map[string]cty.Value) error {
+	return v.set(cty.StringVal(raw), defaults)
+}
+
+// envValues exposes the process environment to expressions as the env
+// object.
+func envValues(envs []string) cty.Value {
+	vals := map[string]cty.Value{}
+	for _, env := range envs {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 {
+			vals[parts[0]] = cty.StringVal(parts[1])
+		}
+	}
+	if len(vals) == 0 {
+		return cty.EmptyObjectVal
+	}
+	return cty.ObjectVal(vals)
+}
+
+// appendBlock renders the block and its evaluated contents as HCL1.
+func appendBlock(buf *bytes.Buffer, block *hclsyntax.Block, ctx *hcl.EvalContext, src []byte, indent string) hcl.Diagnostics {
+	buf.WriteString(indent)
+	buf.WriteString(block.Type)
+	for _, label := range block.Labels {
+		fmt.Fprintf(buf, " %q", label)
+	}
+	buf.WriteString(" {\n")
+	if diags := appendBody(buf, block.Body, ctx, src, indent+"  "); diags.HasErrors() {
+		return diags
+	}
+	buf.WriteString(indent)
+	buf.WriteString("}\n")
+	return nil
+}
+
+// appendBody renders the body's attributes and nested blocks as HCL1 in
+// their original source order. Attribute expressions are evaluated;
+// expressions that reference variables outside the spec's scope, such as
+// Nomad's runtime node attributes, are passed through verbatim so they are
+// interpolated by the servers and clients as before.
+func appendBody(buf *bytes.Buffer, body *hclsyntax.Body, ctx *hcl.EvalContext, src []byte, indent string) hcl.Diagnostics {
+	type item struct {
+		start int
+		attr  *hclsyntax.Attribute
+		block *hclsyntax.Block
+	}
+
+	items := make([]item, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, item{start: attr.SrcRange.Start.Byte, attr: attr})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, item{start: block.TypeRange.Start.Byte, block: block})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].start < items[j].start })
+
+	for _, item := range items {
+		if item.block != nil {
+			if diags := appendBlock(buf, item.block, ctx, src, indent); diags.HasErrors() {
+				return diags
+			}
+			continue
+		}
+
+		attr := item.attr
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			if !onlyUnknownVariables(diags) {
+				return diags
+			}
+			rng := attr.Expr.Range()
+			fmt.Fprintf(buf, "%s%s = %s\n", indent, attr.Name, src[rng.Start.Byte:rng.End.Byte])
+			continue
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", indent, attr.Name,
+			bytes.TrimSpace(hclwrite.TokensForValue(val).Bytes()))
+	}
+	return nil
+}
+
+// onlyUnknownVariables returns true when every error in the diagnostics is
+// an unknown variable reference, the signature of a runtime interpolation.
+func onlyUnknownVariables(diags hcl.Diagnostics) bool {
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError && diag.Summary != "Unknown variable" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package jobspec2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Variables(t *testing.T) {
+	hcl := `
+variable "datacenter" {
+  type        = string
+  default     = "dc1"
+  description = "the datacenter to run in"
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+job "example" {
+  datacenters = [var.datacenter]
+
+  group "cache" {
+    count = var.count
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = "redis:3.2"
+      }
+
+      env {
+        TASK_DIR = "${NOMAD_TASK_DIR}"
+        DC       = upper(var.datacenter)
+      }
+    }
+  }
+}
+`
+
+	// Defaults
+	job, err := ParseWithConfig(&ParseConfig{
+		Path: "example.nomad",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "example", *job.Name)
+	require.Equal(t, []string{"dc1"}, job.Datacenters)
+	require.Equal(t, 1, *job.TaskGroups[0].Count)
+
+	task := job.TaskGroups[0].Tasks[0]
+	require.Equal(t, "DC1", task.Env["DC"])
+
+	// Runtime interpolations are passed through untouched
+	require.Equal(t, "${NOMAD_TASK_DIR}", task.Env["TASK_DIR"])
+
+	// Environment variables override defaults
+	job, err = ParseWithConfig(&ParseConfig{
+		Path: "example.nomad",
+		Body: []byte(hcl),
+		Envs: []string{"NOMAD_VAR_datacenter=dc2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dc2"}, job.Datacenters)
+
+	// -var arguments override environment variables
+	job, err = ParseWithConfig(&ParseConfig{
+		Path:    "example.nomad",
+		Body:    []byte(hcl),
+		ArgVars: []string{"datacenter=dc3", "count=3"},
+		Envs:    []string{"NOMAD_VAR_datacenter=dc2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dc3"}, job.Datacenters)
+	require.Equal(t, 3, *job.TaskGroups[0].Count)
+
+	// Undeclared -var arguments are an error
+	_, err = ParseWithConfig(&ParseConfig{
+		Path:    "example.nomad",
+		Body:    []byte(hcl),
+		ArgVars: []string{"unknown=oops"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "was not declared")
+}
+
+func TestParse_VarFiles(t *testing.T) {
+	hcl := `
+variable "datacenter" {
+  type = string
+}
+
+job "example" {
+  datacenters = [var.datacenter]
+}
+`
+
+	// The variable has no default so it is required
+	_, err := ParseWithConfig(&ParseConfig{
+		Path: "example.nomad",
+		Body: []byte(hcl),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing value for required variable")
+
+	dir, err := ioutil.TempDir("", "jobspec2")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	varFile := filepath.Join(dir, "example.var")
+	require.NoError(t, ioutil.WriteFile(varFile, []byte(`datacenter = "dc4"`), 0644))
+
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:     "example.nomad",
+		Body:     []byte(hcl),
+		VarFiles: []string{varFile},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dc4"}, job.Datacenters)
+}
+
+func TestParse_Functions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobspec2")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	motd := filepath.Join(dir, "motd.tpl")
+	require.NoError(t, ioutil.WriteFile(motd, []byte("hello, ${name}"), 0644))
+
+	hcl := `
+job "example" {
+  meta {
+    greeting = templatefile("motd.tpl", { name = "world" })
+    yaml     = join(",", yamldecode("[a, b]"))
+    shouting = upper("quiet")
+  }
+}
+`
+
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    filepath.Join(dir, "example.nomad"),
+		Body:    []byte(hcl),
+		AllowFS: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hello, world", job.Meta["greeting"])
+	require.Equal(t, "a,b", job.Meta["yaml"])
+	require.Equal(t, "QUIET", job.Meta["shouting"])
+
+	// Filesystem functions are unavailable when AllowFS is false
+	_, err = ParseWithConfig(&ParseConfig{
+		Path: filepath.Join(dir, "example.nomad"),
+		Body: []byte(hcl),
+	})
+	require.Error(t, err)
+}
+
+func TestParse_HCL1Fallback(t *testing.T) {
+	// Quoted attribute names are HCL1-only syntax
+	hcl := `
+job "example" {
+  meta {
+    "nested.key" = "value"
+  }
+}
+`
+
+	job, err := ParseWithConfig(&ParseConfig{
+		Path: "example.nomad",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "value", job.Meta["nested.key"])
+}
@@ -1,6 +1,8 @@
 package nomad
 
 import (
+	"crypto/x509"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -25,6 +27,15 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return acl.ManagementACL, nil
 	}
 
+	// Check if the secret is a TLS certificate identity, which is authorized
+	// by the server's configured identity to policy mapping rather than a
+	// stored token. Tokens bearing the reserved prefix are rejected from
+	// callers at the HTTP and RPC layers, so the identity here can only have
+	// been derived from verified connection state.
+	if strings.HasPrefix(secretID, structs.ACLTLSIdentityPrefix) {
+		return s.resolveTLSIdentity(strings.TrimPrefix(secretID, structs.ACLTLSIdentityPrefix))
+	}
+
 	// Snapshot the state
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
@@ -35,6 +46,63 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// tlsCertificateIdentity returns the first identity presented by a verified
+// TLS client certificate that has an ACL policy mapping configured, or the
+// empty string if there is no match. The certificate common name, DNS SANs,
+// and organizational units are checked in that order.
+func tlsCertificateIdentity(cert *x509.Certificate, mapping map[string][]string) string {
+	if cert == nil || len(mapping) == 0 {
+		return ""
+	}
+
+	candidates := make([]string, 0, 1+len(cert.DNSNames)+len(cert.Subject.OrganizationalUnit))
+	if cert.Subject.CommonName != "" {
+		candidates = append(candidates, cert.Subject.CommonName)
+	}
+	candidates = append(candidates, cert.DNSNames...)
+	candidates = append(candidates, cert.Subject.OrganizationalUnit...)
+
+	for _, identity := range candidates {
+		if _, ok := mapping[identity]; ok {
+			return identity
+		}
+	}
+	return ""
+}
+
+// resolveTLSIdentity is used to resolve a TLS certificate identity into an
+// ACL object using the server's configured identity to policy mapping.
+// Identities without a mapping are treated the same as unknown tokens.
+func (s *Server) resolveTLSIdentity(identity string) (*acl.ACL, error) {
+	policyNames, ok := s.config.TLSIdentityPolicies[identity]
+	if !ok {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	// Snapshot the state
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all associated policies
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
+		policy, err := snap.ACLPolicyByName(nil, policyName)
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			// Ignore policies that don't exist, since they don't grant any more privilege
+			continue
+		}
+		policies = append(policies, policy)
+	}
+
+	// Compile and cache the ACL object
+	return structs.CompileACLObject(s.aclCache, policies)
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -56,14 +124,39 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		}
 	}
 
+	// Check if the token has expired
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
+
 	// Check if this is a management token
 	if token.Type == structs.ACLManagementToken {
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
+	// Expand the token's roles into the full set of policy names. Roles that
+	// don't exist are ignored, as are duplicate policies across roles.
+	policyNames := make(map[string]struct{}, len(token.Policies))
 	for _, policyName := range token.Policies {
+		policyNames[policyName] = struct{}{}
+	}
+	for _, roleName := range token.Roles {
+		role, err := snap.ACLRoleByName(nil, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			// Ignore roles that don't exist, since they don't grant any more privilege
+			continue
+		}
+		for _, policyName := range role.Policies {
+			policyNames[policyName] = struct{}{}
+		}
+	}
+
+	// Get all associated policies
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -96,6 +189,23 @@ func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error)
 	}
 	defer metrics.MeasureSince([]string{"nomad", "acl", "resolveSecretToken"}, time.Now())
 
+	// Synthesize a client token for TLS certificate identities, using the
+	// policies from the server's configured identity mapping.
+	if strings.HasPrefix(secretID, structs.ACLTLSIdentityPrefix) {
+		identity := strings.TrimPrefix(secretID, structs.ACLTLSIdentityPrefix)
+		policies, ok := s.config.TLSIdentityPolicies[identity]
+		if !ok {
+			return nil, structs.ErrTokenNotFound
+		}
+		return &structs.ACLToken{
+			AccessorID: secretID,
+			Name:       "TLS Identity " + identity,
+			Type:       structs.ACLClientToken,
+			Policies:   policies,
+			Global:     false,
+		}, nil
+	}
+
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
 		return nil, err
@@ -116,5 +226,10 @@ func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error)
 		}
 	}
 
+	// Check if the token has expired
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
+
 	return token, nil
 }
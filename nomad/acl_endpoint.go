@@ -9,6 +9,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	bexpr "github.com/hashicorp/go-bexpr"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	policy "github.com/hashicorp/nomad/acl"
@@ -32,20 +33,848 @@ const (
 type ACL struct {
 	srv    *Server
 	logger log.Logger
+
+	// oidcCache caches OIDC provider discovery documents and keys between
+	// logins. The zero value is ready for use.
+	oidcCache oidcProviderCache
+}
+
+// UpsertPolicies is used to create or update a set of policies
+func (a *ACL) UpsertPolicies(args *structs.ACLPolicyUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of policies
+	if len(args.Policies) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	}
+
+	// Validate each policy, compute hash
+	for idx, policy := range args.Policies {
+		if err := policy.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(404, "policy %d invalid: %v", idx, err)
+		}
+		policy.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLPolicyUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeletePolicies is used to delete policies
+func (a *ACL) DeletePolicies(args *structs.ACLPolicyDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeletePolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of policies
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLPolicyDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListPolicies is used to list the policies
+func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.ACLPolicyListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.ListPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_policies"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the policies that may be listed
+	mgt := acl.IsManagement()
+	var policies map[string]struct{}
+	if !mgt {
+		token, err := a.requestACLToken(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		policies = make(map[string]struct{}, len(token.Policies))
+		for _, p := range token.Policies {
+			policies[p] = struct{}{}
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the policies
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLPolicyByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLPolicies(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the policies to a list stub
+			reply.Policies = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				policy := raw.(*structs.ACLPolicy)
+				if _, ok := policies[policy.Name]; ok || mgt {
+					reply.Policies = append(reply.Policies, policy.Stub())
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("acl_policy")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetPolicy is used to get a specific policy
+func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.SingleACLPolicyResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.GetPolicy", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policy"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If the policy is the anonymous one, anyone can get it
+	// If it is not a management token determine if it can get this policy
+	mgt := acl.IsManagement()
+	if !mgt && args.Name != "anonymous" {
+		token, err := a.requestACLToken(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		found := false
+		for _, p := range token.Policies {
+			if p == args.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the policy
+			out, err := state.ACLPolicyByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Policy = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+				rules, err := policy.Parse(out.Rules)
+
+				if err != nil {
+					return err
+				}
+				reply.Policy.RulesJSON = rules
+			} else {
+				// Use the last index that affected the policy table
+				index, err := state.Index("acl_policy")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+func (a *ACL) requestACLToken(secretID string) (*structs.ACLToken, error) {
+	if secretID == "" {
+		return structs.AnonymousACLToken, nil
+	}
+
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return snap.ACLTokenBySecretID(nil, secretID)
+}
+
+// GetPolicies is used to get a set of policies
+func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicySetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policies"}, time.Now())
+
+	// For client typed tokens, allow them to query any policies associated with that token.
+	// This is used by clients which are resolving the policies to enforce. Any associated
+	// policies need to be fetched so that the client can determine what to allow.
+	token, err := a.requestACLToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Policies = make(map[string]*structs.ACLPolicy, len(args.Names))
+
+			// Look for the policy
+			for _, policyName := range args.Names {
+				out, err := state.ACLPolicyByName(ws, policyName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Policies[policyName] = out
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("acl_policy")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertRoles is used to create or update a set of roles
+func (a *ACL) UpsertRoles(args *structs.ACLRoleUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Roles) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one role")
+	}
+
+	// Validate each role, compute hash
+	for idx, role := range args.Roles {
+		if err := role.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(400, "role %d invalid: %v", idx, err)
+		}
+		role.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRoles is used to delete roles
+func (a *ACL) DeleteRoles(args *structs.ACLRoleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one role")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list the roles
+func (a *ACL) ListRoles(args *structs.ACLRoleListRequest, reply *structs.ACLRoleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.ListRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the roles that may be listed
+	mgt := acl.IsManagement()
+	var roles map[string]struct{}
+	if !mgt {
+		token, err := a.requestACLToken(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		roles = make(map[string]struct{}, len(token.Roles))
+		for _, r := range token.Roles {
+			roles[r] = struct{}{}
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the roles
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLRoleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the roles to a list stub
+			reply.Roles = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				role := raw.(*structs.ACLRole)
+				if _, ok := roles[role.Name]; ok || mgt {
+					reply.Roles = append(reply.Roles, role.Stub())
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRole is used to get a specific role
+func (a *ACL) GetRole(args *structs.ACLRoleSpecificRequest, reply *structs.SingleACLRoleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.GetRole", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine if the role is associated
+	// with the token
+	if !acl.IsManagement() {
+		token, err := a.requestACLToken(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		found := false
+		for _, r := range token.Roles {
+			if r == args.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the role
+			out, err := state.ACLRoleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Role = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the role table
+				index, err := state.Index("acl_role")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoles is used to get a set of roles. It is used by the replication
+// process on non-authoritative regions.
+func (a *ACL) GetRoles(args *structs.ACLRoleSetRequest, reply *structs.ACLRoleSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Roles = make(map[string]*structs.ACLRole, len(args.Names))
+
+			// Look for the role
+			for _, roleName := range args.Names {
+				out, err := state.ACLRoleByName(ws, roleName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Roles[roleName] = out
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertAuthMethods is used to create or update a set of auth methods
+func (a *ACL) UpsertAuthMethods(args *structs.ACLAuthMethodUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one auth method")
+	}
+
+	// Validate each auth method, compute hash
+	for idx, method := range args.AuthMethods {
+		if err := method.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(400, "auth method %d invalid: %v", idx, err)
+		}
+		method.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to delete auth methods
+func (a *ACL) DeleteAuthMethods(args *structs.ACLAuthMethodDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one auth method")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods
+func (a *ACL) ListAuthMethods(args *structs.ACLAuthMethodListRequest, reply *structs.ACLAuthMethodListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.ListAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the auth methods
+			iter, err := state.ACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the auth methods to a list stub
+			reply.AuthMethods = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				method := raw.(*structs.ACLAuthMethod)
+				reply.AuthMethods = append(reply.AuthMethods, method.Stub())
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to get a specific auth method
+func (a *ACL) GetAuthMethod(args *structs.ACLAuthMethodSpecificRequest, reply *structs.SingleACLAuthMethodResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward("ACL.GetAuthMethod", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the auth method
+			out, err := state.ACLAuthMethodByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.AuthMethod = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the auth method table
+				index, err := state.Index("acl_auth_method")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethods is used to get a set of auth methods. It is used by the
+// replication process on non-authoritative regions.
+func (a *ACL) GetAuthMethods(args *structs.ACLAuthMethodSetRequest, reply *structs.ACLAuthMethodSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+
+			// Look for the auth method
+			for _, methodName := range args.Names {
+				out, err := state.ACLAuthMethodByName(ws, methodName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[methodName] = out
+				}
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
 }
 
-// UpsertPolicies is used to create or update a set of policies
-func (a *ACL) UpsertPolicies(args *structs.ACLPolicyUpsertRequest, reply *structs.GenericResponse) error {
+// UpsertBindingRules is used to create or update a set of binding rules
+func (a *ACL) UpsertBindingRules(args *structs.ACLBindingRuleUpsertRequest, reply *structs.GenericResponse) error {
 	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
 	args.Region = a.srv.config.AuthoritativeRegion
 
-	if done, err := a.srv.forward("ACL.UpsertPolicies", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.UpsertBindingRules", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_policies"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
 
 	// Check management level permissions
 	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
@@ -54,21 +883,49 @@ func (a *ACL) UpsertPolicies(args *structs.ACLPolicyUpsertRequest, reply *struct
 		return structs.ErrPermissionDenied
 	}
 
-	// Validate non-zero set of policies
-	if len(args.Policies) == 0 {
-		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	// Validate non-zero set of binding rules
+	if len(args.BindingRules) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one binding rule")
 	}
 
-	// Validate each policy, compute hash
-	for idx, policy := range args.Policies {
-		if err := policy.Validate(); err != nil {
-			return structs.NewErrRPCCodedf(404, "policy %d invalid: %v", idx, err)
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule, compute hash
+	for idx, rule := range args.BindingRules {
+		// Generate an ID if new
+		if rule.ID == "" {
+			rule.ID = uuid.Generate()
 		}
-		policy.SetHash()
+
+		if err := rule.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(400, "binding rule %d invalid: %v", idx, err)
+		}
+
+		// Validate the selector parses
+		if rule.Selector != "" {
+			if _, err := bexpr.CreateEvaluator(rule.Selector); err != nil {
+				return structs.NewErrRPCCodedf(400, "binding rule %d has invalid selector: %v", idx, err)
+			}
+		}
+
+		// Verify the referenced auth method exists
+		method, err := state.ACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return err
+		}
+		if method == nil {
+			return structs.NewErrRPCCodedf(400, "binding rule %d references unknown auth method %s", idx, rule.AuthMethod)
+		}
+
+		rule.SetHash()
 	}
 
 	// Update via Raft
-	_, index, err := a.srv.raftApply(structs.ACLPolicyUpsertRequestType, args)
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
 	if err != nil {
 		return err
 	}
@@ -78,18 +935,18 @@ func (a *ACL) UpsertPolicies(args *structs.ACLPolicyUpsertRequest, reply *struct
 	return nil
 }
 
-// DeletePolicies is used to delete policies
-func (a *ACL) DeletePolicies(args *structs.ACLPolicyDeleteRequest, reply *structs.GenericResponse) error {
+// DeleteBindingRules is used to delete binding rules
+func (a *ACL) DeleteBindingRules(args *structs.ACLBindingRuleDeleteRequest, reply *structs.GenericResponse) error {
 	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
 	args.Region = a.srv.config.AuthoritativeRegion
 
-	if done, err := a.srv.forward("ACL.DeletePolicies", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.DeleteBindingRules", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_policies"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
 
 	// Check management level permissions
 	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
@@ -98,13 +955,13 @@ func (a *ACL) DeletePolicies(args *structs.ACLPolicyDeleteRequest, reply *struct
 		return structs.ErrPermissionDenied
 	}
 
-	// Validate non-zero set of policies
-	if len(args.Names) == 0 {
-		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	// Validate non-zero set of binding rules
+	if len(args.IDs) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one binding rule")
 	}
 
 	// Update via Raft
-	_, index, err := a.srv.raftApply(structs.ACLPolicyDeleteRequestType, args)
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
 	if err != nil {
 		return err
 	}
@@ -114,75 +971,47 @@ func (a *ACL) DeletePolicies(args *structs.ACLPolicyDeleteRequest, reply *struct
 	return nil
 }
 
-// ListPolicies is used to list the policies
-func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.ACLPolicyListResponse) error {
+// ListBindingRules is used to list the binding rules
+func (a *ACL) ListBindingRules(args *structs.ACLBindingRuleListRequest, reply *structs.ACLBindingRuleListResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
 
-	if done, err := a.srv.forward("ACL.ListPolicies", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.ListBindingRules", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "list_policies"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
 
 	// Check management level permissions
-	acl, err := a.srv.ResolveToken(args.AuthToken)
-	if err != nil {
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if acl == nil {
+	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
 
-	// If it is not a management token determine the policies that may be listed
-	mgt := acl.IsManagement()
-	var policies map[string]struct{}
-	if !mgt {
-		token, err := a.requestACLToken(args.AuthToken)
-		if err != nil {
-			return err
-		}
-		if token == nil {
-			return structs.ErrTokenNotFound
-		}
-
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
-			policies[p] = struct{}{}
-		}
-	}
-
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Iterate over all the policies
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.ACLPolicyByNamePrefix(ws, prefix)
-			} else {
-				iter, err = state.ACLPolicies(ws)
-			}
+			// Iterate over all the binding rules
+			iter, err := state.ACLBindingRules(ws)
 			if err != nil {
 				return err
 			}
 
-			// Convert all the policies to a list stub
-			reply.Policies = nil
+			reply.BindingRules = nil
 			for {
 				raw := iter.Next()
 				if raw == nil {
 					break
 				}
-				policy := raw.(*structs.ACLPolicy)
-				if _, ok := policies[policy.Name]; ok || mgt {
-					reply.Policies = append(reply.Policies, policy.Stub())
-				}
+				rule := raw.(*structs.ACLBindingRule)
+				reply.BindingRules = append(reply.BindingRules, rule)
 			}
 
-			// Use the last index that affected the policy table
-			index, err := state.Index("acl_policy")
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
 			if err != nil {
 				return err
 			}
@@ -198,74 +1027,42 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 	return a.srv.blockingRPC(&opts)
 }
 
-// GetPolicy is used to get a specific policy
-func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.SingleACLPolicyResponse) error {
+// GetBindingRule is used to get a specific binding rule
+func (a *ACL) GetBindingRule(args *structs.ACLBindingRuleSpecificRequest, reply *structs.SingleACLBindingRuleResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
 
-	if done, err := a.srv.forward("ACL.GetPolicy", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.GetBindingRule", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policy"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
 
 	// Check management level permissions
-	acl, err := a.srv.ResolveToken(args.AuthToken)
-	if err != nil {
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if acl == nil {
+	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
 
-	// If the policy is the anonymous one, anyone can get it
-	// If it is not a management token determine if it can get this policy
-	mgt := acl.IsManagement()
-	if !mgt && args.Name != "anonymous" {
-		token, err := a.requestACLToken(args.AuthToken)
-		if err != nil {
-			return err
-		}
-		if token == nil {
-			return structs.ErrTokenNotFound
-		}
-
-		found := false
-		for _, p := range token.Policies {
-			if p == args.Name {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return structs.ErrPermissionDenied
-		}
-	}
-
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Look for the policy
-			out, err := state.ACLPolicyByName(ws, args.Name)
+			// Look for the binding rule
+			out, err := state.ACLBindingRuleByID(ws, args.ID)
 			if err != nil {
 				return err
 			}
 
 			// Setup the output
-			reply.Policy = out
+			reply.BindingRule = out
 			if out != nil {
 				reply.Index = out.ModifyIndex
-				rules, err := policy.Parse(out.Rules)
-
-				if err != nil {
-					return err
-				}
-				reply.Policy.RulesJSON = rules
 			} else {
-				// Use the last index that affected the policy table
-				index, err := state.Index("acl_policy")
+				// Use the last index that affected the binding rule table
+				index, err := state.Index("acl_binding_rule")
 				if err != nil {
 					return err
 				}
@@ -276,41 +1073,21 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 	return a.srv.blockingRPC(&opts)
 }
 
-func (a *ACL) requestACLToken(secretID string) (*structs.ACLToken, error) {
-	if secretID == "" {
-		return structs.AnonymousACLToken, nil
-	}
-
-	snap, err := a.srv.fsm.State().Snapshot()
-	if err != nil {
-		return nil, err
-	}
-
-	return snap.ACLTokenBySecretID(nil, secretID)
-}
-
-// GetPolicies is used to get a set of policies
-func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicySetResponse) error {
+// GetBindingRules is used to get a set of binding rules. It is used by the
+// replication process on non-authoritative regions.
+func (a *ACL) GetBindingRules(args *structs.ACLBindingRuleSetRequest, reply *structs.ACLBindingRuleSetResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
 	}
-	if done, err := a.srv.forward("ACL.GetPolicies", args, args, reply); done {
+	if done, err := a.srv.forward("ACL.GetBindingRules", args, args, reply); done {
 		return err
 	}
-	defer metrics.MeasureSince([]string{"nomad", "acl", "get_policies"}, time.Now())
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
 
-	// For client typed tokens, allow them to query any policies associated with that token.
-	// This is used by clients which are resolving the policies to enforce. Any associated
-	// policies need to be fetched so that the client can determine what to allow.
-	token, err := a.requestACLToken(args.AuthToken)
-	if err != nil {
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	}
-
-	if token == nil {
-		return structs.ErrTokenNotFound
-	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
+	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -320,21 +1097,21 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 		queryMeta: &reply.QueryMeta,
 		run: func(ws memdb.WatchSet, state *state.StateStore) error {
 			// Setup the output
-			reply.Policies = make(map[string]*structs.ACLPolicy, len(args.Names))
+			reply.BindingRules = make(map[string]*structs.ACLBindingRule, len(args.IDs))
 
-			// Look for the policy
-			for _, policyName := range args.Names {
-				out, err := state.ACLPolicyByName(ws, policyName)
+			// Look for the binding rule
+			for _, id := range args.IDs {
+				out, err := state.ACLBindingRuleByID(ws, id)
 				if err != nil {
 					return err
 				}
 				if out != nil {
-					reply.Policies[policyName] = out
+					reply.BindingRules[id] = out
 				}
 			}
 
-			// Use the last index that affected the policy table
-			index, err := state.Index("acl_policy")
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
 			if err != nil {
 				return err
 			}
@@ -509,6 +1286,12 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
 
+			// Derive the expiration time from the TTL if one was given
+			if token.ExpirationTTL != 0 {
+				expiration := token.CreateTime.Add(token.ExpirationTTL)
+				token.ExpirationTime = &expiration
+			}
+
 		} else {
 			// Verify the token exists
 			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
@@ -523,6 +1306,12 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			if token.Global != out.Global {
 				return structs.NewErrRPCCodedf(400, "cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// Expiration is fixed at creation and cannot be updated
+			if token.ExpirationTTL != out.ExpirationTTL {
+				return structs.NewErrRPCCodedf(400, "cannot update expiration of %s", token.AccessorID)
+			}
+			token.ExpirationTime = out.ExpirationTime
 		}
 
 		// Compute the token hash
@@ -839,3 +1628,107 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 	}
 	return nil
 }
+
+// Check is used to test whether a token is allowed to perform an operation
+// within a namespace and, if so, report which policy granted it. This lets
+// operators debug permission issues without attempting the operation itself.
+func (a *ACL) Check(args *structs.ACLCheckRequest, reply *structs.ACLCheckResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.Check", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "check"}, time.Now())
+
+	// Validate the request
+	if args.Capability == "" {
+		return fmt.Errorf("missing capability to check")
+	}
+	if args.Namespace == "" {
+		args.Namespace = structs.DefaultNamespace
+	}
+
+	// Setup the query meta
+	a.srv.setQueryMeta(&reply.QueryMeta)
+
+	// Look for the token being checked
+	token, err := a.requestACLToken(args.SecretID)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	// Snapshot the state
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Use the last index that affected the policy table
+	index, err := snap.Index("acl_policy")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+
+	// Management tokens are allowed to perform any operation
+	if token.Type == structs.ACLManagementToken {
+		reply.Allow = true
+		return nil
+	}
+
+	// Expand the token's roles into the full set of policy names, preserving
+	// order so the first policy that grants the capability is reported.
+	policyNames := make([]string, len(token.Policies))
+	copy(policyNames, token.Policies)
+	seen := make(map[string]struct{}, len(policyNames))
+	for _, policyName := range policyNames {
+		seen[policyName] = struct{}{}
+	}
+	for _, roleName := range token.Roles {
+		role, err := snap.ACLRoleByName(nil, roleName)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			// Ignore roles that don't exist, since they don't grant any more privilege
+			continue
+		}
+		for _, policyName := range role.Policies {
+			if _, ok := seen[policyName]; ok {
+				continue
+			}
+			seen[policyName] = struct{}{}
+			policyNames = append(policyNames, policyName)
+		}
+	}
+
+	// Check each policy individually so the granting policy can be reported
+	for _, policyName := range policyNames {
+		out, err := snap.ACLPolicyByName(nil, policyName)
+		if err != nil {
+			return err
+		}
+		if out == nil {
+			// Ignore policies that don't exist, since they don't grant any more privilege
+			continue
+		}
+		parsed, err := policy.Parse(out.Rules)
+		if err != nil {
+			return err
+		}
+		aclObj, err := policy.NewACL(false, []*policy.Policy{parsed})
+		if err != nil {
+			return err
+		}
+		if aclObj.AllowNamespaceOperation(args.Namespace, args.Capability) {
+			reply.Allow = true
+			reply.Policy = out.Name
+			return nil
+		}
+	}
+	return nil
+}
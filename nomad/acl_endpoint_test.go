@@ -1,8 +1,14 @@
 package nomad
 
 import (
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,12 +16,15 @@ import (
 	"time"
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 )
 
 func TestACLEndpoint_GetPolicy(t *testing.T) {
@@ -612,6 +621,679 @@ func TestACLEndpoint_UpsertPolicies_Invalid(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_GetRole(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create the register request
+	role := mock.ACLRole()
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{role})
+
+	// Create a token with the role
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Lookup the role
+	get := &structs.ACLRoleSpecificRequest{
+		Name: role.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLRoleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(uint64(1000), resp.Index)
+	assert.Equal(role, resp.Role)
+
+	// Lookup the role using the token associated with it
+	get.AuthToken = token.SecretID
+	var resp2 structs.SingleACLRoleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(role, resp2.Role)
+
+	// Lookup a role not associated with the token
+	get.Name = "unrelated"
+	var resp3 structs.SingleACLRoleResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &resp3)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_ListRoles(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+
+	r1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	r2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Create a token with one of those roles
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{r1.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Lookup the roles
+	get := &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.EqualValues(1000, resp.Index)
+	assert.Len(resp.Roles, 2)
+
+	// Lookup the roles by prefix
+	get = &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Prefix:    "aaaabb",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp2 structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.EqualValues(1000, resp2.Index)
+	assert.Len(resp2.Roles, 1)
+
+	// List roles using the created token
+	get = &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp3 structs.ACLRoleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", get, &resp3); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.EqualValues(1000, resp3.Index)
+	if assert.Len(resp3.Roles, 1) {
+		assert.Equal(resp3.Roles[0].Name, r1.Name)
+	}
+}
+
+func TestACLEndpoint_DeleteRoles(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1})
+
+	// Delete the role
+	req := &structs.ACLRoleDeleteRequest{
+		Names: []string{r1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteRoles", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we deleted the role
+	out, err := s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestACLEndpoint_UpsertRoles(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+
+	// Upsert the role
+	req := &structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{r1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the role
+	out, err := s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestACLEndpoint_UpsertRoles_Invalid(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	r1 := mock.ACLRole()
+	r1.Policies = nil
+
+	// Upsert the role
+	req := &structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{r1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "missing policies") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestACLEndpoint_GetAuthMethod(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create the register request
+	method := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method})
+
+	// Lookup the auth method
+	get := &structs.ACLAuthMethodSpecificRequest{
+		Name: method.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLAuthMethodResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(uint64(1000), resp.Index)
+	assert.Equal(method, resp.AuthMethod)
+}
+
+func TestACLEndpoint_ListAuthMethods(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am1, am2})
+
+	// Lookup the auth methods
+	get := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLAuthMethodListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.EqualValues(1000, resp.Index)
+	assert.Len(resp.AuthMethods, 2)
+}
+
+func TestACLEndpoint_DeleteAuthMethods(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	am1 := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am1})
+
+	// Delete the auth method
+	req := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{am1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteAuthMethods", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we deleted the auth method
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, am1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	am1 := mock.ACLAuthMethod()
+
+	// Upsert the auth method
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{am1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the auth method
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, am1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestACLEndpoint_UpsertAuthMethods_Invalid(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	am1 := mock.ACLAuthMethod()
+	am1.Type = "saml"
+
+	// Upsert the auth method
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{am1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid type") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestACLEndpoint_GetBindingRule(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create the register request
+	rule := mock.ACLBindingRule()
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule})
+
+	// Lookup the binding rule
+	get := &structs.ACLBindingRuleSpecificRequest{
+		ID: rule.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLBindingRuleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(uint64(1000), resp.Index)
+	assert.Equal(rule, resp.BindingRule)
+}
+
+func TestACLEndpoint_ListBindingRules(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	br1 := mock.ACLBindingRule()
+	br2 := mock.ACLBindingRule()
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{br1, br2})
+
+	// Lookup the binding rules
+	get := &structs.ACLBindingRuleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRuleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.EqualValues(1000, resp.Index)
+	assert.Len(resp.BindingRules, 2)
+}
+
+func TestACLEndpoint_DeleteBindingRules(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	br1 := mock.ACLBindingRule()
+	s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{br1})
+
+	// Delete the binding rule
+	req := &structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{br1.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.DeleteBindingRules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we deleted the binding rule
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, br1.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestACLEndpoint_UpsertBindingRules(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the auth method the rule binds to
+	method := mock.ACLAuthMethod()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method})
+
+	// Create the register request without an ID so the server generates one
+	br1 := mock.ACLBindingRule()
+	br1.ID = ""
+	br1.AuthMethod = method.Name
+
+	// Upsert the binding rule
+	req := &structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{br1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the binding rule with a server generated ID
+	iter, err := s1.fsm.State().ACLBindingRulesByAuthMethod(nil, method.Name)
+	assert.Nil(t, err)
+	out, ok := iter.Next().(*structs.ACLBindingRule)
+	if !ok {
+		t.Fatalf("expected a binding rule")
+	}
+	assert.NotEqual(t, "", out.ID)
+	assert.Nil(t, iter.Next())
+}
+
+func TestACLEndpoint_UpsertBindingRules_UnknownMethod(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a binding rule referencing a method that does not exist
+	br1 := mock.ACLBindingRule()
+
+	// Upsert the binding rule
+	req := &structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{br1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "unknown auth method") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestACLEndpoint_Login_JWT(t *testing.T) {
+	t.Parallel()
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Generate a key pair for the trusted JWT issuer
+	privKey, err := rsa.GenerateKey(crand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	pubBytes, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+
+	// Create the auth method trusting the issuer key
+	method := mock.ACLAuthMethod()
+	method.Config.JWTValidationPubKeys = []string{string(pubPEM)}
+	method.SetHash()
+	s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method})
+
+	// Create a policy and a binding rule granting it to engineers
+	policy := mock.ACLPolicy()
+	s1.fsm.State().UpsertACLPolicies(1001, []*structs.ACLPolicy{policy})
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.Selector = "value.group == \"engineering\""
+	rule.BindType = structs.ACLBindingRuleBindTypePolicy
+	rule.BindName = policy.Name
+	rule.SetHash()
+	s1.fsm.State().UpsertACLBindingRules(1002, []*structs.ACLBindingRule{rule})
+
+	// Map the group claim so the selector can match it
+	method.Config.ClaimMappings = map[string]string{"grp": "group"}
+	method.SetHash()
+	s1.fsm.State().UpsertACLAuthMethods(1003, []*structs.ACLAuthMethod{method})
+
+	// Sign a JWT with the issuer key
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: privKey},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	claims := jwt.Claims{
+		Subject:  "alice",
+		Issuer:   "nomad-test",
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	}
+	loginToken, err := jwt.Signed(signer).
+		Claims(claims).
+		Claims(map[string]interface{}{"grp": "engineering"}).
+		CompactSerialize()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Login with the JWT
+	req := &structs.ACLLoginRequest{
+		AuthMethodName: method.Name,
+		LoginToken:     loginToken,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var resp structs.ACLLoginResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if assert.NotNil(resp.Token) {
+		assert.Equal(structs.ACLClientToken, resp.Token.Type)
+		assert.Equal([]string{policy.Name}, resp.Token.Policies)
+		assert.NotNil(resp.Token.ExpirationTime)
+	}
+
+	// A JWT with a claim that matches no binding rule is rejected
+	otherToken, err := jwt.Signed(signer).
+		Claims(claims).
+		Claims(map[string]interface{}{"grp": "marketing"}).
+		CompactSerialize()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	req.LoginToken = otherToken
+	var resp2 structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp2)
+	assert.NotNil(err)
+	if !strings.Contains(err.Error(), "no binding rules matched") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// A JWT signed by an untrusted key is rejected
+	badKey, err := rsa.GenerateKey(crand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	badSigner, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: badKey},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	badToken, err := jwt.Signed(badSigner).
+		Claims(claims).
+		Claims(map[string]interface{}{"grp": "engineering"}).
+		CompactSerialize()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	req.LoginToken = badToken
+	var resp3 structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp3)
+	assert.NotNil(err)
+}
+
+func TestOIDCProviderCache(t *testing.T) {
+	t.Parallel()
+
+	var discoveryHits, jwksHits int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			discoveryHits++
+			fmt.Fprintf(w, `{"issuer": %q, "jwks_uri": %q}`, ts.URL, ts.URL+"/keys")
+		case "/keys":
+			jwksHits++
+			fmt.Fprint(w, `{"keys": []}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer ts.Close()
+
+	// Repeated lookups within the TTL are served from the cache
+	cache := &oidcProviderCache{}
+	for i := 0; i < 3; i++ {
+		doc, err := cache.discoveryDoc(ts.URL)
+		require.NoError(t, err)
+		require.Equal(t, ts.URL+"/keys", doc.JWKSURI)
+
+		_, err = cache.providerKeys(ts.URL)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, discoveryHits)
+	require.Equal(t, 1, jwksHits)
+}
+
 func TestACLEndpoint_GetToken(t *testing.T) {
 	t.Parallel()
 
@@ -1188,6 +1870,45 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_TTL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a token with an expiration TTL
+	p1 := mock.ACLToken()
+	p1.AccessorID = "" // Blank to create
+	p1.ExpirationTTL = time.Hour
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{p1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	if err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The expiration time should be derived from the create time
+	created := resp.Tokens[0]
+	if assert.NotNil(t, created.ExpirationTime) {
+		assert.Equal(t, created.CreateTime.Add(time.Hour), *created.ExpirationTime)
+	}
+
+	// Changing the expiration of an existing token is not allowed
+	created.ExpirationTTL = 2 * time.Hour
+	req.Tokens[0] = created
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot update expiration")
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	t.Parallel()
 
@@ -1247,3 +1968,60 @@ func TestACLEndpoint_ResolveToken(t *testing.T) {
 	assert.Equal(t, uint64(1000), resp.Index)
 	assert.Nil(t, resp.Token)
 }
+
+func TestACLEndpoint_Check(t *testing.T) {
+	t.Parallel()
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a policy granting write in the default namespace and a token
+	// that uses it
+	policy := mock.ACLPolicy()
+	s1.fsm.State().UpsertACLPolicies(1000, []*structs.ACLPolicy{policy})
+
+	token := mock.ACLToken()
+	token.Policies = []string{policy.Name}
+	s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token})
+
+	// Check a capability the policy grants
+	get := &structs.ACLCheckRequest{
+		SecretID:     token.SecretID,
+		Namespace:    structs.DefaultNamespace,
+		Capability:   acl.NamespaceCapabilitySubmitJob,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.ACLCheckResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.Check", get, &resp))
+	require.True(t, resp.Allow)
+	require.Equal(t, policy.Name, resp.Policy)
+
+	// Check the same capability in a namespace the policy does not cover
+	get.Namespace = "other"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.Check", get, &resp))
+	require.False(t, resp.Allow)
+	require.Equal(t, "", resp.Policy)
+
+	// Management tokens are allowed everything but match no single policy
+	mgmt := mock.ACLManagementToken()
+	s1.fsm.State().UpsertACLTokens(1002, []*structs.ACLToken{mgmt})
+
+	get.SecretID = mgmt.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.Check", get, &resp))
+	require.True(t, resp.Allow)
+	require.Equal(t, "", resp.Policy)
+
+	// A missing capability is an error
+	get.Capability = ""
+	err := msgpackrpc.CallWithCodec(codec, "ACL.Check", get, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing capability")
+
+	// An unknown token is rejected
+	get.Capability = acl.NamespaceCapabilitySubmitJob
+	get.SecretID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Check", get, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrTokenNotFound.Error())
+}
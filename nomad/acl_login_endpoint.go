@@ -0,0 +1,564 @@
+package nomad
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	bexpr "github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// oidcRequestTimeout bounds requests to OIDC providers, which are made
+	// inside RPC handlers. A hung provider must not tie up RPC goroutines,
+	// particularly since ACL.Login requires no token.
+	oidcRequestTimeout = 10 * time.Second
+
+	// oidcMaxResponseBody caps how many bytes are read from OIDC provider
+	// responses.
+	oidcMaxResponseBody = 1024 * 1024
+
+	// oidcCacheTTL is how long cached discovery documents and provider keys
+	// are used before they are refetched.
+	oidcCacheTTL = 5 * time.Minute
+)
+
+// oidcHTTPClient is used for all requests to OIDC providers so that slow or
+// unresponsive providers are cut off at the timeout rather than holding the
+// RPC handler open.
+var oidcHTTPClient = &http.Client{Timeout: oidcRequestTimeout}
+
+// Login is used to authenticate with an auth method and be minted an ACL
+// token with the policies granted by the method's binding rules.
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.Login", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	if args.LoginToken == "" {
+		return structs.NewErrRPCCoded(400, "must specify login token")
+	}
+
+	method, err := a.resolveAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	// Global tokens must be minted in the authoritative region
+	if forwarded, err := a.forwardGlobalLogin(method, "ACL.Login", args, reply); forwarded {
+		return err
+	}
+
+	claims, err := a.verifyAuthMethodJWT(method, args.LoginToken)
+	if err != nil {
+		return structs.NewErrRPCCodedf(401, "%v", err)
+	}
+
+	return a.mintLoginToken(method, claims, reply)
+}
+
+// OIDCAuthURL is used to start an OIDC login flow by generating the provider
+// URL the user should visit to authenticate.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCAuthURL", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	method, err := a.resolveAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+	if method.Type != structs.ACLAuthMethodTypeOIDC {
+		return structs.NewErrRPCCodedf(400, "auth method %s is not an OIDC method", method.Name)
+	}
+	if err := validateRedirectURI(method, args.RedirectURI); err != nil {
+		return err
+	}
+
+	disc, err := a.oidcCache.discoveryDoc(method.Config.OIDCDiscoveryURL)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", method.Config.OIDCClientID)
+	q.Set("redirect_uri", args.RedirectURI)
+	q.Set("scope", "openid")
+	q.Set("state", args.ClientNonce)
+	q.Set("nonce", args.ClientNonce)
+	reply.AuthURL = disc.AuthorizationEndpoint + "?" + q.Encode()
+	return nil
+}
+
+// OIDCCompleteAuth is used to complete an OIDC login flow by exchanging the
+// authorization code returned on the provider's callback for an ID token and
+// minting an ACL token from the resulting identity.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCCompleteAuth", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	if args.Code == "" {
+		return structs.NewErrRPCCoded(400, "must specify authorization code")
+	}
+
+	method, err := a.resolveAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+	if method.Type != structs.ACLAuthMethodTypeOIDC {
+		return structs.NewErrRPCCodedf(400, "auth method %s is not an OIDC method", method.Name)
+	}
+	if err := validateRedirectURI(method, args.RedirectURI); err != nil {
+		return err
+	}
+
+	// Global tokens must be minted in the authoritative region
+	if forwarded, err := a.forwardGlobalLogin(method, "ACL.OIDCCompleteAuth", args, reply); forwarded {
+		return err
+	}
+
+	// The state round-trips through the provider and must match the nonce
+	// generated by the caller.
+	if args.State != "" && args.State != args.ClientNonce {
+		return structs.NewErrRPCCoded(401, "OIDC state does not match client nonce")
+	}
+
+	disc, err := a.oidcCache.discoveryDoc(method.Config.OIDCDiscoveryURL)
+	if err != nil {
+		return err
+	}
+
+	// Exchange the authorization code for an ID token
+	resp, err := oidcHTTPClient.PostForm(disc.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {args.Code},
+		"redirect_uri":  {args.RedirectURI},
+		"client_id":     {method.Config.OIDCClientID},
+		"client_secret": {method.Config.OIDCClientSecret},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return structs.NewErrRPCCodedf(401, "authorization code exchange failed with status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBody)).Decode(&tokenResp); err != nil {
+		return fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if tokenResp.IDToken == "" {
+		return structs.NewErrRPCCoded(401, "provider did not return an ID token")
+	}
+
+	claims, err := a.verifyAuthMethodJWT(method, tokenResp.IDToken)
+	if err != nil {
+		return structs.NewErrRPCCodedf(401, "%v", err)
+	}
+
+	// The nonce claim must match the nonce generated by the caller to tie
+	// the ID token to this login.
+	if nonce, ok := claims["nonce"].(string); ok && args.ClientNonce != "" && nonce != args.ClientNonce {
+		return structs.NewErrRPCCoded(401, "OIDC nonce does not match client nonce")
+	}
+
+	return a.mintLoginToken(method, claims, reply)
+}
+
+// resolveAuthMethod returns the named auth method, or the default auth
+// method when no name is given.
+func (a *ACL) resolveAuthMethod(name string) (*structs.ACLAuthMethod, error) {
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	var method *structs.ACLAuthMethod
+	if name != "" {
+		method, err = snap.ACLAuthMethodByName(nil, name)
+	} else {
+		method, err = snap.DefaultACLAuthMethod(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if method == nil {
+		if name == "" {
+			return nil, structs.NewErrRPCCoded(400, "no default auth method configured")
+		}
+		return nil, structs.NewErrRPCCodedf(400, "auth method %s not found", name)
+	}
+	return method, nil
+}
+
+// forwardGlobalLogin forwards a login request to the authoritative region
+// when the auth method mints global tokens, since global tokens must be
+// written there. It returns whether the request was forwarded.
+func (a *ACL) forwardGlobalLogin(method *structs.ACLAuthMethod, rpc string, args, reply interface{}) (bool, error) {
+	if method.TokenLocality == structs.ACLAuthMethodTokenLocalityGlobal &&
+		a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		return true, a.srv.forwardRegion(a.srv.config.AuthoritativeRegion, rpc, args, reply)
+	}
+	return false, nil
+}
+
+// mintLoginToken applies the auth method's binding rules to the verified
+// identity claims and mints the resulting ACL token.
+func (a *ACL) mintLoginToken(method *structs.ACLAuthMethod, claims map[string]interface{}, reply *structs.ACLLoginResponse) error {
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	iter, err := snap.ACLBindingRulesByAuthMethod(nil, method.Name)
+	if err != nil {
+		return err
+	}
+
+	// Gather the grants from the binding rules that match the identity
+	selectorData := authMethodSelectorData(method, claims)
+	var policies, roles []string
+	management := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+
+		if rule.Selector != "" {
+			eval, err := bexpr.CreateEvaluator(rule.Selector)
+			if err != nil {
+				return err
+			}
+			match, err := eval.Evaluate(selectorData)
+			if err != nil || !match {
+				continue
+			}
+		}
+
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypePolicy:
+			policies = append(policies, rule.BindName)
+		case structs.ACLBindingRuleBindTypeRole:
+			roles = append(roles, rule.BindName)
+		case structs.ACLBindingRuleBindTypeManagement:
+			management = true
+		}
+	}
+
+	if !management && len(policies) == 0 && len(roles) == 0 {
+		return structs.NewErrRPCCoded(403, "no binding rules matched the provided identity")
+	}
+
+	// Construct the token. The TTL is fixed by the auth method.
+	name := fmt.Sprintf("%s auth method login", method.Name)
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		name = fmt.Sprintf("%s login for %s", method.Name, sub)
+	}
+
+	token := &structs.ACLToken{
+		AccessorID:    uuid.Generate(),
+		SecretID:      uuid.Generate(),
+		Name:          name,
+		Global:        method.TokenLocality == structs.ACLAuthMethodTokenLocalityGlobal,
+		CreateTime:    time.Now().UTC(),
+		ExpirationTTL: method.MaxTokenTTL,
+	}
+	if management {
+		token.Type = structs.ACLManagementToken
+	} else {
+		token.Type = structs.ACLClientToken
+		token.Policies = policies
+		token.Roles = roles
+	}
+
+	expiration := token.CreateTime.Add(token.ExpirationTTL)
+	token.ExpirationTime = &expiration
+	token.SetHash()
+
+	// Update via Raft
+	req := &structs.ACLTokenUpsertRequest{Tokens: []*structs.ACLToken{token}}
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	reply.Token = token
+	reply.Index = index
+	return nil
+}
+
+// validateRedirectURI ensures the redirect URI is allowed by the auth method
+func validateRedirectURI(method *structs.ACLAuthMethod, redirectURI string) error {
+	for _, allowed := range method.Config.AllowedRedirectURIs {
+		if allowed == redirectURI {
+			return nil
+		}
+	}
+	return structs.NewErrRPCCodedf(400, "redirect URI %s is not allowed by auth method %s", redirectURI, method.Name)
+}
+
+// oidcDiscoveryDoc is the subset of the OIDC discovery document used to
+// drive login flows.
+type oidcDiscoveryDoc struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcProviderCache caches OIDC discovery documents and provider keys per
+// discovery URL so that logins do not refetch them from the provider on
+// every request. The zero value is ready for use.
+type oidcProviderCache struct {
+	lock      sync.Mutex
+	providers map[string]*oidcProviderEntry
+}
+
+// oidcProviderEntry holds the cached provider metadata for one discovery URL
+type oidcProviderEntry struct {
+	doc        *oidcDiscoveryDoc
+	docFetched time.Time
+
+	keys        []interface{}
+	keysFetched time.Time
+}
+
+// discoveryDoc returns the provider's discovery document, fetching it only
+// when the cached copy is missing or older than oidcCacheTTL.
+func (c *oidcProviderCache) discoveryDoc(discoveryURL string) (*oidcDiscoveryDoc, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.discoveryDocLocked(discoveryURL)
+}
+
+func (c *oidcProviderCache) discoveryDocLocked(discoveryURL string) (*oidcDiscoveryDoc, error) {
+	entry := c.entryLocked(discoveryURL)
+	if entry.doc != nil && time.Since(entry.docFetched) < oidcCacheTTL {
+		return entry.doc, nil
+	}
+
+	doc, err := fetchOIDCDiscovery(discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+	entry.doc = doc
+	entry.docFetched = time.Now()
+	return doc, nil
+}
+
+// providerKeys returns the provider's JWKS signing keys, fetching them only
+// when the cached copy is missing or older than oidcCacheTTL.
+func (c *oidcProviderCache) providerKeys(discoveryURL string) ([]interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := c.entryLocked(discoveryURL)
+	if entry.keys != nil && time.Since(entry.keysFetched) < oidcCacheTTL {
+		return entry.keys, nil
+	}
+
+	doc, err := c.discoveryDocLocked(discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := fetchOIDCKeys(doc.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	entry.keys = keys
+	entry.keysFetched = time.Now()
+	return keys, nil
+}
+
+func (c *oidcProviderCache) entryLocked(discoveryURL string) *oidcProviderEntry {
+	if c.providers == nil {
+		c.providers = map[string]*oidcProviderEntry{}
+	}
+	entry, ok := c.providers[discoveryURL]
+	if !ok {
+		entry = &oidcProviderEntry{}
+		c.providers[discoveryURL] = entry
+	}
+	return entry
+}
+
+// fetchOIDCDiscovery retrieves the OIDC discovery document from the auth
+// method's configured provider.
+func fetchOIDCDiscovery(discoveryURL string) (*oidcDiscoveryDoc, error) {
+	wellKnown := strings.TrimSuffix(discoveryURL, "/") + "/.well-known/openid-configuration"
+	resp, err := oidcHTTPClient.Get(wellKnown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching OIDC discovery document", resp.StatusCode)
+	}
+
+	var doc oidcDiscoveryDoc
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBody)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC discovery document: %v", err)
+	}
+	return &doc, nil
+}
+
+// fetchOIDCKeys retrieves the signing keys from the provider's JWKS endpoint.
+func fetchOIDCKeys(jwksURI string) ([]interface{}, error) {
+	resp, err := oidcHTTPClient.Get(jwksURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching JWKS", resp.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBody)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %v", err)
+	}
+	keys := make([]interface{}, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		keys = append(keys, key.Key)
+	}
+	return keys, nil
+}
+
+// authMethodKeys returns the public keys trusted by the auth method. For JWT
+// methods these are the statically configured keys; for OIDC methods they are
+// the cached keys from the provider's JWKS endpoint.
+func (a *ACL) authMethodKeys(method *structs.ACLAuthMethod) ([]interface{}, error) {
+	switch method.Type {
+	case structs.ACLAuthMethodTypeJWT:
+		keys := make([]interface{}, 0, len(method.Config.JWTValidationPubKeys))
+		for _, raw := range method.Config.JWTValidationPubKeys {
+			block, _ := pem.Decode([]byte(raw))
+			if block == nil {
+				return nil, fmt.Errorf("failed to decode JWT validation public key PEM")
+			}
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse JWT validation public key: %v", err)
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+
+	case structs.ACLAuthMethodTypeOIDC:
+		return a.oidcCache.providerKeys(method.Config.OIDCDiscoveryURL)
+
+	default:
+		return nil, fmt.Errorf("unknown auth method type %s", method.Type)
+	}
+}
+
+// verifyAuthMethodJWT verifies the given token against the auth method's
+// trusted keys and bound claims, returning the full set of identity claims.
+func (a *ACL) verifyAuthMethodJWT(method *structs.ACLAuthMethod, token string) (map[string]interface{}, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse login token: %v", err)
+	}
+
+	keys, err := a.authMethodKeys(method)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find a trusted key that verifies the token signature
+	var claims jwt.Claims
+	allClaims := map[string]interface{}{}
+	verified := false
+	for _, key := range keys {
+		if err := parsed.Claims(key, &claims, &allClaims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("login token signed by unknown key")
+	}
+
+	// Validate the standard claims
+	expected := jwt.Expected{Time: time.Now().UTC()}
+	if method.Config.BoundIssuer != "" {
+		expected.Issuer = method.Config.BoundIssuer
+	}
+	if err := claims.Validate(expected); err != nil {
+		return nil, fmt.Errorf("login token validation failed: %v", err)
+	}
+
+	// Validate the audience when one is bound
+	if len(method.Config.BoundAudiences) != 0 {
+		found := false
+		for _, aud := range method.Config.BoundAudiences {
+			if err := claims.Validate(jwt.Expected{Audience: jwt.Audience{aud}}); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("login token audience is not bound to the auth method")
+		}
+	}
+
+	return allClaims, nil
+}
+
+// authMethodSelectorData builds the data binding rule selectors evaluate
+// against by projecting the identity claims through the auth method's claim
+// mappings.
+func authMethodSelectorData(method *structs.ACLAuthMethod, claims map[string]interface{}) map[string]interface{} {
+	values := map[string]string{}
+	lists := map[string][]string{}
+	if method.Config != nil {
+		for claim, name := range method.Config.ClaimMappings {
+			if v, ok := claims[claim]; ok {
+				values[name] = fmt.Sprintf("%v", v)
+			}
+		}
+		for claim, name := range method.Config.ListClaimMappings {
+			raw, ok := claims[claim]
+			if !ok {
+				continue
+			}
+			if items, ok := raw.([]interface{}); ok {
+				for _, item := range items {
+					lists[name] = append(lists[name], fmt.Sprintf("%v", item))
+				}
+			}
+		}
+	}
+	return map[string]interface{}{
+		"value": values,
+		"list":  lists,
+	}
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -92,6 +93,68 @@ func TestResolveACLToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_Roles(t *testing.T) {
+	t.Parallel()
+
+	// Create mock state store and cache
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a policy, a role linking it, and a token with only the role
+	policy := mock.ACLPolicy()
+	err = state.UpsertACLPolicies(100, []*structs.ACLPolicy{policy})
+	assert.Nil(t, err)
+
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	err = state.UpsertACLRoles(110, []*structs.ACLRole{role})
+	assert.Nil(t, err)
+
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name, "nonexistent-role"}
+	err = state.UpsertACLTokens(120, []*structs.ACLToken{token})
+	assert.Nil(t, err)
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	// The token should resolve to the policies of its role
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, aclObj)
+
+	allowed := aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs)
+	assert.Equal(t, true, allowed)
+	allowed = aclObj.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs)
+	assert.Equal(t, false, allowed)
+}
+
+func TestResolveACLToken_Expired(t *testing.T) {
+	t.Parallel()
+
+	// Create mock state store and cache
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a token that has already expired
+	token := mock.ACLToken()
+	expiration := time.Now().UTC().Add(-1 * time.Hour)
+	token.ExpirationTime = &expiration
+	err = state.UpsertACLTokens(100, []*structs.ACLToken{token})
+	assert.Nil(t, err)
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	// Attempt resolution of the expired token. Should fail.
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Equal(t, structs.ErrTokenExpired, err)
+	assert.Nil(t, aclObj)
+}
+
 func TestResolveACLToken_LeaderToken(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -108,6 +171,45 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_TLSIdentity(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	s1, _, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.TLSIdentityPolicies = map[string][]string{
+			"cli.global.nomad": {"readonly"},
+		}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the policy the identity maps to
+	policy := mock.ACLPolicy()
+	policy.Name = "readonly"
+	err := s1.fsm.State().UpsertACLPolicies(100, []*structs.ACLPolicy{policy})
+	assert.Nil(err)
+
+	// A mapped identity resolves to the policies it is granted
+	aclObj, err := s1.ResolveToken(structs.ACLTLSIdentityPrefix + "cli.global.nomad")
+	assert.Nil(err)
+	if assert.NotNil(aclObj) {
+		assert.True(aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+		assert.False(aclObj.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+	}
+
+	// An unmapped identity is treated as an unknown token
+	aclObj, err = s1.ResolveToken(structs.ACLTLSIdentityPrefix + "other.global.nomad")
+	assert.Equal(structs.ErrTokenNotFound, err)
+	assert.Nil(aclObj)
+
+	// The identity also resolves to a synthetic client token
+	token, err := s1.ResolveSecretToken(structs.ACLTLSIdentityPrefix + "cli.global.nomad")
+	assert.Nil(err)
+	if assert.NotNil(token) {
+		assert.Equal(structs.ACLClientToken, token.Type)
+		assert.Equal([]string{"readonly"}, token.Policies)
+	}
+}
+
 func TestResolveSecretToken(t *testing.T) {
 	t.Parallel()
 
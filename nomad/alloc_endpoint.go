@@ -66,14 +66,23 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 			}
 
 			var allocs []*structs.AllocListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				alloc := raw.(*structs.Allocation)
-				allocs = append(allocs, alloc.Stub())
+			paginator, err := newListPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Allocation).ID
+				},
+				func(raw interface{}) error {
+					allocs = append(allocs, raw.(*structs.Allocation).Stub(args.Fields))
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Allocations = allocs
 
 			// Use the last index that affected the jobs table
@@ -281,6 +290,109 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 	return nil
 }
 
+// Hold is used to place an allocation on hold for debugging, or to release a
+// previously placed hold. While held, the scheduler does not replace the
+// allocation and the client pauses its restart policy so the task environment
+// can be inspected.
+func (a *Alloc) Hold(args *structs.AllocHoldRequest, reply *structs.AllocHoldResponse) error {
+	if done, err := a.srv.forward("Alloc.Hold", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "hold"}, time.Now())
+
+	alloc, err := getAlloc(a.srv.State(), args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check for namespace alloc-lifecycle permissions.
+	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if !allowNsOp(aclObj, alloc.Namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      alloc.Namespace,
+		Priority:       alloc.Job.Priority,
+		Type:           alloc.Job.Type,
+		TriggeredBy:    structs.EvalTriggerAllocHold,
+		JobID:          alloc.Job.ID,
+		JobModifyIndex: alloc.Job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+
+	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Evals: []*structs.Evaluation{eval},
+		Allocs: map[string]*structs.DesiredTransition{
+			args.AllocID: {
+				Hold: helper.BoolToPtr(!args.Release),
+			},
+		},
+	}
+
+	// Commit this update via Raft
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
+	if err != nil {
+		a.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err)
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	reply.EvalID = eval.ID
+	return nil
+}
+
+// SetCheckpointMeta is used to replace the checkpoint metadata of an
+// allocation.
+func (a *Alloc) SetCheckpointMeta(args *structs.AllocCheckpointMetaSetRequest, reply *structs.GenericResponse) error {
+	if done, err := a.srv.forward("Alloc.SetCheckpointMeta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "set_checkpoint_meta"}, time.Now())
+
+	// Bound the size of the stored metadata
+	size := 0
+	for k, v := range args.Meta {
+		size += len(k) + len(v)
+	}
+	if size > structs.AllocCheckpointMetaMaxSize {
+		return fmt.Errorf("checkpoint metadata exceeds maximum size of %d bytes", structs.AllocCheckpointMetaMaxSize)
+	}
+
+	alloc, err := getAlloc(a.srv.State(), args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check for namespace alloc-lifecycle permissions.
+	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if !allowNsOp(aclObj, alloc.Namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Commit this update via Raft
+	_, index, err := a.srv.raftApply(structs.AllocCheckpointMetaSetRequestType, args)
+	if err != nil {
+		a.logger.Error("AllocCheckpointMetaSetRequest failed", "error", err)
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	return nil
+}
+
 // UpdateDesiredTransition is used to update the desired transitions of an
 // allocation.
 func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransitionRequest, reply *structs.GenericResponse) error {
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -84,6 +85,106 @@ func TestAllocEndpoint_List(t *testing.T) {
 	}
 }
 
+func TestAllocEndpoint_List_Pagination(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create allocs with ordered IDs.
+	state := s1.fsm.State()
+	ids := []string{
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+		"aaaabbbb-3350-4b4b-d185-0e1992ed43e9",
+		"aaaacccc-3350-4b4b-d185-0e1992ed43e9",
+	}
+	allocs := make([]*structs.Allocation, len(ids))
+	for i, id := range ids {
+		alloc := mock.Alloc()
+		alloc.ID = id
+		require.NoError(t, state.UpsertJobSummary(uint64(900+i), mock.JobSummary(alloc.JobID)))
+		allocs[i] = alloc
+	}
+	require.NoError(t, state.UpsertAllocs(1000, allocs))
+
+	// Fetch the first page.
+	get := &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   2,
+		},
+	}
+	var resp structs.AllocListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Len(t, resp.Allocations, 2)
+	require.Equal(t, ids[2], resp.NextToken)
+
+	// Fetch the second and last page.
+	get.QueryOptions.NextToken = resp.NextToken
+	var resp2 structs.AllocListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp2))
+	require.Len(t, resp2.Allocations, 1)
+	require.Equal(t, ids[2], resp2.Allocations[0].ID)
+	require.Equal(t, "", resp2.NextToken)
+
+	// Fetch with a filter expression.
+	get = &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			Filter:    fmt.Sprintf("JobID == %q", allocs[1].JobID),
+		},
+	}
+	var resp3 structs.AllocListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp3))
+	require.Len(t, resp3.Allocations, 1)
+	require.Equal(t, ids[1], resp3.Allocations[0].ID)
+}
+
+func TestAllocEndpoint_List_Fields(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	alloc.TaskStates = map[string]*structs.TaskState{
+		"web": {State: structs.TaskStateRunning},
+	}
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	// The default stub includes task states but no resources
+	get := &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.AllocListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Len(t, resp.Allocations, 1)
+	require.NotNil(t, resp.Allocations[0].TaskStates)
+	require.Nil(t, resp.Allocations[0].AllocatedResources)
+
+	// Project only the resources
+	get.Fields = &structs.AllocStubFields{
+		Resources:  true,
+		TaskStates: false,
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Len(t, resp.Allocations, 1)
+	require.Nil(t, resp.Allocations[0].TaskStates)
+	require.NotNil(t, resp.Allocations[0].AllocatedResources)
+}
+
 func TestAllocEndpoint_List_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -102,7 +203,7 @@ func TestAllocEndpoint_List_ACL(t *testing.T) {
 	assert.Nil(state.UpsertJobSummary(999, summary), "UpsertJobSummary")
 	assert.Nil(state.UpsertAllocs(1000, allocs), "UpsertAllocs")
 
-	stubAllocs := []*structs.AllocListStub{alloc.Stub()}
+	stubAllocs := []*structs.AllocListStub{alloc.Stub(nil)}
 	stubAllocs[0].CreateIndex = 1000
 	stubAllocs[0].ModifyIndex = 1000
 
@@ -752,3 +853,95 @@ func TestAllocEndpoint_Stop_ACL(t *testing.T) {
 	require.True(*out1.DesiredTransition.Migrate)
 	require.True(*out2.DesiredTransition.Migrate)
 }
+
+func TestAllocEndpoint_Hold(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	// Place the allocation on hold
+	req := &structs.AllocHoldRequest{
+		AllocID: alloc.ID,
+	}
+	req.Namespace = structs.DefaultNamespace
+	req.Region = alloc.Job.Region
+
+	var resp structs.AllocHoldResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Hold", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldHold())
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.Nil(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerAllocHold, eval.TriggeredBy)
+
+	// Release the hold
+	req.Release = true
+	var resp2 structs.AllocHoldResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Hold", req, &resp2))
+	require.NotZero(resp2.Index)
+
+	out, err = state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.False(out.DesiredTransition.ShouldHold())
+
+	// An unknown allocation returns an error
+	req.AllocID = uuid.Generate()
+	var resp3 structs.AllocHoldResponse
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Hold", req, &resp3)
+	require.True(structs.IsErrUnknownAllocation(err), "expected unknown alloc error, got: %v", err)
+}
+
+func TestAllocEndpoint_Hold_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocHoldRequest{
+		AllocID: alloc.ID,
+	}
+	req.Namespace = structs.DefaultNamespace
+	req.Region = alloc.Job.Region
+
+	// Try without permissions
+	var resp structs.AllocHoldResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Hold", req, &resp)
+	require.True(structs.IsErrPermissionDenied(err), "expected permissions error, got: %v", err)
+
+	// Try with alloc-lifecycle permissions
+	validToken := mock.CreatePolicyAndToken(t, state, 1002, "valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	req.WriteRequest.AuthToken = validToken.SecretID
+
+	var resp2 structs.AllocHoldResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Hold", req, &resp2))
+	require.NotZero(resp2.Index)
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldHold())
+}
@@ -1,6 +1,9 @@
 package nomad
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +26,10 @@ const (
 
 	// pruneThreshold is the threshold after which objects will be pruned.
 	pruneThreshold = 15 * time.Minute
+
+	// escalationCheckInterval is the interval at which blocked evaluations
+	// are checked against the escalation threshold.
+	escalationCheckInterval = 1 * time.Minute
 )
 
 // BlockedEvals is used to track evaluations that shouldn't be queued until a
@@ -74,6 +81,15 @@ type BlockedEvals struct {
 	// duplicates.
 	duplicateCh chan struct{}
 
+	// escalationThreshold is the duration after which a still blocked
+	// evaluation is escalated with a warning summarizing the missing
+	// resources. A zero value disables escalation.
+	escalationThreshold time.Duration
+
+	// escalated is the set of evaluation IDs that have already been
+	// escalated so that each blocked eval is only reported once.
+	escalated map[string]struct{}
+
 	// timetable is used to correlate indexes with their insertion time. This
 	// allows us to prune based on time.
 	timetable *TimeTable
@@ -120,6 +136,7 @@ func NewBlockedEvals(evalBroker *EvalBroker, logger log.Logger) *BlockedEvals {
 		system:           newSystemEvals(),
 		jobs:             make(map[structs.NamespacedID]string),
 		unblockIndexes:   make(map[string]uint64),
+		escalated:        make(map[string]struct{}),
 		capacityChangeCh: make(chan *capacityUpdate, unblockBuffer),
 		duplicateCh:      make(chan struct{}, 1),
 		stopCh:           make(chan struct{}),
@@ -145,6 +162,9 @@ func (b *BlockedEvals) SetEnabled(enabled bool) {
 	} else if enabled {
 		go b.watchCapacity(b.stopCh, b.capacityChangeCh)
 		go b.prune(b.stopCh)
+		if b.escalationThreshold > 0 {
+			go b.watchEscalations(b.stopCh)
+		}
 	} else {
 		close(b.stopCh)
 	}
@@ -161,6 +181,15 @@ func (b *BlockedEvals) SetTimetable(timetable *TimeTable) {
 	b.l.Unlock()
 }
 
+// SetEscalationThreshold is used to configure the duration after which a
+// still blocked evaluation is escalated. It must be called before the
+// tracker is enabled.
+func (b *BlockedEvals) SetEscalationThreshold(threshold time.Duration) {
+	b.l.Lock()
+	b.escalationThreshold = threshold
+	b.l.Unlock()
+}
+
 // Block tracks the passed evaluation and enqueues it into the eval broker when
 // a suitable node calls unblock.
 func (b *BlockedEvals) Block(eval *structs.Evaluation) {
@@ -674,6 +703,42 @@ SCAN:
 	}
 }
 
+// QueuePosition returns the approximate position of the given evaluation
+// among all blocked evaluations, with one being the next in line to be
+// unblocked. Evaluations are ordered by priority and then by creation order.
+// Zero is returned if the evaluation is not currently blocked.
+func (b *BlockedEvals) QueuePosition(eval *structs.Evaluation) int {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	w, ok := b.captured[eval.ID]
+	if !ok {
+		if w, ok = b.escaped[eval.ID]; !ok {
+			return 0
+		}
+	}
+
+	ahead := func(other *structs.Evaluation) bool {
+		if other.Priority != w.eval.Priority {
+			return other.Priority > w.eval.Priority
+		}
+		return other.CreateIndex < w.eval.CreateIndex
+	}
+
+	position := 1
+	for id, wrapped := range b.captured {
+		if id != eval.ID && ahead(wrapped.eval) {
+			position++
+		}
+	}
+	for id, wrapped := range b.escaped {
+		if id != eval.ID && ahead(wrapped.eval) {
+			position++
+		}
+	}
+	return position
+}
+
 // Flush is used to clear the state of blocked evaluations.
 func (b *BlockedEvals) Flush() {
 	b.l.Lock()
@@ -687,6 +752,7 @@ func (b *BlockedEvals) Flush() {
 	b.escaped = make(map[string]wrappedEval)
 	b.jobs = make(map[structs.NamespacedID]string)
 	b.unblockIndexes = make(map[string]uint64)
+	b.escalated = make(map[string]struct{})
 	b.timetable = nil
 	b.duplicates = nil
 	b.capacityChangeCh = make(chan *capacityUpdate, unblockBuffer)
@@ -725,6 +791,108 @@ func (b *BlockedEvals) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 	}
 }
 
+// watchEscalations is a long lived function that periodically checks
+// blocked evaluations against the escalation threshold.
+func (b *BlockedEvals) watchEscalations(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(escalationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			b.checkEscalations(time.Now().UTC())
+		}
+	}
+}
+
+// checkEscalations escalates any evaluation that has been blocked longer
+// than the configured threshold. Each evaluation is only escalated once so
+// operators are not paged repeatedly for the same placement failure.
+func (b *BlockedEvals) checkEscalations(now time.Time) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if b.escalationThreshold == 0 {
+		return
+	}
+
+	// tracked is used to drop escalation records for evaluations that are
+	// no longer blocked.
+	tracked := make(map[string]struct{}, len(b.captured)+len(b.escaped)+len(b.system.evals))
+
+	check := func(w wrappedEval) {
+		tracked[w.eval.ID] = struct{}{}
+
+		blockedFor := now.Sub(time.Unix(0, w.eval.CreateTime))
+		if blockedFor < b.escalationThreshold {
+			return
+		}
+		if _, ok := b.escalated[w.eval.ID]; ok {
+			return
+		}
+		b.escalated[w.eval.ID] = struct{}{}
+
+		b.logger.Warn("evaluation blocked beyond escalation threshold",
+			"eval_id", w.eval.ID, "job_id", w.eval.JobID, "namespace", w.eval.Namespace,
+			"blocked_for", blockedFor.Round(time.Second),
+			"missing_resources", missingResourcesSummary(w.eval))
+		metrics.IncrCounter([]string{"nomad", "blocked_evals", "escalated"}, 1)
+	}
+
+	for _, w := range b.captured {
+		check(w)
+	}
+	for _, w := range b.escaped {
+		check(w)
+	}
+	for _, w := range b.system.evals {
+		check(*w)
+	}
+
+	// Forget evaluations that have since been unblocked
+	for id := range b.escalated {
+		if _, ok := tracked[id]; !ok {
+			delete(b.escalated, id)
+		}
+	}
+}
+
+// missingResourcesSummary builds a human readable summary of why an
+// evaluation's placements failed, suitable for escalation notices.
+func missingResourcesSummary(eval *structs.Evaluation) string {
+	if len(eval.FailedTGAllocs) == 0 {
+		return "unknown"
+	}
+
+	groups := make([]string, 0, len(eval.FailedTGAllocs))
+	for tg := range eval.FailedTGAllocs {
+		groups = append(groups, tg)
+	}
+	sort.Strings(groups)
+
+	var parts []string
+	for _, tg := range groups {
+		metric := eval.FailedTGAllocs[tg]
+
+		var reasons []string
+		for dim, count := range metric.DimensionExhausted {
+			reasons = append(reasons, fmt.Sprintf("%s exhausted on %d nodes", dim, count))
+		}
+		for class, count := range metric.ClassExhausted {
+			reasons = append(reasons, fmt.Sprintf("class %q exhausted on %d nodes", class, count))
+		}
+		sort.Strings(reasons)
+		if len(reasons) == 0 {
+			reasons = append(reasons, fmt.Sprintf("%d nodes filtered", metric.NodesFiltered))
+		}
+
+		parts = append(parts, fmt.Sprintf("%s: %s", tg, strings.Join(reasons, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
 // prune is a long lived function that prunes unnecessary objects on a timer.
 func (b *BlockedEvals) prune(stopCh <-chan struct{}) {
 	ticker := time.NewTicker(pruneInterval)
@@ -57,6 +57,33 @@ func TestBlockedEvals_Block_SameJob(t *testing.T) {
 	}
 }
 
+func TestBlockedEvals_QueuePosition(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+
+	// Create three blocked evals with differing priority and creation order
+	e := mock.Eval()
+	e.Priority = 50
+	e.CreateIndex = 100
+	e2 := mock.Eval()
+	e2.Priority = 80
+	e2.CreateIndex = 200
+	e3 := mock.Eval()
+	e3.Priority = 50
+	e3.CreateIndex = 300
+	blocked.Block(e)
+	blocked.Block(e2)
+	blocked.Block(e3)
+
+	// Higher priority evals are ahead in line; ties are broken by creation order
+	require.Equal(t, 1, blocked.QueuePosition(e2))
+	require.Equal(t, 2, blocked.QueuePosition(e))
+	require.Equal(t, 3, blocked.QueuePosition(e3))
+
+	// Evals that aren't blocked have no queue position
+	require.Equal(t, 0, blocked.QueuePosition(mock.Eval()))
+}
+
 func TestBlockedEvals_Block_Quota(t *testing.T) {
 	t.Parallel()
 	blocked, _ := testBlockedEvals(t)
@@ -671,3 +698,72 @@ func TestBlockedEvals_SystemDisableFlush(t *testing.T) {
 	require.Empty(t, blocked.system.byJob)
 	require.Empty(t, blocked.system.byNode)
 }
+
+func TestBlockedEvals_Escalation(t *testing.T) {
+	t.Parallel()
+	blocked, _ := testBlockedEvals(t)
+	blocked.SetEscalationThreshold(time.Hour)
+
+	// Create a blocked eval that has been blocked past the threshold
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.EscapedComputedClass = true
+	e.CreateTime = time.Now().Add(-2 * time.Hour).UnixNano()
+	e.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			DimensionExhausted: map[string]int{"memory": 3},
+		},
+	}
+	blocked.Block(e)
+
+	// Create a recently blocked eval that should not be escalated
+	e2 := mock.Eval()
+	e2.Status = structs.EvalStatusBlocked
+	e2.EscapedComputedClass = true
+	e2.CreateTime = time.Now().UnixNano()
+	blocked.Block(e2)
+
+	now := time.Now().UTC()
+	blocked.checkEscalations(now)
+
+	blocked.l.RLock()
+	_, oldEscalated := blocked.escalated[e.ID]
+	_, newEscalated := blocked.escalated[e2.ID]
+	blocked.l.RUnlock()
+	require.True(t, oldEscalated)
+	require.False(t, newEscalated)
+
+	// A second check should not escalate the same eval again
+	blocked.checkEscalations(now)
+	blocked.l.RLock()
+	count := len(blocked.escalated)
+	blocked.l.RUnlock()
+	require.Equal(t, 1, count)
+
+	// Once the eval is unblocked the escalation record is dropped
+	blocked.Untrack(e.JobID, e.Namespace)
+	blocked.checkEscalations(now)
+	blocked.l.RLock()
+	_, oldEscalated = blocked.escalated[e.ID]
+	blocked.l.RUnlock()
+	require.False(t, oldEscalated)
+}
+
+func TestBlockedEvals_MissingResourcesSummary(t *testing.T) {
+	t.Parallel()
+
+	e := mock.Eval()
+	require.Equal(t, "unknown", missingResourcesSummary(e))
+
+	e.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			DimensionExhausted: map[string]int{"memory": 3},
+			ClassExhausted:     map[string]int{"high-compute": 2},
+		},
+		"cache": {
+			NodesFiltered: 5,
+		},
+	}
+	summary := missingResourcesSummary(e)
+	require.Equal(t, `cache: 5 nodes filtered; web: class "high-compute" exhausted on 2 nodes, memory exhausted on 3 nodes`, summary)
+}
@@ -30,11 +30,11 @@ func (a *Agent) register() {
 }
 
 func (a *Agent) Profile(args *structs.AgentPprofRequest, reply *structs.AgentPprofResponse) error {
-	// Check ACL for agent write
+	// Check ACL for agent debug
 	aclObj, err := a.srv.ResolveToken(args.AuthToken)
 	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowAgentWrite() {
+	} else if aclObj != nil && !aclObj.AllowAgentDebug() {
 		return structs.ErrPermissionDenied
 	}
 
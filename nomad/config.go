@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/scheduler"
@@ -155,6 +156,13 @@ type Config struct {
 	// that the workers dequeue for processing.
 	EnabledSchedulers []string
 
+	// SchedulerWorkerPins maps scheduler types to a number of workers that
+	// are dedicated to evaluations of that type. Pinned workers reserve
+	// scheduling capacity so a flood of evaluations of one type cannot
+	// starve the others. The total number of pinned workers must be less
+	// than NumSchedulers so unpinned workers remain to serve the rest.
+	SchedulerWorkerPins map[string]int
+
 	// ReconcileInterval controls how often we reconcile the strongly
 	// consistent store with the Serf info. This is used to handle nodes
 	// that are force removed, as well as intermittent unavailability during
@@ -176,6 +184,10 @@ type Config struct {
 	// the user time to inspect the job.
 	JobGCThreshold time.Duration
 
+	// JobExpirationInterval is how often we dispatch a job to stop jobs
+	// whose expiration deadline has passed.
+	JobExpirationInterval time.Duration
+
 	// NodeGCInterval is how often we dispatch a job to GC failed nodes.
 	NodeGCInterval time.Duration
 
@@ -191,6 +203,11 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// CanaryGCThreshold is how "old" a failed canary allocation of a failed
+	// deployment must be to be eligible for GC. The deployment itself is
+	// retained so its failure details remain queryable.
+	CanaryGCThreshold time.Duration
+
 	// CSIPluginGCInterval is how often we dispatch a job to GC unused plugins.
 	CSIPluginGCInterval time.Duration
 
@@ -202,10 +219,25 @@ type Config struct {
 	// volume claims.
 	CSIVolumeClaimGCInterval time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
+	// ACLTokenExpirationGCThreshold is how long an ACL token must be expired
+	// before it is eligible for GC. This gives users some time to observe
+	// that a token has expired before it disappears.
+	ACLTokenExpirationGCThreshold time.Duration
+
 	// CSIVolumeClaimGCThreshold is how "old" a volume must be to be
 	// eligible for GC. This gives users some time to debug volumes.
 	CSIVolumeClaimGCThreshold time.Duration
 
+	// LicenseFeatureGracePeriod controls how long after server start an
+	// unlicensed feature is allowed but logged and counted rather than
+	// denied. This gives operators time to update their license after an
+	// upgrade introduces new feature gating. Defaults to 6 hours.
+	LicenseFeatureGracePeriod time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -242,6 +274,32 @@ type Config struct {
 	// additional delay is selected from this range randomly.
 	EvalFailedFollowupDelayRange time.Duration
 
+	// BlockedEvalEscalationThreshold is the duration after which an
+	// evaluation that is still blocked is escalated by emitting a warning
+	// that summarizes the missing resources. A zero value disables
+	// escalation notices.
+	BlockedEvalEscalationThreshold time.Duration
+
+	// JobDefaults are operator-configured default job values merged into
+	// submitted jobs at admission when the job does not specify them.
+	JobDefaults *config.JobDefaults
+
+	// EventBufferSize is the number of event batches the event broker
+	// retains for index-based resumption.
+	EventBufferSize int
+
+	// EventBufferPath enables on-disk persistence of the event buffer
+	// when non-empty, so subscribers can resume across server restarts.
+	EventBufferPath string
+
+	// PeriodicLaunchFailureWebhooks are webhook URLs that periodic job
+	// launch failures are posted to.
+	PeriodicLaunchFailureWebhooks []string
+
+	// DispatchPayloadSizeLimit is the maximum size in bytes of an
+	// uncompressed dispatch payload.
+	DispatchPayloadSizeLimit int
+
 	// MinHeartbeatTTL is the minimum time between heartbeats.
 	// This is used as a floor to prevent excessive updates.
 	MinHeartbeatTTL time.Duration
@@ -280,6 +338,13 @@ type Config struct {
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
+	// TLSIdentityPolicies maps identities presented by verified TLS client
+	// certificates (the certificate common name, DNS SANs, or organizational
+	// units) to the set of ACL policies they are granted. This lets
+	// mTLS-only environments authorize API requests without distributing
+	// bearer tokens.
+	TLSIdentityPolicies map[string][]string
+
 	// ReplicationBackoff is how much we backoff when replication errors.
 	// This is a tunable knob for testing primarily.
 	ReplicationBackoff time.Duration
@@ -395,14 +460,19 @@ func DefaultConfig() *Config {
 		EvalGCThreshold:                  1 * time.Hour,
 		JobGCInterval:                    5 * time.Minute,
 		JobGCThreshold:                   4 * time.Hour,
+		JobExpirationInterval:            1 * time.Minute,
 		NodeGCInterval:                   5 * time.Minute,
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
 		DeploymentGCThreshold:            1 * time.Hour,
+		CanaryGCThreshold:                15 * time.Minute,
 		CSIPluginGCInterval:              5 * time.Minute,
 		CSIPluginGCThreshold:             1 * time.Hour,
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
+		ACLTokenExpirationGCThreshold:    1 * time.Hour,
+		LicenseFeatureGracePeriod:        6 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -420,6 +490,8 @@ func DefaultConfig() *Config {
 		TLSConfig:                        &config.TLSConfig{},
 		ReplicationBackoff:               30 * time.Second,
 		SentinelGCInterval:               30 * time.Second,
+		EventBufferSize:                  stream.DefaultEventBufferSize,
+		DispatchPayloadSizeLimit:         DispatchPayloadSizeLimit,
 		LicenseConfig:                    &LicenseConfig{},
 		AutopilotConfig: &structs.AutopilotConfig{
 			CleanupDeadServers:      true,
@@ -50,12 +50,16 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.nodeGC(eval)
 	case structs.CoreJobJobGC:
 		return c.jobGC(eval)
+	case structs.CoreJobJobExpire:
+		return c.jobExpire(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
 	case structs.CoreJobCSIVolumeClaimGC:
 		return c.csiVolumeClaimGC(eval)
 	case structs.CoreJobCSIPluginGC:
 		return c.csiPluginGC(eval)
+	case structs.CoreJobACLTokenExpirationGC:
+		return c.expiredACLTokenGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -118,8 +122,17 @@ OUTER:
 	for i := iter.Next(); i != nil; i = iter.Next() {
 		job := i.(*structs.Job)
 
+		// Honor a per-job GC threshold if one was set, unless the GC was
+		// forced.
+		jobThreshold := oldThreshold
+		if eval.JobID != structs.CoreJobForceGC && job.GCThreshold != 0 {
+			tt := c.srv.fsm.TimeTable()
+			cutoff := time.Now().UTC().Add(-1 * job.GCThreshold)
+			jobThreshold = tt.NearestIndex(cutoff)
+		}
+
 		// Ignore new jobs.
-		if job.CreateIndex > oldThreshold {
+		if job.CreateIndex > jobThreshold {
 			continue
 		}
 
@@ -133,7 +146,7 @@ OUTER:
 		allEvalsGC := true
 		var jobAlloc, jobEval []string
 		for _, eval := range evals {
-			gc, allocs, err := c.gcEval(eval, oldThreshold, true)
+			gc, allocs, err := c.gcEval(eval, jobThreshold, true)
 			if err != nil {
 				continue OUTER
 			}
@@ -224,6 +237,58 @@ func (c *CoreScheduler) partitionJobReap(jobs []*structs.Job, leaderACL string)
 	return requests
 }
 
+// jobExpire is used to stop jobs whose expiration deadline has passed.
+func (c *CoreScheduler) jobExpire(eval *structs.Evaluation) error {
+	// Get all the jobs so we can check for expirations.
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.Jobs(ws)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	req := &structs.JobBatchDeregisterRequest{
+		Jobs: make(map[structs.NamespacedID]*structs.JobDeregisterOptions),
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.config.Region,
+			AuthToken: eval.LeaderACL,
+		},
+	}
+
+	for i := iter.Next(); i != nil; i = iter.Next() {
+		job := i.(*structs.Job)
+
+		// Stopped jobs are either already expired or were stopped by the
+		// user; in both cases there is nothing left to do.
+		if job.Expiration == nil || job.Stopped() {
+			continue
+		}
+
+		deadline, ok := job.ExpirationDeadline()
+		if !ok || now.Before(deadline) {
+			continue
+		}
+
+		jns := structs.NamespacedID{ID: job.ID, Namespace: job.Namespace}
+		req.Jobs[jns] = &structs.JobDeregisterOptions{Purge: job.Expiration.Purge}
+	}
+
+	if len(req.Jobs) == 0 {
+		return nil
+	}
+
+	c.logger.Debug("job expiration", "num_jobs", len(req.Jobs))
+
+	// Call to the leader to deregister the expired jobs.
+	var resp structs.JobBatchDeregisterResponse
+	if err := c.srv.RPC("Job.BatchDeregister", req, &resp); err != nil {
+		c.logger.Error("job expiration failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 // evalGC is used to garbage collect old evaluations
 func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	// Iterate over the evaluations
@@ -547,11 +612,12 @@ func (c *CoreScheduler) deploymentGC(eval *structs.Evaluation) error {
 		return err
 	}
 
-	var oldThreshold uint64
+	var oldThreshold, canaryThreshold uint64
 	if eval.JobID == structs.CoreJobForceGC {
 		// The GC was forced, so set the threshold to its maximum so everything
 		// will GC.
 		oldThreshold = math.MaxUint64
+		canaryThreshold = math.MaxUint64
 		c.logger.Debug("forced deployment GC")
 	} else {
 		// Compute the old threshold limit for GC using the FSM
@@ -560,12 +626,14 @@ func (c *CoreScheduler) deploymentGC(eval *structs.Evaluation) error {
 		tt := c.srv.fsm.TimeTable()
 		cutoff := time.Now().UTC().Add(-1 * c.srv.config.DeploymentGCThreshold)
 		oldThreshold = tt.NearestIndex(cutoff)
+		canaryCutoff := time.Now().UTC().Add(-1 * c.srv.config.CanaryGCThreshold)
+		canaryThreshold = tt.NearestIndex(canaryCutoff)
 		c.logger.Debug("deployment GC scanning before cutoff index",
 			"index", oldThreshold, "deployment_gc_threshold", c.srv.config.DeploymentGCThreshold)
 	}
 
-	// Collect the deployments to GC
-	var gcDeployment []string
+	// Collect the deployments and canary allocations to GC
+	var gcDeployment, gcCanary []string
 
 OUTER:
 	for {
@@ -575,6 +643,20 @@ OUTER:
 		}
 		deploy := raw.(*structs.Deployment)
 
+		// Failed deployments that are too new to be collected entirely may
+		// still have their failed canaries reaped so they stop holding node
+		// resources. The deployment itself is retained so its failure
+		// details remain queryable.
+		if deploy.Status == structs.DeploymentStatusFailed && deploy.ModifyIndex <= canaryThreshold {
+			canaries, err := c.gcDeploymentCanaries(ws, deploy, canaryThreshold)
+			if err != nil {
+				c.logger.Error("failed to get canary allocs for deployment",
+					"deployment_id", deploy.ID, "error", err)
+				continue
+			}
+			gcCanary = append(gcCanary, canaries...)
+		}
+
 		// Ignore non-terminal and new deployments
 		if deploy.Active() || deploy.ModifyIndex > oldThreshold {
 			continue
@@ -599,6 +681,14 @@ OUTER:
 		gcDeployment = append(gcDeployment, deploy.ID)
 	}
 
+	// Reap any failed canaries that are old enough
+	if len(gcCanary) > 0 {
+		c.logger.Debug("deployment GC found eligible canary allocs", "allocs", len(gcCanary))
+		if err := c.evalReap(nil, gcCanary); err != nil {
+			return err
+		}
+	}
+
 	// Fast-path the nothing case
 	if len(gcDeployment) == 0 {
 		return nil
@@ -607,6 +697,36 @@ OUTER:
 	return c.deploymentReap(gcDeployment)
 }
 
+// gcDeploymentCanaries returns the canary allocations of a failed deployment
+// that have themselves failed, are terminal, and are older than the given
+// threshold index. These can be collected even though their deployment is
+// retained since a failed deployment never replaces its canaries.
+func (c *CoreScheduler) gcDeploymentCanaries(ws memdb.WatchSet, deploy *structs.Deployment, thresholdIndex uint64) ([]string, error) {
+	var gcAllocIDs []string
+	for _, state := range deploy.TaskGroups {
+		for _, allocID := range state.PlacedCanaries {
+			alloc, err := c.snap.AllocByID(ws, allocID)
+			if err != nil {
+				return nil, err
+			}
+			if alloc == nil || !alloc.TerminalStatus() || alloc.ModifyIndex > thresholdIndex {
+				continue
+			}
+
+			// Only collect canaries that failed; healthy canaries of a
+			// deployment that failed for other reasons follow the normal
+			// GC path.
+			if alloc.ClientStatus != structs.AllocClientStatusFailed && !alloc.DeploymentStatus.IsUnhealthy() {
+				continue
+			}
+
+			gcAllocIDs = append(gcAllocIDs, alloc.ID)
+		}
+	}
+
+	return gcAllocIDs, nil
+}
+
 // deploymentReap contacts the leader and issues a reap on the passed
 // deployments.
 func (c *CoreScheduler) deploymentReap(deployments []string) error {
@@ -869,3 +989,73 @@ func (c *CoreScheduler) csiPluginGC(eval *structs.Evaluation) error {
 	}
 	return nil
 }
+
+// expiredACLTokenGC is used to garbage collect ACL tokens that have expired.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation) error {
+	// If ACLs are not enabled there are no tokens to GC.
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+
+	// Tokens must be expired beyond the threshold before they are eligible
+	// for GC. This gives operators some time to observe that a token has
+	// expired before it disappears from listings.
+	cutoff := time.Now().UTC().Add(-1 * c.srv.config.ACLTokenExpirationGCThreshold)
+
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.ACLTokens(ws)
+	if err != nil {
+		return err
+	}
+
+	// Global tokens are replicated from the authoritative region, so only
+	// the authoritative region can delete them.
+	authoritative := c.srv.config.AuthoritativeRegion == c.srv.config.Region
+
+	var gcLocal, gcGlobal []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if !token.IsExpired(cutoff) {
+			continue
+		}
+		if token.Global {
+			if authoritative {
+				gcGlobal = append(gcGlobal, token.AccessorID)
+			}
+		} else {
+			gcLocal = append(gcLocal, token.AccessorID)
+		}
+	}
+
+	if len(gcLocal) == 0 && len(gcGlobal) == 0 {
+		return nil
+	}
+	c.logger.Debug("expired ACL token GC found eligible tokens",
+		"local", len(gcLocal), "global", len(gcGlobal))
+
+	// Local and global tokens must be deleted in separate batches since the
+	// RPC does not accept mixed requests.
+	if err := c.expiredACLTokenReap(eval, gcLocal); err != nil {
+		return err
+	}
+	return c.expiredACLTokenReap(eval, gcGlobal)
+}
+
+// expiredACLTokenReap deletes the given expired tokens in batches.
+func (c *CoreScheduler) expiredACLTokenReap(eval *structs.Evaluation, accessorIDs []string) error {
+	for _, ids := range partitionAll(maxIdsPerReap, accessorIDs) {
+		req := structs.ACLTokenDeleteRequest{
+			AccessorIDs: ids,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: eval.LeaderACL,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("ACL.DeleteTokens", &req, &resp); err != nil {
+			c.logger.Error("expired ACL token reap failed", "error", err)
+			return err
+		}
+	}
+	return nil
+}
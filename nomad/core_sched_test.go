@@ -7,6 +7,7 @@ import (
 
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -1667,6 +1668,165 @@ func TestCoreScheduler_JobGC_Parameterized(t *testing.T) {
 }
 
 // This test ensures periodic jobs don't get GCd until they are stopped
+func TestCoreScheduler_JobGC_PerJobThreshold(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert two dead batch jobs, one that opts into faster collection and
+	// one that retains its history for longer than the server default.
+	state := s1.fsm.State()
+	fast := mock.Job()
+	fast.Type = structs.JobTypeBatch
+	fast.Status = structs.JobStatusDead
+	fast.GCThreshold = time.Hour
+	if err := state.UpsertJob(1000, fast); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	slow := mock.Job()
+	slow.Type = structs.JobTypeBatch
+	slow.Status = structs.JobStatusDead
+	slow.GCThreshold = 48 * time.Hour
+	if err := state.UpsertJob(1001, slow); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Insert terminal evals for both jobs.
+	evalFast := mock.Eval()
+	evalFast.JobID = fast.ID
+	evalFast.Status = structs.EvalStatusComplete
+
+	evalSlow := mock.Eval()
+	evalSlow.JobID = slow.ID
+	evalSlow.Status = structs.EvalStatusComplete
+	if err := state.UpsertEvals(1002, []*structs.Evaluation{evalFast, evalSlow}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Witness an index two hours in the past; old enough for the fast job's
+	// threshold but too recent for the slow job's.
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-2*time.Hour))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobJobGC, 2000)
+	if err := core.Process(gc); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The fast job should be gone
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, fast.Namespace, fast.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// The slow job should remain
+	out, err = state.JobByID(ws, slow.Namespace, slow.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil {
+		t.Fatalf("bad: %v", out)
+	}
+	outE, err := state.EvalByID(ws, evalSlow.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if outE == nil {
+		t.Fatalf("bad: %v", outE)
+	}
+}
+
+func TestCoreScheduler_JobExpire(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert a job whose TTL has elapsed, one that expired with purge
+	// enabled, and one that has not expired yet.
+	state := s1.fsm.State()
+	expired := mock.Job()
+	expired.SubmitTime = time.Now().Add(-2 * time.Hour).UnixNano()
+	expired.Expiration = &structs.JobExpiration{TTL: time.Hour}
+	if err := state.UpsertJob(1000, expired); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	purged := mock.Job()
+	purged.SubmitTime = time.Now().Add(-2 * time.Hour).UnixNano()
+	purged.Expiration = &structs.JobExpiration{TTL: time.Hour, Purge: true}
+	if err := state.UpsertJob(1001, purged); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	fresh := mock.Job()
+	fresh.SubmitTime = time.Now().UnixNano()
+	fresh.Expiration = &structs.JobExpiration{TTL: 24 * time.Hour}
+	if err := state.UpsertJob(1002, fresh); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the expiration
+	eval := s1.coreJobEval(structs.CoreJobJobExpire, 2000)
+	if err := core.Process(eval); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// The expired job should be stopped but not removed
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, expired.Namespace, expired.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil || !out.Stopped() {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// The purged job should be gone
+	out, err = state.JobByID(ws, purged.Namespace, purged.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("bad: %v", out)
+	}
+
+	// The fresh job should be untouched
+	out, err = state.JobByID(ws, fresh.Namespace, fresh.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil || out.Stopped() {
+		t.Fatalf("bad: %v", out)
+	}
+}
+
 func TestCoreScheduler_JobGC_Periodic(t *testing.T) {
 	t.Parallel()
 
@@ -1792,6 +1952,67 @@ func TestCoreScheduler_DeploymentGC(t *testing.T) {
 	assert.NotNil(out3, "Terminal Deployment With Allocs")
 }
 
+func TestCoreScheduler_DeploymentGC_FailedCanaries(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert a failed deployment with a failed canary and a running alloc
+	state := s1.fsm.State()
+	d := mock.Deployment()
+	d.Status = structs.DeploymentStatusFailed
+
+	canary := mock.Alloc()
+	canary.JobID = d.JobID
+	canary.DeploymentID = d.ID
+	canary.ClientStatus = structs.AllocClientStatusFailed
+	canary.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(false),
+		Canary:  true,
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{canary.ID}
+
+	a := mock.Alloc()
+	a.JobID = d.JobID
+	a.DeploymentID = d.ID
+	a.ClientStatus = structs.AllocClientStatusRunning
+
+	assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+	assert.Nil(state.UpsertAllocs(1001, []*structs.Allocation{canary, a}), "UpsertAllocs")
+
+	// Update the time table so only the canary threshold has passed
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.CanaryGCThreshold))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	assert.Nil(err, "Snapshot")
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobDeploymentGC, 2000)
+	assert.Nil(core.Process(gc), "Process GC")
+
+	// The canary should be gone but the deployment and the running alloc
+	// should remain
+	ws := memdb.NewWatchSet()
+	outCanary, err := state.AllocByID(ws, canary.ID)
+	assert.Nil(err, "AllocByID")
+	assert.Nil(outCanary, "Failed Canary")
+	outAlloc, err := state.AllocByID(ws, a.ID)
+	assert.Nil(err, "AllocByID")
+	assert.NotNil(outAlloc, "Running Alloc")
+	outDeploy, err := state.DeploymentByID(ws, d.ID)
+	assert.Nil(err, "DeploymentByID")
+	assert.NotNil(outDeploy, "Failed Deployment")
+}
+
 func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	t.Parallel()
 	for _, withAcl := range []bool{false, true} {
@@ -2455,3 +2676,42 @@ func TestCoreScheduler_FailLoop(t *testing.T) {
 			out.TriggeredBy)
 	}
 }
+
+func TestCoreScheduler_ACLTokenExpirationGC(t *testing.T) {
+	t.Parallel()
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Insert a token expired beyond the threshold, a freshly expired token,
+	// and a token that never expires
+	state := s1.fsm.State()
+	tk1, tk2, tk3 := mock.ACLToken(), mock.ACLToken(), mock.ACLToken()
+	expired := time.Now().UTC().Add(-2 * s1.config.ACLTokenExpirationGCThreshold)
+	tk1.ExpirationTime = &expired
+	fresh := time.Now().UTC().Add(-1 * time.Second)
+	tk2.ExpirationTime = &fresh
+	assert.Nil(state.UpsertACLTokens(1000, []*structs.ACLToken{tk1, tk2, tk3}), "UpsertACLTokens")
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	assert.Nil(err, "Snapshot")
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobACLTokenExpirationGC, 2000)
+	assert.Nil(core.Process(gc), "Process GC")
+
+	// Only the token expired beyond the threshold should be gone
+	out, err := state.ACLTokenByAccessorID(nil, tk1.AccessorID)
+	assert.Nil(err, "ACLTokenByAccessorID")
+	assert.Nil(out, "Expired Token")
+	out2, err := state.ACLTokenByAccessorID(nil, tk2.AccessorID)
+	assert.Nil(err, "ACLTokenByAccessorID")
+	assert.NotNil(out2, "Freshly Expired Token")
+	out3, err := state.ACLTokenByAccessorID(nil, tk3.AccessorID)
+	assert.Nil(err, "ACLTokenByAccessorID")
+	assert.NotNil(out3, "Unexpiring Token")
+}
@@ -293,6 +293,15 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 		}
 	}
 
+	// Enforce any Sentinel policies scoped to volume registration
+	policyWarnings, err := v.enforceVolumeRegister(args)
+	if err != nil {
+		return err
+	}
+	if policyWarnings != nil {
+		v.logger.Warn("volume registration policy warning", "warnings", policyWarnings)
+	}
+
 	resp, index, err := v.srv.raftApply(structs.CSIVolumeRegisterRequestType, args)
 	if err != nil {
 		v.logger.Error("csi raft apply failed", "error", err, "method", "register")
@@ -0,0 +1,13 @@
+// +build !ent
+
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// enforceVolumeRegister is used to check any Sentinel policies for the
+// volume-register scope
+func (v *CSIVolume) enforceVolumeRegister(args *structs.CSIVolumeRegisterRequest) (error, error) {
+	return nil, nil
+}
@@ -859,6 +859,127 @@ func TestWatcher_FailDeployment_Running(t *testing.T) {
 	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
 }
 
+// Test running a pending multiregion deployment
+func TestWatcher_RunDeployment(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a multiregion job and a pending deployment
+	j := mock.MultiregionJob()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.IsMultiregion = true
+	d.Status = structs.DeploymentStatusPending
+	d.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	// require that we get a call to UpsertDeploymentStatusUpdate with an
+	// evaluation so the scheduler starts making placements
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusRunning,
+		StatusDescription: structs.DeploymentStatusDescriptionRunning,
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Call RunDeployment
+	req := &structs.DeploymentRunRequest{
+		DeploymentID: d.ID,
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := w.RunDeployment(req, &resp)
+	require.Nil(err, "RunDeployment")
+
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
+
+// Test unblocking a blocked multiregion deployment
+func TestWatcher_UnblockDeployment(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a multiregion job and a blocked deployment
+	j := mock.MultiregionJob()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.IsMultiregion = true
+	d.Status = structs.DeploymentStatusBlocked
+	d.StatusDescription = structs.DeploymentStatusDescriptionBlocked
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	// require that we get a call to UpsertDeploymentStatusUpdate
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusSuccessful,
+		StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Call UnblockDeployment
+	req := &structs.DeploymentUnblockRequest{
+		DeploymentID: d.ID,
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := w.UnblockDeployment(req, &resp)
+	require.Nil(err, "UnblockDeployment")
+
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
+
+// Test cancelling a multiregion deployment from a peer region
+func TestWatcher_CancelDeployment(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a multiregion job and a running deployment
+	j := mock.MultiregionJob()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.IsMultiregion = true
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	// require that we get a call to UpsertDeploymentStatusUpdate
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusCancelled,
+		StatusDescription: structs.DeploymentStatusDescriptionFailedByPeer,
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Call CancelDeployment
+	req := &structs.DeploymentCancelRequest{
+		DeploymentID: d.ID,
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := w.CancelDeployment(req, &resp)
+	require.Nil(err, "CancelDeployment")
+
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
+
 // Tests that the watcher properly watches for allocation changes and takes the
 // proper actions
 func TestDeploymentWatcher_Watch_NoProgressDeadline(t *testing.T) {
@@ -4,24 +4,292 @@ package deploymentwatcher
 
 import "github.com/hashicorp/nomad/nomad/structs"
 
-// DeploymentRPC and JobRPC hold methods for interacting with peer regions
-// in enterprise edition.
-type DeploymentRPC interface{}
-type JobRPC interface{}
+// DeploymentRPC holds the Deployment RPC methods the deployment watcher uses
+// to coordinate a multiregion rollout with peer regions. Requests to peers
+// are issued without an ACL token, so multiregion deployments in federated
+// clusters with ACLs enabled require the anonymous policy to allow the
+// submit-job capability.
+type DeploymentRPC interface {
+	Run(args *structs.DeploymentRunRequest, reply *structs.DeploymentUpdateResponse) error
+	Unblock(args *structs.DeploymentUnblockRequest, reply *structs.DeploymentUpdateResponse) error
+	Cancel(args *structs.DeploymentCancelRequest, reply *structs.DeploymentUpdateResponse) error
+}
+
+// JobRPC holds the Job RPC methods the deployment watcher uses to inspect
+// the state of a multiregion job in peer regions.
+type JobRPC interface {
+	LatestDeployment(args *structs.JobSpecificRequest, reply *structs.SingleDeploymentResponse) error
+}
 
+// nextRegion advances a multiregion rollout in response to a change in the
+// local deployment's status. When the local region finishes its own rollout
+// and becomes blocked, the next pending region in the job's rollout order is
+// started; once every region has finished, the regions are unblocked. When
+// the local deployment fails, the strategy's on_failure mode picks which
+// peer regions fail along with it.
+//
+// Peer regions are coordinated on a best effort basis: errors reaching a
+// peer are logged and retried the next time the local deployment is updated
+// rather than failing the local deployment.
 func (w *deploymentWatcher) nextRegion(status string) error {
+	d := w.getDeployment()
+	if d == nil || !d.IsMultiregion || !w.j.IsMultiregion() {
+		return nil
+	}
+
+	switch status {
+	case structs.DeploymentStatusBlocked, structs.DeploymentStatusUnblocking:
+		w.advanceRegions()
+	case structs.DeploymentStatusFailed:
+		w.failPeerRegions()
+	}
+
 	return nil
 }
 
+// peerDeployments returns the latest deployment for the job in every peer
+// region of the rollout, keyed by region name. Regions whose deployment
+// cannot be found yet map to nil.
+func (w *deploymentWatcher) peerDeployments() (map[string]*structs.Deployment, error) {
+	deployments := make(map[string]*structs.Deployment, len(w.j.Multiregion.Regions))
+	for _, region := range w.j.Multiregion.Regions {
+		if region.Name == w.j.Region {
+			continue
+		}
+
+		args := &structs.JobSpecificRequest{
+			JobID: w.j.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    region.Name,
+				Namespace: w.j.Namespace,
+			},
+		}
+		var reply structs.SingleDeploymentResponse
+		if err := w.LatestDeployment(args, &reply); err != nil {
+			return nil, err
+		}
+		deployments[region.Name] = reply.Deployment
+	}
+
+	return deployments, nil
+}
+
+// advanceRegions starts the next pending regions of the rollout, keeping at
+// most max_parallel regions deploying at once, and unblocks every region
+// once all of them have finished.
+func (w *deploymentWatcher) advanceRegions() {
+	deployments, err := w.peerDeployments()
+	if err != nil {
+		w.logger.Error("failed to query peer regions for multiregion deployment", "error", err)
+		return
+	}
+	deployments[w.j.Region] = w.getDeployment()
+
+	maxParallel := 0
+	if w.j.Multiregion.Strategy != nil {
+		maxParallel = w.j.Multiregion.Strategy.MaxParallel
+	}
+
+	// Count the regions that are currently deploying
+	deploying := 0
+	for _, d := range deployments {
+		if d == nil {
+			continue
+		}
+		switch d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			deploying++
+		}
+	}
+
+	// Start pending regions, in rollout order, until max_parallel regions
+	// are deploying.
+	for _, region := range w.j.Multiregion.Regions {
+		if maxParallel != 0 && deploying >= maxParallel {
+			break
+		}
+
+		d := deployments[region.Name]
+		if d == nil || d.Status != structs.DeploymentStatusPending {
+			continue
+		}
+
+		run := &structs.DeploymentRunRequest{
+			DeploymentID: d.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: w.j.Namespace,
+			},
+		}
+		var resp structs.DeploymentUpdateResponse
+		if err := w.Run(run, &resp); err != nil {
+			w.logger.Error("failed to run deployment in peer region",
+				"region", region.Name, "error", err)
+			return
+		}
+		deploying++
+	}
+
+	// Unblocking has to wait until every region has finished its own
+	// rollout.
+	for _, region := range w.j.Multiregion.Regions {
+		d := deployments[region.Name]
+		if d == nil {
+			return
+		}
+		switch d.Status {
+		case structs.DeploymentStatusBlocked,
+			structs.DeploymentStatusUnblocking,
+			structs.DeploymentStatusSuccessful:
+		default:
+			return
+		}
+	}
+
+	w.unblockRegions(deployments)
+}
+
+// unblockRegions marks a finished rollout as successful in every region.
+// The local deployment is flagged as unblocking first so that a pass
+// interrupted part way through is retried on the next deployment update.
+func (w *deploymentWatcher) unblockRegions(deployments map[string]*structs.Deployment) {
+	if w.getStatus() == structs.DeploymentStatusBlocked {
+		update := w.getDeploymentStatusUpdate(
+			structs.DeploymentStatusUnblocking, structs.DeploymentStatusDescriptionUnblocking)
+		if _, err := w.upsertDeploymentStatusUpdate(update, nil, nil); err != nil {
+			w.logger.Error("failed to update deployment status", "error", err)
+			return
+		}
+	}
+
+	for _, region := range w.j.Multiregion.Regions {
+		d := deployments[region.Name]
+		if region.Name == w.j.Region || d == nil || d.Status != structs.DeploymentStatusBlocked {
+			continue
+		}
+
+		unblock := &structs.DeploymentUnblockRequest{
+			DeploymentID: d.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: w.j.Namespace,
+			},
+		}
+		var resp structs.DeploymentUpdateResponse
+		if err := w.Unblock(unblock, &resp); err != nil {
+			w.logger.Error("failed to unblock deployment in peer region",
+				"region", region.Name, "error", err)
+			return
+		}
+	}
+
+	update := w.getDeploymentStatusUpdate(
+		structs.DeploymentStatusSuccessful, structs.DeploymentStatusDescriptionSuccessful)
+	if _, err := w.upsertDeploymentStatusUpdate(update, nil, nil); err != nil {
+		w.logger.Error("failed to update deployment status", "error", err)
+	}
+}
+
+// failPeerRegions applies the multiregion strategy's on_failure mode after
+// the local deployment has failed. With fail_local nothing else happens;
+// with fail_all every peer region is cancelled; by default only the peer
+// regions that have not yet finished their rollout are cancelled.
+func (w *deploymentWatcher) failPeerRegions() {
+	onFailure := ""
+	if w.j.Multiregion.Strategy != nil {
+		onFailure = w.j.Multiregion.Strategy.OnFailure
+	}
+	if onFailure == structs.MultiregionStrategyOnFailureLocal {
+		return
+	}
+
+	deployments, err := w.peerDeployments()
+	if err != nil {
+		w.logger.Error("failed to query peer regions for multiregion deployment", "error", err)
+		return
+	}
+
+	for _, region := range w.j.Multiregion.Regions {
+		if region.Name == w.j.Region {
+			continue
+		}
+
+		d := deployments[region.Name]
+		if d == nil {
+			continue
+		}
+		switch d.Status {
+		case structs.DeploymentStatusPending,
+			structs.DeploymentStatusRunning,
+			structs.DeploymentStatusPaused:
+		case structs.DeploymentStatusBlocked, structs.DeploymentStatusUnblocking:
+			if onFailure != structs.MultiregionStrategyOnFailureAll {
+				continue
+			}
+		default:
+			// Already terminal
+			continue
+		}
+
+		cancel := &structs.DeploymentCancelRequest{
+			DeploymentID: d.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: w.j.Namespace,
+			},
+		}
+		var resp structs.DeploymentUpdateResponse
+		if err := w.Cancel(cancel, &resp); err != nil {
+			w.logger.Error("failed to cancel deployment in peer region",
+				"region", region.Name, "error", err)
+		}
+	}
+}
+
 // RunDeployment is used to run a pending multiregion deployment.  In
 // single-region deployments, the pending state is unused.
 func (w *deploymentWatcher) RunDeployment(req *structs.DeploymentRunRequest, resp *structs.DeploymentUpdateResponse) error {
+	// Only pending deployments can be run, which makes repeated requests
+	// from peer regions safe.
+	if w.getStatus() != structs.DeploymentStatusPending {
+		return nil
+	}
+
+	// An evaluation is needed so that the scheduler starts making
+	// placements for the formerly pending deployment.
+	update := w.getDeploymentStatusUpdate(
+		structs.DeploymentStatusRunning, structs.DeploymentStatusDescriptionRunning)
+	eval := w.getEval()
+	i, err := w.upsertDeploymentStatusUpdate(update, eval, nil)
+	if err != nil {
+		return err
+	}
+
+	resp.EvalID = eval.ID
+	resp.EvalCreateIndex = i
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
 	return nil
 }
 
 // UnblockDeployment is used to unblock a multiregion deployment.  In
 // single-region deployments, the blocked state is unused.
 func (w *deploymentWatcher) UnblockDeployment(req *structs.DeploymentUnblockRequest, resp *structs.DeploymentUpdateResponse) error {
+	// Only blocked deployments can be unblocked, which makes repeated
+	// requests from peer regions safe.
+	if w.getStatus() != structs.DeploymentStatusBlocked {
+		return nil
+	}
+
+	update := w.getDeploymentStatusUpdate(
+		structs.DeploymentStatusSuccessful, structs.DeploymentStatusDescriptionSuccessful)
+	i, err := w.upsertDeploymentStatusUpdate(update, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
 	return nil
 }
 
@@ -29,5 +297,17 @@ func (w *deploymentWatcher) UnblockDeployment(req *structs.DeploymentUnblockRequ
 // single-region deployments, the deploymentwatcher has sole responsibility to
 // cancel deployments so this RPC is never used.
 func (w *deploymentWatcher) CancelDeployment(req *structs.DeploymentCancelRequest, resp *structs.DeploymentUpdateResponse) error {
+	update := w.getDeploymentStatusUpdate(
+		structs.DeploymentStatusCancelled, structs.DeploymentStatusDescriptionFailedByPeer)
+	eval := w.getEval()
+	i, err := w.upsertDeploymentStatusUpdate(update, eval, nil)
+	if err != nil {
+		return err
+	}
+
+	resp.EvalID = eval.ID
+	resp.EvalCreateIndex = i
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
 	return nil
 }
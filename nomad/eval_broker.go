@@ -80,6 +80,11 @@ type EvalBroker struct {
 	// timeWait has evaluations that are waiting for time to elapse
 	timeWait map[string]*time.Timer
 
+	// nackReasons tracks the most recent Nack reason by evalID so the
+	// failing scheduler error can be surfaced when an evaluation reaches
+	// the delivery limit
+	nackReasons map[string]string
+
 	// delayedEvalCancelFunc is used to stop the long running go routine
 	// that processes delayed evaluations
 	delayedEvalCancelFunc context.CancelFunc
@@ -101,6 +106,12 @@ type EvalBroker struct {
 	// compounding after the first Nack.
 	subsequentNackDelay time.Duration
 
+	// namespaceWeights optionally weights dequeuing per namespace so
+	// evaluations are dequeued proportionally across namespaces instead of
+	// strictly by priority and age. Namespaces without an entry default to
+	// a weight of 1. When empty, weighted dequeuing is disabled.
+	namespaceWeights map[string]float64
+
 	l sync.RWMutex
 }
 
@@ -139,6 +150,7 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
+		nackReasons:          make(map[string]string),
 		timeWait:             make(map[string]*time.Timer),
 		initialNackDelay:     initialNackDelay,
 		subsequentNackDelay:  subsequentNackDelay,
@@ -424,9 +436,14 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
 	// Get the pending queue
 	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
+
+	var eval *structs.Evaluation
+	if len(b.namespaceWeights) > 0 {
+		eval = b.popWeighted(&pending)
+	} else {
+		eval = heap.Pop(&pending).(*structs.Evaluation)
+	}
 	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -456,6 +473,57 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	return eval, token, nil
 }
 
+// SetNamespaceWeights is used to update the per-namespace admission weights.
+// Passing an empty or nil map disables weighted dequeuing.
+func (b *EvalBroker) SetNamespaceWeights(weights map[string]float64) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	b.namespaceWeights = weights
+}
+
+// namespaceWeight returns the admission weight for a namespace, defaulting
+// to 1 when the namespace has no configured weight.
+func (b *EvalBroker) namespaceWeight(namespace string) float64 {
+	if w, ok := b.namespaceWeights[namespace]; ok && w > 0 {
+		return w
+	}
+	return 1.0
+}
+
+// popWeighted removes and returns a pending evaluation using the configured
+// namespace admission weights. The best pending evaluation of each namespace
+// is found and one is chosen with probability proportional to its
+// namespace's weight, preventing a single namespace that floods the queue
+// from starving the others.
+func (b *EvalBroker) popWeighted(pending *PendingEvaluations) *structs.Evaluation {
+	// Find the best evaluation for each namespace with pending work
+	best := make(map[string]int)
+	for i, eval := range *pending {
+		j, ok := best[eval.Namespace]
+		if !ok || evalLess(eval, (*pending)[j]) {
+			best[eval.Namespace] = i
+		}
+	}
+
+	// Sum the weights of the namespaces with pending work
+	var total float64
+	for namespace := range best {
+		total += b.namespaceWeight(namespace)
+	}
+
+	// Choose a namespace proportionally to its weight
+	r := rand.Float64() * total
+	for namespace, i := range best {
+		r -= b.namespaceWeight(namespace)
+		if r <= 0 {
+			return heap.Remove(pending, i).(*structs.Evaluation)
+		}
+	}
+
+	// Guard against floating point rounding; fall back to the overall best
+	return heap.Pop(pending).(*structs.Evaluation)
+}
+
 // waitForSchedulers is used to wait for work on any of the scheduler or until a timeout.
 // Returns if there is work waiting potentially.
 func (b *EvalBroker) waitForSchedulers(schedulers []string, timeoutCh <-chan time.Time) bool {
@@ -536,6 +604,9 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	// we requeue it or it isn't and we want to remove it.
 	defer delete(b.requeue, token)
 
+	// The evaluation has been delivered so any recorded failure is stale
+	defer delete(b.nackReasons, evalID)
+
 	// Lookup the unack'd eval
 	unack, ok := b.unack[evalID]
 	if !ok {
@@ -553,12 +624,14 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 
 	// Update the stats
 	b.stats.TotalUnacked -= 1
+	b.stats.TotalAcks += 1
 	queue := unack.Eval.Type
 	if b.evals[evalID] > b.deliveryLimit {
 		queue = failedQueue
 	}
 	bySched := b.stats.ByScheduler[queue]
 	bySched.Unacked -= 1
+	bySched.Acks += 1
 
 	// Cleanup
 	delete(b.unack, evalID)
@@ -592,6 +665,26 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 }
 
 // Nack is used to negatively acknowledge handling an evaluation
+// NackWithReason is like Nack but records the failure reason so it can be
+// surfaced if the evaluation reaches the delivery limit.
+func (b *EvalBroker) NackWithReason(evalID, token, reason string) error {
+	if err := b.Nack(evalID, token); err != nil {
+		return err
+	}
+
+	b.l.Lock()
+	b.nackReasons[evalID] = reason
+	b.l.Unlock()
+	return nil
+}
+
+// NackReason returns the most recent Nack reason for an evaluation, if any.
+func (b *EvalBroker) NackReason(evalID string) string {
+	b.l.RLock()
+	defer b.l.RUnlock()
+	return b.nackReasons[evalID]
+}
+
 func (b *EvalBroker) Nack(evalID, token string) error {
 	b.l.Lock()
 	defer b.l.Unlock()
@@ -617,8 +710,10 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 
 	// Update the stats
 	b.stats.TotalUnacked -= 1
+	b.stats.TotalNacks += 1
 	bySched := b.stats.ByScheduler[unack.Eval.Type]
 	bySched.Unacked -= 1
+	bySched.Nacks += 1
 
 	// Check if we've hit the delivery limit, and re-enqueue
 	// in the failedQueue
@@ -719,6 +814,8 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
+	b.stats.TotalAcks = 0
+	b.stats.TotalNacks = 0
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
@@ -813,9 +910,19 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	stats.TotalAcks = b.stats.TotalAcks
+	stats.TotalNacks = b.stats.TotalNacks
+	now := time.Now()
 	for sched, subStat := range b.stats.ByScheduler {
 		subStatCopy := new(SchedulerStats)
 		*subStatCopy = *subStat
+
+		// Compute the age of the oldest ready evaluation for the queue.
+		for _, eval := range b.ready[sched] {
+			if age := now.Sub(time.Unix(0, eval.CreateTime)); age > subStatCopy.OldestEvalAge {
+				subStatCopy.OldestEvalAge = age
+			}
+		}
 		stats.ByScheduler[sched] = subStatCopy
 	}
 	return stats
@@ -831,9 +938,13 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "broker", "total_unacked"}, float32(stats.TotalUnacked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_waiting"}, float32(stats.TotalWaiting))
+			metrics.SetGauge([]string{"nomad", "broker", "total_acks"}, float32(stats.TotalAcks))
+			metrics.SetGauge([]string{"nomad", "broker", "total_nacks"}, float32(stats.TotalNacks))
 			for sched, schedStats := range stats.ByScheduler {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
+				metrics.SetGauge([]string{"nomad", "broker", sched, "nacks"}, float32(schedStats.Nacks))
+				metrics.SetGauge([]string{"nomad", "broker", sched, "oldest_eval_age"}, float32(schedStats.OldestEvalAge))
 			}
 
 		case <-stopCh:
@@ -848,13 +959,25 @@ type BrokerStats struct {
 	TotalUnacked int
 	TotalBlocked int
 	TotalWaiting int
-	ByScheduler  map[string]*SchedulerStats
+
+	// TotalAcks and TotalNacks are cumulative delivery counters since the
+	// broker was last enabled.
+	TotalAcks  int
+	TotalNacks int
+
+	ByScheduler map[string]*SchedulerStats
 }
 
 // SchedulerStats returns the stats per scheduler
 type SchedulerStats struct {
 	Ready   int
 	Unacked int
+	Acks    int
+	Nacks   int
+
+	// OldestEvalAge is the age of the oldest evaluation waiting in the
+	// ready queue. It is only populated by Stats.
+	OldestEvalAge time.Duration
 }
 
 // Len is for the sorting interface
@@ -866,10 +989,16 @@ func (p PendingEvaluations) Len() int {
 // so that the "min" in the min-heap is the element with the
 // highest priority
 func (p PendingEvaluations) Less(i, j int) bool {
-	if p[i].JobID != p[j].JobID && p[i].Priority != p[j].Priority {
-		return !(p[i].Priority < p[j].Priority)
+	return evalLess(p[i], p[j])
+}
+
+// evalLess returns whether evaluation a should be dequeued before b,
+// matching the ordering of the PendingEvaluations heap.
+func evalLess(a, b *structs.Evaluation) bool {
+	if a.JobID != b.JobID && a.Priority != b.Priority {
+		return !(a.Priority < b.Priority)
 	}
-	return p[i].CreateIndex < p[j].CreateIndex
+	return a.CreateIndex < b.CreateIndex
 }
 
 // Swap is for the sorting interface
@@ -1498,3 +1498,53 @@ func TestEvalBroker_NamespacedJobs(t *testing.T) {
 	require.Equal(1, len(b.blocked))
 
 }
+
+func TestEvalBroker_NamespaceWeights(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Heavily weight the "weighted" namespace so the first dequeue is
+	// near-deterministic despite the weighted random selection.
+	b.SetNamespaceWeights(map[string]float64{
+		"weighted": 1e9,
+	})
+
+	// Enqueue higher priority evals in the default namespace
+	for i := 0; i < 10; i++ {
+		eval := mock.Eval()
+		eval.Priority = 80
+		b.Enqueue(eval)
+	}
+
+	// Enqueue a single lower priority eval in the weighted namespace
+	weighted := mock.Eval()
+	weighted.Priority = 10
+	weighted.Namespace = "weighted"
+	b.Enqueue(weighted)
+
+	require := require.New(t)
+
+	// The weighted namespace should win the first dequeue despite its
+	// lower priority
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.Nil(err)
+	require.Equal(weighted.ID, out.ID)
+	require.Nil(b.Ack(out.ID, token))
+
+	// The remaining evals should all drain
+	for i := 0; i < 10; i++ {
+		out, token, err := b.Dequeue(defaultSched, time.Second)
+		require.Nil(err)
+		require.NotNil(out)
+		require.Equal(structs.DefaultNamespace, out.Namespace)
+		require.Nil(b.Ack(out.ID, token))
+	}
+
+	stats := b.Stats()
+	require.Equal(0, stats.TotalReady)
+	require.Equal(0, stats.TotalUnacked)
+
+	// Clearing the weights restores strict priority ordering
+	b.SetNamespaceWeights(nil)
+}
@@ -187,13 +187,115 @@ func (e *Eval) Nack(args *structs.EvalAckRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "eval", "nack"}, time.Now())
 
-	// Nack the EvalID
+	// Nack the EvalID, recording the failure reason if one was provided
+	if args.Error != "" {
+		if err := e.srv.evalBroker.NackWithReason(args.EvalID, args.Token, args.Error); err != nil {
+			return err
+		}
+		return nil
+	}
 	if err := e.srv.evalBroker.Nack(args.EvalID, args.Token); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Retry is used by operators to re-enqueue an evaluation that failed by
+// reaching the delivery limit.
+func (e *Eval) Retry(args *structs.EvalRetryRequest, reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Eval.Retry", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "retry"}, time.Now())
+
+	// This action requires operator write access.
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	eval, err := e.retryableEval(args.EvalID)
+	if err != nil {
+		return err
+	}
+
+	// Re-enqueue by moving the evaluation back to pending via Raft
+	updateEval := eval.Copy()
+	updateEval.Status = structs.EvalStatusPending
+	updateEval.StatusDescription = "operator requested retry"
+	updateEval.Wait = 0
+	updateEval.WaitUntil = time.Time{}
+	updateEval.UpdateModifyTime()
+
+	update := &structs.EvalUpdateRequest{
+		Evals:        []*structs.Evaluation{updateEval},
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+	_, index, err := e.srv.raftApply(structs.EvalUpdateRequestType, update)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Discard is used by operators to cancel an evaluation that failed by
+// reaching the delivery limit rather than retrying it.
+func (e *Eval) Discard(args *structs.EvalRetryRequest, reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Eval.Discard", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "discard"}, time.Now())
+
+	// This action requires operator write access.
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	eval, err := e.retryableEval(args.EvalID)
+	if err != nil {
+		return err
+	}
+
+	updateEval := eval.Copy()
+	updateEval.Status = structs.EvalStatusCancelled
+	updateEval.StatusDescription = "operator discarded failed evaluation"
+	updateEval.UpdateModifyTime()
+
+	update := &structs.EvalUpdateRequest{
+		Evals:        []*structs.Evaluation{updateEval},
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+	_, index, err := e.srv.raftApply(structs.EvalUpdateRequestType, update)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// retryableEval looks up an evaluation and ensures it is in a state that an
+// operator may retry or discard.
+func (e *Eval) retryableEval(evalID string) (*structs.Evaluation, error) {
+	ws := memdb.NewWatchSet()
+	eval, err := e.srv.fsm.State().EvalByID(ws, evalID)
+	if err != nil {
+		return nil, err
+	}
+	if eval == nil {
+		return nil, fmt.Errorf("evaluation not found")
+	}
+	if eval.Status != structs.EvalStatusFailed {
+		return nil, fmt.Errorf("evaluation %q has status %q; only failed evaluations may be retried or discarded", eval.ID, eval.Status)
+	}
+	return eval, nil
+}
+
 // Update is used to perform an update of an Eval if it is outstanding.
 func (e *Eval) Update(args *structs.EvalUpdateRequest,
 	reply *structs.GenericResponse) error {
@@ -338,10 +440,13 @@ func (e *Eval) List(args *structs.EvalListRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "eval", "list"}, time.Now())
 
+	namespace := args.RequestNamespace()
+
 	// Check for read-job permissions
-	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -353,24 +458,42 @@ func (e *Eval) List(args *structs.EvalListRequest,
 			// Scan all the evaluations
 			var err error
 			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.EvalsByIDPrefix(ws, args.RequestNamespace(), prefix)
+			if namespace == structs.AllNamespacesSentinel {
+				allowedNSes, err := allowedNSes(aclObj, state)
+				if err != nil {
+					return err
+				}
+				iter, err = state.EvalsByIDPrefixInNSes(ws, allowedNSes, args.QueryOptions.Prefix)
+				if err != nil {
+					return err
+				}
+			} else if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.EvalsByIDPrefix(ws, namespace, prefix)
 			} else {
-				iter, err = state.EvalsByNamespace(ws, args.RequestNamespace())
+				iter, err = state.EvalsByNamespace(ws, namespace)
 			}
 			if err != nil {
 				return err
 			}
 
 			var evals []*structs.Evaluation
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				eval := raw.(*structs.Evaluation)
-				evals = append(evals, eval)
+			paginator, err := newListPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).ID
+				},
+				func(raw interface{}) error {
+					evals = append(evals, raw.(*structs.Evaluation))
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Evaluations = evals
 
 			// Use the last index that affected the jobs table
@@ -387,6 +510,45 @@ func (e *Eval) List(args *structs.EvalListRequest,
 	return e.srv.blockingRPC(&opts)
 }
 
+// BrokerStats is used to retrieve a snapshot of the leader's eval broker
+// queues so operators can see why evaluations are backing up.
+func (e *Eval) BrokerStats(args *structs.EvalBrokerStatsRequest,
+	reply *structs.EvalBrokerStatsResponse) error {
+	if done, err := e.srv.forward("Eval.BrokerStats", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "broker_stats"}, time.Now())
+
+	// Check that it is a management token.
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	stats := e.srv.evalBroker.Stats()
+	reply.TotalReady = stats.TotalReady
+	reply.TotalUnacked = stats.TotalUnacked
+	reply.TotalBlocked = stats.TotalBlocked
+	reply.TotalWaiting = stats.TotalWaiting
+	reply.TotalAcks = stats.TotalAcks
+	reply.TotalNacks = stats.TotalNacks
+	reply.ByScheduler = make(map[string]*structs.EvalBrokerSchedulerStats, len(stats.ByScheduler))
+	for sched, schedStats := range stats.ByScheduler {
+		reply.ByScheduler[sched] = &structs.EvalBrokerSchedulerStats{
+			Ready:         schedStats.Ready,
+			Unacked:       schedStats.Unacked,
+			Acks:          schedStats.Acks,
+			Nacks:         schedStats.Nacks,
+			OldestEvalAge: schedStats.OldestEvalAge,
+		}
+	}
+
+	// Set the query response
+	e.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 // Allocations is used to list the allocations for an evaluation
 func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 	reply *structs.EvalAllocationsResponse) error {
@@ -426,7 +588,7 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 
 				reply.Allocations = make([]*structs.AllocListStub, 0, len(allocs))
 				for _, alloc := range allocs {
-					reply.Allocations = append(reply.Allocations, alloc.Stub())
+					reply.Allocations = append(reply.Allocations, alloc.Stub(nil))
 				}
 			}
 
@@ -443,3 +605,76 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 		}}
 	return e.srv.blockingRPC(&opts)
 }
+
+// Lineage is used to get the evaluation lineage for a job as a graph of
+// evaluations and the links between them. This shows how follow-up and
+// blocked evaluations descend from their triggering evaluations.
+func (e *Eval) Lineage(args *structs.EvalLineageRequest,
+	reply *structs.EvalLineageResponse) error {
+	if done, err := e.srv.forward("Eval.Lineage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "lineage"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Capture the evaluations for the job
+			evals, err := state.EvalsByJob(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			reply.Evaluations = evals
+
+			// Build the links between the evaluations, deduplicating the
+			// follow-up links that are recorded on both ends of the edge
+			seen := make(map[structs.EvalLineageLink]struct{})
+			var links []*structs.EvalLineageLink
+			addLink := func(from, to, relation string) {
+				link := structs.EvalLineageLink{
+					FromEval: from,
+					ToEval:   to,
+					Relation: relation,
+				}
+				if _, ok := seen[link]; ok {
+					return
+				}
+				seen[link] = struct{}{}
+				links = append(links, &link)
+			}
+
+			for _, eval := range evals {
+				if eval.PreviousEval != "" {
+					addLink(eval.PreviousEval, eval.ID, structs.EvalLineageRelationFollowUp)
+				}
+				if eval.NextEval != "" {
+					addLink(eval.ID, eval.NextEval, structs.EvalLineageRelationFollowUp)
+				}
+				if eval.BlockedEval != "" {
+					addLink(eval.ID, eval.BlockedEval, structs.EvalLineageRelationBlocked)
+				}
+			}
+			reply.Links = links
+
+			// Use the last index that affected the evals table
+			index, err := state.Index("evals")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
@@ -718,6 +718,127 @@ func TestEvalEndpoint_List(t *testing.T) {
 
 }
 
+func TestEvalEndpoint_List_AllNamespaces_OSS(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	eval1 := mock.Eval()
+	eval1.ID = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	eval2 := mock.Eval()
+	eval2.ID = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	require.NoError(t, s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, eval2}))
+
+	t.Run("looking up all evaluations", func(t *testing.T) {
+		get := &structs.EvalListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: "*",
+			},
+		}
+		var resp structs.EvalListResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+		require.Equal(t, uint64(1000), resp.Index)
+		require.Len(t, resp.Evaluations, 2)
+		require.Equal(t, structs.DefaultNamespace, resp.Evaluations[0].Namespace)
+	})
+
+	t.Run("looking up evaluations with prefix", func(t *testing.T) {
+		get := &structs.EvalListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: "*",
+				Prefix:    "aaaabb",
+			},
+		}
+		var resp structs.EvalListResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+		require.Equal(t, uint64(1000), resp.Index)
+		require.Len(t, resp.Evaluations, 1)
+		require.Equal(t, eval2.ID, resp.Evaluations[0].ID)
+	})
+
+	t.Run("looking up evaluations with mismatch prefix", func(t *testing.T) {
+		get := &structs.EvalListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: "*",
+				Prefix:    "bbbbbb",
+			},
+		}
+		var resp structs.EvalListResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+		require.Equal(t, uint64(1000), resp.Index)
+		require.Empty(t, resp.Evaluations)
+	})
+}
+
+func TestEvalEndpoint_List_Pagination(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create evals with ordered IDs.
+	eval1 := mock.Eval()
+	eval1.ID = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	eval2 := mock.Eval()
+	eval2.ID = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	eval3 := mock.Eval()
+	eval3.ID = "aaaacccc-3350-4b4b-d185-0e1992ed43e9"
+	eval3.JobID = "filtered"
+	require.NoError(t, s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3}))
+
+	// Fetch the first page.
+	get := &structs.EvalListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   2,
+		},
+	}
+	var resp structs.EvalListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+	require.Len(t, resp.Evaluations, 2)
+	require.Equal(t, eval1.ID, resp.Evaluations[0].ID)
+	require.Equal(t, eval2.ID, resp.Evaluations[1].ID)
+	require.Equal(t, eval3.ID, resp.NextToken)
+
+	// Fetch the second and last page.
+	get.QueryOptions.NextToken = resp.NextToken
+	var resp2 structs.EvalListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp2))
+	require.Len(t, resp2.Evaluations, 1)
+	require.Equal(t, eval3.ID, resp2.Evaluations[0].ID)
+	require.Equal(t, "", resp2.NextToken)
+
+	// Fetch with a filter expression.
+	get = &structs.EvalListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			Filter:    `JobID == "filtered"`,
+		},
+	}
+	var resp3 structs.EvalListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp3))
+	require.Len(t, resp3.Evaluations, 1)
+	require.Equal(t, eval3.ID, resp3.Evaluations[0].ID)
+
+	// An invalid filter expression errors.
+	get.QueryOptions.Filter = "JobID == "
+	var resp4 structs.EvalListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp4)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse filter expression")
+}
+
 func TestEvalEndpoint_List_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -1140,3 +1261,192 @@ func TestEvalEndpoint_Reblock(t *testing.T) {
 		t.Fatalf("ReblockEval didn't insert eval into the blocked eval tracker")
 	}
 }
+
+func TestEvalEndpoint_Lineage(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create a chain of evals for the same job: a triggering eval that
+	// spawned a blocked eval and has a follow-up eval
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	eval1.BlockedEval = eval2.ID
+	eval3 := mock.Eval()
+	eval3.JobID = eval1.JobID
+	eval3.PreviousEval = eval1.ID
+	eval1.NextEval = eval3.ID
+
+	state := s1.fsm.State()
+	require.Nil(state.UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3}))
+
+	get := &structs.EvalLineageRequest{
+		JobID: eval1.JobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: eval1.Namespace,
+		},
+	}
+	var resp structs.EvalLineageResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Eval.Lineage", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Evaluations, 3)
+
+	// The follow-up link recorded on both ends is deduplicated
+	require.Len(resp.Links, 2)
+	for _, link := range resp.Links {
+		switch link.Relation {
+		case structs.EvalLineageRelationFollowUp:
+			require.Equal(eval1.ID, link.FromEval)
+			require.Equal(eval3.ID, link.ToEval)
+		case structs.EvalLineageRelationBlocked:
+			require.Equal(eval1.ID, link.FromEval)
+			require.Equal(eval2.ID, link.ToEval)
+		default:
+			t.Fatalf("unexpected relation %q", link.Relation)
+		}
+	}
+}
+
+func TestEvalEndpoint_Retry(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create a failed eval that reached the delivery limit
+	eval1 := mock.Eval()
+	eval1.Status = structs.EvalStatusFailed
+	eval1.StatusDescription = "evaluation reached delivery limit (3)"
+	state := s1.fsm.State()
+	require.Nil(state.UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+	get := &structs.EvalRetryRequest{
+		EvalID:       eval1.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Eval.Retry", get, &resp))
+	require.NotZero(resp.Index)
+
+	// The eval is pending again and re-enqueued with the broker
+	out, err := state.EvalByID(nil, eval1.ID)
+	require.Nil(err)
+	require.Equal(structs.EvalStatusPending, out.Status)
+
+	testutil.WaitForResult(func() (bool, error) {
+		stats := s1.evalBroker.Stats()
+		return stats.TotalReady == 1, fmt.Errorf("bad: %#v", stats)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Retrying a non-failed eval is rejected
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Retry", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "only failed evaluations")
+}
+
+func TestEvalEndpoint_Discard(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create a failed eval that reached the delivery limit
+	eval1 := mock.Eval()
+	eval1.Status = structs.EvalStatusFailed
+	state := s1.fsm.State()
+	require.Nil(state.UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+	get := &structs.EvalRetryRequest{
+		EvalID:       eval1.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Eval.Discard", get, &resp))
+
+	// The eval is cancelled and was not re-enqueued
+	out, err := state.EvalByID(nil, eval1.ID)
+	require.Nil(err)
+	require.Equal(structs.EvalStatusCancelled, out.Status)
+	require.Zero(s1.evalBroker.Stats().TotalReady)
+}
+
+func TestEvalEndpoint_BrokerStats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Enqueue two evals and ack one so the counters move.
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	s1.evalBroker.Enqueue(eval1)
+	s1.evalBroker.Enqueue(eval2)
+
+	out, token, err := s1.evalBroker.Dequeue([]string{eval1.Type}, time.Second)
+	require.NoError(err)
+	require.NotNil(out)
+	require.NoError(s1.evalBroker.Ack(out.ID, token))
+
+	get := &structs.EvalBrokerStatsRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.EvalBrokerStatsResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.BrokerStats", get, &resp))
+
+	require.Equal(1, resp.TotalReady)
+	require.Zero(resp.TotalUnacked)
+	require.Equal(1, resp.TotalAcks)
+	require.Zero(resp.TotalNacks)
+
+	sched, ok := resp.ByScheduler[eval1.Type]
+	require.True(ok, "missing scheduler stats for %q", eval1.Type)
+	require.Equal(1, sched.Ready)
+	require.Equal(1, sched.Acks)
+	require.NotZero(sched.OldestEvalAge)
+}
+
+func TestEvalEndpoint_BrokerStats_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	get := &structs.EvalBrokerStatsRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try without a token and expect a permissions error
+	var resp structs.EvalBrokerStatsResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.BrokerStats", get, &resp)
+	require.Error(err)
+	require.True(structs.IsErrPermissionDenied(err), "expected permissions error, got: %v", err)
+
+	// Try with a management token
+	get.AuthToken = root.SecretID
+	var resp2 structs.EvalBrokerStatsResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.BrokerStats", get, &resp2))
+}
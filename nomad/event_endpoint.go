@@ -0,0 +1,135 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Event endpoint is used to stream state change events to subscribers
+type Event struct {
+	srv *Server
+}
+
+func (e *Event) register() {
+	e.srv.streamingRpcs.Register("Event.Stream", e.stream)
+}
+
+// stream is the streaming RPC handler serving the event stream. Each batch
+// of events is JSON encoded and framed in a StreamErrWrapper.
+func (e *Event) stream(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	// Decode args
+	var args structs.EventStreamRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check read permissions for the subscribed topics
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+	namespace := args.RequestNamespace()
+	if err := aclCheckForEvents(aclObj, args.Topics, namespace); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	subscription := e.srv.eventBroker.Subscribe(&stream.SubscribeRequest{
+		Index:     args.Index,
+		Topics:    args.Topics,
+		Namespace: namespace,
+	})
+	defer subscription.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// goroutine to detect remote side closing
+	go func() {
+		if _, err := conn.Read(nil); err != nil {
+			// One end of the pipe explicitly closed, exit
+			cancel()
+			return
+		}
+		<-ctx.Done()
+	}()
+
+	var buf bytes.Buffer
+	frameCodec := codec.NewEncoder(&buf, structs.JsonHandle)
+
+	for {
+		events, err := subscription.Next(ctx)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				// The subscriber went away
+			default:
+				handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			}
+			return
+		}
+
+		if err := frameCodec.Encode(events); err != nil {
+			handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+
+		var resp cstructs.StreamErrWrapper
+		resp.Payload = buf.Bytes()
+		buf.Reset()
+
+		if err := encoder.Encode(resp); err != nil {
+			handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+		encoder.Reset(conn)
+	}
+}
+
+// aclCheckForEvents ensures the token is allowed to read every subscribed
+// topic. A nil ACL object means ACLs are disabled and everything is
+// allowed. Node events require node read, license events operator read, and
+// the remaining topics job read in the request namespace.
+func aclCheckForEvents(aclObj *acl.ACL, topics map[structs.Topic][]string, namespace string) error {
+	if aclObj == nil {
+		return nil
+	}
+
+	for topic := range topics {
+		switch topic {
+		case structs.TopicNode:
+			if !aclObj.AllowNodeRead() {
+				return structs.ErrPermissionDenied
+			}
+		case structs.TopicLicense:
+			if !aclObj.AllowOperatorRead() {
+				return structs.ErrPermissionDenied
+			}
+		case structs.TopicAll:
+			if !aclObj.AllowNodeRead() || !aclObj.AllowOperatorRead() ||
+				!aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+				return structs.ErrPermissionDenied
+			}
+		default:
+			if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+				return structs.ErrPermissionDenied
+			}
+		}
+	}
+	return nil
+}
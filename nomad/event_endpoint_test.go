@@ -0,0 +1,190 @@
+package nomad
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream_StreamJobEvents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	req := structs.EventStreamRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		QueryOptions: structs.QueryOptions{
+			Region: s1.Region(),
+		},
+	}
+
+	handler, err := s1.StreamingRpcHandler("Event.Stream")
+	require.Nil(err)
+
+	// create pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	streamMsg := make(chan *cstructs.StreamErrWrapper)
+
+	go handler(p2)
+
+	// Start decoder
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF || strings.Contains(err.Error(), "closed") {
+					return
+				}
+				errCh <- err
+				return
+			}
+
+			streamMsg <- &msg
+		}
+	}()
+
+	// send request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	// Register a job to generate an event
+	job := mock.Job()
+	registerReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.Region(),
+			Namespace: job.Namespace,
+		},
+	}
+	var registerResp structs.JobRegisterResponse
+	require.NoError(s1.RPC("Job.Register", registerReq, &registerResp))
+
+	timeout := time.After(3 * time.Second)
+OUTER:
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timeout waiting for job event")
+		case err := <-errCh:
+			t.Fatalf("error decoding stream: %v", err)
+		case msg := <-streamMsg:
+			if msg.Error != nil {
+				t.Fatalf("stream error: %v", msg.Error)
+			}
+
+			var events structs.Events
+			require.NoError(json.Unmarshal(msg.Payload, &events))
+			for _, event := range events.Events {
+				if event.Topic == structs.TopicJob && event.Key == job.ID {
+					require.Equal(structs.TypeJobRegistered, event.Type)
+					require.Equal(job.Namespace, event.Namespace)
+					break OUTER
+				}
+			}
+		}
+	}
+}
+
+func TestEventStream_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	token := mock.CreatePolicyAndToken(t, s1.State(), 1001, "test-read-job", policy)
+
+	cases := []struct {
+		name   string
+		token  string
+		topics map[structs.Topic][]string
+		err    string
+	}{
+		{
+			name:   "anonymous token is denied",
+			token:  "",
+			topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+			err:    structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:   "node topic requires node read",
+			token:  token.SecretID,
+			topics: map[structs.Topic][]string{structs.TopicNode: {"*"}},
+			err:    structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:   "job topic allowed with read-job",
+			token:  token.SecretID,
+			topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		},
+		{
+			name:   "management token allowed everything",
+			token:  root.SecretID,
+			topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := structs.EventStreamRequest{
+				Topics: tc.topics,
+				QueryOptions: structs.QueryOptions{
+					Region:    s1.Region(),
+					AuthToken: tc.token,
+				},
+			}
+
+			handler, err := s1.StreamingRpcHandler("Event.Stream")
+			require.Nil(err)
+
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			go handler(p2)
+
+			encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+			require.Nil(encoder.Encode(req))
+
+			if tc.err == "" {
+				// No error frame should arrive before the read deadline
+				p1.SetReadDeadline(time.Now().Add(250 * time.Millisecond))
+				decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+				var msg cstructs.StreamErrWrapper
+				err := decoder.Decode(&msg)
+				if err == nil && msg.Error != nil {
+					t.Fatalf("unexpected stream error: %v", msg.Error)
+				}
+				return
+			}
+
+			p1.SetReadDeadline(time.Now().Add(3 * time.Second))
+			decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+			var msg cstructs.StreamErrWrapper
+			require.NoError(decoder.Decode(&msg))
+			require.NotNil(msg.Error)
+			require.Contains(msg.Error.Error(), tc.err)
+		})
+	}
+}
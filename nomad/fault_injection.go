@@ -0,0 +1,52 @@
+package nomad
+
+import (
+	"sync"
+	"time"
+)
+
+// faultInjection holds the switches for deterministic failure testing. The
+// zero value has every fault disabled, and the switches can only be flipped
+// when Nomad is built with the faultinjection tag, so production builds pay
+// nothing beyond the disabled checks at each injection point.
+type faultInjection struct {
+	l sync.Mutex
+
+	// dropHeartbeats rejects node heartbeats so missed-heartbeat handling
+	// can be exercised without waiting out timers
+	dropHeartbeats bool
+
+	// raftApplyDelay is slept before each raft apply to simulate a slow
+	// or contended raft log
+	raftApplyDelay time.Duration
+
+	// failPlanApplies is the number of upcoming plan applies to fail
+	failPlanApplies int
+}
+
+// shouldDropHeartbeat returns whether the next node heartbeat should be
+// rejected.
+func (f *faultInjection) shouldDropHeartbeat() bool {
+	f.l.Lock()
+	defer f.l.Unlock()
+	return f.dropHeartbeats
+}
+
+// applyDelay returns how long each raft apply should be delayed.
+func (f *faultInjection) applyDelay() time.Duration {
+	f.l.Lock()
+	defer f.l.Unlock()
+	return f.raftApplyDelay
+}
+
+// shouldFailPlanApply returns whether the next plan apply should fail,
+// consuming one failure from the configured budget.
+func (f *faultInjection) shouldFailPlanApply() bool {
+	f.l.Lock()
+	defer f.l.Unlock()
+	if f.failPlanApplies == 0 {
+		return false
+	}
+	f.failPlanApplies--
+	return true
+}
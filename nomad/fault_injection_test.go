@@ -0,0 +1,82 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjection_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// The zero value has every fault disabled.
+	var f faultInjection
+	require.False(t, f.shouldDropHeartbeat())
+	require.Zero(t, f.applyDelay())
+	require.False(t, f.shouldFailPlanApply())
+}
+
+func TestFaultInjection_FailPlanApplies(t *testing.T) {
+	t.Parallel()
+
+	// Each failure consumes one entry from the budget.
+	f := faultInjection{failPlanApplies: 2}
+	require.True(t, f.shouldFailPlanApply())
+	require.True(t, f.shouldFailPlanApply())
+	require.False(t, f.shouldFailPlanApply())
+}
+
+func TestFaultInjection_DropHeartbeats(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	update := &structs.NodeUpdateStatusRequest{
+		NodeID:       node.ID,
+		Status:       structs.NodeStatusReady,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Heartbeats are rejected while the fault is injected.
+	s1.faults.dropHeartbeats = true
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", update, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "heartbeat dropped by fault injection")
+
+	// And processed again once it is cleared.
+	s1.faults.dropHeartbeats = false
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", update, &resp))
+	require.NotZero(t, resp.HeartbeatTTL)
+}
+
+func TestFaultInjection_DelayRaftApply(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	s1.faults.raftApplyDelay = 50 * time.Millisecond
+	defer func() { s1.faults.raftApplyDelay = 0 }()
+
+	start := time.Now()
+	_, _, err := s1.raftApply(structs.NodeRegisterRequestType, &structs.NodeRegisterRequest{Node: mock.Node()})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
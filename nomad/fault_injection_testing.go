@@ -0,0 +1,34 @@
+// +build faultinjection
+
+package nomad
+
+import (
+	"time"
+)
+
+// This file exposes the fault injection switches to integration tests. It is
+// only built with the faultinjection tag so the API cannot be reached in
+// production builds.
+
+// FaultDropHeartbeats configures the server to reject node heartbeats until
+// disabled again.
+func (s *Server) FaultDropHeartbeats(drop bool) {
+	s.faults.l.Lock()
+	defer s.faults.l.Unlock()
+	s.faults.dropHeartbeats = drop
+}
+
+// FaultDelayRaftApply delays every raft apply on this server by the given
+// duration. A zero duration disables the fault.
+func (s *Server) FaultDelayRaftApply(delay time.Duration) {
+	s.faults.l.Lock()
+	defer s.faults.l.Unlock()
+	s.faults.raftApplyDelay = delay
+}
+
+// FaultFailPlanApplies fails the next n plan applies on this server.
+func (s *Server) FaultFailPlanApplies(n int) {
+	s.faults.l.Lock()
+	defer s.faults.l.Unlock()
+	s.faults.failPlanApplies = n
+}
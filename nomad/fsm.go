@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
@@ -52,6 +53,21 @@ const (
 	CSIPluginSnapshot
 	CSIVolumeSnapshot
 	ScalingEventsSnapshot
+	LicenseSnapshot
+	FeatureFlagsSnapshot
+	FreezeWindowsSnapshot
+	LicenseEventSnapshot
+	SentinelPolicySnapshot
+	SentinelOverrideSnapshot
+	SentinelModuleSnapshot
+	NamespaceSnapshot
+	ACLRoleSnapshot
+	ACLAuthMethodSnapshot
+	ACLBindingRuleSnapshot
+	SigningKeySnapshot
+	ServiceRegistrationSnapshot
+	VariableSnapshot
+	JobSubmissionSnapshot
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -125,6 +141,10 @@ type FSMConfig struct {
 
 	// Region is the region of the server embedding the FSM
 	Region string
+
+	// EventBroker is the event broker state changes are published to. It
+	// may be nil, in which case no events are published.
+	EventBroker *stream.EventBroker
 }
 
 // NewFSMPath is used to construct a new FSM with a blank state
@@ -236,6 +256,32 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyACLPolicyUpsert(buf[1:], log.Index)
 	case structs.ACLPolicyDeleteRequestType:
 		return n.applyACLPolicyDelete(buf[1:], log.Index)
+	case structs.ACLRoleUpsertRequestType:
+		return n.applyACLRoleUpsert(buf[1:], log.Index)
+	case structs.ACLRoleDeleteRequestType:
+		return n.applyACLRoleDelete(buf[1:], log.Index)
+	case structs.ACLAuthMethodUpsertRequestType:
+		return n.applyACLAuthMethodUpsert(buf[1:], log.Index)
+	case structs.ACLAuthMethodDeleteRequestType:
+		return n.applyACLAuthMethodDelete(buf[1:], log.Index)
+	case structs.ACLBindingRuleUpsertRequestType:
+		return n.applyACLBindingRuleUpsert(buf[1:], log.Index)
+	case structs.ACLBindingRuleDeleteRequestType:
+		return n.applyACLBindingRuleDelete(buf[1:], log.Index)
+	case structs.SigningKeyUpsertRequestType:
+		return n.applySigningKeyUpsert(buf[1:], log.Index)
+	case structs.SigningKeyDeleteRequestType:
+		return n.applySigningKeyDelete(buf[1:], log.Index)
+	case structs.NodeBatchMetaUpdateRequestType:
+		return n.applyNodeBatchMetaUpdate(buf[1:], log.Index)
+	case structs.ServiceRegistrationUpsertRequestType:
+		return n.applyServiceRegistrationUpsert(buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteByIDRequestType:
+		return n.applyServiceRegistrationDeleteByID(buf[1:], log.Index)
+	case structs.VariablesUpsertRequestType:
+		return n.applyVariablesUpsert(buf[1:], log.Index)
+	case structs.VariablesDeleteRequestType:
+		return n.applyVariablesDelete(buf[1:], log.Index)
 	case structs.ACLTokenUpsertRequestType:
 		return n.applyACLTokenUpsert(buf[1:], log.Index)
 	case structs.ACLTokenDeleteRequestType:
@@ -276,6 +322,30 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyCSIVolumeBatchClaim(buf[1:], log.Index)
 	case structs.CSIPluginDeleteRequestType:
 		return n.applyCSIPluginDelete(buf[1:], log.Index)
+	case structs.AllocCheckpointMetaSetRequestType:
+		return n.applyAllocCheckpointMetaSet(buf[1:], log.Index)
+	case structs.LicenseDeleteRequestType:
+		return n.applyLicenseDelete(buf[1:], log.Index)
+	case structs.FeatureFlagsRequestType:
+		return n.applyFeatureFlagsUpdate(buf[1:], log.Index)
+	case structs.LicenseUpsertRequestType:
+		return n.applyLicenseUpsert(buf[1:], log.Index)
+	case structs.FreezeWindowsRequestType:
+		return n.applyFreezeWindowsUpdate(buf[1:], log.Index)
+	case structs.SentinelPolicyUpsertRequestType:
+		return n.applySentinelPolicyUpsert(buf[1:], log.Index)
+	case structs.SentinelPolicyDeleteRequestType:
+		return n.applySentinelPolicyDelete(buf[1:], log.Index)
+	case structs.SentinelModuleUpsertRequestType:
+		return n.applySentinelModuleUpsert(buf[1:], log.Index)
+	case structs.SentinelModuleDeleteRequestType:
+		return n.applySentinelModuleDelete(buf[1:], log.Index)
+	case structs.JobPayloadAppendRequestType:
+		return n.applyJobPayloadAppend(buf[1:], log.Index)
+	case structs.NamespaceUpsertRequestType:
+		return n.applyNamespaceUpsert(buf[1:], log.Index)
+	case structs.NamespaceDeleteRequestType:
+		return n.applyNamespaceDelete(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -331,6 +401,13 @@ func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 		n.blockedEvals.Unblock(req.Node.ComputedClass, index)
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    structs.TypeNodeRegistered,
+		Key:     req.Node.ID,
+		Payload: req.Node,
+	})
+
 	return nil
 }
 
@@ -346,6 +423,12 @@ func (n *nomadFSM) applyDeregisterNode(buf []byte, index uint64) interface{} {
 		return err
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic: structs.TopicNode,
+		Type:  structs.TypeNodeDeregistered,
+		Key:   req.NodeID,
+	})
+
 	return nil
 }
 
@@ -361,6 +444,16 @@ func (n *nomadFSM) applyDeregisterNodeBatch(buf []byte, index uint64) interface{
 		return err
 	}
 
+	events := make([]structs.Event, 0, len(req.NodeIDs))
+	for _, nodeID := range req.NodeIDs {
+		events = append(events, structs.Event{
+			Topic: structs.TopicNode,
+			Type:  structs.TypeNodeDeregistered,
+			Key:   nodeID,
+		})
+	}
+	n.publishEvents(index, events...)
+
 	return nil
 }
 
@@ -390,6 +483,13 @@ func (n *nomadFSM) applyStatusUpdate(buf []byte, index uint64) interface{} {
 		n.blockedEvals.UnblockNode(req.NodeID, index)
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    structs.TypeNodeStatusUpdated,
+		Key:     req.NodeID,
+		Payload: &req,
+	})
+
 	return nil
 }
 
@@ -418,6 +518,14 @@ func (n *nomadFSM) applyDrainUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpdateNodeDrain failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    structs.TypeNodeDrainUpdated,
+		Key:     req.NodeID,
+		Payload: &req,
+	})
+
 	return nil
 }
 
@@ -462,6 +570,13 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interfac
 		n.blockedEvals.UnblockNode(req.NodeID, index)
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    structs.TypeNodeEligibilityUpdated,
+		Key:     req.NodeID,
+		Payload: &req,
+	})
+
 	return nil
 }
 
@@ -486,6 +601,36 @@ func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 		return err
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic:     structs.TopicJob,
+		Type:      structs.TypeJobRegistered,
+		Key:       req.Job.ID,
+		Namespace: req.Job.Namespace,
+		Payload:   req.Job,
+	})
+
+	// Store the original source material if it was provided. The job's
+	// version was assigned by UpsertJob above.
+	if req.Submission != nil {
+		sub := req.Submission
+		sub.Namespace = req.Job.Namespace
+		sub.JobID = req.Job.ID
+		sub.Version = req.Job.Version
+		sub.JobModifyIndex = index
+		if err := n.state.UpsertJobSubmission(index, sub); err != nil {
+			n.logger.Error("UpsertJobSubmission failed", "error", err)
+			return err
+		}
+	}
+
+	// Record any policy override for auditing
+	if req.SentinelOverride != nil {
+		if err := n.state.InsertSentinelOverride(index, req.SentinelOverride); err != nil {
+			n.logger.Error("InsertSentinelOverride failed", "error", err)
+			return err
+		}
+	}
+
 	// We always add the job to the periodic dispatcher because there is the
 	// possibility that the periodic spec was removed and then we should stop
 	// tracking it.
@@ -599,6 +744,14 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 		return err
 	}
 
+	n.publishEvents(index, structs.Event{
+		Topic:     structs.TopicJob,
+		Type:      structs.TypeJobDeregistered,
+		Key:       req.JobID,
+		Namespace: req.Namespace,
+		Payload:   &req,
+	})
+
 	return nil
 }
 
@@ -639,8 +792,9 @@ func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{}
 
 // handleJobDeregister is used to deregister a job.
 func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, purge bool, tx state.Txn) error {
-	// If it is periodic remove it from the dispatcher
-	if err := n.periodicDispatcher.Remove(namespace, jobID); err != nil {
+	// If it is periodic remove it from the dispatcher. A stop without purge
+	// keeps the tracked cron state so it survives until the job is purged.
+	if err := n.periodicDispatcher.Remove(namespace, jobID, purge); err != nil {
 		n.logger.Error("periodicDispatcher.Remove failed", "error", err)
 		return err
 	}
@@ -696,6 +850,7 @@ func (n *nomadFSM) upsertEvals(index uint64, evals []*structs.Evaluation) error
 	}
 
 	n.handleUpsertedEvals(evals)
+	n.publishEvents(index, evalStreamEvents(evals)...)
 	return nil
 }
 
@@ -774,6 +929,8 @@ func (n *nomadFSM) applyAllocUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpsertAllocs failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, allocStreamEvents(req.Alloc)...)
 	return nil
 }
 
@@ -804,6 +961,8 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 		return err
 	}
 
+	n.publishEvents(index, allocStreamEvents(req.Alloc)...)
+
 	// Update any evals
 	if len(req.Evals) > 0 {
 		if err := n.upsertEvals(index, req.Evals); err != nil {
@@ -858,6 +1017,110 @@ func (n *nomadFSM) applyAllocUpdateDesiredTransition(buf []byte, index uint64) i
 	return nil
 }
 
+// applyAllocCheckpointMetaSet is used to replace the checkpoint metadata of an
+// allocation.
+func (n *nomadFSM) applyAllocCheckpointMetaSet(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "alloc_checkpoint_meta_set"}, time.Now())
+	var req structs.AllocCheckpointMetaSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.SetAllocCheckpointMeta(index, req.AllocID, req.Meta); err != nil {
+		n.logger.Error("SetAllocCheckpointMeta failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyLicenseUpsert is used to set the stored cluster license.
+func (n *nomadFSM) applyLicenseUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "license_upsert"}, time.Now())
+	var req structs.LicenseUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Record the old license ID for the audit trail before it is replaced
+	event := &structs.LicenseEvent{
+		Action:     structs.LicenseEventActionApply,
+		AccessorID: req.AccessorID,
+		Time:       req.Time,
+	}
+	if old, err := n.state.License(nil); err == nil && old != nil {
+		if license, err := structs.ParseLicense(old.Signed); err == nil {
+			event.OldLicenseID = license.LicenseID
+		}
+	}
+	if license, err := structs.ParseLicense(req.License.Signed); err == nil {
+		event.NewLicenseID = license.LicenseID
+	}
+
+	if err := n.state.UpsertLicense(index, req.License); err != nil {
+		n.logger.Error("UpsertLicense failed", "error", err)
+		return err
+	}
+
+	if err := n.state.InsertLicenseEvent(index, event); err != nil {
+		n.logger.Error("InsertLicenseEvent failed", "error", err)
+		return err
+	}
+
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicLicense,
+		Type:    structs.TypeLicenseUpserted,
+		Key:     event.NewLicenseID,
+		Payload: event,
+	})
+
+	return nil
+}
+
+// applyLicenseDelete is used to remove the stored cluster license.
+func (n *nomadFSM) applyLicenseDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "license_delete"}, time.Now())
+	var req structs.LicenseDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Record the old license ID for the audit trail before it is removed
+	event := &structs.LicenseEvent{
+		Action:     structs.LicenseEventActionReset,
+		AccessorID: req.AccessorID,
+		Time:       req.Time,
+	}
+	old, err := n.state.License(nil)
+	if err == nil && old != nil {
+		if license, err := structs.ParseLicense(old.Signed); err == nil {
+			event.OldLicenseID = license.LicenseID
+		}
+	}
+
+	if err := n.state.DeleteLicense(index); err != nil {
+		n.logger.Error("DeleteLicense failed", "error", err)
+		return err
+	}
+
+	// Resetting an empty license is a no-op and is not recorded
+	if old != nil {
+		if err := n.state.InsertLicenseEvent(index, event); err != nil {
+			n.logger.Error("InsertLicenseEvent failed", "error", err)
+			return err
+		}
+
+		n.publishEvents(index, structs.Event{
+			Topic:   structs.TopicLicense,
+			Type:    structs.TypeLicenseDeleted,
+			Key:     event.OldLicenseID,
+			Payload: event,
+		})
+	}
+
+	return nil
+}
+
 // applyReconcileSummaries reconciles summaries for all the jobs
 func (n *nomadFSM) applyReconcileSummaries(buf []byte, index uint64) interface{} {
 	if err := n.state.ReconcileJobSummaries(index); err != nil {
@@ -960,6 +1223,20 @@ func (n *nomadFSM) applyPlanResults(buf []byte, index uint64) interface{} {
 
 	// Add evals for jobs that were preempted
 	n.handleUpsertedEvals(req.PreemptionEvals)
+
+	events := allocStreamEvents(req.Alloc)
+	if req.Deployment != nil {
+		events = append(events, structs.Event{
+			Topic:      structs.TopicDeployment,
+			Type:       structs.TypeDeploymentUpdated,
+			Key:        req.Deployment.ID,
+			FilterKeys: []string{req.Deployment.JobID},
+			Namespace:  req.Deployment.Namespace,
+			Payload:    req.Deployment,
+		})
+	}
+	n.publishEvents(index, events...)
+
 	return nil
 }
 
@@ -978,6 +1255,12 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(buf []byte, index uint64) interfa
 	}
 
 	n.handleUpsertedEval(req.Eval)
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicDeployment,
+		Type:    structs.TypeDeploymentUpdated,
+		Key:     req.DeploymentUpdate.DeploymentID,
+		Payload: req.DeploymentUpdate,
+	})
 	return nil
 }
 
@@ -995,6 +1278,12 @@ func (n *nomadFSM) applyDeploymentPromotion(buf []byte, index uint64) interface{
 	}
 
 	n.handleUpsertedEval(req.Eval)
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicDeployment,
+		Type:    structs.TypeDeploymentPromoted,
+		Key:     req.DeploymentID,
+		Payload: &req,
+	})
 	return nil
 }
 
@@ -1013,6 +1302,12 @@ func (n *nomadFSM) applyDeploymentAllocHealth(buf []byte, index uint64) interfac
 	}
 
 	n.handleUpsertedEval(req.Eval)
+	n.publishEvents(index, structs.Event{
+		Topic:   structs.TopicDeployment,
+		Type:    structs.TypeDeploymentAllocHealth,
+		Key:     req.DeploymentID,
+		Payload: &req,
+	})
 	return nil
 }
 
@@ -1078,243 +1373,569 @@ func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
 	return nil
 }
 
-// applyACLTokenUpsert is used to upsert a set of policies
-func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
-	var req structs.ACLTokenUpsertRequest
+// applyACLRoleUpsert is used to upsert a set of roles
+func (n *nomadFSM) applyACLRoleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
+	var req structs.ACLRoleUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpsertACLTokens(index, req.Tokens); err != nil {
-		n.logger.Error("UpsertACLTokens failed", "error", err)
+	if err := n.state.UpsertACLRoles(index, req.Roles); err != nil {
+		n.logger.Error("UpsertACLRoles failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-// applyACLTokenDelete is used to delete a set of policies
-func (n *nomadFSM) applyACLTokenDelete(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
-	var req structs.ACLTokenDeleteRequest
+// applyACLRoleDelete is used to delete a set of roles
+func (n *nomadFSM) applyACLRoleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_delete"}, time.Now())
+	var req structs.ACLRoleDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteACLTokens(index, req.AccessorIDs); err != nil {
-		n.logger.Error("DeleteACLTokens failed", "error", err)
+	if err := n.state.DeleteACLRoles(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLRoles failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-// applyACLTokenBootstrap is used to bootstrap an ACL token
-func (n *nomadFSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_bootstrap"}, time.Now())
-	var req structs.ACLTokenBootstrapRequest
+// applyACLAuthMethodUpsert is used to upsert a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.BootstrapACLTokens(index, req.ResetIndex, req.Token); err != nil {
-		n.logger.Error("BootstrapACLToken failed", "error", err)
+	if err := n.state.UpsertACLAuthMethods(index, req.AuthMethods); err != nil {
+		n.logger.Error("UpsertACLAuthMethods failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
-	var req structs.AutopilotSetConfigRequest
+// applyACLAuthMethodDelete is used to delete a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "autopilot"}, time.Now())
 
-	if req.CAS {
-		act, err := n.state.AutopilotCASConfig(index, req.Config.ModifyIndex, &req.Config)
-		if err != nil {
-			return err
-		}
-		return act
+	if err := n.state.DeleteACLAuthMethods(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLAuthMethods failed", "error", err)
+		return err
 	}
-	return n.state.AutopilotSetConfig(index, &req.Config)
+	return nil
 }
 
-func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
-	var req structs.SchedulerSetConfigRequest
+// applyACLBindingRuleUpsert is used to upsert a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRuleUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_scheduler_config"}, time.Now())
 
-	req.Config.Canonicalize()
-
-	if req.CAS {
-		applied, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
-		if err != nil {
-			return err
-		}
-		return applied
+	if err := n.state.UpsertACLBindingRules(index, req.BindingRules); err != nil {
+		n.logger.Error("UpsertACLBindingRules failed", "error", err)
+		return err
 	}
-	return n.state.SchedulerSetConfig(index, &req.Config)
+	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{} {
-	var req structs.CSIVolumeRegisterRequest
+// applyACLBindingRuleDelete is used to delete a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRuleDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_register"}, time.Now())
 
-	if err := n.state.CSIVolumeRegister(index, req.Volumes); err != nil {
-		n.logger.Error("CSIVolumeRegister failed", "error", err)
+	if err := n.state.DeleteACLBindingRules(index, req.IDs); err != nil {
+		n.logger.Error("DeleteACLBindingRules failed", "error", err)
 		return err
 	}
-
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
-	var req structs.CSIVolumeDeregisterRequest
+// applySigningKeyUpsert is used to upsert a set of workload identity signing
+// keys
+func (n *nomadFSM) applySigningKeyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_signing_key_upsert"}, time.Now())
+	var req structs.SigningKeyUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_deregister"}, time.Now())
 
-	if err := n.state.CSIVolumeDeregister(index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
-		n.logger.Error("CSIVolumeDeregister failed", "error", err)
+	if err := n.state.UpsertSigningKeys(index, req.Keys); err != nil {
+		n.logger.Error("UpsertSigningKeys failed", "error", err)
 		return err
 	}
-
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{} {
-	var batch *structs.CSIVolumeClaimBatchRequest
-	if err := structs.Decode(buf, &batch); err != nil {
+// applySigningKeyDelete is used to remove a workload identity signing key
+// from the keyring
+func (n *nomadFSM) applySigningKeyDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_signing_key_delete"}, time.Now())
+	var req structs.KeyringRemoveRequest
+	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_batch_claim"}, time.Now())
 
-	for _, req := range batch.Claims {
-		err := n.state.CSIVolumeClaim(index, req.RequestNamespace(),
-			req.VolumeID, req.ToClaim())
-		if err != nil {
-			n.logger.Error("CSIVolumeClaim for batch failed", "error", err)
-			return err // note: fails the remaining batch
-		}
+	if err := n.state.DeleteSigningKey(index, req.KeyID); err != nil {
+		n.logger.Error("DeleteSigningKey failed", "error", err)
+		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeClaim(buf []byte, index uint64) interface{} {
-	var req structs.CSIVolumeClaimRequest
+// applyNodeBatchMetaUpdate is used to merge a metadata update into a batch
+// of nodes
+func (n *nomadFSM) applyNodeBatchMetaUpdate(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "batch_node_meta_update"}, time.Now())
+	var req structs.NodeBatchMetaUpdateRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_claim"}, time.Now())
 
-	if err := n.state.CSIVolumeClaim(index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
-		n.logger.Error("CSIVolumeClaim failed", "error", err)
+	if err := n.state.BatchUpdateNodeMeta(index, req.UpdatedAt, req.NodeIDs, req.Meta); err != nil {
+		n.logger.Error("BatchUpdateNodeMeta failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
-	var req structs.CSIPluginDeleteRequest
+// applyServiceRegistrationUpsert is used to upsert a set of service
+// registrations
+func (n *nomadFSM) applyServiceRegistrationUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_upsert"}, time.Now())
+	var req structs.ServiceRegistrationUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_plugin_delete"}, time.Now())
 
-	if err := n.state.DeleteCSIPlugin(index, req.ID); err != nil {
-		// "plugin in use" is an error for the state store but not for typical
-		// callers, so reduce log noise by not logging that case here
-		if err.Error() != "plugin in use" {
-			n.logger.Error("DeleteCSIPlugin failed", "error", err)
-		}
+	if err := n.state.UpsertServiceRegistrations(index, req.Services); err != nil {
+		n.logger.Error("UpsertServiceRegistrations failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
-	// Create a new snapshot
-	snap, err := n.state.Snapshot()
-	if err != nil {
-		return nil, err
+// applyServiceRegistrationDeleteByID is used to remove a service registration
+func (n *nomadFSM) applyServiceRegistrationDeleteByID(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_delete_by_id"}, time.Now())
+	var req structs.ServiceRegistrationDeleteByIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	ns := &nomadSnapshot{
-		snap:      snap,
-		timetable: n.timetable,
+	if err := n.state.DeleteServiceRegistrationByID(index, req.ID); err != nil {
+		n.logger.Error("DeleteServiceRegistrationByID failed", "error", err)
+		return err
 	}
-	return ns, nil
+	return nil
 }
 
-func (n *nomadFSM) Restore(old io.ReadCloser) error {
-	defer old.Close()
-
-	// Create a new state store
-	config := &state.StateStoreConfig{
-		Logger: n.config.Logger,
-		Region: n.config.Region,
+// applyVariablesUpsert is used to upsert a set of encrypted variables
+func (n *nomadFSM) applyVariablesUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variables_upsert"}, time.Now())
+	var req structs.VariablesEncryptedUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	newState, err := state.NewStateStore(config)
-	if err != nil {
+
+	if err := n.state.UpsertVariables(index, req.Variables); err != nil {
+		n.logger.Error("UpsertVariables failed", "error", err)
 		return err
 	}
+	return nil
+}
+
+// applyVariablesDelete is used to remove the variable at a path
+func (n *nomadFSM) applyVariablesDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variables_delete"}, time.Now())
+	var req structs.VariablesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-	// Start the state restore
-	restore, err := newState.Restore()
-	if err != nil {
+	if err := n.state.DeleteVariable(index, req.RequestNamespace(), req.Path); err != nil {
+		n.logger.Error("DeleteVariable failed", "error", err)
 		return err
 	}
-	defer restore.Abort()
+	return nil
+}
 
-	// Create a decoder
-	dec := codec.NewDecoder(old, structs.MsgpackHandle)
+// applySentinelPolicyUpsert is used to upsert a set of Sentinel policies
+func (n *nomadFSM) applySentinelPolicyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_sentinel_policy_upsert"}, time.Now())
+	var req structs.SentinelPolicyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-	// Read in the header
-	var header snapshotHeader
-	if err := dec.Decode(&header); err != nil {
+	if err := n.state.UpsertSentinelPolicies(index, req.Policies); err != nil {
+		n.logger.Error("UpsertSentinelPolicies failed", "error", err)
 		return err
 	}
+	return nil
+}
 
-	// Populate the new state
-	msgType := make([]byte, 1)
-	for {
-		// Read the message type
-		_, err := old.Read(msgType)
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
-		}
-
-		// Decode
-		snapType := SnapshotType(msgType[0])
-		switch snapType {
-		case TimeTableSnapshot:
-			if err := n.timetable.Deserialize(dec); err != nil {
-				return fmt.Errorf("time table deserialize failed: %v", err)
-			}
+// applySentinelPolicyDelete is used to delete a set of Sentinel policies
+func (n *nomadFSM) applySentinelPolicyDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_sentinel_policy_delete"}, time.Now())
+	var req structs.SentinelPolicyDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-		case NodeSnapshot:
-			node := new(structs.Node)
-			if err := dec.Decode(node); err != nil {
-				return err
-			}
+	if err := n.state.DeleteSentinelPolicies(index, req.Names); err != nil {
+		n.logger.Error("DeleteSentinelPolicies failed", "error", err)
+		return err
+	}
+	return nil
+}
 
-			// Handle upgrade paths
-			node.Canonicalize()
+// applySentinelModuleUpsert is used to upsert a set of Sentinel modules
+func (n *nomadFSM) applySentinelModuleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_sentinel_module_upsert"}, time.Now())
+	var req structs.SentinelModuleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
 
-			if err := restore.NodeRestore(node); err != nil {
-				return err
-			}
+	if err := n.state.UpsertSentinelModules(index, req.Modules); err != nil {
+		n.logger.Error("UpsertSentinelModules failed", "error", err)
+		return err
+	}
+	return nil
+}
 
-		case JobSnapshot:
+// applySentinelModuleDelete is used to delete a set of Sentinel modules
+func (n *nomadFSM) applySentinelModuleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_sentinel_module_delete"}, time.Now())
+	var req structs.SentinelModuleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteSentinelModules(index, req.Names); err != nil {
+		n.logger.Error("DeleteSentinelModules failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyJobPayloadAppend appends a dispatch payload chunk to a dispatched job
+func (n *nomadFSM) applyJobPayloadAppend(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_payload_append"}, time.Now())
+	var req structs.JobPayloadAppendRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.AppendJobPayload(index, req.Namespace, req.JobID, req.Chunk); err != nil {
+		n.logger.Error("AppendJobPayload failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenUpsert is used to upsert a set of policies
+func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
+	var req structs.ACLTokenUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLTokens(index, req.Tokens); err != nil {
+		n.logger.Error("UpsertACLTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenDelete is used to delete a set of policies
+func (n *nomadFSM) applyACLTokenDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
+	var req structs.ACLTokenDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLTokens(index, req.AccessorIDs); err != nil {
+		n.logger.Error("DeleteACLTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLTokenBootstrap is used to bootstrap an ACL token
+func (n *nomadFSM) applyACLTokenBootstrap(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_bootstrap"}, time.Now())
+	var req structs.ACLTokenBootstrapRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.BootstrapACLTokens(index, req.ResetIndex, req.Token); err != nil {
+		n.logger.Error("BootstrapACLToken failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
+	var req structs.AutopilotSetConfigRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "autopilot"}, time.Now())
+
+	if req.CAS {
+		act, err := n.state.AutopilotCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		if err != nil {
+			return err
+		}
+		return act
+	}
+	return n.state.AutopilotSetConfig(index, &req.Config)
+}
+
+func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
+	var req structs.SchedulerSetConfigRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_scheduler_config"}, time.Now())
+
+	req.Config.Canonicalize()
+
+	if req.CAS {
+		applied, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		if err != nil {
+			return err
+		}
+		return applied
+	}
+	return n.state.SchedulerSetConfig(index, &req.Config)
+}
+
+func (n *nomadFSM) applyFeatureFlagsUpdate(buf []byte, index uint64) interface{} {
+	var req structs.FeatureFlagsSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_feature_flags"}, time.Now())
+
+	return n.state.FeatureFlagsSet(index, &structs.FeatureFlags{Flags: req.Flags})
+}
+
+// applyNamespaceUpsert is used to upsert a set of namespaces
+func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
+	var req structs.NamespaceUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Error("UpsertNamespaces failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyNamespaceDelete is used to delete a set of namespaces
+func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
+	var req structs.NamespaceDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Error("DeleteNamespaces failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyFreezeWindowsUpdate(buf []byte, index uint64) interface{} {
+	var req structs.FreezeWindowsSetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_freeze_windows"}, time.Now())
+
+	return n.state.FreezeWindowsSet(index, &structs.FreezeWindows{Windows: req.Windows})
+}
+
+func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{} {
+	var req structs.CSIVolumeRegisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_register"}, time.Now())
+
+	if err := n.state.CSIVolumeRegister(index, req.Volumes); err != nil {
+		n.logger.Error("CSIVolumeRegister failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
+	var req structs.CSIVolumeDeregisterRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_deregister"}, time.Now())
+
+	if err := n.state.CSIVolumeDeregister(index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
+		n.logger.Error("CSIVolumeDeregister failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{} {
+	var batch *structs.CSIVolumeClaimBatchRequest
+	if err := structs.Decode(buf, &batch); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_batch_claim"}, time.Now())
+
+	for _, req := range batch.Claims {
+		err := n.state.CSIVolumeClaim(index, req.RequestNamespace(),
+			req.VolumeID, req.ToClaim())
+		if err != nil {
+			n.logger.Error("CSIVolumeClaim for batch failed", "error", err)
+			return err // note: fails the remaining batch
+		}
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyCSIVolumeClaim(buf []byte, index uint64) interface{} {
+	var req structs.CSIVolumeClaimRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_claim"}, time.Now())
+
+	if err := n.state.CSIVolumeClaim(index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
+		n.logger.Error("CSIVolumeClaim failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
+	var req structs.CSIPluginDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_plugin_delete"}, time.Now())
+
+	if err := n.state.DeleteCSIPlugin(index, req.ID); err != nil {
+		// "plugin in use" is an error for the state store but not for typical
+		// callers, so reduce log noise by not logging that case here
+		if err.Error() != "plugin in use" {
+			n.logger.Error("DeleteCSIPlugin failed", "error", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
+	// Create a new snapshot
+	snap, err := n.state.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	ns := &nomadSnapshot{
+		snap:      snap,
+		timetable: n.timetable,
+	}
+	return ns, nil
+}
+
+func (n *nomadFSM) Restore(old io.ReadCloser) error {
+	defer old.Close()
+
+	// Create a new state store
+	config := &state.StateStoreConfig{
+		Logger: n.config.Logger,
+		Region: n.config.Region,
+	}
+	newState, err := state.NewStateStore(config)
+	if err != nil {
+		return err
+	}
+
+	// Start the state restore
+	restore, err := newState.Restore()
+	if err != nil {
+		return err
+	}
+	defer restore.Abort()
+
+	// Create a decoder
+	dec := codec.NewDecoder(old, structs.MsgpackHandle)
+
+	// Read in the header
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+
+	// Populate the new state
+	msgType := make([]byte, 1)
+	for {
+		// Read the message type
+		_, err := old.Read(msgType)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// Decode
+		snapType := SnapshotType(msgType[0])
+		switch snapType {
+		case TimeTableSnapshot:
+			if err := n.timetable.Deserialize(dec); err != nil {
+				return fmt.Errorf("time table deserialize failed: %v", err)
+			}
+
+		case NodeSnapshot:
+			node := new(structs.Node)
+			if err := dec.Decode(node); err != nil {
+				return err
+			}
+
+			// Handle upgrade paths
+			node.Canonicalize()
+
+			if err := restore.NodeRestore(node); err != nil {
+				return err
+			}
+
+		case JobSnapshot:
 			job := new(structs.Job)
 			if err := dec.Decode(job); err != nil {
 				return err
@@ -1413,6 +2034,16 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+
+			if err := restore.JobSubmissionRestore(sub); err != nil {
+				return err
+			}
+
 		case DeploymentSnapshot:
 			deployment := new(structs.Deployment)
 			if err := dec.Decode(deployment); err != nil {
@@ -1432,6 +2063,60 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case ACLRoleSnapshot:
+			role := new(structs.ACLRole)
+			if err := dec.Decode(role); err != nil {
+				return err
+			}
+			if err := restore.ACLRoleRestore(role); err != nil {
+				return err
+			}
+
+		case ACLAuthMethodSnapshot:
+			method := new(structs.ACLAuthMethod)
+			if err := dec.Decode(method); err != nil {
+				return err
+			}
+			if err := restore.ACLAuthMethodRestore(method); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			rule := new(structs.ACLBindingRule)
+			if err := dec.Decode(rule); err != nil {
+				return err
+			}
+			if err := restore.ACLBindingRuleRestore(rule); err != nil {
+				return err
+			}
+
+		case SigningKeySnapshot:
+			key := new(structs.SigningKey)
+			if err := dec.Decode(key); err != nil {
+				return err
+			}
+			if err := restore.SigningKeyRestore(key); err != nil {
+				return err
+			}
+
+		case ServiceRegistrationSnapshot:
+			service := new(structs.ServiceRegistration)
+			if err := dec.Decode(service); err != nil {
+				return err
+			}
+			if err := restore.ServiceRegistrationRestore(service); err != nil {
+				return err
+			}
+
+		case VariableSnapshot:
+			variable := new(structs.EncryptedVariable)
+			if err := dec.Decode(variable); err != nil {
+				return err
+			}
+			if err := restore.VariableRestore(variable); err != nil {
+				return err
+			}
+
 		case ACLTokenSnapshot:
 			token := new(structs.ACLToken)
 			if err := dec.Decode(token); err != nil {
@@ -1460,6 +2145,78 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case LicenseSnapshot:
+			license := new(structs.StoredLicense)
+			if err := dec.Decode(license); err != nil {
+				return err
+			}
+			if err := restore.LicenseRestore(license); err != nil {
+				return err
+			}
+
+		case FeatureFlagsSnapshot:
+			flags := new(structs.FeatureFlags)
+			if err := dec.Decode(flags); err != nil {
+				return err
+			}
+			if err := restore.FeatureFlagsRestore(flags); err != nil {
+				return err
+			}
+
+		case FreezeWindowsSnapshot:
+			windows := new(structs.FreezeWindows)
+			if err := dec.Decode(windows); err != nil {
+				return err
+			}
+			if err := restore.FreezeWindowsRestore(windows); err != nil {
+				return err
+			}
+
+		case NamespaceSnapshot:
+			namespace := new(structs.Namespace)
+			if err := dec.Decode(namespace); err != nil {
+				return err
+			}
+			if err := restore.NamespaceRestore(namespace); err != nil {
+				return err
+			}
+
+		case LicenseEventSnapshot:
+			event := new(structs.LicenseEvent)
+			if err := dec.Decode(event); err != nil {
+				return err
+			}
+			if err := restore.LicenseEventRestore(event); err != nil {
+				return err
+			}
+
+		case SentinelPolicySnapshot:
+			policy := new(structs.SentinelPolicy)
+			if err := dec.Decode(policy); err != nil {
+				return err
+			}
+			if err := restore.SentinelPolicyRestore(policy); err != nil {
+				return err
+			}
+
+		case SentinelOverrideSnapshot:
+			override := new(structs.SentinelOverride)
+			if err := dec.Decode(override); err != nil {
+				return err
+			}
+			if err := restore.SentinelOverrideRestore(override); err != nil {
+				return err
+			}
+
+		case SentinelModuleSnapshot:
+			module := new(structs.SentinelModule)
+			if err := dec.Decode(module); err != nil {
+				return err
+			}
+			if err := restore.SentinelModuleRestore(module); err != nil {
+				return err
+			}
+
 		case ScalingEventsSnapshot:
 			jobScalingEvents := new(structs.JobScalingEvents)
 			if err := dec.Decode(jobScalingEvents); err != nil {
@@ -1702,430 +2459,798 @@ func (n *nomadFSM) applyUpsertScalingEvent(buf []byte, index uint64) interface{}
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-
-	if err := n.state.UpsertScalingEvent(index, &req); err != nil {
-		n.logger.Error("UpsertScalingEvent failed", "error", err)
+
+	if err := n.state.UpsertScalingEvent(index, &req); err != nil {
+		n.logger.Error("UpsertScalingEvent failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "persist"}, time.Now())
+	// Register the nodes
+	encoder := codec.NewEncoder(sink, structs.MsgpackHandle)
+
+	// Write the header
+	header := snapshotHeader{}
+	if err := encoder.Encode(&header); err != nil {
+		sink.Cancel()
+		return err
+	}
+
+	// Write the time table
+	sink.Write([]byte{byte(TimeTableSnapshot)})
+	if err := s.timetable.Serialize(encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+
+	// Write all the data out
+	if err := s.persistIndexes(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistNodes(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistEvals(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistAllocs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistPeriodicLaunches(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSummaries(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVaultAccessors(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistSITokenAccessors(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobVersions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistDeployments(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistScalingPolicies(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistScalingEvents(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistCSIPlugins(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistCSIVolumes(sink, encoder); err != nil {
+		sink.Cancel()
 		return err
 	}
-
-	return nil
-}
-
-func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
-	defer metrics.MeasureSince([]string{"nomad", "fsm", "persist"}, time.Now())
-	// Register the nodes
-	encoder := codec.NewEncoder(sink, structs.MsgpackHandle)
-
-	// Write the header
-	header := snapshotHeader{}
-	if err := encoder.Encode(&header); err != nil {
+	if err := s.persistACLPolicies(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-
-	// Write the time table
-	sink.Write([]byte{byte(TimeTableSnapshot)})
-	if err := s.timetable.Serialize(encoder); err != nil {
+	if err := s.persistACLRoles(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-
-	// Write all the data out
-	if err := s.persistIndexes(sink, encoder); err != nil {
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistNodes(sink, encoder); err != nil {
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistJobs(sink, encoder); err != nil {
+	if err := s.persistSigningKeys(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistEvals(sink, encoder); err != nil {
+	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistAllocs(sink, encoder); err != nil {
+	if err := s.persistVariables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistPeriodicLaunches(sink, encoder); err != nil {
+	if err := s.persistACLTokens(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistJobSummaries(sink, encoder); err != nil {
+	if err := s.persistSentinelPolicies(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistVaultAccessors(sink, encoder); err != nil {
+	if err := s.persistSentinelModules(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistSITokenAccessors(sink, encoder); err != nil {
+	if err := s.persistSentinelOverrides(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistJobVersions(sink, encoder); err != nil {
+	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistDeployments(sink, encoder); err != nil {
+	if err := s.persistSchedulerConfig(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistScalingPolicies(sink, encoder); err != nil {
+	if err := s.persistClusterMetadata(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistScalingEvents(sink, encoder); err != nil {
+	if err := s.persistLicense(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistCSIPlugins(sink, encoder); err != nil {
+	if err := s.persistLicenseEvents(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistCSIVolumes(sink, encoder); err != nil {
+	if err := s.persistFeatureFlags(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistACLPolicies(sink, encoder); err != nil {
+	if err := s.persistFreezeWindows(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistACLTokens(sink, encoder); err != nil {
+	if err := s.persistNamespaces(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
-	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
-		sink.Cancel()
+	return nil
+}
+
+// persistNamespaces persists all the namespaces
+func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	// Get all the namespaces
+	ws := memdb.NewWatchSet()
+	namespaces, err := s.snap.Namespaces(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := namespaces.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		namespace := raw.(*structs.Namespace)
+
+		// Write out a namespace registration
+		sink.Write([]byte{byte(NamespaceSnapshot)})
+		if err := encoder.Encode(namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistIndexes(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the indexes
+	iter, err := s.snap.Indexes()
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		idx := raw.(*state.IndexEntry)
+
+		// Write out a node registration
+		sink.Write([]byte{byte(IndexSnapshot)})
+		if err := encoder.Encode(idx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistNodes(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the nodes
+	ws := memdb.NewWatchSet()
+	nodes, err := s.snap.Nodes(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := nodes.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		node := raw.(*structs.Node)
+
+		// Write out a node registration
+		sink.Write([]byte{byte(NodeSnapshot)})
+		if err := encoder.Encode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the jobs
+	ws := memdb.NewWatchSet()
+	jobs, err := s.snap.Jobs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := jobs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		job := raw.(*structs.Job)
+
+		// Write out a job registration
+		sink.Write([]byte{byte(JobSnapshot)})
+		if err := encoder.Encode(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistEvals(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the evaluations
+	ws := memdb.NewWatchSet()
+	evals, err := s.snap.Evals(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := evals.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		eval := raw.(*structs.Evaluation)
+
+		// Write out the evaluation
+		sink.Write([]byte{byte(EvalSnapshot)})
+		if err := encoder.Encode(eval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistAllocs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the allocations
+	ws := memdb.NewWatchSet()
+	allocs, err := s.snap.Allocs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := allocs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		alloc := raw.(*structs.Allocation)
+
+		// Write out the evaluation
+		sink.Write([]byte{byte(AllocSnapshot)})
+		if err := encoder.Encode(alloc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistPeriodicLaunches(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the jobs
+	ws := memdb.NewWatchSet()
+	launches, err := s.snap.PeriodicLaunches(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := launches.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		launch := raw.(*structs.PeriodicLaunch)
+
+		// Write out a job registration
+		sink.Write([]byte{byte(PeriodicLaunchSnapshot)})
+		if err := encoder.Encode(launch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobSummaries(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	summaries, err := s.snap.JobSummaries(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := summaries.Next()
+		if raw == nil {
+			break
+		}
+
+		jobSummary := raw.(*structs.JobSummary)
+
+		sink.Write([]byte{byte(JobSummarySnapshot)})
+		if err := encoder.Encode(jobSummary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVaultAccessors(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	accessors, err := s.snap.VaultAccessors(ws)
+	if err != nil {
 		return err
 	}
-	if err := s.persistSchedulerConfig(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
+
+	for {
+		raw := accessors.Next()
+		if raw == nil {
+			break
+		}
+
+		accessor := raw.(*structs.VaultAccessor)
+
+		sink.Write([]byte{byte(VaultAccessorSnapshot)})
+		if err := encoder.Encode(accessor); err != nil {
+			return err
+		}
 	}
-	if err := s.persistClusterMetadata(sink, encoder); err != nil {
-		sink.Cancel()
+	return nil
+}
+
+func (s *nomadSnapshot) persistSITokenAccessors(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	accessors, err := s.snap.SITokenAccessors(ws)
+	if err != nil {
 		return err
 	}
+
+	for raw := accessors.Next(); raw != nil; raw = accessors.Next() {
+		accessor := raw.(*structs.SITokenAccessor)
+		sink.Write([]byte{byte(ServiceIdentityTokenAccessorSnapshot)})
+		if err := encoder.Encode(accessor); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistIndexes(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the indexes
-	iter, err := s.snap.Indexes()
+	// Get all the jobs
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.JobVersions(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := iter.Next()
+		raw := versions.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		idx := raw.(*state.IndexEntry)
+		job := raw.(*structs.Job)
 
-		// Write out a node registration
-		sink.Write([]byte{byte(IndexSnapshot)})
-		if err := encoder.Encode(idx); err != nil {
+		// Write out a job registration
+		sink.Write([]byte{byte(JobVersionSnapshot)})
+		if err := encoder.Encode(job); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistNodes(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the nodes
+	// Get all the job submissions
 	ws := memdb.NewWatchSet()
-	nodes, err := s.snap.Nodes(ws)
+	submissions, err := s.snap.JobSubmissions(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := nodes.Next()
+		raw := submissions.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		node := raw.(*structs.Node)
+		sub := raw.(*structs.JobSubmission)
 
-		// Write out a node registration
-		sink.Write([]byte{byte(NodeSnapshot)})
-		if err := encoder.Encode(node); err != nil {
+		// Write out a job submission
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistJobs(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistDeployments(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
 	ws := memdb.NewWatchSet()
-	jobs, err := s.snap.Jobs(ws)
+	deployments, err := s.snap.Deployments(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := jobs.Next()
+		raw := deployments.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		job := raw.(*structs.Job)
+		deployment := raw.(*structs.Deployment)
 
 		// Write out a job registration
-		sink.Write([]byte{byte(JobSnapshot)})
-		if err := encoder.Encode(job); err != nil {
+		sink.Write([]byte{byte(DeploymentSnapshot)})
+		if err := encoder.Encode(deployment); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistEvals(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistACLPolicies(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the evaluations
+	// Get all the policies
 	ws := memdb.NewWatchSet()
-	evals, err := s.snap.Evals(ws)
+	policies, err := s.snap.ACLPolicies(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := evals.Next()
+		raw := policies.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		eval := raw.(*structs.Evaluation)
+		policy := raw.(*structs.ACLPolicy)
 
-		// Write out the evaluation
-		sink.Write([]byte{byte(EvalSnapshot)})
-		if err := encoder.Encode(eval); err != nil {
+		// Write out a policy registration
+		sink.Write([]byte{byte(ACLPolicySnapshot)})
+		if err := encoder.Encode(policy); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistAllocs(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the allocations
+	// Get all the roles
 	ws := memdb.NewWatchSet()
-	allocs, err := s.snap.Allocs(ws)
+	roles, err := s.snap.ACLRoles(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := allocs.Next()
+		raw := roles.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		alloc := raw.(*structs.Allocation)
+		role := raw.(*structs.ACLRole)
 
-		// Write out the evaluation
-		sink.Write([]byte{byte(AllocSnapshot)})
-		if err := encoder.Encode(alloc); err != nil {
+		// Write out a role registration
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(role); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistPeriodicLaunches(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the jobs
+	// Get all the auth methods
 	ws := memdb.NewWatchSet()
-	launches, err := s.snap.PeriodicLaunches(ws)
+	methods, err := s.snap.ACLAuthMethods(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := launches.Next()
+		raw := methods.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		launch := raw.(*structs.PeriodicLaunch)
+		method := raw.(*structs.ACLAuthMethod)
 
-		// Write out a job registration
-		sink.Write([]byte{byte(PeriodicLaunchSnapshot)})
-		if err := encoder.Encode(launch); err != nil {
+		// Write out an auth method registration
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistJobSummaries(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-
+	// Get all the binding rules
 	ws := memdb.NewWatchSet()
-	summaries, err := s.snap.JobSummaries(ws)
+	rules, err := s.snap.ACLBindingRules(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
-		raw := summaries.Next()
+		// Get the next item
+		raw := rules.Next()
 		if raw == nil {
 			break
 		}
 
-		jobSummary := raw.(*structs.JobSummary)
+		// Prepare the request struct
+		rule := raw.(*structs.ACLBindingRule)
 
-		sink.Write([]byte{byte(JobSummarySnapshot)})
-		if err := encoder.Encode(jobSummary); err != nil {
+		// Write out a binding rule registration
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistVaultAccessors(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistSigningKeys(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
+	// Get all the signing keys
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.SigningKeys(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := keys.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		key := raw.(*structs.SigningKey)
+
+		// Write out a signing key registration
+		sink.Write([]byte{byte(SigningKeySnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the service registrations
 	ws := memdb.NewWatchSet()
-	accessors, err := s.snap.VaultAccessors(ws)
+	services, err := s.snap.ServiceRegistrations(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
-		raw := accessors.Next()
+		// Get the next item
+		raw := services.Next()
 		if raw == nil {
 			break
 		}
 
-		accessor := raw.(*structs.VaultAccessor)
+		// Prepare the request struct
+		service := raw.(*structs.ServiceRegistration)
 
-		sink.Write([]byte{byte(VaultAccessorSnapshot)})
-		if err := encoder.Encode(accessor); err != nil {
+		// Write out a service registration
+		sink.Write([]byte{byte(ServiceRegistrationSnapshot)})
+		if err := encoder.Encode(service); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistSITokenAccessors(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the variables
 	ws := memdb.NewWatchSet()
-	accessors, err := s.snap.SITokenAccessors(ws)
+	variables, err := s.snap.Variables(ws)
 	if err != nil {
 		return err
 	}
 
-	for raw := accessors.Next(); raw != nil; raw = accessors.Next() {
-		accessor := raw.(*structs.SITokenAccessor)
-		sink.Write([]byte{byte(ServiceIdentityTokenAccessorSnapshot)})
-		if err := encoder.Encode(accessor); err != nil {
+	for {
+		// Get the next item
+		raw := variables.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		variable := raw.(*structs.EncryptedVariable)
+
+		// Write out a variable
+		sink.Write([]byte{byte(VariableSnapshot)})
+		if err := encoder.Encode(variable); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistSentinelPolicies(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the jobs
+	// Get all the policies
 	ws := memdb.NewWatchSet()
-	versions, err := s.snap.JobVersions(ws)
+	policies, err := s.snap.SentinelPolicies(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := versions.Next()
+		raw := policies.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		job := raw.(*structs.Job)
+		policy := raw.(*structs.SentinelPolicy)
 
-		// Write out a job registration
-		sink.Write([]byte{byte(JobVersionSnapshot)})
-		if err := encoder.Encode(job); err != nil {
+		// Write out a policy registration
+		sink.Write([]byte{byte(SentinelPolicySnapshot)})
+		if err := encoder.Encode(policy); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistDeployments(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistSentinelOverrides(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the jobs
+	// Get all the overrides
 	ws := memdb.NewWatchSet()
-	deployments, err := s.snap.Deployments(ws)
+	overrides, err := s.snap.SentinelOverrides(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := deployments.Next()
+		raw := overrides.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		deployment := raw.(*structs.Deployment)
+		override := raw.(*structs.SentinelOverride)
 
-		// Write out a job registration
-		sink.Write([]byte{byte(DeploymentSnapshot)})
-		if err := encoder.Encode(deployment); err != nil {
+		// Write out an override record
+		sink.Write([]byte{byte(SentinelOverrideSnapshot)})
+		if err := encoder.Encode(override); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *nomadSnapshot) persistACLPolicies(sink raft.SnapshotSink,
+func (s *nomadSnapshot) persistSentinelModules(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
-	// Get all the policies
+	// Get all the modules
 	ws := memdb.NewWatchSet()
-	policies, err := s.snap.ACLPolicies(ws)
+	modules, err := s.snap.SentinelModules(ws)
 	if err != nil {
 		return err
 	}
 
 	for {
 		// Get the next item
-		raw := policies.Next()
+		raw := modules.Next()
 		if raw == nil {
 			break
 		}
 
 		// Prepare the request struct
-		policy := raw.(*structs.ACLPolicy)
+		module := raw.(*structs.SentinelModule)
 
-		// Write out a policy registration
-		sink.Write([]byte{byte(ACLPolicySnapshot)})
-		if err := encoder.Encode(policy); err != nil {
+		// Write out a module registration
+		sink.Write([]byte{byte(SentinelModuleSnapshot)})
+		if err := encoder.Encode(module); err != nil {
 			return err
 		}
 	}
@@ -2200,6 +3325,99 @@ func (s *nomadSnapshot) persistClusterMetadata(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistLicense(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get the stored license
+	ws := memdb.NewWatchSet()
+	license, err := s.snap.License(ws)
+	if err != nil {
+		return err
+	}
+	if license == nil {
+		return nil
+	}
+
+	// Write out the license
+	sink.Write([]byte{byte(LicenseSnapshot)})
+	if err := encoder.Encode(license); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *nomadSnapshot) persistLicenseEvents(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the license events
+	ws := memdb.NewWatchSet()
+	events, err := s.snap.LicenseEvents(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := events.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		event := raw.(*structs.LicenseEvent)
+
+		// Write out a license event
+		sink.Write([]byte{byte(LicenseEventSnapshot)})
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistFeatureFlags(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get the feature flags
+	_, flags, err := s.snap.FeatureFlags()
+	if err != nil {
+		return err
+	}
+	if flags == nil {
+		return nil
+	}
+
+	// Write out the feature flags
+	sink.Write([]byte{byte(FeatureFlagsSnapshot)})
+	if err := encoder.Encode(flags); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *nomadSnapshot) persistFreezeWindows(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get the freeze windows
+	_, windows, err := s.snap.FreezeWindows()
+	if err != nil {
+		return err
+	}
+	if windows == nil {
+		return nil
+	}
+
+	// Write out the freeze windows
+	sink.Write([]byte{byte(FreezeWindowsSnapshot)})
+	if err := encoder.Encode(windows); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *nomadSnapshot) persistScalingPolicies(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
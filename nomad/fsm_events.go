@@ -0,0 +1,59 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// publishEvents publishes a set of events to the event broker at the given
+// raft index. It is a no-op when the FSM was built without a broker, such
+// as for snapshot restores.
+func (n *nomadFSM) publishEvents(index uint64, events ...structs.Event) {
+	if n.config.EventBroker == nil || len(events) == 0 {
+		return
+	}
+
+	for i := range events {
+		events[i].Index = index
+	}
+	n.config.EventBroker.Publish(&structs.Events{Index: index, Events: events})
+}
+
+// evalStreamEvents builds Eval topic events for a set of upserted
+// evaluations.
+func evalStreamEvents(evals []*structs.Evaluation) []structs.Event {
+	events := make([]structs.Event, 0, len(evals))
+	for _, eval := range evals {
+		if eval == nil {
+			continue
+		}
+		events = append(events, structs.Event{
+			Topic:      structs.TopicEval,
+			Type:       structs.TypeEvalUpdated,
+			Key:        eval.ID,
+			FilterKeys: []string{eval.JobID, eval.DeploymentID},
+			Namespace:  eval.Namespace,
+			Payload:    eval,
+		})
+	}
+	return events
+}
+
+// allocStreamEvents builds Alloc topic events for a set of upserted
+// allocations.
+func allocStreamEvents(allocs []*structs.Allocation) []structs.Event {
+	events := make([]structs.Event, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc == nil {
+			continue
+		}
+		events = append(events, structs.Event{
+			Topic:      structs.TopicAlloc,
+			Type:       structs.TypeAllocUpdated,
+			Key:        alloc.ID,
+			FilterKeys: []string{alloc.JobID, alloc.NodeID, alloc.DeploymentID},
+			Namespace:  alloc.Namespace,
+			Payload:    alloc,
+		})
+	}
+	return events
+}
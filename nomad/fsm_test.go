@@ -354,6 +354,41 @@ func TestFSM_BatchUpdateNodeDrain(t *testing.T) {
 	require.Len(node.Events, 2)
 }
 
+func TestFSM_BatchUpdateNodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	node := mock.Node()
+	req := structs.NodeRegisterRequest{
+		Node: node,
+	}
+	buf, err := structs.Encode(structs.NodeRegisterRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	req2 := structs.NodeBatchMetaUpdateRequest{
+		NodeIDs: []string{node.ID},
+		Meta: map[string]*string{
+			"rack":    helper.StringToPtr("r1"),
+			"pci-dss": nil,
+		},
+	}
+	buf, err = structs.Encode(structs.NodeBatchMetaUpdateRequestType, req2)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Verify the meta was merged
+	node, err = fsm.State().NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal("r1", node.Meta["rack"])
+	require.NotContains(node.Meta, "pci-dss")
+}
+
 func TestFSM_UpdateNodeDrain(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -830,13 +865,17 @@ func TestFSM_DeregisterJob_NoPurge(t *testing.T) {
 		t.Fatalf("job not stopped found!")
 	}
 
-	// Verify it was removed from the periodic runner.
+	// Verify the periodic runner kept the tracked state but stopped the job.
 	tuple := structs.NamespacedID{
 		ID:        job.ID,
 		Namespace: job.Namespace,
 	}
-	if _, ok := fsm.periodicDispatcher.tracked[tuple]; ok {
-		t.Fatal("job not removed from periodic runner")
+	tracked, ok := fsm.periodicDispatcher.tracked[tuple]
+	if !ok {
+		t.Fatal("stop without purge removed the job from the periodic runner")
+	}
+	if !tracked.Stopped() {
+		t.Fatal("tracked periodic job not stopped")
 	}
 
 	// Verify it was removed from the periodic launch table.
@@ -909,12 +948,13 @@ func TestFSM_BatchDeregisterJob(t *testing.T) {
 	require.NotNil(jobOut)
 	require.True(jobOut.Stop)
 
-	// Verify it was removed from the periodic runner.
+	// Verify the periodic runner kept the tracked state but stopped the job.
 	tuple := structs.NamespacedID{
 		ID:        job.ID,
 		Namespace: job.Namespace,
 	}
-	require.NotContains(fsm.periodicDispatcher.tracked, tuple)
+	require.Contains(fsm.periodicDispatcher.tracked, tuple)
+	require.True(fsm.periodicDispatcher.tracked[tuple].Stopped())
 
 	// Verify it was not removed from the periodic launch table.
 	launchOut, err := fsm.State().PeriodicLaunchByID(ws, job.Namespace, job.ID)
@@ -2273,6 +2313,69 @@ func TestFSM_DeleteDeployment(t *testing.T) {
 	}
 }
 
+func TestFSM_UpsertNamespaces(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	req := structs.NamespaceUpsertRequest{
+		Namespaces: []*structs.Namespace{ns1, ns2},
+	}
+	buf, err := structs.Encode(structs.NamespaceUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().NamespaceByName(ws, ns1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+
+	out, err = fsm.State().NamespaceByName(ws, ns2.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	err := fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	assert.Nil(t, err)
+
+	req := structs.NamespaceDeleteRequest{
+		Namespaces: []string{ns1.Name, ns2.Name},
+	}
+	buf, err := structs.Encode(structs.NamespaceDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().NamespaceByName(ws, ns1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+
+	out, err = fsm.State().NamespaceByName(ws, ns2.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
 func TestFSM_UpsertACLPolicies(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2326,6 +2429,246 @@ func TestFSM_DeleteACLPolicies(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func TestFSM_UpsertACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	req := structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+	}
+	buf, err := structs.Encode(structs.ACLRoleUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLRoleByName(ws, role.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	err := fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{role})
+	assert.Nil(t, err)
+
+	req := structs.ACLRoleDeleteRequest{
+		Names: []string{role.Name},
+	}
+	buf, err := structs.Encode(structs.ACLRoleDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLRoleByName(ws, role.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestFSM_UpsertACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	req := structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{method},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLAuthMethodByName(ws, method.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	err := fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method})
+	assert.Nil(t, err)
+
+	req := structs.ACLAuthMethodDeleteRequest{
+		Names: []string{method.Name},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLAuthMethodByName(ws, method.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestFSM_UpsertACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	rule := mock.ACLBindingRule()
+	req := structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRuleUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLBindingRuleByID(ws, rule.ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	rule := mock.ACLBindingRule()
+	err := fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule})
+	assert.Nil(t, err)
+
+	req := structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{rule.ID},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRuleDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().ACLBindingRuleByID(ws, rule.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestFSM_UpsertSigningKeys(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	key := mock.SigningKey()
+	req := structs.SigningKeyUpsertRequest{
+		Keys: []*structs.SigningKey{key},
+	}
+	buf, err := structs.Encode(structs.SigningKeyUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().SigningKeyByID(ws, key.KeyID)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_UpsertVariables(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	variable := mock.EncryptedVariable()
+	req := structs.VariablesEncryptedUpsertRequest{
+		Variables: []*structs.EncryptedVariable{variable},
+	}
+	buf, err := structs.Encode(structs.VariablesUpsertRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify the variable is stored
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetVariable(ws, variable.Namespace, variable.Path)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestFSM_DeleteVariable(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	variable := mock.EncryptedVariable()
+	err := fsm.State().UpsertVariables(1000, []*structs.EncryptedVariable{variable})
+	assert.Nil(t, err)
+
+	req := structs.VariablesDeleteRequest{
+		Path: variable.Path,
+		WriteRequest: structs.WriteRequest{
+			Namespace: variable.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.VariablesDeleteRequestType, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	resp := fsm.Apply(makeLog(buf))
+	if resp != nil {
+		t.Fatalf("resp: %v", resp)
+	}
+
+	// Verify the variable is removed
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetVariable(ws, variable.Namespace, variable.Path)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
 func TestFSM_BootstrapACLTokens(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2797,6 +3140,121 @@ func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	assert.Equal(t, p2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLRole(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	state.UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLRoleByName(ws, r1.Name)
+	out2, _ := state2.ACLRoleByName(ws, r2.Name)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLAuthMethod(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{am1, am2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLAuthMethodByName(ws, am1.Name)
+	out2, _ := state2.ACLAuthMethodByName(ws, am2.Name)
+	assert.Equal(t, am1, out1)
+	assert.Equal(t, am2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLBindingRule(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	br1 := mock.ACLBindingRule()
+	br2 := mock.ACLBindingRule()
+	state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{br1, br2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.ACLBindingRuleByID(ws, br1.ID)
+	out2, _ := state2.ACLBindingRuleByID(ws, br2.ID)
+	assert.Equal(t, br1, out1)
+	assert.Equal(t, br2, out2)
+}
+
+func TestFSM_SnapshotRestore_SigningKeys(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	k1 := mock.SigningKey()
+	k2 := mock.SigningKey()
+	k2.Active = false
+	state.UpsertSigningKeys(1000, []*structs.SigningKey{k1, k2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.SigningKeyByID(ws, k1.KeyID)
+	out2, _ := state2.SigningKeyByID(ws, k2.KeyID)
+	assert.Equal(t, k1, out1)
+	assert.Equal(t, k2, out2)
+}
+
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	v1 := mock.EncryptedVariable()
+	v2 := mock.EncryptedVariable()
+	state.UpsertVariables(1000, []*structs.EncryptedVariable{v1, v2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.GetVariable(ws, v1.Namespace, v1.Path)
+	out2, _ := state2.GetVariable(ws, v2.Namespace, v2.Path)
+	assert.Equal(t, v1, out1)
+	assert.Equal(t, v2, out2)
+}
+
+func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.NamespaceByName(ws, ns1.Name)
+	out2, _ := state2.NamespaceByName(ws, ns2.Name)
+	assert.Equal(t, ns1, out1)
+	assert.Equal(t, ns2, out2)
+}
+
 func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -3176,3 +3634,66 @@ func TestFSM_ClusterMetadata(t *testing.T) {
 	r.Equal(clusterID, storedMetadata.ClusterID)
 	r.Equal(now, storedMetadata.CreateTime)
 }
+
+func TestFSM_UpsertServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	service := mock.ServiceRegistration()
+	req := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{service},
+	}
+	buf, err := structs.Encode(structs.ServiceRegistrationUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify the registration was stored
+	out, err := fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(service.ServiceName, out.ServiceName)
+}
+
+func TestFSM_DeleteServiceRegistrationByID(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	service := mock.ServiceRegistration()
+	require.NoError(fsm.State().UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service}))
+
+	req := structs.ServiceRegistrationDeleteByIDRequest{ID: service.ID}
+	buf, err := structs.Encode(structs.ServiceRegistrationDeleteByIDRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify the registration is gone
+	out, err := fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	service1 := mock.ServiceRegistration()
+	service2 := mock.ServiceRegistration()
+	require.NoError(state.UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service1, service2}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, err := state2.ServiceRegistrationByID(nil, service1.ID)
+	require.NoError(err)
+	out2, err := state2.ServiceRegistrationByID(nil, service2.ID)
+	require.NoError(err)
+	require.Equal(service1, out1)
+	require.Equal(service2, out2)
+}
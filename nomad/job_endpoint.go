@@ -29,9 +29,16 @@ const (
 	// enforcing the job modify index during registers.
 	RegisterEnforceIndexErrPrefix = "Enforcing job modify index"
 
-	// DispatchPayloadSizeLimit is the maximum size of the uncompressed input
-	// data payload.
+	// DispatchPayloadSizeLimit is the default maximum size of the
+	// uncompressed input data payload. Operators can raise it with the
+	// dispatch_payload_size_limit server option.
 	DispatchPayloadSizeLimit = 16 * 1024
+
+	// DispatchPayloadChunkSize is the maximum size of the payload committed
+	// in a single raft entry. Payloads larger than this are appended to the
+	// registered job as a series of chunks so a single dispatch cannot
+	// produce an oversized raft entry.
+	DispatchPayloadChunkSize = 256 * 1024
 )
 
 var (
@@ -59,6 +66,7 @@ func NewJobEndpoints(s *Server) *Job {
 		srv:    s,
 		logger: s.logger.Named("job"),
 		mutators: []jobMutator{
+			jobDefaults{srv: s},
 			jobCanonicalizer{},
 			jobConnectHook{},
 			jobExposeCheckHook{},
@@ -67,6 +75,7 @@ func NewJobEndpoints(s *Server) *Job {
 		validators: []jobValidator{
 			jobConnectHook{},
 			jobExposeCheckHook{},
+			jobOwnershipValidate{srv: s},
 			jobValidate{},
 		},
 	}
@@ -169,6 +178,23 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			}
 			j.logger.Warn("policy override set for job", "job", args.Job.ID)
 		}
+
+		// Overriding a change-freeze window requires operator write access
+		if args.FreezeOverride && !aclObj.AllowOperatorWrite() {
+			j.logger.Warn("freeze override attempted without permissions for job", "job", args.Job.ID)
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Reject the registration during an active change-freeze window unless
+	// the request overrides the freeze
+	if _, windows, err := j.srv.State().FreezeWindows(); err != nil {
+		return err
+	} else if w := windows.ActiveWindow(time.Now(), args.RequestNamespace()); w != nil {
+		if !args.FreezeOverride {
+			return fmt.Errorf("job registrations in namespace %q are rejected by change-freeze window %q", args.RequestNamespace(), w.Name)
+		}
+		j.logger.Warn("freeze override set for job", "job", args.Job.ID, "freeze_window", w.Name)
 	}
 
 	// Lookup the job
@@ -303,6 +329,17 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 	}
 
+	// Record which soft-mandatory policies the override applies to so the
+	// submission can be audited later
+	args.SentinelOverride = nil
+	if args.PolicyOverride {
+		override, err := j.sentinelOverrideRecord(args)
+		if err != nil {
+			return err
+		}
+		args.SentinelOverride = override
+	}
+
 	// Clear the Vault token
 	args.Job.VaultToken = ""
 
@@ -322,8 +359,9 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
-	// Submit a multiregion job to other regions (enterprise only).
-	// The job will have its region interpolated.
+	// Submit a multiregion job to other regions (gated by the
+	// multiregion-deployments feature flag). The job will have its region
+	// interpolated.
 	var existingVersion uint64
 	if existingJob != nil {
 		existingVersion = existingJob.Version
@@ -357,6 +395,14 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		reply.EvalID = eval.ID
 	}
 
+	// Drop the submitted source material if it exceeds the size cap rather
+	// than failing the registration; the source is informational only.
+	if args.Submission != nil && len(args.Submission.Source) > structs.JobMaxSourceSize {
+		j.logger.Warn("job source exceeds maximum size, dropping submission",
+			"job", args.Job.NamespacedID(), "size", len(args.Submission.Source))
+		args.Submission = nil
+	}
+
 	// Check if the job has changed at all
 	if existingJob == nil || existingJob.SpecChanged(args.Job) {
 
@@ -423,7 +469,8 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		reply.Index = evalIndex
 	}
 
-	// Kick off a multiregion deployment (enterprise only).
+	// Kick off a multiregion deployment (gated by the
+	// multiregion-deployments feature flag).
 	if isRunner {
 		err = j.multiregionStart(args, reply)
 		if err != nil {
@@ -478,6 +525,48 @@ func getSignalConstraint(signals []string) *structs.Constraint {
 	}
 }
 
+// sentinelOverrideRecord builds the audit record for a registration that
+// overrides soft-mandatory Sentinel policies. It returns nil if no
+// soft-mandatory policies are in scope for the submission.
+func (j *Job) sentinelOverrideRecord(args *structs.JobRegisterRequest) (*structs.SentinelOverride, error) {
+	iter, err := j.srv.State().SentinelPoliciesByScope(nil, structs.SentinelScopeSubmitJob)
+	if err != nil {
+		return nil, err
+	}
+
+	var overridden []string
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		policy := raw.(*structs.SentinelPolicy)
+		if policy.EnforcementLevel == structs.SentinelEnforcementLevelSoftMandatory {
+			overridden = append(overridden, policy.Name)
+		}
+	}
+	if len(overridden) == 0 {
+		return nil, nil
+	}
+	sort.Strings(overridden)
+
+	// Capture the submitter if ACLs are enabled
+	var accessor string
+	if token, err := j.srv.ResolveSecretToken(args.AuthToken); err != nil {
+		return nil, err
+	} else if token != nil {
+		accessor = token.AccessorID
+	}
+
+	return &structs.SentinelOverride{
+		Policies:   overridden,
+		JobID:      args.Job.ID,
+		Namespace:  args.RequestNamespace(),
+		AccessorID: accessor,
+		Time:       time.Now().UnixNano(),
+	}, nil
+}
+
 // Summary retrieves the summary of a job
 func (j *Job) Summary(args *structs.JobSummaryRequest,
 	reply *structs.JobSummaryResponse) error {
@@ -683,6 +772,136 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	return nil
 }
 
+// Suspend is used to suspend or resume a job. Suspending scales every task
+// group to zero while the job stays registered; resuming restores the counts
+// from the most recent unsuspended version so the job comes back exactly as
+// it was parked.
+func (j *Job) Suspend(args *structs.JobSuspendRequest, reply *structs.JobRegisterResponse) error {
+	if done, err := j.srv.forward("Job.Suspend", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "suspend"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for suspending")
+	}
+
+	// Lookup the job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ws := memdb.NewWatchSet()
+	job, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(404, fmt.Sprintf("job %q not found", args.JobID))
+	}
+
+	// Suspending only makes sense for jobs whose task groups have counts
+	if job.Type == structs.JobTypeSystem {
+		return structs.NewErrRPCCoded(400, "system jobs cannot be suspended")
+	}
+	if job.IsPeriodic() || job.IsParameterized() {
+		return structs.NewErrRPCCoded(400, "periodic and parameterized jobs cannot be suspended")
+	}
+
+	updated := job.Copy()
+	if args.Suspend {
+		if job.Suspended {
+			return structs.NewErrRPCCoded(400, fmt.Sprintf("job %q is already suspended", args.JobID))
+		}
+
+		updated.Suspended = true
+		for _, tg := range updated.TaskGroups {
+			tg.Count = 0
+		}
+	} else {
+		if !job.Suspended {
+			return structs.NewErrRPCCoded(400, fmt.Sprintf("job %q is not suspended", args.JobID))
+		}
+
+		// Restore the counts from the most recent unsuspended version
+		versions, err := snap.JobVersionsByID(ws, args.RequestNamespace(), args.JobID)
+		if err != nil {
+			return err
+		}
+		var prior *structs.Job
+		for _, version := range versions {
+			if !version.Suspended {
+				prior = version
+				break
+			}
+		}
+		if prior == nil {
+			return fmt.Errorf("job %q has no unsuspended version to resume from", args.JobID)
+		}
+
+		updated.Suspended = false
+		for _, tg := range updated.TaskGroups {
+			if prev := prior.LookupTaskGroup(tg.Name); prev != nil {
+				tg.Count = prev.Count
+			}
+		}
+	}
+
+	// Commit the updated job via Raft
+	registerReq := structs.JobRegisterRequest{
+		Job:            updated,
+		EnforceIndex:   true,
+		JobModifyIndex: job.ModifyIndex,
+		WriteRequest:   args.WriteRequest,
+	}
+	_, jobModifyIndex, err := j.srv.raftApply(structs.JobRegisterRequestType, registerReq)
+	if err != nil {
+		j.logger.Error("job register for suspend failed", "error", err)
+		return err
+	}
+	reply.JobModifyIndex = jobModifyIndex
+
+	// Create an eval to act on the count changes
+	now := time.Now().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      args.RequestNamespace(),
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    structs.EvalTriggerJobSuspend,
+		JobID:          args.JobID,
+		JobModifyIndex: reply.JobModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+	update := &structs.EvalUpdateRequest{
+		Evals:        []*structs.Evaluation{eval},
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+
+	// Commit this evaluation via Raft
+	_, evalIndex, err := j.srv.raftApply(structs.EvalUpdateRequestType, update)
+	if err != nil {
+		j.logger.Error("eval create failed", "error", err, "method", "suspend")
+		return err
+	}
+
+	reply.EvalID = eval.ID
+	reply.EvalCreateIndex = evalIndex
+	reply.Index = evalIndex
+	j.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 // Evaluate is used to force a job for re-evaluation
 func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Evaluate", args, args, reply); done {
@@ -1259,6 +1478,144 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to retrieve the original source material of a
+// job version, if it was recorded at registration time.
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the submission
+			out, err := state.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+			if out != nil {
+				reply.Index = out.JobModifyIndex
+			} else {
+				// Use the last index that affected the submissions table
+				index, err := state.Index("job_submission")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// Diff computes the structured diff between two stored versions of a job, or
+// between the selected local version and the same job in another federated
+// region.
+func (j *Job) Diff(args *structs.JobDiffRequest, reply *structs.JobDiffResponse) error {
+	if done, err := j.srv.forward("Job.Diff", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "diff"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Fetch the remote job first when comparing across regions so the
+	// cross-region RPC happens before the local snapshot is taken.
+	var remote *structs.Job
+	if args.CompareRegion != "" && args.CompareRegion != j.srv.Region() {
+		remoteArgs := &structs.JobSpecificRequest{
+			JobID: args.JobID,
+			QueryOptions: structs.QueryOptions{
+				Region:    args.CompareRegion,
+				Namespace: args.RequestNamespace(),
+				AuthToken: args.AuthToken,
+			},
+		}
+		var remoteReply structs.SingleJobResponse
+		if err := j.srv.forwardRegion(args.CompareRegion, "Job.GetJob", remoteArgs, &remoteReply); err != nil {
+			return err
+		}
+		if remoteReply.Job == nil {
+			return fmt.Errorf("job %q not found in region %q", args.JobID, args.CompareRegion)
+		}
+		remote = remoteReply.Job
+	}
+
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ws := memdb.NewWatchSet()
+
+	// versionFor resolves a stored version of the job, defaulting to the
+	// current version when none is given.
+	versionFor := func(version *uint64) (*structs.Job, error) {
+		if version == nil {
+			return snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+		}
+		return snap.JobByIDAndVersion(ws, args.RequestNamespace(), args.JobID, *version)
+	}
+
+	from, err := versionFor(args.FromVersion)
+	if err != nil {
+		return err
+	}
+	if from == nil {
+		return fmt.Errorf("job %q not found", args.JobID)
+	}
+
+	to := remote
+	if to == nil {
+		to, err = versionFor(args.ToVersion)
+		if err != nil {
+			return err
+		}
+		if to == nil {
+			return fmt.Errorf("job %q not found", args.JobID)
+		}
+	}
+
+	diff, err := from.Diff(to, true)
+	if err != nil {
+		return fmt.Errorf("failed to create job diff: %v", err)
+	}
+
+	reply.Diff = diff
+	reply.FromVersion = from.Version
+	reply.ToVersion = to.Version
+
+	// Use the last index that affected the jobs table
+	index, err := snap.Index("jobs")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	j.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 // allowedNSes returns a set (as map of ns->true) of the namespaces a token has access to.
 // Returns `nil` set if the token has access to all namespaces
 // and ErrPermissionDenied if the token has no capabilities on any namespace.
@@ -1324,18 +1681,28 @@ func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse)
 			}
 
 			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				job := raw.(*structs.Job)
-				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
-				}
-				jobs = append(jobs, job.Stub(summary))
+			paginator, err := newListPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Job).ID
+				},
+				func(raw interface{}) error {
+					job := raw.(*structs.Job)
+					summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
+					if err != nil {
+						return fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
+					jobs = append(jobs, job.Stub(summary))
+					return nil
+				})
+			if err != nil {
+				return err
 			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
+			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Jobs = jobs
 
 			// Use the last index that affected the jobs table or summary
@@ -1382,35 +1749,55 @@ func (j *Job) listAllNamespaces(args *structs.JobListRequest, reply *structs.Job
 			}
 
 			// Capture all the jobs
-			iter, err := state.Jobs(ws)
+			rawIter, err := state.Jobs(ws)
 
 			if err != nil {
 				return err
 			}
 
-			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
+			// Drop jobs the token is not permitted to see or that do not
+			// match the prefix before paginating.
+			iter := memdb.NewFilterIterator(rawIter, func(raw interface{}) bool {
 				job := raw.(*structs.Job)
 				if allowedNSes != nil && !allowedNSes[job.Namespace] {
 					// not permitted to this name namespace
-					continue
+					return true
 				}
 				if prefix != "" && !strings.HasPrefix(job.ID, prefix) {
-					continue
-				}
-				summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					return true
 				}
+				return false
+			})
+
+			var jobs []*structs.JobListStub
+			paginator, err := newListPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					job := raw.(*structs.Job)
+					// Jobs iterate in namespace then ID order, so the
+					// paging token covers both.
+					return job.Namespace + "." + job.ID
+				},
+				func(raw interface{}) error {
+					job := raw.(*structs.Job)
+					summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
+					if err != nil {
+						return fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
 
-				stub := job.Stub(summary)
-				stub.Namespace = job.Namespace
-				jobs = append(jobs, stub)
+					stub := job.Stub(summary)
+					stub.Namespace = job.Namespace
+					jobs = append(jobs, stub)
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Jobs = jobs
 
 			// Use the last index that affected the jobs table or summary
@@ -1468,7 +1855,7 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 			if len(allocs) > 0 {
 				reply.Allocations = make([]*structs.AllocListStub, 0, len(allocs))
 				for _, alloc := range allocs {
-					reply.Allocations = append(reply.Allocations, alloc.Stub())
+					reply.Allocations = append(reply.Allocations, alloc.Stub(nil))
 				}
 			}
 
@@ -1514,6 +1901,30 @@ func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 				return err
 			}
 
+			// Annotate blocked evaluations with an approximate queue
+			// position and earliest start hint so callers can judge how long
+			// the job may wait for capacity. The evaluations are copied
+			// since the hints are transient.
+			for i, eval := range reply.Evaluations {
+				if eval.Status != structs.EvalStatusBlocked {
+					continue
+				}
+				position := j.srv.blockedEvals.QueuePosition(eval)
+				if position == 0 {
+					continue
+				}
+
+				hint, err := earliestStartHint(state, time.Now())
+				if err != nil {
+					return err
+				}
+
+				annotated := eval.Copy()
+				annotated.QueuePosition = position
+				annotated.EarliestStart = hint
+				reply.Evaluations[i] = annotated
+			}
+
 			// Use the last index that affected the evals table
 			index, err := state.Index("evals")
 			if err != nil {
@@ -1529,6 +1940,45 @@ func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// earliestStartHint returns an approximate earliest time at which capacity
+// could be freed for a blocked evaluation, based on allocations that are
+// marked for termination but have not yet stopped. The latest an allocation
+// should stop is its kill timeout from now. The zero time is returned when no
+// terminations are pending.
+func earliestStartHint(state *state.StateStore, now time.Time) (time.Time, error) {
+	iter, err := state.Allocs(nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var hint time.Time
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+
+		// Skip allocations that aren't pending termination
+		if alloc.DesiredStatus == structs.AllocDesiredStatusRun || alloc.ClientTerminalStatus() {
+			continue
+		}
+
+		timeout := structs.DefaultKillTimeout
+		if alloc.Job != nil {
+			if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+				for _, task := range tg.Tasks {
+					if task.KillTimeout > timeout {
+						timeout = task.KillTimeout
+					}
+				}
+			}
+		}
+
+		stop := now.Add(timeout)
+		if hint.IsZero() || stop.Before(hint) {
+			hint = stop
+		}
+	}
+	return hint, nil
+}
+
 // Deployments is used to list the deployments for a job
 func (j *Job) Deployments(args *structs.JobSpecificRequest,
 	reply *structs.DeploymentListResponse) error {
@@ -1865,8 +2315,12 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		return fmt.Errorf("Specified job %q is stopped", args.JobID)
 	}
 
-	// Validate the arguments
-	if err := validateDispatchRequest(args, parameterizedJob); err != nil {
+	// Validate the arguments against the configured payload limit
+	limit := j.srv.config.DispatchPayloadSizeLimit
+	if limit <= 0 {
+		limit = DispatchPayloadSizeLimit
+	}
+	if err := validateDispatchRequest(args, parameterizedJob, limit); err != nil {
 		return err
 	}
 
@@ -1886,8 +2340,23 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		dispatchJob.Meta[k] = v
 	}
 
-	// Compress the payload
-	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
+	// Compress the payload. Payloads small enough for a single raft entry
+	// are committed with the job register; larger ones are appended to the
+	// registered job in chunks so no single raft entry is oversized.
+	compressed := snappy.Encode(nil, args.Payload)
+	var chunks [][]byte
+	if len(compressed) <= DispatchPayloadChunkSize {
+		dispatchJob.Payload = compressed
+	} else {
+		for len(compressed) > 0 {
+			l := len(compressed)
+			if l > DispatchPayloadChunkSize {
+				l = DispatchPayloadChunkSize
+			}
+			chunks = append(chunks, compressed[:l])
+			compressed = compressed[l:]
+		}
+	}
 
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
@@ -1909,6 +2378,26 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	reply.DispatchedJobID = dispatchJob.ID
 	reply.Index = jobCreateIndex
 
+	// Append any remaining payload chunks. The evaluation is only created
+	// once the full payload is committed.
+	for _, chunk := range chunks {
+		appendReq := &structs.JobPayloadAppendRequest{
+			JobID:        dispatchJob.ID,
+			Chunk:        chunk,
+			WriteRequest: args.WriteRequest,
+		}
+		fsmErr, appendIndex, err := j.srv.raftApply(structs.JobPayloadAppendRequestType, appendReq)
+		if err, ok := fsmErr.(error); ok && err != nil {
+			j.logger.Error("dispatched job payload append failed", "error", err, "fsm", true)
+			return err
+		}
+		if err != nil {
+			j.logger.Error("dispatched job payload append failed", "error", err, "raft", true)
+			return err
+		}
+		reply.Index = appendIndex
+	}
+
 	// If the job is periodic, we don't create an eval.
 	if !dispatchJob.IsPeriodic() {
 		// Create a new evaluation
@@ -1948,25 +2437,25 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 
 // validateDispatchRequest returns whether the request is valid given the
 // parameterized job.
-func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job) error {
+func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job, payloadSizeLimit int) error {
 	// Check the payload constraint is met
 	hasInputData := len(req.Payload) != 0
 	if job.ParameterizedJob.Payload == structs.DispatchPayloadRequired && !hasInputData {
-		return fmt.Errorf("Payload is not provided but required by parameterized job")
+		return structs.NewErrRPCCoded(400, "Payload is not provided but required by parameterized job")
 	} else if job.ParameterizedJob.Payload == structs.DispatchPayloadForbidden && hasInputData {
-		return fmt.Errorf("Payload provided but forbidden by parameterized job")
+		return structs.NewErrRPCCoded(400, "Payload provided but forbidden by parameterized job")
 	}
 
 	// Check the payload doesn't exceed the size limit
-	if l := len(req.Payload); l > DispatchPayloadSizeLimit {
-		return fmt.Errorf("Payload exceeds maximum size; %d > %d", l, DispatchPayloadSizeLimit)
+	if l := len(req.Payload); l > payloadSizeLimit {
+		return structs.NewErrRPCCodedf(400, "Payload exceeds maximum size; %d > %d", l, payloadSizeLimit)
 	}
 
 	// Check if the metadata is a set
 	keys := make(map[string]struct{}, len(req.Meta))
 	for k := range req.Meta {
 		if _, ok := keys[k]; ok {
-			return fmt.Errorf("Duplicate key %q in passed metadata", k)
+			return structs.NewErrRPCCodedf(400, "Duplicate key %q in passed metadata", k)
 		}
 		keys[k] = struct{}{}
 	}
@@ -1990,7 +2479,7 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 			flat = append(flat, k)
 		}
 
-		return fmt.Errorf("Dispatch request included unpermitted metadata keys: %v", flat)
+		return structs.NewErrRPCCodedf(400, "Dispatch request included unpermitted metadata keys: %v", flat)
 	}
 
 	missing := make(map[string]struct{})
@@ -2006,7 +2495,7 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 			flat = append(flat, k)
 		}
 
-		return fmt.Errorf("Dispatch did not provide required meta keys: %v", flat)
+		return structs.NewErrRPCCodedf(400, "Dispatch did not provide required meta keys: %v", flat)
 	}
 
 	return nil
@@ -139,7 +139,10 @@ func hasGatewayTaskForService(tg *structs.TaskGroup, svc string) bool {
 	for _, t := range tg.Tasks {
 		switch {
 		case isIngressGatewayForService(t, svc):
-			// also terminating and mesh in the future
+			return true
+		case isTerminatingGatewayForService(t, svc):
+			return true
+		case isMeshGatewayForService(t, svc):
 			return true
 		}
 	}
@@ -150,6 +153,14 @@ func isIngressGatewayForService(t *structs.Task, svc string) bool {
 	return t.Kind == structs.NewTaskKind(structs.ConnectIngressPrefix, svc)
 }
 
+func isTerminatingGatewayForService(t *structs.Task, svc string) bool {
+	return t.Kind == structs.NewTaskKind(structs.ConnectTerminatingPrefix, svc)
+}
+
+func isMeshGatewayForService(t *structs.Task, svc string) bool {
+	return t.Kind == structs.NewTaskKind(structs.ConnectMeshPrefix, svc)
+}
+
 // getNamedTaskForNativeService retrieves the Task with the name specified in the
 // group service definition. If the task name is empty and there is only one task
 // in the group, infer the name from the only option.
@@ -233,7 +244,7 @@ func groupConnectHook(job *structs.Job, g *structs.TaskGroup) error {
 
 		case service.Connect.IsGateway():
 			netHost := g.Networks[0].Mode == "host"
-			if !netHost && service.Connect.Gateway.Ingress != nil {
+			if !netHost {
 				// Modify the gateway proxy service configuration to automatically
 				// do the correct envoy bind address plumbing when inside a net
 				// namespace, but only if things are not explicitly configured.
@@ -243,7 +254,7 @@ func groupConnectHook(job *structs.Job, g *structs.TaskGroup) error {
 			// inject the gateway task only if it does not yet already exist
 			if !hasGatewayTaskForService(g, service.Name) {
 				// use the default envoy image, for now there is no support for a custom task
-				task := newConnectGatewayTask(service.Name, netHost)
+				task := newConnectGatewayTask(service.Connect.Gateway.Prefix(), service.Name, netHost)
 				g.Tasks = append(g.Tasks, task)
 				task.Canonicalize(job, g)
 			}
@@ -299,12 +310,44 @@ func gatewayProxyForBridge(gateway *structs.ConsulGateway) *structs.ConsulGatewa
 	// magically set the fields where Nomad knows what to do
 	proxy.EnvoyGatewayNoDefaultBind = true
 	proxy.EnvoyGatewayBindTaggedAddresses = false
-	proxy.EnvoyGatewayBindAddresses = gatewayBindAddresses(gateway.Ingress)
+	proxy.EnvoyGatewayBindAddresses = gatewayBindAddresses(gateway)
 
 	return proxy
 }
 
-func gatewayBindAddresses(ingress *structs.ConsulIngressConfigEntry) map[string]*structs.ConsulGatewayBindAddress {
+func gatewayBindAddresses(gateway *structs.ConsulGateway) map[string]*structs.ConsulGatewayBindAddress {
+	switch {
+	case gateway.Ingress != nil:
+		return gatewayBindAddressesIngress(gateway.Ingress)
+
+	case gateway.Terminating != nil:
+		// A terminating gateway has one listener, serving every linked service.
+		return map[string]*structs.ConsulGatewayBindAddress{
+			"default": {
+				Address: "0.0.0.0",
+				Port:    -1, // filled in later with dynamic port
+			},
+		}
+
+	case gateway.Mesh != nil:
+		// A mesh gateway exposes one listener on the local network and one
+		// reachable from other datacenters.
+		return map[string]*structs.ConsulGatewayBindAddress{
+			"wan": {
+				Address: "0.0.0.0",
+				Port:    -1, // filled in later with configured port
+			},
+			"lan": {
+				Address: "0.0.0.0",
+				Port:    -1, // filled in later with dynamic port
+			},
+		}
+	}
+
+	return nil
+}
+
+func gatewayBindAddressesIngress(ingress *structs.ConsulIngressConfigEntry) map[string]*structs.ConsulGatewayBindAddress {
 	if ingress == nil || len(ingress.Listeners) == 0 {
 		return nil
 	}
@@ -322,11 +365,11 @@ func gatewayBindAddresses(ingress *structs.ConsulIngressConfigEntry) map[string]
 	return addresses
 }
 
-func newConnectGatewayTask(serviceName string, netHost bool) *structs.Task {
+func newConnectGatewayTask(prefix, serviceName string, netHost bool) *structs.Task {
 	return &structs.Task{
 		// Name is used in container name so must start with '[A-Za-z0-9]'
-		Name:          fmt.Sprintf("%s-%s", structs.ConnectIngressPrefix, serviceName),
-		Kind:          structs.NewTaskKind(structs.ConnectIngressPrefix, serviceName),
+		Name:          fmt.Sprintf("%s-%s", prefix, serviceName),
+		Kind:          structs.NewTaskKind(prefix, serviceName),
 		Driver:        "docker",
 		Config:        connectGatewayDriverConfig(netHost),
 		ShutdownDelay: 5 * time.Second,
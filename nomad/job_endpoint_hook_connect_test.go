@@ -123,7 +123,7 @@ func TestJobEndpointConnect_groupConnectHook_IngressGateway(t *testing.T) {
 	expTG := job.TaskGroups[0].Copy()
 	expTG.Tasks = []*structs.Task{
 		// inject the gateway task
-		newConnectGatewayTask(expTG.Services[0].Name, false),
+		newConnectGatewayTask(structs.ConnectIngressPrefix, expTG.Services[0].Name, false),
 	}
 	expTG.Tasks[0].Canonicalize(job, expTG)
 	expTG.Networks[0].Canonicalize()
@@ -252,7 +252,7 @@ func TestJobEndpointConnect_groupConnectGatewayValidate(t *testing.T) {
 }
 
 func TestJobEndpointConnect_newConnectGatewayTask_host(t *testing.T) {
-	task := newConnectGatewayTask("service1", true)
+	task := newConnectGatewayTask(structs.ConnectIngressPrefix, "service1", true)
 	require.Equal(t, "connect-ingress-service1", task.Name)
 	require.Equal(t, "connect-ingress:service1", string(task.Kind))
 	require.Equal(t, ">= 1.8.0", task.Constraints[0].RTarget)
@@ -261,7 +261,7 @@ func TestJobEndpointConnect_newConnectGatewayTask_host(t *testing.T) {
 }
 
 func TestJobEndpointConnect_newConnectGatewayTask_bridge(t *testing.T) {
-	task := newConnectGatewayTask("service1", false)
+	task := newConnectGatewayTask(structs.ConnectIngressPrefix, "service1", false)
 	require.NotContains(t, task.Config, "network_mode")
 }
 
@@ -335,17 +335,17 @@ func TestJobEndpointConnect_gatewayProxyIsDefault(t *testing.T) {
 
 func TestJobEndpointConnect_gatewayBindAddresses(t *testing.T) {
 	t.Run("nil", func(t *testing.T) {
-		result := gatewayBindAddresses(nil)
+		result := gatewayBindAddressesIngress(nil)
 		require.Nil(t, result)
 	})
 
 	t.Run("no listeners", func(t *testing.T) {
-		result := gatewayBindAddresses(&structs.ConsulIngressConfigEntry{Listeners: nil})
+		result := gatewayBindAddressesIngress(&structs.ConsulIngressConfigEntry{Listeners: nil})
 		require.Nil(t, result)
 	})
 
 	t.Run("simple", func(t *testing.T) {
-		result := gatewayBindAddresses(&structs.ConsulIngressConfigEntry{
+		result := gatewayBindAddressesIngress(&structs.ConsulIngressConfigEntry{
 			Listeners: []*structs.ConsulIngressListener{{
 				Port:     3000,
 				Protocol: "tcp",
@@ -363,7 +363,7 @@ func TestJobEndpointConnect_gatewayBindAddresses(t *testing.T) {
 	})
 
 	t.Run("complex", func(t *testing.T) {
-		result := gatewayBindAddresses(&structs.ConsulIngressConfigEntry{
+		result := gatewayBindAddressesIngress(&structs.ConsulIngressConfigEntry{
 			Listeners: []*structs.ConsulIngressListener{{
 				Port:     3000,
 				Protocol: "tcp",
@@ -479,3 +479,75 @@ func TestJobEndpointConnect_gatewayProxyForBridge(t *testing.T) {
 		}, result)
 	})
 }
+
+func TestJobEndpointConnect_gatewayBindAddresses_gateways(t *testing.T) {
+	t.Run("terminating", func(t *testing.T) {
+		result := gatewayBindAddresses(&structs.ConsulGateway{
+			Terminating: &structs.ConsulTerminatingConfigEntry{
+				Services: []*structs.ConsulLinkedService{{
+					Name: "service1",
+				}},
+			},
+		})
+		require.Equal(t, map[string]*structs.ConsulGatewayBindAddress{
+			"default": {
+				Address: "0.0.0.0",
+				Port:    -1,
+			},
+		}, result)
+	})
+
+	t.Run("mesh", func(t *testing.T) {
+		result := gatewayBindAddresses(&structs.ConsulGateway{
+			Mesh: &structs.ConsulMeshConfigEntry{},
+		})
+		require.Equal(t, map[string]*structs.ConsulGatewayBindAddress{
+			"wan": {
+				Address: "0.0.0.0",
+				Port:    -1,
+			},
+			"lan": {
+				Address: "0.0.0.0",
+				Port:    -1,
+			},
+		}, result)
+	})
+}
+
+func TestJobEndpointConnect_newConnectGatewayTask_kinds(t *testing.T) {
+	t.Run("terminating", func(t *testing.T) {
+		task := newConnectGatewayTask(structs.ConnectTerminatingPrefix, "service1", false)
+		require.Equal(t, "connect-terminating-service1", task.Name)
+		require.Equal(t, "connect-terminating:service1", string(task.Kind))
+	})
+
+	t.Run("mesh", func(t *testing.T) {
+		task := newConnectGatewayTask(structs.ConnectMeshPrefix, "service1", true)
+		require.Equal(t, "connect-mesh-service1", task.Name)
+		require.Equal(t, "connect-mesh:service1", string(task.Kind))
+	})
+}
+
+func TestJobEndpointConnect_hasGatewayTaskForService_kinds(t *testing.T) {
+	t.Run("has terminating task", func(t *testing.T) {
+		result := hasGatewayTaskForService(&structs.TaskGroup{
+			Name: "group",
+			Tasks: []*structs.Task{{
+				Name: "task1",
+				Kind: structs.NewTaskKind(structs.ConnectTerminatingPrefix, "my-service"),
+			}},
+		}, "my-service")
+		require.True(t, result)
+	})
+
+	t.Run("has mesh task", func(t *testing.T) {
+		result := hasGatewayTaskForService(&structs.TaskGroup{
+			Name: "group",
+			Tasks: []*structs.Task{{
+				Name: "task1",
+				Kind: structs.NewTaskKind(structs.ConnectMeshPrefix, "my-service"),
+			}},
+		}, "my-service")
+		require.True(t, result)
+	})
+}
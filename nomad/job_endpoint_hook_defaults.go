@@ -0,0 +1,127 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// jobDefaults merges operator-configured default job values into task groups
+// that do not specify them. It runs before the canonicalizer so that the
+// configured values take the place of the built-in defaults, and as the
+// merged values are persisted with the job they are visible in job inspect.
+// Namespace-level defaults attach to this hook in Nomad Enterprise.
+type jobDefaults struct {
+	srv *Server
+}
+
+func (jobDefaults) Name() string {
+	return "defaults"
+}
+
+func (j jobDefaults) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if j.srv.config == nil || j.srv.config.JobDefaults == nil {
+		return job, nil, nil
+	}
+	defaults := j.srv.config.JobDefaults
+
+	for _, tg := range job.TaskGroups {
+		if defaults.Restart != nil && tg.RestartPolicy == nil {
+			tg.RestartPolicy = defaultRestartPolicy(job.Type, defaults.Restart)
+		}
+		if defaults.Reschedule != nil && tg.ReschedulePolicy == nil {
+			tg.ReschedulePolicy = defaultReschedulePolicy(job.Type, defaults.Reschedule)
+		}
+		if defaults.Update != nil && tg.Update == nil && job.Type == structs.JobTypeService {
+			tg.Update = defaultUpdateStrategy(defaults.Update)
+		}
+	}
+
+	return job, nil, nil
+}
+
+// defaultRestartPolicy returns the built-in restart policy for the job type
+// with the configured values layered on top.
+func defaultRestartPolicy(jobType string, d *config.RestartDefaults) *structs.RestartPolicy {
+	rp := structs.NewRestartPolicy(jobType)
+	if rp == nil {
+		return nil
+	}
+
+	if d.Attempts != nil {
+		rp.Attempts = *d.Attempts
+	}
+	if d.Interval != 0 {
+		rp.Interval = d.Interval
+	}
+	if d.Delay != 0 {
+		rp.Delay = d.Delay
+	}
+	if d.Mode != "" {
+		rp.Mode = d.Mode
+	}
+	return rp
+}
+
+// defaultReschedulePolicy returns the built-in reschedule policy for the job
+// type with the configured values layered on top.
+func defaultReschedulePolicy(jobType string, d *config.RescheduleDefaults) *structs.ReschedulePolicy {
+	rp := structs.NewReschedulePolicy(jobType)
+	if rp == nil {
+		return nil
+	}
+
+	if d.Attempts != nil {
+		rp.Attempts = *d.Attempts
+	}
+	if d.Interval != 0 {
+		rp.Interval = d.Interval
+	}
+	if d.Delay != 0 {
+		rp.Delay = d.Delay
+	}
+	if d.DelayFunction != "" {
+		rp.DelayFunction = d.DelayFunction
+	}
+	if d.MaxDelay != 0 {
+		rp.MaxDelay = d.MaxDelay
+	}
+	if d.Unlimited != nil {
+		rp.Unlimited = *d.Unlimited
+	}
+	return rp
+}
+
+// defaultUpdateStrategy returns the built-in update strategy with the
+// configured values layered on top.
+func defaultUpdateStrategy(d *config.UpdateDefaults) *structs.UpdateStrategy {
+	u := structs.DefaultUpdateStrategy.Copy()
+
+	if d.Stagger != 0 {
+		u.Stagger = d.Stagger
+	}
+	if d.MaxParallel != nil {
+		u.MaxParallel = *d.MaxParallel
+	}
+	if d.HealthCheck != "" {
+		u.HealthCheck = d.HealthCheck
+	}
+	if d.MinHealthyTime != 0 {
+		u.MinHealthyTime = d.MinHealthyTime
+	}
+	if d.HealthyDeadline != 0 {
+		u.HealthyDeadline = d.HealthyDeadline
+	}
+	if d.ProgressDeadline != 0 {
+		u.ProgressDeadline = d.ProgressDeadline
+	}
+	if d.AutoRevert != nil {
+		u.AutoRevert = *d.AutoRevert
+	}
+	if d.AutoPromote != nil {
+		u.AutoPromote = *d.AutoPromote
+	}
+	if d.Canary != nil {
+		u.Canary = *d.Canary
+	}
+	return u
+}
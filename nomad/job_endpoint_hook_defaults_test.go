@@ -0,0 +1,113 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEndpointHook_Defaults(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	hook := jobDefaults{srv: &Server{
+		config: &Config{
+			JobDefaults: &config.JobDefaults{
+				Restart: &config.RestartDefaults{
+					Attempts: helper.IntToPtr(5),
+					Mode:     structs.RestartPolicyModeDelay,
+				},
+				Reschedule: &config.RescheduleDefaults{
+					Delay: 1 * time.Minute,
+				},
+				Update: &config.UpdateDefaults{
+					MaxParallel: helper.IntToPtr(2),
+					AutoRevert:  helper.BoolToPtr(true),
+				},
+			},
+		},
+	}}
+
+	job := mock.Job()
+	job.TaskGroups[0].RestartPolicy = nil
+	job.TaskGroups[0].ReschedulePolicy = nil
+	job.TaskGroups[0].Update = nil
+
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(err)
+	require.Empty(warnings)
+
+	// The configured values override the built-in defaults, and unset
+	// fields fall back to them
+	tg := out.TaskGroups[0]
+	require.Equal(5, tg.RestartPolicy.Attempts)
+	require.Equal(structs.RestartPolicyModeDelay, tg.RestartPolicy.Mode)
+	require.Equal(structs.DefaultServiceJobRestartPolicy.Interval, tg.RestartPolicy.Interval)
+
+	require.Equal(1*time.Minute, tg.ReschedulePolicy.Delay)
+	require.Equal(structs.DefaultServiceJobReschedulePolicy.DelayFunction, tg.ReschedulePolicy.DelayFunction)
+
+	require.Equal(2, tg.Update.MaxParallel)
+	require.True(tg.Update.AutoRevert)
+	require.Equal(structs.DefaultUpdateStrategy.HealthyDeadline, tg.Update.HealthyDeadline)
+}
+
+func TestJobEndpointHook_Defaults_Specified(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	hook := jobDefaults{srv: &Server{
+		config: &Config{
+			JobDefaults: &config.JobDefaults{
+				Restart: &config.RestartDefaults{
+					Attempts: helper.IntToPtr(5),
+				},
+				Update: &config.UpdateDefaults{
+					MaxParallel: helper.IntToPtr(2),
+				},
+			},
+		},
+	}}
+
+	// Policies specified by the job are left alone
+	job := mock.Job()
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 7
+	restart := job.TaskGroups[0].RestartPolicy.Copy()
+	update := job.TaskGroups[0].Update.Copy()
+
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(err)
+	require.Empty(warnings)
+	require.Equal(restart, out.TaskGroups[0].RestartPolicy)
+	require.Equal(update, out.TaskGroups[0].Update)
+
+	// Batch jobs do not get an update strategy
+	batch := mock.BatchJob()
+	batch.TaskGroups[0].Update = nil
+
+	out, warnings, err = hook.Mutate(batch)
+	require.NoError(err)
+	require.Empty(warnings)
+	require.Nil(out.TaskGroups[0].Update)
+}
+
+func TestJobEndpointHook_Defaults_Unconfigured(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	hook := jobDefaults{srv: &Server{config: &Config{}}}
+
+	job := mock.Job()
+	job.TaskGroups[0].RestartPolicy = nil
+
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(err)
+	require.Empty(warnings)
+	require.Nil(out.TaskGroups[0].RestartPolicy)
+}
@@ -0,0 +1,41 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobOwnershipValidate enforces namespace ownership requirements. When a
+// namespace requires job ownership, jobs registered in it must name the
+// owning team and a contact so that on-call operators can find the owners
+// of a failing job without archaeology.
+type jobOwnershipValidate struct {
+	srv *Server
+}
+
+func (jobOwnershipValidate) Name() string {
+	return "ownership"
+}
+
+func (j jobOwnershipValidate) Validate(job *structs.Job) ([]error, error) {
+	ns, err := j.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil || !ns.RequireJobOwnership {
+		return nil, nil
+	}
+
+	if job.Ownership == nil {
+		return nil, fmt.Errorf("namespace %q requires an ownership stanza", job.Namespace)
+	}
+	if job.Ownership.Team == "" {
+		return nil, fmt.Errorf("namespace %q requires ownership to name a team", job.Namespace)
+	}
+	if job.Ownership.Contact == "" {
+		return nil, fmt.Errorf("namespace %q requires ownership to name a contact", job.Namespace)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEndpointHook_Ownership(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	hook := jobOwnershipValidate{srv: s1}
+
+	// The default namespace does not require ownership.
+	job := mock.Job()
+	warnings, err := hook.Validate(job)
+	require.NoError(err)
+	require.Empty(warnings)
+
+	// Require ownership in the namespace.
+	ns := &structs.Namespace{
+		Name:                "strict",
+		RequireJobOwnership: true,
+	}
+	ns.SetHash()
+	require.NoError(s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	job.Namespace = ns.Name
+
+	// A job without an ownership stanza is rejected.
+	_, err = hook.Validate(job)
+	require.Error(err)
+	require.Contains(err.Error(), "requires an ownership stanza")
+
+	// Team and contact are both required.
+	job.Ownership = &structs.JobOwnership{Team: "platform"}
+	_, err = hook.Validate(job)
+	require.Error(err)
+	require.Contains(err.Error(), "name a contact")
+
+	job.Ownership.Contact = "platform-oncall@example.com"
+	warnings, err = hook.Validate(job)
+	require.NoError(err)
+	require.Empty(warnings)
+}
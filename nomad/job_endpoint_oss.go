@@ -5,41 +5,317 @@ package nomad
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/structs"
 	vapi "github.com/hashicorp/vault/api"
 )
 
+const (
+	// multiregionRunAttempts and multiregionRunInterval bound how long the
+	// runner region waits for the scheduler in a peer region to create the
+	// deployment that starts the first wave of a multiregion rollout.
+	multiregionRunAttempts = 20
+	multiregionRunInterval = 3 * time.Second
+)
+
 // enforceSubmitJob is used to check any Sentinel policies for the submit-job scope
 func (j *Job) enforceSubmitJob(override bool, job *structs.Job) (error, error) {
 	return nil, nil
 }
 
-// multiregionRegister is used to send a job across multiple regions
+// multiregionEnabled returns whether the multiregion deployment machinery
+// has been switched on via the multiregion-deployments feature flag.
+func (j *Job) multiregionEnabled() (bool, error) {
+	_, flags, err := j.srv.fsm.State().FeatureFlags()
+	if err != nil {
+		return false, err
+	}
+	return flags.FeatureEnabled(structs.FeatureMultiregionDeployments), nil
+}
+
+// interpolateMultiregionJob returns a copy of a multiregion job with the
+// given region's fields folded in. Task groups with a count of zero take the
+// region's count, and the region's datacenters and meta override the job's.
+func interpolateMultiregionJob(job *structs.Job, region *structs.MultiregionRegion) *structs.Job {
+	interpolated := job.Copy()
+	interpolated.Region = region.Name
+
+	for _, tg := range interpolated.TaskGroups {
+		if tg.Count == 0 && region.Count > 0 {
+			tg.Count = region.Count
+		}
+	}
+	if len(region.Datacenters) > 0 {
+		interpolated.Datacenters = region.Datacenters
+	}
+	if len(region.Meta) > 0 {
+		if interpolated.Meta == nil {
+			interpolated.Meta = make(map[string]string, len(region.Meta))
+		}
+		for k, v := range region.Meta {
+			interpolated.Meta[k] = v
+		}
+	}
+
+	return interpolated
+}
+
+// multiregionRegister is used to send a job across multiple regions. The
+// returned boolean reports whether this region is the runner responsible for
+// kicking off the rollout once every region has registered the job.
 func (j *Job) multiregionRegister(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse, existingVersion uint64) (bool, error) {
-	return false, nil
+	if !args.Job.IsMultiregion() {
+		return false, nil
+	}
+
+	enabled, err := j.multiregionEnabled()
+	if err != nil {
+		return false, err
+	}
+	if !enabled {
+		return false, fmt.Errorf("multiregion deployments are disabled; enable the %q feature flag to use them",
+			structs.FeatureMultiregionDeployments)
+	}
+
+	// Fan-out copies arrive with their region already interpolated, so only
+	// the region that received the original submission distributes the job.
+	if args.Job.Region != "global" {
+		return false, nil
+	}
+
+	var local *structs.MultiregionRegion
+	for _, region := range args.Job.Multiregion.Regions {
+		if region.Name == j.srv.Region() {
+			local = region
+			continue
+		}
+
+		remote := *args
+		remote.Job = interpolateMultiregionJob(args.Job, region)
+		remote.Region = region.Name
+		// Job modify indexes are not comparable across regions
+		remote.EnforceIndex = false
+		remote.JobModifyIndex = 0
+
+		var remoteReply structs.JobRegisterResponse
+		if err := j.srv.RPC("Job.Register", &remote, &remoteReply); err != nil {
+			return false, fmt.Errorf("could not register job in region %q: %v", region.Name, err)
+		}
+	}
+
+	if local == nil {
+		return false, fmt.Errorf("could not register job: region %q is not part of the multiregion block",
+			j.srv.Region())
+	}
+
+	// Interpolate the local copy in place so that the caller registers the
+	// region-specific version of the job.
+	args.Job = interpolateMultiregionJob(args.Job, local)
+	return true, nil
 }
 
-// multiregionStart is used to kick-off a deployment across multiple regions
+// multiregionStart is used to kick off the deployment across multiple
+// regions. The scheduler creates multiregion deployments in the pending
+// state, so the runner has to start the first max_parallel regions of the
+// rollout; the deployment watcher starts later regions as earlier ones
+// finish.
 func (j *Job) multiregionStart(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	if !args.Job.IsMultiregion() {
+		return nil
+	}
+
+	// Periodic and parameterized jobs don't create deployments
+	if args.Job.IsPeriodic() || args.Job.IsParameterized() {
+		return nil
+	}
+
+	maxParallel := 0
+	if args.Job.Multiregion.Strategy != nil {
+		maxParallel = args.Job.Multiregion.Strategy.MaxParallel
+	}
+
+	var wave []string
+	for i, region := range args.Job.Multiregion.Regions {
+		if maxParallel != 0 && i >= maxParallel {
+			break
+		}
+		wave = append(wave, region.Name)
+	}
+
+	// The deployments are created asynchronously by the scheduler in each
+	// region, so they are started in the background rather than holding up
+	// the registration.
+	go j.multiregionRunDeployments(args.RequestNamespace(), args.Job.ID, args.AuthToken, wave)
 	return nil
 }
 
-// multiregionDrop is used to deregister regions from a previous version of the
-// job that are no longer in use
+// multiregionRunDeployments moves the latest deployment for the job in each
+// of the given regions from pending to running, retrying until the
+// scheduler has had a chance to create it.
+func (j *Job) multiregionRunDeployments(namespace, jobID, authToken string, regions []string) {
+	remaining := make(map[string]struct{}, len(regions))
+	for _, region := range regions {
+		remaining[region] = struct{}{}
+	}
+
+	for attempt := 0; attempt < multiregionRunAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-j.srv.shutdownCh:
+				return
+			case <-time.After(multiregionRunInterval):
+			}
+		}
+
+		for region := range remaining {
+			get := &structs.JobSpecificRequest{
+				JobID: jobID,
+				QueryOptions: structs.QueryOptions{
+					Region:    region,
+					Namespace: namespace,
+					AuthToken: authToken,
+				},
+			}
+			var latest structs.SingleDeploymentResponse
+			if err := j.srv.RPC("Job.LatestDeployment", get, &latest); err != nil {
+				j.logger.Error("failed to look up multiregion deployment",
+					"job", jobID, "region", region, "error", err)
+				continue
+			}
+			if latest.Deployment == nil {
+				continue
+			}
+			if latest.Deployment.Status != structs.DeploymentStatusPending {
+				// Already started, or superseded by a newer registration
+				delete(remaining, region)
+				continue
+			}
+
+			run := &structs.DeploymentRunRequest{
+				DeploymentID: latest.Deployment.ID,
+				WriteRequest: structs.WriteRequest{
+					Region:    region,
+					Namespace: namespace,
+					AuthToken: authToken,
+				},
+			}
+			var runReply structs.DeploymentUpdateResponse
+			if err := j.srv.RPC("Deployment.Run", run, &runReply); err != nil {
+				j.logger.Error("failed to run multiregion deployment",
+					"job", jobID, "region", region, "error", err)
+				continue
+			}
+			delete(remaining, region)
+		}
+
+		if len(remaining) == 0 {
+			return
+		}
+	}
+
+	for region := range remaining {
+		j.logger.Warn("gave up waiting for multiregion deployment",
+			"job", jobID, "region", region)
+	}
+}
+
+// multiregionDrop is used to deregister the job from any regions that were
+// dropped from the multiregion block by this registration.
 func (j *Job) multiregionDrop(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	if !args.Job.IsMultiregion() {
+		return nil
+	}
+
+	// Compare the region set against the previous version of the job.
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ws := memdb.NewWatchSet()
+	versions, err := snap.JobVersionsByID(ws, args.RequestNamespace(), args.Job.ID)
+	if err != nil {
+		return err
+	}
+	if len(versions) < 2 {
+		return nil
+	}
+	previous := versions[1]
+	if !previous.IsMultiregion() {
+		return nil
+	}
+
+	current := make(map[string]struct{}, len(args.Job.Multiregion.Regions))
+	for _, region := range args.Job.Multiregion.Regions {
+		current[region.Name] = struct{}{}
+	}
+
+	for _, region := range previous.Multiregion.Regions {
+		if _, ok := current[region.Name]; ok {
+			continue
+		}
+
+		dereg := &structs.JobDeregisterRequest{
+			JobID: args.Job.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: args.RequestNamespace(),
+				AuthToken: args.AuthToken,
+			},
+		}
+		var deregReply structs.JobDeregisterResponse
+		if err := j.srv.RPC("Job.Deregister", dereg, &deregReply); err != nil {
+			return fmt.Errorf("could not deregister job in dropped region %q: %v", region.Name, err)
+		}
+	}
+
 	return nil
 }
 
 // multiregionStop is used to fan-out Job.Deregister RPCs to all regions if
 // the global flag is passed to Job.Deregister
 func (j *Job) multiregionStop(job *structs.Job, args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
+	if job == nil || !job.IsMultiregion() || !args.Global {
+		return nil
+	}
+
+	for _, region := range job.Multiregion.Regions {
+		if region.Name == j.srv.Region() {
+			continue
+		}
+
+		remote := *args
+		remote.Region = region.Name
+		// The peer deregisters only its own copy of the job
+		remote.Global = false
+		remote.Eval = nil
+
+		var remoteReply structs.JobDeregisterResponse
+		if err := j.srv.RPC("Job.Deregister", &remote, &remoteReply); err != nil {
+			return fmt.Errorf("could not deregister job in region %q: %v", region.Name, err)
+		}
+	}
+
 	return nil
 }
 
-// interpolateMultiregionFields interpolates a job for a specific region
+// interpolateMultiregionFields interpolates a job for the region handling
+// the plan so that the plan reflects the region's count, datacenters, and
+// meta.
 func (j *Job) interpolateMultiregionFields(args *structs.JobPlanRequest) error {
-	return nil
+	if !args.Job.IsMultiregion() {
+		return nil
+	}
+
+	for _, region := range args.Job.Multiregion.Regions {
+		if region.Name == j.srv.Region() {
+			args.Job = interpolateMultiregionJob(args.Job, region)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("could not plan job: region %q is not part of the multiregion block", j.srv.Region())
 }
 
 // multiVaultNamespaceValidation provides a convience check to ensure
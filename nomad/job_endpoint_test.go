@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	crand "crypto/rand"
 	"errors"
 	"fmt"
 	"reflect"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/raft"
@@ -169,6 +171,81 @@ func TestJobEndpoint_Register_PreserveCounts(t *testing.T) {
 	require.Equal(2, out.TaskGroups[1].Count)  // should be as in job spec
 }
 
+func TestJobEndpoint_Register_Multiregion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	west, cleanupWest := TestServer(t, func(c *Config) {
+		c.Region = "west"
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupWest()
+
+	east, cleanupEast := TestServer(t, func(c *Config) {
+		c.Region = "east"
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupEast()
+
+	TestJoin(t, west, east)
+	testutil.WaitForLeader(t, west.RPC)
+	testutil.WaitForLeader(t, east.RPC)
+	codec := rpcClient(t, west)
+
+	// Wait for the federation to be established
+	testutil.WaitForResult(func() (bool, error) {
+		return len(west.Regions()) == 2, nil
+	}, func(err error) {
+		t.Fatalf("timed out waiting for federation")
+	})
+
+	// Create the register request. The group count of zero takes the
+	// region-specific count.
+	job := mock.MultiregionJob()
+	job.TaskGroups[0].Count = 0
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "west",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Multiregion deployments are gated behind a feature flag
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "multiregion deployments are disabled")
+
+	// Enable the feature flag in both regions
+	require.NoError(west.fsm.State().FeatureFlagsSet(1000, &structs.FeatureFlags{
+		Flags: map[string]bool{structs.FeatureMultiregionDeployments: true},
+	}))
+	require.NoError(east.fsm.State().FeatureFlagsSet(1000, &structs.FeatureFlags{
+		Flags: map[string]bool{structs.FeatureMultiregionDeployments: true},
+	}))
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// The job is interpolated for the receiving region...
+	out, err := west.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("west", out.Region)
+	require.Equal([]string{"west-1", "west-2"}, out.Datacenters)
+	require.Equal("W", out.Meta["region_code"])
+	require.Equal(2, out.TaskGroups[0].Count)
+
+	// ...and fanned out to the peer region
+	out, err = east.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("east", out.Region)
+	require.Equal([]string{"east-1"}, out.Datacenters)
+	require.Equal("E", out.Meta["region_code"])
+	require.Equal(1, out.TaskGroups[0].Count)
+}
+
 func TestJobEndpoint_Register_Connect(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -737,6 +814,49 @@ func TestJobEndpoint_Register_Connect_AllowUnauthenticatedFalse(t *testing.T) {
 	})
 }
 
+func TestJobEndpoint_Register_SentinelOverride(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Store a soft-mandatory policy
+	policy := mock.SentinelPolicy()
+	policy.EnforcementLevel = structs.SentinelEnforcementLevelSoftMandatory
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertSentinelPolicies(900, []*structs.SentinelPolicy{policy}))
+
+	// Register with an override
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job:            job,
+		PolicyOverride: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Check the override was recorded for auditing
+	iter, err := state.SentinelOverrides(nil)
+	require.NoError(t, err)
+
+	raw := iter.Next()
+	require.NotNil(t, raw)
+	override := raw.(*structs.SentinelOverride)
+	require.Equal(t, []string{policy.Name}, override.Policies)
+	require.Equal(t, job.ID, override.JobID)
+	require.Equal(t, job.Namespace, override.Namespace)
+	require.NotZero(t, override.Time)
+	require.Nil(t, iter.Next())
+}
+
 func TestJobEndpoint_Register_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -4022,6 +4142,71 @@ func TestJobEndpoint_GetJobVersions(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	require := require.New(t)
+
+	// Register a job with its original source attached
+	job := mock.Job()
+	source := fmt.Sprintf("job %q {\n  priority = 88\n}\n", job.ID)
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:        source,
+			Format:        "hcl2",
+			VariableFlags: map[string]string{"priority": "88"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// Lookup the submission for version 0
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var subResp structs.JobSubmissionResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(subResp.Submission)
+	require.Equal(source, subResp.Submission.Source)
+	require.Equal("hcl2", subResp.Submission.Format)
+	require.Equal(map[string]string{"priority": "88"}, subResp.Submission.VariableFlags)
+	require.Equal(job.ID, subResp.Submission.JobID)
+	require.EqualValues(0, subResp.Submission.Version)
+
+	// A version without a stored submission returns nil
+	get.Version = 10
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.Nil(subResp.Submission)
+
+	// Oversized sources are dropped rather than failing registration
+	reg.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("x", structs.JobMaxSourceSize+1),
+		Format: "hcl2",
+	}
+	reg.Job = job.Copy()
+	reg.Job.Priority = 100
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	get.Version = 1
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.Nil(subResp.Submission)
+}
+
 func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -4577,9 +4762,63 @@ func TestJobEndpoint_ListJobs(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ListJobs_Pagination(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create jobs with ordered IDs.
+	state := s1.fsm.State()
+	ids := []string{"job-a", "job-b", "job-c"}
+	for i, id := range ids {
+		job := mock.Job()
+		job.ID = id
+		if i == 2 {
+			job.Priority = 90
+		}
+		require.NoError(t, state.UpsertJob(uint64(1000+i), job))
+	}
+
+	// Fetch the first page.
+	get := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   2,
+		},
+	}
+	var resp structs.JobListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+	require.Len(t, resp.Jobs, 2)
+	require.Equal(t, ids[2], resp.NextToken)
+
+	// Fetch the second and last page.
+	get.QueryOptions.NextToken = resp.NextToken
+	var resp2 structs.JobListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp2))
+	require.Len(t, resp2.Jobs, 1)
+	require.Equal(t, ids[2], resp2.Jobs[0].ID)
+	require.Equal(t, "", resp2.NextToken)
+
+	// Fetch with a filter expression.
+	get = &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			Filter:    "Priority == 90",
+		},
+	}
+	var resp3 structs.JobListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp3))
+	require.Len(t, resp3.Jobs, 1)
+	require.Equal(t, ids[2], resp3.Jobs[0].ID)
+}
+
 // TestJobEndpoint_ListJobs_AllNamespaces_OSS asserts that server
 // returns all jobs across namespace.
-//
 func TestJobEndpoint_ListJobs_AllNamespaces_OSS(t *testing.T) {
 	t.Parallel()
 
@@ -6275,6 +6514,109 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Suspend(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	require.Nil(state.UpsertJob(1000, job))
+
+	// Suspend the job
+	suspend := &structs.JobSuspendRequest{
+		JobID:   job.ID,
+		Suspend: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Suspend", suspend, &resp))
+	require.NotEmpty(resp.EvalID)
+
+	// The job should be suspended with all counts zeroed
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(out.Suspended)
+	require.Zero(out.TaskGroups[0].Count)
+
+	// Suspending again is an error
+	err = msgpackrpc.CallWithCodec(codec, "Job.Suspend", suspend, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "already suspended")
+
+	// Resume the job
+	resume := &structs.JobSuspendRequest{
+		JobID:   job.ID,
+		Suspend: false,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Suspend", resume, &resp))
+	require.NotEmpty(resp.EvalID)
+
+	// The prior count should be restored
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.False(out.Suspended)
+	require.Equal(3, out.TaskGroups[0].Count)
+
+	// Resuming an unsuspended job is an error
+	err = msgpackrpc.CallWithCodec(codec, "Job.Suspend", resume, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not suspended")
+}
+
+func TestJobEndpoint_Suspend_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.Nil(state.UpsertJob(1000, job))
+
+	suspend := &structs.JobSuspendRequest{
+		JobID:   job.ID,
+		Suspend: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Suspend without a token should fail
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Suspend", suspend, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	suspend.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.Suspend", suspend, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Fetching with a management token should succeed
+	suspend.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Suspend", suspend, &resp))
+}
+
 func TestJobEndpoint_Scale(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -6927,3 +7269,215 @@ func TestJobEndpoint_GetScaleStatus_ACL(t *testing.T) {
 		require.NotNil(validResp.JobScaleStatus)
 	}
 }
+
+func TestJobEndpoint_Diff(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register two versions of a job
+	job := mock.Job()
+	job.Priority = 88
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	job.Priority = 100
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// Diff the two stored versions
+	get := &structs.JobDiffRequest{
+		JobID:       job.ID,
+		FromVersion: helper.Uint64ToPtr(0),
+		ToVersion:   helper.Uint64ToPtr(1),
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var diffResp structs.JobDiffResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Diff", get, &diffResp))
+
+	require.Equal(uint64(0), diffResp.FromVersion)
+	require.Equal(uint64(1), diffResp.ToVersion)
+	require.NotNil(diffResp.Diff)
+	require.Equal(structs.DiffTypeEdited, diffResp.Diff.Type)
+
+	// Nil versions default to the current version, yielding no differences
+	get.FromVersion = nil
+	get.ToVersion = nil
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Diff", get, &diffResp))
+	require.Equal(uint64(1), diffResp.FromVersion)
+	require.Equal(uint64(1), diffResp.ToVersion)
+	require.Equal(structs.DiffTypeNone, diffResp.Diff.Type)
+
+	// A missing version is an error
+	get.ToVersion = helper.Uint64ToPtr(9)
+	err := msgpackrpc.CallWithCodec(codec, "Job.Diff", get, &diffResp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
+func TestJobEndpoint_Diff_MultiRegion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.Region = "foo"
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.Region = "bar"
+	})
+	defer cleanupS2()
+
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Register the same job in both regions with a different priority
+	job := mock.Job()
+	job.Priority = 88
+	for region, priority := range map[string]int{"foo": 88, "bar": 100} {
+		regional := job.Copy()
+		regional.Priority = priority
+		regional.Region = region
+		reg := &structs.JobRegisterRequest{
+			Job: regional,
+			WriteRequest: structs.WriteRequest{
+				Region:    region,
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(msgpackrpc.CallWithCodec(rpcClient(t, s1), "Job.Register", reg, &resp))
+	}
+
+	// Diff the local job against its counterpart in the other region
+	get := &structs.JobDiffRequest{
+		JobID:         job.ID,
+		CompareRegion: "bar",
+		QueryOptions: structs.QueryOptions{
+			Region:    "foo",
+			Namespace: job.Namespace,
+		},
+	}
+	var diffResp structs.JobDiffResponse
+	require.NoError(msgpackrpc.CallWithCodec(rpcClient(t, s1), "Job.Diff", get, &diffResp))
+
+	require.NotNil(diffResp.Diff)
+	require.Equal(structs.DiffTypeEdited, diffResp.Diff.Type)
+
+	// The job is missing from a region it was never registered in
+	get.JobID = "nonexistent"
+	err := msgpackrpc.CallWithCodec(rpcClient(t, s1), "Job.Diff", get, &diffResp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found in region")
+}
+
+func TestJobEndpoint_Dispatch_PayloadChunks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.DispatchPayloadSizeLimit = 2 * 1024 * 1024
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Payload: structs.DispatchPayloadOptional,
+	}
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	// Random data does not compress, so this compresses to well over the
+	// chunk size and must be committed as multiple raft entries
+	payload := make([]byte, 3*DispatchPayloadChunkSize)
+	_, err := crand.Read(payload)
+	require.NoError(err)
+
+	dispatch := &structs.JobDispatchRequest{
+		JobID:   job.ID,
+		Payload: payload,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobDispatchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", dispatch, &resp))
+	require.NotEmpty(resp.DispatchedJobID)
+	require.NotEmpty(resp.EvalID)
+
+	// The full payload round-trips through the chunked raft entries
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	decoded, err := snappy.Decode(nil, out.Payload)
+	require.NoError(err)
+	require.Equal(payload, decoded)
+}
+
+func TestJobEndpoint_Dispatch_PayloadSizeLimitConfig(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.DispatchPayloadSizeLimit = 1024
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Payload: structs.DispatchPayloadOptional,
+	}
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	dispatch := &structs.JobDispatchRequest{
+		JobID:   job.ID,
+		Payload: make([]byte, 2048),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobDispatchResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Dispatch", dispatch, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "Payload exceeds maximum size")
+}
@@ -0,0 +1,274 @@
+package nomad
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// signingKeyBits is the size of the RSA keys used to sign workload
+	// identity tokens.
+	signingKeyBits = 2048
+
+	// encryptionKeyBytes is the size of the symmetric data encryption keys
+	// used to seal variables, suitable for AES-256.
+	encryptionKeyBytes = 32
+)
+
+// generateSigningKey returns a new active signing key for the workload
+// identity keyring.
+func generateSigningKey() (*structs.SigningKey, error) {
+	privKey, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privKey),
+	})
+	return &structs.SigningKey{
+		KeyID:         uuid.Generate(),
+		RSAPrivateKey: keyPEM,
+		Active:        true,
+		CreateTime:    time.Now().UnixNano(),
+	}, nil
+}
+
+// generateEncryptionKey returns a new symmetric data encryption key for
+// sealing variables. Unlike the signing key it belongs to, the encryption
+// key is never written to raft; it is stored in the server's local keystore
+// and distributed to peers out-of-band.
+func generateEncryptionKey() ([]byte, error) {
+	key := make([]byte, encryptionKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %v", err)
+	}
+	return key, nil
+}
+
+// getOrCreateSigningKey is used to get the active signing key, initializing
+// the keyring if necessary. It is called when a server becomes leader.
+func (s *Server) getOrCreateSigningKey() *structs.SigningKey {
+	state := s.fsm.State()
+	key, err := state.ActiveSigningKey(nil)
+	if err != nil {
+		s.logger.Named("core").Error("failed to get active signing key", "error", err)
+		return nil
+	}
+	if key != nil {
+		return key
+	}
+
+	key, err = generateSigningKey()
+	if err != nil {
+		s.logger.Named("core").Error("failed to generate signing key", "error", err)
+		return nil
+	}
+
+	// Store the key's encryption key material in the local keystore before
+	// the keyring entry that names it is committed to raft; peers replicate
+	// it from here out-of-band.
+	encKey, err := generateEncryptionKey()
+	if err != nil {
+		s.logger.Named("core").Error("failed to generate encryption key", "error", err)
+		return nil
+	}
+	if err := s.keystore.Put(key.KeyID, encKey); err != nil {
+		s.logger.Named("core").Error("failed to store encryption key", "error", err)
+		return nil
+	}
+
+	req := structs.SigningKeyUpsertRequest{Keys: []*structs.SigningKey{key}}
+	if _, _, err := s.raftApply(structs.SigningKeyUpsertRequestType, req); err != nil {
+		s.logger.Named("core").Error("failed to initialize signing keyring", "error", err)
+		return nil
+	}
+	s.logger.Named("core").Info("established workload identity signing key", "key_id", key.KeyID)
+	return key
+}
+
+// encryptionKeyFor returns the local copy of the data encryption key for
+// the given keyring entry. Keys are distributed between servers out-of-band
+// of raft, so a server that has not yet replicated the key returns an error
+// for the caller to surface.
+func (s *Server) encryptionKeyFor(keyID string) ([]byte, error) {
+	encKey, ok := s.keystore.Get(keyID)
+	if !ok {
+		return nil, fmt.Errorf("encryption key for keyring entry %s is not available on this server", keyID)
+	}
+	return encKey, nil
+}
+
+// encryptVariableItems seals the items of a variable with the given data
+// encryption key, using AES-GCM with a random nonce prepended to the
+// ciphertext.
+func encryptVariableItems(encKey []byte, items map[string]string) ([]byte, error) {
+	if len(encKey) != encryptionKeyBytes {
+		return nil, fmt.Errorf("invalid data encryption key length %d", len(encKey))
+	}
+
+	plaintext, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptVariableItems unseals the data of an encrypted variable with the
+// given data encryption key.
+func decryptVariableItems(encKey []byte, data []byte) (map[string]string, error) {
+	if len(encKey) != encryptionKeyBytes {
+		return nil, fmt.Errorf("invalid data encryption key length %d", len(encKey))
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted variable data is truncated")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt variable: %v", err)
+	}
+
+	var items map[string]string
+	if err := json.Unmarshal(plaintext, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// encryptionKeyReplicationInterval is how often servers look for keyring
+// entries whose encryption keys are missing from the local keystore.
+const encryptionKeyReplicationInterval = 10 * time.Second
+
+// replicateEncryptionKeys runs on every server to fetch local copies of the
+// data encryption keys named by the replicated keyring. Key material moves
+// between servers over the Keyring.FetchEncryptionKey RPC rather than
+// through raft, so the raft log and snapshots never hold the keys that seal
+// the variables they store.
+func (s *Server) replicateEncryptionKeys() {
+	logger := s.logger.Named("keyring")
+	ticker := time.NewTicker(encryptionKeyReplicationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+		}
+
+		iter, err := s.fsm.State().SigningKeys(nil)
+		if err != nil {
+			logger.Error("failed to list signing keys", "error", err)
+			continue
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			key := raw.(*structs.SigningKey)
+			if _, ok := s.keystore.Get(key.KeyID); ok {
+				continue
+			}
+			if err := s.fetchEncryptionKey(key.KeyID); err != nil {
+				logger.Warn("failed to fetch encryption key from peers",
+					"key_id", key.KeyID, "error", err)
+			}
+		}
+	}
+}
+
+// fetchEncryptionKey asks the leader, then any other local peer, for the
+// data encryption key of the given keyring entry and stores it in the local
+// keystore. Even the leader asks its peers, to cover keys generated before
+// it took over leadership.
+func (s *Server) fetchEncryptionKey(keyID string) error {
+	_, leader := s.getLeader()
+
+	localName := s.serf.LocalMember().Name
+	s.peerLock.RLock()
+	targets := make([]*serverParts, 0, len(s.localPeers))
+	if leader != nil {
+		targets = append(targets, leader)
+	}
+	for _, peer := range s.localPeers {
+		if peer != leader && peer.Name != localName {
+			targets = append(targets, peer)
+		}
+	}
+	s.peerLock.RUnlock()
+
+	args := structs.KeyringFetchRequest{
+		KeyID:        keyID,
+		QueryOptions: structs.QueryOptions{Region: s.config.Region},
+	}
+	var lastErr error
+	for _, server := range targets {
+		var reply structs.KeyringFetchResponse
+		if err := s.forwardServer(server, "Keyring.FetchEncryptionKey", &args, &reply); err != nil {
+			lastErr = err
+			continue
+		}
+		if len(reply.EncryptionKey) == encryptionKeyBytes {
+			return s.keystore.Put(keyID, reply.EncryptionKey)
+		}
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("no peer has a copy of encryption key %s", keyID)
+}
+
+// signIdentityClaims signs the given claims with the key, producing a JWT
+// with the key ID in the "kid" header so verifiers can select the right
+// public key.
+func signIdentityClaims(key *structs.SigningKey, claims interface{}) (string, error) {
+	block, _ := pem.Decode(key.RSAPrivateKey)
+	if block == nil {
+		return "", fmt.Errorf("failed to decode signing key %s", key.KeyID)
+	}
+	privKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse signing key %s: %v", key.KeyID, err)
+	}
+
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	opts.WithHeader("kid", key.KeyID)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: privKey}, opts)
+	if err != nil {
+		return "", err
+	}
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint serves the variables data encryption keys held in the
+// server's local keystore to peer servers. It is a dynamic endpoint created
+// per connection so it can identify peer servers from the connection's
+// verified TLS certificate.
+type Keyring struct {
+	srv    *Server
+	ctx    *RPCContext
+	logger log.Logger
+}
+
+// FetchEncryptionKey returns the local copy of the data encryption key for
+// a keyring entry. Key material may only be read by peer servers, identified
+// by a verified Nomad server TLS certificate, or by management tokens; when
+// ACLs are enabled, server mTLS is therefore required for keys to replicate.
+// The request is served from the local keystore without forwarding, since
+// the replicator walks the peers itself.
+func (k *Keyring) FetchEncryptionKey(args *structs.KeyringFetchRequest, reply *structs.KeyringFetchResponse) error {
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "fetch_encryption_key"}, time.Now())
+
+	if !k.fromPeerServer() {
+		rule, err := k.srv.ResolveToken(args.AuthToken)
+		if err != nil {
+			return err
+		} else if rule != nil && !rule.IsManagement() {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	if encKey, ok := k.srv.keystore.Get(args.KeyID); ok {
+		reply.KeyID = args.KeyID
+		reply.EncryptionKey = encKey
+	}
+	k.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// fromPeerServer returns whether the request arrived on a connection with a
+// verified Nomad server certificate.
+func (k *Keyring) fromPeerServer() bool {
+	return k.ctx != nil && k.ctx.TLS &&
+		len(k.ctx.VerifiedChains) > 0 && len(k.ctx.VerifiedChains[0]) > 0 &&
+		isServerCertificate(k.ctx.VerifiedChains[0][0])
+}
@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// keystoreFileExt is the extension of the files the keystore writes under
+// the server's data directory.
+const keystoreFileExt = ".nks.json"
+
+// keystoreEntry is the on-disk form of a variables data encryption key.
+type keystoreEntry struct {
+	KeyID         string
+	EncryptionKey []byte
+}
+
+// keystore holds the symmetric data encryption keys used to seal variables,
+// indexed by the ID of the keyring entry they belong to. The keys are kept
+// on the local disk of each server and distributed between servers
+// out-of-band of raft, so that the raft log and snapshots never contain the
+// key material that seals the data they store.
+type keystore struct {
+	// dir is the directory keys are persisted in. If empty, as in dev mode,
+	// keys are held in memory only.
+	dir string
+
+	lock sync.RWMutex
+	keys map[string][]byte
+}
+
+func newKeystore(dir string) *keystore {
+	return &keystore{
+		dir:  dir,
+		keys: make(map[string][]byte),
+	}
+}
+
+// load reads any previously persisted keys from disk, creating the keystore
+// directory if necessary.
+func (ks *keystore) load() error {
+	if ks.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create keystore directory: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore directory: %v", err)
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), keystoreFileExt) {
+			continue
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(ks.dir, file.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read keystore file %s: %v", file.Name(), err)
+		}
+		var entry keystoreEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return fmt.Errorf("failed to decode keystore file %s: %v", file.Name(), err)
+		}
+		ks.keys[entry.KeyID] = entry.EncryptionKey
+	}
+	return nil
+}
+
+// Get returns the encryption key for the given keyring entry, if this server
+// has a local copy of it.
+func (ks *keystore) Get(keyID string) ([]byte, bool) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	key, ok := ks.keys[keyID]
+	return key, ok
+}
+
+// Put stores the encryption key for the given keyring entry, persisting it
+// to disk before it becomes visible to readers.
+func (ks *keystore) Put(keyID string, key []byte) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	if ks.dir != "" {
+		raw, err := json.Marshal(keystoreEntry{KeyID: keyID, EncryptionKey: key})
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(ks.dir, keyID+keystoreFileExt)
+		if err := ioutil.WriteFile(path, raw, 0600); err != nil {
+			return fmt.Errorf("failed to write keystore file: %v", err)
+		}
+	}
+	ks.keys[keyID] = key
+	return nil
+}
+
+// Delete removes the encryption key for the given keyring entry.
+func (ks *keystore) Delete(keyID string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	delete(ks.keys, keyID)
+	if ks.dir != "" {
+		err := os.Remove(filepath.Join(ks.dir, keyID+keystoreFileExt))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove keystore file: %v", err)
+		}
+	}
+	return nil
+}
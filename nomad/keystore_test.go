@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-keystore-")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ks := newKeystore(dir)
+	require.NoError(ks.load())
+
+	encKey, err := generateEncryptionKey()
+	require.NoError(err)
+	require.NoError(ks.Put("key-one", encKey))
+
+	got, ok := ks.Get("key-one")
+	require.True(ok)
+	require.Equal(encKey, got)
+
+	// Keys survive a reload from disk
+	reloaded := newKeystore(dir)
+	require.NoError(reloaded.load())
+	got, ok = reloaded.Get("key-one")
+	require.True(ok)
+	require.Equal(encKey, got)
+
+	// Deletes remove the key from memory and disk
+	require.NoError(ks.Delete("key-one"))
+	_, ok = ks.Get("key-one")
+	require.False(ok)
+
+	reloaded = newKeystore(dir)
+	require.NoError(reloaded.load())
+	_, ok = reloaded.Get("key-one")
+	require.False(ok)
+
+	// An in-memory keystore works without a directory
+	mem := newKeystore("")
+	require.NoError(mem.load())
+	require.NoError(mem.Put("key-two", encKey))
+	got, ok = mem.Get("key-two")
+	require.True(ok)
+	require.Equal(encKey, got)
+}
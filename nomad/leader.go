@@ -237,12 +237,15 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	s.autopilot.Start()
 
 	// Initialize scheduler configuration
-	s.getOrCreateSchedulerConfig()
+	schedulerConfig := s.getOrCreateSchedulerConfig()
 
 	// Initialize the ClusterID
 	_, _ = s.ClusterID()
 	// todo: use cluster ID for stuff, later!
 
+	// Initialize the workload identity signing keyring
+	s.getOrCreateSigningKey()
+
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
 
@@ -251,6 +254,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 
 	// Enable the eval broker, since we are now the leader
 	s.evalBroker.SetEnabled(true)
+	if schedulerConfig != nil {
+		s.evalBroker.SetNamespaceWeights(schedulerConfig.NamespaceAdmissionWeights)
+	}
 
 	// Enable the blocked eval tracker, since we are now the leader
 	s.blockedEvals.SetEnabled(true)
@@ -324,6 +330,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// and we are not the authoritative region.
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
+		go s.replicateACLRoles(stopCh)
+		go s.replicateACLAuthMethods(stopCh)
+		go s.replicateACLBindingRules(stopCh)
 		go s.replicateACLTokens(stopCh)
 	}
 
@@ -549,12 +558,16 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer nodeGC.Stop()
 	jobGC := time.NewTicker(s.config.JobGCInterval)
 	defer jobGC.Stop()
+	jobExpire := time.NewTicker(s.config.JobExpirationInterval)
+	defer jobExpire.Stop()
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
 	csiPluginGC := time.NewTicker(s.config.CSIPluginGCInterval)
 	defer csiPluginGC.Stop()
 	csiVolumeClaimGC := time.NewTicker(s.config.CSIVolumeClaimGCInterval)
 	defer csiVolumeClaimGC.Stop()
+	aclTokenExpirationGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+	defer aclTokenExpirationGC.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -583,6 +596,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobJobGC, index))
 			}
+		case <-jobExpire.C:
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobJobExpire, index))
+			}
 		case <-deploymentGC.C:
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
@@ -595,6 +612,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobCSIVolumeClaimGC, index))
 			}
+		case <-aclTokenExpirationGC.C:
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobACLTokenExpirationGC, index))
+			}
 
 		case <-stopCh:
 			return
@@ -634,10 +655,14 @@ func (s *Server) reapFailedEvaluations(stopCh chan struct{}) {
 				continue
 			}
 
-			// Update the status to failed
+			// Update the status to failed, attaching the last scheduler
+			// error so operators can see why delivery kept failing
 			updateEval := eval.Copy()
 			updateEval.Status = structs.EvalStatusFailed
 			updateEval.StatusDescription = fmt.Sprintf("evaluation reached delivery limit (%d)", s.config.EvalDeliveryLimit)
+			if reason := s.evalBroker.NackReason(eval.ID); reason != "" {
+				updateEval.StatusDescription = fmt.Sprintf("%s: %s", updateEval.StatusDescription, reason)
+			}
 			s.logger.Warn("eval reached delivery limit, marking as failed", "eval", updateEval.GoString())
 
 			// Core job evals that fail or span leader elections will never
@@ -1274,6 +1299,429 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from
+// the authoritative region to this region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL role replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRoleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListRoles", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.Roles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRoleDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRoleSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRoleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetRoles", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.Roles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles
+			if len(fetched) > 0 {
+				args := &structs.ACLRoleUpsertRequest{
+					Roles: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local roles
+// and the remote roles to determine which roles need to be deleted or
+// updated.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.ACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local roles")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		role := raw.(*structs.ACLRole)
+		local[role.Name] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rr := range remoteList {
+		remote[rr.Name] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rr.Name]; !ok {
+			update = append(update, rr.Name)
+
+			// Check if role is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.Name)
+		}
+	}
+
+	// Check if role should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
+// replicateACLAuthMethods is used to replicate ACL auth methods from
+// the authoritative region to this region.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+	req := structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL auth method replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of auth methods
+			var resp structs.ACLAuthMethodListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListAuthMethods", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch auth methods from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// Delete auth methods that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLAuthMethodDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated auth methods
+			var fetched []*structs.ACLAuthMethod
+			if len(update) > 0 {
+				req := structs.ACLAuthMethodSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetAuthMethods", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch auth methods from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, method := range reply.AuthMethods {
+					fetched = append(fetched, method)
+				}
+			}
+
+			// Update local auth methods
+			if len(fetched) > 0 {
+				args := &structs.ACLAuthMethodUpsertRequest{
+					AuthMethods: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local
+// auth methods and the remote auth methods to determine which ones need to
+// be deleted or updated.
+func diffACLAuthMethods(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote auth methods
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local auth methods
+	iter, err := state.ACLAuthMethods(nil)
+	if err != nil {
+		panic("failed to iterate local auth methods")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		method := raw.(*structs.ACLAuthMethod)
+		local[method.Name] = method.Hash
+	}
+
+	// Iterate over the remote auth methods
+	for _, rm := range remoteList {
+		remote[rm.Name] = struct{}{}
+
+		// Check if the auth method is missing locally
+		if localHash, ok := local[rm.Name]; !ok {
+			update = append(update, rm.Name)
+
+			// Check if the auth method is newer remotely and there is a hash mis-match.
+		} else if rm.ModifyIndex > minIndex && !bytes.Equal(localHash, rm.Hash) {
+			update = append(update, rm.Name)
+		}
+	}
+
+	// Check if auth method should be deleted
+	for lm := range local {
+		if _, ok := remote[lm]; !ok {
+			delete = append(delete, lm)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from
+// the authoritative region to this region.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+	req := structs.ACLBindingRuleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL binding rule replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of binding rules
+			var resp structs.ACLBindingRuleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListBindingRules", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch binding rules from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.BindingRules)
+
+			// Delete binding rules that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLBindingRuleDeleteRequest{
+					IDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated binding rules
+			var fetched []*structs.ACLBindingRule
+			if len(update) > 0 {
+				req := structs.ACLBindingRuleSetRequest{
+					IDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRuleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetBindingRules", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch binding rules from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, rule := range reply.BindingRules {
+					fetched = append(fetched, rule)
+				}
+			}
+
+			// Update local binding rules
+			if len(fetched) > 0 {
+				args := &structs.ACLBindingRuleUpsertRequest{
+					BindingRules: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local
+// binding rules and the remote binding rules to determine which rules need
+// to be deleted or updated.
+func diffACLBindingRules(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRule) (delete []string, update []string) {
+	// Construct a set of the local and remote binding rules
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local binding rules
+	iter, err := state.ACLBindingRules(nil)
+	if err != nil {
+		panic("failed to iterate local binding rules")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		rule := raw.(*structs.ACLBindingRule)
+		local[rule.ID] = rule.Hash
+	}
+
+	// Iterate over the remote binding rules
+	for _, rr := range remoteList {
+		remote[rr.ID] = struct{}{}
+
+		// Check if the binding rule is missing locally
+		if localHash, ok := local[rr.ID]; !ok {
+			update = append(update, rr.ID)
+
+			// Check if the binding rule is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.ID)
+		}
+	}
+
+	// Check if binding rule should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
@@ -0,0 +1,367 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// License endpoint is used for manipulating the stored cluster license
+type License struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// GetLicense is used to retrieve the current cluster license.
+func (l *License) GetLicense(args *structs.LicenseGetRequest, reply *structs.LicenseGetResponse) error {
+	if done, err := l.srv.forward("License.GetLicense", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "get_license"}, time.Now())
+
+	// Check for operator read permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			stored, err := state.License(ws)
+			if err != nil {
+				return err
+			}
+			if stored == nil {
+				return fmt.Errorf("no license stored")
+			}
+
+			license, err := structs.ParseLicense(stored.Signed)
+			if err != nil {
+				return err
+			}
+
+			reply.License = license
+			reply.Signed = stored.Signed
+			reply.QueryMeta.Index = stored.ModifyIndex
+			return nil
+		}}
+	return l.srv.blockingRPC(&opts)
+}
+
+// GetLicenseHistory is used to retrieve the license audit trail.
+func (l *License) GetLicenseHistory(args *structs.LicenseHistoryRequest, reply *structs.LicenseHistoryResponse) error {
+	if done, err := l.srv.forward("License.GetLicenseHistory", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "get_license_history"}, time.Now())
+
+	// Check for operator read permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	state := l.srv.fsm.State()
+	iter, err := state.LicenseEvents(nil)
+	if err != nil {
+		return err
+	}
+
+	var events []*structs.LicenseEvent
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		events = append(events, raw.(*structs.LicenseEvent))
+	}
+
+	reply.Events = events
+	index, err := state.Index("license_events")
+	if err != nil {
+		return err
+	}
+	reply.QueryMeta.Index = index
+	l.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// Report is used to aggregate the license state of every federated region
+// and surface feature differences between them. Regions whose license
+// cannot be retrieved are reported with an error and treated as having no
+// features.
+func (l *License) Report(args *structs.LicenseReportRequest, reply *structs.LicenseReportResponse) error {
+	if done, err := l.srv.forward("License.Report", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "report"}, time.Now())
+
+	// Check for operator read permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Collect the license of every federated region
+	regions := l.srv.Regions()
+	licenses := make(map[string]*structs.License, len(regions))
+	for _, region := range regions {
+		entry := &structs.RegionLicense{Region: region}
+		reply.Regions = append(reply.Regions, entry)
+
+		if region == l.srv.Region() {
+			stored, err := l.srv.fsm.State().License(nil)
+			if err != nil {
+				return err
+			}
+			if stored == nil {
+				continue
+			}
+			license, err := structs.ParseLicense(stored.Signed)
+			if err != nil {
+				entry.Error = err.Error()
+				continue
+			}
+			entry.License = license
+			licenses[region] = license
+			continue
+		}
+
+		get := &structs.LicenseGetRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    region,
+				AuthToken: args.AuthToken,
+			},
+		}
+		var resp structs.LicenseGetResponse
+		if err := l.srv.RPC("License.GetLicense", get, &resp); err != nil {
+			entry.Error = err.Error()
+			continue
+		}
+		entry.License = resp.License
+		licenses[region] = resp.License
+	}
+
+	// Compute the features that are not licensed everywhere
+	reply.FeatureDeltas = make(map[string][]string)
+	for _, license := range licenses {
+		if license == nil {
+			continue
+		}
+		for _, feature := range license.Features {
+			if _, ok := reply.FeatureDeltas[feature]; ok {
+				continue
+			}
+			var missing []string
+			for _, region := range regions {
+				if !licenses[region].HasFeature(feature) {
+					missing = append(missing, region)
+				}
+			}
+			if len(missing) > 0 {
+				reply.FeatureDeltas[feature] = missing
+			}
+		}
+	}
+
+	// Warn about multiregion jobs that target a region missing a feature
+	// they require
+	state := l.srv.fsm.State()
+	iter, err := state.Jobs(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.Stopped() || !job.IsMultiregion() {
+			continue
+		}
+		for _, feature := range job.RequiredLicenseFeatures() {
+			for _, region := range job.Multiregion.Regions {
+				if licenses[region.Name].HasFeature(feature) {
+					continue
+				}
+				reply.Warnings = append(reply.Warnings, fmt.Sprintf(
+					"job %q in namespace %q requires feature %q which is not licensed in region %q",
+					job.ID, job.Namespace, feature, region.Name))
+			}
+		}
+	}
+
+	index, err := state.Index("license")
+	if err != nil {
+		return err
+	}
+	reply.QueryMeta.Index = index
+	l.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// PreviewLicense validates a candidate license and reports the objects in
+// the state store that would become unsupported under its feature set. The
+// license is not stored, so operators can see what a downgrade would break
+// before applying it.
+func (l *License) PreviewLicense(args *structs.LicensePreviewRequest, reply *structs.LicensePreviewResponse) error {
+	if done, err := l.srv.forward("License.PreviewLicense", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "preview_license"}, time.Now())
+
+	// Check for operator read permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.License == nil || args.License.Signed == "" {
+		return fmt.Errorf("missing license")
+	}
+
+	license, err := structs.ParseLicense(args.License.Signed)
+	if err != nil {
+		return err
+	}
+	reply.License = license
+
+	state := l.srv.fsm.State()
+
+	// Running jobs that require a feature the candidate license lacks
+	iter, err := state.Jobs(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.Stopped() {
+			continue
+		}
+		for _, feature := range job.RequiredLicenseFeatures() {
+			if license.HasFeature(feature) {
+				continue
+			}
+			reply.Unsupported = append(reply.Unsupported, &structs.LicenseImpactedObject{
+				Type:      "job",
+				ID:        job.ID,
+				Namespace: job.Namespace,
+				Feature:   feature,
+			})
+		}
+	}
+
+	// Sentinel policies are unsupported without the sentinel feature
+	if !license.HasFeature(structs.LicenseFeatureSentinel) {
+		iter, err := state.SentinelPolicies(nil)
+		if err != nil {
+			return err
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			policy := raw.(*structs.SentinelPolicy)
+			reply.Unsupported = append(reply.Unsupported, &structs.LicenseImpactedObject{
+				Type:    "sentinel policy",
+				ID:      policy.Name,
+				Feature: structs.LicenseFeatureSentinel,
+			})
+		}
+	}
+
+	index, err := state.Index("license")
+	if err != nil {
+		return err
+	}
+	reply.QueryMeta.Index = index
+	l.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// UpsertLicense is used to set the cluster license.
+func (l *License) UpsertLicense(args *structs.LicenseUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := l.srv.forward("License.UpsertLicense", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "upsert_license"}, time.Now())
+
+	// Check for operator write permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.License == nil || args.License.Signed == "" {
+		return fmt.Errorf("missing license")
+	}
+
+	// The license must parse before it is stored
+	if _, err := structs.ParseLicense(args.License.Signed); err != nil {
+		return err
+	}
+
+	// Attach the requesting token's accessor and the request time for the
+	// audit trail
+	token, err := l.srv.ResolveSecretToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		args.AccessorID = token.AccessorID
+	}
+	args.Time = time.Now().UnixNano()
+
+	// Commit the license via Raft
+	_, index, err := l.srv.raftApply(structs.LicenseUpsertRequestType, args)
+	if err != nil {
+		l.logger.Error("LicenseUpsertRequest failed", "error", err)
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	return nil
+}
+
+// DeleteLicense is used to remove the stored cluster license so the cluster
+// falls back to a temporary license.
+func (l *License) DeleteLicense(args *structs.LicenseDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := l.srv.forward("License.DeleteLicense", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "license", "delete_license"}, time.Now())
+
+	// Check for operator write permissions
+	if aclObj, err := l.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Attach the requesting token's accessor and the request time for the
+	// audit trail
+	token, err := l.srv.ResolveSecretToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		args.AccessorID = token.AccessorID
+	}
+	args.Time = time.Now().UnixNano()
+
+	// Commit the removal via Raft
+	_, index, err := l.srv.raftApply(structs.LicenseDeleteRequestType, args)
+	if err != nil {
+		l.logger.Error("LicenseDeleteRequest failed", "error", err)
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,185 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicenseEndpoint_GetLicense(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	state := s1.fsm.State()
+	signed := testSignedLicense(t, []string{"multiregion"})
+	require.NoError(state.UpsertLicense(1000, &structs.StoredLicense{Signed: signed}))
+
+	get := &structs.LicenseGetRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.LicenseGetResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "License.GetLicense", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Equal("test-license", resp.License.LicenseID)
+	require.Equal(signed, resp.Signed)
+}
+
+func TestLicenseEndpoint_GetLicense_Blocking(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	state := s1.fsm.State()
+	signed := testSignedLicense(t, nil)
+	require.NoError(state.UpsertLicense(1000, &structs.StoredLicense{Signed: signed}))
+
+	// Replace the license later which should trigger the watch
+	updated := testSignedLicense(t, []string{"multiregion"})
+	time.AfterFunc(200*time.Millisecond, func() {
+		require.NoError(state.UpsertLicense(1100, &structs.StoredLicense{Signed: updated}))
+	})
+
+	get := &structs.LicenseGetRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:        "global",
+			MinQueryIndex: 1000,
+		},
+	}
+	start := time.Now()
+	var resp structs.LicenseGetResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "License.GetLicense", get, &resp))
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("should block (returned in %s) %#v", elapsed, resp)
+	}
+	require.EqualValues(1100, resp.Index)
+	require.Equal(updated, resp.Signed)
+}
+
+func TestLicenseEndpoint_Report(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	west, cleanupWest := TestServer(t, func(c *Config) {
+		c.Region = "west"
+	})
+	defer cleanupWest()
+	codec := rpcClient(t, west)
+
+	east, cleanupEast := TestServer(t, func(c *Config) {
+		c.Region = "east"
+	})
+	defer cleanupEast()
+
+	TestJoin(t, west, east)
+	testutil.WaitForLeader(t, west.RPC)
+	testutil.WaitForLeader(t, east.RPC)
+
+	// Wait for the federation to be established
+	testutil.WaitForResult(func() (bool, error) {
+		return len(west.Regions()) == 2, nil
+	}, func(err error) {
+		t.Fatalf("timed out waiting for federation")
+	})
+
+	// License the multiregion feature in west only
+	require.NoError(west.fsm.State().UpsertLicense(1000,
+		&structs.StoredLicense{Signed: testSignedLicense(t, []string{"multiregion"})}))
+	require.NoError(east.fsm.State().UpsertLicense(1000,
+		&structs.StoredLicense{Signed: testSignedLicense(t, nil)}))
+
+	// Register a multiregion job that targets both regions
+	job := mock.MultiregionJob()
+	job.Region = "west"
+	require.NoError(west.fsm.State().UpsertJob(1001, job))
+
+	get := &structs.LicenseReportRequest{
+		QueryOptions: structs.QueryOptions{Region: "west"},
+	}
+	var resp structs.LicenseReportResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "License.Report", get, &resp))
+
+	// One entry per region, sorted by region name
+	require.Len(resp.Regions, 2)
+	require.Equal("east", resp.Regions[0].Region)
+	require.NotNil(resp.Regions[0].License)
+	require.Empty(resp.Regions[0].License.Features)
+	require.Equal("west", resp.Regions[1].Region)
+	require.Equal([]string{"multiregion"}, resp.Regions[1].License.Features)
+
+	// The multiregion feature is missing in east
+	require.Equal(map[string][]string{"multiregion": {"east"}}, resp.FeatureDeltas)
+
+	// The job deploys to east, which is missing the feature it requires
+	require.Len(resp.Warnings, 1)
+	require.Contains(resp.Warnings[0], job.ID)
+	require.Contains(resp.Warnings[0], `not licensed in region "east"`)
+}
+
+func TestLicenseEndpoint_PreviewLicense(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+
+	// Populate the state with objects that depend on enterprise features
+	job := mock.MultiregionJob()
+	require.NoError(state.UpsertJob(1000, job))
+
+	policy := mock.SentinelPolicy()
+	require.NoError(state.UpsertSentinelPolicies(1001, []*structs.SentinelPolicy{policy}))
+
+	// Preview a license without the features they require
+	get := &structs.LicensePreviewRequest{
+		License:      &structs.StoredLicense{Signed: testSignedLicense(t, nil)},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.LicensePreviewResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "License.PreviewLicense", get, &resp))
+
+	require.Equal("test-license", resp.License.LicenseID)
+	require.Len(resp.Unsupported, 2)
+	require.Equal(&structs.LicenseImpactedObject{
+		Type:      "job",
+		ID:        job.ID,
+		Namespace: job.Namespace,
+		Feature:   structs.LicenseFeatureMultiregion,
+	}, resp.Unsupported[0])
+	require.Equal(&structs.LicenseImpactedObject{
+		Type:    "sentinel policy",
+		ID:      policy.Name,
+		Feature: structs.LicenseFeatureSentinel,
+	}, resp.Unsupported[1])
+
+	// Previewing a license with every required feature reports nothing
+	get.License = &structs.StoredLicense{
+		Signed: testSignedLicense(t, []string{"multiregion", "sentinel"}),
+	}
+	var resp2 structs.LicensePreviewResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "License.PreviewLicense", get, &resp2))
+	require.Empty(resp2.Unsupported)
+
+	// A missing license is an error
+	get.License = nil
+	err := msgpackrpc.CallWithCodec(codec, "License.PreviewLicense", get, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing license")
+}
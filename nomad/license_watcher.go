@@ -0,0 +1,103 @@
+package nomad
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// LicenseWatcher provides access to the parsed form of the stored cluster
+// license and enforces feature gating against it.
+type LicenseWatcher struct {
+	logger  log.Logger
+	stateFn func() *state.StateStore
+
+	// established marks when the watcher started, which anchors the
+	// feature grace period
+	established time.Time
+
+	// gracePeriod is how long after established unlicensed features are
+	// soft-failed rather than denied
+	gracePeriod time.Duration
+
+	// devLicense, if set, is returned when no license is stored. Dev mode
+	// agents use it to enable every feature without license setup.
+	devLicense *structs.License
+
+	// violations counts soft feature check failures by feature name
+	violations map[string]uint64
+	l          sync.Mutex
+}
+
+// NewLicenseWatcher returns a license watcher that reads the license from
+// the given state store.
+func NewLicenseWatcher(logger log.Logger, stateFn func() *state.StateStore, gracePeriod time.Duration) *LicenseWatcher {
+	return &LicenseWatcher{
+		logger:      logger.Named("license"),
+		stateFn:     stateFn,
+		established: time.Now(),
+		gracePeriod: gracePeriod,
+		violations:  make(map[string]uint64),
+	}
+}
+
+// License returns the parsed form of the stored cluster license. If no
+// license is stored, or the stored blob cannot be parsed, the dev license is
+// returned when one is set, and nil otherwise.
+func (w *LicenseWatcher) License() *structs.License {
+	stored, err := w.stateFn().License(nil)
+	if err == nil && stored != nil {
+		if license, err := structs.ParseLicense(stored.Signed); err == nil {
+			return license
+		}
+	}
+	return w.devLicense
+}
+
+// FeatureCheck returns an error if the given feature is not enabled by the
+// current license. While the watcher is within its grace period an
+// unlicensed feature is allowed but logged and counted instead, so upgrades
+// that introduce new gating do not break running clusters before operators
+// have a chance to update their license.
+func (w *LicenseWatcher) FeatureCheck(feature string, emitLog bool) error {
+	if w.License().HasFeature(feature) {
+		return nil
+	}
+
+	err := fmt.Errorf("feature %q is unlicensed", feature)
+	if time.Since(w.established) >= w.gracePeriod {
+		return err
+	}
+
+	w.l.Lock()
+	w.violations[feature]++
+	w.l.Unlock()
+
+	metrics.IncrCounterWithLabels([]string{"nomad", "license", "feature_violation"}, 1,
+		[]metrics.Label{{Name: "feature", Value: feature}})
+
+	if emitLog {
+		w.logger.Warn("feature is unlicensed and will be denied after the grace period",
+			"feature", feature, "grace_period_ends", w.established.Add(w.gracePeriod), "error", err)
+	}
+	return nil
+}
+
+// FeatureViolations returns a copy of the soft feature check failure counts
+// accumulated during the grace period.
+func (w *LicenseWatcher) FeatureViolations() map[string]uint64 {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	violations := make(map[string]uint64, len(w.violations))
+	for feature, count := range w.violations {
+		violations[feature] = count
+	}
+	return violations
+}
@@ -0,0 +1,88 @@
+package nomad
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testSignedLicense(t *testing.T, features []string) string {
+	raw, err := json.Marshal(&structs.License{
+		LicenseID: "test-license",
+		Product:   "nomad",
+		Features:  features,
+	})
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestLicenseWatcher_FeatureCheck(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	stateFn := func() *state.StateStore { return store }
+
+	signed := testSignedLicense(t, []string{"multiregion"})
+	require.NoError(store.UpsertLicense(1000, &structs.StoredLicense{Signed: signed}))
+
+	w := NewLicenseWatcher(testlog.HCLogger(t), stateFn, time.Hour)
+
+	// Licensed features pass
+	require.NoError(w.FeatureCheck("multiregion", true))
+	require.Empty(w.FeatureViolations())
+
+	// Unlicensed features are allowed but counted during the grace period
+	require.NoError(w.FeatureCheck("sentinel", true))
+	require.NoError(w.FeatureCheck("sentinel", false))
+	require.Equal(map[string]uint64{"sentinel": 2}, w.FeatureViolations())
+}
+
+func TestLicenseWatcher_FeatureCheck_GraceExpired(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	stateFn := func() *state.StateStore { return store }
+
+	w := NewLicenseWatcher(testlog.HCLogger(t), stateFn, time.Hour)
+	w.established = time.Now().Add(-2 * time.Hour)
+
+	err := w.FeatureCheck("sentinel", true)
+	require.Error(err)
+	require.Contains(err.Error(), "unlicensed")
+	require.Empty(w.FeatureViolations())
+}
+
+func TestLicenseWatcher_DevLicense(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	stateFn := func() *state.StateStore { return store }
+
+	w := NewLicenseWatcher(testlog.HCLogger(t), stateFn, time.Hour)
+	w.devLicense = structs.DevLicense()
+
+	// With no stored license the dev license enables every feature
+	license := w.License()
+	require.NotNil(license)
+	require.Equal("dev-license", license.LicenseID)
+	require.NoError(w.FeatureCheck(structs.LicenseFeatureMultiregion, true))
+	require.NoError(w.FeatureCheck(structs.LicenseFeatureSentinel, true))
+	require.Empty(w.FeatureViolations())
+
+	// A stored license takes precedence over the dev license
+	signed := testSignedLicense(t, []string{structs.LicenseFeatureSentinel})
+	require.NoError(store.UpsertLicense(1000, &structs.StoredLicense{Signed: signed}))
+	license = w.License()
+	require.NotNil(license)
+	require.Equal("test-license", license.LicenseID)
+	require.False(license.HasFeature(structs.LicenseFeatureMultiregion))
+}
@@ -0,0 +1,84 @@
+package nomad
+
+import (
+	"fmt"
+
+	bexpr "github.com/hashicorp/go-bexpr"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// listPaginator applies the filter and pagination query options to the items
+// of a memdb iterator while building a page of list results. The iterator
+// must produce items in the same order tokenFn sorts them, which holds for
+// the id and namespace indexes used by the list endpoints.
+type listPaginator struct {
+	iter      memdb.ResultIterator
+	filter    *bexpr.Evaluator
+	perPage   int32
+	nextToken string
+
+	// tokenFn returns the paging token of an item.
+	tokenFn func(interface{}) string
+
+	// appendFn adds an item to the page being built.
+	appendFn func(interface{}) error
+}
+
+// newListPaginator returns a paginator for the passed iterator and query
+// options. It returns an error if the filter expression does not parse.
+func newListPaginator(iter memdb.ResultIterator, opts structs.QueryOptions,
+	tokenFn func(interface{}) string, appendFn func(interface{}) error) (*listPaginator, error) {
+
+	p := &listPaginator{
+		iter:      iter,
+		perPage:   opts.PerPage,
+		nextToken: opts.NextToken,
+		tokenFn:   tokenFn,
+		appendFn:  appendFn,
+	}
+
+	if opts.Filter != "" {
+		evaluator, err := bexpr.CreateEvaluator(opts.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse filter expression: %v", err)
+		}
+		p.filter = evaluator
+	}
+
+	return p, nil
+}
+
+// Page consumes the iterator and builds a page of results. It returns the
+// token the next page starts at, or the empty string if this is the last
+// page.
+func (p *listPaginator) Page() (string, error) {
+	var count int32
+	for raw := p.iter.Next(); raw != nil; raw = p.iter.Next() {
+		if p.nextToken != "" && p.tokenFn(raw) < p.nextToken {
+			continue
+		}
+
+		if p.filter != nil {
+			match, err := p.filter.Evaluate(raw)
+			if err != nil {
+				return "", fmt.Errorf("failed to evaluate filter expression: %v", err)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		if p.perPage != 0 && count == p.perPage {
+			return p.tokenFn(raw), nil
+		}
+
+		if err := p.appendFn(raw); err != nil {
+			return "", err
+		}
+		count++
+	}
+
+	return "", nil
+}
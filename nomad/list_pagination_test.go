@@ -0,0 +1,126 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceIterator is a memdb.ResultIterator over a fixed set of evals.
+type sliceIterator struct {
+	evals []*structs.Evaluation
+	idx   int
+}
+
+func (i *sliceIterator) Next() interface{} {
+	if i.idx >= len(i.evals) {
+		return nil
+	}
+	eval := i.evals[i.idx]
+	i.idx++
+	return eval
+}
+
+func (i *sliceIterator) WatchCh() <-chan struct{} {
+	return nil
+}
+
+func TestListPaginator(t *testing.T) {
+	t.Parallel()
+
+	// Build evals with ordered IDs, alternating job IDs.
+	ids := []string{
+		"aaaa1111-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaa22-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaa33-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+	}
+	evals := make([]*structs.Evaluation, len(ids))
+	for i, id := range ids {
+		eval := mock.Eval()
+		eval.ID = id
+		if i%2 == 0 {
+			eval.JobID = "even"
+		} else {
+			eval.JobID = "odd"
+		}
+		evals[i] = eval
+	}
+
+	cases := []struct {
+		Name              string
+		Filter            string
+		PerPage           int32
+		NextToken         string
+		ExpectedIDs       []string
+		ExpectedNextToken string
+	}{
+		{
+			Name:        "no pagination",
+			ExpectedIDs: ids,
+		},
+		{
+			Name:              "first page",
+			PerPage:           2,
+			ExpectedIDs:       ids[:2],
+			ExpectedNextToken: ids[2],
+		},
+		{
+			Name:        "second page",
+			PerPage:     2,
+			NextToken:   ids[2],
+			ExpectedIDs: ids[2:],
+		},
+		{
+			Name:        "filter",
+			Filter:      `JobID == "even"`,
+			ExpectedIDs: []string{ids[0], ids[2]},
+		},
+		{
+			Name:              "filter with pagination",
+			Filter:            `JobID == "even"`,
+			PerPage:           1,
+			ExpectedIDs:       []string{ids[0]},
+			ExpectedNextToken: ids[2],
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			opts := structs.QueryOptions{
+				Filter:    tc.Filter,
+				PerPage:   tc.PerPage,
+				NextToken: tc.NextToken,
+			}
+
+			var out []string
+			paginator, err := newListPaginator(&sliceIterator{evals: evals}, opts,
+				func(raw interface{}) string {
+					return raw.(*structs.Evaluation).ID
+				},
+				func(raw interface{}) error {
+					out = append(out, raw.(*structs.Evaluation).ID)
+					return nil
+				})
+			require.NoError(t, err)
+
+			nextToken, err := paginator.Page()
+			require.NoError(t, err)
+			require.Equal(t, tc.ExpectedIDs, out)
+			require.Equal(t, tc.ExpectedNextToken, nextToken)
+		})
+	}
+}
+
+func TestListPaginator_InvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	opts := structs.QueryOptions{Filter: "JobID == "}
+	_, err := newListPaginator(&sliceIterator{}, opts,
+		func(raw interface{}) string { return "" },
+		func(raw interface{}) error { return nil })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse filter expression")
+}
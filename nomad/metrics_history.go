@@ -0,0 +1,149 @@
+package nomad
+
+import (
+	"sync"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// metricsHistoryInterval is how often the cluster gauges are sampled.
+	metricsHistoryInterval = 30 * time.Second
+
+	// metricsHistoryRetention is how much gauge history each server keeps
+	// in memory.
+	metricsHistoryRetention = 1 * time.Hour
+)
+
+// metricsHistory is a fixed size ring buffer of cluster gauge samples. Each
+// server keeps its own short history so that incident responders can get
+// immediate context from the cluster itself before external monitoring is
+// consulted.
+type metricsHistory struct {
+	mu      sync.RWMutex
+	samples []*structs.ClusterMetricsSample
+	next    int
+	full    bool
+}
+
+// newMetricsHistory returns a ring buffer retaining the given number of
+// samples.
+func newMetricsHistory(capacity int) *metricsHistory {
+	return &metricsHistory{
+		samples: make([]*structs.ClusterMetricsSample, capacity),
+	}
+}
+
+// record adds a sample to the ring, overwriting the oldest sample once the
+// ring is full.
+func (h *metricsHistory) record(sample *structs.ClusterMetricsSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[h.next] = sample
+	h.next++
+	if h.next == len(h.samples) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// since returns the retained samples taken at or after the given time, oldest
+// first.
+func (h *metricsHistory) since(t time.Time) []*structs.ClusterMetricsSample {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var out []*structs.ClusterMetricsSample
+	keep := func(sample *structs.ClusterMetricsSample) {
+		if sample != nil && !sample.Timestamp.Before(t) {
+			out = append(out, sample)
+		}
+	}
+
+	if h.full {
+		for i := h.next; i < len(h.samples); i++ {
+			keep(h.samples[i])
+		}
+	}
+	for i := 0; i < h.next; i++ {
+		keep(h.samples[i])
+	}
+	return out
+}
+
+// collectMetricsHistory periodically samples the cluster gauges into the
+// server's metrics history until the server shuts down.
+func (s *Server) collectMetricsHistory() {
+	ticker := time.NewTicker(metricsHistoryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			sample, err := s.sampleClusterMetrics()
+			if err != nil {
+				s.logger.Error("failed to sample cluster metrics", "error", err)
+				continue
+			}
+			s.metricsHistory.record(sample)
+		}
+	}
+}
+
+// sampleClusterMetrics builds a point in time snapshot of the cluster gauges
+// from the server's state store.
+func (s *Server) sampleClusterMetrics() (*structs.ClusterMetricsSample, error) {
+	state := s.fsm.State()
+	ws := memdb.NewWatchSet()
+
+	sample := &structs.ClusterMetricsSample{
+		Timestamp: time.Now(),
+		Nodes:     make(map[string]int),
+		Allocs:    make(map[string]int),
+	}
+
+	nodes, err := state.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := nodes.Next(); raw != nil; raw = nodes.Next() {
+		node := raw.(*structs.Node)
+		sample.Nodes[node.Status]++
+	}
+
+	allocs, err := state.Allocs(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := allocs.Next(); raw != nil; raw = allocs.Next() {
+		alloc := raw.(*structs.Allocation)
+		sample.Allocs[alloc.ClientStatus]++
+	}
+
+	evals, err := state.Evals(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := evals.Next(); raw != nil; raw = evals.Next() {
+		eval := raw.(*structs.Evaluation)
+		if eval.Status == structs.EvalStatusPending {
+			sample.EvalBacklog++
+		}
+	}
+
+	sample.BlockedEvals = s.blockedEvals.Stats().TotalBlocked
+
+	if license := s.licenseWatcher.License(); license != nil {
+		if remaining := time.Until(license.ExpirationTime); remaining > 0 {
+			sample.LicenseDaysRemaining = remaining.Hours() / 24
+		}
+	}
+
+	return sample, nil
+}
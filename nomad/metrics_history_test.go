@@ -0,0 +1,78 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHistory_RecordSince(t *testing.T) {
+	t.Parallel()
+
+	h := newMetricsHistory(3)
+	base := time.Now()
+
+	sampleAt := func(offset time.Duration) *structs.ClusterMetricsSample {
+		return &structs.ClusterMetricsSample{Timestamp: base.Add(offset)}
+	}
+
+	// Empty ring returns nothing.
+	require.Empty(t, h.since(time.Time{}))
+
+	// Partially filled ring returns what it has, oldest first.
+	h.record(sampleAt(0))
+	h.record(sampleAt(time.Second))
+	out := h.since(time.Time{})
+	require.Len(t, out, 2)
+	require.Equal(t, base, out[0].Timestamp)
+
+	// Overflowing the ring drops the oldest samples.
+	h.record(sampleAt(2 * time.Second))
+	h.record(sampleAt(3 * time.Second))
+	out = h.since(time.Time{})
+	require.Len(t, out, 3)
+	require.Equal(t, base.Add(time.Second), out[0].Timestamp)
+	require.Equal(t, base.Add(3*time.Second), out[2].Timestamp)
+
+	// Filtering by time skips the older samples.
+	out = h.since(base.Add(3 * time.Second))
+	require.Len(t, out, 1)
+	require.Equal(t, base.Add(3*time.Second), out[0].Timestamp)
+}
+
+func TestServer_sampleClusterMetrics(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(1000, node))
+
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	require.NoError(t, state.UpsertNode(1001, down))
+
+	alloc := mock.Alloc()
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, state.UpsertJob(1002, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(1003, []*structs.Allocation{alloc}))
+
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusPending
+	require.NoError(t, state.UpsertEvals(1004, []*structs.Evaluation{eval}))
+
+	sample, err := s1.sampleClusterMetrics()
+	require.NoError(t, err)
+	require.False(t, sample.Timestamp.IsZero())
+	require.Equal(t, 1, sample.Nodes[structs.NodeStatusReady])
+	require.Equal(t, 1, sample.Nodes[structs.NodeStatusDown])
+	require.Equal(t, 1, sample.Allocs[structs.AllocClientStatusRunning])
+	require.Equal(t, 1, sample.EvalBacklog)
+}
@@ -58,6 +58,26 @@ func HostVolumePolicy(vol string, policy string, capabilities []string) string {
 	return policyHCL
 }
 
+// VariablePolicy is a helper for generating the policy hcl for a given
+// variable path. Either policy or capabilities may be nil but not both.
+func VariablePolicy(path string, policy string, capabilities []string) string {
+	policyHCL := fmt.Sprintf("variable %q {", path)
+	if policy != "" {
+		policyHCL += fmt.Sprintf("\n\tpolicy = %q", policy)
+	}
+	if len(capabilities) != 0 {
+		for i, s := range capabilities {
+			if !strings.HasPrefix(s, "\"") {
+				capabilities[i] = strconv.Quote(s)
+			}
+		}
+
+		policyHCL += fmt.Sprintf("\n\tcapabilities = [%v]", strings.Join(capabilities, ","))
+	}
+	policyHCL += "\n}"
+	return policyHCL
+}
+
 // AgentPolicy is a helper for generating the hcl for a given agent policy.
 func AgentPolicy(policy string) string {
 	return fmt.Sprintf("agent {\n\tpolicy = %q\n}\n", policy)
@@ -1319,6 +1319,124 @@ func ACLPolicy() *structs.ACLPolicy {
 	return ap
 }
 
+func ACLRole() *structs.ACLRole {
+	ar := &structs.ACLRole{
+		Name:        fmt.Sprintf("role-%s", uuid.Generate()),
+		Description: "Super cool role!",
+		Policies:    []string{"foo", "bar"},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	ar.SetHash()
+	return ar
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	am := &structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("method-%s", uuid.Generate()),
+		Type:          structs.ACLAuthMethodTypeJWT,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			JWTValidationPubKeys: []string{"test-pub-key"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	am.SetHash()
+	return am
+}
+
+func ACLBindingRule() *structs.ACLBindingRule {
+	br := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "Super cool binding rule!",
+		AuthMethod:  "test-auth-method",
+		Selector:    "value.sub matches \".*\"",
+		BindType:    structs.ACLBindingRuleBindTypePolicy,
+		BindName:    "readonly",
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	br.SetHash()
+	return br
+}
+
+func SigningKey() *structs.SigningKey {
+	return &structs.SigningKey{
+		KeyID:         uuid.Generate(),
+		RSAPrivateKey: []byte("test-private-key"),
+		Active:        true,
+		CreateTime:    time.Now().UnixNano(),
+		CreateIndex:   10,
+		ModifyIndex:   20,
+	}
+}
+
+func EncryptedVariable() *structs.EncryptedVariable {
+	return &structs.EncryptedVariable{
+		Namespace:  structs.DefaultNamespace,
+		Path:       fmt.Sprintf("nomad/jobs/job-%s", uuid.Generate()),
+		KeyID:      uuid.Generate(),
+		Data:       []byte("sealed-test-data"),
+		CreateTime: time.Now().UnixNano(),
+		ModifyTime: time.Now().UnixNano(),
+	}
+}
+
+func Namespace() *structs.Namespace {
+	ns := &structs.Namespace{
+		Name:        fmt.Sprintf("team-%s", uuid.Generate()),
+		Description: "test namespace",
+		CreateIndex: 100,
+		ModifyIndex: 200,
+	}
+	ns.SetHash()
+	return ns
+}
+
+func SentinelPolicy() *structs.SentinelPolicy {
+	sp := &structs.SentinelPolicy{
+		Name:             fmt.Sprintf("sent-policy-%s", uuid.Generate()),
+		Description:      "Super cool policy!",
+		EnforcementLevel: structs.SentinelEnforcementLevelAdvisory,
+		Scope:            structs.SentinelScopeSubmitJob,
+		Policy:           "main = rule { true }",
+		CreateIndex:      10,
+		ModifyIndex:      20,
+	}
+	sp.SetHash()
+	return sp
+}
+
+func ServiceRegistration() *structs.ServiceRegistration {
+	id := fmt.Sprintf("_nomad-task-%s-group-api-countdash-api-http", uuid.Generate())
+	return &structs.ServiceRegistration{
+		ID:          id,
+		ServiceName: "countdash-api",
+		Namespace:   "default",
+		NodeID:      uuid.Generate(),
+		Datacenter:  "dc1",
+		JobID:       "countdash",
+		AllocID:     uuid.Generate(),
+		Tags:        []string{"bar"},
+		Address:     "192.168.200.200",
+		Port:        29001,
+	}
+}
+
+func SentinelModule() *structs.SentinelModule {
+	sm := &structs.SentinelModule{
+		Name:        fmt.Sprintf("sent-module-%s", uuid.Generate()),
+		Description: "Super cool module!",
+		Source:      "always_true = rule { true }",
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	sm.SetHash()
+	return sm
+}
+
 func ACLToken() *structs.ACLToken {
 	tk := &structs.ACLToken{
 		AccessorID:  uuid.Generate(),
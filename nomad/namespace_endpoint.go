@@ -0,0 +1,329 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Namespace endpoint is used for manipulating namespaces
+type Namespace struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// featureEnabled returns whether namespace creation and deletion have been
+// switched on via the namespaces feature flag.
+func (n *Namespace) featureEnabled() (bool, error) {
+	_, flags, err := n.srv.fsm.State().FeatureFlags()
+	if err != nil {
+		return false, err
+	}
+	return flags.FeatureEnabled(structs.FeatureNamespaces), nil
+}
+
+// UpsertNamespaces is used to upsert a set of namespaces
+func (n *Namespace) UpsertNamespaces(args *structs.NamespaceUpsertRequest,
+	reply *structs.GenericResponse) error {
+	args.Region = n.srv.config.AuthoritativeRegion
+	if done, err := n.srv.forward("Namespace.UpsertNamespaces", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "upsert_namespaces"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Creating namespaces is gated by the feature flag
+	if enabled, err := n.featureEnabled(); err != nil {
+		return err
+	} else if !enabled {
+		return fmt.Errorf("namespaces are disabled; enable the %q feature flag to use them", structs.FeatureNamespaces)
+	}
+
+	// Validate there is at least one namespace
+	if len(args.Namespaces) == 0 {
+		return fmt.Errorf("must specify at least one namespace")
+	}
+
+	// Validate the namespaces and set the hash
+	for _, ns := range args.Namespaces {
+		if err := ns.Validate(); err != nil {
+			return fmt.Errorf("Invalid namespace %q: %v", ns.Name, err)
+		}
+
+		ns.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := n.srv.raftApply(structs.NamespaceUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteNamespaces is used to delete a set of namespaces
+func (n *Namespace) DeleteNamespaces(args *structs.NamespaceDeleteRequest,
+	reply *structs.GenericResponse) error {
+	args.Region = n.srv.config.AuthoritativeRegion
+	if done, err := n.srv.forward("Namespace.DeleteNamespaces", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "delete_namespaces"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Deleting namespaces is gated by the feature flag
+	if enabled, err := n.featureEnabled(); err != nil {
+		return err
+	} else if !enabled {
+		return fmt.Errorf("namespaces are disabled; enable the %q feature flag to use them", structs.FeatureNamespaces)
+	}
+
+	// Validate at least one namespace
+	if len(args.Namespaces) == 0 {
+		return fmt.Errorf("must specify at least one namespace to delete")
+	}
+
+	for _, ns := range args.Namespaces {
+		if ns == structs.DefaultNamespace {
+			return fmt.Errorf("can not delete default namespace")
+		}
+	}
+
+	// Check that the deleting namespaces do not have non-terminal jobs
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range args.Namespaces {
+		nonTerminal, err := n.nonTerminalNamespaces(snap, ns)
+		if err != nil {
+			return err
+		}
+		if len(nonTerminal) != 0 {
+			return fmt.Errorf("namespace %q has non-terminal jobs: %v", ns, nonTerminal)
+		}
+	}
+
+	// Update via Raft
+	_, index, err := n.srv.raftApply(structs.NamespaceDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// nonTerminalNamespaces returns the set of jobs in the namespace that are
+// non-terminal
+func (n *Namespace) nonTerminalNamespaces(snap *state.StateSnapshot, namespace string) ([]string, error) {
+	iter, err := snap.JobsByNamespace(nil, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonTerminal []string
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		job := raw.(*structs.Job)
+		if job.Status != structs.JobStatusDead {
+			nonTerminal = append(nonTerminal, job.ID)
+		}
+	}
+
+	return nonTerminal, nil
+}
+
+// ListNamespaces is used to list the namespaces
+func (n *Namespace) ListNamespaces(args *structs.NamespaceListRequest,
+	reply *structs.NamespaceListResponse) error {
+	if done, err := n.srv.forward("Namespace.ListNamespaces", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "list_namespace"}, time.Now())
+
+	// Resolve the token so the list can be filtered to the namespaces the
+	// token can access
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the namespaces
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.NamespacesByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.Namespaces(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Namespaces = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+
+				ns := raw.(*structs.Namespace)
+
+				// Only return namespaces allowed by the ACL token
+				if aclObj != nil && !aclObj.AllowNamespace(ns.Name) {
+					continue
+				}
+
+				reply.Namespaces = append(reply.Namespaces, ns)
+			}
+
+			// Use the last index that affected the namespace table
+			index, err := state.Index("namespaces")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking
+			// query cannot be used.  We floor the index at one, since
+			// realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// GetNamespace is used to get a specific namespace
+func (n *Namespace) GetNamespace(args *structs.NamespaceSpecificRequest,
+	reply *structs.SingleNamespaceResponse) error {
+	if done, err := n.srv.forward("Namespace.GetNamespace", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "get_namespace"}, time.Now())
+
+	// Check capabilities for the given namespace
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNamespace(args.Name) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if args.Name == "" {
+		return fmt.Errorf("missing namespace name")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the namespace
+			out, err := state.NamespaceByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Namespace = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the namespace table
+				index, err := state.Index("namespaces")
+				if err != nil {
+					return err
+				}
+
+				// Ensure we never set the index to zero, otherwise a blocking
+				// query cannot be used.  We floor the index at one, since
+				// realistically the first write must have a higher index.
+				if index == 0 {
+					index = 1
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// GetNamespaces is used to get a set of namespaces
+func (n *Namespace) GetNamespaces(args *structs.NamespaceSetRequest,
+	reply *structs.NamespaceSetResponse) error {
+	if done, err := n.srv.forward("Namespace.GetNamespaces", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "get_namespaces"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Namespaces = make(map[string]*structs.Namespace, len(args.Namespaces))
+
+			// Look for the namespace
+			for _, namespace := range args.Namespaces {
+				out, err := state.NamespaceByName(ws, namespace)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Namespaces[namespace] = out
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("namespaces")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking
+			// query cannot be used.  We floor the index at one, since
+			// realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
@@ -0,0 +1,449 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// enableNamespaces flips the namespaces feature flag on for the test server.
+func enableNamespaces(t *testing.T, s *Server) {
+	t.Helper()
+	require.NoError(t, s.fsm.State().FeatureFlagsSet(1, &structs.FeatureFlags{
+		Flags: map[string]bool{structs.FeatureNamespaces: true},
+	}))
+}
+
+func TestNamespaceEndpoint_GetNamespace(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	ns := mock.Namespace()
+	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+
+	// Lookup the namespace
+	get := &structs.NamespaceSpecificRequest{
+		Name:         ns.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleNamespaceResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Equal(ns, resp.Namespace)
+}
+
+func TestNamespaceEndpoint_GetNamespace_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	state.UpsertNamespaces(1000, []*structs.Namespace{ns})
+
+	validToken := mock.CreatePolicyAndToken(t, state, 1002, "test-valid",
+		mock.NamespacePolicy(ns.Name, "read", nil))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1004, "test-invalid",
+		mock.NamespacePolicy("other", "read", nil))
+
+	get := &structs.NamespaceSpecificRequest{
+		Name:         ns.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Lookup the namespace without a token and expect failure
+	{
+		var resp structs.SingleNamespaceResponse
+		err := msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &resp)
+		require.NotNil(err)
+		require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an invalid token
+	get.AuthToken = invalidToken.SecretID
+	{
+		var resp structs.SingleNamespaceResponse
+		err := msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &resp)
+		require.NotNil(err)
+		require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a valid token
+	get.AuthToken = validToken.SecretID
+	{
+		var resp structs.SingleNamespaceResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &resp))
+		require.EqualValues(1000, resp.Index)
+		require.Equal(ns, resp.Namespace)
+	}
+
+	// Try with a root token
+	get.AuthToken = root.SecretID
+	{
+		var resp structs.SingleNamespaceResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", get, &resp))
+		require.EqualValues(1000, resp.Index)
+		require.Equal(ns, resp.Namespace)
+	}
+}
+
+func TestNamespaceEndpoint_GetNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	// Lookup the namespaces
+	get := &structs.NamespaceSetRequest{
+		Namespaces:   []string{ns1.Name, ns2.Name},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NamespaceSetResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespaces", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Namespaces, 2)
+	require.Contains(resp.Namespaces, ns1.Name)
+	require.Contains(resp.Namespaces, ns2.Name)
+}
+
+func TestNamespaceEndpoint_ListNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	// Lookup the namespaces
+	get := &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NamespaceListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Namespaces, 3)
+
+	// Lookup the namespaces by prefix
+	get = &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+			Prefix: ns1.Name[:4],
+		},
+	}
+	var resp2 structs.NamespaceListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp2))
+	require.EqualValues(1000, resp2.Index)
+	require.Len(resp2.Namespaces, 2)
+}
+
+func TestNamespaceEndpoint_ListNamespaces_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	validToken := mock.CreatePolicyAndToken(t, state, 1002, "test-valid",
+		mock.NamespacePolicy(ns1.Name, "read", nil))
+
+	get := &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Lookup the namespaces without a token and expect an empty list since
+	// the anonymous policy grants no namespace access
+	{
+		var resp structs.NamespaceListResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+		require.Len(resp.Namespaces, 0)
+	}
+
+	// Try with a token scoped to one namespace and expect a filtered list
+	get.AuthToken = validToken.SecretID
+	{
+		var resp structs.NamespaceListResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+		require.EqualValues(1000, resp.Index)
+		require.Len(resp.Namespaces, 1)
+		require.Equal(ns1.Name, resp.Namespaces[0].Name)
+	}
+
+	// Try with a root token and expect all namespaces
+	get.AuthToken = root.SecretID
+	{
+		var resp structs.NamespaceListResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", get, &resp))
+		require.EqualValues(1000, resp.Index)
+		require.Len(resp.Namespaces, 3)
+	}
+}
+
+func TestNamespaceEndpoint_UpsertNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	enableNamespaces(t, s1)
+
+	// Create the register request
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces:   []*structs.Namespace{ns1, ns2},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp))
+	require.NotEqual(uint64(0), resp.Index)
+
+	// Check we created the namespaces
+	state := s1.fsm.State()
+	out, err := state.NamespaceByName(nil, ns1.Name)
+	require.Nil(err)
+	require.NotNil(out)
+
+	out, err = state.NamespaceByName(nil, ns2.Name)
+	require.Nil(err)
+	require.NotNil(out)
+}
+
+func TestNamespaceEndpoint_UpsertNamespaces_FeatureDisabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request without enabling the feature flag
+	ns1 := mock.Namespace()
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces:   []*structs.Namespace{ns1},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "namespaces are disabled")
+
+	// Check we did not create the namespace
+	out, lookupErr := s1.fsm.State().NamespaceByName(nil, ns1.Name)
+	require.Nil(lookupErr)
+	require.Nil(out)
+}
+
+func TestNamespaceEndpoint_UpsertNamespaces_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	enableNamespaces(t, s1)
+	state := s1.fsm.State()
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1004, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil))
+
+	// Create the register request
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces:   []*structs.Namespace{ns1, ns2},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Try without a token and expect failure
+	{
+		var resp structs.GenericResponse
+		err := msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+		require.NotNil(err)
+		require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a non-management token and expect failure
+	req.AuthToken = invalidToken.SecretID
+	{
+		var resp structs.GenericResponse
+		err := msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+		require.NotNil(err)
+		require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a root token
+	req.AuthToken = root.SecretID
+	{
+		var resp structs.GenericResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp))
+		require.NotEqual(uint64(0), resp.Index)
+	}
+
+	// Check we created the namespaces
+	out, err := state.NamespaceByName(nil, ns1.Name)
+	require.Nil(err)
+	require.NotNil(out)
+
+	out, err = state.NamespaceByName(nil, ns2.Name)
+	require.Nil(err)
+	require.NotNil(out)
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	enableNamespaces(t, s1)
+
+	// Create the namespaces
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	// Delete the namespaces
+	req := &structs.NamespaceDeleteRequest{
+		Namespaces:   []string{ns1.Name, ns2.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp))
+	require.NotEqual(uint64(0), resp.Index)
+
+	// Check we deleted the namespaces
+	out, err := s1.fsm.State().NamespaceByName(nil, ns1.Name)
+	require.Nil(err)
+	require.Nil(out)
+
+	out, err = s1.fsm.State().NamespaceByName(nil, ns2.Name)
+	require.Nil(err)
+	require.Nil(out)
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces_Default(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	enableNamespaces(t, s1)
+
+	// Attempt to delete the default namespace
+	req := &structs.NamespaceDeleteRequest{
+		Namespaces:   []string{structs.DefaultNamespace},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "default")
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	enableNamespaces(t, s1)
+	state := s1.fsm.State()
+
+	// Create the namespace with a non-terminal job
+	ns1 := mock.Namespace()
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns1}))
+
+	job := mock.Job()
+	job.Namespace = ns1.Name
+	require.Nil(state.UpsertJob(1001, job))
+
+	// Delete the namespace
+	req := &structs.NamespaceDeleteRequest{
+		Namespaces:   []string{ns1.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "non-terminal")
+
+	// Check the namespace still exists
+	out, err := state.NamespaceByName(nil, ns1.Name)
+	require.Nil(err)
+	require.NotNil(out)
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces_FeatureDisabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create the namespace directly so it exists while the feature is off
+	ns1 := mock.Namespace()
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns1}))
+
+	// Attempt to delete it without enabling the feature flag
+	req := &structs.NamespaceDeleteRequest{
+		Namespaces:   []string{ns1.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "namespaces are disabled")
+
+	// The namespace remains queryable
+	out, lookupErr := state.NamespaceByName(nil, ns1.Name)
+	require.Nil(lookupErr)
+	require.NotNil(out)
+}
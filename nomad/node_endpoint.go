@@ -3,6 +3,7 @@ package nomad
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -387,6 +388,11 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_status"}, time.Now())
 
+	// Reject the heartbeat if the fault is injected
+	if n.srv.faults.shouldDropHeartbeat() {
+		return fmt.Errorf("heartbeat dropped by fault injection")
+	}
+
 	// Verify the arguments
 	if args.NodeID == "" {
 		return fmt.Errorf("missing node ID for client status update")
@@ -543,6 +549,15 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 		return fmt.Errorf("node not found")
 	}
 
+	// Enforce any Sentinel policies scoped to node drain
+	policyWarnings, err := n.enforceNodeDrain(args, node)
+	if err != nil {
+		return err
+	}
+	if policyWarnings != nil {
+		n.logger.Warn("node drain policy warning", "warnings", policyWarnings, "node_id", args.NodeID)
+	}
+
 	now := time.Now().UTC()
 
 	// Update the timestamp of when the node status was updated
@@ -706,6 +721,257 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	return nil
 }
 
+// filterNodes returns the nodes selected by the given batch filter.
+func (n *Node) filterNodes(filter *structs.NodeBatchFilter) ([]*structs.Node, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if filter.Match(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// BatchUpdateDrain is used to update the drain strategy of every node
+// selected by a filter in a single call.
+func (n *Node) BatchUpdateDrain(args *structs.NodeBatchDrainUpdateRequest,
+	reply *structs.NodeBatchUpdateResponse) error {
+	if done, err := n.srv.forward("Node.BatchUpdateDrain", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "batch_update_drain"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Select the nodes to update
+	nodes, err := n.filterNodes(args.Filter)
+	if err != nil {
+		return err
+	}
+	reply.Errors = make(map[string]string)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	// Build the per-node drain updates and node events
+	updates := make(map[string]*structs.DrainUpdate, len(nodes))
+	events := make(map[string]*structs.NodeEvent, len(nodes))
+	for _, node := range nodes {
+		update := &structs.DrainUpdate{
+			DrainStrategy: args.DrainStrategy.Copy(),
+			MarkEligible:  args.MarkEligible,
+		}
+
+		// Setup drain strategy
+		if update.DrainStrategy != nil {
+			// Mark start time for the drain
+			if node.DrainStrategy == nil {
+				update.DrainStrategy.StartedAt = now
+			} else {
+				update.DrainStrategy.StartedAt = node.DrainStrategy.StartedAt
+			}
+
+			// Mark the deadline time
+			if update.DrainStrategy.Deadline.Nanoseconds() > 0 {
+				update.DrainStrategy.ForceDeadline = now.Add(update.DrainStrategy.Deadline)
+			}
+		}
+		updates[node.ID] = update
+
+		// Construct the node event
+		event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemDrain)
+		if node.DrainStrategy == nil && update.DrainStrategy != nil {
+			event.SetMessage(NodeDrainEventDrainSet)
+		} else if node.DrainStrategy != nil && update.DrainStrategy != nil {
+			event.SetMessage(NodeDrainEventDrainUpdated)
+		} else if node.DrainStrategy != nil && update.DrainStrategy == nil {
+			event.SetMessage(NodeDrainEventDrainDisabled)
+		} else {
+			event = nil
+		}
+		if event != nil {
+			events[node.ID] = event
+		}
+	}
+
+	// Commit the updates via Raft
+	batch := &structs.BatchNodeUpdateDrainRequest{
+		Updates:      updates,
+		NodeEvents:   events,
+		UpdatedAt:    now.Unix(),
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := n.srv.raftApply(structs.BatchNodeUpdateDrainRequestType, batch)
+	if err != nil {
+		n.logger.Error("batch drain update failed", "error", err)
+		return err
+	}
+
+	// If nodes are transitioning to be eligible, create Node evaluations
+	// because there may be a System job registered that should be evaluated.
+	for _, node := range nodes {
+		reply.Updated = append(reply.Updated, node.ID)
+
+		if node.SchedulingEligibility == structs.NodeSchedulingIneligible &&
+			args.MarkEligible && args.DrainStrategy == nil {
+			if _, _, err := n.createNodeEvals(node.ID, index); err != nil {
+				n.logger.Error("eval creation failed", "error", err, "node_id", node.ID)
+				return err
+			}
+		}
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// BatchUpdateEligibility is used to update the scheduling eligibility of
+// every node selected by a filter in a single call. Nodes that cannot be
+// updated are reported in the reply rather than failing the whole batch.
+func (n *Node) BatchUpdateEligibility(args *structs.NodeBatchEligibilityUpdateRequest,
+	reply *structs.NodeBatchUpdateResponse) error {
+	if done, err := n.srv.forward("Node.BatchUpdateEligibility", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "batch_update_eligibility"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Check that only allowed types are set
+	switch args.Eligibility {
+	case structs.NodeSchedulingEligible, structs.NodeSchedulingIneligible:
+	default:
+		return fmt.Errorf("invalid scheduling eligibility %q", args.Eligibility)
+	}
+
+	// Select the nodes to update
+	nodes, err := n.filterNodes(args.Filter)
+	if err != nil {
+		return err
+	}
+	reply.Errors = make(map[string]string)
+
+	now := time.Now().Unix()
+	for _, node := range nodes {
+		if node.SchedulingEligibility == args.Eligibility {
+			continue // Nothing to do
+		}
+		if node.DrainStrategy != nil && args.Eligibility == structs.NodeSchedulingEligible {
+			reply.Errors[node.ID] = "can not set node's scheduling eligibility to eligible while it is draining"
+			continue
+		}
+
+		// Construct the node event
+		event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster)
+		if args.Eligibility == structs.NodeSchedulingEligible {
+			event.SetMessage(NodeEligibilityEventEligible)
+		} else {
+			event.SetMessage(NodeEligibilityEventIneligible)
+		}
+
+		// Commit this update via Raft
+		update := &structs.NodeUpdateEligibilityRequest{
+			NodeID:       node.ID,
+			Eligibility:  args.Eligibility,
+			NodeEvent:    event,
+			UpdatedAt:    now,
+			WriteRequest: args.WriteRequest,
+		}
+		outErr, index, err := n.srv.raftApply(structs.NodeUpdateEligibilityRequestType, update)
+		if err == nil && outErr != nil {
+			if applyErr, ok := outErr.(error); ok {
+				err = applyErr
+			}
+		}
+		if err != nil {
+			n.logger.Error("eligibility update failed", "error", err, "node_id", node.ID)
+			reply.Errors[node.ID] = err.Error()
+			continue
+		}
+
+		reply.Updated = append(reply.Updated, node.ID)
+		reply.Index = index
+	}
+	return nil
+}
+
+// BatchUpdateMeta is used to merge a metadata update into every node
+// selected by a filter in a single call.
+func (n *Node) BatchUpdateMeta(args *structs.NodeBatchMetaUpdateRequest,
+	reply *structs.NodeBatchUpdateResponse) error {
+	if done, err := n.srv.forward("Node.BatchUpdateMeta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "batch_update_meta"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if len(args.Meta) == 0 {
+		return fmt.Errorf("missing metadata update")
+	}
+
+	// Select the nodes to update
+	nodes, err := n.filterNodes(args.Filter)
+	if err != nil {
+		return err
+	}
+	reply.Errors = make(map[string]string)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	// Resolve the node IDs so the raft log replays deterministically
+	args.NodeIDs = make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		args.NodeIDs = append(args.NodeIDs, node.ID)
+	}
+	args.UpdatedAt = time.Now().Unix()
+
+	// Commit the update via Raft
+	_, index, err := n.srv.raftApply(structs.NodeBatchMetaUpdateRequestType, args)
+	if err != nil {
+		n.logger.Error("batch meta update failed", "error", err)
+		return err
+	}
+
+	reply.Updated = args.NodeIDs
+	reply.Index = index
+	return nil
+}
+
 // Evaluate is used to force a re-evaluation of the node
 func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUpdateResponse) error {
 	if done, err := n.srv.forward("Node.Evaluate", args, args, reply); done {
@@ -1289,17 +1555,145 @@ func (n *Node) List(args *structs.NodeListRequest,
 			}
 
 			var nodes []*structs.NodeListStub
+			paginator, err := newListPaginator(iter, args.QueryOptions,
+				func(raw interface{}) string {
+					return raw.(*structs.Node).ID
+				},
+				func(raw interface{}) error {
+					nodes = append(nodes, raw.(*structs.Node).Stub())
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
+			}
+			reply.QueryMeta.NextToken = nextToken
+			reply.Nodes = nodes
+
+			// Use the last index that affected the jobs table
+			index, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			n.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// inventoryAttribute returns whether a fingerprinted attribute is part of
+// the OS inventory used for fleet drift detection
+func inventoryAttribute(key string) bool {
+	switch {
+	case strings.HasPrefix(key, "kernel."):
+		return true
+	case strings.HasPrefix(key, "os."):
+		return true
+	case strings.HasPrefix(key, "driver.") && strings.HasSuffix(key, ".version"):
+		return true
+	}
+	return false
+}
+
+// InventoryReport computes the fleet OS inventory baseline from the
+// fingerprinted node attributes and reports the nodes that drift from it.
+// The baseline for each attribute is its most common value across the fleet.
+func (n *Node) InventoryReport(args *structs.NodeInventoryRequest,
+	reply *structs.NodeInventoryReportResponse) error {
+	if done, err := n.srv.forward("Node.InventoryReport", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "inventory_report"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Capture all the nodes
+			iter, err := state.Nodes(ws)
+			if err != nil {
+				return err
+			}
+
+			var nodes []*structs.Node
 			for {
 				raw := iter.Next()
 				if raw == nil {
 					break
 				}
-				node := raw.(*structs.Node)
-				nodes = append(nodes, node.Stub())
+				nodes = append(nodes, raw.(*structs.Node))
 			}
-			reply.Nodes = nodes
 
-			// Use the last index that affected the jobs table
+			// Tally the values for each inventory attribute
+			counts := make(map[string]map[string]int)
+			for _, node := range nodes {
+				for key, value := range node.Attributes {
+					if !inventoryAttribute(key) {
+						continue
+					}
+					if counts[key] == nil {
+						counts[key] = make(map[string]int)
+					}
+					counts[key][value]++
+				}
+			}
+
+			// The baseline is the most common value, ties broken
+			// lexically so the report is deterministic
+			baseline := make(map[string]string, len(counts))
+			for key, values := range counts {
+				var best string
+				bestCount := -1
+				for value, count := range values {
+					if count > bestCount || (count == bestCount && value < best) {
+						best = value
+						bestCount = count
+					}
+				}
+				baseline[key] = best
+			}
+			reply.Baseline = baseline
+
+			// Report each node deviating from the baseline
+			reply.Drifted = nil
+			for _, node := range nodes {
+				var drift map[string]string
+				for key, want := range baseline {
+					if have := node.Attributes[key]; have != want {
+						if drift == nil {
+							drift = make(map[string]string)
+						}
+						drift[key] = have
+					}
+				}
+				if drift != nil {
+					reply.Drifted = append(reply.Drifted, &structs.NodeInventoryDrift{
+						NodeID: node.ID,
+						Name:   node.Name,
+						Drift:  drift,
+					})
+				}
+			}
+			sort.Slice(reply.Drifted, func(i, j int) bool {
+				return reply.Drifted[i].NodeID < reply.Drifted[j].NodeID
+			})
+
+			// Use the last index that affected the nodes table
 			index, err := state.Index("nodes")
 			if err != nil {
 				return err
@@ -1847,6 +2241,126 @@ func (n *Node) DeriveSIToken(args *structs.DeriveSITokenRequest, reply *structs.
 	return nil
 }
 
+// DeriveWorkloadIdentity is used by the clients to request signed workload
+// identity tokens for the tasks of an allocation. The tokens are JWTs signed
+// with the active key of the server keyring and carry the allocation, job,
+// namespace and task in their claims.
+func (n *Node) DeriveWorkloadIdentity(args *structs.DeriveWorkloadIdentityRequest, reply *structs.DeriveWorkloadIdentityResponse) error {
+	setError := func(e error, recoverable bool) {
+		if e != nil {
+			if re, ok := e.(*structs.RecoverableError); ok {
+				reply.Error = re // No need to wrap if error is already a RecoverableError
+			} else {
+				reply.Error = structs.NewRecoverableError(e, recoverable).(*structs.RecoverableError)
+			}
+			n.logger.Error("DeriveWorkloadIdentity failed", "recoverable", recoverable, "error", e)
+		}
+	}
+
+	if done, err := n.srv.forward("Node.DeriveWorkloadIdentity", args, args, reply); done {
+		setError(err, structs.IsRecoverable(err) || err == structs.ErrNoLeader)
+		return nil
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "derive_workload_identity"}, time.Now())
+
+	// Verify the arguments
+	if err := args.Validate(); err != nil {
+		setError(err, false)
+		return nil
+	}
+
+	// Verify the following:
+	// * The Node exists and has the correct SecretID.
+	// * The Allocation exists on the specified Node and is not terminal.
+	// * The Allocation contains the given tasks.
+
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		setError(err, false)
+		return nil
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		setError(err, false)
+		return nil
+	}
+	if node == nil {
+		setError(errors.Errorf("Node %q does not exist", args.NodeID), false)
+		return nil
+	}
+	if node.SecretID != args.SecretID {
+		setError(errors.Errorf("SecretID mismatch"), false)
+		return nil
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		setError(err, false)
+		return nil
+	}
+	if alloc == nil {
+		setError(errors.Errorf("Allocation %q does not exist", args.AllocID), false)
+		return nil
+	}
+	if alloc.NodeID != args.NodeID {
+		setError(errors.Errorf("Allocation %q not running on node %q", args.AllocID, args.NodeID), false)
+		return nil
+	}
+	if alloc.TerminalStatus() {
+		setError(errors.Errorf("Cannot request workload identity for terminal allocation"), false)
+		return nil
+	}
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		setError(errors.Errorf("Allocation %q does not contain TaskGroup %q", args.AllocID, alloc.TaskGroup), false)
+		return nil
+	}
+	for _, task := range args.Tasks {
+		if tg.LookupTask(task) == nil {
+			setError(errors.Errorf("Task %q not found in allocation %q", task, args.AllocID), false)
+			return nil
+		}
+	}
+
+	// Fetch the active signing key. It is established on leader transition so
+	// its absence is transient.
+	key, err := snap.ActiveSigningKey(nil)
+	if err != nil {
+		setError(err, false)
+		return nil
+	}
+	if key == nil {
+		setError(errors.Errorf("no active workload identity signing key"), true)
+		return nil
+	}
+
+	// Sign an identity for each requested task
+	now := time.Now().Unix()
+	tokens := make(map[string]string, len(args.Tasks))
+	for _, task := range args.Tasks {
+		claims := map[string]interface{}{
+			"iss":                 "nomad",
+			"iat":                 now,
+			"sub":                 fmt.Sprintf("%s:%s:%s:%s", alloc.Namespace, alloc.JobID, alloc.ID, task),
+			"nomad_namespace":     alloc.Namespace,
+			"nomad_job_id":        alloc.JobID,
+			"nomad_allocation_id": alloc.ID,
+			"nomad_task":          task,
+		}
+		token, err := signIdentityClaims(key, claims)
+		if err != nil {
+			setError(err, false)
+			return nil
+		}
+		tokens[task] = token
+	}
+
+	reply.Tokens = tokens
+	n.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 func connectTasks(tg *structs.TaskGroup, tasks []string) ([]string, []connectTask) {
 	var notConnect []string
 	var usesConnect []connectTask
@@ -0,0 +1,13 @@
+// +build !ent
+
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// enforceNodeDrain is used to check any Sentinel policies for the node-drain
+// scope
+func (n *Node) enforceNodeDrain(args *structs.NodeUpdateDrainRequest, node *structs.Node) (error, error) {
+	return nil, nil
+}
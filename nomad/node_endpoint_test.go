@@ -23,6 +23,7 @@ import (
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
 )
 
 func TestClientEndpoint_Register(t *testing.T) {
@@ -1272,6 +1273,166 @@ func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_BatchUpdateDrain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Disable drainer to prevent drain from completing during test
+	s1.nodeDrainer.SetEnabled(false, nil)
+
+	// Create two nodes in one class and a third in another
+	state := s1.fsm.State()
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node3 := mock.Node()
+	node3.NodeClass = "other"
+	require.NoError(state.UpsertNode(2, node1))
+	require.NoError(state.UpsertNode(3, node2))
+	require.NoError(state.UpsertNode(4, node3))
+
+	// Drain every node in the first class
+	req := &structs.NodeBatchDrainUpdateRequest{
+		Filter: &structs.NodeBatchFilter{Class: node1.NodeClass},
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline: 10 * time.Second,
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeBatchUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp))
+	require.Len(resp.Updated, 2)
+	require.Contains(resp.Updated, node1.ID)
+	require.Contains(resp.Updated, node2.ID)
+	require.Empty(resp.Errors)
+
+	// Both nodes should be draining and ineligible
+	for _, nodeID := range []string{node1.ID, node2.ID} {
+		out, err := state.NodeByID(nil, nodeID)
+		require.NoError(err)
+		require.NotNil(out.DrainStrategy)
+		require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	}
+
+	// The unmatched node is untouched
+	out, err := state.NodeByID(nil, node3.ID)
+	require.NoError(err)
+	require.Nil(out.DrainStrategy)
+
+	// A request without a filter is an error
+	req.Filter = nil
+	err = msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateDrain", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing node filter")
+}
+
+func TestClientEndpoint_BatchUpdateEligibility(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Disable drainer to prevent drain from completing during test
+	s1.nodeDrainer.SetEnabled(false, nil)
+
+	// Create two nodes in the same class, one of which is draining
+	state := s1.fsm.State()
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{Deadline: time.Hour},
+	}
+	node2.SchedulingEligibility = structs.NodeSchedulingIneligible
+	require.NoError(state.UpsertNode(2, node1))
+	require.NoError(state.UpsertNode(3, node2))
+
+	// Mark the class ineligible; only the non-draining node changes
+	req := &structs.NodeBatchEligibilityUpdateRequest{
+		Filter:       &structs.NodeBatchFilter{Class: node1.NodeClass},
+		Eligibility:  structs.NodeSchedulingIneligible,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeBatchUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateEligibility", req, &resp))
+	require.Equal([]string{node1.ID}, resp.Updated)
+	require.Empty(resp.Errors)
+
+	out, err := state.NodeByID(nil, node1.ID)
+	require.NoError(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+
+	// Marking the class eligible reports the draining node as a failure
+	req.Eligibility = structs.NodeSchedulingEligible
+	var resp2 structs.NodeBatchUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateEligibility", req, &resp2))
+	require.Equal([]string{node1.ID}, resp2.Updated)
+	require.Len(resp2.Errors, 1)
+	require.Contains(resp2.Errors[node2.ID], "draining")
+
+	// An invalid eligibility is an error
+	req.Eligibility = "foo"
+	err = msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateEligibility", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid scheduling eligibility")
+}
+
+func TestClientEndpoint_BatchUpdateMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create two nodes, only one matching the attribute filter
+	state := s1.fsm.State()
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.Attributes["arch"] = "arm64"
+	require.NoError(state.UpsertNode(2, node1))
+	require.NoError(state.UpsertNode(3, node2))
+
+	// Set a new key and remove an existing one on the matched node
+	req := &structs.NodeBatchMetaUpdateRequest{
+		Filter: &structs.NodeBatchFilter{Attributes: map[string]string{"arch": "x86"}},
+		Meta: map[string]*string{
+			"rack":    helper.StringToPtr("r1"),
+			"pci-dss": nil,
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeBatchUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateMeta", req, &resp))
+	require.Equal([]string{node1.ID}, resp.Updated)
+
+	out, err := state.NodeByID(nil, node1.ID)
+	require.NoError(err)
+	require.Equal("r1", out.Meta["rack"])
+	require.NotContains(out.Meta, "pci-dss")
+	require.Equal("mysql", out.Meta["database"])
+
+	// The unmatched node is untouched
+	out, err = state.NodeByID(nil, node2.ID)
+	require.NoError(err)
+	require.NotContains(out.Meta, "rack")
+
+	// An empty update is an error
+	req.Meta = nil
+	err = msgpackrpc.CallWithCodec(codec, "Node.BatchUpdateMeta", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing metadata update")
+}
+
 func TestClientEndpoint_GetNode(t *testing.T) {
 	t.Parallel()
 
@@ -2543,6 +2704,66 @@ func TestClientEndpoint_Evaluate_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_InventoryReport(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a fleet where two nodes agree and one drifts
+	state := s1.fsm.State()
+	baselineAttrs := map[string]string{
+		"kernel.name":           "linux",
+		"kernel.version":        "5.4.0",
+		"os.name":               "ubuntu",
+		"os.version":            "20.04",
+		"driver.docker.version": "19.03.8",
+		"cpu.frequency":         "2000", // not part of the inventory
+	}
+
+	node1 := mock.Node()
+	node1.Attributes = baselineAttrs
+	require.NoError(t, state.UpsertNode(1000, node1))
+
+	node2 := mock.Node()
+	node2.Attributes = baselineAttrs
+	require.NoError(t, state.UpsertNode(1001, node2))
+
+	drifted := mock.Node()
+	drifted.Attributes = map[string]string{
+		"kernel.name":           "linux",
+		"kernel.version":        "4.15.0",
+		"os.name":               "ubuntu",
+		"os.version":            "20.04",
+		"driver.docker.version": "19.03.8",
+	}
+	require.NoError(t, state.UpsertNode(1002, drifted))
+
+	// Fetch the report
+	get := &structs.NodeInventoryRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodeInventoryReportResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.InventoryReport", get, &resp))
+	require.Equal(t, uint64(1002), resp.Index)
+
+	// The baseline covers only the inventory attributes
+	require.Equal(t, map[string]string{
+		"kernel.name":           "linux",
+		"kernel.version":        "5.4.0",
+		"os.name":               "ubuntu",
+		"os.version":            "20.04",
+		"driver.docker.version": "19.03.8",
+	}, resp.Baseline)
+
+	// Only the drifted node is reported
+	require.Len(t, resp.Drifted, 1)
+	require.Equal(t, drifted.ID, resp.Drifted[0].NodeID)
+	require.Equal(t, map[string]string{"kernel.version": "4.15.0"}, resp.Drifted[0].Drift)
+}
+
 func TestClientEndpoint_ListNodes(t *testing.T) {
 	t.Parallel()
 
@@ -2611,6 +2832,57 @@ func TestClientEndpoint_ListNodes(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_ListNodes_Pagination(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create nodes with ordered IDs in two datacenters.
+	state := s1.fsm.State()
+	ids := []string{
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+		"aaaabbbb-3350-4b4b-d185-0e1992ed43e9",
+		"aaaacccc-3350-4b4b-d185-0e1992ed43e9",
+	}
+	for i, id := range ids {
+		node := mock.Node()
+		node.ID = id
+		if i == 2 {
+			node.Datacenter = "dc2"
+		}
+		require.NoError(t, state.UpsertNode(uint64(1000+i), node))
+	}
+
+	// Fetch the first page.
+	get := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", PerPage: 2},
+	}
+	var resp structs.NodeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.List", get, &resp))
+	require.Len(t, resp.Nodes, 2)
+	require.Equal(t, ids[2], resp.NextToken)
+
+	// Fetch the second and last page.
+	get.QueryOptions.NextToken = resp.NextToken
+	var resp2 structs.NodeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.List", get, &resp2))
+	require.Len(t, resp2.Nodes, 1)
+	require.Equal(t, ids[2], resp2.Nodes[0].ID)
+	require.Equal(t, "", resp2.NextToken)
+
+	// Fetch with a filter expression.
+	get = &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Filter: `Datacenter == "dc2"`},
+	}
+	var resp3 structs.NodeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.List", get, &resp3))
+	require.Len(t, resp3.Nodes, 1)
+	require.Equal(t, ids[2], resp3.Nodes[0].ID)
+}
+
 func TestClientEndpoint_ListNodes_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -3212,6 +3484,93 @@ func TestClientEndpoint_DeriveSIToken_ConsulError(t *testing.T) {
 	r.True(response.Error.IsRecoverable()) // and is recoverable
 }
 
+func TestClientEndpoint_DeriveWorkloadIdentity(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the node
+	node := mock.Node()
+	err := state.UpsertNode(2, node)
+	r.NoError(err)
+
+	// Create an alloc on the node
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	err = state.UpsertAllocs(3, []*structs.Allocation{alloc})
+	r.NoError(err)
+
+	request := &structs.DeriveWorkloadIdentityRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		AllocID:      alloc.ID,
+		Tasks:        []string{task.Name},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var response structs.DeriveWorkloadIdentityResponse
+	err = msgpackrpc.CallWithCodec(codec, "Node.DeriveWorkloadIdentity", request, &response)
+	r.NoError(err)
+	r.Nil(response.Error)
+	r.NotEmpty(response.Tokens[task.Name])
+
+	// Decode the token and check the embedded claims
+	parsed, err := jwt.ParseSigned(response.Tokens[task.Name])
+	r.NoError(err)
+
+	active, err := state.ActiveSigningKey(nil)
+	r.NoError(err)
+	r.Equal(active.KeyID, parsed.Headers[0].KeyID)
+
+	claims := make(map[string]interface{})
+	r.NoError(parsed.UnsafeClaimsWithoutVerification(&claims))
+	r.Equal(alloc.ID, claims["nomad_allocation_id"])
+	r.Equal(task.Name, claims["nomad_task"])
+	r.Equal(alloc.Job.ID, claims["nomad_job_id"])
+	r.Equal(alloc.Namespace, claims["nomad_namespace"])
+}
+
+func TestClientEndpoint_DeriveWorkloadIdentity_BadSecret(t *testing.T) {
+	t.Parallel()
+	r := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	r.NoError(state.UpsertNode(2, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	r.NoError(state.UpsertAllocs(3, []*structs.Allocation{alloc}))
+
+	request := &structs.DeriveWorkloadIdentityRequest{
+		NodeID:       node.ID,
+		SecretID:     uuid.Generate(), // wrong secret
+		AllocID:      alloc.ID,
+		Tasks:        []string{alloc.Job.TaskGroups[0].Tasks[0].Name},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var response structs.DeriveWorkloadIdentityResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.DeriveWorkloadIdentity", request, &response)
+	r.NoError(err)
+	r.NotNil(response.Error)
+	r.Contains(response.Error.Error(), "SecretID mismatch")
+}
+
 func TestClientEndpoint_EmitEvents(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -4,14 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
+	"sort"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-msgpack/codec"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
@@ -335,6 +339,12 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		reply.Updated = respBool
 	}
 	reply.Index = index
+
+	// Update the eval broker with the new admission weights so they take
+	// effect without waiting for a leadership transition.
+	if reply.Updated {
+		op.srv.evalBroker.SetNamespaceWeights(args.Config.NamespaceAdmissionWeights)
+	}
 	return nil
 }
 
@@ -368,6 +378,466 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 	return nil
 }
 
+// SigningKeyringList is used to list the workload identity signing keyring.
+// Only the public key metadata is returned; the private key material never
+// leaves the servers.
+func (op *Operator) SigningKeyringList(args *structs.KeyringListRequest, reply *structs.KeyringListResponse) error {
+	if done, err := op.srv.forward("Operator.SigningKeyringList", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	state := op.srv.fsm.State()
+	iter, err := state.SigningKeys(nil)
+	if err != nil {
+		return err
+	}
+
+	reply.Keys = nil
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		reply.Keys = append(reply.Keys, raw.(*structs.SigningKey).Stub())
+	}
+
+	index, err := state.Index("signing_key")
+	if err != nil {
+		return err
+	}
+	reply.QueryMeta.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// SigningKeyringRotate is used to rotate the active workload identity signing
+// key. The previous keys are retained so outstanding identities remain
+// verifiable.
+func (op *Operator) SigningKeyringRotate(args *structs.KeyringRotateRequest, reply *structs.KeyringRotateResponse) error {
+	if done, err := op.srv.forward("Operator.SigningKeyringRotate", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Generate the new active key. Its data encryption key goes into the
+	// local keystore before the keyring entry that names it enters raft;
+	// peer servers replicate it from here out-of-band.
+	newKey, err := generateSigningKey()
+	if err != nil {
+		return err
+	}
+	encKey, err := generateEncryptionKey()
+	if err != nil {
+		return err
+	}
+	if err := op.srv.keystore.Put(newKey.KeyID, encKey); err != nil {
+		return err
+	}
+	upsert := []*structs.SigningKey{newKey}
+
+	// Deactivate the current active key in the same raft entry so the
+	// rotation is atomic
+	state := op.srv.fsm.State()
+	current, err := state.ActiveSigningKey(nil)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		deactivated := current.Copy()
+		deactivated.Active = false
+		upsert = append(upsert, deactivated)
+	}
+
+	// Apply the rotation
+	req := structs.SigningKeyUpsertRequest{Keys: upsert}
+	resp, index, err := op.srv.raftApply(structs.SigningKeyUpsertRequestType, req)
+	if err != nil {
+		op.logger.Error("failed applying signing keyring rotation", "error", err)
+		return err
+	} else if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Key = newKey.Stub()
+	reply.Index = index
+	return nil
+}
+
+// SigningKeyringRemove is used to remove a signing key from the keyring. The
+// active key cannot be removed, nor can a key that still seals stored
+// variables; those are re-wrapped with the active key when they are next
+// written.
+func (op *Operator) SigningKeyringRemove(args *structs.KeyringRemoveRequest, reply *structs.KeyringRemoveResponse) error {
+	if done, err := op.srv.forward("Operator.SigningKeyringRemove", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.KeyID == "" {
+		return fmt.Errorf("missing key ID")
+	}
+
+	state := op.srv.fsm.State()
+	key, err := state.SigningKeyByID(nil, args.KeyID)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("signing key not found")
+	}
+	if key.Active {
+		return fmt.Errorf("cannot remove the active signing key")
+	}
+
+	// Refuse to remove a key that still seals stored variables
+	iter, err := state.Variables(nil)
+	if err != nil {
+		return err
+	}
+	inUse := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.EncryptedVariable).KeyID == args.KeyID {
+			inUse++
+		}
+	}
+	if inUse > 0 {
+		return fmt.Errorf("signing key %s is in use by %d variables", args.KeyID, inUse)
+	}
+
+	// Apply the removal
+	resp, index, err := op.srv.raftApply(structs.SigningKeyDeleteRequestType, args)
+	if err != nil {
+		op.logger.Error("failed applying signing keyring removal", "error", err)
+		return err
+	} else if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	// Drop the local copy of the key's encryption key material
+	if err := op.srv.keystore.Delete(args.KeyID); err != nil {
+		op.logger.Warn("failed to remove local encryption key",
+			"key_id", args.KeyID, "error", err)
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// FeatureFlagsSet is used to update the cluster-wide feature flags.
+func (op *Operator) FeatureFlagsSet(args *structs.FeatureFlagsSetRequest, reply *structs.GenericResponse) error {
+	if done, err := op.srv.forward("Operator.FeatureFlagsSet", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Only registered flags may be toggled
+	flags := &structs.FeatureFlags{Flags: args.Flags}
+	if err := flags.Validate(); err != nil {
+		return err
+	}
+
+	// Apply the update
+	resp, index, err := op.srv.raftApply(structs.FeatureFlagsRequestType, args)
+	if err != nil {
+		op.logger.Error("failed applying feature flags", "error", err)
+		return err
+	} else if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// FeatureFlagsGet is used to retrieve the current cluster-wide feature flags.
+func (op *Operator) FeatureFlagsGet(args *structs.GenericRequest, reply *structs.FeatureFlagsResponse) error {
+	if done, err := op.srv.forward("Operator.FeatureFlagsGet", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	state := op.srv.fsm.State()
+	index, flags, err := state.FeatureFlags()
+	if err != nil {
+		return err
+	}
+	if flags == nil {
+		// No overrides have been set; report the registered defaults
+		flags = &structs.FeatureFlags{}
+	}
+
+	reply.FeatureFlags = flags
+	reply.QueryMeta.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// FreezeWindowsSet is used to replace the cluster's change-freeze windows.
+func (op *Operator) FreezeWindowsSet(args *structs.FreezeWindowsSetRequest, reply *structs.GenericResponse) error {
+	if done, err := op.srv.forward("Operator.FreezeWindowsSet", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	windows := &structs.FreezeWindows{Windows: args.Windows}
+	if err := windows.Validate(); err != nil {
+		return err
+	}
+
+	// Apply the update
+	resp, index, err := op.srv.raftApply(structs.FreezeWindowsRequestType, args)
+	if err != nil {
+		op.logger.Error("failed applying freeze windows", "error", err)
+		return err
+	} else if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// FreezeWindowsGet is used to retrieve the cluster's change-freeze windows.
+func (op *Operator) FreezeWindowsGet(args *structs.GenericRequest, reply *structs.FreezeWindowsResponse) error {
+	if done, err := op.srv.forward("Operator.FreezeWindowsGet", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	state := op.srv.fsm.State()
+	index, windows, err := state.FreezeWindows()
+	if err != nil {
+		return err
+	}
+	if windows != nil {
+		reply.FreezeWindows = *windows
+	}
+	reply.QueryMeta.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// ClusterHeadroom computes how many instances of a hypothetical task shape
+// could still be placed on the cluster's ready nodes, grouped by datacenter
+// and node class.
+func (op *Operator) ClusterHeadroom(args *structs.ClusterHeadroomRequest, reply *structs.ClusterHeadroomResponse) error {
+	if done, err := op.srv.forward("Operator.ClusterHeadroom", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.CPU <= 0 && args.MemoryMB <= 0 && args.Ports <= 0 {
+		return fmt.Errorf("headroom requires a task shape with cpu, memory or ports")
+	}
+
+	state := op.srv.fsm.State()
+	ws := memdb.NewWatchSet()
+	iter, err := state.Nodes(ws)
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]*structs.ClusterHeadroomEntry)
+	for i := iter.Next(); i != nil; i = iter.Next() {
+		node := i.(*structs.Node)
+		if node.Status != structs.NodeStatusReady || node.SchedulingEligibility != structs.NodeSchedulingEligible {
+			continue
+		}
+
+		allocs, err := state.AllocsByNode(ws, node.ID)
+		if err != nil {
+			return err
+		}
+
+		key := node.Datacenter + "/" + node.NodeClass
+		entry, ok := entries[key]
+		if !ok {
+			entry = &structs.ClusterHeadroomEntry{
+				Datacenter: node.Datacenter,
+				NodeClass:  node.NodeClass,
+			}
+			entries[key] = entry
+		}
+		entry.Nodes++
+		entry.Fit += nodeHeadroom(node, allocs, args)
+	}
+
+	reply.Headroom = make([]*structs.ClusterHeadroomEntry, 0, len(entries))
+	for _, entry := range entries {
+		reply.Headroom = append(reply.Headroom, entry)
+		reply.Total += entry.Fit
+	}
+	sort.Slice(reply.Headroom, func(i, j int) bool {
+		a, b := reply.Headroom[i], reply.Headroom[j]
+		if a.Datacenter != b.Datacenter {
+			return a.Datacenter < b.Datacenter
+		}
+		return a.NodeClass < b.NodeClass
+	})
+
+	index, err := state.Index("nodes")
+	if err != nil {
+		return err
+	}
+	reply.QueryMeta.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// MetricsHistory returns the server's retained history of cluster gauge
+// samples so operators get immediate context during incidents without
+// consulting external monitoring.
+func (op *Operator) MetricsHistory(args *structs.MetricsHistoryRequest, reply *structs.MetricsHistoryResponse) error {
+	if done, err := op.srv.forward("Operator.MetricsHistory", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	since := time.Time{}
+	if args.Since > 0 {
+		since = time.Unix(0, args.Since)
+	}
+
+	reply.Samples = op.srv.metricsHistory.since(since)
+	reply.Interval = metricsHistoryInterval
+	reply.Retention = metricsHistoryRetention
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// VaultStatus returns the status of the leader's Vault integration, covering
+// token renewal and the token revocation backlog.
+func (op *Operator) VaultStatus(args *structs.VaultStatusRequest, reply *structs.VaultStatusResponse) error {
+	if done, err := op.srv.forward("Operator.VaultStatus", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Status = op.srv.vault.Status()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// nodeHeadroom returns how many instances of the requested shape fit on the
+// node given its current allocations.
+func nodeHeadroom(node *structs.Node, allocs []*structs.Allocation, args *structs.ClusterHeadroomRequest) int {
+	used := new(structs.ComparableResources)
+	for _, alloc := range allocs {
+		// Do not consider the resource impact of terminal allocations
+		if alloc.TerminalStatus() {
+			continue
+		}
+		used.Add(alloc.ComparableResources())
+	}
+
+	available := node.ComparableResources()
+	available.Subtract(node.ComparableReservedResources())
+	available.Subtract(used)
+
+	fit := math.MaxInt32
+	if args.CPU > 0 {
+		fit = helper.IntMin(fit, int(available.Flattened.Cpu.CpuShares/args.CPU))
+	}
+	if args.MemoryMB > 0 {
+		fit = helper.IntMin(fit, int(available.Flattened.Memory.MemoryMB/args.MemoryMB))
+	}
+	if args.Ports > 0 {
+		netIdx := structs.NewNetworkIndex()
+		defer netIdx.Release()
+		netIdx.SetNode(node)
+		netIdx.AddAllocs(allocs)
+
+		// An instance's dynamic ports must all come from a single address,
+		// so take the best available IP on the node.
+		free := 0
+		for _, network := range node.ComparableResources().Flattened.Networks {
+			if network.IP == "" {
+				continue
+			}
+			free = helper.IntMax(free, netIdx.AvailableDynamicPorts(network.IP))
+		}
+		fit = helper.IntMin(fit, free/args.Ports)
+	}
+
+	return helper.IntMax(fit, 0)
+}
+
 func (op *Operator) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := op.srv.findRegionServer(region)
 	if err != nil {
@@ -436,6 +436,123 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 	require.False(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
 }
 
+func TestOperator_SigningKeyringRotate(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	require := require.New(t)
+
+	// Leader election establishes the initial active key
+	listArg := structs.KeyringListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	var listReply structs.KeyringListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringList", &listArg, &listReply))
+	require.Len(listReply.Keys, 1)
+	require.True(listReply.Keys[0].Active)
+	initialKeyID := listReply.Keys[0].KeyID
+
+	// Rotate the keyring
+	rotateArg := structs.KeyringRotateRequest{}
+	rotateArg.Region = s1.config.Region
+
+	var rotateReply structs.KeyringRotateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRotate", &rotateArg, &rotateReply))
+	require.NotNil(rotateReply.Key)
+	require.True(rotateReply.Key.Active)
+	require.NotEqual(initialKeyID, rotateReply.Key.KeyID)
+
+	// The old key is retained but no longer active
+	var listReply2 structs.KeyringListResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringList", &listArg, &listReply2))
+	require.Len(listReply2.Keys, 2)
+	for _, key := range listReply2.Keys {
+		switch key.KeyID {
+		case initialKeyID:
+			require.False(key.Active)
+		case rotateReply.Key.KeyID:
+			require.True(key.Active)
+		default:
+			t.Fatalf("unexpected key: %s", key.KeyID)
+		}
+	}
+
+	// The state store agrees on the active key
+	active, err := s1.fsm.State().ActiveSigningKey(nil)
+	require.NoError(err)
+	require.NotNil(active)
+	require.Equal(rotateReply.Key.KeyID, active.KeyID)
+}
+
+func TestOperator_SigningKeyringRemove(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	require := require.New(t)
+	state := s1.fsm.State()
+
+	// Leader election establishes the initial active key
+	initial, err := state.ActiveSigningKey(nil)
+	require.NoError(err)
+	require.NotNil(initial)
+
+	// Seal a variable with the initial key
+	upsertArg := structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}},
+		WriteRequest: structs.WriteRequest{Region: s1.config.Region},
+	}
+	var upsertReply structs.VariablesUpsertResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", &upsertArg, &upsertReply))
+
+	// The active key cannot be removed
+	removeArg := structs.KeyringRemoveRequest{KeyID: initial.KeyID}
+	removeArg.Region = s1.config.Region
+
+	var removeReply structs.KeyringRemoveResponse
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRemove", &removeArg, &removeReply)
+	require.Error(err)
+	require.Contains(err.Error(), "active signing key")
+
+	// Rotate so the initial key is inactive, but it still seals the variable
+	rotateArg := structs.KeyringRotateRequest{}
+	rotateArg.Region = s1.config.Region
+	var rotateReply structs.KeyringRotateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRotate", &rotateArg, &rotateReply))
+
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRemove", &removeArg, &removeReply)
+	require.Error(err)
+	require.Contains(err.Error(), "in use by 1 variables")
+
+	// Rewriting the variable re-wraps it with the new active key
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", &upsertArg, &upsertReply))
+
+	// Now the old key can be removed
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRemove", &removeArg, &removeReply))
+	require.NotZero(removeReply.Index)
+
+	out, err := state.SigningKeyByID(nil, initial.KeyID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Removing an unknown key is an error
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SigningKeyringRemove", &removeArg, &removeReply)
+	require.Error(err)
+	require.Contains(err.Error(), "signing key not found")
+}
+
 func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
 	t.Parallel()
 
@@ -981,3 +1098,187 @@ func TestOperator_SnapshotRestore_ACL(t *testing.T) {
 		})
 	}
 }
+
+func TestOperator_ClusterHeadroom(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert a ready node and an ineligible one that must be skipped.
+	state := s1.fsm.State()
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(1000, node))
+
+	ineligible := mock.Node()
+	ineligible.SchedulingEligibility = structs.NodeSchedulingIneligible
+	require.NoError(t, state.UpsertNode(1001, ineligible))
+
+	get := &structs.ClusterHeadroomRequest{
+		CPU:          500,
+		MemoryMB:     256,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.ClusterHeadroomResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClusterHeadroom", get, &resp))
+	require.NotZero(t, resp.Index)
+
+	require.Len(t, resp.Headroom, 1)
+	entry := resp.Headroom[0]
+	require.Equal(t, node.Datacenter, entry.Datacenter)
+	require.Equal(t, node.NodeClass, entry.NodeClass)
+	require.Equal(t, 1, entry.Nodes)
+	require.Equal(t, entry.Fit, resp.Total)
+
+	// CPU is the constraining dimension: (4000 - 100 reserved) / 500
+	require.Equal(t, 7, entry.Fit)
+
+	// A shape without any dimensions is rejected.
+	get.CPU, get.MemoryMB = 0, 0
+	err := msgpackrpc.CallWithCodec(codec, "Operator.ClusterHeadroom", get, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "task shape")
+}
+
+func TestOperator_ClusterHeadroom_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	get := &structs.ClusterHeadroomRequest{
+		CPU:          500,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try without a token and expect permission denied
+	var resp structs.ClusterHeadroomResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.ClusterHeadroom", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with an invalid token and expect permission denied
+	deniedToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+	get.AuthToken = deniedToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.ClusterHeadroom", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a management token
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClusterHeadroom", get, &resp))
+}
+
+func TestOperator_MetricsHistory(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Record a couple of samples directly rather than waiting for the
+	// collector to tick.
+	now := time.Now()
+	old := &structs.ClusterMetricsSample{Timestamp: now.Add(-30 * time.Minute)}
+	s1.metricsHistory.record(old)
+	recent, err := s1.sampleClusterMetrics()
+	require.NoError(t, err)
+	s1.metricsHistory.record(recent)
+
+	get := &structs.MetricsHistoryRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.MetricsHistoryResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MetricsHistory", get, &resp))
+	require.Len(t, resp.Samples, 2)
+	require.Equal(t, metricsHistoryInterval, resp.Interval)
+	require.Equal(t, metricsHistoryRetention, resp.Retention)
+
+	// Filter out the older sample.
+	get.Since = now.Add(-time.Minute).UnixNano()
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MetricsHistory", get, &resp))
+	require.Len(t, resp.Samples, 1)
+	require.Equal(t, recent.Timestamp.Unix(), resp.Samples[0].Timestamp.Unix())
+}
+
+func TestOperator_VaultStatus(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	get := &structs.VaultStatusRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.VaultStatusResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.VaultStatus", get, &resp))
+
+	// Vault is not configured in the test server.
+	require.NotNil(t, resp.Status)
+	require.False(t, resp.Status.Enabled)
+	require.Zero(t, resp.Status.RevocationBacklog)
+}
+
+func TestOperator_VaultStatus_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	get := &structs.VaultStatusRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try without a token and expect permission denied
+	var resp structs.VaultStatusResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.VaultStatus", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with an invalid token and expect permission denied
+	deniedToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+	get.AuthToken = deniedToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.VaultStatus", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a management token
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.VaultStatus", get, &resp))
+}
+
+func TestOperator_MetricsHistory_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	get := &structs.MetricsHistoryRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try without a token and expect permission denied
+	var resp structs.MetricsHistoryResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.MetricsHistory", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with an invalid token and expect permission denied
+	deniedToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+	get.AuthToken = deniedToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.MetricsHistory", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a management token
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MetricsHistory", get, &resp))
+}
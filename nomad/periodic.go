@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 
@@ -23,9 +24,17 @@ type PeriodicDispatch struct {
 	dispatcher JobEvalDispatcher
 	enabled    bool
 
+	// notifier publishes launch failures to configured webhook targets and
+	// may be nil when no targets are configured.
+	notifier *LaunchFailureNotifier
+
 	tracked map[structs.NamespacedID]*structs.Job
 	heap    *periodicHeap
 
+	// launches holds the most recent launch timing records per tracked job,
+	// oldest first. Records are kept in memory by the active dispatcher only.
+	launches map[structs.NamespacedID][]*structs.PeriodicLaunchRecord
+
 	updateCh chan struct{}
 	stopFn   context.CancelFunc
 	logger   log.Logger
@@ -162,11 +171,32 @@ func NewPeriodicDispatch(logger log.Logger, dispatcher JobEvalDispatcher) *Perio
 		dispatcher: dispatcher,
 		tracked:    make(map[structs.NamespacedID]*structs.Job),
 		heap:       NewPeriodicHeap(),
+		launches:   make(map[structs.NamespacedID][]*structs.PeriodicLaunchRecord),
 		updateCh:   make(chan struct{}, 1),
 		logger:     logger.Named("periodic"),
 	}
 }
 
+// SetLaunchFailureNotifier configures the notifier launch failures are
+// published to. It should be set before the dispatcher is enabled.
+func (p *PeriodicDispatch) SetLaunchFailureNotifier(notifier *LaunchFailureNotifier) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.notifier = notifier
+}
+
+// notifyLaunchFailure publishes a launch failure to the configured notifier,
+// if there is one.
+func (p *PeriodicDispatch) notifyLaunchFailure(job *structs.Job, launchTime time.Time, err error) {
+	p.l.RLock()
+	notifier := p.notifier
+	p.l.RUnlock()
+
+	if notifier != nil {
+		notifier.Notify(job, launchTime, err)
+	}
+}
+
 // SetEnabled is used to control if the periodic dispatcher is enabled. It
 // should only be enabled on the active leader. Disabling an active dispatcher
 // will stop any launched go routine and flush the dispatcher.
@@ -225,7 +255,9 @@ func (p *PeriodicDispatch) Add(job *structs.Job) error {
 	_, tracked := p.tracked[tuple]
 	if disabled {
 		if tracked {
-			p.removeLocked(tuple)
+			// A stop keeps the tracked state around so the cron state
+			// survives; becoming non-periodic or parameterized purges it.
+			p.removeLocked(tuple, !job.Stopped())
 		}
 
 		// If the job is disabled and we aren't tracking it, do nothing.
@@ -238,7 +270,7 @@ func (p *PeriodicDispatch) Add(job *structs.Job) error {
 	if err != nil {
 		return fmt.Errorf("failed adding job %s: %v", job.NamespacedID(), err)
 	}
-	if tracked {
+	if tracked && p.heap.Contains(job) {
 		if err := p.heap.Update(job, next); err != nil {
 			return fmt.Errorf("failed to update job %q (%s) launch time: %v", job.ID, job.Namespace, err)
 		}
@@ -261,18 +293,18 @@ func (p *PeriodicDispatch) Add(job *structs.Job) error {
 
 // Remove stops tracking the passed job. If the job is not tracked, it is a
 // no-op.
-func (p *PeriodicDispatch) Remove(namespace, jobID string) error {
+func (p *PeriodicDispatch) Remove(namespace, jobID string, purge bool) error {
 	p.l.Lock()
 	defer p.l.Unlock()
 	return p.removeLocked(structs.NamespacedID{
 		ID:        jobID,
 		Namespace: namespace,
-	})
+	}, purge)
 }
 
 // Remove stops tracking the passed job. If the job is not tracked, it is a
 // no-op. It assumes this is called while a lock is held.
-func (p *PeriodicDispatch) removeLocked(jobID structs.NamespacedID) error {
+func (p *PeriodicDispatch) removeLocked(jobID structs.NamespacedID, purge bool) error {
 	// Do nothing if not enabled
 	if !p.enabled {
 		return nil
@@ -283,9 +315,21 @@ func (p *PeriodicDispatch) removeLocked(jobID structs.NamespacedID) error {
 		return nil
 	}
 
-	delete(p.tracked, jobID)
-	if err := p.heap.Remove(job); err != nil {
-		return fmt.Errorf("failed to remove tracked job %q (%s): %v", jobID.ID, jobID.Namespace, err)
+	if purge {
+		delete(p.tracked, jobID)
+		delete(p.launches, jobID)
+	} else {
+		// A stop without purge keeps the job tracked so its cron state
+		// survives, but it no longer launches children.
+		stopped := job.Copy()
+		stopped.Stop = true
+		p.tracked[jobID] = stopped
+	}
+
+	if p.heap.Contains(job) {
+		if err := p.heap.Remove(job); err != nil {
+			return fmt.Errorf("failed to remove tracked job %q (%s): %v", jobID.ID, jobID.Namespace, err)
+		}
 	}
 
 	// Signal an update.
@@ -413,18 +457,77 @@ func (p *PeriodicDispatch) nextLaunch() (*structs.Job, time.Time) {
 func (p *PeriodicDispatch) createEval(periodicJob *structs.Job, time time.Time) (*structs.Evaluation, error) {
 	derived, err := p.deriveJob(periodicJob, time)
 	if err != nil {
+		p.notifyLaunchFailure(periodicJob, time, err)
 		return nil, err
 	}
 
 	eval, err := p.dispatcher.DispatchJob(derived)
 	if err != nil {
 		p.logger.Error("failed to dispatch job", "job", periodicJob.NamespacedID(), "error", err)
+		p.notifyLaunchFailure(periodicJob, time, err)
 		return nil, err
 	}
 
+	p.recordLaunch(periodicJob, time)
 	return eval, nil
 }
 
+// maxLaunchRecords is the number of launch timing records retained per
+// periodic job.
+const maxLaunchRecords = 32
+
+// recordLaunch stores the timing of a launch of the passed job and emits its
+// launch delay as a metric. The dispatch time is taken to be now.
+func (p *PeriodicDispatch) recordLaunch(job *structs.Job, scheduled time.Time) {
+	record := &structs.PeriodicLaunchRecord{
+		ScheduledTime: scheduled,
+		DispatchTime:  time.Now(),
+	}
+	metrics.AddSampleWithLabels([]string{"nomad", "periodic", "launch_delay"},
+		float32(record.LaunchDelay().Milliseconds()),
+		[]metrics.Label{
+			{Name: "job", Value: job.ID},
+			{Name: "namespace", Value: job.Namespace},
+		})
+
+	tuple := structs.NamespacedID{
+		ID:        job.ID,
+		Namespace: job.Namespace,
+	}
+
+	p.l.Lock()
+	defer p.l.Unlock()
+	records := append(p.launches[tuple], record)
+	if len(records) > maxLaunchRecords {
+		records = records[len(records)-maxLaunchRecords:]
+	}
+	p.launches[tuple] = records
+}
+
+// LaunchStats returns the retained launch timing records for the passed job,
+// oldest first. Records are only held by the active dispatcher, so a disabled
+// dispatcher returns nothing.
+func (p *PeriodicDispatch) LaunchStats(namespace, jobID string) []*structs.PeriodicLaunchRecord {
+	tuple := structs.NamespacedID{
+		ID:        jobID,
+		Namespace: namespace,
+	}
+
+	p.l.RLock()
+	defer p.l.RUnlock()
+	records := p.launches[tuple]
+	if len(records) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.PeriodicLaunchRecord, len(records))
+	for i, record := range records {
+		copy := *record
+		out[i] = &copy
+	}
+	return out
+}
+
 // deriveJob instantiates a new job based on the passed periodic job and the
 // launch time.
 func (p *PeriodicDispatch) deriveJob(periodicJob *structs.Job, time time.Time) (
@@ -436,7 +539,7 @@ func (p *PeriodicDispatch) deriveJob(periodicJob *structs.Job, time time.Time) (
 			p.logger.Error("deriving child job from periodic job failed; deregistering from periodic runner",
 				"job", periodicJob.NamespacedID(), "error", r)
 
-			p.Remove(periodicJob.Namespace, periodicJob.ID)
+			p.Remove(periodicJob.Namespace, periodicJob.ID, true)
 			derived = nil
 			err = fmt.Errorf("Failed to create a copy of the periodic job %q (%s): %v",
 				periodicJob.ID, periodicJob.Namespace, r)
@@ -480,6 +583,7 @@ func (p *PeriodicDispatch) flush() {
 	p.updateCh = make(chan struct{}, 1)
 	p.tracked = make(map[structs.NamespacedID]*structs.Job)
 	p.heap = NewPeriodicHeap()
+	p.launches = make(map[structs.NamespacedID][]*structs.PeriodicLaunchRecord)
 	p.stopFn = nil
 }
 
@@ -2,6 +2,8 @@ package nomad
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -67,3 +69,96 @@ func (p *Periodic) Force(args *structs.PeriodicForceRequest, reply *structs.Peri
 	reply.Index = eval.CreateIndex
 	return nil
 }
+
+// LaunchStats returns the recent launch timing records of a periodic job so
+// scheduled vs actual launch times can be compared. The records are held in
+// memory by the leader's periodic dispatcher, so the request is always served
+// by the leader.
+func (p *Periodic) LaunchStats(args *structs.PeriodicLaunchStatsRequest, reply *structs.PeriodicLaunchStatsResponse) error {
+	if done, err := p.srv.forward("Periodic.LaunchStats", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "periodic", "launch_stats"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := p.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for launch stats")
+	}
+
+	// Lookup the job
+	snap, err := p.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	job, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job not found")
+	}
+
+	if !job.IsPeriodic() {
+		return fmt.Errorf("can't get launch stats for non-periodic job")
+	}
+
+	launches := p.srv.periodicDispatcher.LaunchStats(args.RequestNamespace(), job.ID)
+
+	// Fill in when the first allocation of each launch started running.
+	for _, launch := range launches {
+		childID := fmt.Sprintf("%s%s%d", job.ID, structs.PeriodicLaunchSuffix, launch.ScheduledTime.Unix())
+		allocs, err := snap.AllocsByJob(ws, job.Namespace, childID, false)
+		if err != nil {
+			return err
+		}
+
+		for _, alloc := range allocs {
+			for _, state := range alloc.TaskStates {
+				if state.StartedAt.IsZero() {
+					continue
+				}
+				if launch.AllocStartTime.IsZero() || state.StartedAt.Before(launch.AllocStartTime) {
+					launch.AllocStartTime = state.StartedAt
+				}
+			}
+		}
+	}
+
+	reply.Launches = launches
+	reply.P95LaunchDelay = p95LaunchDelay(launches)
+
+	// Use the last index that affected the jobs table
+	index, err := snap.Index("jobs")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	p.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// p95LaunchDelay returns the 95th percentile launch delay of the passed
+// records, or zero if there are none.
+func p95LaunchDelay(launches []*structs.PeriodicLaunchRecord) time.Duration {
+	if len(launches) == 0 {
+		return 0
+	}
+
+	delays := make([]time.Duration, len(launches))
+	for i, launch := range launches {
+		delays[i] = launch.LaunchDelay()
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+
+	idx := int(math.Ceil(0.95*float64(len(delays)))) - 1
+	return delays[idx]
+}
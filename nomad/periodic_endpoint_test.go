@@ -176,3 +176,150 @@ func TestPeriodicEndpoint_Force_NonPeriodic(t *testing.T) {
 		t.Fatalf("Force on non-periodic job should err")
 	}
 }
+
+func TestPeriodicEndpoint_LaunchStats(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create and insert a periodic job.
+	job := mock.PeriodicJob()
+	if err := state.UpsertJob(100, job); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	s1.periodicDispatcher.Add(job)
+
+	// Force launch it so a record is kept.
+	if _, err := s1.periodicDispatcher.ForceRun(job.Namespace, job.ID); err != nil {
+		t.Fatalf("ForceRun failed: %v", err)
+	}
+
+	// Fetch the launch stats.
+	req := &structs.PeriodicLaunchStatsRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.PeriodicLaunchStatsResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Index == 0 {
+		t.Fatalf("bad index: %d", resp.Index)
+	}
+	if len(resp.Launches) != 1 {
+		t.Fatalf("expected one launch record; got %d", len(resp.Launches))
+	}
+
+	launch := resp.Launches[0]
+	if launch.DispatchTime.Before(launch.ScheduledTime) {
+		t.Fatalf("bad record: %+v", launch)
+	}
+	if !launch.AllocStartTime.IsZero() {
+		t.Fatalf("expected zero alloc start time; got %v", launch.AllocStartTime)
+	}
+	if resp.P95LaunchDelay != launch.LaunchDelay() {
+		t.Fatalf("bad p95 launch delay: %v", resp.P95LaunchDelay)
+	}
+}
+
+func TestPeriodicEndpoint_LaunchStats_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	state := s1.fsm.State()
+	assert := assert.New(t)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create and insert a periodic job.
+	job := mock.PeriodicJob()
+	assert.Nil(state.UpsertJob(100, job))
+	assert.Nil(s1.periodicDispatcher.Add(job))
+
+	req := &structs.PeriodicLaunchStatsRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Try with no token and expect permission denied
+	{
+		var resp structs.PeriodicLaunchStatsResponse
+		err := msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an invalid token and expect permission denied
+	{
+		invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "invalid", mock.NodePolicy(acl.PolicyWrite))
+		req.AuthToken = invalidToken.SecretID
+		var resp structs.PeriodicLaunchStatsResponse
+		err := msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp)
+		assert.NotNil(err)
+		assert.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Fetch the response with a valid token
+	{
+		policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+		token := mock.CreatePolicyAndToken(t, state, 1005, "valid", policy)
+		req.AuthToken = token.SecretID
+		var resp structs.PeriodicLaunchStatsResponse
+		assert.Nil(msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp))
+		assert.NotEqual(uint64(0), resp.Index)
+	}
+
+	// Fetch the response with a management token
+	{
+		req.AuthToken = root.SecretID
+		var resp structs.PeriodicLaunchStatsResponse
+		assert.Nil(msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp))
+		assert.NotEqual(uint64(0), resp.Index)
+	}
+}
+
+func TestPeriodicEndpoint_LaunchStats_NonPeriodic(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create and insert a non-periodic job.
+	job := mock.Job()
+	if err := state.UpsertJob(100, job); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	req := &structs.PeriodicLaunchStatsRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.PeriodicLaunchStatsResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Periodic.LaunchStats", req, &resp); err == nil {
+		t.Fatalf("LaunchStats on non-periodic job should err")
+	}
+}
@@ -0,0 +1,154 @@
+package nomad
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// notifierBufferSize is the number of pending notifications buffered
+	// before new failures are dropped.
+	notifierBufferSize = 64
+
+	// notifierMaxRetries is the number of delivery attempts made per
+	// webhook target before a notification is given up on.
+	notifierMaxRetries = 3
+
+	// notifierBaseBackoff is the delay after the first failed delivery
+	// attempt, doubling with each subsequent attempt.
+	notifierBaseBackoff = 1 * time.Second
+
+	// notifierRequestTimeout bounds a single webhook request.
+	notifierRequestTimeout = 10 * time.Second
+)
+
+// LaunchFailureNotification is the JSON payload posted to webhook targets
+// when a periodic job fails to launch.
+type LaunchFailureNotification struct {
+	Source     string    `json:"source"`
+	JobID      string    `json:"job_id"`
+	Namespace  string    `json:"namespace"`
+	LaunchTime time.Time `json:"launch_time"`
+	Error      string    `json:"error"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// LaunchFailureNotifier delivers periodic launch failures to configured
+// webhook targets. Deliveries happen asynchronously with bounded retries and
+// exponential backoff so a slow or down target never blocks the periodic
+// dispatcher.
+type LaunchFailureNotifier struct {
+	// targets are the webhook URLs each notification is posted to
+	targets []string
+
+	client *http.Client
+
+	notifyCh chan *LaunchFailureNotification
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	logger log.Logger
+}
+
+// NewLaunchFailureNotifier returns a started notifier posting to the given
+// webhook targets.
+func NewLaunchFailureNotifier(logger log.Logger, targets []string) *LaunchFailureNotifier {
+	n := &LaunchFailureNotifier{
+		targets:  targets,
+		client:   &http.Client{Timeout: notifierRequestTimeout},
+		notifyCh: make(chan *LaunchFailureNotification, notifierBufferSize),
+		stopCh:   make(chan struct{}),
+		logger:   logger.Named("periodic_notifier"),
+	}
+	go n.run()
+	return n
+}
+
+// Notify enqueues a launch failure for delivery. If the buffer is full the
+// notification is dropped and logged rather than blocking the caller.
+func (n *LaunchFailureNotifier) Notify(job *structs.Job, launchTime time.Time, launchErr error) {
+	notification := &LaunchFailureNotification{
+		Source:     "periodic-dispatch",
+		JobID:      job.ID,
+		Namespace:  job.Namespace,
+		LaunchTime: launchTime,
+		Error:      launchErr.Error(),
+		Timestamp:  time.Now().UTC(),
+	}
+
+	select {
+	case n.notifyCh <- notification:
+	default:
+		n.logger.Warn("dropping launch failure notification; buffer is full",
+			"job", job.NamespacedID())
+	}
+}
+
+// Shutdown stops the notifier. Buffered notifications are not flushed.
+func (n *LaunchFailureNotifier) Shutdown() {
+	n.stopOnce.Do(func() {
+		close(n.stopCh)
+	})
+}
+
+// run delivers queued notifications until the notifier is shut down.
+func (n *LaunchFailureNotifier) run() {
+	for {
+		select {
+		case notification := <-n.notifyCh:
+			for _, target := range n.targets {
+				if err := n.deliver(target, notification); err != nil {
+					n.logger.Error("failed to deliver launch failure notification",
+						"target", target, "job_id", notification.JobID,
+						"namespace", notification.Namespace, "error", err)
+				}
+			}
+		case <-n.stopCh:
+			return
+		}
+	}
+}
+
+// deliver posts a notification to a single target, retrying with exponential
+// backoff on errors and non-2xx responses.
+func (n *LaunchFailureNotifier) deliver(target string, notification *LaunchFailureNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	backoff := notifierBaseBackoff
+	var lastErr error
+	for attempt := 0; attempt < notifierMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-n.stopCh:
+				return lastErr
+			}
+		}
+
+		resp, err := n.client.Post(target, "application/json", bytes.NewReader(body))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+
+	return lastErr
+}
@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchFailureNotifier_Delivers(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	received := make(chan *LaunchFailureNotification, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification LaunchFailureNotification
+		require.NoError(json.NewDecoder(r.Body).Decode(&notification))
+		received <- &notification
+	}))
+	defer ts.Close()
+
+	n := NewLaunchFailureNotifier(testlog.HCLogger(t), []string{ts.URL})
+	defer n.Shutdown()
+
+	job := mock.PeriodicJob()
+	launch := time.Now().UTC()
+	n.Notify(job, launch, errors.New("dispatch failed"))
+
+	select {
+	case notification := <-received:
+		require.Equal("periodic-dispatch", notification.Source)
+		require.Equal(job.ID, notification.JobID)
+		require.Equal(job.Namespace, notification.Namespace)
+		require.True(launch.Equal(notification.LaunchTime))
+		require.Equal("dispatch failed", notification.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+}
+
+func TestLaunchFailureNotifier_Retries(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var attempts int32
+	received := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to force a retry
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received <- struct{}{}
+	}))
+	defer ts.Close()
+
+	n := NewLaunchFailureNotifier(testlog.HCLogger(t), []string{ts.URL})
+	defer n.Shutdown()
+
+	n.Notify(mock.PeriodicJob(), time.Now(), errors.New("dispatch failed"))
+
+	select {
+	case <-received:
+		require.Equal(int32(2), atomic.LoadInt32(&attempts))
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for retried notification")
+	}
+}
+
+// failingJobEvalDispatcher errors on every dispatch.
+type failingJobEvalDispatcher struct{}
+
+func (failingJobEvalDispatcher) DispatchJob(*structs.Job) (*structs.Evaluation, error) {
+	return nil, errors.New("no cluster leader")
+}
+
+func (failingJobEvalDispatcher) RunningChildren(*structs.Job) (bool, error) {
+	return false, nil
+}
+
+func TestPeriodicDispatch_LaunchFailureNotification(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	received := make(chan *LaunchFailureNotification, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification LaunchFailureNotification
+		require.NoError(json.NewDecoder(r.Body).Decode(&notification))
+		received <- &notification
+	}))
+	defer ts.Close()
+
+	p := NewPeriodicDispatch(testlog.HCLogger(t), failingJobEvalDispatcher{})
+	n := NewLaunchFailureNotifier(testlog.HCLogger(t), []string{ts.URL})
+	defer n.Shutdown()
+	p.SetLaunchFailureNotifier(n)
+
+	job := mock.PeriodicJob()
+	_, err := p.createEval(job, time.Now())
+	require.Error(err)
+
+	select {
+	case notification := <-received:
+		require.Equal("periodic-dispatch", notification.Source)
+		require.Equal(job.ID, notification.JobID)
+		require.Equal("no cluster leader", notification.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+}
@@ -206,7 +206,7 @@ func TestPeriodicDispatch_Add_Remove_Namespaced(t *testing.T) {
 
 	assert.Len(p.Tracked(), 2)
 
-	assert.Nil(p.Remove(job2.Namespace, job2.ID))
+	assert.Nil(p.Remove(job2.Namespace, job2.ID, true))
 	assert.Len(p.Tracked(), 1)
 	assert.Equal(p.Tracked()[0], job)
 }
@@ -282,7 +282,7 @@ func TestPeriodicDispatch_Add_TriggersUpdate(t *testing.T) {
 func TestPeriodicDispatch_Remove_Untracked(t *testing.T) {
 	t.Parallel()
 	p, _ := testPeriodicDispatcher(t)
-	if err := p.Remove("ns", "foo"); err != nil {
+	if err := p.Remove("ns", "foo", true); err != nil {
 		t.Fatalf("Remove failed %v; expected a no-op", err)
 	}
 }
@@ -301,7 +301,7 @@ func TestPeriodicDispatch_Remove_Tracked(t *testing.T) {
 		t.Fatalf("Add didn't track the job: %v", tracked)
 	}
 
-	if err := p.Remove(job.Namespace, job.ID); err != nil {
+	if err := p.Remove(job.Namespace, job.ID, true); err != nil {
 		t.Fatalf("Remove failed %v", err)
 	}
 
@@ -311,6 +311,47 @@ func TestPeriodicDispatch_Remove_Tracked(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_Remove_Stop(t *testing.T) {
+	t.Parallel()
+	p, _ := testPeriodicDispatcher(t)
+
+	job := mock.PeriodicJob()
+	if err := p.Add(job); err != nil {
+		t.Fatalf("Add failed %v", err)
+	}
+
+	// A stop without purge keeps the job tracked but stopped.
+	if err := p.Remove(job.Namespace, job.ID, false); err != nil {
+		t.Fatalf("Remove failed %v", err)
+	}
+
+	tracked := p.Tracked()
+	if len(tracked) != 1 {
+		t.Fatalf("stop without purge untracked the job: %v", tracked)
+	}
+	if !tracked[0].Stopped() {
+		t.Fatalf("tracked job not marked stopped")
+	}
+
+	// Re-registering the job should resume launches.
+	if err := p.Add(job); err != nil {
+		t.Fatalf("Add failed %v", err)
+	}
+
+	tracked = p.Tracked()
+	if len(tracked) != 1 || tracked[0].Stopped() {
+		t.Fatalf("re-registered job still stopped: %v", tracked)
+	}
+
+	// A purge forgets the job entirely.
+	if err := p.Remove(job.Namespace, job.ID, true); err != nil {
+		t.Fatalf("Remove failed %v", err)
+	}
+	if tracked := p.Tracked(); len(tracked) != 0 {
+		t.Fatalf("purge didn't untrack the job: %v", tracked)
+	}
+}
+
 func TestPeriodicDispatch_Remove_TriggersUpdate(t *testing.T) {
 	t.Parallel()
 	p, _ := testPeriodicDispatcher(t)
@@ -324,7 +365,7 @@ func TestPeriodicDispatch_Remove_TriggersUpdate(t *testing.T) {
 	}
 
 	// Remove the job.
-	if err := p.Remove(job.Namespace, job.ID); err != nil {
+	if err := p.Remove(job.Namespace, job.ID, true); err != nil {
 		t.Fatalf("Remove failed %v", err)
 	}
 
@@ -441,6 +482,53 @@ func TestPeriodicDispatch_Run_Multiple(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_LaunchStats(t *testing.T) {
+	t.Parallel()
+	p, _ := testPeriodicDispatcher(t)
+
+	// Create a job that won't be evaluated for a while.
+	job := testPeriodicJob(time.Now().Add(10 * time.Second))
+
+	// Add it.
+	if err := p.Add(job); err != nil {
+		t.Fatalf("Add failed %v", err)
+	}
+
+	// No launches have happened yet.
+	if records := p.LaunchStats(job.Namespace, job.ID); len(records) != 0 {
+		t.Fatalf("expected no launch records; got %d", len(records))
+	}
+
+	// ForceRun the job and check a record was kept.
+	if _, err := p.ForceRun(job.Namespace, job.ID); err != nil {
+		t.Fatalf("ForceRun failed %v", err)
+	}
+
+	records := p.LaunchStats(job.Namespace, job.ID)
+	if len(records) != 1 {
+		t.Fatalf("expected one launch record; got %d", len(records))
+	}
+	if records[0].DispatchTime.Before(records[0].ScheduledTime) {
+		t.Fatalf("bad record: %+v", records[0])
+	}
+
+	// Check that the records are bounded.
+	for i := 0; i < 2*maxLaunchRecords; i++ {
+		p.recordLaunch(job, time.Now())
+	}
+	if records := p.LaunchStats(job.Namespace, job.ID); len(records) != maxLaunchRecords {
+		t.Fatalf("expected %d launch records; got %d", maxLaunchRecords, len(records))
+	}
+
+	// Check that purging the job drops its records.
+	if err := p.Remove(job.Namespace, job.ID, true); err != nil {
+		t.Fatalf("Remove failed %v", err)
+	}
+	if records := p.LaunchStats(job.Namespace, job.ID); len(records) != 0 {
+		t.Fatalf("expected no launch records after purge; got %d", len(records))
+	}
+}
+
 func TestPeriodicDispatch_Run_SameTime(t *testing.T) {
 	t.Parallel()
 	p, m := testPeriodicDispatcher(t)
@@ -581,7 +669,7 @@ func TestPeriodicDispatch_Complex(t *testing.T) {
 	}
 
 	for _, job := range toDelete {
-		if err := p.Remove(job.Namespace, job.ID); err != nil {
+		if err := p.Remove(job.Namespace, job.ID, true); err != nil {
 			t.Fatalf("Remove failed %v", err)
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	"math/rand"
 	"net"
 	"net/rpc"
+	"regexp"
 	"strings"
 	"time"
 
@@ -237,7 +238,7 @@ func (r *rpcHandler) handleConn(ctx context.Context, conn net.Conn, rpcCtx *RPCC
 		// Create an RPC Server and handle the request
 		server := rpc.NewServer()
 		r.setupRpcServer(server, rpcCtx)
-		r.handleNomadConn(ctx, conn, server)
+		r.handleNomadConn(ctx, conn, server, rpcCtx)
 
 		// Remove any potential mapping between a NodeID to this connection and
 		// close the underlying connection.
@@ -369,14 +370,90 @@ func (r *rpcHandler) handleMultiplex(ctx context.Context, conn net.Conn, rpcCtx
 			}
 			return
 		}
-		go r.handleNomadConn(ctx, sub, rpcServer)
+		go r.handleNomadConn(ctx, sub, rpcServer, rpcCtx)
 	}
 }
 
+// serverCertNameRegex matches the name issued to Nomad server certificates
+// (server.<region>.nomad), as opposed to client or CLI certificates.
+var serverCertNameRegex = regexp.MustCompile(`^server\.[^.]+\.nomad$`)
+
+// authTokenRequest is implemented by RPC arguments that carry an ACL auth
+// token, via their embedded QueryOptions or WriteRequest.
+type authTokenRequest interface {
+	GetAuthToken() string
+	SetAuthToken(string)
+}
+
+// tlsIdentityCodec wraps a ServerCodec to guarantee that ACL tokens bearing
+// the reserved TLS identity prefix are derived from verified connection state
+// rather than supplied in-band by the caller.
+type tlsIdentityCodec struct {
+	rpc.ServerCodec
+
+	// identity is the mapped ACL identity of the connection's verified
+	// client certificate, if any.
+	identity string
+
+	// trustedServer marks connections from peer servers, whose forwarded
+	// requests may carry a TLS identity resolved by the originating server.
+	trustedServer bool
+}
+
+func (c *tlsIdentityCodec) ReadRequestBody(args interface{}) error {
+	if err := c.ServerCodec.ReadRequestBody(args); err != nil {
+		return err
+	}
+	req, ok := args.(authTokenRequest)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(req.GetAuthToken(), structs.ACLTLSIdentityPrefix):
+		// A caller-supplied TLS identity token is forged unless the request
+		// was forwarded by a peer server that resolved the identity itself.
+		if !c.trustedServer {
+			req.SetAuthToken("")
+		}
+	case req.GetAuthToken() == "" && c.identity != "":
+		req.SetAuthToken(structs.ACLTLSIdentityPrefix + c.identity)
+	}
+	return nil
+}
+
+// wrapTLSIdentity wraps an RPC codec so that caller-supplied TLS identity
+// tokens are stripped from requests, and the identity of the connection's
+// verified client certificate is applied in their place when one is mapped to
+// ACL policies.
+func (r *rpcHandler) wrapTLSIdentity(codec rpc.ServerCodec, rpcCtx *RPCContext) rpc.ServerCodec {
+	c := &tlsIdentityCodec{ServerCodec: codec}
+	if rpcCtx != nil && len(rpcCtx.VerifiedChains) > 0 && len(rpcCtx.VerifiedChains[0]) > 0 {
+		leaf := rpcCtx.VerifiedChains[0][0]
+		c.trustedServer = isServerCertificate(leaf)
+		c.identity = tlsCertificateIdentity(leaf, r.config.TLSIdentityPolicies)
+	}
+	return c
+}
+
+// isServerCertificate returns whether the verified certificate was issued to
+// a Nomad server.
+func isServerCertificate(cert *x509.Certificate) bool {
+	if serverCertNameRegex.MatchString(cert.Subject.CommonName) {
+		return true
+	}
+	for _, name := range cert.DNSNames {
+		if serverCertNameRegex.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // handleNomadConn is used to service a single Nomad RPC connection
-func (r *rpcHandler) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server) {
+func (r *rpcHandler) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server, rpcCtx *RPCContext) {
 	defer conn.Close()
-	rpcCodec := pool.NewServerCodec(conn)
+	rpcCodec := r.wrapTLSIdentity(pool.NewServerCodec(conn), rpcCtx)
 	for {
 		select {
 		case <-ctx.Done():
@@ -492,7 +569,7 @@ func (r *rpcHandler) handleMultiplexV2(ctx context.Context, conn net.Conn, rpcCt
 		// Determine which handler to use
 		switch pool.RPCType(buf[0]) {
 		case pool.RpcNomad:
-			go r.handleNomadConn(ctx, sub, rpcServer)
+			go r.handleNomadConn(ctx, sub, rpcServer, rpcCtx)
 		case pool.RpcStreaming:
 			go r.handleStreamingConn(sub)
 
@@ -720,6 +797,11 @@ func (r *rpcHandler) streamingRpcImpl(conn net.Conn, method string) (net.Conn, e
 
 // raftApplyFuture is used to encode a message, run it through raft, and return the Raft future.
 func (s *Server) raftApplyFuture(t structs.MessageType, msg interface{}) (raft.ApplyFuture, error) {
+	// Simulate a slow raft log if the fault is injected
+	if delay := s.faults.applyDelay(); delay > 0 {
+		time.Sleep(delay)
+	}
+
 	buf, err := structs.Encode(t, msg)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to encode request: %v", err)
@@ -1008,3 +1008,43 @@ func TestRPC_Limits_Streaming(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// noopServerCodec implements just enough of rpc.ServerCodec to test codec
+// wrappers; ReadRequestBody leaves the passed arguments untouched.
+type noopServerCodec struct {
+	rpc.ServerCodec
+}
+
+func (noopServerCodec) ReadRequestBody(args interface{}) error { return nil }
+
+func TestRPC_TLSIdentityCodec(t *testing.T) {
+	t.Parallel()
+
+	forged := structs.ACLTLSIdentityPrefix + "cli.global.nomad"
+
+	// A caller-supplied token bearing the reserved prefix is stripped
+	c := &tlsIdentityCodec{ServerCodec: noopServerCodec{}}
+	args := &structs.GenericRequest{QueryOptions: structs.QueryOptions{AuthToken: forged}}
+	require.NoError(t, c.ReadRequestBody(args))
+	require.Equal(t, "", args.AuthToken)
+
+	// ...unless the connection is from a peer server forwarding a request
+	// whose identity it resolved itself
+	c = &tlsIdentityCodec{ServerCodec: noopServerCodec{}, trustedServer: true}
+	args = &structs.GenericRequest{QueryOptions: structs.QueryOptions{AuthToken: forged}}
+	require.NoError(t, c.ReadRequestBody(args))
+	require.Equal(t, forged, args.AuthToken)
+
+	// The connection's verified certificate identity is applied to requests
+	// without a token
+	c = &tlsIdentityCodec{ServerCodec: noopServerCodec{}, identity: "cli.global.nomad"}
+	args = &structs.GenericRequest{}
+	require.NoError(t, c.ReadRequestBody(args))
+	require.Equal(t, forged, args.AuthToken)
+
+	// Ordinary tokens pass through unchanged
+	c = &tlsIdentityCodec{ServerCodec: noopServerCodec{}, identity: "cli.global.nomad"}
+	args = &structs.GenericRequest{QueryOptions: structs.QueryOptions{AuthToken: "secret"}}
+	require.NoError(t, c.ReadRequestBody(args))
+	require.Equal(t, "secret", args.AuthToken)
+}
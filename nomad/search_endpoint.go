@@ -87,6 +87,58 @@ func (s *Search) getMatches(iter memdb.ResultIterator, prefix string) ([]string,
 	return matches, iter.Next() != nil
 }
 
+// getTypedMatches extracts matches from an iterator like getMatches, but
+// returns them as typed matches carrying the context and namespace of each
+// matched object.
+func (s *Search) getTypedMatches(iter memdb.ResultIterator, prefix string, context structs.Context) ([]*structs.IdentifierMatch, bool) {
+	var matches []*structs.IdentifierMatch
+
+	for i := 0; i < truncateLimit; i++ {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		var id, namespace string
+		switch t := raw.(type) {
+		case *structs.Job:
+			id, namespace = t.ID, t.Namespace
+		case *structs.Evaluation:
+			id, namespace = t.ID, t.Namespace
+		case *structs.Allocation:
+			id, namespace = t.ID, t.Namespace
+		case *structs.Node:
+			id = t.ID
+		case *structs.Deployment:
+			id, namespace = t.ID, t.Namespace
+		case *structs.CSIPlugin:
+			id = t.ID
+		case *structs.CSIVolume:
+			id, namespace = t.ID, t.Namespace
+		default:
+			matchID, ok := getEnterpriseMatch(raw)
+			if !ok {
+				s.logger.Error("unexpected type for resources context", "type", fmt.Sprintf("%T", t))
+				continue
+			}
+
+			id = matchID
+		}
+
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+
+		matches = append(matches, &structs.IdentifierMatch{
+			Context:   context,
+			ID:        id,
+			Namespace: namespace,
+		})
+	}
+
+	return matches, iter.Next() != nil
+}
+
 // getResourceIter takes a context and returns a memdb iterator specific to
 // that context
 func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix string, ws memdb.WatchSet, state *state.StateStore) (memdb.ResultIterator, error) {
@@ -204,3 +256,75 @@ func (s *Search) PrefixSearch(args *structs.SearchRequest, reply *structs.Search
 		}}
 	return s.srv.blockingRPC(&opts)
 }
+
+// ResolveIdentifier resolves an ID prefix across all contexts in a single
+// query, returning typed matches so callers can disambiguate what kind of
+// object a prefix refers to without issuing a query per type.
+func (s *Search) ResolveIdentifier(args *structs.ResolveIdentifierRequest, reply *structs.ResolveIdentifierResponse) error {
+	if done, err := s.srv.forward("Search.ResolveIdentifier", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "search", "resolve_identifier"}, time.Now())
+
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Require either node:read or namespace:read-job
+	if !anySearchPerms(aclObj, namespace, structs.All) {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Truncations = make(map[structs.Context]bool)
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryMeta: &reply.QueryMeta,
+		queryOpts: &structs.QueryOptions{},
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+
+			reply.Matches = nil
+			contexts := searchContexts(aclObj, namespace, structs.All)
+
+			for _, ctx := range contexts {
+				iter, err := getResourceIter(ctx, aclObj, namespace, roundUUIDDownIfOdd(args.Prefix, ctx), ws, state)
+				if err != nil {
+					e := err.Error()
+					switch {
+					// Searching other contexts with job names raises an error, which in
+					// this case we want to ignore.
+					case strings.Contains(e, "Invalid UUID: encoding/hex"):
+					case strings.Contains(e, "UUID have 36 characters"):
+					case strings.Contains(e, "must be even length"):
+					case strings.Contains(e, "UUID should have maximum of 4"):
+					default:
+						return err
+					}
+					continue
+				}
+
+				res, isTrunc := s.getTypedMatches(iter, args.Prefix, ctx)
+				reply.Matches = append(reply.Matches, res...)
+				reply.Truncations[ctx] = isTrunc
+			}
+
+			// Use the maximum index across all resources as the index of the
+			// response.
+			for _, ctx := range contexts {
+				index, err := state.Index(contextToIndex(ctx))
+				if err != nil {
+					return err
+				}
+				if index > reply.Index {
+					reply.Index = index
+				}
+			}
+
+			s.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
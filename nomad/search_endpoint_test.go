@@ -823,3 +823,105 @@ func TestSearch_PrefixSearch_CSIVolume(t *testing.T) {
 	assert.Equal(id, resp.Matches[structs.Volumes][0])
 	assert.Equal(resp.Truncations[structs.Volumes], false)
 }
+
+func TestSearch_ResolveIdentifier(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	job := registerAndVerifyJob(s, t, "example", 0)
+
+	eval := mock.Eval()
+	eval.ID = uuid.Generate()
+	require.NoError(s.fsm.State().UpsertEvals(2000, []*structs.Evaluation{eval}))
+
+	req := &structs.ResolveIdentifierRequest{
+		Prefix: eval.ID[:8],
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	var resp structs.ResolveIdentifierResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp))
+
+	require.Len(resp.Matches, 1)
+	require.Equal(structs.Evals, resp.Matches[0].Context)
+	require.Equal(eval.ID, resp.Matches[0].ID)
+	require.Equal(eval.Namespace, resp.Matches[0].Namespace)
+	require.Equal(uint64(2000), resp.Index)
+
+	// A job name resolves with its context and namespace
+	req.Prefix = job.ID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp))
+
+	require.Len(resp.Matches, 1)
+	require.Equal(structs.Jobs, resp.Matches[0].Context)
+	require.Equal(job.ID, resp.Matches[0].ID)
+	require.Equal(job.Namespace, resp.Matches[0].Namespace)
+}
+
+func TestSearch_ResolveIdentifier_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, root, cleanupS := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job := registerAndVerifyJob(s, t, "example", 0)
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1001, node))
+
+	req := &structs.ResolveIdentifierRequest{
+		Prefix: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Try without a token and expect failure
+	{
+		var resp structs.ResolveIdentifierResponse
+		err := msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp)
+		require.EqualError(err, structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a read-job token; jobs resolve but node prefixes do not
+	{
+		validToken := mock.CreatePolicyAndToken(t, state, 1002, "test-valid",
+			mock.NamespacePolicy(structs.DefaultNamespace, "read", nil))
+		req.AuthToken = validToken.SecretID
+		var resp structs.ResolveIdentifierResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp))
+		require.Len(resp.Matches, 1)
+		require.Equal(structs.Jobs, resp.Matches[0].Context)
+
+		req.Prefix = node.ID[:8]
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp))
+		require.Empty(resp.Matches)
+	}
+
+	// Try with a management token; nodes resolve as well
+	{
+		req.AuthToken = root.SecretID
+		req.Prefix = node.ID[:8]
+		var resp structs.ResolveIdentifierResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Search.ResolveIdentifier", req, &resp))
+		require.Len(resp.Matches, 1)
+		require.Equal(structs.Nodes, resp.Matches[0].Context)
+		require.Equal(node.ID, resp.Matches[0].ID)
+	}
+}
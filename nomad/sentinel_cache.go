@@ -0,0 +1,90 @@
+package nomad
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	metrics "github.com/armon/go-metrics"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// sentinelCacheSize is the number of Sentinel evaluation results that
+	// are cached. Entries are small so this is sized generously to cover
+	// repeated submissions of the same jobs against a stable policy set.
+	sentinelCacheSize = 512
+)
+
+// sentinelCacheEntry is the cached outcome of evaluating a single Sentinel
+// policy against a job.
+type sentinelCacheEntry struct {
+	// Warning holds the advisory or overridden soft-mandatory failure, if
+	// any, that was produced by the evaluation.
+	Warning error
+
+	// Error holds the mandatory failure, if any, that was produced by the
+	// evaluation.
+	Error error
+}
+
+// sentinelResultCache caches Sentinel evaluation results so that repeated
+// registrations of an unchanged job do not recompile and re-evaluate the
+// policy set. Entries are keyed on the policy hash and the job hash, so a
+// change to either naturally misses the cache; the cache is purged when the
+// policy or module set changes to drop entries for removed policies.
+type sentinelResultCache struct {
+	cache *lru.TwoQueueCache
+}
+
+// newSentinelResultCache is used to create a new Sentinel result cache
+func newSentinelResultCache() (*sentinelResultCache, error) {
+	cache, err := lru.New2Q(sentinelCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Sentinel result cache: %v", err)
+	}
+	return &sentinelResultCache{cache: cache}, nil
+}
+
+// Key returns the cache key for evaluating the policy with the given hash
+// against the given job.
+func (c *sentinelResultCache) Key(policyHash []byte, jobHash []byte) string {
+	return hex.EncodeToString(policyHash) + ":" + hex.EncodeToString(jobHash)
+}
+
+// Get looks up a cached evaluation result, tracking the hit rate
+func (c *sentinelResultCache) Get(key string) (*sentinelCacheEntry, bool) {
+	raw, ok := c.cache.Get(key)
+	if !ok {
+		metrics.IncrCounter([]string{"nomad", "sentinel", "cache", "miss"}, 1)
+		return nil, false
+	}
+	metrics.IncrCounter([]string{"nomad", "sentinel", "cache", "hit"}, 1)
+	return raw.(*sentinelCacheEntry), true
+}
+
+// Add stores an evaluation result
+func (c *sentinelResultCache) Add(key string, entry *sentinelCacheEntry) {
+	c.cache.Add(key, entry)
+}
+
+// Purge is used to drop all cached results. It is called when the policy or
+// module set changes.
+func (c *sentinelResultCache) Purge() {
+	c.cache.Purge()
+}
+
+// jobSentinelHash returns a hash of the job suitable for keying Sentinel
+// evaluation results. The msgpack encoding is used so any field change
+// produces a new hash.
+func jobSentinelHash(job *structs.Job) ([]byte, error) {
+	var buf []byte
+	err := codec.NewEncoderBytes(&buf, structs.MsgpackHandle).Encode(job)
+	if err != nil {
+		return nil, err
+	}
+	hash := blake2b.Sum256(buf)
+	return hash[:], nil
+}
@@ -0,0 +1,51 @@
+package nomad
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentinelResultCache(t *testing.T) {
+	t.Parallel()
+	c, err := newSentinelResultCache()
+	require.NoError(t, err)
+
+	policy := mock.SentinelPolicy()
+	policy.SetHash()
+	job := mock.Job()
+	jobHash, err := jobSentinelHash(job)
+	require.NoError(t, err)
+
+	// Lookup of an unknown key should miss
+	key := c.Key(policy.Hash, jobHash)
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+
+	// Add a result and look it up
+	entry := &sentinelCacheEntry{
+		Warning: errors.New("soft-mandatory failure"),
+	}
+	c.Add(key, entry)
+	out, ok := c.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, entry, out)
+
+	// A changed job should produce a different key
+	job2 := job.Copy()
+	job2.Priority++
+	jobHash2, err := jobSentinelHash(job2)
+	require.NoError(t, err)
+	key2 := c.Key(policy.Hash, jobHash2)
+	assert.NotEqual(t, key, key2)
+	_, ok = c.Get(key2)
+	assert.False(t, ok)
+
+	// Purge should drop the cached result
+	c.Purge()
+	_, ok = c.Get(key)
+	assert.False(t, ok)
+}
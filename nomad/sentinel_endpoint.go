@@ -0,0 +1,528 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Sentinel endpoint is used for manipulating Sentinel policies
+type Sentinel struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// UpsertPolicies is used to create or update a set of policies
+func (s *Sentinel) UpsertPolicies(args *structs.SentinelPolicyUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure Sentinel is licensed, and always flow modification requests to
+	// the authoritative region
+	if err := s.srv.licenseWatcher.FeatureCheck("sentinel", true); err != nil {
+		return err
+	}
+	args.Region = s.srv.config.AuthoritativeRegion
+
+	if done, err := s.srv.forward("Sentinel.UpsertPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "upsert_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of policies
+	if len(args.Policies) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	}
+
+	// Validate each policy, compute hash
+	for idx, policy := range args.Policies {
+		if err := policy.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(400, "policy %d invalid: %v", idx, err)
+		}
+		policy.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.SentinelPolicyUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate any cached evaluation results for the old policy set
+	s.srv.sentinelCache.Purge()
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeletePolicies is used to delete policies
+func (s *Sentinel) DeletePolicies(args *structs.SentinelPolicyDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure Sentinel is licensed, and always flow modification requests to
+	// the authoritative region
+	if err := s.srv.licenseWatcher.FeatureCheck("sentinel", true); err != nil {
+		return err
+	}
+	args.Region = s.srv.config.AuthoritativeRegion
+
+	if done, err := s.srv.forward("Sentinel.DeletePolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "delete_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of policies
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one policy")
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.SentinelPolicyDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate any cached evaluation results for the old policy set
+	s.srv.sentinelCache.Purge()
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListPolicies is used to list the policies
+func (s *Sentinel) ListPolicies(args *structs.SentinelPolicyListRequest, reply *structs.SentinelPolicyListResponse) error {
+	if done, err := s.srv.forward("Sentinel.ListPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "list_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the policies
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.SentinelPolicyByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.SentinelPolicies(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the policies to a list stub
+			reply.Policies = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				policy := raw.(*structs.SentinelPolicy)
+				reply.Policies = append(reply.Policies, policy.Stub())
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("sentinel_policy")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetPolicy is used to get a specific policy
+func (s *Sentinel) GetPolicy(args *structs.SentinelPolicySpecificRequest, reply *structs.SingleSentinelPolicyResponse) error {
+	if done, err := s.srv.forward("Sentinel.GetPolicy", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "get_policy"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the policy
+			out, err := state.SentinelPolicyByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Policy = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the policy table
+				index, err := state.Index("sentinel_policy")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// ListOverrides is used to list the recorded soft-mandatory policy overrides
+func (s *Sentinel) ListOverrides(args *structs.SentinelOverrideListRequest, reply *structs.SentinelOverrideListResponse) error {
+	if done, err := s.srv.forward("Sentinel.ListOverrides", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "list_overrides"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the overrides
+			iter, err := state.SentinelOverrides(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Overrides = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				reply.Overrides = append(reply.Overrides, raw.(*structs.SentinelOverride))
+			}
+
+			// Use the last index that affected the overrides table
+			index, err := state.Index("sentinel_overrides")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// TestPolicy is used to dry-run a policy against a job without storing the
+// policy or submitting the job. The policy is compiled and evaluated by the
+// Sentinel runtime and the trace output is returned for policy authors.
+func (s *Sentinel) TestPolicy(args *structs.SentinelPolicyTestRequest, reply *structs.SentinelPolicyTestResponse) error {
+	if done, err := s.srv.forward("Sentinel.TestPolicy", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "test_policy"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the request
+	if args.Policy == nil {
+		return structs.NewErrRPCCoded(400, "must specify a policy")
+	}
+	if args.Job == nil {
+		return structs.NewErrRPCCoded(400, "must specify a job")
+	}
+	if err := args.Policy.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(400, "policy invalid: %v", err)
+	}
+
+	// Hand off to the policy runtime
+	passed, trace, err := s.testPolicy(args.Policy, args.Job)
+	if err != nil {
+		return err
+	}
+	reply.Passed = passed
+	reply.Trace = trace
+	return nil
+}
+
+// UpsertModules is used to create or update a set of modules
+func (s *Sentinel) UpsertModules(args *structs.SentinelModuleUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure Sentinel is licensed, and always flow modification requests to
+	// the authoritative region
+	if err := s.srv.licenseWatcher.FeatureCheck("sentinel", true); err != nil {
+		return err
+	}
+	args.Region = s.srv.config.AuthoritativeRegion
+
+	if done, err := s.srv.forward("Sentinel.UpsertModules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "upsert_modules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of modules
+	if len(args.Modules) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one module")
+	}
+
+	// Validate each module, compute hash
+	for idx, module := range args.Modules {
+		if err := module.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(400, "module %d invalid: %v", idx, err)
+		}
+		module.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.SentinelModuleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cached evaluation results as policies may import the
+	// changed modules
+	s.srv.sentinelCache.Purge()
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteModules is used to delete modules
+func (s *Sentinel) DeleteModules(args *structs.SentinelModuleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure Sentinel is licensed, and always flow modification requests to
+	// the authoritative region
+	if err := s.srv.licenseWatcher.FeatureCheck("sentinel", true); err != nil {
+		return err
+	}
+	args.Region = s.srv.config.AuthoritativeRegion
+
+	if done, err := s.srv.forward("Sentinel.DeleteModules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "delete_modules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of modules
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(400, "must specify as least one module")
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.SentinelModuleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cached evaluation results as policies may import the
+	// changed modules
+	s.srv.sentinelCache.Purge()
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListModules is used to list the modules
+func (s *Sentinel) ListModules(args *structs.SentinelModuleListRequest, reply *structs.SentinelModuleListResponse) error {
+	if done, err := s.srv.forward("Sentinel.ListModules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "list_modules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the modules
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.SentinelModuleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.SentinelModules(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the modules to a list stub
+			reply.Modules = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				module := raw.(*structs.SentinelModule)
+				reply.Modules = append(reply.Modules, module.Stub())
+			}
+
+			// Use the last index that affected the module table
+			index, err := state.Index("sentinel_module")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetModule is used to get a specific module
+func (s *Sentinel) GetModule(args *structs.SentinelModuleSpecificRequest, reply *structs.SingleSentinelModuleResponse) error {
+	if done, err := s.srv.forward("Sentinel.GetModule", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "get_module"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the module
+			out, err := state.SentinelModuleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Module = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the module table
+				index, err := state.Index("sentinel_module")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetPolicies is used to get a set of policies
+func (s *Sentinel) GetPolicies(args *structs.SentinelPolicySetRequest, reply *structs.SentinelPolicySetResponse) error {
+	if done, err := s.srv.forward("Sentinel.GetPolicies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "sentinel", "get_policies"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl != nil && !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Policies = make(map[string]*structs.SentinelPolicy, len(args.Names))
+
+			// Look for the policy
+			for _, policyName := range args.Names {
+				out, err := state.SentinelPolicyByName(ws, policyName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Policies[policyName] = out
+				}
+			}
+
+			// Use the last index that affected the policy table
+			index, err := state.Index("sentinel_policy")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
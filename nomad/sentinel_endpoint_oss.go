@@ -0,0 +1,14 @@
+// +build !ent
+
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// testPolicy compiles and evaluates a policy against a job. The Sentinel
+// policy compiler is not bundled with the open source build, so policies can
+// only be stored and managed here, not executed.
+func (s *Sentinel) testPolicy(policy *structs.SentinelPolicy, job *structs.Job) (bool, string, error) {
+	return false, "", structs.NewErrRPCCoded(501, "Sentinel policy execution requires the Sentinel runtime which is not bundled in this build")
+}
@@ -0,0 +1,441 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinelEndpoint_GetPolicy(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	policy := mock.SentinelPolicy()
+	s1.fsm.State().UpsertSentinelPolicies(1000, []*structs.SentinelPolicy{policy})
+
+	// Lookup the policy
+	get := &structs.SentinelPolicySpecificRequest{
+		Name: policy.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleSentinelPolicyResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetPolicy", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, policy, resp.Policy)
+
+	// Lookup non-existing policy
+	get.Name = uuid.Generate()
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetPolicy", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Nil(t, resp.Policy)
+}
+
+func TestSentinelEndpoint_GetPolicy_NonManagement(t *testing.T) {
+	t.Parallel()
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a non-management token
+	token := mock.ACLToken()
+	s1.fsm.State().UpsertACLTokens(1000, []*structs.ACLToken{token})
+
+	// Lookup a policy
+	get := &structs.SentinelPolicySpecificRequest{
+		Name: "missing",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SingleSentinelPolicyResponse
+	err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetPolicy", get, &resp)
+	assert.Equal(t, structs.ErrPermissionDenied.Error(), err.Error())
+}
+
+func TestSentinelEndpoint_GetPolicies(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	policy := mock.SentinelPolicy()
+	policy2 := mock.SentinelPolicy()
+	s1.fsm.State().UpsertSentinelPolicies(1000, []*structs.SentinelPolicy{policy, policy2})
+
+	// Lookup the policies
+	get := &structs.SentinelPolicySetRequest{
+		Names: []string{policy.Name, policy2.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SentinelPolicySetResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetPolicies", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, 2, len(resp.Policies))
+	assert.Equal(t, policy, resp.Policies[policy.Name])
+	assert.Equal(t, policy2, resp.Policies[policy2.Name])
+}
+
+func TestSentinelEndpoint_ListPolicies(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	p1 := mock.SentinelPolicy()
+	p2 := mock.SentinelPolicy()
+
+	p1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	p2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	s1.fsm.State().UpsertSentinelPolicies(1000, []*structs.SentinelPolicy{p1, p2})
+
+	// Lookup the policies
+	get := &structs.SentinelPolicyListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SentinelPolicyListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.ListPolicies", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, 2, len(resp.Policies))
+
+	// Lookup the policies by prefix
+	get = &structs.SentinelPolicyListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Prefix:    "aaaabb",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp2 structs.SentinelPolicyListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.ListPolicies", get, &resp2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp2.Index)
+	assert.Equal(t, 1, len(resp2.Policies))
+}
+
+func TestSentinelEndpoint_DeletePolicies(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	p1 := mock.SentinelPolicy()
+	s1.fsm.State().UpsertSentinelPolicies(1000, []*structs.SentinelPolicy{p1})
+
+	// Lookup the policies
+	req := &structs.SentinelPolicyDeleteRequest{
+		Names: []string{p1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.DeletePolicies", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we deleted the policy
+	out, err := s1.fsm.State().SentinelPolicyByName(nil, p1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestSentinelEndpoint_UpsertPolicies(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	p1 := mock.SentinelPolicy()
+
+	// Upsert the policy
+	req := &structs.SentinelPolicyUpsertRequest{
+		Policies: []*structs.SentinelPolicy{p1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.UpsertPolicies", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the policy
+	out, err := s1.fsm.State().SentinelPolicyByName(nil, p1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestSentinelEndpoint_ListOverrides(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Record an override
+	override := &structs.SentinelOverride{
+		Policies:  []string{"foo"},
+		JobID:     "example",
+		Namespace: "default",
+		Time:      time.Now().UnixNano(),
+	}
+	s1.fsm.State().InsertSentinelOverride(1000, override)
+
+	// List the overrides
+	get := &structs.SentinelOverrideListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SentinelOverrideListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.ListOverrides", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, 1, len(resp.Overrides))
+	assert.Equal(t, []string{"foo"}, resp.Overrides[0].Policies)
+}
+
+func TestSentinelEndpoint_TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Dry-run a policy against a job
+	req := &structs.SentinelPolicyTestRequest{
+		Policy: mock.SentinelPolicy(),
+		Job:    mock.Job(),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SentinelPolicyTestResponse
+	err := msgpackrpc.CallWithCodec(codec, "Sentinel.TestPolicy", req, &resp)
+
+	// The open source build does not bundle the policy runtime
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Sentinel runtime")
+
+	// An invalid policy is rejected before execution
+	req.Policy.Scope = "random"
+	err = msgpackrpc.CallWithCodec(codec, "Sentinel.TestPolicy", req, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid scope")
+}
+
+func TestSentinelEndpoint_UpsertPolicies_Invalid(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	p1 := mock.SentinelPolicy()
+	p1.Scope = "random"
+
+	// Upsert the invalid policy
+	req := &structs.SentinelPolicyUpsertRequest{
+		Policies: []*structs.SentinelPolicy{p1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Sentinel.UpsertPolicies", req, &resp)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid scope")
+}
+
+func TestSentinelEndpoint_UpsertModules(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	m1 := mock.SentinelModule()
+
+	// Upsert the module
+	req := &structs.SentinelModuleUpsertRequest{
+		Modules: []*structs.SentinelModule{m1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.UpsertModules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we created the module
+	out, err := s1.fsm.State().SentinelModuleByName(nil, m1.Name)
+	assert.Nil(t, err)
+	assert.NotNil(t, out)
+}
+
+func TestSentinelEndpoint_GetModule(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	module := mock.SentinelModule()
+	s1.fsm.State().UpsertSentinelModules(1000, []*structs.SentinelModule{module})
+
+	// Lookup the module
+	get := &structs.SentinelModuleSpecificRequest{
+		Name: module.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleSentinelModuleResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetModule", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Equal(t, module, resp.Module)
+
+	// Lookup non-existing module
+	get.Name = uuid.Generate()
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.GetModule", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Nil(t, resp.Module)
+}
+
+func TestSentinelEndpoint_ListModules(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	m1 := mock.SentinelModule()
+	m1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	m2 := mock.SentinelModule()
+	m2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	s1.fsm.State().UpsertSentinelModules(1000, []*structs.SentinelModule{m1, m2})
+
+	// Lookup the modules
+	get := &structs.SentinelModuleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SentinelModuleListResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.ListModules", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.Modules, 2)
+
+	// Lookup the modules by prefix
+	get.QueryOptions.Prefix = "aaaabb"
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.ListModules", get, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, uint64(1000), resp.Index)
+	assert.Len(t, resp.Modules, 1)
+}
+
+func TestSentinelEndpoint_DeleteModules(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	m1 := mock.SentinelModule()
+	s1.fsm.State().UpsertSentinelModules(1000, []*structs.SentinelModule{m1})
+
+	// Delete the module
+	req := &structs.SentinelModuleDeleteRequest{
+		Names: []string{m1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Sentinel.DeleteModules", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.NotEqual(t, uint64(0), resp.Index)
+
+	// Check we deleted the module
+	out, err := s1.fsm.State().SentinelModuleByName(nil, m1.Name)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/nomad/volumewatcher"
@@ -202,9 +203,16 @@ type Server struct {
 	// that are waiting to be brokered to a sub-scheduler
 	evalBroker *EvalBroker
 
+	// eventBroker publishes state changes to event stream subscribers
+	eventBroker *stream.EventBroker
+
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
+	// launchFailureNotifier posts periodic launch failures to configured
+	// webhook targets. It is nil when no targets are configured.
+	launchFailureNotifier *LaunchFailureNotifier
+
 	// planner is used to mange the submitted allocation plans that are waiting
 	// to be accessed by the leader
 	*planner
@@ -231,6 +239,13 @@ type Server struct {
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// sentinelCache is used to maintain Sentinel evaluation results
+	sentinelCache *sentinelResultCache
+
+	// keystore holds the variables data encryption keys, which are kept on
+	// the server's local disk and never enter raft
+	keystore *keystore
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -244,6 +259,17 @@ type Server struct {
 	// Nomad router.
 	statsFetcher *StatsFetcher
 
+	// licenseWatcher provides access to the cluster license and enforces
+	// feature gating against it
+	licenseWatcher *LicenseWatcher
+
+	// metricsHistory retains a short in-memory history of cluster gauges
+	metricsHistory *metricsHistory
+
+	// faults holds the fault injection switches, settable only in builds
+	// with the faultinjection tag
+	faults faultInjection
+
 	// EnterpriseState is used to fill in state for Pro/Ent builds
 	EnterpriseState
 
@@ -258,23 +284,29 @@ type Server struct {
 
 // Holds the RPC endpoints
 type endpoints struct {
-	Status     *Status
-	Node       *Node
-	Job        *Job
-	Eval       *Eval
-	Plan       *Plan
-	Alloc      *Alloc
-	CSIVolume  *CSIVolume
-	CSIPlugin  *CSIPlugin
-	Deployment *Deployment
-	Region     *Region
-	Search     *Search
-	Periodic   *Periodic
-	System     *System
-	Operator   *Operator
-	ACL        *ACL
-	Scaling    *Scaling
-	Enterprise *EnterpriseEndpoints
+	Status              *Status
+	Node                *Node
+	Job                 *Job
+	Eval                *Eval
+	Plan                *Plan
+	Alloc               *Alloc
+	CSIVolume           *CSIVolume
+	CSIPlugin           *CSIPlugin
+	Deployment          *Deployment
+	Region              *Region
+	Search              *Search
+	License             *License
+	Namespace           *Namespace
+	Periodic            *Periodic
+	System              *System
+	Operator            *Operator
+	ACL                 *ACL
+	Scaling             *Scaling
+	Sentinel            *Sentinel
+	ServiceRegistration *ServiceRegistration
+	Variables           *Variables
+	Event               *Event
+	Enterprise          *EnterpriseEndpoints
 
 	// Client endpoints
 	ClientStats       *ClientStats
@@ -318,9 +350,25 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		return nil, err
 	}
 
+	// Create the Sentinel result cache
+	sentinelCache, err := newSentinelResultCache()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the logger
 	logger := config.Logger.ResetNamedIntercept("nomad")
 
+	// Create the event broker
+	eventBroker, err := stream.NewEventBroker(stream.EventBrokerCfg{
+		BufferSize:  config.EventBufferSize,
+		DurablePath: config.EventBufferPath,
+		Logger:      logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event broker: %v", err)
+	}
+
 	// Create the server
 	s := &Server{
 		config:           config,
@@ -337,14 +385,19 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		reconcileCh:      make(chan serf.Member, 32),
 		eventCh:          make(chan serf.Event, 256),
 		evalBroker:       evalBroker,
+		eventBroker:      eventBroker,
 		blockedEvals:     NewBlockedEvals(evalBroker, logger),
 		rpcTLS:           incomingTLS,
 		aclCache:         aclCache,
+		sentinelCache:    sentinelCache,
 	}
 
 	s.shutdownCtx, s.shutdownCancel = context.WithCancel(context.Background())
 	s.shutdownCh = s.shutdownCtx.Done()
 
+	// Configure escalation of long-blocked evaluations
+	s.blockedEvals.SetEscalationThreshold(config.BlockedEvalEscalationThreshold)
+
 	// Create the RPC handler
 	s.rpcHandler = newRpcHandler(s)
 
@@ -360,6 +413,10 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
+	if len(config.PeriodicLaunchFailureWebhooks) != 0 {
+		s.launchFailureNotifier = NewLaunchFailureNotifier(s.logger, config.PeriodicLaunchFailureWebhooks)
+		s.periodicDispatcher.SetLaunchFailureNotifier(s.launchFailureNotifier)
+	}
 
 	// Initialize the stats fetcher that autopilot will use.
 	s.statsFetcher = NewStatsFetcher(s.logger, s.connPool, s.config.Region)
@@ -374,6 +431,19 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		return nil, fmt.Errorf("Failed to setup Vault client: %v", err)
 	}
 
+	// Load the variables encryption keystore from the data dir. The keys
+	// are kept out of raft; dev mode servers hold them in memory only.
+	keystoreDir := ""
+	if config.DataDir != "" {
+		keystoreDir = filepath.Join(config.DataDir, "keystore")
+	}
+	s.keystore = newKeystore(keystoreDir)
+	if err := s.keystore.load(); err != nil {
+		s.Shutdown()
+		s.logger.Error("failed to load keystore", "error", err)
+		return nil, fmt.Errorf("Failed to load keystore: %v", err)
+	}
+
 	// Initialize the RPC layer
 	if err := s.setupRPC(tlsWrap); err != nil {
 		s.Shutdown()
@@ -388,6 +458,16 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		return nil, fmt.Errorf("Failed to start Raft: %v", err)
 	}
 
+	// Create the license watcher now that the fsm state is available. Dev
+	// mode servers fall back to an in-memory license with every feature.
+	s.licenseWatcher = NewLicenseWatcher(s.logger, s.State, config.LicenseFeatureGracePeriod)
+	if config.DevMode {
+		s.licenseWatcher.devLicense = structs.DevLicense()
+	}
+
+	// Create the cluster metrics history ring
+	s.metricsHistory = newMetricsHistory(int(metricsHistoryRetention / metricsHistoryInterval))
+
 	// Initialize the wan Serf
 	s.serf, err = s.setupSerf(config.SerfConfig, s.eventCh, serfSnapshot)
 	if err != nil {
@@ -432,6 +512,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Monitor leadership changes
 	go s.monitorLeadership()
 
+	// Replicate variables encryption keys from peer servers
+	go s.replicateEncryptionKeys()
+
 	// Start ingesting events for Serf
 	go s.serfEventHandler()
 
@@ -450,6 +533,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Emit metrics for the Vault client.
 	go s.vault.EmitStats(time.Second, s.shutdownCh)
 
+	// Sample cluster gauges into the metrics history
+	go s.collectMetricsHistory()
+
 	// Emit metrics
 	go s.heartbeatStats()
 
@@ -657,6 +743,16 @@ func (s *Server) Shutdown() error {
 		s.fsm.Close()
 	}
 
+	// Close the event broker's durable buffer, if any
+	if s.eventBroker != nil {
+		s.eventBroker.Close()
+	}
+
+	// Stop delivering launch failure notifications
+	if s.launchFailureNotifier != nil {
+		s.launchFailureNotifier.Shutdown()
+	}
+
 	// Stop Vault token renewal and revocations
 	if s.vault != nil {
 		s.vault.Stop()
@@ -1141,10 +1237,15 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.Operator = &Operator{srv: s, logger: s.logger.Named("operator")}
 		s.staticEndpoints.Operator.register()
 
+		s.staticEndpoints.License = &License{srv: s, logger: s.logger.Named("license")}
+		s.staticEndpoints.Namespace = &Namespace{srv: s, logger: s.logger.Named("namespace")}
 		s.staticEndpoints.Periodic = &Periodic{srv: s, logger: s.logger.Named("periodic")}
 		s.staticEndpoints.Plan = &Plan{srv: s, logger: s.logger.Named("plan")}
 		s.staticEndpoints.Region = &Region{srv: s, logger: s.logger.Named("region")}
 		s.staticEndpoints.Scaling = &Scaling{srv: s, logger: s.logger.Named("scaling")}
+		s.staticEndpoints.Sentinel = &Sentinel{srv: s, logger: s.logger.Named("sentinel")}
+		s.staticEndpoints.ServiceRegistration = &ServiceRegistration{srv: s, logger: s.logger.Named("service_registration")}
+		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables")}
 		s.staticEndpoints.Status = &Status{srv: s, logger: s.logger.Named("status")}
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
@@ -1162,6 +1263,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 
 		s.staticEndpoints.Agent = &Agent{srv: s}
 		s.staticEndpoints.Agent.register()
+
+		s.staticEndpoints.Event = &Event{srv: s}
+		s.staticEndpoints.Event.register()
 	}
 
 	// Register the static handlers
@@ -1173,10 +1277,15 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.CSIPlugin)
 	server.Register(s.staticEndpoints.Deployment)
 	server.Register(s.staticEndpoints.Operator)
+	server.Register(s.staticEndpoints.License)
+	server.Register(s.staticEndpoints.Namespace)
 	server.Register(s.staticEndpoints.Periodic)
 	server.Register(s.staticEndpoints.Plan)
 	server.Register(s.staticEndpoints.Region)
 	server.Register(s.staticEndpoints.Scaling)
+	server.Register(s.staticEndpoints.Sentinel)
+	server.Register(s.staticEndpoints.ServiceRegistration)
+	server.Register(s.staticEndpoints.Variables)
 	server.Register(s.staticEndpoints.Status)
 	server.Register(s.staticEndpoints.System)
 	server.Register(s.staticEndpoints.Search)
@@ -1189,9 +1298,11 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	node := &Node{srv: s, ctx: ctx, logger: s.logger.Named("client")}
+	keyring := &Keyring{srv: s, ctx: ctx, logger: s.logger.Named("keyring")}
 
 	// Register the dynamic endpoints
 	server.Register(node)
+	server.Register(keyring)
 }
 
 // setupRaft is used to setup and initialize Raft
@@ -1207,11 +1318,12 @@ func (s *Server) setupRaft() error {
 
 	// Create the FSM
 	fsmConfig := &FSMConfig{
-		EvalBroker: s.evalBroker,
-		Periodic:   s.periodicDispatcher,
-		Blocked:    s.blockedEvals,
-		Logger:     s.logger,
-		Region:     s.Region(),
+		EvalBroker:  s.evalBroker,
+		Periodic:    s.periodicDispatcher,
+		Blocked:     s.blockedEvals,
+		Logger:      s.logger,
+		Region:      s.Region(),
+		EventBroker: s.eventBroker,
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -1440,15 +1552,50 @@ func (s *Server) setupWorkers() error {
 		return fmt.Errorf("invalid configuration: %q scheduler not enabled", structs.JobTypeCore)
 	}
 
-	// Start the workers
-	for i := 0; i < s.config.NumSchedulers; i++ {
+	// Validate the scheduler worker pins
+	numPinned := 0
+	for sched, count := range s.config.SchedulerWorkerPins {
+		if count <= 0 {
+			return fmt.Errorf("invalid configuration: non-positive worker count for pinned scheduler %q", sched)
+		}
+		found := false
+		for _, enabled := range s.config.EnabledSchedulers {
+			if sched == enabled {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid configuration: pinned scheduler %q is not enabled", sched)
+		}
+		numPinned += count
+	}
+	if numPinned >= s.config.NumSchedulers {
+		return fmt.Errorf("invalid configuration: %d pinned workers leaves no unpinned workers out of %d",
+			numPinned, s.config.NumSchedulers)
+	}
+
+	// Start the pinned workers
+	for sched, count := range s.config.SchedulerWorkerPins {
+		for i := 0; i < count; i++ {
+			if w, err := NewPinnedWorker(s, []string{sched}); err != nil {
+				return err
+			} else {
+				s.workers = append(s.workers, w)
+			}
+		}
+	}
+
+	// Start the remaining workers against the full enabled set
+	for i := numPinned; i < s.config.NumSchedulers; i++ {
 		if w, err := NewWorker(s); err != nil {
 			return err
 		} else {
 			s.workers = append(s.workers, w)
 		}
 	}
-	s.logger.Info("starting scheduling worker(s)", "num_workers", s.config.NumSchedulers, "schedulers", s.config.EnabledSchedulers)
+	s.logger.Info("starting scheduling worker(s)", "num_workers", s.config.NumSchedulers,
+		"schedulers", s.config.EnabledSchedulers, "pinned_workers", s.config.SchedulerWorkerPins)
 	return nil
 }
 
@@ -1506,6 +1653,12 @@ func (s *Server) State() *state.StateStore {
 	return s.fsm.State()
 }
 
+// LicenseWatcher returns the license watcher, used for feature checks
+// outside of the RPC handlers.
+func (s *Server) LicenseWatcher() *LicenseWatcher {
+	return s.licenseWatcher
+}
+
 // setLeaderAcl stores the given ACL token as the current leader's ACL token.
 func (s *Server) setLeaderAcl(token string) {
 	s.leaderAclLock.Lock()
@@ -1586,6 +1739,18 @@ func (s *Server) Stats() map[string]map[string]string {
 		"vault":   s.vault.Stats(),
 	}
 
+	// Eval broker stats are only meaningful on the leader, where the broker
+	// is enabled, but are cheap to collect everywhere.
+	broker := s.evalBroker.Stats()
+	stats["broker"] = map[string]string{
+		"total_ready":   strconv.Itoa(broker.TotalReady),
+		"total_unacked": strconv.Itoa(broker.TotalUnacked),
+		"total_blocked": strconv.Itoa(broker.TotalBlocked),
+		"total_waiting": strconv.Itoa(broker.TotalWaiting),
+		"total_acks":    strconv.Itoa(broker.TotalAcks),
+		"total_nacks":   strconv.Itoa(broker.TotalNacks),
+	}
+
 	return stats
 }
 
@@ -551,6 +551,39 @@ func TestServer_InvalidSchedulers(t *testing.T) {
 	require.Contains(err.Error(), "foo")
 }
 
+func TestServer_InvalidSchedulerWorkerPins(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	config := DefaultConfig()
+	logger := testlog.HCLogger(t)
+	s := &Server{
+		config: config,
+		logger: logger,
+	}
+
+	config.NumSchedulers = 2
+	config.EnabledSchedulers = []string{"batch", "service", structs.JobTypeCore}
+
+	// Pin a scheduler type that is not enabled
+	config.SchedulerWorkerPins = map[string]int{"system": 1}
+	err := s.setupWorkers()
+	require.NotNil(err)
+	require.Contains(err.Error(), "not enabled")
+
+	// Pin more workers than are available
+	config.SchedulerWorkerPins = map[string]int{"batch": 1, "service": 1}
+	err = s.setupWorkers()
+	require.NotNil(err)
+	require.Contains(err.Error(), "no unpinned workers")
+
+	// Pin a non-positive worker count
+	config.SchedulerWorkerPins = map[string]int{"batch": 0}
+	err = s.setupWorkers()
+	require.NotNil(err)
+	require.Contains(err.Error(), "non-positive")
+}
+
 func TestServer_RPCNameAndRegionValidation(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
@@ -0,0 +1,251 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceRegistration endpoint is used for manipulating and discovering
+// services registered with the native service discovery provider.
+type ServiceRegistration struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used by Nomad clients to register the services of their
+// allocations. Requests are authenticated by the secret ID of the node the
+// registrations belong to, or by a token with node write permissions.
+func (s *ServiceRegistration) Upsert(args *structs.ServiceRegistrationUpsertRequest, reply *structs.ServiceRegistrationUpsertResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "upsert"}, time.Now())
+
+	if len(args.Services) == 0 {
+		return fmt.Errorf("missing service registrations")
+	}
+	for _, service := range args.Services {
+		if err := service.Validate(); err != nil {
+			return err
+		}
+		// Registrations are submitted by the client the allocations run
+		// on, so a single request never spans nodes.
+		if service.NodeID != args.Services[0].NodeID {
+			return fmt.Errorf("service registrations must belong to a single node")
+		}
+	}
+
+	if err := s.authenticateNodeRequest(args.AuthToken, args.Services[0].NodeID); err != nil {
+		return err
+	}
+
+	// Commit the registrations via Raft
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationUpsertRequestType, args)
+	if err != nil {
+		s.logger.Error("service registration upsert failed", "error", err)
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// DeleteByID removes a single service registration. It is used both by
+// clients removing the services of stopped allocations and by operators
+// removing stale registrations.
+func (s *ServiceRegistration) DeleteByID(args *structs.ServiceRegistrationDeleteByIDRequest, reply *structs.ServiceRegistrationDeleteByIDResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.DeleteByID", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "delete_by_id"}, time.Now())
+
+	if args.ID == "" {
+		return fmt.Errorf("missing service registration ID")
+	}
+
+	// Look for the registration so the request can be authenticated against
+	// the node and namespace it belongs to
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	service, err := snap.ServiceRegistrationByID(nil, args.ID)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		return fmt.Errorf("service registration not found")
+	}
+
+	// Allow the request if it is authenticated by the owning node, so
+	// clients can remove the services of stopped allocations.
+	if err := s.authenticateNodeRequest(args.AuthToken, service.NodeID); err != nil {
+		// Otherwise require submit-job on the service's namespace
+		if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+			return err
+		} else if aclObj != nil && !aclObj.AllowNsOp(service.Namespace, acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Commit the removal via Raft
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteByIDRequestType, args)
+	if err != nil {
+		s.logger.Error("service registration delete failed", "error", err)
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List returns one stub per service name registered within the request
+// namespace, with the union of the tags their instances carry.
+func (s *ServiceRegistration) List(args *structs.ServiceRegistrationListRequest, reply *structs.ServiceRegistrationListResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "list"}, time.Now())
+
+	// Check namespace read-job permissions
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ServiceRegistrations(ws)
+			if err != nil {
+				return err
+			}
+
+			// Aggregate the registrations by service name
+			stubs := make(map[string]*structs.ServiceRegistrationStub)
+			var names []string
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				service := raw.(*structs.ServiceRegistration)
+				if service.Namespace != args.RequestNamespace() {
+					continue
+				}
+
+				stub, ok := stubs[service.ServiceName]
+				if !ok {
+					stub = service.Stub()
+					stubs[service.ServiceName] = stub
+					names = append(names, service.ServiceName)
+					continue
+				}
+				for _, tag := range service.Tags {
+					if !stubContainsTag(stub, tag) {
+						stub.Tags = append(stub.Tags, tag)
+					}
+				}
+			}
+
+			reply.Services = make([]*structs.ServiceRegistrationStub, 0, len(names))
+			for _, name := range names {
+				reply.Services = append(reply.Services, stubs[name])
+			}
+
+			// Use the last index that affected the table
+			index, err := state.Index("service_registrations")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetService returns every registration of the named service within the
+// request namespace.
+func (s *ServiceRegistration) GetService(args *structs.ServiceRegistrationByNameRequest, reply *structs.ServiceRegistrationByNameResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.GetService", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "get_service"}, time.Now())
+
+	// Check namespace read-job permissions
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ServiceRegistrationsByName(ws, args.ServiceName)
+			if err != nil {
+				return err
+			}
+
+			reply.Services = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				service := raw.(*structs.ServiceRegistration)
+				if service.Namespace != args.RequestNamespace() {
+					continue
+				}
+				reply.Services = append(reply.Services, service)
+			}
+
+			// Use the last index that affected the table
+			index, err := state.Index("service_registrations")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// authenticateNodeRequest allows a request when ACLs are disabled or when
+// the provided token is the secret ID of the given node.
+func (s *ServiceRegistration) authenticateNodeRequest(authToken, nodeID string) error {
+	if !s.srv.config.ACLEnabled {
+		return nil
+	}
+
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, nodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+	if node.SecretID != authToken {
+		return structs.ErrPermissionDenied
+	}
+	return nil
+}
+
+// stubContainsTag returns whether the stub already carries the tag.
+func stubContainsTag(stub *structs.ServiceRegistrationStub, tag string) bool {
+	for _, t := range stub.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistrationEndpoint_Upsert(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// An empty request is invalid
+	req := &structs.ServiceRegistrationUpsertRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ServiceRegistrationUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing service registrations")
+
+	// Registrations spanning nodes are rejected
+	service1 := mock.ServiceRegistration()
+	service2 := mock.ServiceRegistration()
+	req.Services = []*structs.ServiceRegistration{service1, service2}
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "single node")
+
+	// Upsert two registrations for the same node
+	service2.NodeID = service1.NodeID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.NoError(err)
+	require.NotZero(resp.Index)
+
+	out, err := s1.fsm.State().ServiceRegistrationByID(nil, service1.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(service1.ServiceName, out.ServiceName)
+}
+
+func TestServiceRegistrationEndpoint_Upsert_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	node := mock.Node()
+	require.NoError(s1.fsm.State().UpsertNode(100, node))
+
+	service := mock.ServiceRegistration()
+	service.NodeID = node.ID
+	req := &structs.ServiceRegistrationUpsertRequest{
+		Services:     []*structs.ServiceRegistration{service},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Without the node secret the request is denied
+	var resp structs.ServiceRegistrationUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// The owning node's secret ID authenticates the request
+	req.AuthToken = node.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp))
+
+	out, err := s1.fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestServiceRegistrationEndpoint_DeleteByID(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	service := mock.ServiceRegistration()
+	require.NoError(s1.fsm.State().UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service}))
+
+	// Deleting an unknown ID is an error
+	req := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           "nope",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ServiceRegistrationDeleteByIDResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "service registration not found")
+
+	// Delete the registration
+	req.ID = service.ID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", req, &resp))
+
+	out, err := s1.fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestServiceRegistrationEndpoint_List(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Two instances of the same service with differing tags, plus a second
+	// service and one in another namespace
+	service1 := mock.ServiceRegistration()
+	service2 := mock.ServiceRegistration()
+	service2.Tags = []string{"bar", "canary"}
+	service3 := mock.ServiceRegistration()
+	service3.ServiceName = "countdash-web"
+	service4 := mock.ServiceRegistration()
+	service4.Namespace = "platform"
+	require.NoError(s1.fsm.State().UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service1, service2, service3, service4}))
+
+	req := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", req, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Services, 2)
+
+	for _, stub := range resp.Services {
+		switch stub.ServiceName {
+		case "countdash-api":
+			require.ElementsMatch([]string{"bar", "canary"}, stub.Tags)
+		case "countdash-web":
+			require.Equal([]string{"bar"}, stub.Tags)
+		default:
+			t.Fatalf("unexpected service %q", stub.ServiceName)
+		}
+	}
+}
+
+func TestServiceRegistrationEndpoint_GetService(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	service1 := mock.ServiceRegistration()
+	service2 := mock.ServiceRegistration()
+	service3 := mock.ServiceRegistration()
+	service3.Namespace = "platform"
+	require.NoError(s1.fsm.State().UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service1, service2, service3}))
+
+	req := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  service1.ServiceName,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", req, &resp))
+	require.EqualValues(1000, resp.Index)
+
+	// Only the registrations in the request namespace are returned
+	require.Len(resp.Services, 2)
+	for _, service := range resp.Services {
+		require.Equal(structs.DefaultNamespace, service.Namespace)
+	}
+
+	// An unknown service returns an empty response
+	req.ServiceName = "nope"
+	var resp2 structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", req, &resp2))
+	require.Empty(resp2.Services)
+}
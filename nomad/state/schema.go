@@ -9,6 +9,11 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+const (
+	// TableNamespaces is the name of the table that stores namespaces.
+	TableNamespaces = "namespaces"
+)
+
 var (
 	schemaFactories SchemaFactories
 	factoriesLock   sync.Mutex
@@ -37,6 +42,7 @@ func init() {
 		jobTableSchema,
 		jobSummarySchema,
 		jobVersionSchema,
+		jobSubmissionSchema,
 		deploymentSchema,
 		periodicLaunchTableSchema,
 		evalTableSchema,
@@ -44,14 +50,28 @@ func init() {
 		vaultAccessorTableSchema,
 		siTokenAccessorTableSchema,
 		aclPolicyTableSchema,
+		aclRoleTableSchema,
+		aclAuthMethodTableSchema,
+		aclBindingRuleTableSchema,
+		signingKeyTableSchema,
+		serviceRegistrationTableSchema,
+		variablesTableSchema,
+		sentinelPolicyTableSchema,
+		sentinelModuleTableSchema,
+		sentinelOverrideTableSchema,
 		aclTokenTableSchema,
 		autopilotConfigTableSchema,
 		schedulerConfigTableSchema,
 		clusterMetaTableSchema,
+		licenseTableSchema,
+		licenseEventTableSchema,
+		featureFlagsTableSchema,
+		freezeWindowsTableSchema,
 		csiVolumeTableSchema,
 		csiPluginTableSchema,
 		scalingPolicyTableSchema,
 		scalingEventTableSchema,
+		namespaceTableSchema,
 	}...)
 }
 
@@ -239,6 +259,40 @@ func jobVersionSchema() *memdb.TableSchema {
 	}
 }
 
+// jobSubmissionSchema returns the memdb table schema of the original job
+// source material kept for each tracked job version.
+func jobSubmissionSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "job_submission",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "JobID",
+							Lowercase: true,
+						},
+
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // jobIsGCable satisfies the ConditionalIndexFunc interface and creates an index
 // on whether a job is eligible for garbage collection.
 func jobIsGCable(obj interface{}) (bool, error) {
@@ -612,6 +666,205 @@ func aclPolicyTableSchema() *memdb.TableSchema {
 	}
 }
 
+// aclRoleTableSchema returns the MemDB schema for the ACL role table.
+// Roles group policies so that they can be granted to tokens as a unit.
+func aclRoleTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_role",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclAuthMethodTableSchema returns the MemDB schema for the ACL auth method
+// table. Auth methods describe how users can log in via external identity
+// providers.
+func aclAuthMethodTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_auth_method",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclBindingRuleTableSchema returns the MemDB schema for the ACL binding rule
+// table. Binding rules convert auth method identities into token grants.
+func aclBindingRuleTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_binding_rule",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+			"auth_method": {
+				Name:         "auth_method",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AuthMethod",
+				},
+			},
+		},
+	}
+}
+
+// signingKeyTableSchema returns the MemDB schema for the workload identity
+// signing keyring table. This table is used to store the keys used to sign
+// workload identity tokens
+func signingKeyTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "signing_key",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// serviceRegistrationTableSchema returns the MemDB schema for the service
+// registrations table. This table is used to store the services registered
+// with the native service discovery provider
+func serviceRegistrationTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "service_registrations",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			"service": {
+				Name:         "service",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ServiceName",
+				},
+			},
+			"alloc_id": {
+				Name:         "alloc_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "AllocID",
+				},
+			},
+			"node_id": {
+				Name:         "node_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "NodeID",
+				},
+			},
+		},
+	}
+}
+
+// variablesTableSchema returns the MemDB schema for the variables table.
+// This table is used to store the encrypted key/value variables
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "variables",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, Path) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// sentinelPolicyTableSchema returns the MemDB schema for the sentinel policy
+// table. This table is used to store the policies which are enforced on
+// writes in a given scope
+func sentinelPolicyTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "sentinel_policy",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+			"scope": {
+				Name:         "scope",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Scope",
+				},
+			},
+		},
+	}
+}
+
+// sentinelModuleTableSchema returns the MemDB schema for the sentinel module
+// table. This table is used to store the modules that policies can import.
+func sentinelModuleTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "sentinel_module",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
 // aclTokenTableSchema returns the MemDB schema for the tokens table.
 // This table is used to store the bearer tokens which are used to authenticate
 func aclTokenTableSchema() *memdb.TableSchema {
@@ -683,6 +936,90 @@ func clusterMetaTableSchema() *memdb.TableSchema {
 	}
 }
 
+// featureFlagsTableSchema returns the MemDB schema for the feature flags
+// table. This table is used to store cluster-wide feature flag overrides.
+func featureFlagsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "feature_flags",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: true,
+				Unique:       true,
+				Indexer:      singletonRecord, // we store only 1 flag set
+			},
+		},
+	}
+}
+
+// freezeWindowsTableSchema returns the MemDB schema for the freeze windows
+// table. This table is used to store the cluster's change-freeze windows.
+func freezeWindowsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "freeze_windows",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: true,
+				Unique:       true,
+				Indexer:      singletonRecord, // we store only 1 window set
+			},
+		},
+	}
+}
+
+// licenseEventTableSchema returns the MemDB schema for the license events
+// table. This table records the audit trail of license changes.
+func licenseEventTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "license_events",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UintFieldIndex{
+					Field: "CreateIndex",
+				},
+			},
+		},
+	}
+}
+
+// sentinelOverrideTableSchema returns the MemDB schema for the sentinel
+// overrides table. This table records job submissions that overrode
+// soft-mandatory Sentinel policies.
+func sentinelOverrideTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "sentinel_overrides",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UintFieldIndex{
+					Field: "CreateIndex",
+				},
+			},
+		},
+	}
+}
+
+// licenseTableSchema returns the MemDB schema for the license table.
+func licenseTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "license",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer:      singletonRecord, // we store only 1 license
+			},
+		},
+	}
+}
+
 // CSIVolumes are identified by id globally, and searchable by driver
 func csiVolumeTableSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
@@ -880,3 +1217,20 @@ func scalingEventTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// namespaceTableSchema returns the MemDB schema for the namespace table.
+func namespaceTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNamespaces,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
@@ -87,6 +87,11 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		return nil, fmt.Errorf("enterprise state store initialization failed: %v", err)
 	}
 
+	// Initialize the state store with the default namespace.
+	if err := s.namespaceInit(); err != nil {
+		return nil, fmt.Errorf("namespace state store initialization failed: %v", err)
+	}
+
 	return s, nil
 }
 
@@ -982,6 +987,55 @@ func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibil
 	return nil
 }
 
+// BatchUpdateNodeMeta is used to merge a metadata update into a set of
+// nodes. A nil value in the update removes the key.
+func (s *StateStore) BatchUpdateNodeMeta(index uint64, updatedAt int64, nodes []string, meta map[string]*string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, nodeID := range nodes {
+		// Lookup the node
+		existing, err := txn.First("nodes", "id", nodeID)
+		if err != nil {
+			return fmt.Errorf("node lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("node not found")
+		}
+
+		// Copy the existing node
+		existingNode := existing.(*structs.Node)
+		copyNode := existingNode.Copy()
+		copyNode.StatusUpdatedAt = updatedAt
+
+		// Merge the update into the copy
+		if copyNode.Meta == nil {
+			copyNode.Meta = make(map[string]string, len(meta))
+		}
+		for k, v := range meta {
+			if v == nil {
+				delete(copyNode.Meta, k)
+			} else {
+				copyNode.Meta[k] = *v
+			}
+		}
+
+		copyNode.ModifyIndex = index
+
+		// Insert the node
+		if err := txn.Insert("nodes", copyNode); err != nil {
+			return fmt.Errorf("node update failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // UpsertNodeEvents adds the node events to the nodes, rotating events as
 // necessary.
 func (s *StateStore) UpsertNodeEvents(index uint64, nodeEvents map[string][]*structs.NodeEvent) error {
@@ -1560,6 +1614,11 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 		return err
 	}
 
+	// Delete any stored job submissions
+	if err := s.deleteJobSubmissions(namespace, jobID, txn); err != nil {
+		return err
+	}
+
 	// Cleanup plugins registered by this job, before we delete the summary
 	err = s.deleteJobFromPlugins(index, txn, job)
 	if err != nil {
@@ -1711,6 +1770,119 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *txn)
 	return nil
 }
 
+// UpsertJobSubmission stores the original source material of a job version,
+// pruning submissions for versions that are no longer tracked.
+func (s *StateStore) UpsertJobSubmission(index uint64, sub *structs.JobSubmission) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	if err := txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("failed to insert job submission: %v", err)
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"job_submission", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	// Prune submissions beyond the number of tracked job versions. The
+	// compound index yields them in ascending version order.
+	iter, err := txn.Get("job_submission", "id_prefix", sub.Namespace, sub.JobID)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+
+	var all []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		all = append(all, raw.(*structs.JobSubmission))
+	}
+	for i := 0; i < len(all)-structs.JobTrackedVersions; i++ {
+		if err := txn.Delete("job_submission", all[i]); err != nil {
+			return fmt.Errorf("failed to delete job submission: %v", err)
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// JobSubmission returns the original source material of the given job
+// version, or nil if none was recorded.
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("job_submission", "id", namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over all job submissions.
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("job_submission", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// deleteJobSubmissions deletes all stored submissions for the given job.
+func (s *StateStore) deleteJobSubmissions(namespace, jobID string, txn *txn) error {
+	iter, err := txn.Get("job_submission", "id_prefix", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+
+	var all []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		all = append(all, raw.(*structs.JobSubmission))
+	}
+	for _, sub := range all {
+		if err := txn.Delete("job_submission", sub); err != nil {
+			return fmt.Errorf("failed to delete job submission: %v", err)
+		}
+	}
+	return nil
+}
+
+// AppendJobPayload appends a chunk of a dispatch payload to an existing job.
+// Only the payload is modified, so no new job version is created for each
+// chunk.
+func (s *StateStore) AppendJobPayload(index uint64, namespace, id string, chunk []byte) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("jobs", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job %q (%s) does not exist", id, namespace)
+	}
+
+	job := existing.(*structs.Job).Copy()
+	job.Payload = append(job.Payload, chunk...)
+	job.ModifyIndex = index
+
+	if err := txn.Insert("jobs", job); err != nil {
+		return fmt.Errorf("job insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // JobByID is used to lookup a job by its ID. JobByID returns the current/latest job
 // version.
 func (s *StateStore) JobByID(ws memdb.WatchSet, namespace, id string) (*structs.Job, error) {
@@ -2422,7 +2594,7 @@ func (s *StateStore) CSIPluginDenormalize(ws memdb.WatchSet, plug *structs.CSIPl
 		if alloc == nil {
 			continue
 		}
-		plug.Allocations = append(plug.Allocations, alloc.Stub())
+		plug.Allocations = append(plug.Allocations, alloc.Stub(nil))
 	}
 
 	return plug, nil
@@ -2831,6 +3003,39 @@ func (s *StateStore) EvalsByIDPrefix(ws memdb.WatchSet, namespace, id string) (m
 	return wrap, nil
 }
 
+// EvalsByIDPrefixInNSes returns an iterator over all evals in the namespace
+// set that match the ID prefix. A nil namespace set means all namespaces are
+// allowed. An empty prefix matches all evals.
+func (s *StateStore) EvalsByIDPrefixInNSes(ws memdb.WatchSet, namespaces map[string]bool, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+	if prefix != "" {
+		iter, err = txn.Get("evals", "id_prefix", prefix)
+	} else {
+		iter, err = txn.Get("evals", "id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eval lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+
+	// Wrap the iterator in a filter
+	nsesFilter := func(raw interface{}) bool {
+		eval, ok := raw.(*structs.Evaluation)
+		if !ok {
+			return true
+		}
+
+		return namespaces != nil && !namespaces[eval.Namespace]
+	}
+
+	wrap := memdb.NewFilterIterator(iter, nsesFilter)
+	return wrap, nil
+}
+
 // evalNamespaceFilter returns a filter function that filters all evaluations
 // not in the given namespace.
 func evalNamespaceFilter(namespace string) func(interface{}) bool {
@@ -2952,6 +3157,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 	copyAlloc.ClientStatus = alloc.ClientStatus
 	copyAlloc.ClientDescription = alloc.ClientDescription
 	copyAlloc.TaskStates = alloc.TaskStates
+	copyAlloc.NetworkStatus = alloc.NetworkStatus
 
 	// The client can only set its deployment health and timestamp, so just take
 	// those
@@ -3151,6 +3357,38 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 	return nil
 }
 
+// SetAllocCheckpointMeta is used to replace the checkpoint metadata of an
+// existing allocation.
+func (s *StateStore) SetAllocCheckpointMeta(index uint64, allocID string, meta map[string]string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("allocs", "id", allocID)
+	if err != nil {
+		return fmt.Errorf("alloc lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("alloc not found")
+	}
+
+	// Copy everything from the existing allocation
+	copyAlloc := existing.(*structs.Allocation).Copy()
+	copyAlloc.CheckpointMeta = meta
+	copyAlloc.ModifyIndex = index
+
+	if err := txn.Insert("allocs", copyAlloc); err != nil {
+		return fmt.Errorf("alloc insert failed: %v", err)
+	}
+
+	// Update the indexes
+	if err := txn.Insert("index", &IndexEntry{"allocs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // UpdateAllocsDesiredTransitions is used to update a set of allocations
 // desired transitions.
 func (s *StateStore) UpdateAllocsDesiredTransitions(index uint64, allocs map[string]*structs.DesiredTransition,
@@ -3280,14 +3518,15 @@ func (s *StateStore) AllocsByIDPrefixInNSes(ws memdb.WatchSet, namespaces map[st
 
 	ws.Add(iter.WatchCh())
 
-	// Wrap the iterator in a filter
+	// Wrap the iterator in a filter. A nil namespace set means all namespaces
+	// are allowed.
 	nsesFilter := func(raw interface{}) bool {
 		alloc, ok := raw.(*structs.Allocation)
 		if !ok {
 			return true
 		}
 
-		return namespaces[alloc.Namespace]
+		return namespaces != nil && !namespaces[alloc.Namespace]
 	}
 
 	wrap := memdb.NewFilterIterator(iter, nsesFilter)
@@ -3850,6 +4089,21 @@ func (s *StateStore) UpdateDeploymentPromotion(index uint64, req *structs.ApplyD
 		groupIndex[g] = struct{}{}
 	}
 
+	// Ensure the requested groups are part of the deployment so a mistyped
+	// group does not silently promote nothing
+	if !req.All {
+		var invalid []string
+		for _, g := range req.Groups {
+			if _, ok := deployment.TaskGroups[g]; !ok {
+				invalid = append(invalid, g)
+			}
+		}
+		if len(invalid) != 0 {
+			sort.Strings(invalid)
+			return fmt.Errorf("Task groups %v are not part of deployment %q", invalid, deployment.ID)
+		}
+	}
+
 	// canaryIndex is the set of placed canaries in the deployment
 	canaryIndex := make(map[string]struct{}, len(deployment.TaskGroups))
 	for _, dstate := range deployment.TaskGroups {
@@ -5021,142 +5275,101 @@ func (s *StateStore) ACLPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error
 	return iter, nil
 }
 
-// UpsertACLTokens is used to create or update a set of ACL tokens
-func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+// UpsertACLRoles is used to create or update a set of ACL roles
+func (s *StateStore) UpsertACLRoles(index uint64, roles []*structs.ACLRole) error {
 	txn := s.db.WriteTxn(index)
 	defer txn.Abort()
 
-	for _, token := range tokens {
-		// Ensure the policy hash is non-nil. This should be done outside the state store
+	for _, role := range roles {
+		// Ensure the role hash is non-nil. This should be done outside the state store
 		// for performance reasons, but we check here for defense in depth.
-		if len(token.Hash) == 0 {
-			token.SetHash()
+		if len(role.Hash) == 0 {
+			role.SetHash()
 		}
 
-		// Check if the token already exists
-		existing, err := txn.First("acl_token", "id", token.AccessorID)
+		// Check if the role already exists
+		existing, err := txn.First("acl_role", "id", role.Name)
 		if err != nil {
-			return fmt.Errorf("token lookup failed: %v", err)
+			return fmt.Errorf("role lookup failed: %v", err)
 		}
 
 		// Update all the indexes
 		if existing != nil {
-			existTK := existing.(*structs.ACLToken)
-			token.CreateIndex = existTK.CreateIndex
-			token.ModifyIndex = index
-
-			// Do not allow SecretID or create time to change
-			token.SecretID = existTK.SecretID
-			token.CreateTime = existTK.CreateTime
-
+			role.CreateIndex = existing.(*structs.ACLRole).CreateIndex
+			role.ModifyIndex = index
 		} else {
-			token.CreateIndex = index
-			token.ModifyIndex = index
+			role.CreateIndex = index
+			role.ModifyIndex = index
 		}
 
-		// Update the token
-		if err := txn.Insert("acl_token", token); err != nil {
-			return fmt.Errorf("upserting token failed: %v", err)
+		// Update the role
+		if err := txn.Insert("acl_role", role); err != nil {
+			return fmt.Errorf("upserting role failed: %v", err)
 		}
 	}
 
 	// Update the indexes table
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
+
 	txn.Commit()
 	return nil
 }
 
-// DeleteACLTokens deletes the tokens with the given accessor ids
-func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+// DeleteACLRoles deletes the roles with the given names
+func (s *StateStore) DeleteACLRoles(index uint64, names []string) error {
 	txn := s.db.WriteTxn(index)
 	defer txn.Abort()
 
-	// Delete the tokens
-	for _, id := range ids {
-		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
-			return fmt.Errorf("deleting acl token failed: %v", err)
+	// Delete the role
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_role", "id", name); err != nil {
+			return fmt.Errorf("deleting acl role failed: %v", err)
 		}
 	}
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 	txn.Commit()
 	return nil
 }
 
-// ACLTokenByAccessorID is used to lookup a token by accessor ID
-func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
-	if id == "" {
-		return nil, fmt.Errorf("acl token lookup failed: missing accessor id")
-	}
-
-	txn := s.db.ReadTxn()
-
-	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
-	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
-	}
-	ws.Add(watchCh)
-
-	if existing != nil {
-		return existing.(*structs.ACLToken), nil
-	}
-	return nil, nil
-}
-
-// ACLTokenBySecretID is used to lookup a token by secret ID
-func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
-	if secretID == "" {
-		return nil, fmt.Errorf("acl token lookup failed: missing secret id")
-	}
-
+// ACLRoleByName is used to lookup a role by name
+func (s *StateStore) ACLRoleByName(ws memdb.WatchSet, name string) (*structs.ACLRole, error) {
 	txn := s.db.ReadTxn()
 
-	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	watchCh, existing, err := txn.FirstWatch("acl_role", "id", name)
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
 	}
 	ws.Add(watchCh)
 
 	if existing != nil {
-		return existing.(*structs.ACLToken), nil
+		return existing.(*structs.ACLRole), nil
 	}
 	return nil, nil
 }
 
-// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
-func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+// ACLRoleByNamePrefix is used to lookup roles by prefix
+func (s *StateStore) ACLRoleByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	iter, err := txn.Get("acl_role", "id_prefix", prefix)
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
 	}
 	ws.Add(iter.WatchCh())
-	return iter, nil
-}
-
-// ACLTokens returns an iterator over all the tokens
-func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
-	txn := s.db.ReadTxn()
 
-	// Walk the entire table
-	iter, err := txn.Get("acl_token", "id")
-	if err != nil {
-		return nil, err
-	}
-	ws.Add(iter.WatchCh())
 	return iter, nil
 }
 
-// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
-func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+// ACLRoles returns an iterator over all the acl roles
+func (s *StateStore) ACLRoles(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("acl_token", "global", globalVal)
+	iter, err := txn.Get("acl_role", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -5164,18 +5377,1082 @@ func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb
 	return iter, nil
 }
 
-// CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
-func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
-	txn := s.db.ReadTxn()
+// UpsertACLAuthMethods is used to create or update a set of ACL auth methods
+func (s *StateStore) UpsertACLAuthMethods(index uint64, methods []*structs.ACLAuthMethod) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
 
-	// Lookup the bootstrap sentinel
-	out, err := txn.First("index", "id", "acl_token_bootstrap")
-	if err != nil {
-		return false, 0, err
-	}
+	for _, method := range methods {
+		// Ensure the method hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(method.Hash) == 0 {
+			method.SetHash()
+		}
 
-	// No entry, we haven't bootstrapped yet
-	if out == nil {
+		// Check if the auth method already exists
+		existing, err := txn.First("acl_auth_method", "id", method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			method.CreateIndex = existing.(*structs.ACLAuthMethod).CreateIndex
+			method.ModifyIndex = index
+		} else {
+			method.CreateIndex = index
+			method.ModifyIndex = index
+		}
+
+		// Update the auth method
+		if err := txn.Insert("acl_auth_method", method); err != nil {
+			return fmt.Errorf("upserting auth method failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLAuthMethods deletes the auth methods with the given names
+func (s *StateStore) DeleteACLAuthMethods(index uint64, names []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Delete the auth method
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_auth_method", "id", name); err != nil {
+			return fmt.Errorf("deleting acl auth method failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLAuthMethodByName is used to lookup an auth method by name
+func (s *StateStore) ACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("acl_auth_method", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("acl auth method lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// ACLAuthMethods returns an iterator over all the acl auth methods
+func (s *StateStore) ACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_auth_method", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// DefaultACLAuthMethod returns the auth method flagged as the default, or
+// nil if no default is configured.
+func (s *StateStore) DefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	iter, err := s.ACLAuthMethods(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Default {
+			return method, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpsertACLBindingRules is used to create or update a set of ACL binding rules
+func (s *StateStore) UpsertACLBindingRules(index uint64, rules []*structs.ACLBindingRule) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, rule := range rules {
+		// Ensure the rule hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(rule.Hash) == 0 {
+			rule.SetHash()
+		}
+
+		// Check if the binding rule already exists
+		existing, err := txn.First("acl_binding_rule", "id", rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			rule.CreateIndex = existing.(*structs.ACLBindingRule).CreateIndex
+			rule.ModifyIndex = index
+		} else {
+			rule.CreateIndex = index
+			rule.ModifyIndex = index
+		}
+
+		// Update the binding rule
+		if err := txn.Insert("acl_binding_rule", rule); err != nil {
+			return fmt.Errorf("upserting binding rule failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLBindingRules deletes the binding rules with the given IDs
+func (s *StateStore) DeleteACLBindingRules(index uint64, ids []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Delete the binding rule
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_binding_rule", "id", id); err != nil {
+			return fmt.Errorf("deleting acl binding rule failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLBindingRuleByID is used to lookup a binding rule by ID
+func (s *StateStore) ACLBindingRuleByID(ws memdb.WatchSet, id string) (*structs.ACLBindingRule, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("acl_binding_rule", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("acl binding rule lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLBindingRule), nil
+	}
+	return nil, nil
+}
+
+// ACLBindingRulesByAuthMethod returns an iterator over the binding rules
+// attached to the given auth method
+func (s *StateStore) ACLBindingRulesByAuthMethod(ws memdb.WatchSet, method string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("acl_binding_rule", "auth_method", method)
+	if err != nil {
+		return nil, fmt.Errorf("acl binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ACLBindingRules returns an iterator over all the acl binding rules
+func (s *StateStore) ACLBindingRules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_binding_rule", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertSigningKeys is used to register a set of workload identity signing
+// keys
+func (s *StateStore) UpsertSigningKeys(index uint64, keys []*structs.SigningKey) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, key := range keys {
+		// Check if the key already exists
+		existing, err := txn.First("signing_key", "id", key.KeyID)
+		if err != nil {
+			return fmt.Errorf("signing key lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			key.CreateIndex = existing.(*structs.SigningKey).CreateIndex
+			key.ModifyIndex = index
+		} else {
+			key.CreateIndex = index
+			key.ModifyIndex = index
+		}
+
+		// Update the signing key
+		if err := txn.Insert("signing_key", key); err != nil {
+			return fmt.Errorf("upserting signing key failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"signing_key", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// SigningKeyByID is used to lookup a signing key by its KeyID
+func (s *StateStore) SigningKeyByID(ws memdb.WatchSet, id string) (*structs.SigningKey, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("signing_key", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("signing key lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.SigningKey), nil
+	}
+	return nil, nil
+}
+
+// ActiveSigningKey returns the signing key used to sign new workload
+// identities, if one has been established.
+func (s *StateStore) ActiveSigningKey(ws memdb.WatchSet) (*structs.SigningKey, error) {
+	iter, err := s.SigningKeys(ws)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		key := raw.(*structs.SigningKey)
+		if key.Active {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// SigningKeys returns an iterator over the entire signing keyring
+func (s *StateStore) SigningKeys(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("signing_key", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// DeleteSigningKey is used to remove a signing key from the keyring
+func (s *StateStore) DeleteSigningKey(index uint64, keyID string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("signing_key", "id", keyID)
+	if err != nil {
+		return fmt.Errorf("signing key lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("signing key not found")
+	}
+
+	if err := txn.Delete("signing_key", existing); err != nil {
+		return fmt.Errorf("deleting signing key failed: %v", err)
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"signing_key", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpsertServiceRegistrations is used to insert or update a set of service
+// registrations
+func (s *StateStore) UpsertServiceRegistrations(index uint64, services []*structs.ServiceRegistration) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	var updated bool
+	for _, service := range services {
+		// Check if the registration already exists
+		existing, err := txn.First("service_registrations", "id", service.ID)
+		if err != nil {
+			return fmt.Errorf("service registration lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			if existing.(*structs.ServiceRegistration).Equals(service) {
+				// The registration is unchanged; avoid a spurious write
+				continue
+			}
+			service.CreateIndex = existing.(*structs.ServiceRegistration).CreateIndex
+			service.ModifyIndex = index
+		} else {
+			service.CreateIndex = index
+			service.ModifyIndex = index
+		}
+
+		// Update the registration
+		if err := txn.Insert("service_registrations", service); err != nil {
+			return fmt.Errorf("upserting service registration failed: %v", err)
+		}
+		updated = true
+	}
+
+	// Update the indexes table
+	if updated {
+		if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteServiceRegistrationByID is used to remove a service registration
+func (s *StateStore) DeleteServiceRegistrationByID(index uint64, id string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("service_registrations", "id", id)
+	if err != nil {
+		return fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("service registration not found")
+	}
+
+	if err := txn.Delete("service_registrations", existing); err != nil {
+		return fmt.Errorf("deleting service registration failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// ServiceRegistrationByID is used to lookup a service registration by its ID
+func (s *StateStore) ServiceRegistrationByID(ws memdb.WatchSet, id string) (*structs.ServiceRegistration, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("service_registrations", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ServiceRegistration), nil
+	}
+	return nil, nil
+}
+
+// ServiceRegistrationsByName returns an iterator over the registrations of
+// the named service
+func (s *StateStore) ServiceRegistrationsByName(ws memdb.WatchSet, name string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("service_registrations", "service", name)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ServiceRegistrationsByAllocID returns an iterator over the registrations
+// belonging to the given allocation
+func (s *StateStore) ServiceRegistrationsByAllocID(ws memdb.WatchSet, allocID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ServiceRegistrations returns an iterator over the entire service
+// registrations table
+func (s *StateStore) ServiceRegistrations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("service_registrations", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertVariables is used to insert or update a set of encrypted variables
+func (s *StateStore) UpsertVariables(index uint64, vars []*structs.EncryptedVariable) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, v := range vars {
+		// Check if the variable already exists
+		existing, err := txn.First("variables", "id", v.Namespace, v.Path)
+		if err != nil {
+			return fmt.Errorf("variable lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			exist := existing.(*structs.EncryptedVariable)
+			v.CreateIndex = exist.CreateIndex
+			v.CreateTime = exist.CreateTime
+			v.ModifyIndex = index
+		} else {
+			v.CreateIndex = index
+			v.ModifyIndex = index
+		}
+
+		// Update the variable
+		if err := txn.Insert("variables", v); err != nil {
+			return fmt.Errorf("upserting variable failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteVariable is used to remove the variable at a path
+func (s *StateStore) DeleteVariable(index uint64, namespace, path string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("variables", "id", namespace, path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("variable not found")
+	}
+
+	if err := txn.Delete("variables", existing); err != nil {
+		return fmt.Errorf("deleting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetVariable is used to lookup the variable at a path
+func (s *StateStore) GetVariable(ws memdb.WatchSet, namespace, path string) (*structs.EncryptedVariable, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("variables", "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.EncryptedVariable), nil
+	}
+	return nil, nil
+}
+
+// VariablesByNamespace returns an iterator over the variables of a
+// namespace whose paths match the given prefix
+func (s *StateStore) VariablesByNamespace(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("variables", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// Variables returns an iterator over the entire variables table
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("variables", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// namespaceInit ensures the default namespace exists.
+func (s *StateStore) namespaceInit() error {
+	// Upsert a default namespace if it doesn't exist.
+	defaultNs := &structs.Namespace{
+		Name:        structs.DefaultNamespace,
+		Description: structs.DefaultNamespaceDescription,
+	}
+
+	// Should never fail
+	return s.UpsertNamespaces(1, []*structs.Namespace{defaultNs})
+}
+
+// UpsertNamespaces is used to register or update a set of namespaces
+func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, ns := range namespaces {
+		// Ensure the namespace hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(ns.Hash) == 0 {
+			ns.SetHash()
+		}
+
+		// Check if the namespace already exists
+		existing, err := txn.First(TableNamespaces, "id", ns.Name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+
+		// Setup the indexes correctly
+		if existing != nil {
+			exist := existing.(*structs.Namespace)
+			ns.CreateIndex = exist.CreateIndex
+			ns.ModifyIndex = index
+		} else {
+			ns.CreateIndex = index
+			ns.ModifyIndex = index
+		}
+
+		// Insert the namespace
+		if err := txn.Insert(TableNamespaces, ns); err != nil {
+			return fmt.Errorf("namespace insert failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{TableNamespaces, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteNamespaces is used to remove a set of namespaces
+func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		// Lookup the namespace
+		existing, err := txn.First(TableNamespaces, "id", name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("namespace not found")
+		}
+
+		ns := existing.(*structs.Namespace)
+		if ns.Name == structs.DefaultNamespace {
+			return fmt.Errorf("default namespace can not be deleted")
+		}
+
+		// Ensure that the namespace doesn't have any non-terminal jobs
+		iter, err := s.jobsByNamespaceImpl(nil, name, txn)
+		if err != nil {
+			return err
+		}
+
+		for {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			job := raw.(*structs.Job)
+
+			if job.Status != structs.JobStatusDead {
+				return fmt.Errorf("namespace %q contains at least one non-terminal job %q. "+
+					"All jobs must be terminal in namespace before it can be deleted", name, job.ID)
+			}
+		}
+
+		// Delete the namespace
+		if err := txn.Delete(TableNamespaces, existing); err != nil {
+			return fmt.Errorf("namespace deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableNamespaces, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// NamespaceByName is used to lookup a namespace by name
+func (s *StateStore) NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error) {
+	txn := s.db.ReadTxn()
+	return s.namespaceByNameImpl(ws, txn, name)
+}
+
+// namespaceByNameImpl is used to lookup a namespace by name
+func (s *StateStore) namespaceByNameImpl(ws memdb.WatchSet, txn *txn, name string) (*structs.Namespace, error) {
+	watchCh, existing, err := txn.FirstWatch(TableNamespaces, "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.Namespace), nil
+	}
+	return nil, nil
+}
+
+// NamespacesByNamePrefix is used to lookup namespaces by prefix
+func (s *StateStore) NamespacesByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNamespaces, "id_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("namespaces lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// Namespaces returns an iterator over all the namespaces
+func (s *StateStore) Namespaces(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire namespace table
+	iter, err := txn.Get(TableNamespaces, "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertSentinelPolicies is used to create or update a set of Sentinel policies
+func (s *StateStore) UpsertSentinelPolicies(index uint64, policies []*structs.SentinelPolicy) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, policy := range policies {
+		// Ensure the policy hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(policy.Hash) == 0 {
+			policy.SetHash()
+		}
+
+		// Check if the policy already exists
+		existing, err := txn.First("sentinel_policy", "id", policy.Name)
+		if err != nil {
+			return fmt.Errorf("policy lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			policy.CreateIndex = existing.(*structs.SentinelPolicy).CreateIndex
+			policy.ModifyIndex = index
+		} else {
+			policy.CreateIndex = index
+			policy.ModifyIndex = index
+		}
+
+		// Update the policy
+		if err := txn.Insert("sentinel_policy", policy); err != nil {
+			return fmt.Errorf("upserting policy failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"sentinel_policy", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteSentinelPolicies deletes the policies with the given names
+func (s *StateStore) DeleteSentinelPolicies(index uint64, names []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Delete the policy
+	for _, name := range names {
+		if _, err := txn.DeleteAll("sentinel_policy", "id", name); err != nil {
+			return fmt.Errorf("deleting sentinel policy failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"sentinel_policy", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// SentinelPolicyByName is used to lookup a policy by name
+func (s *StateStore) SentinelPolicyByName(ws memdb.WatchSet, name string) (*structs.SentinelPolicy, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("sentinel_policy", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("sentinel policy lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.SentinelPolicy), nil
+	}
+	return nil, nil
+}
+
+// SentinelPolicyByNamePrefix is used to lookup policies by prefix
+func (s *StateStore) SentinelPolicyByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("sentinel_policy", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("sentinel policy lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// SentinelPolicies returns an iterator over all the sentinel policies
+func (s *StateStore) SentinelPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("sentinel_policy", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// SentinelPoliciesByScope returns an iterator over all the sentinel policies
+// for the given scope
+func (s *StateStore) SentinelPoliciesByScope(ws memdb.WatchSet, scope string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("sentinel_policy", "scope", scope)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertSentinelModules is used to create or update a set of Sentinel modules
+func (s *StateStore) UpsertSentinelModules(index uint64, modules []*structs.SentinelModule) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, module := range modules {
+		// Ensure the module hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(module.Hash) == 0 {
+			module.SetHash()
+		}
+
+		// Check if the module already exists
+		existing, err := txn.First("sentinel_module", "id", module.Name)
+		if err != nil {
+			return fmt.Errorf("module lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			module.CreateIndex = existing.(*structs.SentinelModule).CreateIndex
+			module.ModifyIndex = index
+		} else {
+			module.CreateIndex = index
+			module.ModifyIndex = index
+		}
+
+		// Update the module
+		if err := txn.Insert("sentinel_module", module); err != nil {
+			return fmt.Errorf("upserting module failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"sentinel_module", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteSentinelModules deletes the modules with the given names
+func (s *StateStore) DeleteSentinelModules(index uint64, names []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Delete the module
+	for _, name := range names {
+		if _, err := txn.DeleteAll("sentinel_module", "id", name); err != nil {
+			return fmt.Errorf("deleting sentinel module failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"sentinel_module", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// SentinelModuleByName is used to lookup a module by name
+func (s *StateStore) SentinelModuleByName(ws memdb.WatchSet, name string) (*structs.SentinelModule, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("sentinel_module", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("sentinel module lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.SentinelModule), nil
+	}
+	return nil, nil
+}
+
+// SentinelModuleByNamePrefix is used to lookup modules by prefix
+func (s *StateStore) SentinelModuleByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("sentinel_module", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("sentinel module lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// SentinelModules returns an iterator over all the sentinel modules
+func (s *StateStore) SentinelModules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("sentinel_module", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertACLTokens is used to create or update a set of ACL tokens
+func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	for _, token := range tokens {
+		// Ensure the policy hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(token.Hash) == 0 {
+			token.SetHash()
+		}
+
+		// Check if the token already exists
+		existing, err := txn.First("acl_token", "id", token.AccessorID)
+		if err != nil {
+			return fmt.Errorf("token lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			existTK := existing.(*structs.ACLToken)
+			token.CreateIndex = existTK.CreateIndex
+			token.ModifyIndex = index
+
+			// Do not allow SecretID or create time to change
+			token.SecretID = existTK.SecretID
+			token.CreateTime = existTK.CreateTime
+
+		} else {
+			token.CreateIndex = index
+			token.ModifyIndex = index
+		}
+
+		// Update the token
+		if err := txn.Insert("acl_token", token); err != nil {
+			return fmt.Errorf("upserting token failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLTokens deletes the tokens with the given accessor ids
+func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Delete the tokens
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
+			return fmt.Errorf("deleting acl token failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLTokenByAccessorID is used to lookup a token by accessor ID
+func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("acl token lookup failed: missing accessor id")
+	}
+
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenBySecretID is used to lookup a token by secret ID
+func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
+	if secretID == "" {
+		return nil, fmt.Errorf("acl token lookup failed: missing secret id")
+	}
+
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
+func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokens returns an iterator over all the tokens
+func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_token", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
+func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_token", "global", globalVal)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
+func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
+	txn := s.db.ReadTxn()
+
+	// Lookup the bootstrap sentinel
+	out, err := txn.First("index", "id", "acl_token_bootstrap")
+	if err != nil {
+		return false, 0, err
+	}
+
+	// No entry, we haven't bootstrapped yet
+	if out == nil {
 		return true, 0, nil
 	}
 
@@ -5251,6 +6528,96 @@ func (s *StateStore) SchedulerSetConfig(index uint64, config *structs.SchedulerC
 	return nil
 }
 
+// FreezeWindows is used to get the current change-freeze windows.
+func (s *StateStore) FreezeWindows() (uint64, *structs.FreezeWindows, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	// Get the freeze windows
+	w, err := tx.First("freeze_windows", "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed freeze windows lookup: %s", err)
+	}
+
+	windows, ok := w.(*structs.FreezeWindows)
+	if !ok {
+		return 0, nil, nil
+	}
+
+	return windows.ModifyIndex, windows, nil
+}
+
+// FreezeWindowsSet is used to replace the change-freeze windows.
+func (s *StateStore) FreezeWindowsSet(index uint64, windows *structs.FreezeWindows) error {
+	tx := s.db.WriteTxn(index)
+	defer tx.Abort()
+
+	// Check for existing windows to preserve the create index
+	existing, err := tx.First("freeze_windows", "id")
+	if err != nil {
+		return fmt.Errorf("failed freeze windows lookup: %s", err)
+	}
+
+	if existing != nil {
+		windows.CreateIndex = existing.(*structs.FreezeWindows).CreateIndex
+	} else {
+		windows.CreateIndex = index
+	}
+	windows.ModifyIndex = index
+
+	if err := tx.Insert("freeze_windows", windows); err != nil {
+		return fmt.Errorf("failed updating freeze windows: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// FeatureFlags is used to get the current cluster-wide feature flags.
+func (s *StateStore) FeatureFlags() (uint64, *structs.FeatureFlags, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	// Get the feature flags
+	f, err := tx.First("feature_flags", "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed feature flags lookup: %s", err)
+	}
+
+	flags, ok := f.(*structs.FeatureFlags)
+	if !ok {
+		return 0, nil, nil
+	}
+
+	return flags.ModifyIndex, flags, nil
+}
+
+// FeatureFlagsSet is used to set the cluster-wide feature flags.
+func (s *StateStore) FeatureFlagsSet(index uint64, flags *structs.FeatureFlags) error {
+	tx := s.db.WriteTxn(index)
+	defer tx.Abort()
+
+	// Check for existing flags to preserve the create index
+	existing, err := tx.First("feature_flags", "id")
+	if err != nil {
+		return fmt.Errorf("failed feature flags lookup: %s", err)
+	}
+
+	if existing != nil {
+		flags.CreateIndex = existing.(*structs.FeatureFlags).CreateIndex
+	} else {
+		flags.CreateIndex = index
+	}
+	flags.ModifyIndex = index
+
+	if err := tx.Insert("feature_flags", flags); err != nil {
+		return fmt.Errorf("failed updating feature flags: %s", err)
+	}
+
+	tx.Commit()
+	return nil
+}
+
 func (s *StateStore) ClusterMetadata(ws memdb.WatchSet) (*structs.ClusterMetadata, error) {
 	txn := s.db.ReadTxn()
 	defer txn.Abort()
@@ -5281,6 +6648,138 @@ func (s *StateStore) ClusterSetMetadata(index uint64, meta *structs.ClusterMetad
 	return nil
 }
 
+// License returns the stored cluster license, if any.
+func (s *StateStore) License(ws memdb.WatchSet) (*structs.StoredLicense, error) {
+	txn := s.db.ReadTxn()
+	defer txn.Abort()
+
+	// Get the stored license
+	watchCh, m, err := txn.FirstWatch("license", "id")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed license lookup")
+	}
+	ws.Add(watchCh)
+
+	if m != nil {
+		return m.(*structs.StoredLicense), nil
+	}
+
+	return nil, nil
+}
+
+// UpsertLicense is used to set the stored cluster license.
+func (s *StateStore) UpsertLicense(index uint64, license *structs.StoredLicense) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	// Check for an existing license to preserve the create index
+	existing, err := txn.First("license", "id")
+	if err != nil {
+		return fmt.Errorf("failed license lookup: %v", err)
+	}
+
+	if existing != nil {
+		license.CreateIndex = existing.(*structs.StoredLicense).CreateIndex
+	} else {
+		license.CreateIndex = index
+	}
+	license.ModifyIndex = index
+
+	if err := txn.Insert("license", license); err != nil {
+		return fmt.Errorf("failed updating license: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteLicense removes the stored cluster license. Deleting when no license
+// is stored is a no-op.
+func (s *StateStore) DeleteLicense(index uint64) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	existing, err := txn.First("license", "id")
+	if err != nil {
+		return fmt.Errorf("failed license lookup: %v", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := txn.Delete("license", existing); err != nil {
+		return fmt.Errorf("failed deleting license: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// InsertLicenseEvent appends an event to the license audit trail.
+func (s *StateStore) InsertLicenseEvent(index uint64, event *structs.LicenseEvent) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	event.CreateIndex = index
+	if err := txn.Insert("license_events", event); err != nil {
+		return fmt.Errorf("failed inserting license event: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"license_events", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// LicenseEvents returns an iterator over the license audit trail, ordered
+// oldest first.
+func (s *StateStore) LicenseEvents(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+	defer txn.Abort()
+
+	iter, err := txn.Get("license_events", "id")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed license events lookup")
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// InsertSentinelOverride records a policy override for auditing. Overrides
+// are append-only and keyed by the raft index recording them.
+func (s *StateStore) InsertSentinelOverride(index uint64, override *structs.SentinelOverride) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	override.CreateIndex = index
+	if err := txn.Insert("sentinel_overrides", override); err != nil {
+		return fmt.Errorf("failed inserting sentinel override: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"sentinel_overrides", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// SentinelOverrides returns an iterator over the recorded policy overrides,
+// ordered oldest first.
+func (s *StateStore) SentinelOverrides(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+	defer txn.Abort()
+
+	iter, err := txn.Get("sentinel_overrides", "id")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed sentinel overrides lookup")
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
 // WithWriteTransaction executes the passed function within a write transaction,
 // and returns its result.  If the invocation returns no error, the transaction
 // is committed; otherwise, it's aborted.
@@ -5742,6 +7241,14 @@ func (r *StateRestore) JobVersionRestore(version *structs.Job) error {
 	return nil
 }
 
+// JobSubmissionRestore is used to restore a job submission
+func (r *StateRestore) JobSubmissionRestore(sub *structs.JobSubmission) error {
+	if err := r.txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	return nil
+}
+
 // DeploymentRestore is used to restore a deployment
 func (r *StateRestore) DeploymentRestore(deployment *structs.Deployment) error {
 	if err := r.txn.Insert("deployment", deployment); err != nil {
@@ -5774,6 +7281,78 @@ func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 	return nil
 }
 
+// ACLRoleRestore is used to restore an ACL role
+func (r *StateRestore) ACLRoleRestore(role *structs.ACLRole) error {
+	if err := r.txn.Insert("acl_role", role); err != nil {
+		return fmt.Errorf("inserting acl role failed: %v", err)
+	}
+	return nil
+}
+
+// ACLAuthMethodRestore is used to restore an ACL auth method
+func (r *StateRestore) ACLAuthMethodRestore(method *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert("acl_auth_method", method); err != nil {
+		return fmt.Errorf("inserting acl auth method failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore an ACL binding rule
+func (r *StateRestore) ACLBindingRuleRestore(rule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert("acl_binding_rule", rule); err != nil {
+		return fmt.Errorf("inserting acl binding rule failed: %v", err)
+	}
+	return nil
+}
+
+// SigningKeyRestore is used to restore a workload identity signing key
+func (r *StateRestore) SigningKeyRestore(key *structs.SigningKey) error {
+	if err := r.txn.Insert("signing_key", key); err != nil {
+		return fmt.Errorf("inserting signing key failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationRestore is used to restore a service registration
+func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
+	if err := r.txn.Insert("service_registrations", service); err != nil {
+		return fmt.Errorf("inserting service registration failed: %v", err)
+	}
+	return nil
+}
+
+// VariableRestore is used to restore a variable
+func (r *StateRestore) VariableRestore(v *structs.EncryptedVariable) error {
+	if err := r.txn.Insert("variables", v); err != nil {
+		return fmt.Errorf("inserting variable failed: %v", err)
+	}
+	return nil
+}
+
+// SentinelPolicyRestore is used to restore a Sentinel policy
+func (r *StateRestore) SentinelPolicyRestore(policy *structs.SentinelPolicy) error {
+	if err := r.txn.Insert("sentinel_policy", policy); err != nil {
+		return fmt.Errorf("inserting sentinel policy failed: %v", err)
+	}
+	return nil
+}
+
+// SentinelModuleRestore is used to restore a Sentinel module
+func (r *StateRestore) SentinelModuleRestore(module *structs.SentinelModule) error {
+	if err := r.txn.Insert("sentinel_module", module); err != nil {
+		return fmt.Errorf("inserting sentinel module failed: %v", err)
+	}
+	return nil
+}
+
+// SentinelOverrideRestore is used to restore a recorded policy override
+func (r *StateRestore) SentinelOverrideRestore(override *structs.SentinelOverride) error {
+	if err := r.txn.Insert("sentinel_overrides", override); err != nil {
+		return fmt.Errorf("inserting sentinel override failed: %v", err)
+	}
+	return nil
+}
+
 // ACLTokenRestore is used to restore an ACL token
 func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
 	if err := r.txn.Insert("acl_token", token); err != nil {
@@ -5796,6 +7375,46 @@ func (r *StateRestore) ClusterMetadataRestore(meta *structs.ClusterMetadata) err
 	return nil
 }
 
+// FeatureFlagsRestore is used to restore the cluster-wide feature flags
+func (r *StateRestore) FeatureFlagsRestore(flags *structs.FeatureFlags) error {
+	if err := r.txn.Insert("feature_flags", flags); err != nil {
+		return fmt.Errorf("inserting feature flags failed: %v", err)
+	}
+	return nil
+}
+
+// FreezeWindowsRestore is used to restore the change-freeze windows
+func (r *StateRestore) FreezeWindowsRestore(windows *structs.FreezeWindows) error {
+	if err := r.txn.Insert("freeze_windows", windows); err != nil {
+		return fmt.Errorf("inserting freeze windows failed: %v", err)
+	}
+	return nil
+}
+
+// NamespaceRestore is used to restore a namespace
+func (r *StateRestore) NamespaceRestore(ns *structs.Namespace) error {
+	if err := r.txn.Insert(TableNamespaces, ns); err != nil {
+		return fmt.Errorf("namespace insert failed: %v", err)
+	}
+	return nil
+}
+
+// LicenseEventRestore is used to restore a license audit trail event
+func (r *StateRestore) LicenseEventRestore(event *structs.LicenseEvent) error {
+	if err := r.txn.Insert("license_events", event); err != nil {
+		return fmt.Errorf("inserting license event failed: %v", err)
+	}
+	return nil
+}
+
+// LicenseRestore is used to restore the stored cluster license
+func (r *StateRestore) LicenseRestore(license *structs.StoredLicense) error {
+	if err := r.txn.Insert("license", license); err != nil {
+		return fmt.Errorf("inserting license failed: %v", err)
+	}
+	return nil
+}
+
 // ScalingPolicyRestore is used to restore a scaling policy
 func (r *StateRestore) ScalingPolicyRestore(scalingPolicy *structs.ScalingPolicy) error {
 	if err := r.txn.Insert("scaling_policy", scalingPolicy); err != nil {
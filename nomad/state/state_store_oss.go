@@ -14,7 +14,12 @@ func (s *StateStore) enterpriseInit() error {
 
 // namespaceExists returns whether a namespace exists
 func (s *StateStore) namespaceExists(txn *txn, namespace string) (bool, error) {
-	return namespace == structs.DefaultNamespace, nil
+	existing, err := txn.First(TableNamespaces, "id", namespace)
+	if err != nil {
+		return false, err
+	}
+
+	return existing != nil, nil
 }
 
 // updateEntWithAlloc is used to update Nomad Enterprise objects when an allocation is
@@ -23,6 +28,22 @@ func (s *StateStore) updateEntWithAlloc(index uint64, new, existing *structs.All
 	return nil
 }
 
+// NamespaceNames returns the names of the registered namespaces
 func (s *StateStore) NamespaceNames() ([]string, error) {
-	return []string{structs.DefaultNamespace}, nil
+	it, err := s.Namespaces(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nses := []string{}
+	for {
+		next := it.Next()
+		if next == nil {
+			break
+		}
+		ns := next.(*structs.Namespace)
+		nses = append(nses, ns.Name)
+	}
+
+	return nses, nil
 }
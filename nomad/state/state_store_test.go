@@ -1165,6 +1165,49 @@ func TestStateStore_UpdateNodeDrain_ResetEligiblity(t *testing.T) {
 	require.False(watchFired(ws))
 }
 
+func TestStateStore_BatchUpdateNodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	node1 := mock.Node()
+	node2 := mock.Node()
+	require.NoError(state.UpsertNode(1000, node1))
+	require.NoError(state.UpsertNode(1001, node2))
+
+	// Create a watchset so we can test that the update fires the watch
+	ws := memdb.NewWatchSet()
+	if _, err := state.NodeByID(ws, node1.ID); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	meta := map[string]*string{
+		"rack":    helper.StringToPtr("r1"),
+		"pci-dss": nil,
+	}
+	require.NoError(state.BatchUpdateNodeMeta(1002, 7, []string{node1.ID, node2.ID}, meta))
+	require.True(watchFired(ws))
+
+	for _, nodeID := range []string{node1.ID, node2.ID} {
+		out, err := state.NodeByID(nil, nodeID)
+		require.NoError(err)
+		require.Equal("r1", out.Meta["rack"])
+		require.NotContains(out.Meta, "pci-dss")
+		require.Equal("mysql", out.Meta["database"])
+		require.EqualValues(1002, out.ModifyIndex)
+		require.EqualValues(7, out.StatusUpdatedAt)
+	}
+
+	index, err := state.Index("nodes")
+	require.NoError(err)
+	require.EqualValues(1002, index)
+
+	// Updating an unknown node is an error
+	err = state.BatchUpdateNodeMeta(1003, 8, []string{uuid.Generate()}, meta)
+	require.Error(err)
+	require.Contains(err.Error(), "node not found")
+}
+
 func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1985,6 +2028,51 @@ func TestStateStore_DeleteJob_MultipleVersions(t *testing.T) {
 	assert.False(watchFired(ws))
 }
 
+func TestStateStore_UpsertJobSubmission(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	require := require.New(t)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	// Store more submissions than the number of tracked versions
+	for i := 0; i <= structs.JobTrackedVersions+2; i++ {
+		sub := &structs.JobSubmission{
+			Source:         fmt.Sprintf("job %q { priority = %d }", job.ID, i),
+			Format:         "hcl2",
+			Namespace:      job.Namespace,
+			JobID:          job.ID,
+			Version:        uint64(i),
+			JobModifyIndex: uint64(1000 + i),
+		}
+		require.NoError(state.UpsertJobSubmission(uint64(1000+i), sub))
+	}
+
+	// Submissions for versions that are no longer tracked were pruned
+	ws := memdb.NewWatchSet()
+	out, err := state.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The most recent submission is retrievable
+	latest := uint64(structs.JobTrackedVersions + 2)
+	out, err = state.JobSubmission(ws, job.Namespace, job.ID, latest)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Contains(out.Source, job.ID)
+	require.Equal("hcl2", out.Format)
+
+	// Deleting the job removes all of its submissions
+	require.NoError(state.DeleteJob(2000, job.Namespace, job.ID))
+	require.True(watchFired(ws))
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, latest)
+	require.NoError(err)
+	require.Nil(out)
+}
+
 func TestStateStore_DeleteJob_ChildJob(t *testing.T) {
 	t.Parallel()
 
@@ -5174,6 +5262,42 @@ func TestStateStore_UpdateAlloc_NoJob(t *testing.T) {
 	}
 }
 
+func TestStateStore_SetAllocCheckpointMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+
+	require.Nil(state.UpsertJob(999, alloc.Job))
+	require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	meta := map[string]string{"offset": "1234"}
+	require.Nil(state.SetAllocCheckpointMeta(1001, alloc.ID, meta))
+
+	ws := memdb.NewWatchSet()
+	out, err := state.AllocByID(ws, alloc.ID)
+	require.Nil(err)
+	require.Equal(meta, out.CheckpointMeta)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	index, err := state.Index("allocs")
+	require.Nil(err)
+	require.EqualValues(1001, index)
+
+	// Replacing the metadata drops the old values
+	meta2 := map[string]string{"offset": "5678"}
+	require.Nil(state.SetAllocCheckpointMeta(1002, alloc.ID, meta2))
+
+	out, err = state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.Equal(meta2, out.CheckpointMeta)
+
+	// Setting metadata on a bogus alloc id errors
+	require.Error(state.SetAllocCheckpointMeta(1003, uuid.Generate(), meta))
+}
+
 func TestStateStore_UpdateAllocDesiredTransition(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -7913,6 +8037,269 @@ func TestStateStore_RestoreSITokenAccessor(t *testing.T) {
 	r.False(wsFired)
 }
 
+func TestStateStore_UpsertNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns1.Name)
+	require.Nil(err)
+	_, err = state.NamespaceByName(ws, ns2.Name)
+	require.Nil(err)
+
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.NamespaceByName(ws, ns1.Name)
+	require.Nil(err)
+	require.Equal(ns1, out)
+
+	out, err = state.NamespaceByName(ws, ns2.Name)
+	require.Nil(err)
+	require.Equal(ns2, out)
+
+	index, err := state.Index(TableNamespaces)
+	require.Nil(err)
+	require.EqualValues(1000, index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_DeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns1.Name)
+	require.Nil(err)
+
+	require.Nil(state.DeleteNamespaces(1001, []string{ns1.Name, ns2.Name}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.NamespaceByName(ws, ns1.Name)
+	require.Nil(err)
+	require.Nil(out)
+
+	out, err = state.NamespaceByName(ws, ns2.Name)
+	require.Nil(err)
+	require.Nil(out)
+
+	index, err := state.Index(TableNamespaces)
+	require.Nil(err)
+	require.EqualValues(1001, index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_DeleteNamespaces_Default(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.Name = structs.DefaultNamespace
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	err := state.DeleteNamespaces(1002, []string{ns.Name})
+	require.NotNil(err)
+	require.Contains(err.Error(), "can not be deleted")
+}
+
+func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	ns := mock.Namespace()
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.Nil(state.UpsertJob(1001, job))
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns.Name)
+	require.Nil(err)
+
+	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	require.NotNil(err)
+	require.Contains(err.Error(), "one non-terminal")
+	require.False(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.NamespaceByName(ws, ns.Name)
+	require.Nil(err)
+	require.NotNil(out)
+
+	index, err := state.Index(TableNamespaces)
+	require.Nil(err)
+	require.EqualValues(1000, index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_Namespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	var namespaces []*structs.Namespace
+
+	for i := 0; i < 10; i++ {
+		ns := mock.Namespace()
+		namespaces = append(namespaces, ns)
+	}
+
+	require.Nil(state.UpsertNamespaces(1000, namespaces))
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	iter, err := state.Namespaces(ws)
+	require.Nil(err)
+
+	var out []*structs.Namespace
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		ns := raw.(*structs.Namespace)
+		if ns.Name == structs.DefaultNamespace {
+			continue
+		}
+		out = append(out, ns)
+	}
+
+	namespaceSort(namespaces)
+	namespaceSort(out)
+	require.Equal(namespaces, out)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_NamespaceNames(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	var namespaces []*structs.Namespace
+	expectedNames := []string{structs.DefaultNamespace}
+
+	for i := 0; i < 10; i++ {
+		ns := mock.Namespace()
+		namespaces = append(namespaces, ns)
+		expectedNames = append(expectedNames, ns.Name)
+	}
+
+	err := state.UpsertNamespaces(1000, namespaces)
+	require.NoError(err)
+
+	found, err := state.NamespaceNames()
+	require.NoError(err)
+
+	sort.Strings(expectedNames)
+	sort.Strings(found)
+
+	require.Equal(expectedNames, found)
+}
+
+func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ns := mock.Namespace()
+
+	ns.Name = "foobar"
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	iter, err := state.NamespacesByNamePrefix(ws, ns.Name)
+	require.Nil(err)
+
+	gatherNamespaces := func(iter memdb.ResultIterator) []*structs.Namespace {
+		var namespaces []*structs.Namespace
+		for {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			ns := raw.(*structs.Namespace)
+			namespaces = append(namespaces, ns)
+		}
+		return namespaces
+	}
+
+	namespaces := gatherNamespaces(iter)
+	require.Len(namespaces, 1)
+	require.False(watchFired(ws))
+
+	iter, err = state.NamespacesByNamePrefix(ws, "foo")
+	require.Nil(err)
+
+	namespaces = gatherNamespaces(iter)
+	require.Len(namespaces, 1)
+
+	ns = mock.Namespace()
+	ns.Name = "foozip"
+	err = state.UpsertNamespaces(1001, []*structs.Namespace{ns})
+	require.Nil(err)
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	iter, err = state.NamespacesByNamePrefix(ws, "foo")
+	require.Nil(err)
+
+	namespaces = gatherNamespaces(iter)
+	require.Len(namespaces, 2)
+
+	iter, err = state.NamespacesByNamePrefix(ws, "foob")
+	require.Nil(err)
+
+	namespaces = gatherNamespaces(iter)
+	require.Len(namespaces, 1)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_RestoreNamespace(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	ns := mock.Namespace()
+
+	restore, err := state.Restore()
+	require.Nil(err)
+
+	require.Nil(restore.NamespaceRestore(ns))
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.NamespaceByName(ws, ns.Name)
+	require.Nil(err)
+	require.Equal(out, ns)
+}
+
+// namespaceSort is used to sort namespaces by name
+func namespaceSort(namespaces []*structs.Namespace) {
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+}
+
 func TestStateStore_UpsertACLPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -8094,41 +8481,44 @@ func TestStateStore_ACLPolicyByNamePrefix(t *testing.T) {
 	assert.Equal(t, expect, out)
 }
 
-func TestStateStore_BootstrapACLTokens(t *testing.T) {
+func TestStateStore_UpsertACLRole(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	tk1 := mock.ACLToken()
-	tk2 := mock.ACLToken()
-
-	ok, resetIdx, err := state.CanBootstrapACLToken()
-	assert.Nil(t, err)
-	assert.Equal(t, true, ok)
-	assert.EqualValues(t, 0, resetIdx)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
 
-	if err := state.BootstrapACLTokens(1000, 0, tk1); err != nil {
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLRoleByName(ws, role.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := state.ACLRoleByName(ws, role2.Name); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	out, err := state.ACLTokenByAccessorID(nil, tk1.AccessorID)
-	assert.Equal(t, nil, err)
-	assert.Equal(t, tk1, out)
+	if err := state.UpsertACLRoles(1000,
+		[]*structs.ACLRole{role, role2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-	ok, resetIdx, err = state.CanBootstrapACLToken()
-	assert.Nil(t, err)
-	assert.Equal(t, false, ok)
-	assert.EqualValues(t, 1000, resetIdx)
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, role, out)
 
-	if err := state.BootstrapACLTokens(1001, 0, tk2); err == nil {
-		t.Fatalf("expected error")
-	}
+	out, err = state.ACLRoleByName(ws, role2.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, role2, out)
 
-	iter, err := state.ACLTokens(nil)
+	iter, err := state.ACLRoles(ws)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Ensure we see both policies
+	// Ensure we see both roles
 	count := 0
 	for {
 		raw := iter.Next()
@@ -8137,64 +8527,128 @@ func TestStateStore_BootstrapACLTokens(t *testing.T) {
 		}
 		count++
 	}
-	if count != 1 {
+	if count != 2 {
 		t.Fatalf("bad: %d", count)
 	}
 
-	index, err := state.Index("acl_token")
+	index, err := state.Index("acl_role")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	if index != 1000 {
 		t.Fatalf("bad: %d", index)
 	}
-	index, err = state.Index("acl_token_bootstrap")
-	if err != nil {
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_DeleteACLRole(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	role := mock.ACLRole()
+
+	if err := state.UpsertACLRoles(1000, []*structs.ACLRole{role}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if index != 1000 {
-		t.Fatalf("bad: %d", index)
+
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLRoleByName(ws, role.Name); err != nil {
+		t.Fatalf("err: %v", err)
 	}
 
-	// Should allow bootstrap with reset index
-	if err := state.BootstrapACLTokens(1001, 1000, tk2); err != nil {
-		t.Fatalf("err %v", err)
+	if err := state.DeleteACLRoles(1001, []string{role.Name}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
 	}
 
-	// Check we've modified the index
-	index, err = state.Index("acl_token")
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, out)
+
+	index, err := state.Index("acl_role")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	if index != 1001 {
 		t.Fatalf("bad: %d", index)
 	}
-	index, err = state.Index("acl_token_bootstrap")
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_ACLRoleByNamePrefix(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	names := []string{
+		"foobar",
+		"foobaz",
+		"foozip",
+		"zip",
+	}
+
+	// Create the roles
+	var baseIndex uint64 = 1000
+	for _, name := range names {
+		role := mock.ACLRole()
+		role.Name = name
+		if err := state.UpsertACLRoles(baseIndex, []*structs.ACLRole{role}); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		baseIndex++
+	}
+
+	// Scan by prefix
+	iter, err := state.ACLRoleByNamePrefix(nil, "foo")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if index != 1001 {
-		t.Fatalf("bad: %d", index)
+
+	// Ensure we see both roles
+	count := 0
+	out := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+		out = append(out, raw.(*structs.ACLRole).Name)
+	}
+	if count != 3 {
+		t.Fatalf("bad: %d %v", count, out)
 	}
+	sort.Strings(out)
+
+	expect := []string{"foobar", "foobaz", "foozip"}
+	assert.Equal(t, expect, out)
 }
 
-func TestStateStore_UpsertACLTokens(t *testing.T) {
+func TestStateStore_UpsertACLAuthMethod(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	tk1 := mock.ACLToken()
-	tk2 := mock.ACLToken()
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
 
 	ws := memdb.NewWatchSet()
-	if _, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID); err != nil {
+	if _, err := state.ACLAuthMethodByName(ws, method.Name); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if _, err := state.ACLTokenByAccessorID(ws, tk2.AccessorID); err != nil {
+	if _, err := state.ACLAuthMethodByName(ws, method2.Name); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	if err := state.UpsertACLTokens(1000,
-		[]*structs.ACLToken{tk1, tk2}); err != nil {
+	if err := state.UpsertACLAuthMethods(1000,
+		[]*structs.ACLAuthMethod{method, method2}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 	if !watchFired(ws) {
@@ -8202,28 +8656,20 @@ func TestStateStore_UpsertACLTokens(t *testing.T) {
 	}
 
 	ws = memdb.NewWatchSet()
-	out, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID)
-	assert.Equal(t, nil, err)
-	assert.Equal(t, tk1, out)
-
-	out, err = state.ACLTokenByAccessorID(ws, tk2.AccessorID)
-	assert.Equal(t, nil, err)
-	assert.Equal(t, tk2, out)
-
-	out, err = state.ACLTokenBySecretID(ws, tk1.SecretID)
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
 	assert.Equal(t, nil, err)
-	assert.Equal(t, tk1, out)
+	assert.Equal(t, method, out)
 
-	out, err = state.ACLTokenBySecretID(ws, tk2.SecretID)
+	out, err = state.ACLAuthMethodByName(ws, method2.Name)
 	assert.Equal(t, nil, err)
-	assert.Equal(t, tk2, out)
+	assert.Equal(t, method2, out)
 
-	iter, err := state.ACLTokens(ws)
+	iter, err := state.ACLAuthMethods(ws)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Ensure we see both policies
+	// Ensure we see both auth methods
 	count := 0
 	for {
 		raw := iter.Next()
@@ -8236,7 +8682,7 @@ func TestStateStore_UpsertACLTokens(t *testing.T) {
 		t.Fatalf("bad: %d", count)
 	}
 
-	index, err := state.Index("acl_token")
+	index, err := state.Index("acl_auth_method")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8249,63 +8695,34 @@ func TestStateStore_UpsertACLTokens(t *testing.T) {
 	}
 }
 
-func TestStateStore_DeleteACLTokens(t *testing.T) {
+func TestStateStore_DeleteACLAuthMethod(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	tk1 := mock.ACLToken()
-	tk2 := mock.ACLToken()
+	method := mock.ACLAuthMethod()
 
-	// Create the tokens
-	if err := state.UpsertACLTokens(1000,
-		[]*structs.ACLToken{tk1, tk2}); err != nil {
+	if err := state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Create a watcher
 	ws := memdb.NewWatchSet()
-	if _, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID); err != nil {
+	if _, err := state.ACLAuthMethodByName(ws, method.Name); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Delete the token
-	if err := state.DeleteACLTokens(1001,
-		[]string{tk1.AccessorID, tk2.AccessorID}); err != nil {
+	if err := state.DeleteACLAuthMethods(1001, []string{method.Name}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-
-	// Ensure watching triggered
 	if !watchFired(ws) {
 		t.Fatalf("bad")
 	}
 
-	// Ensure we don't get the object back
 	ws = memdb.NewWatchSet()
-	out, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID)
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
 	assert.Equal(t, nil, err)
-	if out != nil {
-		t.Fatalf("bad: %#v", out)
-	}
-
-	iter, err := state.ACLTokens(ws)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-
-	// Ensure we see both policies
-	count := 0
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		count++
-	}
-	if count != 0 {
-		t.Fatalf("bad: %d", count)
-	}
+	assert.Nil(t, out)
 
-	index, err := state.Index("acl_token")
+	index, err := state.Index("acl_auth_method")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8318,101 +8735,70 @@ func TestStateStore_DeleteACLTokens(t *testing.T) {
 	}
 }
 
-func TestStateStore_ACLTokenByAccessorIDPrefix(t *testing.T) {
+func TestStateStore_DefaultACLAuthMethod(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	prefixes := []string{
-		"aaaa",
-		"aabb",
-		"bbbb",
-		"bbcc",
-		"ffff",
-	}
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+	method2.Default = true
+	method2.SetHash()
 
-	// Create the tokens
-	var baseIndex uint64 = 1000
-	for _, prefix := range prefixes {
-		tk := mock.ACLToken()
-		tk.AccessorID = prefix + tk.AccessorID[4:]
-		if err := state.UpsertACLTokens(baseIndex, []*structs.ACLToken{tk}); err != nil {
-			t.Fatalf("err: %v", err)
-		}
-		baseIndex++
-	}
-
-	// Scan by prefix
-	iter, err := state.ACLTokenByAccessorIDPrefix(nil, "aa")
-	if err != nil {
+	if err := state.UpsertACLAuthMethods(1000,
+		[]*structs.ACLAuthMethod{method, method2}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Ensure we see both tokens
-	count := 0
-	out := []string{}
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		count++
-		out = append(out, raw.(*structs.ACLToken).AccessorID[:4])
-	}
-	if count != 2 {
-		t.Fatalf("bad: %d %v", count, out)
+	out, err := state.DefaultACLAuthMethod(nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, method2, out)
+
+	// Removing the default method leaves none
+	if err := state.DeleteACLAuthMethods(1001, []string{method2.Name}); err != nil {
+		t.Fatalf("err: %v", err)
 	}
-	sort.Strings(out)
 
-	expect := []string{"aaaa", "aabb"}
-	assert.Equal(t, expect, out)
+	out, err = state.DefaultACLAuthMethod(nil)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, out)
 }
 
-func TestStateStore_RestoreACLPolicy(t *testing.T) {
+func TestStateStore_UpsertACLBindingRule(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	policy := mock.ACLPolicy()
+	rule := mock.ACLBindingRule()
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = "other-auth-method"
+	rule2.SetHash()
 
-	restore, err := state.Restore()
-	if err != nil {
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLBindingRuleByID(ws, rule.ID); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	err = restore.ACLPolicyRestore(policy)
-	if err != nil {
+	if err := state.UpsertACLBindingRules(1000,
+		[]*structs.ACLBindingRule{rule, rule2}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	restore.Commit()
-
-	ws := memdb.NewWatchSet()
-	out, err := state.ACLPolicyByName(ws, policy.Name)
-	if err != nil {
-		t.Fatalf("err: %v", err)
+	if !watchFired(ws) {
+		t.Fatalf("bad")
 	}
-	assert.Equal(t, policy, out)
-}
-
-func TestStateStore_ACLTokensByGlobal(t *testing.T) {
-	t.Parallel()
 
-	state := testStateStore(t)
-	tk1 := mock.ACLToken()
-	tk2 := mock.ACLToken()
-	tk3 := mock.ACLToken()
-	tk4 := mock.ACLToken()
-	tk3.Global = true
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, rule, out)
 
-	if err := state.UpsertACLTokens(1000,
-		[]*structs.ACLToken{tk1, tk2, tk3, tk4}); err != nil {
-		t.Fatalf("err: %v", err)
-	}
+	out, err = state.ACLBindingRuleByID(ws, rule2.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, rule2, out)
 
-	iter, err := state.ACLTokensByGlobal(nil, true)
+	// Lookup by auth method only returns the matching rule
+	iter, err := state.ACLBindingRulesByAuthMethod(ws, rule.AuthMethod)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-
-	// Ensure we see the one global policies
 	count := 0
 	for {
 		raw := iter.Next()
@@ -8420,162 +8806,241 @@ func TestStateStore_ACLTokensByGlobal(t *testing.T) {
 			break
 		}
 		count++
+		assert.Equal(t, rule, raw.(*structs.ACLBindingRule))
 	}
 	if count != 1 {
 		t.Fatalf("bad: %d", count)
 	}
+
+	index, err := state.Index("acl_binding_rule")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
 }
 
-func TestStateStore_RestoreACLToken(t *testing.T) {
+func TestStateStore_DeleteACLBindingRule(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	token := mock.ACLToken()
+	rule := mock.ACLBindingRule()
 
-	restore, err := state.Restore()
-	if err != nil {
+	if err := state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	err = restore.ACLTokenRestore(token)
-	if err != nil {
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLBindingRuleByID(ws, rule.ID); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	restore.Commit()
 
-	ws := memdb.NewWatchSet()
-	out, err := state.ACLTokenByAccessorID(ws, token.AccessorID)
-	if err != nil {
+	if err := state.DeleteACLBindingRules(1001, []string{rule.ID}); err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	assert.Equal(t, token, out)
-}
-
-func TestStateStore_SchedulerConfig(t *testing.T) {
-	t.Parallel()
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-	state := testStateStore(t)
-	schedConfig := &structs.SchedulerConfiguration{
-		PreemptionConfig: structs.PreemptionConfig{
-			SystemSchedulerEnabled: false,
-		},
-		CreateIndex: 100,
-		ModifyIndex: 200,
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, out)
+
+	index, err := state.Index("acl_binding_rule")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
 	}
 
-	require := require.New(t)
-	restore, err := state.Restore()
-	require.Nil(err)
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
 
-	err = restore.SchedulerConfigRestore(schedConfig)
-	require.Nil(err)
+func TestStateStore_UpsertSigningKeys(t *testing.T) {
+	t.Parallel()
 
-	restore.Commit()
+	state := testStateStore(t)
+	key := mock.SigningKey()
+	key2 := mock.SigningKey()
+	key2.Active = false
 
-	modIndex, out, err := state.SchedulerConfig()
-	require.Nil(err)
-	require.Equal(schedConfig.ModifyIndex, modIndex)
+	ws := memdb.NewWatchSet()
+	if _, err := state.SigningKeyByID(ws, key.KeyID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	require.Equal(schedConfig, out)
-}
+	if err := state.UpsertSigningKeys(1000,
+		[]*structs.SigningKey{key, key2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-func TestStateStore_ClusterMetadata(t *testing.T) {
-	require := require.New(t)
+	ws = memdb.NewWatchSet()
+	out, err := state.SigningKeyByID(ws, key.KeyID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, key, out)
 
-	state := testStateStore(t)
-	clusterID := "12345678-1234-1234-1234-1234567890"
-	now := time.Now().UnixNano()
-	meta := &structs.ClusterMetadata{ClusterID: clusterID, CreateTime: now}
+	out, err = state.SigningKeyByID(ws, key2.KeyID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, key2, out)
 
-	err := state.ClusterSetMetadata(100, meta)
-	require.NoError(err)
+	// Only the active key is returned by ActiveSigningKey
+	active, err := state.ActiveSigningKey(nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, key, active)
 
-	result, err := state.ClusterMetadata(nil)
-	require.NoError(err)
-	require.Equal(clusterID, result.ClusterID)
-	require.Equal(now, result.CreateTime)
+	index, err := state.Index("signing_key")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
 }
 
-func TestStateStore_ClusterMetadataRestore(t *testing.T) {
-	require := require.New(t)
+func TestStateStore_BootstrapACLTokens(t *testing.T) {
+	t.Parallel()
 
 	state := testStateStore(t)
-	clusterID := "12345678-1234-1234-1234-1234567890"
-	now := time.Now().UnixNano()
-	meta := &structs.ClusterMetadata{ClusterID: clusterID, CreateTime: now}
+	tk1 := mock.ACLToken()
+	tk2 := mock.ACLToken()
 
-	restore, err := state.Restore()
-	require.NoError(err)
+	ok, resetIdx, err := state.CanBootstrapACLToken()
+	assert.Nil(t, err)
+	assert.Equal(t, true, ok)
+	assert.EqualValues(t, 0, resetIdx)
 
-	err = restore.ClusterMetadataRestore(meta)
-	require.NoError(err)
+	if err := state.BootstrapACLTokens(1000, 0, tk1); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	restore.Commit()
+	out, err := state.ACLTokenByAccessorID(nil, tk1.AccessorID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tk1, out)
 
-	out, err := state.ClusterMetadata(nil)
-	require.NoError(err)
-	require.Equal(clusterID, out.ClusterID)
-	require.Equal(now, out.CreateTime)
-}
+	ok, resetIdx, err = state.CanBootstrapACLToken()
+	assert.Nil(t, err)
+	assert.Equal(t, false, ok)
+	assert.EqualValues(t, 1000, resetIdx)
 
-func TestStateStore_RestoreScalingPolicy(t *testing.T) {
-	t.Parallel()
-	require := require.New(t)
+	if err := state.BootstrapACLTokens(1001, 0, tk2); err == nil {
+		t.Fatalf("expected error")
+	}
 
-	state := testStateStore(t)
-	scalingPolicy := mock.ScalingPolicy()
+	iter, err := state.ACLTokens(nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	restore, err := state.Restore()
-	require.NoError(err)
+	// Ensure we see both policies
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("bad: %d", count)
+	}
 
-	err = restore.ScalingPolicyRestore(scalingPolicy)
-	require.NoError(err)
-	restore.Commit()
+	index, err := state.Index("acl_token")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+	index, err = state.Index("acl_token_bootstrap")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
 
-	ws := memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByID(ws, scalingPolicy.ID)
-	require.NoError(err)
-	require.EqualValues(out, scalingPolicy)
+	// Should allow bootstrap with reset index
+	if err := state.BootstrapACLTokens(1001, 1000, tk2); err != nil {
+		t.Fatalf("err %v", err)
+	}
+
+	// Check we've modified the index
+	index, err = state.Index("acl_token")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
+	index, err = state.Index("acl_token_bootstrap")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
 }
 
-func TestStateStore_UpsertScalingPolicy(t *testing.T) {
+func TestStateStore_UpsertACLTokens(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
 	state := testStateStore(t)
-	policy := mock.ScalingPolicy()
-	policy2 := mock.ScalingPolicy()
-
-	wsAll := memdb.NewWatchSet()
-	all, err := state.ScalingPolicies(wsAll)
-	require.NoError(err)
-	require.Nil(all.Next())
+	tk1 := mock.ACLToken()
+	tk2 := mock.ACLToken()
 
 	ws := memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
-	require.NoError(err)
-	require.Nil(out)
-
-	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
-	require.NoError(err)
-	require.Nil(out)
+	if _, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := state.ACLTokenByAccessorID(ws, tk2.AccessorID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
-	require.NoError(err)
-	require.True(watchFired(ws))
-	require.True(watchFired(wsAll))
+	if err := state.UpsertACLTokens(1000,
+		[]*structs.ACLToken{tk1, tk2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
 	ws = memdb.NewWatchSet()
-	out, err = state.ScalingPolicyByTarget(ws, policy.Target)
-	require.NoError(err)
-	require.Equal(policy, out)
+	out, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tk1, out)
 
-	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
-	require.NoError(err)
-	require.Equal(policy2, out)
+	out, err = state.ACLTokenByAccessorID(ws, tk2.AccessorID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tk2, out)
 
-	iter, err := state.ScalingPolicies(ws)
-	require.NoError(err)
+	out, err = state.ACLTokenBySecretID(ws, tk1.SecretID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tk1, out)
+
+	out, err = state.ACLTokenBySecretID(ws, tk2.SecretID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tk2, out)
+
+	iter, err := state.ACLTokens(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
 	// Ensure we see both policies
 	count := 0
@@ -8586,947 +9051,2117 @@ func TestStateStore_UpsertScalingPolicy(t *testing.T) {
 		}
 		count++
 	}
-	require.Equal(2, count)
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
 
-	index, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.True(1000 == index)
-	require.False(watchFired(ws))
+	index, err := state.Index("acl_token")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
 }
 
-func TestStateStore_UpsertScalingPolicy_Namespace(t *testing.T) {
+func TestStateStore_DeleteACLTokens(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
-	otherNamespace := "not-default-namespace"
 	state := testStateStore(t)
-	policy := mock.ScalingPolicy()
-	policy2 := mock.ScalingPolicy()
-	policy2.Target[structs.ScalingTargetNamespace] = otherNamespace
+	tk1 := mock.ACLToken()
+	tk2 := mock.ACLToken()
 
-	ws1 := memdb.NewWatchSet()
-	iter, err := state.ScalingPoliciesByNamespace(ws1, structs.DefaultNamespace)
-	require.NoError(err)
-	require.Nil(iter.Next())
+	// Create the tokens
+	if err := state.UpsertACLTokens(1000,
+		[]*structs.ACLToken{tk1, tk2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	ws2 := memdb.NewWatchSet()
-	iter, err = state.ScalingPoliciesByNamespace(ws2, otherNamespace)
-	require.NoError(err)
-	require.Nil(iter.Next())
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
-	require.NoError(err)
-	require.True(watchFired(ws1))
-	require.True(watchFired(ws2))
+	// Delete the token
+	if err := state.DeleteACLTokens(1001,
+		[]string{tk1.AccessorID, tk2.AccessorID}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, structs.DefaultNamespace)
-	require.NoError(err)
-	policiesInDefaultNamespace := []string{}
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		policiesInDefaultNamespace = append(policiesInDefaultNamespace, raw.(*structs.ScalingPolicy).ID)
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
 	}
-	require.ElementsMatch([]string{policy.ID}, policiesInDefaultNamespace)
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, otherNamespace)
-	require.NoError(err)
-	policiesInOtherNamespace := []string{}
+	// Ensure we don't get the object back
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLTokenByAccessorID(ws, tk1.AccessorID)
+	assert.Equal(t, nil, err)
+	if out != nil {
+		t.Fatalf("bad: %#v", out)
+	}
+
+	iter, err := state.ACLTokens(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see both policies
+	count := 0
 	for {
 		raw := iter.Next()
 		if raw == nil {
 			break
 		}
-		policiesInOtherNamespace = append(policiesInOtherNamespace, raw.(*structs.ScalingPolicy).ID)
+		count++
+	}
+	if count != 0 {
+		t.Fatalf("bad: %d", count)
+	}
+
+	index, err := state.Index("acl_token")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
 	}
-	require.ElementsMatch([]string{policy2.ID}, policiesInOtherNamespace)
 }
 
-func TestStateStore_UpsertScalingPolicy_Namespace_PrefixBug(t *testing.T) {
+func TestStateStore_ACLTokenByAccessorIDPrefix(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
-	ns1 := "name"
-	ns2 := "name2" // matches prefix "name"
 	state := testStateStore(t)
-	policy1 := mock.ScalingPolicy()
-	policy1.Target[structs.ScalingTargetNamespace] = ns1
-	policy2 := mock.ScalingPolicy()
-	policy2.Target[structs.ScalingTargetNamespace] = ns2
-
-	ws1 := memdb.NewWatchSet()
-	iter, err := state.ScalingPoliciesByNamespace(ws1, ns1)
-	require.NoError(err)
-	require.Nil(iter.Next())
-
-	ws2 := memdb.NewWatchSet()
-	iter, err = state.ScalingPoliciesByNamespace(ws2, ns2)
-	require.NoError(err)
-	require.Nil(iter.Next())
-
-	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy1, policy2})
-	require.NoError(err)
-	require.True(watchFired(ws1))
-	require.True(watchFired(ws2))
+	prefixes := []string{
+		"aaaa",
+		"aabb",
+		"bbbb",
+		"bbcc",
+		"ffff",
+	}
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, ns1)
-	require.NoError(err)
-	policiesInNS1 := []string{}
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
+	// Create the tokens
+	var baseIndex uint64 = 1000
+	for _, prefix := range prefixes {
+		tk := mock.ACLToken()
+		tk.AccessorID = prefix + tk.AccessorID[4:]
+		if err := state.UpsertACLTokens(baseIndex, []*structs.ACLToken{tk}); err != nil {
+			t.Fatalf("err: %v", err)
 		}
-		policiesInNS1 = append(policiesInNS1, raw.(*structs.ScalingPolicy).ID)
+		baseIndex++
 	}
-	require.ElementsMatch([]string{policy1.ID}, policiesInNS1)
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, ns2)
-	require.NoError(err)
-	policiesInNS2 := []string{}
+	// Scan by prefix
+	iter, err := state.ACLTokenByAccessorIDPrefix(nil, "aa")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see both tokens
+	count := 0
+	out := []string{}
 	for {
 		raw := iter.Next()
 		if raw == nil {
 			break
 		}
-		policiesInNS2 = append(policiesInNS2, raw.(*structs.ScalingPolicy).ID)
+		count++
+		out = append(out, raw.(*structs.ACLToken).AccessorID[:4])
 	}
-	require.ElementsMatch([]string{policy2.ID}, policiesInNS2)
+	if count != 2 {
+		t.Fatalf("bad: %d %v", count, out)
+	}
+	sort.Strings(out)
+
+	expect := []string{"aaaa", "aabb"}
+	assert.Equal(t, expect, out)
 }
 
-func TestStateStore_UpsertJob_UpsertScalingPolicies(t *testing.T) {
+func TestStateStore_RestoreACLPolicy(t *testing.T) {
 	t.Parallel()
 
-	require := require.New(t)
-
 	state := testStateStore(t)
-	job, policy := mock.JobWithScalingPolicy()
-
-	// Create a watchset so we can test that upsert fires the watch
-	ws := memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
-	require.NoError(err)
-	require.Nil(out)
+	policy := mock.ACLPolicy()
 
-	var newIndex uint64 = 1000
-	err = state.UpsertJob(newIndex, job)
-	require.NoError(err)
-	require.True(watchFired(ws), "watch did not fire")
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	ws = memdb.NewWatchSet()
-	out, err = state.ScalingPolicyByTarget(ws, policy.Target)
-	require.NoError(err)
-	require.NotNil(out)
-	require.Equal(newIndex, out.CreateIndex)
-	require.Equal(newIndex, out.ModifyIndex)
+	err = restore.ACLPolicyRestore(policy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
 
-	index, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.Equal(newIndex, index)
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLPolicyByName(ws, policy.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, policy, out)
 }
 
-// Scaling Policy IDs are generated randomly during Job.Register
-// Subsequent updates of the job should preserve the ID for the scaling policy
-// associated with a given target.
-func TestStateStore_UpsertJob_PreserveScalingPolicyIDsAndIndex(t *testing.T) {
+func TestStateStore_RestoreACLRole(t *testing.T) {
 	t.Parallel()
 
-	require := require.New(t)
-
 	state := testStateStore(t)
-	job, policy := mock.JobWithScalingPolicy()
+	role := mock.ACLRole()
 
-	var newIndex uint64 = 1000
-	err := state.UpsertJob(newIndex, job)
-	require.NoError(err)
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLRoleRestore(role)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
 
 	ws := memdb.NewWatchSet()
-	p1, err := state.ScalingPolicyByTarget(ws, policy.Target)
-	require.NoError(err)
-	require.NotNil(p1)
-	require.Equal(newIndex, p1.CreateIndex)
-	require.Equal(newIndex, p1.ModifyIndex)
+	out, err := state.ACLRoleByName(ws, role.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, role, out)
+}
 
-	index, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.Equal(newIndex, index)
-	require.NotEmpty(p1.ID)
+func TestStateStore_RestoreACLAuthMethod(t *testing.T) {
+	t.Parallel()
 
-	// update the job
-	job.Meta["new-meta"] = "new-value"
-	newIndex += 100
-	err = state.UpsertJob(newIndex, job)
-	require.NoError(err)
-	require.False(watchFired(ws), "watch should not have fired")
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
 
-	p2, err := state.ScalingPolicyByTarget(nil, policy.Target)
-	require.NoError(err)
-	require.NotNil(p2)
-	require.Equal(p1.ID, p2.ID, "ID should not have changed")
-	require.Equal(p1.CreateIndex, p2.CreateIndex)
-	require.Equal(p1.ModifyIndex, p2.ModifyIndex)
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	index, err = state.Index("scaling_policy")
-	require.NoError(err)
-	require.Equal(index, p1.CreateIndex, "table index should not have changed")
+	err = restore.ACLAuthMethodRestore(method)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, method, out)
 }
 
-// Updating the scaling policy for a job should update the index table and fire the watch.
-// This test is the converse of TestStateStore_UpsertJob_PreserveScalingPolicyIDsAndIndex
-func TestStateStore_UpsertJob_UpdateScalingPolicy(t *testing.T) {
+func TestStateStore_RestoreACLBindingRule(t *testing.T) {
 	t.Parallel()
 
-	require := require.New(t)
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLBindingRuleRestore(rule)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, rule, out)
+}
+
+func TestStateStore_RestoreSigningKey(t *testing.T) {
+	t.Parallel()
 
 	state := testStateStore(t)
-	job, policy := mock.JobWithScalingPolicy()
+	key := mock.SigningKey()
 
-	var oldIndex uint64 = 1000
-	require.NoError(state.UpsertJob(oldIndex, job))
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.SigningKeyRestore(key)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
 
 	ws := memdb.NewWatchSet()
-	p1, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	out, err := state.SigningKeyByID(ws, key.KeyID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, key, out)
+}
+
+func TestStateStore_ACLTokensByGlobal(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	tk1 := mock.ACLToken()
+	tk2 := mock.ACLToken()
+	tk3 := mock.ACLToken()
+	tk4 := mock.ACLToken()
+	tk3.Global = true
+
+	if err := state.UpsertACLTokens(1000,
+		[]*structs.ACLToken{tk1, tk2, tk3, tk4}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	iter, err := state.ACLTokensByGlobal(nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see the one global policies
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("bad: %d", count)
+	}
+}
+
+func TestStateStore_RestoreACLToken(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	token := mock.ACLToken()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	err = restore.ACLTokenRestore(token)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ACLTokenByAccessorID(ws, token.AccessorID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	assert.Equal(t, token, out)
+}
+
+func TestStateStore_SchedulerConfig(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	schedConfig := &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled: false,
+		},
+		CreateIndex: 100,
+		ModifyIndex: 200,
+	}
+
+	require := require.New(t)
+	restore, err := state.Restore()
+	require.Nil(err)
+
+	err = restore.SchedulerConfigRestore(schedConfig)
+	require.Nil(err)
+
+	restore.Commit()
+
+	modIndex, out, err := state.SchedulerConfig()
+	require.Nil(err)
+	require.Equal(schedConfig.ModifyIndex, modIndex)
+
+	require.Equal(schedConfig, out)
+}
+
+func TestStateStore_ClusterMetadata(t *testing.T) {
+	require := require.New(t)
+
+	state := testStateStore(t)
+	clusterID := "12345678-1234-1234-1234-1234567890"
+	now := time.Now().UnixNano()
+	meta := &structs.ClusterMetadata{ClusterID: clusterID, CreateTime: now}
+
+	err := state.ClusterSetMetadata(100, meta)
 	require.NoError(err)
-	require.NotNil(p1)
-	require.Equal(oldIndex, p1.CreateIndex)
-	require.Equal(oldIndex, p1.ModifyIndex)
-	prevId := p1.ID
 
-	index, err := state.Index("scaling_policy")
+	result, err := state.ClusterMetadata(nil)
 	require.NoError(err)
-	require.Equal(oldIndex, index)
-	require.NotEmpty(p1.ID)
+	require.Equal(clusterID, result.ClusterID)
+	require.Equal(now, result.CreateTime)
+}
 
-	// update the job with the updated scaling policy; make sure to use a different object
-	newPolicy := structs.CopyScalingPolicy(p1)
-	newPolicy.Policy["new-field"] = "new-value"
-	job.TaskGroups[0].Scaling = newPolicy
-	require.NoError(state.UpsertJob(oldIndex+100, job))
-	require.True(watchFired(ws), "watch should have fired")
+func TestStateStore_ClusterMetadataRestore(t *testing.T) {
+	require := require.New(t)
 
-	p2, err := state.ScalingPolicyByTarget(nil, policy.Target)
+	state := testStateStore(t)
+	clusterID := "12345678-1234-1234-1234-1234567890"
+	now := time.Now().UnixNano()
+	meta := &structs.ClusterMetadata{ClusterID: clusterID, CreateTime: now}
+
+	restore, err := state.Restore()
 	require.NoError(err)
-	require.NotNil(p2)
-	require.Equal(p2.Policy["new-field"], "new-value")
-	require.Equal(prevId, p2.ID, "ID should not have changed")
-	require.Equal(oldIndex, p2.CreateIndex)
-	require.Greater(p2.ModifyIndex, oldIndex, "ModifyIndex should have advanced")
 
-	index, err = state.Index("scaling_policy")
+	err = restore.ClusterMetadataRestore(meta)
 	require.NoError(err)
-	require.Greater(index, oldIndex, "table index should have advanced")
-}
 
-func TestStateStore_DeleteScalingPolicies(t *testing.T) {
-	t.Parallel()
+	restore.Commit()
+
+	out, err := state.ClusterMetadata(nil)
+	require.NoError(err)
+	require.Equal(clusterID, out.ClusterID)
+	require.Equal(now, out.CreateTime)
+}
 
+func TestStateStore_FeatureFlags(t *testing.T) {
 	require := require.New(t)
 
 	state := testStateStore(t)
-	policy := mock.ScalingPolicy()
-	policy2 := mock.ScalingPolicy()
 
-	// Create the policy
-	err := state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
+	// No flags stored yet
+	index, flags, err := state.FeatureFlags()
 	require.NoError(err)
+	require.Nil(flags)
+	require.Zero(index)
 
-	// Create a watcher
-	ws := memdb.NewWatchSet()
-	_, err = state.ScalingPolicyByTarget(ws, policy.Target)
+	err = state.FeatureFlagsSet(1000, &structs.FeatureFlags{
+		Flags: map[string]bool{"example": true},
+	})
 	require.NoError(err)
 
-	// Delete the policy
-	err = state.DeleteScalingPolicies(1001, []string{policy.ID, policy2.ID})
+	index, flags, err = state.FeatureFlags()
 	require.NoError(err)
+	require.EqualValues(1000, index)
+	require.True(flags.FeatureEnabled("example"))
+	require.False(flags.FeatureEnabled("unknown"))
+	require.EqualValues(1000, flags.CreateIndex)
 
-	// Ensure watching triggered
-	require.True(watchFired(ws))
+	// Updates preserve the create index
+	err = state.FeatureFlagsSet(1001, &structs.FeatureFlags{
+		Flags: map[string]bool{"example": false},
+	})
+	require.NoError(err)
 
-	// Ensure we don't get the objects back
-	ws = memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	_, flags, err = state.FeatureFlags()
 	require.NoError(err)
-	require.Nil(out)
+	require.False(flags.FeatureEnabled("example"))
+	require.EqualValues(1000, flags.CreateIndex)
+	require.EqualValues(1001, flags.ModifyIndex)
+}
 
-	ws = memdb.NewWatchSet()
-	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
+func TestStateStore_License(t *testing.T) {
+	require := require.New(t)
+
+	state := testStateStore(t)
+	license := &structs.StoredLicense{Signed: "raw-license-blob"}
+
+	err := state.UpsertLicense(1000, license)
 	require.NoError(err)
-	require.Nil(out)
 
-	// Ensure we see both policies
-	iter, err := state.ScalingPoliciesByNamespace(ws, policy.Target[structs.ScalingTargetNamespace])
+	result, err := state.License(nil)
 	require.NoError(err)
-	count := 0
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		count++
-	}
-	require.Equal(0, count)
+	require.Equal("raw-license-blob", result.Signed)
+	require.EqualValues(1000, result.CreateIndex)
+	require.EqualValues(1000, result.ModifyIndex)
 
-	index, err := state.Index("scaling_policy")
+	// Deleting removes the stored license
+	require.NoError(state.DeleteLicense(1001))
+
+	result, err = state.License(nil)
 	require.NoError(err)
-	require.True(1001 == index)
-	require.False(watchFired(ws))
-}
+	require.Nil(result)
 
-func TestStateStore_StopJob_DeleteScalingPolicies(t *testing.T) {
-	t.Parallel()
+	// Deleting when no license is stored is a no-op
+	require.NoError(state.DeleteLicense(1002))
+}
 
+func TestStateStore_LicenseRestore(t *testing.T) {
 	require := require.New(t)
 
 	state := testStateStore(t)
+	license := &structs.StoredLicense{Signed: "raw-license-blob"}
 
-	job := mock.Job()
+	restore, err := state.Restore()
+	require.NoError(err)
 
-	err := state.UpsertJob(1000, job)
+	err = restore.LicenseRestore(license)
 	require.NoError(err)
 
-	policy := mock.ScalingPolicy()
-	policy.Target[structs.ScalingTargetJob] = job.ID
-	err = state.UpsertScalingPolicies(1100, []*structs.ScalingPolicy{policy})
+	restore.Commit()
+
+	out, err := state.License(nil)
 	require.NoError(err)
+	require.Equal("raw-license-blob", out.Signed)
+}
 
-	// Ensure the scaling policy is present and start some watches
-	wsGet := memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByTarget(wsGet, policy.Target)
+func TestStateStore_RestoreScalingPolicy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	scalingPolicy := mock.ScalingPolicy()
+
+	restore, err := state.Restore()
 	require.NoError(err)
-	require.NotNil(out)
-	wsList := memdb.NewWatchSet()
-	_, err = state.ScalingPolicies(wsList)
+
+	err = restore.ScalingPolicyRestore(scalingPolicy)
+	require.NoError(err)
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByID(ws, scalingPolicy.ID)
 	require.NoError(err)
+	require.EqualValues(out, scalingPolicy)
+}
+
+func TestStateStore_UpsertScalingPolicy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	policy := mock.ScalingPolicy()
+	policy2 := mock.ScalingPolicy()
+
+	wsAll := memdb.NewWatchSet()
+	all, err := state.ScalingPolicies(wsAll)
+	require.NoError(err)
+	require.Nil(all.Next())
+
+	ws := memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
+	require.NoError(err)
+	require.Nil(out)
+
+	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
+	require.NoError(err)
+	require.True(watchFired(ws))
+	require.True(watchFired(wsAll))
+
+	ws = memdb.NewWatchSet()
+	out, err = state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.Equal(policy, out)
+
+	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
+	require.NoError(err)
+	require.Equal(policy2, out)
+
+	iter, err := state.ScalingPolicies(ws)
+	require.NoError(err)
+
+	// Ensure we see both policies
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	require.Equal(2, count)
+
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.True(1000 == index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_UpsertScalingPolicy_Namespace(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	otherNamespace := "not-default-namespace"
+	state := testStateStore(t)
+	policy := mock.ScalingPolicy()
+	policy2 := mock.ScalingPolicy()
+	policy2.Target[structs.ScalingTargetNamespace] = otherNamespace
+
+	ws1 := memdb.NewWatchSet()
+	iter, err := state.ScalingPoliciesByNamespace(ws1, structs.DefaultNamespace)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	ws2 := memdb.NewWatchSet()
+	iter, err = state.ScalingPoliciesByNamespace(ws2, otherNamespace)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
+	require.NoError(err)
+	require.True(watchFired(ws1))
+	require.True(watchFired(ws2))
+
+	iter, err = state.ScalingPoliciesByNamespace(nil, structs.DefaultNamespace)
+	require.NoError(err)
+	policiesInDefaultNamespace := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		policiesInDefaultNamespace = append(policiesInDefaultNamespace, raw.(*structs.ScalingPolicy).ID)
+	}
+	require.ElementsMatch([]string{policy.ID}, policiesInDefaultNamespace)
+
+	iter, err = state.ScalingPoliciesByNamespace(nil, otherNamespace)
+	require.NoError(err)
+	policiesInOtherNamespace := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		policiesInOtherNamespace = append(policiesInOtherNamespace, raw.(*structs.ScalingPolicy).ID)
+	}
+	require.ElementsMatch([]string{policy2.ID}, policiesInOtherNamespace)
+}
+
+func TestStateStore_UpsertScalingPolicy_Namespace_PrefixBug(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ns1 := "name"
+	ns2 := "name2" // matches prefix "name"
+	state := testStateStore(t)
+	policy1 := mock.ScalingPolicy()
+	policy1.Target[structs.ScalingTargetNamespace] = ns1
+	policy2 := mock.ScalingPolicy()
+	policy2.Target[structs.ScalingTargetNamespace] = ns2
+
+	ws1 := memdb.NewWatchSet()
+	iter, err := state.ScalingPoliciesByNamespace(ws1, ns1)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	ws2 := memdb.NewWatchSet()
+	iter, err = state.ScalingPoliciesByNamespace(ws2, ns2)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	err = state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy1, policy2})
+	require.NoError(err)
+	require.True(watchFired(ws1))
+	require.True(watchFired(ws2))
+
+	iter, err = state.ScalingPoliciesByNamespace(nil, ns1)
+	require.NoError(err)
+	policiesInNS1 := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		policiesInNS1 = append(policiesInNS1, raw.(*structs.ScalingPolicy).ID)
+	}
+	require.ElementsMatch([]string{policy1.ID}, policiesInNS1)
+
+	iter, err = state.ScalingPoliciesByNamespace(nil, ns2)
+	require.NoError(err)
+	policiesInNS2 := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		policiesInNS2 = append(policiesInNS2, raw.(*structs.ScalingPolicy).ID)
+	}
+	require.ElementsMatch([]string{policy2.ID}, policiesInNS2)
+}
+
+func TestStateStore_UpsertJob_UpsertScalingPolicies(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+	job, policy := mock.JobWithScalingPolicy()
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.Nil(out)
+
+	var newIndex uint64 = 1000
+	err = state.UpsertJob(newIndex, job)
+	require.NoError(err)
+	require.True(watchFired(ws), "watch did not fire")
+
+	ws = memdb.NewWatchSet()
+	out, err = state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(newIndex, out.CreateIndex)
+	require.Equal(newIndex, out.ModifyIndex)
+
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(newIndex, index)
+}
+
+// Scaling Policy IDs are generated randomly during Job.Register
+// Subsequent updates of the job should preserve the ID for the scaling policy
+// associated with a given target.
+func TestStateStore_UpsertJob_PreserveScalingPolicyIDsAndIndex(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+	job, policy := mock.JobWithScalingPolicy()
+
+	var newIndex uint64 = 1000
+	err := state.UpsertJob(newIndex, job)
+	require.NoError(err)
+
+	ws := memdb.NewWatchSet()
+	p1, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.NotNil(p1)
+	require.Equal(newIndex, p1.CreateIndex)
+	require.Equal(newIndex, p1.ModifyIndex)
+
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(newIndex, index)
+	require.NotEmpty(p1.ID)
+
+	// update the job
+	job.Meta["new-meta"] = "new-value"
+	newIndex += 100
+	err = state.UpsertJob(newIndex, job)
+	require.NoError(err)
+	require.False(watchFired(ws), "watch should not have fired")
+
+	p2, err := state.ScalingPolicyByTarget(nil, policy.Target)
+	require.NoError(err)
+	require.NotNil(p2)
+	require.Equal(p1.ID, p2.ID, "ID should not have changed")
+	require.Equal(p1.CreateIndex, p2.CreateIndex)
+	require.Equal(p1.ModifyIndex, p2.ModifyIndex)
+
+	index, err = state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(index, p1.CreateIndex, "table index should not have changed")
+}
+
+// Updating the scaling policy for a job should update the index table and fire the watch.
+// This test is the converse of TestStateStore_UpsertJob_PreserveScalingPolicyIDsAndIndex
+func TestStateStore_UpsertJob_UpdateScalingPolicy(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+	job, policy := mock.JobWithScalingPolicy()
+
+	var oldIndex uint64 = 1000
+	require.NoError(state.UpsertJob(oldIndex, job))
+
+	ws := memdb.NewWatchSet()
+	p1, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.NotNil(p1)
+	require.Equal(oldIndex, p1.CreateIndex)
+	require.Equal(oldIndex, p1.ModifyIndex)
+	prevId := p1.ID
+
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(oldIndex, index)
+	require.NotEmpty(p1.ID)
+
+	// update the job with the updated scaling policy; make sure to use a different object
+	newPolicy := structs.CopyScalingPolicy(p1)
+	newPolicy.Policy["new-field"] = "new-value"
+	job.TaskGroups[0].Scaling = newPolicy
+	require.NoError(state.UpsertJob(oldIndex+100, job))
+	require.True(watchFired(ws), "watch should have fired")
+
+	p2, err := state.ScalingPolicyByTarget(nil, policy.Target)
+	require.NoError(err)
+	require.NotNil(p2)
+	require.Equal(p2.Policy["new-field"], "new-value")
+	require.Equal(prevId, p2.ID, "ID should not have changed")
+	require.Equal(oldIndex, p2.CreateIndex)
+	require.Greater(p2.ModifyIndex, oldIndex, "ModifyIndex should have advanced")
+
+	index, err = state.Index("scaling_policy")
+	require.NoError(err)
+	require.Greater(index, oldIndex, "table index should have advanced")
+}
+
+func TestStateStore_DeleteScalingPolicies(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+	policy := mock.ScalingPolicy()
+	policy2 := mock.ScalingPolicy()
+
+	// Create the policy
+	err := state.UpsertScalingPolicies(1000, []*structs.ScalingPolicy{policy, policy2})
+	require.NoError(err)
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	_, err = state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+
+	// Delete the policy
+	err = state.DeleteScalingPolicies(1001, []string{policy.ID, policy2.ID})
+	require.NoError(err)
+
+	// Ensure watching triggered
+	require.True(watchFired(ws))
+
+	// Ensure we don't get the objects back
+	ws = memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.Nil(out)
+
+	ws = memdb.NewWatchSet()
+	out, err = state.ScalingPolicyByTarget(ws, policy2.Target)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Ensure we see both policies
+	iter, err := state.ScalingPoliciesByNamespace(ws, policy.Target[structs.ScalingTargetNamespace])
+	require.NoError(err)
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	require.Equal(0, count)
+
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.True(1001 == index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_StopJob_DeleteScalingPolicies(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+
+	err := state.UpsertJob(1000, job)
+	require.NoError(err)
+
+	policy := mock.ScalingPolicy()
+	policy.Target[structs.ScalingTargetJob] = job.ID
+	err = state.UpsertScalingPolicies(1100, []*structs.ScalingPolicy{policy})
+	require.NoError(err)
+
+	// Ensure the scaling policy is present and start some watches
+	wsGet := memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByTarget(wsGet, policy.Target)
+	require.NoError(err)
+	require.NotNil(out)
+	wsList := memdb.NewWatchSet()
+	_, err = state.ScalingPolicies(wsList)
+	require.NoError(err)
+
+	// Stop the job
+	job, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	job.Stop = true
+	err = state.UpsertJob(1200, job)
+	require.NoError(err)
+
+	// Ensure:
+	// * the scaling policy was deleted
+	// * the watches were fired
+	// * the table index was advanced
+	require.True(watchFired(wsGet))
+	require.True(watchFired(wsList))
+	out, err = state.ScalingPolicyByTarget(nil, policy.Target)
+	require.NoError(err)
+	require.Nil(out)
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.GreaterOrEqual(index, uint64(1200))
+}
+
+func TestStateStore_UnstopJob_UpsertScalingPolicies(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	job, policy := mock.JobWithScalingPolicy()
+	job.Stop = true
+
+	// establish watcher, verify there are no scaling policies yet
+	ws := memdb.NewWatchSet()
+	list, err := state.ScalingPolicies(ws)
+	require.NoError(err)
+	require.Nil(list.Next())
+
+	// upsert a stopped job, verify that we don't fire the watcher or add any scaling policies
+	err = state.UpsertJob(1000, job)
+	require.NoError(err)
+	require.False(watchFired(ws))
+	// stopped job should have no scaling policies, watcher doesn't fire
+	list, err = state.ScalingPolicies(ws)
+	require.NoError(err)
+	require.Nil(list.Next())
+
+	// Establish a new watcher
+	ws = memdb.NewWatchSet()
+	_, err = state.ScalingPolicies(ws)
+	require.NoError(err)
+	// Unstop this job, say you'll run it again...
+	job.Stop = false
+	err = state.UpsertJob(1100, job)
+	require.NoError(err)
+
+	// Ensure the scaling policy was added, watch was fired, index was advanced
+	require.True(watchFired(ws))
+	out, err := state.ScalingPolicyByTarget(nil, policy.Target)
+	require.NoError(err)
+	require.NotNil(out)
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.GreaterOrEqual(index, uint64(1100))
+}
+
+func TestStateStore_DeleteJob_DeleteScalingPolicies(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+
+	err := state.UpsertJob(1000, job)
+	require.NoError(err)
+
+	policy := mock.ScalingPolicy()
+	policy.Target[structs.ScalingTargetJob] = job.ID
+	err = state.UpsertScalingPolicies(1001, []*structs.ScalingPolicy{policy})
+	require.NoError(err)
+
+	// Delete the job
+	err = state.DeleteJob(1002, job.Namespace, job.ID)
+	require.NoError(err)
+
+	// Ensure the scaling policy was deleted
+	ws := memdb.NewWatchSet()
+	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	require.NoError(err)
+	require.Nil(out)
+	index, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.True(index > 1001)
+}
+
+func TestStateStore_DeleteJob_DeleteScalingPoliciesPrefixBug(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	job2 := job.Copy()
+	job2.ID = job.ID + "-but-longer"
+	require.NoError(state.UpsertJob(1001, job2))
+
+	policy := mock.ScalingPolicy()
+	policy.Target[structs.ScalingTargetJob] = job.ID
+	policy2 := mock.ScalingPolicy()
+	policy2.Target[structs.ScalingTargetJob] = job2.ID
+	require.NoError(state.UpsertScalingPolicies(1002, []*structs.ScalingPolicy{policy, policy2}))
+
+	// Delete job with the shorter prefix-ID
+	require.NoError(state.DeleteJob(1003, job.Namespace, job.ID))
+
+	// Ensure only the associated scaling policy was deleted, not the one matching the job with the longer ID
+	out, err := state.ScalingPolicyByID(nil, policy.ID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ScalingPolicyByID(nil, policy2.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+// This test ensures that deleting a job that doesn't have any scaling policies
+// will not cause the scaling_policy table index to increase, on either job
+// registration or deletion.
+func TestStateStore_DeleteJob_ScalingPolicyIndexNoop(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	job := mock.Job()
+
+	prevIndex, err := state.Index("scaling_policy")
+	require.NoError(err)
+
+	err = state.UpsertJob(1000, job)
+	require.NoError(err)
+
+	newIndex, err := state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(prevIndex, newIndex)
+
+	// Delete the job
+	err = state.DeleteJob(1002, job.Namespace, job.ID)
+	require.NoError(err)
+
+	newIndex, err = state.Index("scaling_policy")
+	require.NoError(err)
+	require.Equal(prevIndex, newIndex)
+}
+
+func TestStateStore_ScalingPoliciesByJob(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	state := testStateStore(t)
+	policyA := mock.ScalingPolicy()
+	policyB1 := mock.ScalingPolicy()
+	policyB2 := mock.ScalingPolicy()
+	policyB1.Target[structs.ScalingTargetJob] = policyB2.Target[structs.ScalingTargetJob]
+
+	// Create the policies
+	var baseIndex uint64 = 1000
+	err := state.UpsertScalingPolicies(baseIndex, []*structs.ScalingPolicy{policyA, policyB1, policyB2})
+	require.NoError(err)
+
+	iter, err := state.ScalingPoliciesByJob(nil,
+		policyA.Target[structs.ScalingTargetNamespace],
+		policyA.Target[structs.ScalingTargetJob])
+	require.NoError(err)
+
+	// Ensure we see expected policies
+	count := 0
+	found := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+		found = append(found, raw.(*structs.ScalingPolicy).Target[structs.ScalingTargetGroup])
+	}
+	require.Equal(1, count)
+	sort.Strings(found)
+	expect := []string{policyA.Target[structs.ScalingTargetGroup]}
+	sort.Strings(expect)
+	require.Equal(expect, found)
+
+	iter, err = state.ScalingPoliciesByJob(nil,
+		policyB1.Target[structs.ScalingTargetNamespace],
+		policyB1.Target[structs.ScalingTargetJob])
+	require.NoError(err)
+
+	// Ensure we see expected policies
+	count = 0
+	found = []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+		found = append(found, raw.(*structs.ScalingPolicy).Target[structs.ScalingTargetGroup])
+	}
+	require.Equal(2, count)
+	sort.Strings(found)
+	expect = []string{
+		policyB1.Target[structs.ScalingTargetGroup],
+		policyB2.Target[structs.ScalingTargetGroup],
+	}
+	sort.Strings(expect)
+	require.Equal(expect, found)
+}
+
+func TestStateStore_ScalingPoliciesByJob_PrefixBug(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	jobPrefix := "job-name-" + uuid.Generate()
+
+	state := testStateStore(t)
+	policy1 := mock.ScalingPolicy()
+	policy1.Target[structs.ScalingTargetJob] = jobPrefix
+	policy2 := mock.ScalingPolicy()
+	policy2.Target[structs.ScalingTargetJob] = jobPrefix + "-more"
+
+	// Create the policies
+	var baseIndex uint64 = 1000
+	err := state.UpsertScalingPolicies(baseIndex, []*structs.ScalingPolicy{policy1, policy2})
+	require.NoError(err)
+
+	iter, err := state.ScalingPoliciesByJob(nil,
+		policy1.Target[structs.ScalingTargetNamespace],
+		jobPrefix)
+	require.NoError(err)
+
+	// Ensure we see expected policies
+	count := 0
+	found := []string{}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+		found = append(found, raw.(*structs.ScalingPolicy).ID)
+	}
+	require.Equal(1, count)
+	expect := []string{policy1.ID}
+	require.Equal(expect, found)
+}
+
+func TestStateStore_UpsertScalingEvent(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	job := mock.Job()
+	groupName := job.TaskGroups[0].Name
+
+	newEvent := structs.NewScalingEvent("message 1").SetMeta(map[string]interface{}{
+		"a": 1,
+	})
+
+	wsAll := memdb.NewWatchSet()
+	all, err := state.ScalingEvents(wsAll)
+	require.NoError(err)
+	require.Nil(all.Next())
+
+	ws := memdb.NewWatchSet()
+	out, _, err := state.ScalingEventsByJob(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	err = state.UpsertScalingEvent(1000, &structs.ScalingEventRequest{
+		Namespace:    job.Namespace,
+		JobID:        job.ID,
+		TaskGroup:    groupName,
+		ScalingEvent: newEvent,
+	})
+	require.NoError(err)
+	require.True(watchFired(ws))
+	require.True(watchFired(wsAll))
+
+	ws = memdb.NewWatchSet()
+	out, eventsIndex, err := state.ScalingEventsByJob(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(map[string][]*structs.ScalingEvent{
+		groupName: {newEvent},
+	}, out)
+	require.EqualValues(eventsIndex, 1000)
+
+	iter, err := state.ScalingEvents(ws)
+	require.NoError(err)
+
+	count := 0
+	jobsReturned := []string{}
+	var jobEvents *structs.JobScalingEvents
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		jobEvents = raw.(*structs.JobScalingEvents)
+		jobsReturned = append(jobsReturned, jobEvents.JobID)
+		count++
+	}
+	require.Equal(1, count)
+	require.EqualValues(jobEvents.ModifyIndex, 1000)
+	require.EqualValues(jobEvents.ScalingEvents[groupName][0].CreateIndex, 1000)
+
+	index, err := state.Index("scaling_event")
+	require.NoError(err)
+	require.ElementsMatch([]string{job.ID}, jobsReturned)
+	require.Equal(map[string][]*structs.ScalingEvent{
+		groupName: {newEvent},
+	}, jobEvents.ScalingEvents)
+	require.EqualValues(1000, index)
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_UpsertScalingEvent_LimitAndOrder(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	namespace := uuid.Generate()
+	jobID := uuid.Generate()
+	group1 := uuid.Generate()
+	group2 := uuid.Generate()
+
+	index := uint64(1000)
+	for i := 1; i <= structs.JobTrackedScalingEvents+10; i++ {
+		newEvent := structs.NewScalingEvent("").SetMeta(map[string]interface{}{
+			"i":     i,
+			"group": group1,
+		})
+		err := state.UpsertScalingEvent(index, &structs.ScalingEventRequest{
+			Namespace:    namespace,
+			JobID:        jobID,
+			TaskGroup:    group1,
+			ScalingEvent: newEvent,
+		})
+		index++
+		require.NoError(err)
+
+		newEvent = structs.NewScalingEvent("").SetMeta(map[string]interface{}{
+			"i":     i,
+			"group": group2,
+		})
+		err = state.UpsertScalingEvent(index, &structs.ScalingEventRequest{
+			Namespace:    namespace,
+			JobID:        jobID,
+			TaskGroup:    group2,
+			ScalingEvent: newEvent,
+		})
+		index++
+		require.NoError(err)
+	}
+
+	out, _, err := state.ScalingEventsByJob(nil, namespace, jobID)
+	require.NoError(err)
+	require.Len(out, 2)
+
+	expectedEvents := []int{}
+	for i := structs.JobTrackedScalingEvents; i > 0; i-- {
+		expectedEvents = append(expectedEvents, i+10)
+	}
+
+	// checking order and content
+	require.Len(out[group1], structs.JobTrackedScalingEvents)
+	actualEvents := []int{}
+	for _, event := range out[group1] {
+		require.Equal(group1, event.Meta["group"])
+		actualEvents = append(actualEvents, event.Meta["i"].(int))
+	}
+	require.Equal(expectedEvents, actualEvents)
+
+	// checking order and content
+	require.Len(out[group2], structs.JobTrackedScalingEvents)
+	actualEvents = []int{}
+	for _, event := range out[group2] {
+		require.Equal(group2, event.Meta["group"])
+		actualEvents = append(actualEvents, event.Meta["i"].(int))
+	}
+	require.Equal(expectedEvents, actualEvents)
+}
+
+func TestStateStore_RestoreScalingEvents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := testStateStore(t)
+	jobScalingEvents := &structs.JobScalingEvents{
+		Namespace: uuid.Generate(),
+		JobID:     uuid.Generate(),
+		ScalingEvents: map[string][]*structs.ScalingEvent{
+			uuid.Generate(): {
+				structs.NewScalingEvent(uuid.Generate()),
+			},
+		},
+	}
+
+	restore, err := state.Restore()
+	require.NoError(err)
+
+	err = restore.ScalingEventsRestore(jobScalingEvents)
+	require.NoError(err)
+	restore.Commit()
+
+	ws := memdb.NewWatchSet()
+	out, _, err := state.ScalingEventsByJob(ws, jobScalingEvents.Namespace,
+		jobScalingEvents.JobID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.EqualValues(jobScalingEvents.ScalingEvents, out)
+}
+
+func TestStateStore_Abandon(t *testing.T) {
+	t.Parallel()
+
+	s := testStateStore(t)
+	abandonCh := s.AbandonCh()
+	s.Abandon()
+	select {
+	case <-abandonCh:
+	default:
+		t.Fatalf("bad")
+	}
+}
+
+// Verifies that an error is returned when an allocation doesn't exist in the state store.
+func TestStateSnapshot_DenormalizeAllocationDiffSlice_AllocDoesNotExist(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+	require := require.New(t)
+
+	// Insert job
+	err := state.UpsertJob(999, alloc.Job)
+	require.NoError(err)
+
+	allocDiffs := []*structs.AllocationDiff{
+		{
+			ID: alloc.ID,
+		},
+	}
+
+	snap, err := state.Snapshot()
+	require.NoError(err)
+
+	denormalizedAllocs, err := snap.DenormalizeAllocationDiffSlice(allocDiffs)
+
+	require.EqualError(err, fmt.Sprintf("alloc %v doesn't exist", alloc.ID))
+	require.Nil(denormalizedAllocs)
+}
+
+// TestStateStore_SnapshotMinIndex_OK asserts StateStore.SnapshotMinIndex blocks
+// until the StateStore's latest index is >= the requested index.
+func TestStateStore_SnapshotMinIndex_OK(t *testing.T) {
+	t.Parallel()
+
+	s := testStateStore(t)
+	index, err := s.LatestIndex()
+	require.NoError(t, err)
+
+	node := mock.Node()
+	require.NoError(t, s.UpsertNode(index+1, node))
+
+	// Assert SnapshotMinIndex returns immediately if index < latest index
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	snap, err := s.SnapshotMinIndex(ctx, index)
+	cancel()
+	require.NoError(t, err)
+
+	snapIndex, err := snap.LatestIndex()
+	require.NoError(t, err)
+	if snapIndex <= index {
+		require.Fail(t, "snapshot index should be greater than index")
+	}
+
+	// Assert SnapshotMinIndex returns immediately if index == latest index
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	snap, err = s.SnapshotMinIndex(ctx, index+1)
+	cancel()
+	require.NoError(t, err)
+
+	snapIndex, err = snap.LatestIndex()
+	require.NoError(t, err)
+	require.Equal(t, snapIndex, index+1)
+
+	// Assert SnapshotMinIndex blocks if index > latest index
+	errCh := make(chan error, 1)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		defer close(errCh)
+		waitIndex := index + 2
+		snap, err := s.SnapshotMinIndex(ctx, waitIndex)
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		snapIndex, err := snap.LatestIndex()
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		if snapIndex < waitIndex {
+			errCh <- fmt.Errorf("snapshot index < wait index: %d < %d", snapIndex, waitIndex)
+			return
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(500 * time.Millisecond):
+		// Let it block for a bit before unblocking by upserting
+	}
+
+	node.Name = "hal"
+	require.NoError(t, s.UpsertNode(index+2, node))
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for SnapshotMinIndex to unblock")
+	}
+}
+
+// TestStateStore_SnapshotMinIndex_Timeout asserts StateStore.SnapshotMinIndex
+// returns an error if the desired index is not reached within the deadline.
+func TestStateStore_SnapshotMinIndex_Timeout(t *testing.T) {
+	t.Parallel()
+
+	s := testStateStore(t)
+	index, err := s.LatestIndex()
+	require.NoError(t, err)
+
+	// Assert SnapshotMinIndex blocks if index > latest index
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	snap, err := s.SnapshotMinIndex(ctx, index+1)
+	require.EqualError(t, err, context.DeadlineExceeded.Error())
+	require.Nil(t, snap)
+}
+
+// watchFired is a helper for unit tests that returns if the given watch set
+// fired (it doesn't care which watch actually fired). This uses a fixed
+// timeout since we already expect the event happened before calling this and
+// just need to distinguish a fire from a timeout. We do need a little time to
+// allow the watch to set up any goroutines, though.
+func watchFired(ws memdb.WatchSet) bool {
+	timedOut := ws.Watch(time.After(50 * time.Millisecond))
+	return !timedOut
+}
+
+// NodeIDSort is used to sort nodes by ID
+type NodeIDSort []*structs.Node
+
+func (n NodeIDSort) Len() int {
+	return len(n)
+}
+
+func (n NodeIDSort) Less(i, j int) bool {
+	return n[i].ID < n[j].ID
+}
+
+func (n NodeIDSort) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
+
+// JobIDis used to sort jobs by id
+type JobIDSort []*structs.Job
 
-	// Stop the job
-	job, err = state.JobByID(nil, job.Namespace, job.ID)
-	require.NoError(err)
-	job.Stop = true
-	err = state.UpsertJob(1200, job)
-	require.NoError(err)
+func (n JobIDSort) Len() int {
+	return len(n)
+}
 
-	// Ensure:
-	// * the scaling policy was deleted
-	// * the watches were fired
-	// * the table index was advanced
-	require.True(watchFired(wsGet))
-	require.True(watchFired(wsList))
-	out, err = state.ScalingPolicyByTarget(nil, policy.Target)
-	require.NoError(err)
-	require.Nil(out)
-	index, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.GreaterOrEqual(index, uint64(1200))
+func (n JobIDSort) Less(i, j int) bool {
+	return n[i].ID < n[j].ID
 }
 
-func TestStateStore_UnstopJob_UpsertScalingPolicies(t *testing.T) {
-	t.Parallel()
+func (n JobIDSort) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
 
-	require := require.New(t)
+// EvalIDis used to sort evals by id
+type EvalIDSort []*structs.Evaluation
 
-	state := testStateStore(t)
+func (n EvalIDSort) Len() int {
+	return len(n)
+}
 
-	job, policy := mock.JobWithScalingPolicy()
-	job.Stop = true
+func (n EvalIDSort) Less(i, j int) bool {
+	return n[i].ID < n[j].ID
+}
 
-	// establish watcher, verify there are no scaling policies yet
-	ws := memdb.NewWatchSet()
-	list, err := state.ScalingPolicies(ws)
-	require.NoError(err)
-	require.Nil(list.Next())
+func (n EvalIDSort) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
 
-	// upsert a stopped job, verify that we don't fire the watcher or add any scaling policies
-	err = state.UpsertJob(1000, job)
-	require.NoError(err)
-	require.False(watchFired(ws))
-	// stopped job should have no scaling policies, watcher doesn't fire
-	list, err = state.ScalingPolicies(ws)
-	require.NoError(err)
-	require.Nil(list.Next())
+// AllocIDsort used to sort allocations by id
+type AllocIDSort []*structs.Allocation
 
-	// Establish a new watcher
-	ws = memdb.NewWatchSet()
-	_, err = state.ScalingPolicies(ws)
-	require.NoError(err)
-	// Unstop this job, say you'll run it again...
-	job.Stop = false
-	err = state.UpsertJob(1100, job)
-	require.NoError(err)
+func (n AllocIDSort) Len() int {
+	return len(n)
+}
 
-	// Ensure the scaling policy was added, watch was fired, index was advanced
-	require.True(watchFired(ws))
-	out, err := state.ScalingPolicyByTarget(nil, policy.Target)
-	require.NoError(err)
-	require.NotNil(out)
-	index, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.GreaterOrEqual(index, uint64(1100))
+func (n AllocIDSort) Less(i, j int) bool {
+	return n[i].ID < n[j].ID
 }
 
-func TestStateStore_DeleteJob_DeleteScalingPolicies(t *testing.T) {
-	t.Parallel()
+func (n AllocIDSort) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
 
+func TestStateStore_FreezeWindows(t *testing.T) {
 	require := require.New(t)
 
 	state := testStateStore(t)
 
-	job := mock.Job()
-
-	err := state.UpsertJob(1000, job)
+	// No windows stored yet
+	index, windows, err := state.FreezeWindows()
 	require.NoError(err)
-
-	policy := mock.ScalingPolicy()
-	policy.Target[structs.ScalingTargetJob] = job.ID
-	err = state.UpsertScalingPolicies(1001, []*structs.ScalingPolicy{policy})
+	require.Nil(windows)
+	require.Zero(index)
+
+	err = state.FreezeWindowsSet(1000, &structs.FreezeWindows{
+		Windows: []*structs.FreezeWindow{
+			{
+				Name:     "weekend",
+				Spec:     "0 0 * * 6",
+				Duration: 48 * time.Hour,
+				Enabled:  true,
+			},
+		},
+	})
 	require.NoError(err)
 
-	// Delete the job
-	err = state.DeleteJob(1002, job.Namespace, job.ID)
+	index, windows, err = state.FreezeWindows()
 	require.NoError(err)
+	require.EqualValues(1000, index)
+	require.Len(windows.Windows, 1)
+	require.EqualValues(1000, windows.CreateIndex)
 
-	// Ensure the scaling policy was deleted
-	ws := memdb.NewWatchSet()
-	out, err := state.ScalingPolicyByTarget(ws, policy.Target)
+	// Updates preserve the create index
+	err = state.FreezeWindowsSet(1001, &structs.FreezeWindows{})
 	require.NoError(err)
-	require.Nil(out)
-	index, err := state.Index("scaling_policy")
+
+	_, windows, err = state.FreezeWindows()
 	require.NoError(err)
-	require.True(index > 1001)
+	require.Empty(windows.Windows)
+	require.EqualValues(1000, windows.CreateIndex)
+	require.EqualValues(1001, windows.ModifyIndex)
 }
 
-func TestStateStore_DeleteJob_DeleteScalingPoliciesPrefixBug(t *testing.T) {
-	t.Parallel()
-
+func TestStateStore_LicenseEvents(t *testing.T) {
 	require := require.New(t)
 
 	state := testStateStore(t)
 
-	job := mock.Job()
-	require.NoError(state.UpsertJob(1000, job))
-	job2 := job.Copy()
-	job2.ID = job.ID + "-but-longer"
-	require.NoError(state.UpsertJob(1001, job2))
+	// No events recorded yet
+	iter, err := state.LicenseEvents(nil)
+	require.NoError(err)
+	require.Nil(iter.Next())
 
-	policy := mock.ScalingPolicy()
-	policy.Target[structs.ScalingTargetJob] = job.ID
-	policy2 := mock.ScalingPolicy()
-	policy2.Target[structs.ScalingTargetJob] = job2.ID
-	require.NoError(state.UpsertScalingPolicies(1002, []*structs.ScalingPolicy{policy, policy2}))
+	require.NoError(state.InsertLicenseEvent(1000, &structs.LicenseEvent{
+		Action:       structs.LicenseEventActionApply,
+		AccessorID:   "accessor-1",
+		NewLicenseID: "license-1",
+		Time:         time.Now().UnixNano(),
+	}))
+	require.NoError(state.InsertLicenseEvent(1001, &structs.LicenseEvent{
+		Action:       structs.LicenseEventActionReset,
+		AccessorID:   "accessor-2",
+		OldLicenseID: "license-1",
+		Time:         time.Now().UnixNano(),
+	}))
+
+	// Events are returned oldest first
+	iter, err = state.LicenseEvents(nil)
+	require.NoError(err)
 
-	// Delete job with the shorter prefix-ID
-	require.NoError(state.DeleteJob(1003, job.Namespace, job.ID))
+	first := iter.Next().(*structs.LicenseEvent)
+	require.Equal(structs.LicenseEventActionApply, first.Action)
+	require.EqualValues(1000, first.CreateIndex)
 
-	// Ensure only the associated scaling policy was deleted, not the one matching the job with the longer ID
-	out, err := state.ScalingPolicyByID(nil, policy.ID)
-	require.NoError(err)
-	require.Nil(out)
-	out, err = state.ScalingPolicyByID(nil, policy2.ID)
+	second := iter.Next().(*structs.LicenseEvent)
+	require.Equal(structs.LicenseEventActionReset, second.Action)
+	require.Equal("license-1", second.OldLicenseID)
+	require.EqualValues(1001, second.CreateIndex)
+
+	require.Nil(iter.Next())
+
+	index, err := state.Index("license_events")
 	require.NoError(err)
-	require.NotNil(out)
+	require.EqualValues(1001, index)
 }
 
-// This test ensures that deleting a job that doesn't have any scaling policies
-// will not cause the scaling_policy table index to increase, on either job
-// registration or deletion.
-func TestStateStore_DeleteJob_ScalingPolicyIndexNoop(t *testing.T) {
+// Test promoting a task group that is not part of the deployment.
+func TestStateStore_UpsertDeploymentPromotion_UnknownGroup(t *testing.T) {
 	t.Parallel()
 
+	state := testStateStore(t)
 	require := require.New(t)
 
-	state := testStateStore(t)
+	// Create a job and deployment
+	j := mock.Job()
+	require.Nil(state.UpsertJob(1, j))
 
-	job := mock.Job()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	require.Nil(state.UpsertDeployment(2, d))
 
-	prevIndex, err := state.Index("scaling_policy")
-	require.NoError(err)
+	// Promote a group that does not exist in the deployment
+	req := &structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			Groups:       []string{"nonexistent"},
+		},
+	}
+	err := state.UpdateDeploymentPromotion(3, req)
+	require.Error(err)
+	require.Contains(err.Error(), "are not part of deployment")
+}
 
-	err = state.UpsertJob(1000, job)
-	require.NoError(err)
+func TestStateStore_UpsertSentinelPolicy(t *testing.T) {
+	t.Parallel()
 
-	newIndex, err := state.Index("scaling_policy")
-	require.NoError(err)
-	require.Equal(prevIndex, newIndex)
+	state := testStateStore(t)
+	policy := mock.SentinelPolicy()
+	policy2 := mock.SentinelPolicy()
 
-	// Delete the job
-	err = state.DeleteJob(1002, job.Namespace, job.ID)
-	require.NoError(err)
+	ws := memdb.NewWatchSet()
+	if _, err := state.SentinelPolicyByName(ws, policy.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := state.SentinelPolicyByName(ws, policy2.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	newIndex, err = state.Index("scaling_policy")
-	require.NoError(err)
-	require.Equal(prevIndex, newIndex)
-}
+	if err := state.UpsertSentinelPolicies(1000,
+		[]*structs.SentinelPolicy{policy, policy2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-func TestStateStore_ScalingPoliciesByJob(t *testing.T) {
-	t.Parallel()
+	ws = memdb.NewWatchSet()
+	out, err := state.SentinelPolicyByName(ws, policy.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, policy, out)
 
-	require := require.New(t)
+	out, err = state.SentinelPolicyByName(ws, policy2.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, policy2, out)
 
-	state := testStateStore(t)
-	policyA := mock.ScalingPolicy()
-	policyB1 := mock.ScalingPolicy()
-	policyB2 := mock.ScalingPolicy()
-	policyB1.Target[structs.ScalingTargetJob] = policyB2.Target[structs.ScalingTargetJob]
+	iter, err := state.SentinelPolicies(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	// Create the policies
-	var baseIndex uint64 = 1000
-	err := state.UpsertScalingPolicies(baseIndex, []*structs.ScalingPolicy{policyA, policyB1, policyB2})
-	require.NoError(err)
+	// Ensure we see both policies
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
 
-	iter, err := state.ScalingPoliciesByJob(nil,
-		policyA.Target[structs.ScalingTargetNamespace],
-		policyA.Target[structs.ScalingTargetJob])
-	require.NoError(err)
+	iter, err = state.SentinelPoliciesByScope(ws, structs.SentinelScopeSubmitJob)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	// Ensure we see expected policies
-	count := 0
-	found := []string{}
+	// Ensure we see both policies in the scope
+	count = 0
 	for {
 		raw := iter.Next()
 		if raw == nil {
 			break
 		}
 		count++
-		found = append(found, raw.(*structs.ScalingPolicy).Target[structs.ScalingTargetGroup])
 	}
-	require.Equal(1, count)
-	sort.Strings(found)
-	expect := []string{policyA.Target[structs.ScalingTargetGroup]}
-	sort.Strings(expect)
-	require.Equal(expect, found)
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
+
+	index, err := state.Index("sentinel_policy")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
+
+func TestStateStore_DeleteSentinelPolicy(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	policy := mock.SentinelPolicy()
+	policy2 := mock.SentinelPolicy()
+
+	// Create the policy
+	if err := state.UpsertSentinelPolicies(1000,
+		[]*structs.SentinelPolicy{policy, policy2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.SentinelPolicyByName(ws, policy.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Delete the policy
+	if err := state.DeleteSentinelPolicies(1001,
+		[]string{policy.Name, policy2.Name}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-	iter, err = state.ScalingPoliciesByJob(nil,
-		policyB1.Target[structs.ScalingTargetNamespace],
-		policyB1.Target[structs.ScalingTargetJob])
-	require.NoError(err)
+	// Ensure we don't get the object back
+	ws = memdb.NewWatchSet()
+	out, err := state.SentinelPolicyByName(ws, policy.Name)
+	assert.Equal(t, nil, err)
+	if out != nil {
+		t.Fatalf("bad: %#v", out)
+	}
 
-	// Ensure we see expected policies
-	count = 0
-	found = []string{}
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		count++
-		found = append(found, raw.(*structs.ScalingPolicy).Target[structs.ScalingTargetGroup])
+	index, err := state.Index("sentinel_policy")
+	if err != nil {
+		t.Fatalf("err: %v", err)
 	}
-	require.Equal(2, count)
-	sort.Strings(found)
-	expect = []string{
-		policyB1.Target[structs.ScalingTargetGroup],
-		policyB2.Target[structs.ScalingTargetGroup],
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
 	}
-	sort.Strings(expect)
-	require.Equal(expect, found)
 }
 
-func TestStateStore_ScalingPoliciesByJob_PrefixBug(t *testing.T) {
+func TestStateStore_SentinelPolicyByNamePrefix(t *testing.T) {
 	t.Parallel()
 
-	require := require.New(t)
-
-	jobPrefix := "job-name-" + uuid.Generate()
-
 	state := testStateStore(t)
-	policy1 := mock.ScalingPolicy()
-	policy1.Target[structs.ScalingTargetJob] = jobPrefix
-	policy2 := mock.ScalingPolicy()
-	policy2.Target[structs.ScalingTargetJob] = jobPrefix + "-more"
+	names := []string{
+		"foo",
+		"bar",
+		"foobar",
+		"foozip",
+		"zip",
+	}
 
 	// Create the policies
 	var baseIndex uint64 = 1000
-	err := state.UpsertScalingPolicies(baseIndex, []*structs.ScalingPolicy{policy1, policy2})
-	require.NoError(err)
+	for _, name := range names {
+		p := mock.SentinelPolicy()
+		p.Name = name
+		if err := state.UpsertSentinelPolicies(baseIndex, []*structs.SentinelPolicy{p}); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		baseIndex++
+	}
 
-	iter, err := state.ScalingPoliciesByJob(nil,
-		policy1.Target[structs.ScalingTargetNamespace],
-		jobPrefix)
-	require.NoError(err)
+	// Scan by prefix
+	iter, err := state.SentinelPolicyByNamePrefix(nil, "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	// Ensure we see expected policies
+	// Ensure we see both policies
 	count := 0
-	found := []string{}
+	out := []string{}
 	for {
 		raw := iter.Next()
 		if raw == nil {
 			break
 		}
 		count++
-		found = append(found, raw.(*structs.ScalingPolicy).ID)
+		out = append(out, raw.(*structs.SentinelPolicy).Name)
 	}
-	require.Equal(1, count)
-	expect := []string{policy1.ID}
-	require.Equal(expect, found)
+	if count != 3 {
+		t.Fatalf("bad: %d %v", count, out)
+	}
+	sort.Strings(out)
+
+	expect := []string{"foo", "foobar", "foozip"}
+	assert.Equal(t, expect, out)
 }
 
-func TestStateStore_UpsertScalingEvent(t *testing.T) {
+func TestStateStore_RestoreSentinelPolicy(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
 	state := testStateStore(t)
-	job := mock.Job()
-	groupName := job.TaskGroups[0].Name
+	policy := mock.SentinelPolicy()
 
-	newEvent := structs.NewScalingEvent("message 1").SetMeta(map[string]interface{}{
-		"a": 1,
-	})
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	wsAll := memdb.NewWatchSet()
-	all, err := state.ScalingEvents(wsAll)
-	require.NoError(err)
-	require.Nil(all.Next())
+	err = restore.SentinelPolicyRestore(policy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
 
 	ws := memdb.NewWatchSet()
-	out, _, err := state.ScalingEventsByJob(ws, job.Namespace, job.ID)
-	require.NoError(err)
-	require.Nil(out)
-
-	err = state.UpsertScalingEvent(1000, &structs.ScalingEventRequest{
-		Namespace:    job.Namespace,
-		JobID:        job.ID,
-		TaskGroup:    groupName,
-		ScalingEvent: newEvent,
-	})
-	require.NoError(err)
-	require.True(watchFired(ws))
-	require.True(watchFired(wsAll))
-
-	ws = memdb.NewWatchSet()
-	out, eventsIndex, err := state.ScalingEventsByJob(ws, job.Namespace, job.ID)
-	require.NoError(err)
-	require.Equal(map[string][]*structs.ScalingEvent{
-		groupName: {newEvent},
-	}, out)
-	require.EqualValues(eventsIndex, 1000)
-
-	iter, err := state.ScalingEvents(ws)
-	require.NoError(err)
-
-	count := 0
-	jobsReturned := []string{}
-	var jobEvents *structs.JobScalingEvents
-	for {
-		raw := iter.Next()
-		if raw == nil {
-			break
-		}
-		jobEvents = raw.(*structs.JobScalingEvents)
-		jobsReturned = append(jobsReturned, jobEvents.JobID)
-		count++
+	out, err := state.SentinelPolicyByName(ws, policy.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
 	}
-	require.Equal(1, count)
-	require.EqualValues(jobEvents.ModifyIndex, 1000)
-	require.EqualValues(jobEvents.ScalingEvents[groupName][0].CreateIndex, 1000)
-
-	index, err := state.Index("scaling_event")
-	require.NoError(err)
-	require.ElementsMatch([]string{job.ID}, jobsReturned)
-	require.Equal(map[string][]*structs.ScalingEvent{
-		groupName: {newEvent},
-	}, jobEvents.ScalingEvents)
-	require.EqualValues(1000, index)
-	require.False(watchFired(ws))
+	assert.Equal(t, policy, out)
 }
 
-func TestStateStore_UpsertScalingEvent_LimitAndOrder(t *testing.T) {
+func TestStateStore_SentinelOverrides(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
 	state := testStateStore(t)
-	namespace := uuid.Generate()
-	jobID := uuid.Generate()
-	group1 := uuid.Generate()
-	group2 := uuid.Generate()
 
-	index := uint64(1000)
-	for i := 1; i <= structs.JobTrackedScalingEvents+10; i++ {
-		newEvent := structs.NewScalingEvent("").SetMeta(map[string]interface{}{
-			"i":     i,
-			"group": group1,
-		})
-		err := state.UpsertScalingEvent(index, &structs.ScalingEventRequest{
-			Namespace:    namespace,
-			JobID:        jobID,
-			TaskGroup:    group1,
-			ScalingEvent: newEvent,
-		})
-		index++
-		require.NoError(err)
+	// Insert two overrides
+	o1 := &structs.SentinelOverride{
+		Policies:  []string{"foo"},
+		JobID:     "example",
+		Namespace: "default",
+		Time:      time.Now().UnixNano(),
+	}
+	require.NoError(t, state.InsertSentinelOverride(1000, o1))
 
-		newEvent = structs.NewScalingEvent("").SetMeta(map[string]interface{}{
-			"i":     i,
-			"group": group2,
-		})
-		err = state.UpsertScalingEvent(index, &structs.ScalingEventRequest{
-			Namespace:    namespace,
-			JobID:        jobID,
-			TaskGroup:    group2,
-			ScalingEvent: newEvent,
-		})
-		index++
-		require.NoError(err)
+	o2 := &structs.SentinelOverride{
+		Policies:   []string{"foo", "bar"},
+		JobID:      "example",
+		Namespace:  "default",
+		AccessorID: "deadbeef",
+		Time:       time.Now().UnixNano(),
 	}
+	require.NoError(t, state.InsertSentinelOverride(1001, o2))
 
-	out, _, err := state.ScalingEventsByJob(nil, namespace, jobID)
-	require.NoError(err)
-	require.Len(out, 2)
+	// Iterate the audit trail oldest first
+	ws := memdb.NewWatchSet()
+	iter, err := state.SentinelOverrides(ws)
+	require.NoError(t, err)
 
-	expectedEvents := []int{}
-	for i := structs.JobTrackedScalingEvents; i > 0; i-- {
-		expectedEvents = append(expectedEvents, i+10)
+	var out []*structs.SentinelOverride
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.SentinelOverride))
 	}
+	require.Len(t, out, 2)
+	require.Equal(t, uint64(1000), out[0].CreateIndex)
+	require.Equal(t, uint64(1001), out[1].CreateIndex)
+	require.Equal(t, "deadbeef", out[1].AccessorID)
 
-	// checking order and content
-	require.Len(out[group1], structs.JobTrackedScalingEvents)
-	actualEvents := []int{}
-	for _, event := range out[group1] {
-		require.Equal(group1, event.Meta["group"])
-		actualEvents = append(actualEvents, event.Meta["i"].(int))
-	}
-	require.Equal(expectedEvents, actualEvents)
+	index, err := state.Index("sentinel_overrides")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1001), index)
 
-	// checking order and content
-	require.Len(out[group2], structs.JobTrackedScalingEvents)
-	actualEvents = []int{}
-	for _, event := range out[group2] {
-		require.Equal(group2, event.Meta["group"])
-		actualEvents = append(actualEvents, event.Meta["i"].(int))
+	// Inserting another override fires the watch
+	o3 := &structs.SentinelOverride{
+		Policies:  []string{"baz"},
+		JobID:     "example",
+		Namespace: "default",
+		Time:      time.Now().UnixNano(),
 	}
-	require.Equal(expectedEvents, actualEvents)
+	require.NoError(t, state.InsertSentinelOverride(1002, o3))
+	require.True(t, watchFired(ws))
 }
 
-func TestStateStore_RestoreScalingEvents(t *testing.T) {
+func TestStateStore_RestoreSentinelOverride(t *testing.T) {
 	t.Parallel()
-	require := require.New(t)
 
 	state := testStateStore(t)
-	jobScalingEvents := &structs.JobScalingEvents{
-		Namespace: uuid.Generate(),
-		JobID:     uuid.Generate(),
-		ScalingEvents: map[string][]*structs.ScalingEvent{
-			uuid.Generate(): {
-				structs.NewScalingEvent(uuid.Generate()),
-			},
-		},
+	override := &structs.SentinelOverride{
+		Policies:    []string{"foo"},
+		JobID:       "example",
+		Namespace:   "default",
+		Time:        time.Now().UnixNano(),
+		CreateIndex: 1000,
 	}
 
 	restore, err := state.Restore()
-	require.NoError(err)
-
-	err = restore.ScalingEventsRestore(jobScalingEvents)
-	require.NoError(err)
+	require.NoError(t, err)
+	require.NoError(t, restore.SentinelOverrideRestore(override))
 	restore.Commit()
 
-	ws := memdb.NewWatchSet()
-	out, _, err := state.ScalingEventsByJob(ws, jobScalingEvents.Namespace,
-		jobScalingEvents.JobID)
-	require.NoError(err)
-	require.NotNil(out)
-	require.EqualValues(jobScalingEvents.ScalingEvents, out)
-}
-
-func TestStateStore_Abandon(t *testing.T) {
-	t.Parallel()
-
-	s := testStateStore(t)
-	abandonCh := s.AbandonCh()
-	s.Abandon()
-	select {
-	case <-abandonCh:
-	default:
-		t.Fatalf("bad")
-	}
+	iter, err := state.SentinelOverrides(nil)
+	require.NoError(t, err)
+	require.Equal(t, override, iter.Next())
 }
 
-// Verifies that an error is returned when an allocation doesn't exist in the state store.
-func TestStateSnapshot_DenormalizeAllocationDiffSlice_AllocDoesNotExist(t *testing.T) {
+func TestStateStore_UpsertSentinelModule(t *testing.T) {
 	t.Parallel()
 
 	state := testStateStore(t)
-	alloc := mock.Alloc()
-	require := require.New(t)
+	module := mock.SentinelModule()
+	module2 := mock.SentinelModule()
 
-	// Insert job
-	err := state.UpsertJob(999, alloc.Job)
-	require.NoError(err)
+	ws := memdb.NewWatchSet()
+	if _, err := state.SentinelModuleByName(ws, module.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	allocDiffs := []*structs.AllocationDiff{
-		{
-			ID: alloc.ID,
-		},
+	if err := state.UpsertSentinelModules(1000,
+		[]*structs.SentinelModule{module, module2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !watchFired(ws) {
+		t.Fatalf("bad")
 	}
 
-	snap, err := state.Snapshot()
-	require.NoError(err)
+	ws = memdb.NewWatchSet()
+	out, err := state.SentinelModuleByName(ws, module.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, module, out)
 
-	denormalizedAllocs, err := snap.DenormalizeAllocationDiffSlice(allocDiffs)
+	out, err = state.SentinelModuleByName(ws, module2.Name)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, module2, out)
 
-	require.EqualError(err, fmt.Sprintf("alloc %v doesn't exist", alloc.ID))
-	require.Nil(denormalizedAllocs)
+	iter, err := state.SentinelModules(ws)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure we see both modules
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("bad: %d", count)
+	}
+
+	index, err := state.Index("sentinel_module")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1000 {
+		t.Fatalf("bad: %d", index)
+	}
+
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
 }
 
-// TestStateStore_SnapshotMinIndex_OK asserts StateStore.SnapshotMinIndex blocks
-// until the StateStore's latest index is >= the requested index.
-func TestStateStore_SnapshotMinIndex_OK(t *testing.T) {
+func TestStateStore_DeleteSentinelModule(t *testing.T) {
 	t.Parallel()
 
-	s := testStateStore(t)
-	index, err := s.LatestIndex()
-	require.NoError(t, err)
+	state := testStateStore(t)
+	module := mock.SentinelModule()
+	module2 := mock.SentinelModule()
 
-	node := mock.Node()
-	require.NoError(t, s.UpsertNode(index+1, node))
+	// Create the module
+	if err := state.UpsertSentinelModules(1000,
+		[]*structs.SentinelModule{module, module2}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	// Assert SnapshotMinIndex returns immediately if index < latest index
-	ctx, cancel := context.WithTimeout(context.Background(), 0)
-	snap, err := s.SnapshotMinIndex(ctx, index)
-	cancel()
-	require.NoError(t, err)
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	if _, err := state.SentinelModuleByName(ws, module.Name); err != nil {
+		t.Fatalf("err: %v", err)
+	}
 
-	snapIndex, err := snap.LatestIndex()
-	require.NoError(t, err)
-	if snapIndex <= index {
-		require.Fail(t, "snapshot index should be greater than index")
+	// Delete the module
+	if err := state.DeleteSentinelModules(1001,
+		[]string{module.Name, module2.Name}); err != nil {
+		t.Fatalf("err: %v", err)
 	}
 
-	// Assert SnapshotMinIndex returns immediately if index == latest index
-	ctx, cancel = context.WithTimeout(context.Background(), 0)
-	snap, err = s.SnapshotMinIndex(ctx, index+1)
-	cancel()
-	require.NoError(t, err)
+	// Ensure watching triggered
+	if !watchFired(ws) {
+		t.Fatalf("bad")
+	}
 
-	snapIndex, err = snap.LatestIndex()
-	require.NoError(t, err)
-	require.Equal(t, snapIndex, index+1)
+	// Ensure we don't get the object back
+	ws = memdb.NewWatchSet()
+	out, err := state.SentinelModuleByName(ws, module.Name)
+	assert.Equal(t, nil, err)
+	if out != nil {
+		t.Fatalf("bad: %#v", out)
+	}
 
-	// Assert SnapshotMinIndex blocks if index > latest index
-	errCh := make(chan error, 1)
-	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	go func() {
-		defer close(errCh)
-		waitIndex := index + 2
-		snap, err := s.SnapshotMinIndex(ctx, waitIndex)
-		if err != nil {
-			errCh <- err
-			return
-		}
+	index, err := state.Index("sentinel_module")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if index != 1001 {
+		t.Fatalf("bad: %d", index)
+	}
 
-		snapIndex, err := snap.LatestIndex()
-		if err != nil {
-			errCh <- err
-			return
-		}
+	if watchFired(ws) {
+		t.Fatalf("bad")
+	}
+}
 
-		if snapIndex < waitIndex {
-			errCh <- fmt.Errorf("snapshot index < wait index: %d < %d", snapIndex, waitIndex)
-			return
-		}
-	}()
+func TestStateStore_RestoreSentinelModule(t *testing.T) {
+	t.Parallel()
 
-	select {
-	case err := <-errCh:
-		require.NoError(t, err)
-	case <-time.After(500 * time.Millisecond):
-		// Let it block for a bit before unblocking by upserting
+	state := testStateStore(t)
+	module := mock.SentinelModule()
+
+	restore, err := state.Restore()
+	if err != nil {
+		t.Fatalf("err: %v", err)
 	}
 
-	node.Name = "hal"
-	require.NoError(t, s.UpsertNode(index+2, node))
+	err = restore.SentinelModuleRestore(module)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	restore.Commit()
 
-	select {
-	case err := <-errCh:
-		require.NoError(t, err)
-	case <-time.After(5 * time.Second):
-		require.Fail(t, "timed out waiting for SnapshotMinIndex to unblock")
+	ws := memdb.NewWatchSet()
+	out, err := state.SentinelModuleByName(ws, module.Name)
+	if err != nil {
+		t.Fatalf("err: %v", err)
 	}
+	assert.Equal(t, module, out)
 }
 
-// TestStateStore_SnapshotMinIndex_Timeout asserts StateStore.SnapshotMinIndex
-// returns an error if the desired index is not reached within the deadline.
-func TestStateStore_SnapshotMinIndex_Timeout(t *testing.T) {
+func TestStateStore_UpsertServiceRegistrations(t *testing.T) {
 	t.Parallel()
+	require := require.New(t)
 
-	s := testStateStore(t)
-	index, err := s.LatestIndex()
-	require.NoError(t, err)
+	state := testStateStore(t)
+	service1 := mock.ServiceRegistration()
+	service2 := mock.ServiceRegistration()
 
-	// Assert SnapshotMinIndex blocks if index > latest index
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-	defer cancel()
-	snap, err := s.SnapshotMinIndex(ctx, index+1)
-	require.EqualError(t, err, context.DeadlineExceeded.Error())
-	require.Nil(t, snap)
-}
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.ServiceRegistrationByID(ws, service1.ID)
+	require.NoError(err)
 
-// watchFired is a helper for unit tests that returns if the given watch set
-// fired (it doesn't care which watch actually fired). This uses a fixed
-// timeout since we already expect the event happened before calling this and
-// just need to distinguish a fire from a timeout. We do need a little time to
-// allow the watch to set up any goroutines, though.
-func watchFired(ws memdb.WatchSet) bool {
-	timedOut := ws.Watch(time.After(50 * time.Millisecond))
-	return !timedOut
-}
+	require.NoError(state.UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service1, service2}))
+	require.True(watchFired(ws))
 
-// NodeIDSort is used to sort nodes by ID
-type NodeIDSort []*structs.Node
+	ws = memdb.NewWatchSet()
+	out, err := state.ServiceRegistrationByID(ws, service1.ID)
+	require.NoError(err)
+	require.Equal(service1, out)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1000, out.ModifyIndex)
 
-func (n NodeIDSort) Len() int {
-	return len(n)
-}
+	// Lookup by service name
+	iter, err := state.ServiceRegistrationsByName(nil, service1.ServiceName)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(2, count)
 
-func (n NodeIDSort) Less(i, j int) bool {
-	return n[i].ID < n[j].ID
-}
+	// Lookup by allocation
+	iter, err = state.ServiceRegistrationsByAllocID(nil, service2.AllocID)
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(service2, raw.(*structs.ServiceRegistration))
+	require.Nil(iter.Next())
 
-func (n NodeIDSort) Swap(i, j int) {
-	n[i], n[j] = n[j], n[i]
-}
+	// Upserting an unchanged registration should not bump the index
+	require.NoError(state.UpsertServiceRegistrations(1001,
+		[]*structs.ServiceRegistration{service1.Copy()}))
+	index, err := state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1000, index)
 
-// JobIDis used to sort jobs by id
-type JobIDSort []*structs.Job
+	// An updated registration keeps its create index
+	update := service1.Copy()
+	update.Port = 29002
+	require.NoError(state.UpsertServiceRegistrations(1002,
+		[]*structs.ServiceRegistration{update}))
+	require.True(watchFired(ws))
 
-func (n JobIDSort) Len() int {
-	return len(n)
+	out, err = state.ServiceRegistrationByID(nil, service1.ID)
+	require.NoError(err)
+	require.Equal(29002, out.Port)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1002, out.ModifyIndex)
 }
 
-func (n JobIDSort) Less(i, j int) bool {
-	return n[i].ID < n[j].ID
-}
+func TestStateStore_DeleteServiceRegistrationByID(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
 
-func (n JobIDSort) Swap(i, j int) {
-	n[i], n[j] = n[j], n[i]
-}
+	state := testStateStore(t)
+	service := mock.ServiceRegistration()
+	require.NoError(state.UpsertServiceRegistrations(1000,
+		[]*structs.ServiceRegistration{service}))
 
-// EvalIDis used to sort evals by id
-type EvalIDSort []*structs.Evaluation
+	ws := memdb.NewWatchSet()
+	_, err := state.ServiceRegistrationByID(ws, service.ID)
+	require.NoError(err)
 
-func (n EvalIDSort) Len() int {
-	return len(n)
-}
+	require.NoError(state.DeleteServiceRegistrationByID(1001, service.ID))
+	require.True(watchFired(ws))
 
-func (n EvalIDSort) Less(i, j int) bool {
-	return n[i].ID < n[j].ID
-}
+	out, err := state.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Nil(out)
 
-func (n EvalIDSort) Swap(i, j int) {
-	n[i], n[j] = n[j], n[i]
+	index, err := state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+
+	// Deleting an unknown registration is an error
+	err = state.DeleteServiceRegistrationByID(1002, service.ID)
+	require.Error(err)
+	require.Contains(err.Error(), "service registration not found")
 }
 
-// AllocIDsort used to sort allocations by id
-type AllocIDSort []*structs.Allocation
+func TestStateStore_RestoreServiceRegistration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
 
-func (n AllocIDSort) Len() int {
-	return len(n)
-}
+	state := testStateStore(t)
+	service := mock.ServiceRegistration()
 
-func (n AllocIDSort) Less(i, j int) bool {
-	return n[i].ID < n[j].ID
-}
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.ServiceRegistrationRestore(service))
+	restore.Commit()
 
-func (n AllocIDSort) Swap(i, j int) {
-	n[i], n[j] = n[j], n[i]
+	out, err := state.ServiceRegistrationByID(memdb.NewWatchSet(), service.ID)
+	require.NoError(err)
+	require.Equal(service, out)
 }
@@ -0,0 +1,141 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// durableEventFile is the name of the file event batches are persisted to
+// inside the configured durable path.
+const durableEventFile = "events.json"
+
+// durableBuffer persists the broker's event buffer as newline-delimited
+// JSON so subscribers can resume from their last index across server
+// restarts. Batches are appended as they are published and the file is
+// compacted back down to the in-memory buffer once it grows to twice the
+// buffer size.
+type durableBuffer struct {
+	// path is the file event batches are appended to
+	path string
+
+	// maxSize is the broker's buffer size, bounding reloads and
+	// triggering compaction
+	maxSize int
+
+	f   *os.File
+	enc *json.Encoder
+
+	// entries is the number of batches in the file, used to decide when
+	// to compact
+	entries int
+
+	// corrupt is set when load found an undecodable tail, so the file is
+	// rewritten before new batches are appended to it
+	corrupt bool
+}
+
+// newDurableBuffer opens or creates the event file in dir.
+func newDurableBuffer(dir string, maxSize int) (*durableBuffer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create event buffer path: %v", err)
+	}
+
+	path := filepath.Join(dir, durableEventFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event buffer file: %v", err)
+	}
+
+	return &durableBuffer{
+		path:    path,
+		maxSize: maxSize,
+		f:       f,
+		enc:     json.NewEncoder(f),
+	}, nil
+}
+
+// load reads the persisted batches, returning at most the last maxSize of
+// them. Undecodable lines, such as a partial write from a crash, end the
+// reload; everything before them is still recovered.
+func (d *durableBuffer) load() []*structs.Events {
+	f, err := os.Open(d.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var buffer []*structs.Events
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var events structs.Events
+		if err := json.Unmarshal(scanner.Bytes(), &events); err != nil {
+			d.corrupt = true
+			break
+		}
+		d.entries++
+		buffer = append(buffer, &events)
+		if len(buffer) > d.maxSize {
+			buffer = buffer[1:]
+		}
+	}
+	return buffer
+}
+
+// append persists a batch, compacting the file from the in-memory buffer
+// once it holds twice the buffer size.
+func (d *durableBuffer) append(events *structs.Events, buffer []*structs.Events) error {
+	if err := d.enc.Encode(events); err != nil {
+		return err
+	}
+	d.entries++
+
+	if d.entries >= 2*d.maxSize {
+		return d.compact(buffer)
+	}
+	return nil
+}
+
+// compact rewrites the file to contain only the in-memory buffer. The new
+// file is written beside the old one and renamed into place so a crash
+// mid-compaction loses no batches.
+func (d *durableBuffer) compact(buffer []*structs.Events) error {
+	tmp := d.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	for _, events := range buffer {
+		if err := enc.Encode(events); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := os.Rename(tmp, d.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	d.f.Close()
+	d.f = f
+	d.enc = enc
+	d.entries = len(buffer)
+	d.corrupt = false
+	return nil
+}
+
+func (d *durableBuffer) Close() error {
+	return d.f.Close()
+}
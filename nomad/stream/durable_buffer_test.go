@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurableBuffer_Reload(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-events")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	broker, err := NewEventBroker(EventBrokerCfg{BufferSize: 10, DurablePath: dir})
+	require.NoError(err)
+
+	broker.Publish(jobEvent(1, "one", "default"))
+	broker.Publish(jobEvent(2, "two", "default"))
+	require.NoError(broker.Close())
+
+	// A new broker over the same path resumes from the persisted buffer
+	broker, err = NewEventBroker(EventBrokerCfg{BufferSize: 10, DurablePath: dir})
+	require.NoError(err)
+	defer broker.Close()
+	require.Equal(uint64(2), broker.Index())
+
+	sub := broker.Subscribe(&SubscribeRequest{
+		Index:  1,
+		Topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+	})
+	defer sub.Unsubscribe()
+
+	events := nextEvents(t, sub)
+	require.Equal(uint64(2), events.Index)
+	require.Equal("two", events.Events[0].Key)
+}
+
+func TestDurableBuffer_Compaction(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-events")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	broker, err := NewEventBroker(EventBrokerCfg{BufferSize: 2, DurablePath: dir})
+	require.NoError(err)
+	defer broker.Close()
+
+	for i := 1; i <= 10; i++ {
+		broker.Publish(jobEvent(uint64(i), "example", "default"))
+	}
+
+	// Compaction keeps the file bounded to the in-memory buffer
+	require.LessOrEqual(broker.durable.entries, 4)
+
+	// And the persisted tail is still intact
+	broker.Close()
+	broker, err = NewEventBroker(EventBrokerCfg{BufferSize: 2, DurablePath: dir})
+	require.NoError(err)
+	require.Equal(uint64(10), broker.Index())
+}
+
+func TestDurableBuffer_TruncatedTail(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-events")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	broker, err := NewEventBroker(EventBrokerCfg{BufferSize: 10, DurablePath: dir})
+	require.NoError(err)
+	broker.Publish(jobEvent(1, "one", "default"))
+	broker.Publish(jobEvent(2, "two", "default"))
+	require.NoError(broker.Close())
+
+	// Simulate a crash mid-write by appending a partial line
+	path := filepath.Join(dir, durableEventFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(err)
+	_, err = f.WriteString(`{"Index":3,"Events":[{"To`)
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	// Everything before the partial write is recovered
+	broker, err = NewEventBroker(EventBrokerCfg{BufferSize: 10, DurablePath: dir})
+	require.NoError(err)
+	require.Equal(uint64(2), broker.Index())
+
+	// New batches append cleanly after the corrupt tail was rewritten
+	broker.Publish(jobEvent(3, "three", "default"))
+	require.NoError(broker.Close())
+
+	broker, err = NewEventBroker(EventBrokerCfg{BufferSize: 10, DurablePath: dir})
+	require.NoError(err)
+	defer broker.Close()
+	require.Equal(uint64(3), broker.Index())
+}
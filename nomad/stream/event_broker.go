@@ -0,0 +1,171 @@
+// Package stream provides the server-side event broker backing the event
+// stream endpoint. State changes are published to the broker as they are
+// applied and fanned out to subscribers, with a bounded buffer of recent
+// events retained so subscribers can resume from a raft index.
+package stream
+
+import (
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// DefaultEventBufferSize is the number of event batches the broker retains
+// for index-based resumption.
+const DefaultEventBufferSize = 1024
+
+// EventBrokerCfg configures the event broker.
+type EventBrokerCfg struct {
+	// BufferSize is the number of event batches retained for index-based
+	// resumption. Zero uses DefaultEventBufferSize.
+	BufferSize int
+
+	// DurablePath enables on-disk persistence of the buffer when
+	// non-empty, so subscribers can resume across server restarts
+	DurablePath string
+
+	// Logger is used to log persistence failures
+	Logger log.Logger
+}
+
+// EventBroker fans out published events to any number of subscribers and
+// retains a bounded buffer of recent events for resumption.
+type EventBroker struct {
+	// maxSize is the max number of event batches retained in the buffer
+	maxSize int
+
+	// l protects the buffer and subscription set
+	l sync.Mutex
+
+	// buffer holds the most recent event batches, ordered by index
+	buffer []*structs.Events
+
+	// index is the index of the most recently published batch
+	index uint64
+
+	// subs is the set of active subscriptions
+	subs map[*Subscription]struct{}
+
+	// durable persists the buffer to disk when configured
+	durable *durableBuffer
+
+	logger log.Logger
+}
+
+// NewEventBroker returns an event broker configured with cfg. When a
+// durable path is configured, previously persisted event batches are
+// reloaded into the buffer so subscribers can resume across restarts.
+func NewEventBroker(cfg EventBrokerCfg) (*EventBroker, error) {
+	size := cfg.BufferSize
+	if size == 0 {
+		size = DefaultEventBufferSize
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.NewNullLogger()
+	}
+
+	b := &EventBroker{
+		maxSize: size,
+		subs:    make(map[*Subscription]struct{}),
+		logger:  logger.Named("event_broker"),
+	}
+
+	if cfg.DurablePath != "" {
+		durable, err := newDurableBuffer(cfg.DurablePath, size)
+		if err != nil {
+			return nil, err
+		}
+		b.durable = durable
+
+		b.buffer = durable.load()
+		if len(b.buffer) > 0 {
+			b.index = b.buffer[len(b.buffer)-1].Index
+		}
+
+		// Rewrite the file if the reload found a corrupt tail, such as
+		// a partial write from a crash, so appends start clean
+		if durable.corrupt {
+			if err := durable.compact(b.buffer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// Publish delivers a batch of events to all matching subscribers and
+// appends it to the buffer, evicting the oldest batch once full. Batches
+// are expected to arrive in index order.
+func (b *EventBroker) Publish(events *structs.Events) {
+	if len(events.Events) == 0 {
+		return
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.buffer = append(b.buffer, events)
+	if len(b.buffer) > b.maxSize {
+		b.buffer = b.buffer[1:]
+	}
+	b.index = events.Index
+
+	if b.durable != nil {
+		if err := b.durable.append(events, b.buffer); err != nil {
+			b.logger.Error("failed to persist events", "error", err)
+		}
+	}
+
+	for sub := range b.subs {
+		if !sub.send(events) {
+			// The subscriber fell too far behind; close it so the
+			// client can resubscribe from its last seen index
+			delete(b.subs, sub)
+			sub.forceClose()
+		}
+	}
+}
+
+// Index returns the index of the most recently published batch.
+func (b *EventBroker) Index() uint64 {
+	b.l.Lock()
+	defer b.l.Unlock()
+	return b.index
+}
+
+// Subscribe registers a new subscription with the broker. Buffered batches
+// newer than the requested index are replayed before new events are
+// delivered. The caller must call Unsubscribe when done.
+func (b *EventBroker) Subscribe(req *SubscribeRequest) *Subscription {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	sub := newSubscription(b, req)
+	for _, events := range b.buffer {
+		if events.Index > req.Index {
+			sub.send(events)
+		}
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe removes a subscription from the broker.
+func (b *EventBroker) unsubscribe(sub *Subscription) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	delete(b.subs, sub)
+}
+
+// Close releases the durable buffer, if one is configured.
+func (b *EventBroker) Close() error {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if b.durable != nil {
+		return b.durable.Close()
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testBroker(t *testing.T, size int) *EventBroker {
+	t.Helper()
+	broker, err := NewEventBroker(EventBrokerCfg{BufferSize: size})
+	require.NoError(t, err)
+	return broker
+}
+
+func jobEvent(index uint64, jobID, namespace string) *structs.Events {
+	return &structs.Events{
+		Index: index,
+		Events: []structs.Event{
+			{
+				Topic:     structs.TopicJob,
+				Type:      structs.TypeJobRegistered,
+				Key:       jobID,
+				Namespace: namespace,
+				Index:     index,
+			},
+		},
+	}
+}
+
+func nextEvents(t *testing.T, sub *Subscription) *structs.Events {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events, err := sub.Next(ctx)
+	require.NoError(t, err)
+	return events
+}
+
+func TestEventBroker_PublishSubscribe(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+	})
+	defer sub.Unsubscribe()
+
+	broker.Publish(jobEvent(1, "example", "default"))
+
+	events := nextEvents(t, sub)
+	require.Equal(uint64(1), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal("example", events.Events[0].Key)
+	require.Equal(uint64(1), broker.Index())
+}
+
+func TestEventBroker_Resume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	broker.Publish(jobEvent(1, "one", "default"))
+	broker.Publish(jobEvent(2, "two", "default"))
+	broker.Publish(jobEvent(3, "three", "default"))
+
+	// Resuming from index 1 replays only the buffered events after it
+	sub := broker.Subscribe(&SubscribeRequest{
+		Index:  1,
+		Topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+	})
+	defer sub.Unsubscribe()
+
+	require.Equal("two", nextEvents(t, sub).Events[0].Key)
+	require.Equal("three", nextEvents(t, sub).Events[0].Key)
+}
+
+func TestEventBroker_TopicAndKeyFilter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"web"}},
+	})
+	defer sub.Unsubscribe()
+
+	broker.Publish(jobEvent(1, "api", "default"))
+	broker.Publish(&structs.Events{
+		Index: 2,
+		Events: []structs.Event{
+			{Topic: structs.TopicNode, Type: structs.TypeNodeRegistered, Key: "web"},
+		},
+	})
+	broker.Publish(jobEvent(3, "web", "default"))
+
+	// Only the matching topic and key is delivered
+	events := nextEvents(t, sub)
+	require.Equal(uint64(3), events.Index)
+	require.Equal("web", events.Events[0].Key)
+}
+
+func TestEventBroker_FilterKeys(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicAlloc: {"job-1"}},
+	})
+	defer sub.Unsubscribe()
+
+	broker.Publish(&structs.Events{
+		Index: 1,
+		Events: []structs.Event{
+			{Topic: structs.TopicAlloc, Key: "alloc-1", FilterKeys: []string{"job-2"}},
+			{Topic: structs.TopicAlloc, Key: "alloc-2", FilterKeys: []string{"job-1"}},
+		},
+	})
+
+	// Only the alloc whose filter keys include the job is delivered
+	events := nextEvents(t, sub)
+	require.Len(events.Events, 1)
+	require.Equal("alloc-2", events.Events[0].Key)
+}
+
+func TestEventBroker_NamespaceFilter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		Namespace: "prod",
+	})
+	defer sub.Unsubscribe()
+
+	broker.Publish(jobEvent(1, "staging-job", "staging"))
+	broker.Publish(jobEvent(2, "prod-job", "prod"))
+
+	events := nextEvents(t, sub)
+	require.Equal("prod-job", events.Events[0].Key)
+}
+
+func TestEventBroker_BufferEviction(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 2)
+	broker.Publish(jobEvent(1, "one", "default"))
+	broker.Publish(jobEvent(2, "two", "default"))
+	broker.Publish(jobEvent(3, "three", "default"))
+
+	// The oldest batch was evicted so resuming from zero replays from two
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+	})
+	defer sub.Unsubscribe()
+
+	require.Equal("two", nextEvents(t, sub).Events[0].Key)
+	require.Equal("three", nextEvents(t, sub).Events[0].Key)
+}
+
+func TestEventBroker_SlowSubscriberClosed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	broker := testBroker(t, 10)
+	sub := broker.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+	})
+	defer sub.Unsubscribe()
+
+	// Overflow the subscription buffer without consuming
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		broker.Publish(jobEvent(uint64(i+1), "example", "default"))
+	}
+
+	// Drain until the subscription reports it was closed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		_, err := sub.Next(ctx)
+		if err != nil {
+			require.Equal(ErrSubscriptionClosed, err)
+			break
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// subscriptionBufferSize is the number of event batches a subscription can
+// hold before the broker considers it too far behind and closes it.
+const subscriptionBufferSize = 128
+
+// ErrSubscriptionClosed is returned by Next when the broker closed the
+// subscription because the subscriber fell too far behind. The subscriber
+// should resubscribe from its last seen index.
+var ErrSubscriptionClosed = errors.New("subscription closed by server, client should resubscribe")
+
+// SubscribeRequest describes the topics, keys and namespace a subscription
+// is interested in and the index to resume from.
+type SubscribeRequest struct {
+	// Index is the raft index to resume from; buffered events with a
+	// higher index are replayed on subscribe
+	Index uint64
+
+	// Topics maps subscribed topics to the object keys of interest. The
+	// wildcard "*" matches every topic or key.
+	Topics map[structs.Topic][]string
+
+	// Namespace restricts namespaced events to a single namespace. Empty
+	// matches every namespace.
+	Namespace string
+}
+
+// Subscription is a single subscriber's view of the event stream. Events
+// are filtered to the subscribed topics, keys and namespace.
+type Subscription struct {
+	broker *EventBroker
+	req    *SubscribeRequest
+
+	// eventCh delivers filtered event batches to the subscriber
+	eventCh chan *structs.Events
+
+	// closeCh is closed by the broker when the subscriber falls behind
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func newSubscription(broker *EventBroker, req *SubscribeRequest) *Subscription {
+	return &Subscription{
+		broker:  broker,
+		req:     req,
+		eventCh: make(chan *structs.Events, subscriptionBufferSize),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Next blocks until the next batch of matching events is available, the
+// context is canceled, or the broker closes the subscription.
+func (s *Subscription) Next(ctx context.Context) (*structs.Events, error) {
+	select {
+	case events := <-s.eventCh:
+		return events, nil
+	case <-s.closeCh:
+		return nil, ErrSubscriptionClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Unsubscribe removes the subscription from the broker.
+func (s *Subscription) Unsubscribe() {
+	s.broker.unsubscribe(s)
+	s.forceClose()
+}
+
+// send filters a published batch and queues any matching events. It
+// returns false if the subscription buffer is full.
+func (s *Subscription) send(events *structs.Events) bool {
+	filtered := filterEvents(s.req, events)
+	if filtered == nil {
+		return true
+	}
+
+	select {
+	case s.eventCh <- filtered:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Subscription) forceClose() {
+	s.once.Do(func() { close(s.closeCh) })
+}
+
+// filterEvents returns the subset of a batch matching the subscription, or
+// nil if no event matches.
+func filterEvents(req *SubscribeRequest, events *structs.Events) *structs.Events {
+	var matched []structs.Event
+	for _, event := range events.Events {
+		if eventMatches(req, event) {
+			matched = append(matched, event)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	return &structs.Events{Index: events.Index, Events: matched}
+}
+
+func eventMatches(req *SubscribeRequest, event structs.Event) bool {
+	if req.Namespace != "" && event.Namespace != "" && req.Namespace != event.Namespace {
+		return false
+	}
+
+	keys, ok := req.Topics[event.Topic]
+	if !ok {
+		keys, ok = req.Topics[structs.TopicAll]
+		if !ok {
+			return false
+		}
+	}
+
+	if len(keys) == 0 {
+		return true
+	}
+	for _, key := range keys {
+		if key == string(structs.TopicAll) || key == event.Key {
+			return true
+		}
+		for _, filterKey := range event.FilterKeys {
+			if key == filterKey {
+				return true
+			}
+		}
+	}
+	return false
+}
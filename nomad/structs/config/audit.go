@@ -30,7 +30,7 @@ type AuditSink struct {
 	// be met in order to successfully make requests
 	DeliveryGuarantee string `hcl:"delivery_guarantee"`
 
-	// Type is the sink type to configure. (file)
+	// Type is the sink type to configure. (file, syslog, tcp)
 	Type string `hcl:"type"`
 
 	// Format is the sink output format. (json)
@@ -40,6 +40,24 @@ type AuditSink struct {
 	// If rotation is enabled the pattern will be name-timestamp.log
 	Path string `hcl:"path"`
 
+	// Address is the remote address that tcp sinks stream to
+	Address string `hcl:"address"`
+
+	// TLS enables TLS for tcp sinks
+	TLS bool `hcl:"tls"`
+
+	// CAFile is the path to a CA certificate used to verify the remote
+	// address for tcp sinks with TLS enabled
+	CAFile string `hcl:"ca_file"`
+
+	// BufferSize is the number of events a tcp sink holds in memory
+	// while the remote is unreachable
+	BufferSize int `hcl:"buffer_size"`
+
+	// Facility is the syslog facility for syslog sinks. Defaults to
+	// LOCAL0.
+	Facility string `hcl:"facility"`
+
 	// RotateDuration is the time period that logs should be rotated in
 	RotateDuration    time.Duration
 	RotateDurationHCL string `hcl:"rotate_duration" json:"-"`
@@ -65,8 +83,15 @@ type AuditFilter struct {
 	// State is the auditing request lifecycle stage to filter
 	Stages []string `hcl:"stages"`
 
-	// Operations is the type of operation to filter, such as GET, DELETE
+	// Operations is the type of operation to filter, such as GET, DELETE.
+	// The special values "read-only" (GET, HEAD, OPTIONS) and "operator"
+	// (all other verbs) match classes of operations.
 	Operations []string `hcl:"operations"`
+
+	// SampleRate is the fraction of matching events, between 0 and 1,
+	// that are still written to the sinks. The default of 0 excludes all
+	// matching events.
+	SampleRate float64 `hcl:"sample_rate"`
 }
 
 // Copy returns a new copy of an AuditConfig
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// JobDefaults is operator configuration for default job values that are
+// merged into submitted jobs at admission when the job does not specify
+// them. Only the configured fields override the built-in defaults, and the
+// merged values are persisted with the job so they are visible in job
+// inspect.
+type JobDefaults struct {
+	// Restart overrides the default task group restart policy
+	Restart *RestartDefaults `hcl:"restart"`
+
+	// Reschedule overrides the default task group reschedule policy
+	Reschedule *RescheduleDefaults `hcl:"reschedule"`
+
+	// Update overrides the default update strategy for service jobs
+	Update *UpdateDefaults `hcl:"update"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// RestartDefaults are default restart policy values. Unset fields fall back
+// to the built-in defaults for the job type.
+type RestartDefaults struct {
+	Attempts *int `hcl:"attempts"`
+
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	Delay    time.Duration `hcl:"-"`
+	DelayHCL string        `hcl:"delay" json:"-"`
+
+	Mode string `hcl:"mode"`
+}
+
+// RescheduleDefaults are default reschedule policy values. Unset fields fall
+// back to the built-in defaults for the job type.
+type RescheduleDefaults struct {
+	Attempts *int `hcl:"attempts"`
+
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	Delay    time.Duration `hcl:"-"`
+	DelayHCL string        `hcl:"delay" json:"-"`
+
+	DelayFunction string `hcl:"delay_function"`
+
+	MaxDelay    time.Duration `hcl:"-"`
+	MaxDelayHCL string        `hcl:"max_delay" json:"-"`
+
+	Unlimited *bool `hcl:"unlimited"`
+}
+
+// UpdateDefaults are default update strategy values. Unset fields fall back
+// to the built-in defaults.
+type UpdateDefaults struct {
+	Stagger    time.Duration `hcl:"-"`
+	StaggerHCL string        `hcl:"stagger" json:"-"`
+
+	MaxParallel *int `hcl:"max_parallel"`
+
+	HealthCheck string `hcl:"health_check"`
+
+	MinHealthyTime    time.Duration `hcl:"-"`
+	MinHealthyTimeHCL string        `hcl:"min_healthy_time" json:"-"`
+
+	HealthyDeadline    time.Duration `hcl:"-"`
+	HealthyDeadlineHCL string        `hcl:"healthy_deadline" json:"-"`
+
+	ProgressDeadline    time.Duration `hcl:"-"`
+	ProgressDeadlineHCL string        `hcl:"progress_deadline" json:"-"`
+
+	AutoRevert *bool `hcl:"auto_revert"`
+
+	AutoPromote *bool `hcl:"auto_promote"`
+
+	Canary *int `hcl:"canary"`
+}
+
+// Copy returns a new copy of a JobDefaults
+func (d *JobDefaults) Copy() *JobDefaults {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(JobDefaults)
+	*nd = *d
+	nd.Restart = d.Restart.Copy()
+	nd.Reschedule = d.Reschedule.Copy()
+	nd.Update = d.Update.Copy()
+	return nd
+}
+
+// Merge is used to merge two JobDefaults together. Settings from the input
+// take precedence.
+func (d *JobDefaults) Merge(b *JobDefaults) *JobDefaults {
+	result := d.Copy()
+	if b == nil {
+		return result
+	}
+	if result == nil {
+		return b.Copy()
+	}
+
+	if b.Restart != nil {
+		result.Restart = b.Restart.Copy()
+	}
+	if b.Reschedule != nil {
+		result.Reschedule = b.Reschedule.Copy()
+	}
+	if b.Update != nil {
+		result.Update = b.Update.Copy()
+	}
+	return result
+}
+
+func (d *RestartDefaults) Copy() *RestartDefaults {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(RestartDefaults)
+	*nd = *d
+	if d.Attempts != nil {
+		nd.Attempts = helper.IntToPtr(*d.Attempts)
+	}
+	return nd
+}
+
+func (d *RescheduleDefaults) Copy() *RescheduleDefaults {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(RescheduleDefaults)
+	*nd = *d
+	if d.Attempts != nil {
+		nd.Attempts = helper.IntToPtr(*d.Attempts)
+	}
+	if d.Unlimited != nil {
+		nd.Unlimited = helper.BoolToPtr(*d.Unlimited)
+	}
+	return nd
+}
+
+func (d *UpdateDefaults) Copy() *UpdateDefaults {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(UpdateDefaults)
+	*nd = *d
+	if d.MaxParallel != nil {
+		nd.MaxParallel = helper.IntToPtr(*d.MaxParallel)
+	}
+	if d.AutoRevert != nil {
+		nd.AutoRevert = helper.BoolToPtr(*d.AutoRevert)
+	}
+	if d.AutoPromote != nil {
+		nd.AutoPromote = helper.BoolToPtr(*d.AutoPromote)
+	}
+	if d.Canary != nil {
+		nd.Canary = helper.IntToPtr(*d.Canary)
+	}
+	return nd
+}
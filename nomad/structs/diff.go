@@ -132,6 +132,16 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// Ownership diff
+	if oDiff := primitiveObjectDiff(j.Ownership, other.Ownership, nil, "Ownership", contextual); oDiff != nil {
+		diff.Objects = append(diff.Objects, oDiff)
+	}
+
+	// Expiration diff
+	if eDiff := primitiveObjectDiff(j.Expiration, other.Expiration, nil, "Expiration", contextual); eDiff != nil {
+		diff.Objects = append(diff.Objects, eDiff)
+	}
+
 	// Multiregion diff
 	if mrDiff := multiregionDiff(j.Multiregion, other.Multiregion, contextual); mrDiff != nil {
 		diff.Objects = append(diff.Objects, mrDiff)
@@ -833,6 +843,117 @@ func connectGatewayDiff(prev, next *ConsulGateway, contextual bool) *ObjectDiff
 		diff.Objects = append(diff.Objects, gatewayIngressDiff)
 	}
 
+	// Diff the ConsulGatewayTerminating fields.
+	gatewayTerminatingDiff := connectGatewayTerminatingDiff(prev.Terminating, next.Terminating, contextual)
+	if gatewayTerminatingDiff != nil {
+		diff.Objects = append(diff.Objects, gatewayTerminatingDiff)
+	}
+
+	// Diff the ConsulGatewayMesh fields.
+	gatewayMeshDiff := connectGatewayMeshDiff(prev.Mesh, next.Mesh, contextual)
+	if gatewayMeshDiff != nil {
+		diff.Objects = append(diff.Objects, gatewayMeshDiff)
+	}
+
+	return diff
+}
+
+func connectGatewayTerminatingDiff(prev, next *ConsulTerminatingConfigEntry, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Terminating"}
+
+	if reflect.DeepEqual(prev, next) {
+		return nil
+	} else if prev == nil {
+		prev = new(ConsulTerminatingConfigEntry)
+		diff.Type = DiffTypeAdded
+	} else if next == nil {
+		next = new(ConsulTerminatingConfigEntry)
+		diff.Type = DiffTypeDeleted
+	} else {
+		diff.Type = DiffTypeEdited
+	}
+
+	// Diff the linked service lists.
+	if diffs := connectGatewayTerminatingServicesDiff(prev.Services, next.Services, contextual); diffs != nil {
+		diff.Objects = append(diff.Objects, diffs...)
+	}
+
+	return diff
+}
+
+// connectGatewayTerminatingServicesDiff diffs are a set of linked services keyed
+// by their service name, which is a workaround for having slices instead of maps.
+// Presumably the service name is a unique key, because if not the config entry is
+// not going to make sense anyway.
+func connectGatewayTerminatingServicesDiff(prev, next []*ConsulLinkedService, contextual bool) []*ObjectDiff {
+
+	prevMap := make(map[string]*ConsulLinkedService, len(prev))
+	nextMap := make(map[string]*ConsulLinkedService, len(next))
+
+	for _, s := range prev {
+		prevMap[s.Name] = s
+	}
+	for _, s := range next {
+		nextMap[s.Name] = s
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldLS := range prevMap {
+		// Diff the same, deleted, and edited
+		if diff := connectGatewayTerminatingServiceDiff(oldLS, nextMap[name], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for name, newLS := range nextMap {
+		// Diff the added
+		if old, ok := prevMap[name]; !ok {
+			if diff := connectGatewayTerminatingServiceDiff(old, newLS, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+func connectGatewayTerminatingServiceDiff(prev, next *ConsulLinkedService, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Service"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(prev, next) {
+		return nil
+	} else if prev == nil {
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(next, nil, true)
+	} else if next == nil {
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(prev, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(prev, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(next, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	return diff
+}
+
+// connectGatewayMeshDiff is only ever add or remove, as the mesh config entry
+// has no fields of its own.
+func connectGatewayMeshDiff(prev, next *ConsulMeshConfigEntry, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Mesh"}
+
+	if reflect.DeepEqual(prev, next) {
+		return nil
+	} else if prev == nil {
+		diff.Type = DiffTypeAdded
+	} else if next == nil {
+		diff.Type = DiffTypeDeleted
+	}
+
 	return diff
 }
 
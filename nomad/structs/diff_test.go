@@ -159,6 +159,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "false",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "GCThreshold",
+						Old:  "0",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Meta[foo]",
@@ -189,6 +195,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "false",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Suspended",
+						Old:  "false",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Type",
@@ -228,6 +240,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "false",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "GCThreshold",
+						Old:  "",
+						New:  "0",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Meta[foo]",
@@ -258,6 +276,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "false",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Suspended",
+						Old:  "",
+						New:  "false",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Type",
@@ -2759,6 +2783,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "TaskName",
@@ -5033,6 +5063,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "bar",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "TaskName",
@@ -5169,6 +5205,10 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "PortLabel",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "TaskName",
@@ -5668,6 +5708,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "TaskName",
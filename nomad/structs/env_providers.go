@@ -0,0 +1,83 @@
+package structs
+
+import (
+	"github.com/hashicorp/nomad/helper"
+)
+
+// ClientEnvProviderConfig is used to configure a task environment provider on
+// a Nomad client. Env providers inject additional environment variables and
+// files into tasks at start.
+type ClientEnvProviderConfig struct {
+	// Name of the registered env provider.
+	Name string `hcl:",key"`
+
+	// Namespaces is the allow list of namespaces the provider applies to. An
+	// empty list allows all namespaces.
+	Namespaces []string `hcl:"namespaces"`
+
+	// Config is provider-specific configuration.
+	Config map[string]string `hcl:"config"`
+}
+
+// AllowsNamespace returns whether the provider applies to tasks in the passed
+// namespace.
+func (c *ClientEnvProviderConfig) AllowsNamespace(namespace string) bool {
+	if len(c.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range c.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ClientEnvProviderConfig) Copy() *ClientEnvProviderConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(ClientEnvProviderConfig)
+	*nc = *c
+	nc.Namespaces = helper.CopySliceString(nc.Namespaces)
+	nc.Config = helper.CopyMapStringString(nc.Config)
+	return nc
+}
+
+func CopySliceClientEnvProviderConfig(s []*ClientEnvProviderConfig) []*ClientEnvProviderConfig {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*ClientEnvProviderConfig, l)
+	for idx, cfg := range s {
+		ns[idx] = cfg.Copy()
+	}
+
+	return ns
+}
+
+func EnvProviderSliceMerge(a, b []*ClientEnvProviderConfig) []*ClientEnvProviderConfig {
+	n := make([]*ClientEnvProviderConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
@@ -0,0 +1,82 @@
+package structs
+
+// Topic is an event stream topic. Each event published to the event broker
+// is tagged with the topic of the object it describes.
+type Topic string
+
+const (
+	TopicJob        Topic = "Job"
+	TopicEval       Topic = "Eval"
+	TopicAlloc      Topic = "Alloc"
+	TopicDeployment Topic = "Deployment"
+	TopicNode       Topic = "Node"
+	TopicLicense    Topic = "License"
+
+	// TopicAll is a wildcard that matches every topic
+	TopicAll Topic = "*"
+)
+
+// Event types describe the change an event records, such as a job being
+// registered or a node draining.
+const (
+	TypeJobRegistered          = "JobRegistered"
+	TypeJobDeregistered        = "JobDeregistered"
+	TypeEvalUpdated            = "EvalUpdated"
+	TypeAllocUpdated           = "AllocUpdated"
+	TypeDeploymentUpdated      = "DeploymentUpdated"
+	TypeDeploymentPromoted     = "DeploymentPromoted"
+	TypeDeploymentAllocHealth  = "DeploymentAllocHealth"
+	TypeNodeRegistered         = "NodeRegistered"
+	TypeNodeDeregistered       = "NodeDeregistered"
+	TypeNodeStatusUpdated      = "NodeStatusUpdated"
+	TypeNodeDrainUpdated       = "NodeDrainUpdated"
+	TypeNodeEligibilityUpdated = "NodeEligibilityUpdated"
+	TypeLicenseUpserted        = "LicenseUpserted"
+	TypeLicenseDeleted         = "LicenseDeleted"
+)
+
+// Event is a single state change published to the event broker.
+type Event struct {
+	// Topic is the topic the event belongs to, such as Job or Node
+	Topic Topic
+
+	// Type describes the change, such as JobRegistered
+	Type string
+
+	// Key is the identifier of the object the event describes, such as a
+	// job ID or node ID
+	Key string
+
+	// FilterKeys are additional identifiers subscribers may filter on,
+	// such as the job ID of an allocation
+	FilterKeys []string
+
+	// Namespace is the namespace of the object, if it is namespaced
+	Namespace string
+
+	// Index is the raft index at which the change was applied
+	Index uint64
+
+	// Payload is the object the event describes
+	Payload interface{}
+}
+
+// Events is a set of events published together at a single raft index.
+type Events struct {
+	Index  uint64
+	Events []Event
+}
+
+// EventStreamRequest is used to subscribe to the event stream via the
+// Event.Stream streaming RPC.
+type EventStreamRequest struct {
+	// Topics is the set of topics to subscribe to, mapped to the object
+	// keys of interest. The wildcard "*" matches every topic or key.
+	Topics map[Topic][]string
+
+	// Index is the raft index to resume the stream from. Buffered events
+	// with a higher index are replayed before new events are streamed.
+	Index uint64
+
+	QueryOptions
+}
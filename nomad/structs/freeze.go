@@ -0,0 +1,133 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+)
+
+// FreezeWindow declares a recurring change-freeze window during which
+// non-emergency job registrations in the covered namespaces are rejected.
+type FreezeWindow struct {
+	// Name is a unique human readable name for the window
+	Name string
+
+	// Namespaces is the set of namespaces covered by the window. An empty
+	// set covers all namespaces.
+	Namespaces []string
+
+	// Spec is a cron specification for when the window opens, using the
+	// same format as periodic jobs
+	Spec string
+
+	// Duration is how long the window stays open after each trigger
+	Duration time.Duration
+
+	// Enabled allows a window to be retained but inactive
+	Enabled bool
+}
+
+// Validate is used to sanity check a freeze window.
+func (f *FreezeWindow) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("missing freeze window name")
+	}
+	if _, err := cronexpr.Parse(f.Spec); err != nil {
+		return fmt.Errorf("invalid cron expression %q for freeze window %q: %v", f.Spec, f.Name, err)
+	}
+	if f.Duration <= 0 {
+		return fmt.Errorf("freeze window %q must have a positive duration", f.Name)
+	}
+	return nil
+}
+
+// Active returns whether the window is open at the given time for the given
+// namespace.
+func (f *FreezeWindow) Active(t time.Time, namespace string) bool {
+	if !f.Enabled {
+		return false
+	}
+	if len(f.Namespaces) != 0 {
+		covered := false
+		for _, ns := range f.Namespaces {
+			if ns == namespace {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	e, err := cronexpr.Parse(f.Spec)
+	if err != nil {
+		return false
+	}
+
+	// Find the last trigger at or before t by walking forward from t minus
+	// the window duration. If that trigger's window contains t, the window
+	// is open.
+	next := e.Next(t.Add(-f.Duration))
+	for !next.IsZero() && !next.After(t) {
+		if t.Before(next.Add(f.Duration)) {
+			return true
+		}
+		next = e.Next(next)
+	}
+	return false
+}
+
+// FreezeWindows is the set of declared change-freeze windows for the
+// cluster.
+type FreezeWindows struct {
+	Windows []*FreezeWindow
+
+	// CreateIndex/ModifyIndex store the create/modify indexes of this set.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate is used to sanity check the full set of freeze windows.
+func (f *FreezeWindows) Validate() error {
+	names := make(map[string]struct{}, len(f.Windows))
+	for _, w := range f.Windows {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[w.Name]; ok {
+			return fmt.Errorf("duplicate freeze window name %q", w.Name)
+		}
+		names[w.Name] = struct{}{}
+	}
+	return nil
+}
+
+// ActiveWindow returns the first window that is open at the given time for
+// the given namespace, or nil if none are.
+func (f *FreezeWindows) ActiveWindow(t time.Time, namespace string) *FreezeWindow {
+	if f == nil {
+		return nil
+	}
+	for _, w := range f.Windows {
+		if w.Active(t, namespace) {
+			return w
+		}
+	}
+	return nil
+}
+
+// FreezeWindowsResponse is used to return the current freeze windows.
+type FreezeWindowsResponse struct {
+	FreezeWindows
+
+	QueryMeta
+}
+
+// FreezeWindowsSetRequest is used to replace the declared freeze windows.
+type FreezeWindowsSetRequest struct {
+	Windows []*FreezeWindow
+
+	WriteRequest
+}
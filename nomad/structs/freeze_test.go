@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeWindow_Validate(t *testing.T) {
+	w := &FreezeWindow{}
+	err := w.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing freeze window name")
+
+	w.Name = "weekend"
+	w.Spec = "not a cron"
+	err = w.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid cron expression")
+
+	w.Spec = "0 0 * * 6"
+	err = w.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "positive duration")
+
+	w.Duration = 48 * time.Hour
+	require.NoError(t, w.Validate())
+
+	windows := &FreezeWindows{Windows: []*FreezeWindow{w, w}}
+	err = windows.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate freeze window name")
+}
+
+func TestFreezeWindow_Active(t *testing.T) {
+	// Freeze every Saturday at midnight for 48 hours
+	w := &FreezeWindow{
+		Name:     "weekend",
+		Spec:     "0 0 * * 6",
+		Duration: 48 * time.Hour,
+		Enabled:  true,
+	}
+
+	saturday := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2020, 8, 5, 12, 0, 0, 0, time.UTC)
+
+	require.True(t, w.Active(saturday, "default"))
+	require.False(t, w.Active(wednesday, "default"))
+
+	// Disabled windows are never active
+	w.Enabled = false
+	require.False(t, w.Active(saturday, "default"))
+	w.Enabled = true
+
+	// Namespace scoping
+	w.Namespaces = []string{"prod"}
+	require.False(t, w.Active(saturday, "default"))
+	require.True(t, w.Active(saturday, "prod"))
+}
+
+func TestFreezeWindows_ActiveWindow(t *testing.T) {
+	saturday := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	var none *FreezeWindows
+	require.Nil(t, none.ActiveWindow(saturday, "default"))
+
+	windows := &FreezeWindows{
+		Windows: []*FreezeWindow{
+			{
+				Name:       "prod-weekend",
+				Namespaces: []string{"prod"},
+				Spec:       "0 0 * * 6",
+				Duration:   48 * time.Hour,
+				Enabled:    true,
+			},
+		},
+	}
+
+	require.Nil(t, windows.ActiveWindow(saturday, "default"))
+
+	active := windows.ActiveWindow(saturday, "prod")
+	require.NotNil(t, active)
+	require.Equal(t, "prod-weekend", active.Name)
+}
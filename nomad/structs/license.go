@@ -0,0 +1,288 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StoredLicense is the raft-replicated representation of the cluster license
+// as provided by an operator.
+type StoredLicense struct {
+	// Signed is the raw signed license blob
+	Signed string
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// License is the parsed form of a signed license blob.
+type License struct {
+	// The unique identifier of the license
+	LicenseID string
+
+	// The customer ID associated with the license
+	CustomerID string
+
+	// If set, an identifier that should be used to lock the license to a
+	// particular site, cluster, etc.
+	InstallationID string
+
+	// The time at which the license was issued
+	IssueTime time.Time
+
+	// The time at which the license starts being valid
+	StartTime time.Time
+
+	// The time after which the license expires
+	ExpirationTime time.Time
+
+	// The time at which the license ceases to function and can
+	// no longer be used in any capacity
+	TerminationTime time.Time
+
+	// The product the license is valid for
+	Product string
+
+	// License Specific Flags
+	Flags map[string]interface{}
+
+	// Modules is a list of the licensed enterprise modules
+	Modules []string
+
+	// List of features enabled by the license
+	Features []string
+}
+
+// HasFeature returns whether the license enables the given feature.
+func (l *License) HasFeature(feature string) bool {
+	if l == nil {
+		return false
+	}
+	for _, f := range l.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// LicenseFeatureMultiregion is the license feature that multiregion jobs
+	// require in every region they deploy to.
+	LicenseFeatureMultiregion = "multiregion"
+
+	// LicenseFeatureSentinel is the license feature that Sentinel policies
+	// require.
+	LicenseFeatureSentinel = "sentinel"
+)
+
+// RequiredLicenseFeatures returns the license features the job depends on.
+func (j *Job) RequiredLicenseFeatures() []string {
+	var features []string
+	if j.IsMultiregion() {
+		features = append(features, LicenseFeatureMultiregion)
+	}
+	return features
+}
+
+// DevLicense returns an in-memory license enabling every feature, used by
+// dev mode agents so demos and integration tests need no license setup. It
+// is never stored and a license applied by an operator takes precedence.
+func DevLicense() *License {
+	now := time.Now()
+	return &License{
+		LicenseID:       "dev-license",
+		CustomerID:      "dev",
+		IssueTime:       now,
+		StartTime:       now,
+		ExpirationTime:  now.AddDate(10, 0, 0),
+		TerminationTime: now.AddDate(10, 0, 0),
+		Product:         "nomad",
+		Features: []string{
+			LicenseFeatureMultiregion,
+			LicenseFeatureSentinel,
+		},
+	}
+}
+
+// ParseLicense parses a signed license blob into a License. The blob is a
+// base64 encoded JSON payload.
+func ParseLicense(signed string) (*License, error) {
+	raw, err := base64.StdEncoding.DecodeString(signed)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding license: %v", err)
+	}
+
+	license := &License{}
+	if err := json.Unmarshal(raw, license); err != nil {
+		return nil, fmt.Errorf("error parsing license: %v", err)
+	}
+
+	if license.LicenseID == "" {
+		return nil, fmt.Errorf("license is missing a license id")
+	}
+
+	return license, nil
+}
+
+const (
+	// LicenseEventActionApply is recorded when a license is applied
+	LicenseEventActionApply = "apply"
+
+	// LicenseEventActionReset is recorded when a license is removed
+	LicenseEventActionReset = "reset"
+)
+
+// LicenseEvent records a change to the stored cluster license so operators
+// can audit when entitlements changed and by whom.
+type LicenseEvent struct {
+	// Action is the change that occurred: apply or reset
+	Action string
+
+	// AccessorID is the accessor ID of the ACL token that requested the
+	// change, if any
+	AccessorID string
+
+	// OldLicenseID is the ID of the license being replaced or removed
+	OldLicenseID string
+
+	// NewLicenseID is the ID of the newly applied license
+	NewLicenseID string
+
+	// Time is the unix nanosecond timestamp the change was requested
+	Time int64
+
+	// Raft index at which the event was recorded
+	CreateIndex uint64
+}
+
+// LicenseUpsertRequest is used to set the cluster license.
+type LicenseUpsertRequest struct {
+	License *StoredLicense
+
+	// AccessorID is the accessor ID of the requesting ACL token, recorded
+	// in the license audit trail
+	AccessorID string
+
+	// Time is the unix nanosecond timestamp the request was made. It is set
+	// by the servers so the audit trail is deterministic across the raft
+	// peers.
+	Time int64
+
+	WriteRequest
+}
+
+// LicenseGetRequest is used to request the current cluster license.
+type LicenseGetRequest struct {
+	QueryOptions
+}
+
+// LicenseGetResponse returns the stored and parsed forms of the cluster
+// license.
+type LicenseGetResponse struct {
+	License *License
+
+	// Signed is the raw blob the license was parsed from
+	Signed string
+
+	QueryMeta
+}
+
+// LicenseDeleteRequest is used to remove the stored cluster license so the
+// cluster falls back to a temporary license.
+type LicenseDeleteRequest struct {
+	// AccessorID is the accessor ID of the requesting ACL token, recorded
+	// in the license audit trail
+	AccessorID string
+
+	// Time is the unix nanosecond timestamp the request was made
+	Time int64
+
+	WriteRequest
+}
+
+// LicenseReportRequest is used to request the per-region license report.
+type LicenseReportRequest struct {
+	QueryOptions
+}
+
+// RegionLicense is the license state of a single federated region.
+type RegionLicense struct {
+	// Region is the name of the federated region
+	Region string
+
+	// License is the region's parsed license, or nil when the region has
+	// none
+	License *License
+
+	// Error is set when the region's license could not be retrieved
+	Error string
+}
+
+// LicenseReportResponse returns the aggregated per-region license report.
+type LicenseReportResponse struct {
+	// Regions holds one entry per federated region, sorted by region name
+	Regions []*RegionLicense
+
+	// FeatureDeltas maps each feature that is licensed in some regions but
+	// not all of them to the sorted list of regions missing it
+	FeatureDeltas map[string][]string
+
+	// Warnings describe multiregion jobs that require a license feature
+	// missing in one of their target regions
+	Warnings []string
+
+	QueryMeta
+}
+
+// LicensePreviewRequest is used to dry-run a candidate license against the
+// state store without applying it.
+type LicensePreviewRequest struct {
+	License *StoredLicense
+
+	QueryOptions
+}
+
+// LicenseImpactedObject identifies an object in the state store that
+// requires a license feature the candidate license does not include.
+type LicenseImpactedObject struct {
+	// Type is the kind of object, e.g. "job" or "sentinel policy"
+	Type string
+
+	// ID identifies the object, e.g. the job ID or policy name
+	ID string
+
+	// Namespace is set for namespaced objects
+	Namespace string
+
+	// Feature is the required license feature the candidate license lacks
+	Feature string
+}
+
+// LicensePreviewResponse reports what would become unsupported if the
+// candidate license were applied.
+type LicensePreviewResponse struct {
+	// License is the parsed form of the candidate license
+	License *License
+
+	// Unsupported lists the objects whose required features are missing
+	// from the candidate license
+	Unsupported []*LicenseImpactedObject
+
+	QueryMeta
+}
+
+// LicenseHistoryRequest is used to request the license audit trail.
+type LicenseHistoryRequest struct {
+	QueryOptions
+}
+
+// LicenseHistoryResponse returns the license audit trail, oldest first.
+type LicenseHistoryResponse struct {
+	Events []*LicenseEvent
+
+	QueryMeta
+}
@@ -8,10 +8,10 @@ import (
 )
 
 const (
-	// MinDynamicPort is the smallest dynamic port generated
+	// MinDynamicPort is the smallest dynamic port generated by default
 	MinDynamicPort = 20000
 
-	// MaxDynamicPort is the largest dynamic port generated
+	// MaxDynamicPort is the largest dynamic port generated by default
 	MaxDynamicPort = 32000
 
 	// maxRandPortAttempts is the maximum number of attempt
@@ -39,6 +39,8 @@ type NetworkIndex struct {
 	AvailBandwidth map[string]int                  // Bandwidth by device
 	UsedPorts      map[string]Bitmap               // Ports by IP
 	UsedBandwidth  map[string]int                  // Bandwidth by device
+	MinDynamicPort int                             // The smallest dynamic port generated
+	MaxDynamicPort int                             // The largest dynamic port generated
 }
 
 // NewNetworkIndex is used to construct a new network index
@@ -48,6 +50,8 @@ func NewNetworkIndex() *NetworkIndex {
 		AvailBandwidth: make(map[string]int),
 		UsedPorts:      make(map[string]Bitmap),
 		UsedBandwidth:  make(map[string]int),
+		MinDynamicPort: MinDynamicPort,
+		MaxDynamicPort: MaxDynamicPort,
 	}
 }
 
@@ -105,6 +109,14 @@ func (idx *NetworkIndex) SetNode(node *Node) (collide bool) {
 		nodeNetworks = node.NodeResources.NodeNetworks
 	}
 
+	// Use the node's configured dynamic port range if it has one.
+	if node.NodeResources != nil && node.NodeResources.MinDynamicPort > 0 {
+		idx.MinDynamicPort = node.NodeResources.MinDynamicPort
+	}
+	if node.NodeResources != nil && node.NodeResources.MaxDynamicPort > 0 {
+		idx.MaxDynamicPort = node.NodeResources.MaxDynamicPort
+	}
+
 	// Add the available CIDR blocks
 	for _, n := range networks {
 		if n.Device != "" {
@@ -367,10 +379,10 @@ func (idx *NetworkIndex) AssignPorts(ask *NetworkResource) (AllocatedPorts, erro
 			// lower memory usage.
 			var dynPorts []int
 			// TODO: its more efficient to find multiple dynamic ports at once
-			dynPorts, addrErr = getDynamicPortsStochastic(used, reservedIdx[port.HostNetwork], 1)
+			dynPorts, addrErr = idx.getDynamicPortsStochastic(used, reservedIdx[port.HostNetwork], 1)
 			if addrErr != nil {
 				// Fall back to the precise method if the random sampling failed.
-				dynPorts, addrErr = getDynamicPortsPrecise(used, reservedIdx[port.HostNetwork], 1)
+				dynPorts, addrErr = idx.getDynamicPortsPrecise(used, reservedIdx[port.HostNetwork], 1)
 				if addrErr != nil {
 					continue
 				}
@@ -449,13 +461,13 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 		// lower memory usage.
 		var dynPorts []int
 		var dynErr error
-		dynPorts, dynErr = getDynamicPortsStochastic(used, ask.ReservedPorts, len(ask.DynamicPorts))
+		dynPorts, dynErr = idx.getDynamicPortsStochastic(used, ask.ReservedPorts, len(ask.DynamicPorts))
 		if dynErr == nil {
 			goto BUILD_OFFER
 		}
 
 		// Fall back to the precise method if the random sampling failed.
-		dynPorts, dynErr = getDynamicPortsPrecise(used, ask.ReservedPorts, len(ask.DynamicPorts))
+		dynPorts, dynErr = idx.getDynamicPortsPrecise(used, ask.ReservedPorts, len(ask.DynamicPorts))
 		if dynErr != nil {
 			err = dynErr
 			return
@@ -480,11 +492,21 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 	return
 }
 
+// AvailableDynamicPorts returns the number of unused ports in the dynamic
+// port range for the given IP.
+func (idx *NetworkIndex) AvailableDynamicPorts(ip string) int {
+	used := idx.UsedPorts[ip]
+	if used == nil {
+		return idx.MaxDynamicPort - idx.MinDynamicPort + 1
+	}
+	return len(used.IndexesInRange(false, uint(idx.MinDynamicPort), uint(idx.MaxDynamicPort)))
+}
+
 // getDynamicPortsPrecise takes the nodes used port bitmap which may be nil if
 // no ports have been allocated yet, the network ask and returns a set of unused
 // ports to fulfil the ask's DynamicPorts or an error if it failed. An error
 // means the ask can not be satisfied as the method does a precise search.
-func getDynamicPortsPrecise(nodeUsed Bitmap, reserved []Port, numDyn int) ([]int, error) {
+func (idx *NetworkIndex) getDynamicPortsPrecise(nodeUsed Bitmap, reserved []Port, numDyn int) ([]int, error) {
 	// Create a copy of the used ports and apply the new reserves
 	var usedSet Bitmap
 	var err error
@@ -505,7 +527,7 @@ func getDynamicPortsPrecise(nodeUsed Bitmap, reserved []Port, numDyn int) ([]int
 	}
 
 	// Get the indexes of the unset
-	availablePorts := usedSet.IndexesInRange(false, MinDynamicPort, MaxDynamicPort)
+	availablePorts := usedSet.IndexesInRange(false, uint(idx.MinDynamicPort), uint(idx.MaxDynamicPort))
 
 	// Randomize the amount we need
 	if len(availablePorts) < numDyn {
@@ -526,7 +548,7 @@ func getDynamicPortsPrecise(nodeUsed Bitmap, reserved []Port, numDyn int) ([]int
 // ports to fulfil the ask's DynamicPorts or an error if it failed. An error
 // does not mean the ask can not be satisfied as the method has a fixed amount
 // of random probes and if these fail, the search is aborted.
-func getDynamicPortsStochastic(nodeUsed Bitmap, reservedPorts []Port, count int) ([]int, error) {
+func (idx *NetworkIndex) getDynamicPortsStochastic(nodeUsed Bitmap, reservedPorts []Port, count int) ([]int, error) {
 	var reserved, dynamic []int
 	for _, port := range reservedPorts {
 		reserved = append(reserved, port.Value)
@@ -540,7 +562,7 @@ func getDynamicPortsStochastic(nodeUsed Bitmap, reservedPorts []Port, count int)
 			return nil, fmt.Errorf("stochastic dynamic port selection failed")
 		}
 
-		randPort := MinDynamicPort + rand.Intn(MaxDynamicPort-MinDynamicPort)
+		randPort := idx.MinDynamicPort + rand.Intn(idx.MaxDynamicPort-idx.MinDynamicPort)
 		if nodeUsed != nil && nodeUsed.Check(uint(randPort)) {
 			goto PICK
 		}
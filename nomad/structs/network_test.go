@@ -87,6 +87,50 @@ func TestNetworkIndex_SetNode(t *testing.T) {
 	}
 }
 
+func TestNetworkIndex_SetNode_DynamicPortRange(t *testing.T) {
+	idx := NewNetworkIndex()
+	if idx.MinDynamicPort != MinDynamicPort || idx.MaxDynamicPort != MaxDynamicPort {
+		t.Fatalf("bad default range: %d-%d", idx.MinDynamicPort, idx.MaxDynamicPort)
+	}
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			Networks: []*NetworkResource{
+				{
+					Device: "eth0",
+					CIDR:   "192.168.0.100/32",
+					IP:     "192.168.0.100",
+					MBits:  1000,
+				},
+			},
+			MinDynamicPort: 1000,
+			MaxDynamicPort: 2000,
+		},
+	}
+	if collide := idx.SetNode(n); collide {
+		t.Fatalf("bad")
+	}
+
+	if idx.MinDynamicPort != 1000 || idx.MaxDynamicPort != 2000 {
+		t.Fatalf("bad range: %d-%d", idx.MinDynamicPort, idx.MaxDynamicPort)
+	}
+
+	// The network offer should only assign ports from the node's range.
+	ask := &NetworkResource{
+		DynamicPorts: []Port{{"http", 0, 0, ""}},
+	}
+	offer, err := idx.AssignNetwork(ask)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if offer == nil {
+		t.Fatalf("bad")
+	}
+	if p := offer.DynamicPorts[0].Value; p < 1000 || p > 2000 {
+		t.Fatalf("Dynamic port %d out of range 1000-2000", p)
+	}
+}
+
 func TestNetworkIndex_AddAllocs(t *testing.T) {
 	idx := NewNetworkIndex()
 	allocs := []*Allocation{
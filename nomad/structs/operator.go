@@ -149,6 +149,12 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig `hcl:"preemption_config"`
 
+	// NamespaceAdmissionWeights optionally weights how often evaluations
+	// from each namespace are dequeued by the eval broker. Namespaces
+	// without an entry default to a weight of 1. When empty, evaluations
+	// are dequeued strictly by priority and age.
+	NamespaceAdmissionWeights map[string]float64 `hcl:"namespace_admission_weights"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -179,6 +185,12 @@ func (s *SchedulerConfiguration) Validate() error {
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
+	for ns, weight := range s.NamespaceAdmissionWeights {
+		if weight <= 0 {
+			return fmt.Errorf("invalid admission weight %v for namespace %q: must be greater than zero", weight, ns)
+		}
+	}
+
 	return nil
 }
 
@@ -225,6 +237,80 @@ type SchedulerSetConfigRequest struct {
 	WriteRequest
 }
 
+// FeatureMultiregionDeployments gates the federated multiregion deployment
+// machinery. While the flag is disabled, jobs with a multiregion stanza are
+// rejected at registration time.
+const FeatureMultiregionDeployments = "multiregion-deployments"
+
+// FeatureNamespaces gates creation and deletion of namespaces. While the
+// flag is disabled only the default namespace is usable, but namespaces
+// created while the flag was enabled remain queryable.
+const FeatureNamespaces = "namespaces"
+
+// KnownFeatureFlags is the registry of feature flags servers accept, mapped
+// to the default value used when a flag has not been toggled. Behaviors that
+// need a migration window (e.g. a new cron library or changed GC semantics)
+// register their flag here and remove it once the window closes.
+var KnownFeatureFlags = map[string]bool{
+	FeatureMultiregionDeployments: false,
+	FeatureNamespaces:             false,
+}
+
+// FeatureFlags is the cluster-wide set of feature flag overrides. It allows
+// operators to toggle registered behaviors at runtime instead of relying on
+// compile-time defaults alone.
+type FeatureFlags struct {
+	// Flags maps feature names to whether the feature is enabled. Flags that
+	// are not present fall back to their default in KnownFeatureFlags.
+	Flags map[string]bool
+
+	// CreateIndex/ModifyIndex store the create/modify indexes of the flag set.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// FeatureEnabled returns whether the named feature is enabled, falling back
+// to the feature's registered default when it has not been toggled.
+func (f *FeatureFlags) FeatureEnabled(name string) bool {
+	if f != nil {
+		if enabled, ok := f.Flags[name]; ok {
+			return enabled
+		}
+	}
+	return KnownFeatureFlags[name]
+}
+
+func (f *FeatureFlags) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	for name := range f.Flags {
+		if _, ok := KnownFeatureFlags[name]; !ok {
+			return fmt.Errorf("unknown feature flag: %q", name)
+		}
+	}
+
+	return nil
+}
+
+// FeatureFlagsResponse is the response object that wraps FeatureFlags
+type FeatureFlagsResponse struct {
+	FeatureFlags *FeatureFlags
+
+	QueryMeta
+}
+
+// FeatureFlagsSetRequest is used by the Operator endpoint to update the
+// cluster-wide feature flags.
+type FeatureFlagsSetRequest struct {
+	// Flags is the new set of feature flag overrides.
+	Flags map[string]bool
+
+	// WriteRequest holds the ACL token to go along with this request.
+	WriteRequest
+}
+
 // SnapshotSaveRequest is used by the Operator endpoint to get a Raft snapshot
 type SnapshotSaveRequest struct {
 	QueryOptions
@@ -257,3 +343,135 @@ type SnapshotRestoreResponse struct {
 
 	QueryMeta
 }
+
+// ClusterHeadroomRequest is used to ask the servers how many instances of a
+// hypothetical task shape could still be placed on the cluster's ready
+// nodes.
+type ClusterHeadroomRequest struct {
+	// CPU is the amount of CPU in MHz required per instance.
+	CPU int64
+
+	// MemoryMB is the amount of memory in MB required per instance.
+	MemoryMB int64
+
+	// Ports is the number of dynamic ports required per instance.
+	Ports int
+
+	QueryOptions
+}
+
+// ClusterHeadroomResponse is the response to a headroom query.
+type ClusterHeadroomResponse struct {
+	// Headroom groups the fit counts by datacenter and node class.
+	Headroom []*ClusterHeadroomEntry
+
+	// Total is the number of instances that fit cluster wide.
+	Total int
+
+	QueryMeta
+}
+
+// ClusterHeadroomEntry is the headroom of the nodes in a single datacenter
+// and node class.
+type ClusterHeadroomEntry struct {
+	Datacenter string
+	NodeClass  string
+
+	// Nodes is the number of ready nodes in the group.
+	Nodes int
+
+	// Fit is the number of instances of the requested shape that fit on
+	// the group's nodes.
+	Fit int
+}
+
+// ClusterMetricsSample is a point in time snapshot of key cluster gauges,
+// kept by the servers in a short in-memory history.
+type ClusterMetricsSample struct {
+	// Timestamp is when the sample was taken.
+	Timestamp time.Time
+
+	// Nodes counts the cluster's nodes by node status.
+	Nodes map[string]int
+
+	// Allocs counts the cluster's allocations by client status.
+	Allocs map[string]int
+
+	// EvalBacklog is the number of evaluations waiting to be scheduled.
+	EvalBacklog int
+
+	// BlockedEvals is the number of evaluations blocked on resources.
+	BlockedEvals int
+
+	// LicenseDaysRemaining is the number of days until the cluster license
+	// expires, or zero if no license is stored.
+	LicenseDaysRemaining float64
+}
+
+// MetricsHistoryRequest is used to query the in-memory history of cluster
+// gauges on the servers.
+type MetricsHistoryRequest struct {
+	// Since limits the response to samples taken at or after the given
+	// time, expressed in Unix nanoseconds. Zero returns all retained
+	// samples.
+	Since int64
+
+	QueryOptions
+}
+
+// MetricsHistoryResponse is the response to a metrics history query.
+type MetricsHistoryResponse struct {
+	// Samples are the retained gauge samples, oldest first.
+	Samples []*ClusterMetricsSample
+
+	// Interval is how often the servers take a sample.
+	Interval time.Duration
+
+	// Retention is how much history the servers keep.
+	Retention time.Duration
+
+	QueryMeta
+}
+
+// VaultStatus describes the health of a server's Vault integration,
+// covering token renewal and the token revocation backlog.
+type VaultStatus struct {
+	// Enabled is whether the Vault integration is enabled.
+	Enabled bool
+
+	// Active is whether the server is actively managing Vault tokens. Only
+	// the leader's Vault client is active.
+	Active bool
+
+	// Connected is whether a connection with the Vault cluster has been
+	// established.
+	Connected bool
+
+	// ConnectionError is the error from the latest connection attempt, if
+	// any.
+	ConnectionError string
+
+	// RevocationBacklog is the number of token accessors tracked for
+	// revocation because they could not be immediately revoked.
+	RevocationBacklog int
+
+	// TokenTTL is the time-to-live of the server's own Vault token.
+	TokenTTL time.Duration
+
+	// TokenExpiry is when the server's own Vault token expires, refreshed
+	// as the token is renewed.
+	TokenExpiry time.Time
+}
+
+// VaultStatusRequest is used to query the status of the server's Vault
+// integration.
+type VaultStatusRequest struct {
+	QueryOptions
+}
+
+// VaultStatusResponse is the response to a Vault status query.
+type VaultStatusResponse struct {
+	Status *VaultStatus
+
+	QueryMeta
+}
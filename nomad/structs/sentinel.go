@@ -0,0 +1,331 @@
+package structs
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// SentinelScopeSubmitJob is the scope for policies that are evaluated
+	// when a job is submitted
+	SentinelScopeSubmitJob = "submit-job"
+
+	// SentinelScopeNodeDrain is the scope for policies that are evaluated
+	// when a node drain is requested
+	SentinelScopeNodeDrain = "node-drain"
+
+	// SentinelScopeVolumeRegister is the scope for policies that are
+	// evaluated when a CSI volume is registered
+	SentinelScopeVolumeRegister = "volume-register"
+
+	// SentinelScopeQuota is the scope for policies that are evaluated when
+	// a quota specification is changed. Quotas only exist in Nomad
+	// Enterprise, so the enforcement hook for this scope lives there.
+	SentinelScopeQuota = "quota"
+)
+
+const (
+	// SentinelEnforcementLevelAdvisory policies only issue a warning when
+	// they fail
+	SentinelEnforcementLevelAdvisory = "advisory"
+
+	// SentinelEnforcementLevelSoftMandatory policies prevent the operation
+	// unless an override is requested
+	SentinelEnforcementLevelSoftMandatory = "soft-mandatory"
+
+	// SentinelEnforcementLevelHardMandatory policies prevent the operation
+	// and cannot be overridden
+	SentinelEnforcementLevelHardMandatory = "hard-mandatory"
+)
+
+// SentinelPolicy is used to represent a Sentinel policy
+type SentinelPolicy struct {
+	Name             string // Unique name
+	Description      string // Human readable
+	Scope            string // Where should this policy be executed
+	EnforcementLevel string // Enforcement Level
+	Policy           string // Sentinel policy
+	Hash             []byte
+	CreateIndex      uint64
+	ModifyIndex      uint64
+}
+
+// SetHash is used to compute and set the hash of the Sentinel policy
+func (s *SentinelPolicy) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(s.Name))
+	hash.Write([]byte(s.Description))
+	hash.Write([]byte(s.Scope))
+	hash.Write([]byte(s.EnforcementLevel))
+	hash.Write([]byte(s.Policy))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	s.Hash = hashVal
+	return hashVal
+}
+
+func (s *SentinelPolicy) Stub() *SentinelPolicyListStub {
+	return &SentinelPolicyListStub{
+		Name:             s.Name,
+		Description:      s.Description,
+		Scope:            s.Scope,
+		EnforcementLevel: s.EnforcementLevel,
+		Hash:             s.Hash,
+		CreateIndex:      s.CreateIndex,
+		ModifyIndex:      s.ModifyIndex,
+	}
+}
+
+func (s *SentinelPolicy) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(s.Name) {
+		err := fmt.Errorf("invalid name '%s'", s.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch s.Scope {
+	case SentinelScopeSubmitJob, SentinelScopeNodeDrain,
+		SentinelScopeVolumeRegister, SentinelScopeQuota:
+	default:
+		err := fmt.Errorf("invalid scope '%s'", s.Scope)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch s.EnforcementLevel {
+	case SentinelEnforcementLevelAdvisory, SentinelEnforcementLevelSoftMandatory, SentinelEnforcementLevelHardMandatory:
+	default:
+		err := fmt.Errorf("invalid enforcement level '%s'", s.EnforcementLevel)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if s.Policy == "" {
+		err := fmt.Errorf("missing policy")
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(s.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SentinelPolicyListStub is used to for listing Sentinel policies
+type SentinelPolicyListStub struct {
+	Name             string
+	Description      string
+	Scope            string
+	EnforcementLevel string
+	Hash             []byte
+	CreateIndex      uint64
+	ModifyIndex      uint64
+}
+
+// SentinelPolicyListRequest is used to request a list of policies
+type SentinelPolicyListRequest struct {
+	QueryOptions
+}
+
+// SentinelPolicySpecificRequest is used to query a specific policy
+type SentinelPolicySpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SentinelPolicySetRequest is used to query a set of policies
+type SentinelPolicySetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// SentinelPolicyListResponse is used for a list request
+type SentinelPolicyListResponse struct {
+	Policies []*SentinelPolicyListStub
+	QueryMeta
+}
+
+// SingleSentinelPolicyResponse is used to return a single policy
+type SingleSentinelPolicyResponse struct {
+	Policy *SentinelPolicy
+	QueryMeta
+}
+
+// SentinelPolicySetResponse is used to return a set of policies
+type SentinelPolicySetResponse struct {
+	Policies map[string]*SentinelPolicy
+	QueryMeta
+}
+
+// SentinelOverride records a job submission that overrode soft-mandatory
+// Sentinel policies so that overrides can be audited later
+type SentinelOverride struct {
+	// Policies are the names of the soft-mandatory policies that were
+	// overridden
+	Policies []string
+
+	// JobID and Namespace identify the job that was submitted
+	JobID     string
+	Namespace string
+
+	// AccessorID is the accessor of the ACL token that requested the
+	// override, if ACLs are enabled
+	AccessorID string
+
+	// Time is the UnixNano timestamp at which the override was requested
+	Time int64
+
+	// CreateIndex is the raft index at which the override was recorded
+	CreateIndex uint64
+}
+
+// SentinelOverrideListRequest is used to list the recorded overrides
+type SentinelOverrideListRequest struct {
+	QueryOptions
+}
+
+// SentinelOverrideListResponse is used to return the recorded overrides
+type SentinelOverrideListResponse struct {
+	Overrides []*SentinelOverride
+	QueryMeta
+}
+
+// SentinelPolicyTestRequest is used to dry-run a policy against a job
+// without submitting either
+type SentinelPolicyTestRequest struct {
+	Policy *SentinelPolicy
+	Job    *Job
+	WriteRequest
+}
+
+// SentinelPolicyTestResponse is used to return the result of a dry-run
+type SentinelPolicyTestResponse struct {
+	// Passed indicates whether the policy allowed the job
+	Passed bool
+
+	// Trace holds the execution trace emitted by the policy runtime to aid
+	// policy authors in debugging failures
+	Trace string
+}
+
+// SentinelPolicyDeleteRequest is used to delete a set of policies
+type SentinelPolicyDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// SentinelPolicyUpsertRequest is used to upsert a set of policies
+type SentinelPolicyUpsertRequest struct {
+	Policies []*SentinelPolicy
+	WriteRequest
+}
+
+// SentinelModule is a reusable Sentinel source unit that policies can pull in
+// with `import "modules/<name>"`. Modules are stored in the state store so
+// imports resolve from raft-replicated content rather than only built-ins.
+type SentinelModule struct {
+	Name        string // Unique name, referenced by imports
+	Description string // Human readable
+	Source      string // Sentinel module source
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the Sentinel module
+func (m *SentinelModule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(m.Name))
+	hash.Write([]byte(m.Description))
+	hash.Write([]byte(m.Source))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	m.Hash = hashVal
+	return hashVal
+}
+
+func (m *SentinelModule) Stub() *SentinelModuleListStub {
+	return &SentinelModuleListStub{
+		Name:        m.Name,
+		Description: m.Description,
+		Hash:        m.Hash,
+		CreateIndex: m.CreateIndex,
+		ModifyIndex: m.ModifyIndex,
+	}
+}
+
+func (m *SentinelModule) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(m.Name) {
+		err := fmt.Errorf("invalid name '%s'", m.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if m.Source == "" {
+		err := fmt.Errorf("missing source")
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(m.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SentinelModuleListStub is used to for listing Sentinel modules
+type SentinelModuleListStub struct {
+	Name        string
+	Description string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SentinelModuleListRequest is used to request a list of modules
+type SentinelModuleListRequest struct {
+	QueryOptions
+}
+
+// SentinelModuleSpecificRequest is used to query a specific module
+type SentinelModuleSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SentinelModuleListResponse is used for a list request
+type SentinelModuleListResponse struct {
+	Modules []*SentinelModuleListStub
+	QueryMeta
+}
+
+// SingleSentinelModuleResponse is used to return a single module
+type SingleSentinelModuleResponse struct {
+	Module *SentinelModule
+	QueryMeta
+}
+
+// SentinelModuleDeleteRequest is used to delete a set of modules
+type SentinelModuleDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// SentinelModuleUpsertRequest is used to upsert a set of modules
+type SentinelModuleUpsertRequest struct {
+	Modules []*SentinelModule
+	WriteRequest
+}
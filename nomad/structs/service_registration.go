@@ -0,0 +1,163 @@
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// ServiceRegistration is a service registered with the Nomad servers by a
+// client on behalf of an allocation, providing basic service discovery for
+// clusters that do not run Consul.
+type ServiceRegistration struct {
+	// ID is the unique identifier of the registration. It is generated by
+	// the client in a deterministic manner so registrations can be removed
+	// without a server round trip.
+	ID string
+
+	// ServiceName is the human friendly name of the service as specified
+	// within the job service stanza.
+	ServiceName string
+
+	// Namespace is the namespace of the job the service belongs to.
+	Namespace string
+
+	// NodeID is the node the allocation is placed on.
+	NodeID string
+
+	// Datacenter is the datacenter of the node.
+	Datacenter string
+
+	// JobID is the ID of the job the service belongs to.
+	JobID string
+
+	// AllocID is the allocation the service instance belongs to.
+	AllocID string
+
+	// Tags are the tags of the service stanza.
+	Tags []string
+
+	// Address is the IP address the service is reachable on.
+	Address string
+
+	// Port is the port number the service is reachable on.
+	Port int
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy creates a deep copy of the service registration.
+func (s *ServiceRegistration) Copy() *ServiceRegistration {
+	ns := new(ServiceRegistration)
+	*ns = *s
+	ns.Tags = helper.CopySliceString(ns.Tags)
+	return ns
+}
+
+// Equals performs an equality check on the two service registrations. It
+// ignores the raft indexes.
+func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
+	if s.ID != o.ID ||
+		s.ServiceName != o.ServiceName ||
+		s.Namespace != o.Namespace ||
+		s.NodeID != o.NodeID ||
+		s.Datacenter != o.Datacenter ||
+		s.JobID != o.JobID ||
+		s.AllocID != o.AllocID ||
+		s.Address != o.Address ||
+		s.Port != o.Port {
+		return false
+	}
+	return helper.CompareSliceSetString(s.Tags, o.Tags)
+}
+
+// Validate ensures the service registration contains everything needed to
+// store and discover it.
+func (s *ServiceRegistration) Validate() error {
+	switch {
+	case s.ID == "":
+		return fmt.Errorf("missing service registration ID")
+	case s.ServiceName == "":
+		return fmt.Errorf("missing service name")
+	case s.NodeID == "":
+		return fmt.Errorf("missing node ID")
+	case s.AllocID == "":
+		return fmt.Errorf("missing allocation ID")
+	case s.Address == "":
+		return fmt.Errorf("missing address")
+	case s.Port == 0:
+		return fmt.Errorf("missing port")
+	}
+	return nil
+}
+
+// Stub produces the list representation of the registration.
+func (s *ServiceRegistration) Stub() *ServiceRegistrationStub {
+	return &ServiceRegistrationStub{
+		ServiceName: s.ServiceName,
+		Tags:        helper.CopySliceString(s.Tags),
+	}
+}
+
+// ServiceRegistrationStub is the list representation of a service name and
+// the union of the tags its instances carry.
+type ServiceRegistrationStub struct {
+	ServiceName string
+	Tags        []string
+}
+
+// ServiceRegistrationUpsertRequest is used by clients to register the
+// services of an allocation.
+type ServiceRegistrationUpsertRequest struct {
+	// Services are the registrations to upsert, keyed on their ID.
+	Services []*ServiceRegistration
+
+	WriteRequest
+}
+
+// ServiceRegistrationUpsertResponse is the response to an upsert.
+type ServiceRegistrationUpsertResponse struct {
+	WriteMeta
+}
+
+// ServiceRegistrationDeleteByIDRequest removes a single registration.
+type ServiceRegistrationDeleteByIDRequest struct {
+	ID string
+
+	WriteRequest
+}
+
+// ServiceRegistrationDeleteByIDResponse is the response to a delete.
+type ServiceRegistrationDeleteByIDResponse struct {
+	WriteMeta
+}
+
+// ServiceRegistrationListRequest lists the known service names within the
+// request namespace.
+type ServiceRegistrationListRequest struct {
+	QueryOptions
+}
+
+// ServiceRegistrationListResponse returns one stub per service name.
+type ServiceRegistrationListResponse struct {
+	Services []*ServiceRegistrationStub
+
+	QueryMeta
+}
+
+// ServiceRegistrationByNameRequest returns every registration of the named
+// service within the request namespace.
+type ServiceRegistrationByNameRequest struct {
+	ServiceName string
+
+	QueryOptions
+}
+
+// ServiceRegistrationByNameResponse is the response to a service lookup.
+type ServiceRegistrationByNameResponse struct {
+	Services []*ServiceRegistration
+
+	QueryMeta
+}
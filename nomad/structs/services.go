@@ -416,8 +416,23 @@ type Service struct {
 	Connect    *ConsulConnect    // Consul Connect configuration
 	Meta       map[string]string // Consul service meta
 	CanaryMeta map[string]string // Consul service meta when it is a canary
+
+	// Provider is the backend the service is registered with. Defaults to
+	// Consul.
+	Provider string
 }
 
+const (
+	// ServiceProviderConsul is the default service provider and the way
+	// Nomad worked before native service discovery.
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad is the native service discovery provider, which
+	// registers services with the Nomad servers instead of Consul. It does
+	// not support checks or Connect.
+	ServiceProviderNomad = "nomad"
+)
+
 // Copy the stanza recursively. Returns nil if nil.
 func (s *Service) Copy() *Service {
 	if s == nil {
@@ -466,6 +481,11 @@ func (s *Service) Canonicalize(job string, taskGroup string, task string) {
 		"BASE":      fmt.Sprintf("%s-%s-%s", job, taskGroup, task),
 	})
 
+	// Default to the Consul service provider
+	if s.Provider == "" {
+		s.Provider = ServiceProviderConsul
+	}
+
 	for _, check := range s.Checks {
 		check.Canonicalize(s.Name)
 	}
@@ -491,6 +511,21 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service address_mode must be %q, %q, or %q; not %q", AddressModeAuto, AddressModeHost, AddressModeDriver, s.AddressMode))
 	}
 
+	switch s.Provider {
+	case "", ServiceProviderConsul:
+		// OK
+	case ServiceProviderNomad:
+		// The native provider does not support checks or Connect
+		if len(s.Checks) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service with provider %q cannot have checks", ServiceProviderNomad))
+		}
+		if s.Connect != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service with provider %q cannot use Consul Connect", ServiceProviderNomad))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service provider must be %q or %q; not %q", ServiceProviderConsul, ServiceProviderNomad, s.Provider))
+	}
+
 	// check checks
 	for _, c := range s.Checks {
 		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
@@ -550,6 +585,7 @@ func (s *Service) Hash(allocID, taskName string, canary bool) string {
 	hashString(h, s.Name)
 	hashString(h, s.PortLabel)
 	hashString(h, s.AddressMode)
+	hashString(h, s.Provider)
 	hashTags(h, s.Tags)
 	hashTags(h, s.CanaryTags)
 	hashBool(h, canary, "Canary")
@@ -648,6 +684,10 @@ OUTER:
 		return false
 	}
 
+	if s.Provider != o.Provider {
+		return false
+	}
+
 	if !reflect.DeepEqual(s.Meta, o.Meta) {
 		return false
 	}
@@ -1169,11 +1209,25 @@ type ConsulGateway struct {
 	// Ingress represents the Consul Configuration Entry for an Ingress Gateway.
 	Ingress *ConsulIngressConfigEntry
 
-	// Terminating is not yet supported.
-	// Terminating *ConsulTerminatingConfigEntry
+	// Terminating represents the Consul Configuration Entry for a Terminating
+	// Gateway.
+	Terminating *ConsulTerminatingConfigEntry
 
-	// Mesh is not yet supported.
-	// Mesh *ConsulMeshConfigEntry
+	// Mesh indicates the Consul service should be a Mesh Gateway.
+	Mesh *ConsulMeshConfigEntry
+}
+
+// Prefix returns the task kind prefix associated with the type of gateway
+// being configured, e.g. "connect-ingress".
+func (g *ConsulGateway) Prefix() string {
+	switch {
+	case g.Mesh != nil:
+		return ConnectMeshPrefix
+	case g.Ingress != nil:
+		return ConnectIngressPrefix
+	default:
+		return ConnectTerminatingPrefix
+	}
 }
 
 func (g *ConsulGateway) Copy() *ConsulGateway {
@@ -1182,8 +1236,10 @@ func (g *ConsulGateway) Copy() *ConsulGateway {
 	}
 
 	return &ConsulGateway{
-		Proxy:   g.Proxy.Copy(),
-		Ingress: g.Ingress.Copy(),
+		Proxy:       g.Proxy.Copy(),
+		Ingress:     g.Ingress.Copy(),
+		Terminating: g.Terminating.Copy(),
+		Mesh:        g.Mesh.Copy(),
 	}
 }
 
@@ -1200,6 +1256,14 @@ func (g *ConsulGateway) Equals(o *ConsulGateway) bool {
 		return false
 	}
 
+	if !g.Terminating.Equals(o.Terminating) {
+		return false
+	}
+
+	if !g.Mesh.Equals(o.Mesh) {
+		return false
+	}
+
 	return true
 }
 
@@ -1214,12 +1278,31 @@ func (g *ConsulGateway) Validate() error {
 		}
 	}
 
-	// eventually one of: ingress, terminating, mesh
+	// exactly one of ingress, terminating, mesh must be set
+	count := 0
 	if g.Ingress != nil {
+		count++
+	}
+	if g.Terminating != nil {
+		count++
+	}
+	if g.Mesh != nil {
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("One Consul Gateway Configuration Entry must be set")
+	}
+
+	switch {
+	case g.Ingress != nil:
 		return g.Ingress.Validate()
+	case g.Terminating != nil:
+		return g.Terminating.Validate()
+	case g.Mesh != nil:
+		return g.Mesh.Validate()
 	}
 
-	return fmt.Errorf("Consul Gateway ingress Configuration Entry must be set")
+	return nil
 }
 
 // ConsulGatewayBindAddress is equivalent to Consul's api/catalog.go ServiceAddress
@@ -1609,3 +1692,175 @@ COMPARE: // order does not matter
 	}
 	return true
 }
+
+// ConsulLinkedService is used to configure a service fronted by the terminating
+// gateway.
+type ConsulLinkedService struct {
+	Name     string
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	SNI      string
+}
+
+func (s *ConsulLinkedService) Copy() *ConsulLinkedService {
+	if s == nil {
+		return nil
+	}
+
+	return &ConsulLinkedService{
+		Name:     s.Name,
+		CAFile:   s.CAFile,
+		CertFile: s.CertFile,
+		KeyFile:  s.KeyFile,
+		SNI:      s.SNI,
+	}
+}
+
+func (s *ConsulLinkedService) Equals(o *ConsulLinkedService) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+
+	switch {
+	case s.Name != o.Name:
+		return false
+	case s.CAFile != o.CAFile:
+		return false
+	case s.CertFile != o.CertFile:
+		return false
+	case s.KeyFile != o.KeyFile:
+		return false
+	case s.SNI != o.SNI:
+		return false
+	}
+
+	return true
+}
+
+func (s *ConsulLinkedService) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Name == "" {
+		return fmt.Errorf("Consul Linked Service requires Name")
+	}
+
+	caSet := s.CAFile != ""
+	certSet := s.CertFile != ""
+	keySet := s.KeyFile != ""
+	sniSet := s.SNI != ""
+
+	if (certSet || keySet) && !caSet {
+		return fmt.Errorf("Consul Linked Service TLS requires CAFile")
+	}
+
+	if certSet != keySet {
+		return fmt.Errorf("Consul Linked Service TLS Cert and Key must both be set")
+	}
+
+	if sniSet && !caSet {
+		return fmt.Errorf("Consul Linked Service TLS SNI requires CAFile")
+	}
+
+	return nil
+}
+
+func linkedServicesEqual(servicesA, servicesB []*ConsulLinkedService) bool {
+	if len(servicesA) != len(servicesB) {
+		return false
+	}
+
+COMPARE: // order does not matter
+	for _, serviceA := range servicesA {
+		for _, serviceB := range servicesB {
+			if serviceA.Equals(serviceB) {
+				continue COMPARE
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ConsulTerminatingConfigEntry represents the Consul Configuration Entry type
+// for a Terminating Gateway.
+//
+// https://www.consul.io/docs/agent/config-entries/terminating-gateway#available-fields
+type ConsulTerminatingConfigEntry struct {
+	// Namespace is not yet supported.
+	// Namespace string
+
+	Services []*ConsulLinkedService
+}
+
+func (e *ConsulTerminatingConfigEntry) Copy() *ConsulTerminatingConfigEntry {
+	if e == nil {
+		return nil
+	}
+
+	var services []*ConsulLinkedService = nil
+	if n := len(e.Services); n > 0 {
+		services = make([]*ConsulLinkedService, n)
+		for i := 0; i < n; i++ {
+			services[i] = e.Services[i].Copy()
+		}
+	}
+
+	return &ConsulTerminatingConfigEntry{
+		Services: services,
+	}
+}
+
+func (e *ConsulTerminatingConfigEntry) Equals(o *ConsulTerminatingConfigEntry) bool {
+	if e == nil || o == nil {
+		return e == o
+	}
+
+	return linkedServicesEqual(e.Services, o.Services)
+}
+
+func (e *ConsulTerminatingConfigEntry) Validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if len(e.Services) == 0 {
+		return fmt.Errorf("Consul Terminating Gateway requires at least one service")
+	}
+
+	for _, service := range e.Services {
+		if err := service.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConsulMeshConfigEntry is a stub used to represent that the gateway service
+// type should be for a Mesh Gateway. Unlike Ingress and Terminating, there is no
+// actual Consul Config Entry type for mesh-gateway, at least for now.
+type ConsulMeshConfigEntry struct {
+	// nothing in here
+}
+
+func (e *ConsulMeshConfigEntry) Copy() *ConsulMeshConfigEntry {
+	if e == nil {
+		return nil
+	}
+	return new(ConsulMeshConfigEntry)
+}
+
+func (e *ConsulMeshConfigEntry) Equals(o *ConsulMeshConfigEntry) bool {
+	if e == nil || o == nil {
+		return e == o
+	}
+	// both are non-nil, always equal
+	return true
+}
+
+func (e *ConsulMeshConfigEntry) Validate() error {
+	return nil
+}
@@ -697,6 +697,203 @@ func TestConsulGateway_Validate(t *testing.T) {
 		}).Validate()
 		require.EqualError(t, err, "Consul Ingress Gateway requires at least one listener")
 	})
+
+	t.Run("bad terminating config entry", func(t *testing.T) {
+		err := (&ConsulGateway{
+			Terminating: &ConsulTerminatingConfigEntry{
+				Services: nil,
+			},
+		}).Validate()
+		require.EqualError(t, err, "Consul Terminating Gateway requires at least one service")
+	})
+
+	t.Run("no config entry set", func(t *testing.T) {
+		err := (&ConsulGateway{
+			Ingress:     nil,
+			Terminating: nil,
+			Mesh:        nil,
+		}).Validate()
+		require.EqualError(t, err, "One Consul Gateway Configuration Entry must be set")
+	})
+
+	t.Run("multiple config entries set", func(t *testing.T) {
+		err := (&ConsulGateway{
+			Ingress: &ConsulIngressConfigEntry{
+				Listeners: []*ConsulIngressListener{{
+					Port: 1111,
+				}},
+			},
+			Terminating: &ConsulTerminatingConfigEntry{
+				Services: []*ConsulLinkedService{{
+					Name: "linked-service1",
+				}},
+			},
+		}).Validate()
+		require.EqualError(t, err, "One Consul Gateway Configuration Entry must be set")
+	})
+
+	t.Run("as mesh", func(t *testing.T) {
+		err := (&ConsulGateway{
+			Mesh: &ConsulMeshConfigEntry{},
+		}).Validate()
+		require.NoError(t, err)
+	})
+}
+
+func TestConsulLinkedService_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		err := (*ConsulLinkedService)(nil).Validate()
+		require.Nil(t, err)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		err := (&ConsulLinkedService{}).Validate()
+		require.EqualError(t, err, "Consul Linked Service requires Name")
+	})
+
+	t.Run("missing ca_file", func(t *testing.T) {
+		err := (&ConsulLinkedService{
+			Name:     "linked-service1",
+			CertFile: "cert_file.pem",
+			KeyFile:  "key_file.pem",
+		}).Validate()
+		require.EqualError(t, err, "Consul Linked Service TLS requires CAFile")
+	})
+
+	t.Run("mutual cert key", func(t *testing.T) {
+		err := (&ConsulLinkedService{
+			Name:     "linked-service1",
+			CAFile:   "ca_file.pem",
+			CertFile: "cert_file.pem",
+		}).Validate()
+		require.EqualError(t, err, "Consul Linked Service TLS Cert and Key must both be set")
+	})
+
+	t.Run("sni without ca_file", func(t *testing.T) {
+		err := (&ConsulLinkedService{
+			Name: "linked-service1",
+			SNI:  "service.consul",
+		}).Validate()
+		require.EqualError(t, err, "Consul Linked Service TLS SNI requires CAFile")
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		err := (&ConsulLinkedService{
+			Name: "linked-service1",
+		}).Validate()
+		require.NoError(t, err)
+	})
+
+	t.Run("tls minimal", func(t *testing.T) {
+		err := (&ConsulLinkedService{
+			Name:   "linked-service1",
+			CAFile: "ca_file.pem",
+			SNI:    "service.consul",
+		}).Validate()
+		require.NoError(t, err)
+	})
+}
+
+func TestConsulLinkedService_Copy(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, (*ConsulLinkedService)(nil).Copy())
+	require.Equal(t, &ConsulLinkedService{
+		Name:     "service1",
+		CAFile:   "ca.pem",
+		CertFile: "cert.pem",
+		KeyFile:  "key.pem",
+		SNI:      "service1.consul",
+	}, (&ConsulLinkedService{
+		Name:     "service1",
+		CAFile:   "ca.pem",
+		CertFile: "cert.pem",
+		KeyFile:  "key.pem",
+		SNI:      "service1.consul",
+	}).Copy())
+}
+
+func TestConsulGateway_linkedServicesEqual(t *testing.T) {
+	t.Parallel()
+
+	ls1 := []*ConsulLinkedService{{
+		Name:   "service1",
+		CAFile: "ca.pem",
+	}, {
+		Name:   "service2",
+		CAFile: "ca.pem",
+	}}
+
+	require.False(t, linkedServicesEqual(ls1, nil))
+
+	reversed := []*ConsulLinkedService{
+		ls1[1], ls1[0], // services reversed
+	}
+
+	require.True(t, linkedServicesEqual(ls1, reversed))
+
+	different := []*ConsulLinkedService{
+		ls1[0], {
+			Name:   "service2",
+			CAFile: "ca.pem",
+			SNI:    "service2.consul",
+		},
+	}
+
+	require.False(t, linkedServicesEqual(ls1, different))
+}
+
+func TestConsulTerminatingConfigEntry_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		err := (*ConsulTerminatingConfigEntry)(nil).Validate()
+		require.NoError(t, err)
+	})
+
+	t.Run("no services", func(t *testing.T) {
+		err := (&ConsulTerminatingConfigEntry{}).Validate()
+		require.EqualError(t, err, "Consul Terminating Gateway requires at least one service")
+	})
+
+	t.Run("service invalid", func(t *testing.T) {
+		err := (&ConsulTerminatingConfigEntry{
+			Services: []*ConsulLinkedService{{
+				Name: "",
+			}},
+		}).Validate()
+		require.EqualError(t, err, "Consul Linked Service requires Name")
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		err := (&ConsulTerminatingConfigEntry{
+			Services: []*ConsulLinkedService{{
+				Name: "service1",
+			}},
+		}).Validate()
+		require.NoError(t, err)
+	})
+}
+
+func TestConsulMeshConfigEntry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copy", func(t *testing.T) {
+		require.Nil(t, (*ConsulMeshConfigEntry)(nil).Copy())
+		require.Equal(t, &ConsulMeshConfigEntry{}, (&ConsulMeshConfigEntry{}).Copy())
+	})
+
+	t.Run("equals", func(t *testing.T) {
+		require.True(t, (*ConsulMeshConfigEntry)(nil).Equals(nil))
+		require.False(t, (*ConsulMeshConfigEntry)(nil).Equals(&ConsulMeshConfigEntry{}))
+		require.True(t, (&ConsulMeshConfigEntry{}).Equals(&ConsulMeshConfigEntry{}))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, (&ConsulMeshConfigEntry{}).Validate())
+	})
 }
 
 func TestConsulGatewayBindAddress_Validate(t *testing.T) {
@@ -893,3 +1090,56 @@ func TestConsulIngressConfigEntry_Validate(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestService_Validate_Provider(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{
+		Name:      "testservice",
+		PortLabel: "http",
+	}
+
+	t.Run("empty provider defaults to consul", func(t *testing.T) {
+		s := service.Copy()
+		s.Canonicalize("testjob", "testgroup", "testtask")
+		require.Equal(t, ServiceProviderConsul, s.Provider)
+		require.NoError(t, s.Validate())
+	})
+
+	t.Run("nomad provider", func(t *testing.T) {
+		s := service.Copy()
+		s.Provider = ServiceProviderNomad
+		require.NoError(t, s.Validate())
+	})
+
+	t.Run("nomad provider rejects checks", func(t *testing.T) {
+		s := service.Copy()
+		s.Provider = ServiceProviderNomad
+		s.Checks = []*ServiceCheck{{
+			Name:     "check",
+			Type:     ServiceCheckTCP,
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+		}}
+		err := s.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot have checks")
+	})
+
+	t.Run("nomad provider rejects connect", func(t *testing.T) {
+		s := service.Copy()
+		s.Provider = ServiceProviderNomad
+		s.Connect = &ConsulConnect{Native: true}
+		err := s.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Consul Connect")
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		s := service.Copy()
+		s.Provider = "etcd"
+		err := s.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "provider must be")
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -24,7 +25,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/hashicorp/cronexpr"
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
@@ -37,6 +37,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/args"
 	"github.com/hashicorp/nomad/helper/constraints/semver"
+	"github.com/hashicorp/nomad/helper/crontab"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/kheap"
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
@@ -96,6 +97,31 @@ const (
 	ScalingEventRegisterRequestType
 	CSIVolumeClaimBatchRequestType
 	CSIPluginDeleteRequestType
+	AllocCheckpointMetaSetRequestType
+	LicenseDeleteRequestType
+	FeatureFlagsRequestType
+	LicenseUpsertRequestType
+	FreezeWindowsRequestType
+	SentinelPolicyUpsertRequestType
+	SentinelPolicyDeleteRequestType
+	SentinelModuleUpsertRequestType
+	SentinelModuleDeleteRequestType
+	JobPayloadAppendRequestType
+	NamespaceUpsertRequestType
+	NamespaceDeleteRequestType
+	ACLRoleUpsertRequestType
+	ACLRoleDeleteRequestType
+	ACLAuthMethodUpsertRequestType
+	ACLAuthMethodDeleteRequestType
+	ACLBindingRuleUpsertRequestType
+	ACLBindingRuleDeleteRequestType
+	SigningKeyUpsertRequestType
+	NodeBatchMetaUpdateRequestType
+	ServiceRegistrationUpsertRequestType
+	ServiceRegistrationDeleteByIDRequestType
+	VariablesUpsertRequestType
+	VariablesDeleteRequestType
+	SigningKeyDeleteRequestType
 )
 
 const (
@@ -135,6 +161,12 @@ const (
 	ACLClientToken     = "client"
 	ACLManagementToken = "management"
 
+	// ACLTLSIdentityPrefix is the prefix used to pass a verified TLS
+	// certificate identity in place of an ACL token Secret ID. Identities
+	// are authorized by the server's configured identity to policy mapping
+	// rather than a stored token.
+	ACLTLSIdentityPrefix = "tls:"
+
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace            = "default"
 	DefaultNamespaceDescription = "Default shared namespace"
@@ -254,6 +286,18 @@ type QueryOptions struct {
 	// AuthToken is secret portion of the ACL token used for the request
 	AuthToken string
 
+	// Filter is a go-bexpr expression used to filter the results of a list
+	// query server-side.
+	Filter string
+
+	// PerPage is the maximum number of entries to return per page on a list
+	// query. Zero means no limit.
+	PerPage int32
+
+	// NextToken is the token of the entry a paged list query should resume
+	// at, as returned in the NextToken query meta of the previous page.
+	NextToken string
+
 	InternalRpcInfo
 }
 
@@ -282,6 +326,14 @@ func (q QueryOptions) AllowStaleRead() bool {
 	return q.AllowStale
 }
 
+func (q QueryOptions) GetAuthToken() string {
+	return q.AuthToken
+}
+
+func (q *QueryOptions) SetAuthToken(token string) {
+	q.AuthToken = token
+}
+
 // AgentPprofRequest is used to request a pprof report for a given node.
 type AgentPprofRequest struct {
 	// ReqType specifies the profile to use
@@ -368,6 +420,14 @@ func (w WriteRequest) AllowStaleRead() bool {
 	return false
 }
 
+func (w WriteRequest) GetAuthToken() string {
+	return w.AuthToken
+}
+
+func (w *WriteRequest) SetAuthToken(token string) {
+	w.AuthToken = token
+}
+
 // QueryMeta allows a query response to include potentially
 // useful metadata about a query
 type QueryMeta struct {
@@ -381,6 +441,10 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is the token of the entry a paged list query should pass as
+	// its NextToken to fetch the next page. It is empty on the last page.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially
@@ -502,6 +566,104 @@ type NodeUpdateEligibilityRequest struct {
 	WriteRequest
 }
 
+// NodeBatchFilter selects the set of nodes a batch node operation applies
+// to. At least one selector must be set; every selector that is set must
+// match for a node to be selected.
+type NodeBatchFilter struct {
+	// Prefix matches nodes whose ID starts with the prefix
+	Prefix string
+
+	// Class matches nodes by their node class
+	Class string
+
+	// Attributes matches nodes whose attributes contain every given value
+	Attributes map[string]string
+}
+
+// Validate ensures the filter selects nodes by at least one criterion.
+func (f *NodeBatchFilter) Validate() error {
+	if f == nil || (f.Prefix == "" && f.Class == "" && len(f.Attributes) == 0) {
+		return fmt.Errorf("missing node filter")
+	}
+	return nil
+}
+
+// Match returns whether the node is selected by the filter.
+func (f *NodeBatchFilter) Match(node *Node) bool {
+	if f.Prefix != "" && !strings.HasPrefix(node.ID, f.Prefix) {
+		return false
+	}
+	if f.Class != "" && node.NodeClass != f.Class {
+		return false
+	}
+	for k, v := range f.Attributes {
+		if node.Attributes[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// NodeBatchDrainUpdateRequest is used to update the drain strategy of every
+// node selected by a filter
+type NodeBatchDrainUpdateRequest struct {
+	// Filter selects the nodes to update
+	Filter *NodeBatchFilter
+
+	// DrainStrategy is the strategy to apply, or nil to disable draining
+	DrainStrategy *DrainStrategy
+
+	// MarkEligible marks the nodes as eligible if removing the drain strategy.
+	MarkEligible bool
+
+	WriteRequest
+}
+
+// NodeBatchEligibilityUpdateRequest is used to update the scheduling
+// eligibility of every node selected by a filter
+type NodeBatchEligibilityUpdateRequest struct {
+	// Filter selects the nodes to update
+	Filter *NodeBatchFilter
+
+	// Eligibility is the new scheduling eligibility
+	Eligibility string
+
+	WriteRequest
+}
+
+// NodeBatchMetaUpdateRequest is used to merge metadata updates into every
+// node selected by a filter
+type NodeBatchMetaUpdateRequest struct {
+	// Filter selects the nodes to update
+	Filter *NodeBatchFilter
+
+	// NodeIDs are the resolved nodes the update applies to. It is set by
+	// the server before the request is committed so the raft log replays
+	// deterministically.
+	NodeIDs []string
+
+	// Meta is merged into each node's metadata. A nil value removes the
+	// key.
+	Meta map[string]*string
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	WriteRequest
+}
+
+// NodeBatchUpdateResponse reports the per-node outcome of a batch node
+// operation.
+type NodeBatchUpdateResponse struct {
+	// Updated are the IDs of the nodes that were updated
+	Updated []string
+
+	// Errors maps the nodes that could not be updated to the reason
+	Errors map[string]string
+
+	WriteMeta
+}
+
 // NodeEvaluateRequest is used to re-evaluate the node
 type NodeEvaluateRequest struct {
 	NodeID string
@@ -544,6 +706,43 @@ type SearchRequest struct {
 	QueryOptions
 }
 
+// IdentifierMatch is a typed prefix match returned by
+// Search.ResolveIdentifier, pairing a matched ID with the context it was
+// found in.
+type IdentifierMatch struct {
+	// Context is the type of the matched object, such as jobs or nodes
+	Context Context
+
+	// ID is the full ID of the matched object
+	ID string
+
+	// Namespace is the namespace of the matched object, if it is
+	// namespaced
+	Namespace string
+}
+
+// ResolveIdentifierRequest is used to resolve an ID prefix across every
+// context in a single query.
+type ResolveIdentifierRequest struct {
+	// Prefix is the ID prefix to resolve
+	Prefix string
+
+	QueryOptions
+}
+
+// ResolveIdentifierResponse returns the typed matches for a prefix across
+// all contexts.
+type ResolveIdentifierResponse struct {
+	// Matches are the typed matches for the prefix
+	Matches []*IdentifierMatch
+
+	// Truncations indicates whether the matches for a particular context
+	// have been truncated
+	Truncations map[Context]bool
+
+	QueryMeta
+}
+
 // JobRegisterRequest is used for Job.Register endpoint
 // to register a job as being a schedulable entity.
 type JobRegisterRequest struct {
@@ -563,9 +762,23 @@ type JobRegisterRequest struct {
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 
+	// FreezeOverride is set when the user is attempting to register during
+	// an active change-freeze window
+	FreezeOverride bool
+
+	// SentinelOverride is set by the leader when PolicyOverride applies to
+	// soft-mandatory policies so the override is recorded for auditing. It
+	// is built at the RPC layer since it captures the submitter and time.
+	SentinelOverride *SentinelOverride
+
 	// Eval is the evaluation that is associated with the job registration
 	Eval *Evaluation
 
+	// Submission is the original source material of the job, stored so it
+	// can be returned verbatim later. It may be nil when the submitter did
+	// not provide it or when the source exceeds JobMaxSourceSize.
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -678,6 +891,20 @@ type JobDispatchRequest struct {
 	WriteRequest
 }
 
+// JobPayloadAppendRequest appends a chunk of a dispatch payload to an
+// already registered dispatched job. Large payloads are committed as a
+// series of these so a single dispatch does not produce an oversized raft
+// entry.
+type JobPayloadAppendRequest struct {
+	// JobID is the ID of the dispatched child job the chunk belongs to
+	JobID string
+
+	// Chunk is the next piece of the compressed payload
+	Chunk []byte
+
+	WriteRequest
+}
+
 // JobValidateRequest is used to validate a job
 type JobValidateRequest struct {
 	Job *Job
@@ -727,11 +954,29 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobSuspendRequest is used to suspend or resume a job. Suspending a job
+// scales every task group to zero while the prior counts remain recoverable
+// from the job's version history.
+type JobSuspendRequest struct {
+	// JobID is the ID of the job being suspended or resumed
+	JobID string
+
+	// Suspend suspends the job when true and resumes it when false
+	Suspend bool
+
+	WriteRequest
+}
+
 // NodeListRequest is used to parameterize a list request
 type NodeListRequest struct {
 	QueryOptions
 }
 
+// NodeInventoryRequest is used to request the fleet OS inventory report
+type NodeInventoryRequest struct {
+	QueryOptions
+}
+
 // EvalUpdateRequest is used for upserting evaluations.
 type EvalUpdateRequest struct {
 	Evals     []*Evaluation
@@ -756,6 +1001,19 @@ type EvalSpecificRequest struct {
 type EvalAckRequest struct {
 	EvalID string
 	Token  string
+
+	// Error is an optional failure reason attached when Nacking so the
+	// scheduler error is visible if the evaluation reaches the delivery
+	// limit
+	Error string
+
+	WriteRequest
+}
+
+// EvalRetryRequest is used by operators to retry or discard an evaluation
+// that reached the delivery limit
+type EvalRetryRequest struct {
+	EvalID string
 	WriteRequest
 }
 
@@ -772,6 +1030,12 @@ type EvalListRequest struct {
 	QueryOptions
 }
 
+// EvalLineageRequest is used to request the evaluation lineage for a job
+type EvalLineageRequest struct {
+	JobID string
+	QueryOptions
+}
+
 // PlanRequest is used to submit an allocation plan to the leader
 type PlanRequest struct {
 	Plan *Plan
@@ -857,6 +1121,22 @@ type AllocUpdateDesiredTransitionRequest struct {
 	WriteRequest
 }
 
+// AllocCheckpointMetaMaxSize is the maximum combined size in bytes of the keys
+// and values of an allocation's checkpoint metadata.
+const AllocCheckpointMetaMaxSize = 4 * 1024
+
+// AllocCheckpointMetaSetRequest is used to replace the checkpoint metadata of
+// an allocation.
+type AllocCheckpointMetaSetRequest struct {
+	AllocID string
+
+	// Meta is the checkpoint metadata to store. It replaces any previously
+	// stored metadata for the allocation.
+	Meta map[string]string
+
+	WriteRequest
+}
+
 // AllocStopRequest is used to stop and reschedule a running Allocation.
 type AllocStopRequest struct {
 	AllocID string
@@ -872,9 +1152,30 @@ type AllocStopResponse struct {
 	WriteMeta
 }
 
+// AllocHoldRequest is used to place an Allocation on hold for debugging or
+// release a previously placed hold.
+type AllocHoldRequest struct {
+	AllocID string
+
+	// Release releases an existing hold instead of placing one.
+	Release bool
+
+	WriteRequest
+}
+
+// AllocHoldResponse is the response to an `AllocHoldRequest`
+type AllocHoldResponse struct {
+	// EvalID is the id of the follow up evalution created for the alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocListRequest is used to request a list of allocations
 type AllocListRequest struct {
 	QueryOptions
+
+	Fields *AllocStubFields
 }
 
 // AllocSpecificRequest is used to query a specific allocation
@@ -911,6 +1212,13 @@ type PeriodicForceRequest struct {
 	WriteRequest
 }
 
+// PeriodicLaunchStatsRequest is used to query the launch timing records of a
+// specific periodic job.
+type PeriodicLaunchStatsRequest struct {
+	JobID string
+	QueryOptions
+}
+
 // ServerMembersResponse has the list of servers in a cluster
 type ServerMembersResponse struct {
 	ServerName   string
@@ -1276,6 +1584,32 @@ type NodeListResponse struct {
 	QueryMeta
 }
 
+// NodeInventoryDrift describes a node whose OS inventory deviates from the
+// fleet baseline
+type NodeInventoryDrift struct {
+	// NodeID and Name identify the drifting node
+	NodeID string
+	Name   string
+
+	// Drift maps inventory attributes to the node's value where it differs
+	// from the fleet baseline. A missing attribute is reported as the empty
+	// string.
+	Drift map[string]string
+}
+
+// NodeInventoryReportResponse returns the fleet OS inventory baseline along
+// with the nodes that drift from it
+type NodeInventoryReportResponse struct {
+	// Baseline maps each inventory attribute to its most common value
+	// across the fleet
+	Baseline map[string]string
+
+	// Drifted lists the nodes that deviate from the baseline
+	Drifted []*NodeInventoryDrift
+
+	QueryMeta
+}
+
 // SingleJobResponse is used to return a single job
 type SingleJobResponse struct {
 	Job *Job
@@ -1341,6 +1675,90 @@ type JobVersionsResponse struct {
 	QueryMeta
 }
 
+// JobSubmission is the original source material of a job as the user
+// submitted it, stored alongside the registered job so it can be returned
+// verbatim later. It is never used by the scheduler.
+type JobSubmission struct {
+	// Source is the original job specification.
+	Source string
+
+	// Format indicates whether Source is "hcl1", "hcl2", or "json".
+	Format string
+
+	// VariableFlags are the HCL2 variable assignments given with -var
+	// flags at submission time.
+	VariableFlags map[string]string
+
+	// Variables is the content of the HCL2 var files given at submission
+	// time.
+	Variables string
+
+	// Namespace, JobID, and Version identify the job version this
+	// submission produced. They are set by the server on registration.
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	// JobModifyIndex is the raft index of the registration.
+	JobModifyIndex uint64
+}
+
+// Copy returns a deep copy of the job submission.
+func (js *JobSubmission) Copy() *JobSubmission {
+	if js == nil {
+		return nil
+	}
+	njs := new(JobSubmission)
+	*njs = *js
+	njs.VariableFlags = helper.CopyMapStringString(js.VariableFlags)
+	return njs
+}
+
+// JobSubmissionRequest is used to query the original source of a job
+// version.
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse contains the original source of a job version, or
+// a nil submission if none was recorded.
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
+// JobDiffRequest is used to compare two stored versions of a job, or the
+// local job against its counterpart in another federated region.
+type JobDiffRequest struct {
+	JobID string
+
+	// FromVersion and ToVersion select the stored versions to compare. When
+	// nil the current version is used.
+	FromVersion *uint64
+	ToVersion   *uint64
+
+	// CompareRegion compares the selected local version against the current
+	// version of the job in the given region instead of ToVersion.
+	CompareRegion string
+
+	QueryOptions
+}
+
+// JobDiffResponse returns the structured diff between the two selected jobs.
+type JobDiffResponse struct {
+	Diff *JobDiff
+
+	// FromVersion and ToVersion are the job versions that were compared. In
+	// a cross-region comparison ToVersion is the remote region's current
+	// version.
+	FromVersion uint64
+	ToVersion   uint64
+
+	QueryMeta
+}
+
 // JobPlanResponse is used to respond to a job plan request
 type JobPlanResponse struct {
 	// Annotations stores annotations explaining decisions the scheduler made.
@@ -1454,6 +1872,64 @@ type EvalListResponse struct {
 	QueryMeta
 }
 
+// EvalBrokerStatsRequest is used to request the leader's eval broker
+// statistics
+type EvalBrokerStatsRequest struct {
+	QueryOptions
+}
+
+// EvalBrokerStatsResponse returns a snapshot of the eval broker queues
+type EvalBrokerStatsResponse struct {
+	TotalReady   int
+	TotalUnacked int
+	TotalBlocked int
+	TotalWaiting int
+	TotalAcks    int
+	TotalNacks   int
+	ByScheduler  map[string]*EvalBrokerSchedulerStats
+	QueryMeta
+}
+
+// EvalBrokerSchedulerStats is the per-scheduler breakdown of eval broker
+// statistics
+type EvalBrokerSchedulerStats struct {
+	Ready         int
+	Unacked       int
+	Acks          int
+	Nacks         int
+	OldestEvalAge time.Duration
+}
+
+const (
+	// EvalLineageRelationFollowUp links an evaluation to the follow-up
+	// evaluation created from it, such as a replacement after a failure
+	EvalLineageRelationFollowUp = "follow-up"
+
+	// EvalLineageRelationBlocked links an evaluation to the blocked
+	// evaluation it spawned for failed placements
+	EvalLineageRelationBlocked = "blocked"
+)
+
+// EvalLineageLink is an edge in the evaluation lineage graph for a job.
+type EvalLineageLink struct {
+	// FromEval is the ID of the evaluation the link originates from
+	FromEval string
+
+	// ToEval is the ID of the evaluation the link points at
+	ToEval string
+
+	// Relation describes how ToEval descends from FromEval
+	Relation string
+}
+
+// EvalLineageResponse returns the evaluations for a job together with the
+// links between them as a graph.
+type EvalLineageResponse struct {
+	Evaluations []*Evaluation
+	Links       []*EvalLineageLink
+	QueryMeta
+}
+
 // EvalAllocationsResponse is used to return the allocations for an evaluation
 type EvalAllocationsResponse struct {
 	Allocations []*AllocListStub
@@ -1467,6 +1943,14 @@ type PeriodicForceResponse struct {
 	WriteMeta
 }
 
+// PeriodicLaunchStatsResponse returns the recent launch timing records of a
+// periodic job along with its 95th percentile launch delay.
+type PeriodicLaunchStatsResponse struct {
+	Launches       []*PeriodicLaunchRecord
+	P95LaunchDelay time.Duration
+	QueryMeta
+}
+
 // DeploymentUpdateResponse is used to respond to a deployment change. The
 // response will include the modify index of the deployment as well as details
 // of any triggered evaluation.
@@ -2378,6 +2862,17 @@ type DNSConfig struct {
 	Options  []string
 }
 
+func (d *DNSConfig) Copy() *DNSConfig {
+	if d == nil {
+		return nil
+	}
+	newD := new(DNSConfig)
+	newD.Servers = helper.CopySliceString(d.Servers)
+	newD.Searches = helper.CopySliceString(d.Searches)
+	newD.Options = helper.CopySliceString(d.Options)
+	return newD
+}
+
 // NetworkResource is used to represent available network
 // resources
 type NetworkResource struct {
@@ -2662,6 +3157,12 @@ type NodeResources struct {
 	Networks     Networks
 	NodeNetworks []*NodeNetworkResource
 	Devices      []*NodeDeviceResource
+
+	// MinDynamicPort and MaxDynamicPort set the range from which the
+	// scheduler assigns dynamic ports on this node. A zero value uses the
+	// global default.
+	MinDynamicPort int
+	MaxDynamicPort int
 }
 
 func (n *NodeResources) Copy() *NodeResources {
@@ -2724,6 +3225,14 @@ func (n *NodeResources) Merge(o *NodeResources) {
 		n.Networks = append(n.Networks, o.Networks...)
 	}
 
+	if o.MinDynamicPort != 0 {
+		n.MinDynamicPort = o.MinDynamicPort
+	}
+
+	if o.MaxDynamicPort != 0 {
+		n.MaxDynamicPort = o.MaxDynamicPort
+	}
+
 	if len(o.Devices) != 0 {
 		n.Devices = o.Devices
 	}
@@ -2769,6 +3278,12 @@ func (n *NodeResources) Equals(o *NodeResources) bool {
 	if !n.Networks.Equals(&o.Networks) {
 		return false
 	}
+	if n.MinDynamicPort != o.MinDynamicPort {
+		return false
+	}
+	if n.MaxDynamicPort != o.MaxDynamicPort {
+		return false
+	}
 
 	// Check the devices
 	if !DevicesEquals(n.Devices, o.Devices) {
@@ -3724,6 +4239,11 @@ const (
 	// kept.
 	JobTrackedVersions = 6
 
+	// JobMaxSourceSize is the maximum size, in bytes, of a job submission
+	// source that will be stored alongside the job. Larger submissions are
+	// registered without their source.
+	JobMaxSourceSize = 1 << 20
+
 	// JobTrackedScalingEvents is the number of scaling events that are
 	// kept for a single task group.
 	JobTrackedScalingEvents = 20
@@ -3740,6 +4260,12 @@ type Job struct {
 	// queried and the job to be inspected as it is being killed.
 	Stop bool
 
+	// Suspended marks whether the job has been suspended. A suspended job
+	// has all of its task group counts treated as zero so its allocations
+	// are stopped, but the job stays registered and its prior counts are
+	// recoverable from the version history so it can be resumed exactly.
+	Suspended bool
+
 	// Region is the Nomad region that handles scheduling this job
 	Region string
 
@@ -3815,6 +4341,23 @@ type Job struct {
 	// job. This is opaque to Nomad.
 	Meta map[string]string
 
+	// GCThreshold overrides the server's job_gc_threshold for this job,
+	// controlling how long the job must be terminal before it is garbage
+	// collected. A zero value uses the server default. This is mainly useful
+	// for periodic and dispatched children which are GC eligible once
+	// terminal.
+	GCThreshold time.Duration
+
+	// Expiration configures an optional deadline after which the servers
+	// automatically stop, and optionally purge, the job. This is mainly
+	// useful for demo environments and time-boxed batch campaigns.
+	Expiration *JobExpiration
+
+	// Ownership records who is responsible for the job so that on-call
+	// operators can quickly find the owners of a misbehaving job. Namespaces
+	// may require it at submission.
+	Ownership *JobOwnership
+
 	// ConsulToken is the Consul token that proves the submitter of the job has
 	// access to the Service Identity policies associated with the job's
 	// Consul Connect enabled services. This field is only used to transfer the
@@ -3932,6 +4475,8 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = helper.CopyMapStringString(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.Expiration = nj.Expiration.Copy()
+	nj.Ownership = nj.Ownership.Copy()
 	return nj
 }
 
@@ -3963,6 +4508,9 @@ func (j *Job) Validate() error {
 	if j.Priority < JobMinPriority || j.Priority > JobMaxPriority {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job priority must be between [%d, %d]", JobMinPriority, JobMaxPriority))
 	}
+	if j.GCThreshold < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Job GC threshold must be a positive duration"))
+	}
 	if len(j.Datacenters) == 0 && !j.IsMultiregion() {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	} else {
@@ -4069,6 +4617,18 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Expiration != nil {
+		if err := j.Expiration.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
+	if j.Ownership != nil {
+		if err := j.Ownership.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	if j.IsMultiregion() {
 		if err := j.Multiregion.Validate(j.Type, j.Datacenters); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
@@ -4206,6 +4766,24 @@ func (j *Job) IsParameterized() bool {
 	return j.ParameterizedJob != nil && !j.Dispatched
 }
 
+// ExpirationDeadline returns the time at which the job expires and whether
+// the job has a usable expiration configured.
+func (j *Job) ExpirationDeadline() (time.Time, bool) {
+	if j.Expiration == nil {
+		return time.Time{}, false
+	}
+
+	if j.Expiration.At != "" {
+		t, err := time.Parse(time.RFC3339, j.Expiration.At)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+
+	return time.Unix(0, j.SubmitTime).Add(j.Expiration.TTL), true
+}
+
 // IsMultiregion returns whether a job is multiregion
 func (j *Job) IsMultiregion() bool {
 	return j.Multiregion != nil && j.Multiregion.Regions != nil && len(j.Multiregion.Regions) > 0
@@ -4630,6 +5208,18 @@ func (m *Multiregion) Copy() *Multiregion {
 	return copy
 }
 
+const (
+	// MultiregionStrategyOnFailureAll fails the deployment in every region
+	// when any region fails, including regions that already finished their
+	// rollout.
+	MultiregionStrategyOnFailureAll = "fail_all"
+
+	// MultiregionStrategyOnFailureLocal fails only the region whose
+	// deployment failed. The default (empty) mode fails the regions that
+	// have not yet finished their rollout.
+	MultiregionStrategyOnFailureLocal = "fail_local"
+)
+
 type MultiregionStrategy struct {
 	MaxParallel int
 	OnFailure   string
@@ -4706,7 +5296,7 @@ func (p *PeriodicConfig) Validate() error {
 	switch p.SpecType {
 	case PeriodicSpecCron:
 		// Validate the cron spec
-		if _, err := cronexpr.Parse(p.Spec); err != nil {
+		if _, err := crontab.Parse(p.Spec); err != nil {
 			multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", p.Spec, err))
 		}
 	case PeriodicSpecTest:
@@ -4728,17 +5318,15 @@ func (p *PeriodicConfig) Canonicalize() {
 	p.location = l
 }
 
-// CronParseNext is a helper that parses the next time for the given expression
-// but captures any panic that may occur in the underlying library.
-func CronParseNext(e *cronexpr.Expression, fromTime time.Time, spec string) (t time.Time, err error) {
-	defer func() {
-		if recover() != nil {
-			t = time.Time{}
-			err = fmt.Errorf("failed parsing cron expression: %q", spec)
-		}
-	}()
+// CronParseNext is a helper that parses the next time for the given
+// expression, decorating any error with the offending spec.
+func CronParseNext(e *crontab.Expression, fromTime time.Time, spec string) (time.Time, error) {
+	next, err := e.Next(fromTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", spec, err)
+	}
 
-	return e.Next(fromTime), nil
+	return next, nil
 }
 
 // Next returns the closest time instant matching the spec that is after the
@@ -4748,7 +5336,7 @@ func CronParseNext(e *cronexpr.Expression, fromTime time.Time, spec string) (t t
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	switch p.SpecType {
 	case PeriodicSpecCron:
-		e, err := cronexpr.Parse(p.Spec)
+		e, err := crontab.Parse(p.Spec)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", p.Spec, err)
 		}
@@ -4792,20 +5380,126 @@ func (p *PeriodicConfig) GetLocation() *time.Location {
 	return time.UTC
 }
 
-const (
-	// PeriodicLaunchSuffix is the string appended to the periodic jobs ID
-	// when launching derived instances of it.
-	PeriodicLaunchSuffix = "/periodic-"
-)
+// JobExpiration configures when a job is automatically stopped by the
+// servers. Exactly one of At or TTL must be set.
+type JobExpiration struct {
+	// At is the absolute time the job expires, formatted as RFC3339.
+	At string
 
-// PeriodicLaunch tracks the last launch time of a periodic job.
-type PeriodicLaunch struct {
-	ID        string    // ID of the periodic job.
-	Namespace string    // Namespace of the periodic job
-	Launch    time.Time // The last launch time.
+	// TTL is how long after submission the job expires.
+	TTL time.Duration
 
-	// Raft Indexes
-	CreateIndex uint64
+	// Purge causes the job to be purged rather than just stopped when it
+	// expires.
+	Purge bool
+}
+
+func (e *JobExpiration) Copy() *JobExpiration {
+	if e == nil {
+		return nil
+	}
+	ne := new(JobExpiration)
+	*ne = *e
+	return ne
+}
+
+func (e *JobExpiration) Validate() error {
+	var mErr multierror.Error
+	switch {
+	case e.At != "" && e.TTL != 0:
+		multierror.Append(&mErr, fmt.Errorf("Expiration may only specify one of at or ttl"))
+	case e.At == "" && e.TTL == 0:
+		multierror.Append(&mErr, fmt.Errorf("Expiration must specify one of at or ttl"))
+	}
+
+	if e.At != "" {
+		if _, err := time.Parse(time.RFC3339, e.At); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("Invalid expiration time %q: %v", e.At, err))
+		}
+	}
+
+	if e.TTL < 0 {
+		multierror.Append(&mErr, fmt.Errorf("Expiration TTL must be a positive duration"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// JobOwnership records who is responsible for a job so that on-call
+// operators can quickly find the owners of a misbehaving job.
+type JobOwnership struct {
+	// Team is the name of the team that owns the job.
+	Team string
+
+	// Contact is how to reach the owners, typically an email address or
+	// paging target.
+	Contact string
+
+	// RepoURL links to the source repository the job is deployed from.
+	RepoURL string
+}
+
+func (o *JobOwnership) Copy() *JobOwnership {
+	if o == nil {
+		return nil
+	}
+	no := new(JobOwnership)
+	*no = *o
+	return no
+}
+
+func (o *JobOwnership) Validate() error {
+	var mErr multierror.Error
+
+	if o.Team == "" && o.Contact == "" && o.RepoURL == "" {
+		multierror.Append(&mErr, fmt.Errorf("Ownership must set at least one of team, contact or repo url"))
+	}
+
+	if o.RepoURL != "" {
+		u, err := url.Parse(o.RepoURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			multierror.Append(&mErr, fmt.Errorf("Ownership repo url %q must be an absolute URL", o.RepoURL))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// PeriodicLaunchSuffix is the string appended to the periodic jobs ID
+	// when launching derived instances of it.
+	PeriodicLaunchSuffix = "/periodic-"
+)
+
+// PeriodicLaunchRecord captures the timing of a single launch of a periodic
+// job as observed by the periodic dispatcher.
+type PeriodicLaunchRecord struct {
+	// ScheduledTime is the launch time dictated by the periodic spec.
+	ScheduledTime time.Time
+
+	// DispatchTime is when the dispatcher created the evaluation for the
+	// launch.
+	DispatchTime time.Time
+
+	// AllocStartTime is when the first allocation of the launch started
+	// running. It is the zero time if no allocation has started.
+	AllocStartTime time.Time
+}
+
+// LaunchDelay returns how long after its scheduled time the launch was
+// dispatched.
+func (p *PeriodicLaunchRecord) LaunchDelay() time.Duration {
+	return p.DispatchTime.Sub(p.ScheduledTime)
+}
+
+// PeriodicLaunch tracks the last launch time of a periodic job.
+type PeriodicLaunch struct {
+	ID        string    // ID of the periodic job.
+	Namespace string    // Namespace of the periodic job
+	Launch    time.Time // The last launch time.
+
+	// Raft Indexes
+	CreateIndex uint64
 	ModifyIndex uint64
 }
 
@@ -6683,10 +7377,22 @@ func (k TaskKind) IsConnectIngress() bool {
 	return k.hasPrefix(ConnectIngressPrefix)
 }
 
+func (k TaskKind) IsConnectTerminating() bool {
+	return k.hasPrefix(ConnectTerminatingPrefix)
+}
+
+func (k TaskKind) IsConnectMesh() bool {
+	return k.hasPrefix(ConnectMeshPrefix)
+}
+
 func (k TaskKind) IsAnyConnectGateway() bool {
 	switch {
 	case k.IsConnectIngress():
 		return true
+	case k.IsConnectTerminating():
+		return true
+	case k.IsConnectMesh():
+		return true
 	default:
 		return false
 	}
@@ -6707,15 +7413,11 @@ const (
 
 	// ConnectTerminatingPrefix is the prefix used for fields referencing a Consul
 	// Connect Terminating Gateway Proxy.
-	//
-	// Not yet supported.
-	// ConnectTerminatingPrefix = "connect-terminating"
+	ConnectTerminatingPrefix = "connect-terminating"
 
 	// ConnectMeshPrefix is the prefix used for fields referencing a Consul Connect
 	// Mesh Gateway Proxy.
-	//
-	// Not yet supported.
-	// ConnectMeshPrefix = "connect-mesh"
+	ConnectMeshPrefix = "connect-mesh"
 )
 
 // ValidateConnectProxyService checks that the service that is being
@@ -7033,6 +7735,14 @@ const (
 	// restarted
 	TaskRestartSignal = "Restart Signaled"
 
+	// TaskHeld indicates that the task terminated but restarts are paused
+	// because the allocation has been placed on hold.
+	TaskHeld = "Held"
+
+	// TaskCPUThrottled indicates that cgroup CPU throttling has been
+	// observed for the task.
+	TaskCPUThrottled = "CPU Throttled"
+
 	// TaskSignaling indicates that the task is being signalled.
 	TaskSignaling = "Signaling"
 
@@ -7248,6 +7958,10 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		if event.Message != "" {
 			parts = append(parts, fmt.Sprintf("Exit Message: %q", event.Message))
 		}
+
+		if event.Details["oom_killed"] == "true" {
+			parts = append(parts, "OOM Killed")
+		}
 		desc = strings.Join(parts, ", ")
 	case TaskRestarting:
 		in := fmt.Sprintf("Task restarting in %v", time.Duration(event.StartDelay))
@@ -7262,6 +7976,10 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Task exceeded restart policy"
 		}
+	case TaskHeld:
+		desc = "Task restarts paused while the allocation is on hold"
+	case TaskCPUThrottled:
+		desc = "Task is hitting its CPU limit and being throttled"
 	case TaskSiblingFailed:
 		if event.FailedSibling != "" {
 			desc = fmt.Sprintf("Task's sibling %q failed", event.FailedSibling)
@@ -7474,6 +8192,12 @@ func (e *TaskEvent) SetOOMKilled(oom bool) *TaskEvent {
 	return e
 }
 
+func (e *TaskEvent) SetCPUThrottled(periods, throttledTime uint64) *TaskEvent {
+	e.Details["throttled_periods"] = strconv.FormatUint(periods, 10)
+	e.Details["throttled_time"] = strconv.FormatUint(throttledTime, 10)
+	return e
+}
+
 // TaskArtifact is an artifact to download before running the task.
 type TaskArtifact struct {
 	// GetterSource is the source to download an artifact using go-getter
@@ -8451,6 +9175,11 @@ type DesiredTransition struct {
 	// This field is only used when operators want to force a placement even if
 	// a failed allocation is not eligible to be rescheduled
 	ForceReschedule *bool
+
+	// Hold is used to indicate that this allocation should be held for
+	// debugging: the scheduler will not replace it and the client pauses
+	// the restart policy so the task environment can be inspected.
+	Hold *bool
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -8467,6 +9196,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.ForceReschedule != nil {
 		d.ForceReschedule = o.ForceReschedule
 	}
+
+	if o.Hold != nil {
+		d.Hold = o.Hold
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -8489,6 +9222,15 @@ func (d *DesiredTransition) ShouldForceReschedule() bool {
 	return d.ForceReschedule != nil && *d.ForceReschedule
 }
 
+// ShouldHold returns whether the transition object dictates that the
+// allocation is held for debugging.
+func (d *DesiredTransition) ShouldHold() bool {
+	if d == nil {
+		return false
+	}
+	return d.Hold != nil && *d.Hold
+}
+
 const (
 	AllocDesiredStatusRun   = "run"   // Allocation should run
 	AllocDesiredStatusStop  = "stop"  // Allocation should stop
@@ -8596,6 +9338,10 @@ type Allocation struct {
 	// given deployment
 	DeploymentStatus *AllocDeploymentStatus
 
+	// NetworkStatus captures the status of the allocation's network during
+	// runtime. It is set by the client and is not stored by older servers.
+	NetworkStatus *AllocNetworkStatus
+
 	// RescheduleTrackers captures details of previous reschedule attempts of the allocation
 	RescheduleTracker *RescheduleTracker
 
@@ -8611,6 +9357,11 @@ type Allocation struct {
 	// to stop running because it got preempted
 	PreemptedByAllocation string
 
+	// CheckpointMeta is a small set of key/value pairs tasks may store to
+	// checkpoint their progress. It is carried forward to replacement
+	// allocations so rescheduled work can resume where it left off.
+	CheckpointMeta map[string]string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -8706,6 +9457,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.Metrics = na.Metrics.Copy()
 	na.DeploymentStatus = na.DeploymentStatus.Copy()
+	na.NetworkStatus = na.NetworkStatus.Copy()
 
 	if a.TaskStates != nil {
 		ts := make(map[string]*TaskState, len(na.TaskStates))
@@ -8717,6 +9469,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
 	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.CheckpointMeta = helper.CopyMapStringString(a.CheckpointMeta)
 	return na
 }
 
@@ -9077,8 +9830,11 @@ func (a *Allocation) LookupTask(name string) *Task {
 }
 
 // Stub returns a list stub for the allocation
-func (a *Allocation) Stub() *AllocListStub {
-	return &AllocListStub{
+// Stub returns a list stub for the allocation. The fields parameter selects
+// which large, rarely-needed fields are included; a nil value uses the
+// defaults from NewAllocStubFields.
+func (a *Allocation) Stub(fields *AllocStubFields) *AllocListStub {
+	stub := &AllocListStub{
 		ID:                    a.ID,
 		EvalID:                a.EvalID,
 		Name:                  a.Name,
@@ -9094,7 +9850,6 @@ func (a *Allocation) Stub() *AllocListStub {
 		ClientStatus:          a.ClientStatus,
 		ClientDescription:     a.ClientDescription,
 		DesiredTransition:     a.DesiredTransition,
-		TaskStates:            a.TaskStates,
 		DeploymentStatus:      a.DeploymentStatus,
 		FollowupEvalID:        a.FollowupEvalID,
 		RescheduleTracker:     a.RescheduleTracker,
@@ -9105,6 +9860,18 @@ func (a *Allocation) Stub() *AllocListStub {
 		CreateTime:            a.CreateTime,
 		ModifyTime:            a.ModifyTime,
 	}
+
+	if fields == nil {
+		fields = NewAllocStubFields()
+	}
+	if fields.TaskStates {
+		stub.TaskStates = a.TaskStates
+	}
+	if fields.Resources {
+		stub.AllocatedResources = a.AllocatedResources
+	}
+
+	return stub
 }
 
 // AllocationDiff converts an Allocation type to an AllocationDiff type
@@ -9138,6 +9905,7 @@ type AllocListStub struct {
 	ClientDescription     string
 	DesiredTransition     DesiredTransition
 	TaskStates            map[string]*TaskState
+	AllocatedResources    *AllocatedResources `json:",omitempty"`
 	DeploymentStatus      *AllocDeploymentStatus
 	FollowupEvalID        string
 	RescheduleTracker     *RescheduleTracker
@@ -9149,6 +9917,24 @@ type AllocListStub struct {
 	ModifyTime            int64
 }
 
+// AllocStubFields defines which fields are included in the AllocListStub.
+type AllocStubFields struct {
+	// Resources includes resource-related fields if true.
+	Resources bool
+
+	// TaskStates removes the TaskStates field if false (default is to
+	// include TaskStates).
+	TaskStates bool
+}
+
+func NewAllocStubFields() *AllocStubFields {
+	return &AllocStubFields{
+		// Maintain backward-compatibility by retaining task states by
+		// default.
+		TaskStates: true,
+	}
+}
+
 // SetEventDisplayMessage populates the display message if its not already set,
 // a temporary fix to handle old allocations that don't have it.
 // This method will be removed in a future release.
@@ -9359,6 +10145,33 @@ func (s *NodeScoreMeta) Data() interface{} {
 	return s
 }
 
+// AllocNetworkStatus captures the status of an allocation's network during
+// runtime. Depending on the network mode, an allocation's address may need to
+// be known to other systems in Nomad such as service registration.
+type AllocNetworkStatus struct {
+	// InterfaceName is the name of the interface inside the allocation's
+	// network namespace that was assigned the address
+	InterfaceName string
+
+	// Address is the IP address assigned to the allocation by the network
+	// plugin
+	Address string
+
+	// DNS is the DNS configuration applied to the network namespace
+	DNS *DNSConfig
+}
+
+func (a *AllocNetworkStatus) Copy() *AllocNetworkStatus {
+	if a == nil {
+		return nil
+	}
+
+	c := new(AllocNetworkStatus)
+	*c = *a
+	c.DNS = a.DNS.Copy()
+	return c
+}
+
 // AllocDeploymentStatus captures the status of the allocation as part of the
 // deployment. This can include things like if the allocation has been marked as
 // healthy.
@@ -9444,6 +10257,7 @@ const (
 	EvalTriggerNodeDrain         = "node-drain"
 	EvalTriggerNodeUpdate        = "node-update"
 	EvalTriggerAllocStop         = "alloc-stop"
+	EvalTriggerAllocHold         = "alloc-hold"
 	EvalTriggerScheduled         = "scheduled"
 	EvalTriggerRollingUpdate     = "rolling-update"
 	EvalTriggerDeploymentWatcher = "deployment-watcher"
@@ -9453,6 +10267,7 @@ const (
 	EvalTriggerQueuedAllocs      = "queued-allocs"
 	EvalTriggerPreemption        = "preemption"
 	EvalTriggerScaling           = "job-scaling"
+	EvalTriggerJobSuspend        = "job-suspend"
 )
 
 const (
@@ -9473,6 +10288,11 @@ const (
 	// the system.
 	CoreJobJobGC = "job-gc"
 
+	// CoreJobJobExpire is used to stop jobs whose expiration deadline has
+	// passed. We periodically scan jobs with an expiration configured and
+	// deregister, optionally purging, any that have expired.
+	CoreJobJobExpire = "job-expire"
+
 	// CoreJobDeploymentGC is used for the garbage collection of eligible
 	// deployments. We periodically scan garbage collectible deployments and
 	// check if they are terminal. If so, we delete these out of the system.
@@ -9488,6 +10308,11 @@ const (
 	// or allocs running them. If so, we delete the plugin.
 	CoreJobCSIPluginGC = "csi-plugin-gc"
 
+	// CoreJobACLTokenExpirationGC is used for the garbage collection of
+	// expired ACL tokens. We periodically scan tokens and delete any whose
+	// expiration time has passed beyond the configured threshold.
+	CoreJobACLTokenExpirationGC = "acl-token-expiration-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -9606,6 +10431,17 @@ type Evaluation struct {
 	// the SnapshotIndex being less than the CreateIndex.
 	SnapshotIndex uint64
 
+	// QueuePosition is the approximate position of a blocked evaluation in
+	// the queue of blocked evaluations, with one being next in line. It is
+	// computed when the evaluation is read and is not persisted.
+	QueuePosition int
+
+	// EarliestStart is a hint at the earliest time a blocked evaluation's
+	// allocations could be placed, based on allocations that are pending
+	// termination. The zero value means no hint is available. It is computed
+	// when the evaluation is read and is not persisted.
+	EarliestStart time.Time
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -10318,20 +11154,518 @@ type ACLPolicyUpsertRequest struct {
 	WriteRequest
 }
 
+// ACLRole is used to represent a named group of ACL policies that can be
+// granted to tokens as a unit.
+type ACLRole struct {
+	Name        string   // Unique name
+	Description string   // Human readable
+	Policies    []string // Policies this role expands to
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL role
+func (a *ACLRole) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Description))
+	for _, policyName := range a.Policies {
+		hash.Write([]byte(policyName))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+func (a *ACLRole) Stub() *ACLRoleListStub {
+	return &ACLRoleListStub{
+		Name:        a.Name,
+		Description: a.Description,
+		Policies:    a.Policies,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+func (a *ACLRole) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(a.Name) {
+		err := fmt.Errorf("invalid name '%s'", a.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(a.Policies) == 0 {
+		err := fmt.Errorf("role missing policies")
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(a.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLRoleListStub is used to for listing ACL roles
+type ACLRoleListStub struct {
+	Name        string
+	Description string
+	Policies    []string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRoleListRequest is used to request a list of roles
+type ACLRoleListRequest struct {
+	QueryOptions
+}
+
+// ACLRoleSpecificRequest is used to query a specific role
+type ACLRoleSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLRoleSetRequest is used to query a set of roles
+type ACLRoleSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLRoleListResponse is used for a list request
+type ACLRoleListResponse struct {
+	Roles []*ACLRoleListStub
+	QueryMeta
+}
+
+// SingleACLRoleResponse is used to return a single role
+type SingleACLRoleResponse struct {
+	Role *ACLRole
+	QueryMeta
+}
+
+// ACLRoleSetResponse is used to return a set of roles
+type ACLRoleSetResponse struct {
+	Roles map[string]*ACLRole
+	QueryMeta
+}
+
+// ACLRoleDeleteRequest is used to delete a set of roles
+type ACLRoleDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLRoleUpsertRequest is used to upsert a set of roles
+type ACLRoleUpsertRequest struct {
+	Roles []*ACLRole
+	WriteRequest
+}
+
+const (
+	// ACLAuthMethodTypeOIDC and ACLAuthMethodTypeJWT are the supported auth
+	// method types.
+	ACLAuthMethodTypeOIDC = "oidc"
+	ACLAuthMethodTypeJWT  = "jwt"
+
+	// ACLAuthMethodTokenLocalityLocal and ACLAuthMethodTokenLocalityGlobal
+	// control whether tokens minted by an auth method are local or global.
+	ACLAuthMethodTokenLocalityLocal  = "local"
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy and
+	// ACLBindingRuleBindTypeManagement are the supported binding rule bind
+	// types.
+	ACLBindingRuleBindTypeRole       = "role"
+	ACLBindingRuleBindTypePolicy     = "policy"
+	ACLBindingRuleBindTypeManagement = "management"
+)
+
+// ACLAuthMethod is used to represent an external authentication method, such
+// as an OIDC provider or a trusted JWT issuer, that users can log in with to
+// be minted an ACL token.
+type ACLAuthMethod struct {
+	Name          string // Unique name
+	Type          string // "oidc" or "jwt"
+	TokenLocality string // Whether minted tokens are local or global
+	MaxTokenTTL   time.Duration
+	Default       bool // Used when no method is named during login
+	Config        *ACLAuthMethodConfig
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig holds the type specific configuration for an auth
+// method.
+type ACLAuthMethodConfig struct {
+	// OIDC specific configuration
+	OIDCDiscoveryURL    string
+	OIDCClientID        string
+	OIDCClientSecret    string
+	AllowedRedirectURIs []string
+
+	// JWT specific configuration
+	JWTValidationPubKeys []string
+	BoundIssuer          string
+
+	// Configuration shared by both types
+	BoundAudiences    []string
+	SigningAlgs       []string
+	ClaimMappings     map[string]string
+	ListClaimMappings map[string]string
+}
+
+// SetHash is used to compute and set the hash of the ACL auth method
+func (a *ACLAuthMethod) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Type))
+	hash.Write([]byte(a.TokenLocality))
+	hash.Write([]byte(a.MaxTokenTTL.String()))
+	if a.Default {
+		hash.Write([]byte("default"))
+	}
+	if a.Config != nil {
+		hash.Write([]byte(fmt.Sprintf("%v", *a.Config)))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodListStub {
+	return &ACLAuthMethodListStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+func (a *ACLAuthMethod) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(a.Name) {
+		err := fmt.Errorf("invalid name '%s'", a.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch a.Type {
+	case ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT:
+	default:
+		err := fmt.Errorf("invalid type '%s'", a.Type)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch a.TokenLocality {
+	case ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal:
+	default:
+		err := fmt.Errorf("invalid token locality '%s'", a.TokenLocality)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if a.MaxTokenTTL <= 0 {
+		err := fmt.Errorf("max token TTL must be set")
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if a.Config == nil {
+		err := fmt.Errorf("auth method missing config")
+		mErr.Errors = append(mErr.Errors, err)
+	} else {
+		switch a.Type {
+		case ACLAuthMethodTypeOIDC:
+			if a.Config.OIDCDiscoveryURL == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("config missing OIDC discovery URL"))
+			}
+			if a.Config.OIDCClientID == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("config missing OIDC client ID"))
+			}
+		case ACLAuthMethodTypeJWT:
+			if len(a.Config.JWTValidationPubKeys) == 0 {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("config missing JWT validation public keys"))
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLAuthMethodListStub is used to for listing ACL auth methods
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodListRequest is used to request a list of auth methods
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodSpecificRequest is used to query a specific auth method
+type ACLAuthMethodSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLAuthMethodSetRequest is used to query a set of auth methods
+type ACLAuthMethodSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is used for a list request
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodListStub
+	QueryMeta
+}
+
+// SingleACLAuthMethodResponse is used to return a single auth method
+type SingleACLAuthMethodResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodSetResponse is used to return a set of auth methods
+type ACLAuthMethodSetResponse struct {
+	AuthMethods map[string]*ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodDeleteRequest is used to delete a set of auth methods
+type ACLAuthMethodDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodUpsertRequest is used to upsert a set of auth methods
+type ACLAuthMethodUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLBindingRule is used to convert the identity claims produced by an auth
+// method login into grants on the minted ACL token.
+type ACLBindingRule struct {
+	ID          string // Public ID (UUID)
+	Description string // Human readable
+	AuthMethod  string // Name of the auth method this rule applies to
+	Selector    string // Optional boolean expression over the identity claims
+	BindType    string // "role", "policy" or "management"
+	BindName    string // Name of the role or policy to grant
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL binding rule
+func (a *ACLBindingRule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.ID))
+	hash.Write([]byte(a.Description))
+	hash.Write([]byte(a.AuthMethod))
+	hash.Write([]byte(a.Selector))
+	hash.Write([]byte(a.BindType))
+	hash.Write([]byte(a.BindName))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+func (a *ACLBindingRule) Validate() error {
+	var mErr multierror.Error
+	if a.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing auth method"))
+	}
+	switch a.BindType {
+	case ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy:
+		if a.BindName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing bind name"))
+		}
+	case ACLBindingRuleBindTypeManagement:
+		if a.BindName != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("bind name must not be set for management bind type"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind type '%s'", a.BindType))
+	}
+	if len(a.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLBindingRuleListRequest is used to request a list of binding rules
+type ACLBindingRuleListRequest struct {
+	QueryOptions
+}
+
+// ACLBindingRuleSpecificRequest is used to query a specific binding rule
+type ACLBindingRuleSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// ACLBindingRuleSetRequest is used to query a set of binding rules
+type ACLBindingRuleSetRequest struct {
+	IDs []string
+	QueryOptions
+}
+
+// ACLBindingRuleListResponse is used for a list request
+type ACLBindingRuleListResponse struct {
+	BindingRules []*ACLBindingRule
+	QueryMeta
+}
+
+// SingleACLBindingRuleResponse is used to return a single binding rule
+type SingleACLBindingRuleResponse struct {
+	BindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleSetResponse is used to return a set of binding rules
+type ACLBindingRuleSetResponse struct {
+	BindingRules map[string]*ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleDeleteRequest is used to delete a set of binding rules
+type ACLBindingRuleDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertRequest is used to upsert a set of binding rules
+type ACLBindingRuleUpsertRequest struct {
+	BindingRules []*ACLBindingRule
+	WriteRequest
+}
+
+// ACLLoginRequest is used to login via an auth method and be minted an ACL
+// token.
+type ACLLoginRequest struct {
+	// AuthMethodName is the name of the auth method to login with.
+	AuthMethodName string
+
+	// LoginToken is the bearer token presented to the auth method, such as
+	// an OIDC ID token or a third party JWT.
+	LoginToken string
+
+	WriteRequest
+}
+
+// ACLLoginResponse is used to return the minted token from a login
+type ACLLoginResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
+
+// ACLOIDCAuthURLRequest is used to request an OIDC authentication URL to
+// start a login flow.
+type ACLOIDCAuthURLRequest struct {
+	AuthMethodName string
+
+	// RedirectURI is the URI of the OIDC callback the caller is serving. It
+	// must be listed in the auth method's allowed redirect URIs.
+	RedirectURI string
+
+	// ClientNonce is a randomly generated string used to link the auth URL
+	// to the completing request.
+	ClientNonce string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse is used to return the OIDC authentication URL
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete an OIDC login by exchanging
+// the authorization code returned by the provider.
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	RedirectURI    string
+	ClientNonce    string
+
+	// Code and State are returned by the OIDC provider on the callback.
+	Code  string
+	State string
+
+	WriteRequest
+}
+
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
+	AccessorID string   // Public Accessor ID (UUID)
+	SecretID   string   // Secret ID, private (UUID)
+	Name       string   // Human friendly name
+	Type       string   // Client or Management
+	Policies   []string // Policies this token ties to
+	Roles      []string // Roles whose policies this token also carries
+	Global     bool     // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime is the point after which the token is no longer valid
+	// and will be garbage collected. Nil means the token never expires.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field for setting ExpirationTime
+	// relative to the token's create time when the token is upserted.
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// IsExpired returns whether the token has an expiration time before the
+// passed time. Tokens without an expiration time never expire.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	if a.ExpirationTime == nil || a.ExpirationTime.IsZero() {
+		return false
+	}
+
+	return a.ExpirationTime.Before(t)
+}
+
 var (
 	// AnonymousACLToken is used no SecretID is provided, and the
 	// request is made anonymously.
@@ -10345,15 +11679,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token
@@ -10370,11 +11706,17 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		hash.Write([]byte(policyName))
 	}
+	for _, roleName := range a.Roles {
+		hash.Write([]byte(roleName))
+	}
 	if a.Global {
 		hash.Write([]byte("global"))
 	} else {
 		hash.Write([]byte("local"))
 	}
+	if a.ExpirationTime != nil {
+		hash.Write([]byte(a.ExpirationTime.String()))
+	}
 
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
@@ -10386,15 +11728,17 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
 	}
 }
 
@@ -10404,14 +11748,17 @@ func (a *ACLToken) Validate() error {
 	if len(a.Name) > maxTokenNameLength {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token name too long"))
 	}
+	if a.ExpirationTTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token expiration TTL must be positive"))
+	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
-		if len(a.Policies) != 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
+		if len(a.Policies) != 0 || len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies or roles"))
 		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
@@ -10485,6 +11832,33 @@ type ResolveACLTokenResponse struct {
 	QueryMeta
 }
 
+// ACLCheckRequest is used to check whether a token is allowed to perform a
+// namespaced operation
+type ACLCheckRequest struct {
+	// SecretID is the secret ID of the token being checked
+	SecretID string
+
+	// Namespace is the namespace the operation applies to. Defaults to the
+	// default namespace if unset.
+	Namespace string
+
+	// Capability is the namespace capability being checked, e.g. submit-job
+	Capability string
+
+	QueryOptions
+}
+
+// ACLCheckResponse is the result of a capability check
+type ACLCheckResponse struct {
+	// Allow is true if the token is permitted to perform the operation
+	Allow bool
+
+	// Policy is the name of the policy that granted the capability, if any
+	Policy string
+
+	QueryMeta
+}
+
 // ACLTokenDeleteRequest is used to delete a set of tokens
 type ACLTokenDeleteRequest struct {
 	AccessorIDs []string
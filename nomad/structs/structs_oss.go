@@ -2,12 +2,69 @@
 
 package structs
 
-import "errors"
+import (
+	"errors"
+	"fmt"
 
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// Validate ensures that the multiregion stanza describes a usable rollout:
+// every region is named exactly once, every region can resolve a set of
+// datacenters, and the rollout strategy is well formed. Whether multiregion
+// deployments are available at all is decided at registration time by the
+// multiregion-deployments feature flag.
 func (m *Multiregion) Validate(jobType string, jobDatacenters []string) error {
-	if m != nil {
-		return errors.New("Multiregion jobs are unlicensed.")
+	var mErr multierror.Error
+
+	if jobType == JobTypeSystem {
+		mErr.Errors = append(mErr.Errors,
+			errors.New("Multiregion is not supported for system jobs"))
+	}
+
+	if len(m.Regions) == 0 {
+		mErr.Errors = append(mErr.Errors,
+			errors.New("Multiregion jobs require at least one region"))
+	}
+
+	seen := make(map[string]struct{}, len(m.Regions))
+	for _, region := range m.Regions {
+		if region.Name == "" {
+			mErr.Errors = append(mErr.Errors,
+				errors.New("Multiregion region must have a name"))
+			continue
+		}
+		if _, ok := seen[region.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Multiregion region %q specified more than once", region.Name))
+		}
+		seen[region.Name] = struct{}{}
+
+		if region.Count < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Multiregion region %q count must not be negative", region.Name))
+		}
+		if len(region.Datacenters) == 0 && len(jobDatacenters) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Multiregion region %q must have datacenters when the job does not", region.Name))
+		}
+	}
+
+	if m.Strategy != nil {
+		if m.Strategy.MaxParallel < 0 {
+			mErr.Errors = append(mErr.Errors,
+				errors.New("Multiregion max_parallel must not be negative"))
+		}
+		switch m.Strategy.OnFailure {
+		case "", MultiregionStrategyOnFailureAll, MultiregionStrategyOnFailureLocal:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Multiregion on_failure %q is invalid, must be %q or %q",
+				m.Strategy.OnFailure,
+				MultiregionStrategyOnFailureAll,
+				MultiregionStrategyOnFailureLocal))
+		}
 	}
 
-	return nil
+	return mErr.ErrorOrNil()
 }
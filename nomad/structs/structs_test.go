@@ -3047,6 +3047,64 @@ func TestJob_CombinedTaskMeta(t *testing.T) {
 
 }
 
+func TestJobExpiration_Validate(t *testing.T) {
+	// Neither at nor ttl is invalid.
+	e := &JobExpiration{}
+	if err := e.Validate(); err == nil || !strings.Contains(err.Error(), "must specify") {
+		t.Fatalf("JobExpiration with no deadline shouldn't be valid: %v", err)
+	}
+
+	// Both at and ttl is invalid.
+	e = &JobExpiration{At: "2026-01-02T15:04:05Z", TTL: time.Hour}
+	if err := e.Validate(); err == nil || !strings.Contains(err.Error(), "only specify one") {
+		t.Fatalf("JobExpiration with both at and ttl shouldn't be valid: %v", err)
+	}
+
+	// Unparseable at is invalid.
+	e = &JobExpiration{At: "tomorrow"}
+	if err := e.Validate(); err == nil || !strings.Contains(err.Error(), "Invalid expiration time") {
+		t.Fatalf("JobExpiration with bad at shouldn't be valid: %v", err)
+	}
+
+	// Valid configs.
+	e = &JobExpiration{At: "2026-01-02T15:04:05Z", Purge: true}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	e = &JobExpiration{TTL: 24 * time.Hour}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestJobOwnership_Validate(t *testing.T) {
+	// Fully empty ownership is invalid.
+	o := &JobOwnership{}
+	if err := o.Validate(); err == nil || !strings.Contains(err.Error(), "at least one") {
+		t.Fatalf("empty JobOwnership shouldn't be valid: %v", err)
+	}
+
+	// A relative repo url is invalid.
+	o = &JobOwnership{RepoURL: "example/foo"}
+	if err := o.Validate(); err == nil || !strings.Contains(err.Error(), "absolute URL") {
+		t.Fatalf("JobOwnership with relative repo url shouldn't be valid: %v", err)
+	}
+
+	// Valid configs.
+	o = &JobOwnership{Team: "platform"}
+	if err := o.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	o = &JobOwnership{
+		Team:    "platform",
+		Contact: "platform-oncall@example.com",
+		RepoURL: "https://github.com/example/foo",
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestPeriodicConfig_EnabledInvalid(t *testing.T) {
 	// Create a config that is enabled but with no interval specified.
 	p := &PeriodicConfig{Enabled: true}
@@ -3074,7 +3132,7 @@ func TestPeriodicConfig_EnabledInvalid(t *testing.T) {
 }
 
 func TestPeriodicConfig_InvalidCron(t *testing.T) {
-	specs := []string{"foo", "* *", "@foo"}
+	specs := []string{"foo", "* *", "@foo", "* * * * * * * *", "@every", "@every 2x", "@every 500ms"}
 	for _, spec := range specs {
 		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: spec}
 		p.Canonicalize()
@@ -3085,7 +3143,7 @@ func TestPeriodicConfig_InvalidCron(t *testing.T) {
 }
 
 func TestPeriodicConfig_ValidCron(t *testing.T) {
-	specs := []string{"0 0 29 2 *", "@hourly", "0 0-15 * * *"}
+	specs := []string{"0 0 29 2 *", "@hourly", "0 0-15 * * *", "30 * * * * *", "@every 10m", "@every 1h30m"}
 	for _, spec := range specs {
 		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: spec}
 		p.Canonicalize()
@@ -3116,6 +3174,10 @@ func TestPeriodicConfig_NextCron(t *testing.T) {
 			nextTime: time.Time{},
 			errorMsg: "failed parsing cron expression",
 		},
+		{
+			spec:     "@every 10m",
+			nextTime: time.Date(2009, time.November, 10, 23, 32, 30, 0, time.UTC),
+		},
 	}
 
 	for i, c := range cases {
@@ -4899,6 +4961,180 @@ func TestACLTokenValidate(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestACLRoleValidate(t *testing.T) {
+	rl := &ACLRole{}
+
+	// Missing a name
+	err := rl.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid name") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Missing policies
+	rl.Name = "dev"
+	err = rl.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "missing policies") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Make it valid
+	rl.Policies = []string{"readonly"}
+	err = rl.Validate()
+	assert.Nil(t, err)
+}
+
+func TestACLAuthMethodValidate(t *testing.T) {
+	am := &ACLAuthMethod{}
+
+	// Missing a name
+	err := am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid name") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Bad type
+	am.Name = "okta"
+	am.Type = "saml"
+	err = am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid type") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Bad token locality
+	am.Type = ACLAuthMethodTypeJWT
+	am.TokenLocality = "regional"
+	err = am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "invalid token locality") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Missing TTL and config
+	am.TokenLocality = ACLAuthMethodTokenLocalityLocal
+	err = am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "max token TTL must be set") {
+		t.Fatalf("bad: %v", err)
+	}
+	if !strings.Contains(err.Error(), "missing config") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// JWT methods need public keys
+	am.MaxTokenTTL = time.Hour
+	am.Config = &ACLAuthMethodConfig{}
+	err = am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "JWT validation public keys") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Make it valid
+	am.Config.JWTValidationPubKeys = []string{"pub-key"}
+	err = am.Validate()
+	assert.Nil(t, err)
+
+	// OIDC methods need a discovery URL and client ID
+	am.Type = ACLAuthMethodTypeOIDC
+	err = am.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "OIDC discovery URL") {
+		t.Fatalf("bad: %v", err)
+	}
+	if !strings.Contains(err.Error(), "OIDC client ID") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	am.Config.OIDCDiscoveryURL = "https://example.com"
+	am.Config.OIDCClientID = "abc123"
+	err = am.Validate()
+	assert.Nil(t, err)
+}
+
+func TestACLBindingRuleValidate(t *testing.T) {
+	br := &ACLBindingRule{}
+
+	// Missing an auth method and bind type
+	err := br.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "missing auth method") {
+		t.Fatalf("bad: %v", err)
+	}
+	if !strings.Contains(err.Error(), "invalid bind type") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Policy bindings need a bind name
+	br.AuthMethod = "okta"
+	br.BindType = ACLBindingRuleBindTypePolicy
+	err = br.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "missing bind name") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Make it valid
+	br.BindName = "readonly"
+	err = br.Validate()
+	assert.Nil(t, err)
+
+	// Management bindings must not name a policy or role
+	br.BindType = ACLBindingRuleBindTypeManagement
+	err = br.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "must not be set") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	br.BindName = ""
+	err = br.Validate()
+	assert.Nil(t, err)
+}
+
+func TestACLTokenValidate_ExpirationTTL(t *testing.T) {
+	tk := &ACLToken{
+		Type:          ACLManagementToken,
+		Name:          "foo",
+		ExpirationTTL: -1 * time.Hour,
+	}
+	err := tk.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "must be positive") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Make it valid
+	tk.ExpirationTTL = time.Hour
+	err = tk.Validate()
+	assert.Nil(t, err)
+}
+
+func TestACLTokenIsExpired(t *testing.T) {
+	now := time.Now().UTC()
+	tk := &ACLToken{}
+
+	// A token without an expiration time never expires
+	assert.False(t, tk.IsExpired(now))
+
+	// A zero expiration time also means no expiry
+	tk.ExpirationTime = &time.Time{}
+	assert.False(t, tk.IsExpired(now))
+
+	// Expiring in the future
+	future := now.Add(time.Hour)
+	tk.ExpirationTime = &future
+	assert.False(t, tk.IsExpired(now))
+
+	// Already expired
+	past := now.Add(-1 * time.Hour)
+	tk.ExpirationTime = &past
+	assert.True(t, tk.IsExpired(now))
+}
+
 func TestACLTokenPolicySubset(t *testing.T) {
 	tk := &ACLToken{
 		Type:     ACLClientToken,
@@ -4990,6 +5226,8 @@ func TestTaskEventPopulate(t *testing.T) {
 		{NewTaskEvent(TaskKilling).SetKillTimeout(1 * time.Second), "Sent interrupt. Waiting 1s before force killing"},
 		{NewTaskEvent(TaskTerminated).SetExitCode(-1).SetSignal(3), "Exit Code: -1, Signal: 3"},
 		{NewTaskEvent(TaskTerminated).SetMessage("Goodbye"), "Exit Code: 0, Exit Message: \"Goodbye\""},
+		{NewTaskEvent(TaskTerminated).SetSignal(9).SetOOMKilled(true), "Exit Code: 0, Signal: 9, OOM Killed"},
+		{NewTaskEvent(TaskCPUThrottled).SetCPUThrottled(10, 100), "Task is hitting its CPU limit and being throttled"},
 		{NewTaskEvent(TaskKilled), "Task successfully killed"},
 		{NewTaskEvent(TaskKilled).SetKillError(fmt.Errorf("undead creatures can't be killed")), "undead creatures can't be killed"},
 		{NewTaskEvent(TaskNotRestarting).SetRestartReason("Chaos Monkey did it"), "Chaos Monkey did it"},
@@ -5501,6 +5739,104 @@ func TestMultiregion_CopyCanonicalize(t *testing.T) {
 	require.False(old.Diff(nonEmptyOld))
 }
 
+func TestMultiregion_Validate(t *testing.T) {
+	require := require.New(t)
+
+	cases := []struct {
+		Name    string
+		JobType string
+		Case    *Multiregion
+		Errors  []string
+	}{
+		{
+			Name:    "empty multiregion spec",
+			JobType: JobTypeService,
+			Case:    &Multiregion{},
+			Errors:  []string{"at least one region"},
+		},
+		{
+			Name:    "non-empty valid multiregion spec",
+			JobType: JobTypeService,
+			Case: &Multiregion{
+				Strategy: &MultiregionStrategy{
+					MaxParallel: 2,
+					OnFailure:   "fail_all",
+				},
+				Regions: []*MultiregionRegion{
+					{
+						Name:        "west",
+						Count:       2,
+						Datacenters: []string{"west-1", "west-2"},
+						Meta:        map[string]string{},
+					},
+					{
+						Name:        "east",
+						Count:       1,
+						Datacenters: []string{"east-1"},
+						Meta:        map[string]string{},
+					},
+				},
+			},
+		},
+		{
+			Name:    "repeated region, wrong strategy, missing datacenters",
+			JobType: JobTypeBatch,
+			Case: &Multiregion{
+				Strategy: &MultiregionStrategy{
+					MaxParallel: -1,
+					OnFailure:   "fail_one",
+				},
+				Regions: []*MultiregionRegion{
+					{
+						Name:        "west",
+						Datacenters: []string{"west-1", "west-2"},
+					},
+					{
+						Name:        "west",
+						Datacenters: []string{"west-2"},
+					},
+					{
+						Name: "east",
+					},
+				},
+			},
+			Errors: []string{
+				"\"west\" specified more than once",
+				"\"east\" must have datacenters",
+				"max_parallel must not be negative",
+				"on_failure \"fail_one\" is invalid",
+			},
+		},
+		{
+			Name:    "system jobs not supported",
+			JobType: JobTypeSystem,
+			Case: &Multiregion{
+				Regions: []*MultiregionRegion{
+					{
+						Name:        "west",
+						Datacenters: []string{"west-1"},
+					},
+				},
+			},
+			Errors: []string{"not supported for system jobs"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Case.Validate(tc.JobType, []string{})
+			if len(tc.Errors) == 0 {
+				require.NoError(err)
+				return
+			}
+			require.Error(err)
+			for _, expected := range tc.Errors {
+				require.Contains(err.Error(), expected)
+			}
+		})
+	}
+}
+
 func TestNodeResources_Merge(t *testing.T) {
 	res := &NodeResources{
 		Cpu: NodeCpuResources{
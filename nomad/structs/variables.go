@@ -0,0 +1,214 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VariableMaxSize is the maximum size of the encoded items of a single
+	// variable, to keep raft log entries and snapshots bounded.
+	VariableMaxSize = 64 * 1024
+
+	// VariableMaxPathLength is the maximum length of a variable path.
+	VariableMaxPathLength = 128
+)
+
+// validVariablePath matches the paths variables may be stored at. Paths are
+// slash-delimited but must not start or end with a slash.
+var validVariablePath = regexp.MustCompile(`^[a-zA-Z0-9-_~/]+$`)
+
+// Variable is the decrypted form of a key/value document stored at a path.
+// It only exists in server memory and on the wire; the persisted form is
+// EncryptedVariable.
+type Variable struct {
+	// Namespace is the namespace the variable belongs to.
+	Namespace string
+
+	// Path is the slash-delimited location of the variable, unique within
+	// its namespace.
+	Path string
+
+	// Items are the key/value entries of the variable.
+	Items map[string]string
+
+	// CreateTime and ModifyTime are Unix times in nanoseconds.
+	CreateTime int64
+	ModifyTime int64
+
+	// Raft indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy creates a deep copy of the variable.
+func (v *Variable) Copy() *Variable {
+	nv := new(Variable)
+	*nv = *v
+	nv.Items = helper.CopyMapStringString(nv.Items)
+	return nv
+}
+
+// Validate checks the variable is well formed before it is encrypted and
+// written to raft.
+func (v *Variable) Validate() error {
+	switch {
+	case v.Path == "":
+		return fmt.Errorf("missing variable path")
+	case len(v.Path) > VariableMaxPathLength:
+		return fmt.Errorf("variable path may not be longer than %d characters", VariableMaxPathLength)
+	case !validVariablePath.MatchString(v.Path):
+		return fmt.Errorf("invalid variable path %q", v.Path)
+	case v.Path[0] == '/' || v.Path[len(v.Path)-1] == '/':
+		return fmt.Errorf("variable path may not start or end with a slash")
+	case len(v.Items) == 0:
+		return fmt.Errorf("variable must have at least one item")
+	}
+
+	size := 0
+	for k, i := range v.Items {
+		size += len(k) + len(i)
+	}
+	if size > VariableMaxSize {
+		return fmt.Errorf("variable items may not be larger than %d bytes", VariableMaxSize)
+	}
+	return nil
+}
+
+// Metadata produces the list representation of the variable, which never
+// includes the items.
+func (v *Variable) Metadata() *VariableMetadata {
+	return &VariableMetadata{
+		Namespace:   v.Namespace,
+		Path:        v.Path,
+		CreateTime:  v.CreateTime,
+		ModifyTime:  v.ModifyTime,
+		CreateIndex: v.CreateIndex,
+		ModifyIndex: v.ModifyIndex,
+	}
+}
+
+// VariableMetadata is the list representation of a variable; it carries
+// everything but the items.
+type VariableMetadata struct {
+	Namespace  string
+	Path       string
+	CreateTime int64
+	ModifyTime int64
+
+	// Raft indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EncryptedVariable is the persisted form of a variable. The items are
+// sealed with a data encryption key from the server keyring before the
+// variable is written to raft, so they are never stored in plaintext.
+type EncryptedVariable struct {
+	// Namespace is the namespace the variable belongs to.
+	Namespace string
+
+	// Path is the slash-delimited location of the variable, unique within
+	// its namespace.
+	Path string
+
+	// KeyID identifies the keyring entry whose data encryption key sealed
+	// the items.
+	KeyID string
+
+	// Data is the encrypted encoding of the variable items.
+	Data []byte
+
+	// CreateTime and ModifyTime are Unix times in nanoseconds.
+	CreateTime int64
+	ModifyTime int64
+
+	// Raft indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy creates a deep copy of the encrypted variable.
+func (v *EncryptedVariable) Copy() *EncryptedVariable {
+	nv := new(EncryptedVariable)
+	*nv = *v
+	nv.Data = make([]byte, len(v.Data))
+	copy(nv.Data, v.Data)
+	return nv
+}
+
+// Metadata produces the list representation of the encrypted variable.
+func (v *EncryptedVariable) Metadata() *VariableMetadata {
+	return &VariableMetadata{
+		Namespace:   v.Namespace,
+		Path:        v.Path,
+		CreateTime:  v.CreateTime,
+		ModifyTime:  v.ModifyTime,
+		CreateIndex: v.CreateIndex,
+		ModifyIndex: v.ModifyIndex,
+	}
+}
+
+// VariablesUpsertRequest is used to create or update variables. The items
+// are in plaintext; the server encrypts them before applying to raft.
+type VariablesUpsertRequest struct {
+	Variables []*Variable
+
+	WriteRequest
+}
+
+// VariablesUpsertResponse is the response to an upsert.
+type VariablesUpsertResponse struct {
+	WriteMeta
+}
+
+// VariablesEncryptedUpsertRequest is the raft message applied for an
+// upsert, carrying only the sealed form of the variables.
+type VariablesEncryptedUpsertRequest struct {
+	Variables []*EncryptedVariable
+
+	WriteRequest
+}
+
+// VariablesDeleteRequest deletes the variable at a path within the request
+// namespace.
+type VariablesDeleteRequest struct {
+	Path string
+
+	WriteRequest
+}
+
+// VariablesDeleteResponse is the response to a delete.
+type VariablesDeleteResponse struct {
+	WriteMeta
+}
+
+// VariablesReadRequest reads the variable at a path within the request
+// namespace.
+type VariablesReadRequest struct {
+	Path string
+
+	QueryOptions
+}
+
+// VariablesReadResponse returns the decrypted variable.
+type VariablesReadResponse struct {
+	Data *Variable
+
+	QueryMeta
+}
+
+// VariablesListRequest lists variable metadata within the request
+// namespace, optionally under a path prefix.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse returns one metadata entry per variable.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+
+	QueryMeta
+}
@@ -0,0 +1,160 @@
+package structs
+
+import "errors"
+
+// SigningKey is an RSA key pair used by servers to sign workload identity
+// tokens. The keyring is stored in raft so every server can verify tokens
+// signed by any of its peers. At most one key is active at a time; rotation
+// adds a new active key while the previous keys are kept so outstanding
+// identities remain verifiable. The variables data encryption key named by
+// KeyID is deliberately not part of this struct: it lives in each server's
+// local keystore and never enters raft, so raft snapshots cannot decrypt
+// the variables they contain.
+type SigningKey struct {
+	// KeyID is the unique identifier of the key, set as the "kid" header on
+	// tokens it signs.
+	KeyID string
+
+	// RSAPrivateKey is the PEM encoded private key. It never leaves the
+	// servers; list endpoints only return the SigningKeyStub.
+	RSAPrivateKey []byte
+
+	// Active indicates this key is the one used to sign new identities.
+	Active bool
+
+	// CreateTime is the Unix time in nanoseconds the key was generated.
+	CreateTime int64
+
+	// Raft indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (k *SigningKey) Copy() *SigningKey {
+	if k == nil {
+		return nil
+	}
+	nk := new(SigningKey)
+	*nk = *k
+	nk.RSAPrivateKey = make([]byte, len(k.RSAPrivateKey))
+	copy(nk.RSAPrivateKey, k.RSAPrivateKey)
+	return nk
+}
+
+// Stub returns a version of the key safe to return to clients, without the
+// private key material.
+func (k *SigningKey) Stub() *SigningKeyStub {
+	return &SigningKeyStub{
+		KeyID:       k.KeyID,
+		Active:      k.Active,
+		CreateTime:  k.CreateTime,
+		CreateIndex: k.CreateIndex,
+		ModifyIndex: k.ModifyIndex,
+	}
+}
+
+// SigningKeyStub is the public metadata of a SigningKey.
+type SigningKeyStub struct {
+	KeyID       string
+	Active      bool
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SigningKeyUpsertRequest is used to register or update signing keys. A
+// rotation submits the new active key together with the deactivated previous
+// key so the change is atomic.
+type SigningKeyUpsertRequest struct {
+	Keys []*SigningKey
+	WriteRequest
+}
+
+// KeyringListRequest is used to list the signing keyring.
+type KeyringListRequest struct {
+	QueryOptions
+}
+
+// KeyringListResponse is used to return the signing keyring metadata.
+type KeyringListResponse struct {
+	Keys []*SigningKeyStub
+	QueryMeta
+}
+
+// KeyringRotateRequest is used to rotate the active signing key.
+type KeyringRotateRequest struct {
+	WriteRequest
+}
+
+// KeyringRotateResponse returns the newly activated signing key.
+type KeyringRotateResponse struct {
+	Key *SigningKeyStub
+	WriteMeta
+}
+
+// KeyringRemoveRequest is used to remove a signing key from the keyring. Only
+// inactive keys that are no longer referenced by any stored material may be
+// removed.
+type KeyringRemoveRequest struct {
+	KeyID string
+	WriteRequest
+}
+
+// KeyringRemoveResponse is used to respond to a keyring removal.
+type KeyringRemoveResponse struct {
+	WriteMeta
+}
+
+// KeyringFetchRequest is used by servers to fetch a local copy of the
+// variables data encryption key for a keyring entry from a peer. The key
+// material is distributed over this RPC rather than through raft so that
+// the raft log and snapshots never hold the keys that seal the variables
+// they store.
+type KeyringFetchRequest struct {
+	KeyID string
+	QueryOptions
+}
+
+// KeyringFetchResponse returns the requested encryption key. The key is
+// left empty when the serving server has no local copy of it.
+type KeyringFetchResponse struct {
+	KeyID         string
+	EncryptionKey []byte
+	QueryMeta
+}
+
+// DeriveWorkloadIdentityRequest is used by clients to request signed workload
+// identity tokens for the named tasks in the given allocation.
+type DeriveWorkloadIdentityRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Tasks    []string
+	QueryOptions
+}
+
+func (r *DeriveWorkloadIdentityRequest) Validate() error {
+	switch {
+	case r.NodeID == "":
+		return errors.New("missing node ID")
+	case r.SecretID == "":
+		return errors.New("missing node SecretID")
+	case r.AllocID == "":
+		return errors.New("missing allocation ID")
+	case len(r.Tasks) == 0:
+		return errors.New("no tasks specified")
+	default:
+		return nil
+	}
+}
+
+type DeriveWorkloadIdentityResponse struct {
+	// Tokens maps from Task Name to its signed identity JWT
+	Tokens map[string]string
+
+	// Error stores any error that occurred. Errors are stored here so we can
+	// communicate whether it is retryable
+	Error *RecoverableError
+
+	QueryMeta
+}
@@ -0,0 +1,269 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables endpoint is used for manipulating the encrypted key/value
+// variables stored in raft. Items are sealed with the server keyring before
+// they are applied, so only the encrypted form is ever persisted.
+type Variables struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used to create or update a set of variables. The items arrive
+// in plaintext and are encrypted with the active keyring entry before being
+// committed via raft.
+func (v *Variables) Upsert(args *structs.VariablesUpsertRequest, reply *structs.VariablesUpsertResponse) error {
+	if done, err := v.srv.forward("Variables.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "upsert"}, time.Now())
+
+	if len(args.Variables) == 0 {
+		return fmt.Errorf("missing variables")
+	}
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range args.Variables {
+		if variable.Namespace == "" {
+			variable.Namespace = args.RequestNamespace()
+		}
+		if err := variable.Validate(); err != nil {
+			return err
+		}
+		if aclObj != nil && !aclObj.AllowVariableOperation(variable.Path, acl.VariableCapabilityWrite) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Seal the items with the active keyring entry
+	key, err := v.srv.fsm.State().ActiveSigningKey(nil)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("keyring has not been initialized")
+	}
+	encKey, err := v.srv.encryptionKeyFor(key.KeyID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	encrypted := make([]*structs.EncryptedVariable, 0, len(args.Variables))
+	for _, variable := range args.Variables {
+		data, err := encryptVariableItems(encKey, variable.Items)
+		if err != nil {
+			return err
+		}
+		encrypted = append(encrypted, &structs.EncryptedVariable{
+			Namespace:  variable.Namespace,
+			Path:       variable.Path,
+			KeyID:      key.KeyID,
+			Data:       data,
+			CreateTime: now,
+			ModifyTime: now,
+		})
+	}
+
+	// Commit the encrypted variables via Raft
+	req := structs.VariablesEncryptedUpsertRequest{
+		Variables:    encrypted,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := v.srv.raftApply(structs.VariablesUpsertRequestType, req)
+	if err != nil {
+		v.logger.Error("variables upsert failed", "error", err)
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Delete removes the variable at a path within the request namespace.
+func (v *Variables) Delete(args *structs.VariablesDeleteRequest, reply *structs.VariablesDeleteResponse) error {
+	if done, err := v.srv.forward("Variables.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "delete"}, time.Now())
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(args.Path, acl.VariableCapabilityDestroy) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Commit the removal via Raft
+	_, index, err := v.srv.raftApply(structs.VariablesDeleteRequestType, args)
+	if err != nil {
+		v.logger.Error("variables delete failed", "error", err)
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Read returns the decrypted variable at a path within the request
+// namespace.
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+
+	if aclObj, err := v.srv.ResolveToken(args.AuthToken); err != nil {
+		// If ResolveToken had an unexpected error return that
+		if err != structs.ErrTokenNotFound {
+			return err
+		}
+
+		// Attempt to lookup AuthToken as a Node.SecretID since nodes call
+		// this endpoint to render task templates and don't have an ACL token.
+		node, stateErr := v.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+		if stateErr != nil {
+			// Return the original ResolveToken error with this err
+			var merr multierror.Error
+			merr.Errors = append(merr.Errors, err, stateErr)
+			return merr.ErrorOrNil()
+		}
+
+		// Not a node or a valid ACL token
+		if node == nil {
+			return structs.ErrTokenNotFound
+		}
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(args.Path, acl.VariableCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			encrypted, err := state.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			if encrypted != nil {
+				decrypted, err := v.decrypt(state, encrypted)
+				if err != nil {
+					return err
+				}
+				reply.Data = decrypted
+			}
+
+			// Use the last index that affected the table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// List returns the metadata of the variables within the request namespace
+// whose paths match the request prefix. Paths the token cannot list are
+// filtered out rather than denying the whole request.
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VariablesByNamespace(ws, args.RequestNamespace(), args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				variable := raw.(*structs.EncryptedVariable)
+				if aclObj != nil && !aclObj.AllowVariableOperation(variable.Path, acl.VariableCapabilityList) {
+					continue
+				}
+				reply.Data = append(reply.Data, variable.Metadata())
+			}
+
+			// Use the last index that affected the table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// decrypt unseals an encrypted variable with the keyring entry that sealed
+// it.
+func (v *Variables) decrypt(state *state.StateStore, encrypted *structs.EncryptedVariable) (*structs.Variable, error) {
+	key, err := state.SigningKeyByID(nil, encrypted.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("keyring entry %s used to encrypt %s not found",
+			encrypted.KeyID, strings.Join([]string{encrypted.Namespace, encrypted.Path}, "/"))
+	}
+	encKey, err := v.srv.encryptionKeyFor(key.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decryptVariableItems(encKey, encrypted.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.Variable{
+		Namespace:   encrypted.Namespace,
+		Path:        encrypted.Path,
+		Items:       items,
+		CreateTime:  encrypted.CreateTime,
+		ModifyTime:  encrypted.ModifyTime,
+		CreateIndex: encrypted.CreateIndex,
+		ModifyIndex: encrypted.ModifyIndex,
+	}, nil
+}
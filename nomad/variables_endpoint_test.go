@@ -0,0 +1,274 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_Upsert(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// An empty request is invalid
+	req := &structs.VariablesUpsertRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.VariablesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing variables")
+
+	// Invalid paths are rejected before anything is written
+	req.Variables = []*structs.Variable{{
+		Path:  "/nomad/jobs",
+		Items: map[string]string{"user": "me"},
+	}}
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "may not start or end with a slash")
+
+	// Upsert a variable
+	req.Variables = []*structs.Variable{{
+		Path:  "nomad/jobs/example",
+		Items: map[string]string{"user": "me", "password": "passw0rd1"},
+	}}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Only the encrypted form is stored in state
+	out, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, "nomad/jobs/example")
+	require.NoError(err)
+	require.NotNil(out)
+	require.NotEmpty(out.KeyID)
+	require.NotContains(string(out.Data), "passw0rd1")
+}
+
+func TestVariablesEndpoint_ReadAndDelete(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	upsert := &structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	// Read returns the decrypted items
+	read := &structs.VariablesReadRequest{
+		Path:         "nomad/jobs/example",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.NotNil(readResp.Data)
+	require.Equal(map[string]string{"user": "me"}, readResp.Data.Items)
+
+	// Delete the variable and it can no longer be read
+	del := &structs.VariablesDeleteRequest{
+		Path:         "nomad/jobs/example",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.VariablesDeleteResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+
+	var readResp2 structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp2))
+	require.Nil(readResp2.Data)
+}
+
+func TestVariablesEndpoint_List(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	upsert := &structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{
+			{Path: "nomad/jobs/example", Items: map[string]string{"user": "me"}},
+			{Path: "nomad/jobs/example/web", Items: map[string]string{"port": "8080"}},
+			{Path: "system/secrets", Items: map[string]string{"root": "toor"}},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	// Listing without a prefix returns all the variables in the namespace
+	req := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", req, &resp))
+	require.Len(resp.Data, 3)
+
+	// Items are never included in list responses
+	req.Prefix = "nomad/jobs"
+	var resp2 structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", req, &resp2))
+	require.Len(resp2.Data, 2)
+	for _, meta := range resp2.Data {
+		require.Equal(structs.DefaultNamespace, meta.Namespace)
+	}
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	state := s1.fsm.State()
+	writeToken := mock.CreatePolicyAndToken(t, state, 100, "var-write",
+		mock.VariablePolicy("nomad/jobs/*", "write", nil))
+	readToken := mock.CreatePolicyAndToken(t, state, 102, "var-read",
+		mock.VariablePolicy("nomad/jobs/*", "read", nil))
+
+	// Without a token the request is denied
+	upsert := &structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// The read token cannot write either
+	upsert.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// The write token can write within its path
+	upsert.AuthToken = writeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	// But not outside of it
+	upsert.Variables[0].Path = "system/secrets"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Add a variable outside the tokens' paths with the root token
+	upsert.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	// The read token can read within its path
+	read := &structs.VariablesReadRequest{
+		Path: "nomad/jobs/example",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.NotNil(readResp.Data)
+
+	// But not outside of it
+	read.Path = "system/secrets"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Listing filters paths the token cannot list rather than denying
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal("nomad/jobs/example", listResp.Data[0].Path)
+
+	// The read token cannot destroy
+	del := &structs.VariablesDeleteRequest{
+		Path:         "nomad/jobs/example",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	del.AuthToken = readToken.SecretID
+	var delResp structs.VariablesDeleteResponse
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// The write token can
+	del.AuthToken = writeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+}
+
+func TestVariablesEndpoint_Read_NodeSecret(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	node := mock.Node()
+	require.NoError(s1.fsm.State().UpsertNode(100, node))
+
+	upsert := &structs.VariablesUpsertRequest{
+		Variables: []*structs.Variable{{
+			Path:  "nomad/jobs/example",
+			Items: map[string]string{"user": "me"},
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var upsertResp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", upsert, &upsertResp))
+
+	// A node's secret ID authenticates reads so clients can render task
+	// templates without an ACL token
+	read := &structs.VariablesReadRequest{
+		Path: "nomad/jobs/example",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp))
+	require.NotNil(readResp.Data)
+	require.Equal(map[string]string{"user": "me"}, readResp.Data.Items)
+
+	// An unknown secret does not
+	read.QueryOptions.AuthToken = "0244a7fe-1563-4d45-b1f2-60f88b9d4f8b"
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Read", read, &readResp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrTokenNotFound.Error())
+}
@@ -133,6 +133,10 @@ type VaultClient interface {
 	// Stats returns the Vault clients statistics
 	Stats() map[string]string
 
+	// Status returns the client's connection, renewal, and revocation
+	// backlog status
+	Status() *structs.VaultStatus
+
 	// EmitStats emits that clients statistics at the given period until stopCh
 	// is called.
 	EmitStats(period time.Duration, stopCh <-chan struct{})
@@ -1402,6 +1406,27 @@ func (v *vaultClient) Stats() map[string]string {
 	}
 }
 
+// Status returns the client's connection, renewal, and revocation backlog
+// status.
+func (v *vaultClient) Status() *structs.VaultStatus {
+	stats := v.stats()
+	status := &structs.VaultStatus{
+		Enabled:           v.Enabled(),
+		Active:            v.Active(),
+		RevocationBacklog: stats.TrackedForRevoke,
+		TokenTTL:          stats.TokenTTL,
+		TokenExpiry:       stats.TokenExpiry,
+	}
+
+	established, err := v.ConnectionEstablished()
+	status.Connected = established
+	if err != nil {
+		status.ConnectionError = err.Error()
+	}
+
+	return status
+}
+
 func (v *vaultClient) stats() *VaultStats {
 	// Allocate a new stats struct
 	stats := new(VaultStats)
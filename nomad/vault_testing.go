@@ -145,4 +145,5 @@ func (v *TestVaultClient) SetActive(enabled bool)
 func (v *TestVaultClient) SetConfig(config *config.VaultConfig) error             { return nil }
 func (v *TestVaultClient) Running() bool                                          { return true }
 func (v *TestVaultClient) Stats() map[string]string                               { return map[string]string{} }
+func (v *TestVaultClient) Status() *structs.VaultStatus                           { return &structs.VaultStatus{} }
 func (v *TestVaultClient) EmitStats(period time.Duration, stopCh <-chan struct{}) {}
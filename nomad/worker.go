@@ -56,6 +56,11 @@ type Worker struct {
 	logger log.Logger
 	start  time.Time
 
+	// schedulers is the set of scheduler types this worker will dequeue
+	// evaluations for. If empty, the worker serves every enabled scheduler.
+	// Operators can pin workers to specific types to reserve capacity.
+	schedulers []string
+
 	paused    bool
 	pauseLock sync.Mutex
 	pauseCond *sync.Cond
@@ -72,10 +77,21 @@ type Worker struct {
 
 // NewWorker starts a new worker associated with the given server
 func NewWorker(srv *Server) (*Worker, error) {
+	return newWorker(srv, nil)
+}
+
+// NewPinnedWorker starts a new worker that only dequeues evaluations for the
+// given scheduler types.
+func NewPinnedWorker(srv *Server, schedulers []string) (*Worker, error) {
+	return newWorker(srv, schedulers)
+}
+
+func newWorker(srv *Server, schedulers []string) (*Worker, error) {
 	w := &Worker{
-		srv:    srv,
-		logger: srv.logger.ResetNamed("worker"),
-		start:  time.Now(),
+		srv:        srv,
+		logger:     srv.logger.ResetNamed("worker"),
+		start:      time.Now(),
+		schedulers: schedulers,
 	}
 	w.pauseCond = sync.NewCond(&w.pauseLock)
 	go w.run()
@@ -113,7 +129,7 @@ func (w *Worker) run() {
 		// Check for a shutdown
 		if w.srv.IsShutdown() {
 			w.logger.Error("nacking eval because the server is shutting down", "eval", log.Fmt("%#v", eval))
-			w.sendAck(eval.ID, token, false)
+			w.sendAck(eval.ID, token, false, "server shutting down")
 			return
 		}
 
@@ -121,19 +137,19 @@ func (w *Worker) run() {
 		snap, err := w.snapshotMinIndex(waitIndex, raftSyncLimit)
 		if err != nil {
 			w.logger.Error("error waiting for Raft index", "error", err, "index", waitIndex)
-			w.sendAck(eval.ID, token, false)
+			w.sendAck(eval.ID, token, false, err.Error())
 			continue
 		}
 
 		// Invoke the scheduler to determine placements
 		if err := w.invokeScheduler(snap, eval, token); err != nil {
 			w.logger.Error("error invoking scheduler", "error", err)
-			w.sendAck(eval.ID, token, false)
+			w.sendAck(eval.ID, token, false, err.Error())
 			continue
 		}
 
 		// Complete the evaluation
-		w.sendAck(eval.ID, token, true)
+		w.sendAck(eval.ID, token, true, "")
 	}
 }
 
@@ -141,9 +157,14 @@ func (w *Worker) run() {
 // This blocks until an evaluation is available or a timeout is reached.
 func (w *Worker) dequeueEvaluation(timeout time.Duration) (
 	eval *structs.Evaluation, token string, waitIndex uint64, shutdown bool) {
-	// Setup the request
+	// Setup the request. Pinned workers only ask for their scheduler types,
+	// everyone else serves the full enabled set.
+	schedulers := w.schedulers
+	if len(schedulers) == 0 {
+		schedulers = w.srv.config.EnabledSchedulers
+	}
 	req := structs.EvalDequeueRequest{
-		Schedulers:       w.srv.config.EnabledSchedulers,
+		Schedulers:       schedulers,
 		Timeout:          timeout,
 		SchedulerVersion: scheduler.SchedulerVersion,
 		WriteRequest: structs.WriteRequest{
@@ -193,14 +214,16 @@ REQ:
 	goto REQ
 }
 
-// sendAck makes a best effort to ack or nack the evaluation.
-// Any errors are logged but swallowed.
-func (w *Worker) sendAck(evalID, token string, ack bool) {
+// sendAck makes a best effort to ack or nack the evaluation. When nacking,
+// nackReason carries the failure so it can be surfaced if the evaluation
+// reaches the delivery limit. Any errors are logged but swallowed.
+func (w *Worker) sendAck(evalID, token string, ack bool, nackReason string) {
 	defer metrics.MeasureSince([]string{"nomad", "worker", "send_ack"}, time.Now())
 	// Setup the request
 	req := structs.EvalAckRequest{
 		EvalID: evalID,
 		Token:  token,
+		Error:  nackReason,
 		WriteRequest: structs.WriteRequest{
 			Region: w.srv.config.Region,
 		},
@@ -82,6 +82,39 @@ func TestWorker_dequeueEvaluation(t *testing.T) {
 	}
 }
 
+func TestWorker_dequeueEvaluation_Pinned(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.EnabledSchedulers = []string{structs.JobTypeService, structs.JobTypeBatch}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a batch and a service evaluation
+	eval1 := mock.Eval()
+	eval1.Type = structs.JobTypeBatch
+	s1.evalBroker.Enqueue(eval1)
+	eval2 := mock.Eval()
+	s1.evalBroker.Enqueue(eval2)
+
+	// Create a worker pinned to service evals
+	w := &Worker{srv: s1, logger: s1.logger, schedulers: []string{structs.JobTypeService}}
+
+	// The pinned worker should skip the batch eval
+	eval, token, _, shutdown := w.dequeueEvaluation(10 * time.Millisecond)
+	if shutdown {
+		t.Fatalf("should not shutdown")
+	}
+	if token == "" {
+		t.Fatalf("should get token")
+	}
+	if !reflect.DeepEqual(eval, eval2) {
+		t.Fatalf("bad: %#v %#v", eval, eval2)
+	}
+}
+
 // Test that the worker picks up the correct wait index when there are multiple
 // evals for the same job.
 func TestWorker_dequeueEvaluation_SerialJobs(t *testing.T) {
@@ -133,7 +166,7 @@ func TestWorker_dequeueEvaluation_SerialJobs(t *testing.T) {
 	}
 
 	// Send the Ack
-	w.sendAck(eval1.ID, token, true)
+	w.sendAck(eval1.ID, token, true, "")
 
 	// Attempt second dequeue
 	eval, token, waitIndex, shutdown = w.dequeueEvaluation(10 * time.Millisecond)
@@ -258,7 +291,7 @@ func TestWorker_sendAck(t *testing.T) {
 	}
 
 	// Send the Nack
-	w.sendAck(eval.ID, token, false)
+	w.sendAck(eval.ID, token, false, "")
 
 	// Check the depth is 1, nothing unacked
 	stats = s1.evalBroker.Stats()
@@ -270,7 +303,7 @@ func TestWorker_sendAck(t *testing.T) {
 	eval, token, _, _ = w.dequeueEvaluation(10 * time.Millisecond)
 
 	// Send the Ack
-	w.sendAck(eval.ID, token, true)
+	w.sendAck(eval.ID, token, true, "")
 
 	// Check the depth is 0
 	stats = s1.evalBroker.Stats()
@@ -674,7 +707,7 @@ func TestWorker_ReblockEval(t *testing.T) {
 	}
 
 	// Ack the eval
-	w.sendAck(evalOut.ID, token, true)
+	w.sendAck(evalOut.ID, token, true, "")
 
 	// Check that it is blocked
 	bStats := s1.blockedEvals.Stats()
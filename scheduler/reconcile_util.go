@@ -269,6 +269,13 @@ func (a allocSet) filterByRescheduleable(isBatch bool, now time.Time, evalID str
 			continue
 		}
 
+		// Allocations placed on hold are kept around for debugging and are
+		// never replaced until the hold is released.
+		if alloc.DesiredTransition.ShouldHold() {
+			untainted[alloc.ID] = alloc
+			continue
+		}
+
 		// Only failed allocs with desired state run get to this point
 		// If the failed alloc is not eligible for rescheduling now we add it to the untainted set
 		eligibleNow, eligibleLater, rescheduleTime = updateByReschedulable(alloc, now, evalID, deployment)